@@ -15,6 +15,7 @@ import (
 	"notification_system/internal/messaging"
 	"notification_system/migrations"
 	"notification_system/pkg/database"
+	"notification_system/pkg/envelope"
 	"notification_system/pkg/logger"
 	"notification_system/pkg/server"
 )
@@ -25,19 +26,33 @@ func main() {
 
 	db := database.New(cfg.GetDBURL())
 	migrations.Migrate(cfg.GetDBURL())
+	// the receivers, the workers and the API share the keys and reload them after a rotation
+	cipher := envelope.MustOpen(cfg.EncryptionKeyFile)
 
-	srv := server.NewGinServer(cfg, db)
+	srv := server.NewGinServer(cfg, db, cipher)
 	go func() {
 		if err := srv.Run(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			slog.Error("Gin server error", slog.Any("error", err))
 		}
 	}()
 
-	sender := messaging.NewNotificationSender(cfg, db)
+	sender := messaging.NewNotificationSender(cfg, db, cipher)
 	ctxSender, cancelSender := context.WithCancel(context.Background())
 	sender.StartProcessNotifications(ctxSender, time.Duration(cfg.SenderHandlePeriodMs)*time.Millisecond)
 
-	receiver := messaging.NewNotificationReceiver(cfg, db)
+	scheduler := messaging.NewRecurringScheduler(cfg, db, cipher)
+	ctxScheduler, cancelScheduler := context.WithCancel(context.Background())
+	scheduler.StartScheduling(ctxScheduler, time.Duration(cfg.SchedulerPeriodMs)*time.Millisecond)
+
+	broadcastWorker := messaging.NewBroadcastWorker(cfg, db, cipher)
+	ctxBroadcast, cancelBroadcast := context.WithCancel(context.Background())
+	broadcastWorker.StartFanOut(ctxBroadcast, time.Duration(cfg.SchedulerPeriodMs)*time.Millisecond)
+
+	retentionWorker := messaging.NewRetentionWorker(cfg, db)
+	ctxRetention, cancelRetention := context.WithCancel(context.Background())
+	retentionWorker.StartRetention(ctxRetention, time.Duration(cfg.RetentionPeriodMs)*time.Millisecond)
+
+	receiver := messaging.NewNotificationReceiver(cfg, db, cipher)
 	ctxReceiver, cancelReceiver := context.WithCancel(context.Background())
 	receiver.StartProcessNotifications(ctxReceiver)
 
@@ -46,6 +61,9 @@ func main() {
 	signal.Notify(quit, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	cancelSender()
+	cancelScheduler()
+	cancelBroadcast()
+	cancelRetention()
 	cancelReceiver()
 	ctxShutdown, cancelShutdown := context.WithCancel(context.Background())
 	defer cancelShutdown()
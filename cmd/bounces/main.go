@@ -0,0 +1,71 @@
+// Command bounces feeds delivery status notifications from a local mbox file or
+// Maildir into the bounce processing, for testing without a mail provider webhook.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"os"
+
+	"notification_system/config"
+	"notification_system/internal/bounces"
+	"notification_system/internal/repositories"
+	"notification_system/internal/services"
+	"notification_system/pkg/database"
+	"notification_system/pkg/envelope"
+	"notification_system/pkg/logger"
+)
+
+func main() {
+	mbox := flag.String("mbox", "", "path to an mbox file")
+	maildir := flag.String("maildir", "", "path to a Maildir")
+	flag.Parse()
+	if (*mbox == "") == (*maildir == "") {
+		slog.Error("exactly one of -mbox and -maildir is required")
+		os.Exit(2)
+	}
+
+	cfg := config.MustLoad()
+	slogger.SetLogger(cfg.AppEnv)
+	db := database.New(cfg.GetDBURL())
+	defer db.Pool.Close()
+	cipher := envelope.MustOpen(cfg.EncryptionKeyFile)
+
+	bounceService := services.NewBounceServiceImpl(
+		repositories.NewNotificationPostgresRepository(db, cipher),
+		repositories.NewSuppressionPostgresRepository(db),
+	)
+	ctx := context.Background()
+	processed, skipped := 0, 0
+	process := func(message []byte) error {
+		_, err := bounceService.ProcessBounce(ctx, message)
+		if errors.Is(err, services.ErrInvalidBounce) {
+			skipped++
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		processed++
+		return nil
+	}
+
+	var err error
+	if *mbox != "" {
+		var file *os.File
+		file, err = os.Open(*mbox)
+		if err == nil {
+			err = bounces.ReadMbox(file, process)
+			file.Close()
+		}
+	} else {
+		err = bounces.ReadMaildir(*maildir, process)
+	}
+	if err != nil {
+		slog.Error("failed to process bounces", slog.Any("error", err))
+		os.Exit(1)
+	}
+	slog.Info("bounces processed", slog.Int("processed", processed), slog.Int("skipped", skipped))
+}
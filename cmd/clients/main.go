@@ -0,0 +1,119 @@
+// Command clients manages the tenants, the API clients and their keys. New keys are
+// printed once, only their hashes are stored.
+//
+//	clients create-tenant -name retail [-email-from <address> -smtp-host <host> -smtp-port 587 -smtp-username <user> -smtp-password <password>]
+//	clients tenants
+//	clients create -name billing [-oauth-client-id billing-app] [-tenant <id>]
+//	clients list
+//	clients link -client <id> -oauth-client-id <client_id claim>
+//	clients keys -client <id>
+//	clients new-key -client <id>
+//	clients rotate -client <id> -key <key id> [-grace 24h]
+//	clients revoke -client <id> -key <key id>
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/google/uuid"
+
+	"notification_system/config"
+	"notification_system/internal/dto"
+	"notification_system/internal/repositories"
+	"notification_system/internal/services"
+	"notification_system/pkg/database"
+	"notification_system/pkg/envelope"
+	"notification_system/pkg/logger"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	command := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	name := command.String("name", "", "client name")
+	tenant := command.String("tenant", "", "tenant ID, the default tenant when empty")
+	emailFrom := command.String("email-from", "", "From address of the email of the tenant")
+	smtpHost := command.String("smtp-host", "", "SMTP server of the tenant")
+	smtpPort := command.Int("smtp-port", 0, "SMTP port of the tenant, 587 when empty")
+	smtpUsername := command.String("smtp-username", "", "SMTP username of the tenant, the From address when empty")
+	smtpPassword := command.String("smtp-password", "", "SMTP password of the tenant")
+	oauthClientID := command.String("oauth-client-id", "", "OAuth client whose tokens act as the client, empty unlinks")
+	client := command.String("client", "", "client ID")
+	key := command.String("key", "", "API key ID")
+	grace := command.Duration("grace", services.DefaultRotationGracePeriod, "how long a rotated key keeps working")
+	_ = command.Parse(os.Args[2:])
+
+	cfg := config.MustLoad()
+	slogger.SetLogger(cfg.AppEnv)
+	db := database.New(cfg.GetDBURL())
+	defer db.Pool.Close()
+	cipher := envelope.MustOpen(cfg.EncryptionKeyFile)
+
+	clientService := services.NewClientServiceImpl(repositories.NewClientPostgresRepository(db, cipher))
+	ctx := context.Background()
+
+	var result any
+	var err error
+	switch os.Args[1] {
+	case "create-tenant":
+		result, err = clientService.CreateTenant(ctx, &dto.TenantCreate{
+			Name:         *name,
+			EmailFrom:    *emailFrom,
+			SMTPHost:     *smtpHost,
+			SMTPPort:     int32(*smtpPort),
+			SMTPUsername: *smtpUsername,
+			SMTPPassword: *smtpPassword,
+		})
+	case "tenants":
+		result, err = clientService.GetTenants(ctx)
+	case "create":
+		clientCreate := &dto.ClientCreate{Name: *name, OAuthClientID: *oauthClientID}
+		if *tenant != "" {
+			clientCreate.TenantID = mustParseID("tenant", *tenant)
+		}
+		result, err = clientService.CreateClient(ctx, clientCreate)
+	case "list":
+		result, err = clientService.GetClients(ctx)
+	case "link":
+		result, err = clientService.LinkOAuthClient(ctx, mustParseID("client", *client), *oauthClientID)
+	case "keys":
+		result, err = clientService.GetAPIKeys(ctx, mustParseID("client", *client))
+	case "new-key":
+		result, err = clientService.CreateAPIKey(ctx, mustParseID("client", *client))
+	case "rotate":
+		result, err = clientService.RotateAPIKey(ctx, mustParseID("client", *client), mustParseID("key", *key), *grace)
+	case "revoke":
+		err = clientService.RevokeAPIKey(ctx, mustParseID("client", *client), mustParseID("key", *key))
+	default:
+		usage()
+	}
+	if err != nil {
+		slog.Error("command failed", slog.String("command", os.Args[1]), slog.Any("error", err))
+		os.Exit(1)
+	}
+	if result != nil {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		_ = encoder.Encode(result)
+	}
+}
+
+func mustParseID(flagName, value string) uuid.UUID {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		slog.Error("invalid or missing ID", slog.String("flag", "-"+flagName))
+		os.Exit(2)
+	}
+	return id
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: clients create-tenant|tenants|create|list|link|keys|new-key|rotate|revoke [flags]")
+	os.Exit(2)
+}
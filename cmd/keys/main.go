@@ -0,0 +1,161 @@
+// Command keys manages the local key file of the encryption at rest and re-encrypts the
+// stored notifications and tenant SMTP passwords after a rotation. A rotation adds a master
+// key and makes it the primary one; the previous keys stay in the file until reencrypt has
+// finished. Running services reload the file, reencrypt waits until they have switched to
+// the new primary key.
+//
+//	keys init -file keys.json [-id <key id>]
+//	keys rotate -file keys.json [-id <key id>]
+//	keys reencrypt [-batch 500]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+
+	"notification_system/config"
+	"notification_system/internal/repositories"
+	"notification_system/pkg/database"
+	"notification_system/pkg/envelope"
+	"notification_system/pkg/logger"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	command := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	file := command.String("file", "", "path of the key file")
+	keyID := command.String("id", time.Now().UTC().Format("20060102T150405"), "ID of the new master key")
+	batch := command.Uint("batch", 500, "notifications re-encrypted per transaction")
+	_ = command.Parse(os.Args[2:])
+
+	var err error
+	switch os.Args[1] {
+	case "init":
+		err = initKeyFile(*file, *keyID)
+	case "rotate":
+		err = rotateKeyFile(*file, *keyID)
+	case "reencrypt":
+		err = reencrypt(*batch)
+	default:
+		usage()
+	}
+	if err != nil {
+		slog.Error("command failed", slog.String("command", os.Args[1]), slog.Any("error", err))
+		os.Exit(1)
+	}
+}
+
+func initKeyFile(path, keyID string) error {
+	if path == "" {
+		usage()
+	}
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("key file %s already exists", path)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	file, err := envelope.NewKeyFile(keyID)
+	if err != nil {
+		return err
+	}
+	if err := file.Write(path); err != nil {
+		return err
+	}
+	slog.Info("key file created", slog.String("file", path), slog.String("primary", keyID))
+	return nil
+}
+
+func rotateKeyFile(path, keyID string) error {
+	if path == "" {
+		usage()
+	}
+	file, err := envelope.ReadKeyFile(path)
+	if err != nil {
+		return err
+	}
+	previous := file.Primary
+	if err := file.AddKey(keyID); err != nil {
+		return err
+	}
+	if err := file.Write(path); err != nil {
+		return err
+	}
+	slog.Info("master key rotated, run reencrypt",
+		slog.String("file", path),
+		slog.String("previous", previous),
+		slog.String("primary", keyID),
+	)
+	return nil
+}
+
+// reencrypt walks all notifications in ID order, then the SMTP passwords of the tenants,
+// with the key file of the configuration.
+func reencrypt(batch uint) error {
+	cfg := config.MustLoad()
+	slogger.SetLogger(cfg.AppEnv)
+	if cfg.EncryptionKeyFile == "" {
+		return errors.New("ENCRYPTION_KEY_FILE is not set")
+	}
+	cipher, err := envelope.Open(cfg.EncryptionKeyFile)
+	if err != nil {
+		return err
+	}
+	wait, err := reloadWait(cfg.EncryptionKeyFile, time.Now())
+	if err != nil {
+		return err
+	}
+	if wait > 0 {
+		slog.Info("waiting for the services to reload the key file", slog.Duration("wait", wait))
+		time.Sleep(wait)
+	}
+	db := database.New(cfg.GetDBURL())
+	defer db.Pool.Close()
+
+	encryptionRepo := repositories.NewEncryptionPostgresRepository(db, cipher)
+	ctx := context.Background()
+	after := uuid.Nil
+	total := 0
+	for {
+		last, count, err := encryptionRepo.ReencryptNotifications(ctx, after, batch)
+		if err != nil {
+			return fmt.Errorf("re-encrypt after %s: %w", after, err)
+		}
+		if last == uuid.Nil {
+			break
+		}
+		after = last
+		total += count
+	}
+	slog.Info("notifications re-encrypted", slog.Int("rows", total))
+	tenants, err := encryptionRepo.ReencryptTenants(ctx)
+	if err != nil {
+		return fmt.Errorf("re-encrypt tenants: %w", err)
+	}
+	slog.Info("tenant SMTP passwords re-encrypted", slog.Int("tenants", tenants))
+	return nil
+}
+
+// reloadWait returns how long the running services may still seal new values with the
+// previous primary key after the key file was written; such values written behind the
+// walk of reencrypt would stay under it.
+func reloadWait(path string, now time.Time) (time.Duration, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	return max(info.ModTime().Add(envelope.KeyFileCheckInterval).Sub(now), 0), nil
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: keys init|rotate|reencrypt [flags]")
+	os.Exit(2)
+}
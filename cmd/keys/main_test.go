@@ -0,0 +1,65 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"notification_system/pkg/envelope"
+)
+
+func TestInitKeyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	if err := initKeyFile(path, "k1"); err != nil {
+		t.Fatalf("initKeyFile() error = %v", err)
+	}
+	file, err := envelope.ReadKeyFile(path)
+	if err != nil {
+		t.Fatalf("ReadKeyFile() error = %v", err)
+	}
+	if file.Primary != "k1" || len(file.Keys) != 1 || file.IndexKey == "" {
+		t.Errorf("unexpected key file %+v", file)
+	}
+	if err := initKeyFile(path, "k2"); err == nil {
+		t.Error("initKeyFile() of an existing file, want an error")
+	}
+}
+
+func TestRotateKeyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	_ = initKeyFile(path, "k1")
+	before, _ := envelope.ReadKeyFile(path)
+
+	if err := rotateKeyFile(path, "k2"); err != nil {
+		t.Fatalf("rotateKeyFile() error = %v", err)
+	}
+	after, err := envelope.ReadKeyFile(path)
+	if err != nil {
+		t.Fatalf("ReadKeyFile() error = %v", err)
+	}
+	if after.Primary != "k2" || after.Keys["k1"] != before.Keys["k1"] || after.IndexKey != before.IndexKey {
+		t.Errorf("rotated key file %+v, want k2 primary with k1 and the index key kept", after)
+	}
+	if err := rotateKeyFile(path, "k2"); err == nil {
+		t.Error("rotateKeyFile() to an existing key, want an error")
+	}
+	if err := rotateKeyFile(filepath.Join(t.TempDir(), "missing.json"), "k3"); err == nil {
+		t.Error("rotateKeyFile() of a missing file, want an error")
+	}
+}
+
+func TestReloadWait(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	_ = initKeyFile(path, "k1")
+
+	wait, err := reloadWait(path, time.Now())
+	if err != nil || wait <= 0 || wait > envelope.KeyFileCheckInterval {
+		t.Errorf("reloadWait() right after writing = %v, %v, want up to %v", wait, err, envelope.KeyFileCheckInterval)
+	}
+	if wait, _ := reloadWait(path, time.Now().Add(envelope.KeyFileCheckInterval)); wait != 0 {
+		t.Errorf("reloadWait() after the check interval = %v, want 0", wait)
+	}
+	if _, err := reloadWait(filepath.Join(t.TempDir(), "missing.json"), time.Now()); err == nil {
+		t.Error("reloadWait() of a missing file, want an error")
+	}
+}
@@ -9,23 +9,65 @@ import (
 )
 
 type Config struct {
-	AppEnv                AppEnv `env:"APP_ENV"`
-	AppPort               uint16 `env:"APP_PORT"`
-	DBHost                string `env:"DB_HOST"`
-	DBPort                uint16 `env:"DB_PORT"`
-	DBUsername            string `env:"DB_USERNAME"`
-	DBPassword            string `env:"DB_PASSWORD"`
-	DBName                string `env:"DB_NAME"`
-	DBPath                string `env:"DB_PATH"`
-	MaxBatchSize          uint   `env:"MAX_BATCH_SIZE"`
-	MaxRetries            uint8  `env:"MAX_RETRIES"`
-	KafkaPort             uint16 `env:"KAFKA_PORT"`
-	NotificationTopicName string `env:"NOTIFICATION_TOPIC_NAME"`
-	ConsumerGroupID       string `env:"CONSUMER_GROUP_ID"`
-	SenderHandlePeriodMs  int    `env:"SENDER_HANDLE_PERIOD_MS"`
-	Timeout               int    `env:"TIMEOUT"`
-	Gmail                 string `env:"GMAIL"`
-	GmailAppPassword      string `env:"GMAIL_APP_PASSWORD"`
+	AppEnv                 AppEnv            `env:"APP_ENV"`
+	AppPort                uint16            `env:"APP_PORT"`
+	DBHost                 string            `env:"DB_HOST"`
+	DBPort                 uint16            `env:"DB_PORT"`
+	DBUsername             string            `env:"DB_USERNAME"`
+	DBPassword             string            `env:"DB_PASSWORD"`
+	DBName                 string            `env:"DB_NAME"`
+	DBPath                 string            `env:"DB_PATH"`
+	MaxBatchSize           uint              `env:"MAX_BATCH_SIZE"`
+	MaxRetries             uint8             `env:"MAX_RETRIES"`
+	KafkaPort              uint16            `env:"KAFKA_PORT"`
+	NotificationTopicName  string            `env:"NOTIFICATION_TOPIC_NAME"`
+	ConsumerGroupID        string            `env:"CONSUMER_GROUP_ID"`
+	SenderHandlePeriodMs   int               `env:"SENDER_HANDLE_PERIOD_MS"`
+	SchedulerPeriodMs      int               `env:"SCHEDULER_PERIOD_MS" env-default:"1000"`
+	BroadcastChunkSize     uint              `env:"BROADCAST_CHUNK_SIZE" env-default:"1000"`
+	Timeout                int               `env:"TIMEOUT"`
+	Gmail                  string            `env:"GMAIL"`
+	GmailAppPassword       string            `env:"GMAIL_APP_PASSWORD"`
+	TelegramBotToken       string            `env:"TELEGRAM_BOT_TOKEN"`
+	TelegramAPIURL         string            `env:"TELEGRAM_API_URL"`
+	WebhookMethod          string            `env:"WEBHOOK_METHOD" env-default:"POST"`
+	WebhookHeaders         map[string]string `env:"WEBHOOK_HEADERS"`
+	WebhookTimeoutMs       int               `env:"WEBHOOK_TIMEOUT_MS" env-default:"10000"`
+	WebhookSigningSecret   string            `env:"WEBHOOK_SIGNING_SECRET"`
+	WebhookClientCert      string            `env:"WEBHOOK_CLIENT_CERT"`
+	WebhookClientKey       string            `env:"WEBHOOK_CLIENT_KEY"`
+	WebhookCACert          string            `env:"WEBHOOK_CA_CERT"`
+	WebhookSuccessCodes    string            `env:"WEBHOOK_SUCCESS_CODES" env-default:"200-299"`
+	WebhookAllowedNetworks string            `env:"WEBHOOK_ALLOWED_NETWORKS"`
+	SlackBotToken          string            `env:"SLACK_BOT_TOKEN"`
+	SlackAPIURL            string            `env:"SLACK_API_URL"`
+	FCMServiceAccountFile  string            `env:"FCM_SERVICE_ACCOUNT_FILE"`
+	FCMEndpoint            string            `env:"FCM_ENDPOINT"`
+	FCMTokenURI            string            `env:"FCM_TOKEN_URI"`
+	APNsKeyFile            string            `env:"APNS_KEY_FILE"`
+	APNsKeyID              string            `env:"APNS_KEY_ID"`
+	APNsTeamID             string            `env:"APNS_TEAM_ID"`
+	APNsTopic              string            `env:"APNS_TOPIC"`
+	APNsEndpoint           string            `env:"APNS_ENDPOINT"`
+	WebPushVAPIDPrivateKey string            `env:"WEB_PUSH_VAPID_PRIVATE_KEY"`
+	WebPushVAPIDSubject    string            `env:"WEB_PUSH_VAPID_SUBJECT"`
+	UnsubscribeSecret      string            `env:"UNSUBSCRIBE_SECRET"`
+	UnsubscribeBaseURL     string            `env:"UNSUBSCRIBE_BASE_URL"`
+	UnsubscribeTTLHours    int               `env:"UNSUBSCRIBE_TTL_HOURS" env-default:"720"`
+	UnsubscribeMailto      string            `env:"UNSUBSCRIBE_MAILTO"`
+	UnsubscribeFooter      bool              `env:"UNSUBSCRIBE_FOOTER"`
+	JWKSURL                string            `env:"JWT_JWKS_URL"`
+	JWTIssuer              string            `env:"JWT_ISSUER"`
+	JWTAudience            string            `env:"JWT_AUDIENCE"`
+	JWKSCacheTTLSeconds    int               `env:"JWT_JWKS_CACHE_TTL_SECONDS" env-default:"300"`
+	JWTLeewaySeconds       int               `env:"JWT_LEEWAY_SECONDS" env-default:"60"`
+	RateLimitPerSecond     float64           `env:"RATE_LIMIT_PER_SECOND" env-default:"50"`
+	RateLimitBurst         int               `env:"RATE_LIMIT_BURST" env-default:"100"`
+	EncryptionKeyFile      string            `env:"ENCRYPTION_KEY_FILE"`
+	MaskPII                bool              `env:"MASK_PII"`
+	RetentionPeriodMs      int               `env:"RETENTION_PERIOD_MS" env-default:"60000"`
+	RetentionBatchSize     uint              `env:"RETENTION_BATCH_SIZE" env-default:"1000"`
+	ArchiveDir             string            `env:"ARCHIVE_DIR"`
 }
 
 type AppEnv string
@@ -47,11 +89,25 @@ func MustLoad() *Config {
 		if err := cleanenv.ReadEnv(Cfg); err != nil {
 			log.Fatalf("Cannot read .env file: %s", err)
 		}
+		if err := Cfg.validate(); err != nil {
+			log.Fatalf("Invalid configuration: %s", err)
+		}
 		fmt.Printf("APP_PORT: %d", Cfg.AppPort)
 	})
 	return Cfg
 }
 
+// validate rejects settings the services cannot run with.
+func (cfg *Config) validate() error {
+	if cfg.RateLimitPerSecond <= 0 {
+		return fmt.Errorf("RATE_LIMIT_PER_SECOND must be positive, got %v", cfg.RateLimitPerSecond)
+	}
+	if cfg.RateLimitBurst < 1 {
+		return fmt.Errorf("RATE_LIMIT_BURST must be at least 1, got %d", cfg.RateLimitBurst)
+	}
+	return nil
+}
+
 func (cfg *Config) GetDBURL() string {
 	dbUrl := fmt.Sprintf("postgres://%s:%s@%s:5432/%s?sslmode=disable",
 		cfg.DBUsername,
@@ -0,0 +1,103 @@
+// Package audit collects what a request changed for the audit log.
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+)
+
+// ChangeKey is the context key of the change of an audited request, set by the audit middleware.
+const ChangeKey = "AuditChange"
+
+// Change is what a request did to a resource, recorded by the service that did it.
+// An empty Action means no service recorded a change.
+type Change struct {
+	Action       string
+	ResourceType string
+	ResourceID   string
+	Before       json.RawMessage
+	After        json.RawMessage
+	Diff         json.RawMessage
+}
+
+// FromContext returns the change of the audited request, false outside one.
+func FromContext(ctx context.Context) (*Change, bool) {
+	change, ok := ctx.Value(ChangeKey).(*Change)
+	return change, ok
+}
+
+// Enabled tells if the request is audited, services use it to skip reading the state
+// before a change nobody records.
+func Enabled(ctx context.Context) bool {
+	_, ok := FromContext(ctx)
+	return ok
+}
+
+// Record notes the action and the state of the resource before and after it on the
+// audited request. Either state may be nil, like before a create. Callers outside a
+// request, like the workers, are not audited.
+func Record(ctx context.Context, action, resourceType, resourceID string, before, after any) {
+	change, ok := FromContext(ctx)
+	if !ok {
+		return
+	}
+	change.Action = action
+	change.ResourceType = resourceType
+	change.ResourceID = resourceID
+	change.Before = marshal(before)
+	change.After = marshal(after)
+	change.Diff = Diff(change.Before, change.After)
+}
+
+func marshal(state any) json.RawMessage {
+	if state == nil {
+		return nil
+	}
+	data, err := json.Marshal(state)
+	if err != nil || bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	return data
+}
+
+type change struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// Diff returns the top-level fields of two JSON objects that differ, as {"field": {"before": …,
+// "after": …}}. States that are not both objects are compared as a whole. Nil means no change.
+func Diff(before, after json.RawMessage) json.RawMessage {
+	var beforeValue, afterValue any
+	if len(before) != 0 && json.Unmarshal(before, &beforeValue) != nil {
+		return nil
+	}
+	if len(after) != 0 && json.Unmarshal(after, &afterValue) != nil {
+		return nil
+	}
+	if reflect.DeepEqual(beforeValue, afterValue) {
+		return nil
+	}
+
+	beforeFields, beforeObject := beforeValue.(map[string]any)
+	afterFields, afterObject := afterValue.(map[string]any)
+	if (!beforeObject && beforeValue != nil) || (!afterObject && afterValue != nil) {
+		data, _ := json.Marshal(change{Before: beforeValue, After: afterValue})
+		return data
+	}
+	changes := make(map[string]change)
+	for field, value := range beforeFields {
+		if afterField, ok := afterFields[field]; !ok || !reflect.DeepEqual(value, afterField) {
+			changes[field] = change{Before: value, After: afterFields[field]}
+		}
+	}
+	for field, value := range afterFields {
+		if _, ok := beforeFields[field]; !ok {
+			changes[field] = change{After: value}
+		}
+	}
+	data, _ := json.Marshal(changes)
+	return data
+}
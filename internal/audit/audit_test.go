@@ -0,0 +1,61 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   string
+	}{
+		{"unchanged", `{"a":1,"b":"x"}`, `{"b":"x","a":1}`, ``},
+		{"changed field", `{"a":1,"b":"x"}`, `{"a":2,"b":"x"}`, `{"a":{"before":1,"after":2}}`},
+		{"added and removed", `{"a":1}`, `{"b":true}`, `{"a":{"before":1,"after":null},"b":{"before":null,"after":true}}`},
+		{"created", ``, `{"a":1}`, `{"a":{"before":null,"after":1}}`},
+		{"deleted", `{"a":1}`, ``, `{"a":{"before":1,"after":null}}`},
+		{"arrays", `[1]`, `[1,2]`, `{"before":[1],"after":[1,2]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Diff(json.RawMessage(tt.before), json.RawMessage(tt.after))
+			if tt.want == "" {
+				if got != nil {
+					t.Errorf("Diff() = %s, want no change", got)
+				}
+				return
+			}
+			var gotValue, wantValue any
+			if err := json.Unmarshal(got, &gotValue); err != nil {
+				t.Fatalf("Diff() = %s, not JSON: %v", got, err)
+			}
+			_ = json.Unmarshal([]byte(tt.want), &wantValue)
+			gotJSON, _ := json.Marshal(gotValue)
+			wantJSON, _ := json.Marshal(wantValue)
+			if string(gotJSON) != string(wantJSON) {
+				t.Errorf("Diff() = %s, want %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
+
+func TestRecord(t *testing.T) {
+	Record(context.Background(), "topic.update", "topic", "news", nil, map[string]string{"name": "news"})
+
+	change := &Change{}
+	ctx := context.WithValue(context.Background(), ChangeKey, change)
+	if !Enabled(ctx) {
+		t.Fatal("Enabled() = false with a change in the context")
+	}
+	Record(ctx, "api_key.revoke", "api_key", "", map[string]any{"revoked": false}, map[string]any{"revoked": true})
+	if change.Action != "api_key.revoke" || change.ResourceType != "api_key" || change.ResourceID != "" {
+		t.Errorf("unexpected change %+v", change)
+	}
+	if string(change.Diff) != `{"revoked":{"before":false,"after":true}}` {
+		t.Errorf("Diff = %s", change.Diff)
+	}
+}
@@ -0,0 +1,83 @@
+// Package auth issues and checks API keys and carries the authenticated client in the context.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ClientIDKey is the context key of the authenticated client, set by the API key middleware.
+const ClientIDKey = "ClientID"
+
+// TenantIDKey is the context key of the tenant of the caller, unset for the default tenant.
+const TenantIDKey = "TenantID"
+
+const (
+	// KeyPrefix starts every API key, it makes keys recognizable in configs and secret scanners
+	KeyPrefix = "nsk_"
+
+	prefixBytes = 5
+	secretBytes = 32
+)
+
+var prefixEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateKey returns a new API key and its prefix. The key is nsk_<id>_<secret>,
+// the prefix nsk_<id> identifies the key and may be stored and shown in clear.
+func GenerateKey() (key, prefix string, err error) {
+	buf := make([]byte, prefixBytes+secretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("auth.GenerateKey error: %w", err)
+	}
+	prefix = KeyPrefix + strings.ToLower(prefixEncoding.EncodeToString(buf[:prefixBytes]))
+	key = prefix + "_" + base64.RawURLEncoding.EncodeToString(buf[prefixBytes:])
+	return key, prefix, nil
+}
+
+// Prefix returns the prefix of a key, false when the key is not shaped like an API key.
+func Prefix(key string) (string, bool) {
+	if !strings.HasPrefix(key, KeyPrefix) {
+		return "", false
+	}
+	i := strings.IndexByte(key[len(KeyPrefix):], '_')
+	if i <= 0 || len(key) == len(KeyPrefix)+i+1 {
+		return "", false
+	}
+	return key[:len(KeyPrefix)+i], true
+}
+
+// Hash is what is stored for a key. Keys are random, a plain SHA-256 is enough.
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Verify compares a key with a stored hash in constant time.
+func Verify(key, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(Hash(key)), []byte(hash)) == 1
+}
+
+// ClientIDFromContext returns the authenticated client, false for callers without one.
+func ClientIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	clientID, ok := ctx.Value(ClientIDKey).(uuid.UUID)
+	return clientID, ok
+}
+
+// TenantIDFromContext returns the tenant of the caller, nil for the default tenant and
+// for callers that work across tenants, like the workers and unlinked admin tokens.
+func TenantIDFromContext(ctx context.Context) *uuid.UUID {
+	tenantID, ok := ctx.Value(TenantIDKey).(uuid.UUID)
+	if !ok {
+		return nil
+	}
+	return &tenantID
+}
@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestGenerateKey(t *testing.T) {
+	key, prefix, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	if !strings.HasPrefix(key, prefix+"_") || !strings.HasPrefix(prefix, KeyPrefix) {
+		t.Errorf("key %q does not start with prefix %q", key, prefix)
+	}
+	if got, ok := Prefix(key); !ok || got != prefix {
+		t.Errorf("Prefix(%q) = %q, %v, want %q", key, got, ok, prefix)
+	}
+	other, _, _ := GenerateKey()
+	if other == key {
+		t.Error("GenerateKey() returned the same key twice")
+	}
+
+	hash := Hash(key)
+	if !Verify(key, hash) {
+		t.Error("Verify() of the key = false")
+	}
+	if Verify(other, hash) {
+		t.Error("Verify() of another key = true")
+	}
+}
+
+func TestPrefix(t *testing.T) {
+	for _, key := range []string{"", "secret", "nsk_", "nsk_abc", "nsk__secret", "nsk_abc_", "Bearer nsk_abc_x"} {
+		if prefix, ok := Prefix(key); ok {
+			t.Errorf("Prefix(%q) = %q, want not an API key", key, prefix)
+		}
+	}
+}
+
+func TestClientIDFromContext(t *testing.T) {
+	if _, ok := ClientIDFromContext(context.Background()); ok {
+		t.Error("ClientIDFromContext() without a client = true")
+	}
+	clientID := uuid.New()
+	ctx := context.WithValue(context.Background(), ClientIDKey, clientID)
+	if got, ok := ClientIDFromContext(ctx); !ok || got != clientID {
+		t.Errorf("ClientIDFromContext() = %v, %v, want %v", got, ok, clientID)
+	}
+}
+
+func TestIdentity_HasScope(t *testing.T) {
+	reader := &Identity{Scopes: []string{ScopeNotificationsRead}}
+	if !reader.HasScope(ScopeNotificationsRead) || reader.HasScope(ScopeNotificationsWrite) {
+		t.Errorf("reader scopes = %v", reader.Scopes)
+	}
+	admin := &Identity{Scopes: []string{ScopeAdmin}}
+	if !admin.HasScope(ScopeNotificationsWrite) {
+		t.Error("admin does not have every scope")
+	}
+}
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// IdentityKey is the context key of the caller, set by the authentication middleware.
+const IdentityKey = "Identity"
+
+const (
+	ScopeNotificationsRead  = "notifications:read"
+	ScopeNotificationsWrite = "notifications:write"
+	// ScopeNotificationsPII reveals recipients and contents when responses are masked
+	ScopeNotificationsPII = "notifications:pii"
+	// ScopeContactsRead and ScopeContactsWrite cover the contacts of the users, their addresses,
+	// preferences and subscriptions
+	ScopeContactsRead  = "contacts:read"
+	ScopeContactsWrite = "contacts:write"
+	// ScopeAdmin grants every scope and sees the notifications of all clients
+	ScopeAdmin = "admin"
+
+	MethodAPIKey = "api_key"
+	MethodJWT    = "jwt"
+)
+
+// Identity is the authenticated caller, kept for scoping and auditing.
+type Identity struct {
+	Method string
+	// Subject is the key prefix for API keys and the sub claim for tokens
+	Subject string
+	// ClientID is the client whose notifications the caller works with,
+	// nil for an admin token not linked to a client
+	ClientID *uuid.UUID
+	// TenantID is the tenant of the client, nil for the default tenant
+	TenantID *uuid.UUID
+	Scopes   []string
+}
+
+// HasScope tells if the caller was granted the scope, admin grants all of them.
+func (i *Identity) HasScope(scope string) bool {
+	return slices.Contains(i.Scopes, scope) || slices.Contains(i.Scopes, ScopeAdmin)
+}
+
+// IdentityFromContext returns the caller, false for unauthenticated routes and the workers.
+func IdentityFromContext(ctx context.Context) (*Identity, bool) {
+	identity, ok := ctx.Value(IdentityKey).(*Identity)
+	return identity, ok
+}
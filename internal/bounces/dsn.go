@@ -0,0 +1,135 @@
+// Package bounces parses delivery status notifications (RFC 3464) returned by
+// mail servers for messages they could not deliver.
+package bounces
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var ErrNotDSN = errors.New("message is not a delivery status notification")
+
+// Report is a parsed delivery status notification.
+type Report struct {
+	// MessageID is the Message-ID of the original message when the report returns its headers
+	MessageID    string
+	ReportingMTA string
+	Recipients   []Recipient
+}
+
+// Recipient holds the per-recipient fields of the report.
+type Recipient struct {
+	FinalRecipient    string
+	OriginalRecipient string
+	Action            string
+	Status            string
+	DiagnosticCode    string
+}
+
+// Permanent reports whether delivery failed with a 5.x.x status, a 4.x.x status
+// or a delayed action means the server keeps retrying.
+func (r *Recipient) Permanent() bool {
+	return r.Action == "failed" && strings.HasPrefix(r.Status, "5")
+}
+
+// NotificationID extracts the notification ID from a Message-ID of the form <id@domain>.
+func (r *Report) NotificationID() (uuid.UUID, bool) {
+	localPart, _, ok := strings.Cut(strings.Trim(r.MessageID, "<> "), "@")
+	if !ok {
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(localPart)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// ParseDSN reads a multipart/report message and returns its delivery status.
+func ParseDSN(r io.Reader) (*Report, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, fmt.Errorf("bounces.ParseDSN error: %w", err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") || params["boundary"] == "" {
+		return nil, ErrNotDSN
+	}
+
+	report := &Report{}
+	found := false
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := parts.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("bounces.ParseDSN error: %w", err)
+		}
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		switch partType {
+		case "message/delivery-status", "message/global-delivery-status":
+			if err := parseDeliveryStatus(part, report); err != nil {
+				return nil, fmt.Errorf("bounces.ParseDSN error: %w", err)
+			}
+			found = true
+		case "message/rfc822", "text/rfc822-headers", "message/global", "message/global-headers":
+			header, err := textproto.NewReader(bufio.NewReader(part)).ReadMIMEHeader()
+			if err != nil && !errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("bounces.ParseDSN error: %w", err)
+			}
+			report.MessageID = header.Get("Message-Id")
+		}
+	}
+	if !found {
+		return nil, ErrNotDSN
+	}
+	return report, nil
+}
+
+// parseDeliveryStatus reads the per-message field group followed by one group per recipient.
+func parseDeliveryStatus(r io.Reader, report *Report) error {
+	reader := textproto.NewReader(bufio.NewReader(r))
+	first := true
+	for {
+		header, err := reader.ReadMIMEHeader()
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if len(header) != 0 {
+			if first {
+				report.ReportingMTA = fieldValue(header.Get("Reporting-Mta"))
+				first = false
+			} else {
+				report.Recipients = append(report.Recipients, Recipient{
+					FinalRecipient:    fieldValue(header.Get("Final-Recipient")),
+					OriginalRecipient: fieldValue(header.Get("Original-Recipient")),
+					Action:            strings.ToLower(strings.TrimSpace(header.Get("Action"))),
+					Status:            strings.TrimSpace(header.Get("Status")),
+					DiagnosticCode:    fieldValue(header.Get("Diagnostic-Code")),
+				})
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+	}
+}
+
+// fieldValue strips the type prefix of fields like "rfc822; user@example.com".
+func fieldValue(value string) string {
+	if _, v, ok := strings.Cut(value, ";"); ok {
+		return strings.TrimSpace(v)
+	}
+	return strings.TrimSpace(value)
+}
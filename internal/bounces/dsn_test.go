@@ -0,0 +1,74 @@
+package bounces
+
+import (
+	"strings"
+	"testing"
+)
+
+const testDSN = "From: Mail Delivery Subsystem <mailer-daemon@googlemail.com>\r\n" +
+	"To: sender@example.com\r\n" +
+	"Subject: Delivery Status Notification (Failure)\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/report; report-type=delivery-status; boundary=\"b1\"\r\n" +
+	"\r\n" +
+	"--b1\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"\r\n" +
+	"Your message wasn't delivered to nobody@example.org.\r\n" +
+	"--b1\r\n" +
+	"Content-Type: message/delivery-status\r\n" +
+	"\r\n" +
+	"Reporting-MTA: dns; googlemail.com\r\n" +
+	"Arrival-Date: Mon, 19 Oct 2026 10:00:00 -0700\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; nobody@example.org\r\n" +
+	"Action: failed\r\n" +
+	"Status: 5.1.1\r\n" +
+	"Diagnostic-Code: smtp; 550 5.1.1 The email account does not exist\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; busy@example.org\r\n" +
+	"Action: delayed\r\n" +
+	"Status: 4.2.2\r\n" +
+	"\r\n" +
+	"--b1\r\n" +
+	"Content-Type: text/rfc822-headers\r\n" +
+	"\r\n" +
+	"From: sender@example.com\r\n" +
+	"To: nobody@example.org\r\n" +
+	"Message-ID: <0b5a1f0e-4a8d-4a53-9c55-3f3d3c0f0b39@example.com>\r\n" +
+	"Subject: Hello\r\n" +
+	"--b1--\r\n"
+
+func TestParseDSN(t *testing.T) {
+	report, err := ParseDSN(strings.NewReader(testDSN))
+	if err != nil {
+		t.Fatalf("ParseDSN() error = %v", err)
+	}
+	if report.ReportingMTA != "googlemail.com" {
+		t.Errorf("reporting MTA = %q", report.ReportingMTA)
+	}
+	if len(report.Recipients) != 2 {
+		t.Fatalf("recipients = %d, want 2", len(report.Recipients))
+	}
+	failed, delayed := report.Recipients[0], report.Recipients[1]
+	if failed.FinalRecipient != "nobody@example.org" || failed.Status != "5.1.1" || !failed.Permanent() {
+		t.Errorf("unexpected failed recipient %+v", failed)
+	}
+	if failed.DiagnosticCode != "550 5.1.1 The email account does not exist" {
+		t.Errorf("diagnostic code = %q", failed.DiagnosticCode)
+	}
+	if delayed.Permanent() {
+		t.Errorf("delayed recipient %+v reported as permanent", delayed)
+	}
+	id, ok := report.NotificationID()
+	if !ok || id.String() != "0b5a1f0e-4a8d-4a53-9c55-3f3d3c0f0b39" {
+		t.Errorf("NotificationID() = %v, %v", id, ok)
+	}
+}
+
+func TestParseDSN_NotDSN(t *testing.T) {
+	message := "From: someone@example.com\r\nContent-Type: text/plain\r\n\r\nhello\r\n"
+	if _, err := ParseDSN(strings.NewReader(message)); err != ErrNotDSN {
+		t.Errorf("ParseDSN() error = %v, want %v", err, ErrNotDSN)
+	}
+}
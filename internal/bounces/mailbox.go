@@ -0,0 +1,74 @@
+package bounces
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ReadMbox calls fn with every message of an mbox file. Lines quoted as ">From "
+// are unquoted (mboxrd).
+func ReadMbox(r io.Reader, fn func(message []byte) error) error {
+	reader := bufio.NewReader(r)
+	var message bytes.Buffer
+	started := false
+	flush := func() error {
+		if !started {
+			return nil
+		}
+		return fn(bytes.Clone(message.Bytes()))
+	}
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) != 0 {
+			switch {
+			case bytes.HasPrefix(line, []byte("From ")):
+				if err := flush(); err != nil {
+					return err
+				}
+				message.Reset()
+				started = true
+			case started:
+				if unquoted := bytes.TrimLeft(line, ">"); len(unquoted) < len(line) && bytes.HasPrefix(unquoted, []byte("From ")) {
+					line = line[1:]
+				}
+				message.Write(line)
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return flush()
+		}
+		if err != nil {
+			return fmt.Errorf("bounces.ReadMbox error: %w", err)
+		}
+	}
+}
+
+// ReadMaildir calls fn with every message in the new and cur folders of a Maildir.
+func ReadMaildir(dir string, fn func(message []byte) error) error {
+	for _, sub := range []string{"new", "cur"} {
+		entries, err := os.ReadDir(filepath.Join(dir, sub))
+		if err != nil {
+			return fmt.Errorf("bounces.ReadMaildir error: %w", err)
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			message, err := os.ReadFile(filepath.Join(dir, sub, entry.Name()))
+			if err != nil {
+				return fmt.Errorf("bounces.ReadMaildir error: %w", err)
+			}
+			if err := fn(message); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package bounces
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadMbox(t *testing.T) {
+	mbox := "From MAILER-DAEMON Mon Oct 19 10:00:00 2026\n" +
+		"Subject: first\n\n" +
+		">From the mailbox\n" +
+		"\n" +
+		"From MAILER-DAEMON Mon Oct 19 10:05:00 2026\n" +
+		"Subject: second\n\nbody\n"
+
+	var messages []string
+	err := ReadMbox(strings.NewReader(mbox), func(message []byte) error {
+		messages = append(messages, string(message))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ReadMbox() error = %v", err)
+	}
+	if len(messages) != 2 {
+		t.Fatalf("messages = %d, want 2", len(messages))
+	}
+	if messages[0] != "Subject: first\n\nFrom the mailbox\n\n" {
+		t.Errorf("first message = %q", messages[0])
+	}
+	if messages[1] != "Subject: second\n\nbody\n" {
+		t.Errorf("second message = %q", messages[1])
+	}
+}
+
+func TestReadMaildir(t *testing.T) {
+	dir := t.TempDir()
+	for _, sub := range []string{"new", "cur", "tmp"} {
+		if err := os.Mkdir(filepath.Join(dir, sub), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "new", "1.host"), []byte(testDSN), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "cur", "2.host:2,S"), []byte(testDSN), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	reports := 0
+	err := ReadMaildir(dir, func(message []byte) error {
+		if _, err := ParseDSN(strings.NewReader(string(message))); err != nil {
+			return err
+		}
+		reports++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ReadMaildir() error = %v", err)
+	}
+	if reports != 2 {
+		t.Errorf("reports = %d, want 2", reports)
+	}
+}
@@ -0,0 +1,84 @@
+// Package digests renders the summary message of a digest with text/template.
+package digests
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/entities"
+)
+
+// DefaultTemplate is used for digest keys without a template of their own.
+const DefaultTemplate = `You have {{.Count}} new notifications:
+{{range .Items}}- {{.Content}}
+{{end}}`
+
+// Data is what a digest template is executed with.
+type Data struct {
+	Key       string
+	Recipient string
+	Count     int
+	Items     []Item
+	// First and Last are the creation times of the oldest and the newest item
+	First time.Time
+	Last  time.Time
+}
+
+type Item struct {
+	ID        uuid.UUID
+	Content   string
+	Category  string
+	CreatedAt time.Time
+}
+
+// Parse checks the template text.
+func Parse(text string) (*template.Template, error) {
+	tmpl, err := template.New("digest").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("digests.Parse error: %w", err)
+	}
+	return tmpl, nil
+}
+
+// Render executes the template with the items of the digest, an empty text uses DefaultTemplate.
+func Render(text string, digest *entities.Digest) (string, error) {
+	if text == "" {
+		text = DefaultTemplate
+	}
+	tmpl, err := Parse(text)
+	if err != nil {
+		return "", err
+	}
+	data := Data{
+		Key:       digest.Key,
+		Recipient: digest.Recipient,
+		Count:     len(digest.Items),
+		Items:     make([]Item, len(digest.Items)),
+	}
+	for i, notification := range digest.Items {
+		item := Item{
+			ID:        notification.ID,
+			Content:   notification.Content,
+			CreatedAt: notification.CreatedAt,
+		}
+		if notification.Category != nil {
+			item.Category = *notification.Category
+		}
+		data.Items[i] = item
+		if data.First.IsZero() || item.CreatedAt.Before(data.First) {
+			data.First = item.CreatedAt
+		}
+		if item.CreatedAt.After(data.Last) {
+			data.Last = item.CreatedAt
+		}
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("digests.Render error: %w", err)
+	}
+	return b.String(), nil
+}
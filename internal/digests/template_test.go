@@ -0,0 +1,44 @@
+package digests
+
+import (
+	"testing"
+	"time"
+
+	"notification_system/internal/entities"
+)
+
+func TestRender(t *testing.T) {
+	category := "comments"
+	digest := &entities.Digest{
+		Key:       "post-42",
+		Recipient: "user@example.com",
+		Items: []*entities.Notification{
+			{Content: "Alice commented", Category: &category, CreatedAt: time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)},
+			{Content: "Bob commented", Category: &category, CreatedAt: time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	text, err := Render("", digest)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	want := "You have 2 new notifications:\n- Alice commented\n- Bob commented\n"
+	if text != want {
+		t.Errorf("Render() = %q, want %q", text, want)
+	}
+
+	text, err = Render(`{{.Count}} on {{.Key}} since {{.First.Format "15:04"}}{{range .Items}}, {{.Category}}{{end}}`, digest)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if want := "2 on post-42 since 09:00, comments, comments"; text != want {
+		t.Errorf("Render() = %q, want %q", text, want)
+	}
+
+	if _, err := Render("{{.Missing}}", digest); err == nil {
+		t.Error("Render() with an unknown field succeeded")
+	}
+	if _, err := Parse("{{range .Items}}"); err == nil {
+		t.Error("Parse() of an unterminated range succeeded")
+	}
+}
@@ -0,0 +1,85 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/entities"
+)
+
+type (
+	AuditEntry struct {
+		ID           uuid.UUID       `json:"id"`
+		OccurredAt   time.Time       `json:"occurred_at"`
+		RequestID    string          `json:"request_id"`
+		ActorMethod  string          `json:"actor_method,omitempty"`
+		ActorSubject string          `json:"actor_subject,omitempty"`
+		ClientID     *uuid.UUID      `json:"client_id,omitempty"`
+		TenantID     *uuid.UUID      `json:"tenant_id,omitempty"`
+		IP           string          `json:"ip"`
+		Method       string          `json:"method"`
+		Path         string          `json:"path"`
+		StatusCode   int             `json:"status_code"`
+		Action       string          `json:"action"`
+		ResourceType *string         `json:"resource_type,omitempty"`
+		ResourceID   *string         `json:"resource_id,omitempty"`
+		Before       json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+		After        json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+		Diff         json.RawMessage `json:"diff,omitempty" swaggertype:"object"`
+	}
+
+	// AuditRequest is the HTTP side of an audited request, the caller and the change
+	// are taken from the context.
+	AuditRequest struct {
+		RequestID  string
+		IP         string
+		Method     string
+		Path       string
+		StatusCode int
+	}
+
+	AuditSearch struct {
+		ClientID     *uuid.UUID
+		ActorSubject string
+		Action       string
+		ResourceType string
+		ResourceID   string
+		RequestID    string
+		From         *time.Time
+		To           *time.Time
+		Limit        uint
+		Offset       uint
+	}
+)
+
+func AuditEntryEntityToDTO(entry *entities.AuditEntry) *AuditEntry {
+	return &AuditEntry{
+		ID:           entry.ID,
+		OccurredAt:   entry.OccurredAt,
+		RequestID:    entry.RequestID,
+		ActorMethod:  entry.ActorMethod,
+		ActorSubject: entry.ActorSubject,
+		ClientID:     entry.ClientID,
+		TenantID:     entry.TenantID,
+		IP:           entry.IP,
+		Method:       entry.Method,
+		Path:         entry.Path,
+		StatusCode:   entry.StatusCode,
+		Action:       entry.Action,
+		ResourceType: entry.ResourceType,
+		ResourceID:   entry.ResourceID,
+		Before:       entry.Before,
+		After:        entry.After,
+		Diff:         entry.Diff,
+	}
+}
+
+func AuditEntryEntitiesToDTOs(entries []*entities.AuditEntry) []*AuditEntry {
+	entriesResponse := make([]*AuditEntry, len(entries))
+	for i, entry := range entries {
+		entriesResponse[i] = AuditEntryEntityToDTO(entry)
+	}
+	return entriesResponse
+}
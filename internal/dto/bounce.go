@@ -0,0 +1,21 @@
+package dto
+
+import "github.com/google/uuid"
+
+type (
+	BounceResult struct {
+		// NotificationID is set when the report was matched to a notification by its Message-ID
+		NotificationID *uuid.UUID         `json:"notification_id"`
+		Status         string             `json:"status,omitempty"`
+		Recipients     []*BounceRecipient `json:"recipients"`
+	}
+
+	BounceRecipient struct {
+		Address        string `json:"address"`
+		Action         string `json:"action"`
+		Status         string `json:"status"`
+		DiagnosticCode string `json:"diagnostic_code,omitempty"`
+		// Suppressed is true when the bounce was permanent and the address was added to the suppression list
+		Suppressed bool `json:"suppressed"`
+	}
+)
@@ -0,0 +1,125 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/entities"
+)
+
+type (
+	TopicUpdate struct {
+		Description string `json:"description"`
+	}
+
+	Topic struct {
+		Name        string    `json:"name"`
+		Description string    `json:"description"`
+		Subscribers int64     `json:"subscribers"`
+		CreatedAt   time.Time `json:"created_at"`
+		UpdatedAt   time.Time `json:"updated_at"`
+	}
+
+	TopicSubscription struct {
+		Topic     string    `json:"topic"`
+		UserID    string    `json:"user_id"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+
+	// BroadcastCreate targets either the subscribers of Topic or the Segment of the contacts.
+	// The broadcast is fanned out asynchronously into one notification per user, the address
+	// is resolved from the user's contacts when each notification is sent.
+	BroadcastCreate struct {
+		Topic        string            `json:"topic,omitempty"`
+		Segment      *BroadcastSegment `json:"segment,omitempty"`
+		DeliveryType string            `json:"delivery_type"`
+		Content      string            `json:"content"`
+		Priority     string            `json:"priority" enums:"low,normal,high,critical" default:"normal"`
+		Category     string            `json:"category,omitempty"`
+	}
+
+	// BroadcastSegment selects the contacts with a verified address on the delivery type,
+	// only those in one of TimeZones when it is set.
+	BroadcastSegment struct {
+		TimeZones []string `json:"time_zones,omitempty"`
+	}
+
+	Broadcast struct {
+		ID           uuid.UUID         `json:"id"`
+		Topic        *string           `json:"topic,omitempty"`
+		Segment      *BroadcastSegment `json:"segment,omitempty"`
+		DeliveryType string            `json:"delivery_type"`
+		Content      string            `json:"content"`
+		Priority     string            `json:"priority"`
+		Category     *string           `json:"category,omitempty"`
+		Status       string            `json:"status" enums:"pending,running,paused,completed,cancelled"`
+		// Total is the number of users counted when the fan-out started,
+		// Processed the number of notifications created so far
+		Total       *int32     `json:"total,omitempty"`
+		Processed   int32      `json:"processed"`
+		CreatedAt   time.Time  `json:"created_at"`
+		StartedAt   *time.Time `json:"started_at,omitempty"`
+		CompletedAt *time.Time `json:"completed_at,omitempty"`
+	}
+)
+
+func TopicEntityToDTO(topic *entities.Topic) *Topic {
+	return &Topic{
+		Name:        topic.Name,
+		Description: topic.Description,
+		Subscribers: topic.Subscribers,
+		CreatedAt:   topic.CreatedAt,
+		UpdatedAt:   topic.UpdatedAt,
+	}
+}
+
+func TopicEntitiesToDTOs(topics []*entities.Topic) []*Topic {
+	topicsResponse := make([]*Topic, len(topics))
+	for i, topic := range topics {
+		topicsResponse[i] = TopicEntityToDTO(topic)
+	}
+	return topicsResponse
+}
+
+func TopicSubscriptionEntitiesToDTOs(subscriptions []*entities.TopicSubscription) []*TopicSubscription {
+	subscriptionsResponse := make([]*TopicSubscription, len(subscriptions))
+	for i, subscription := range subscriptions {
+		subscriptionsResponse[i] = &TopicSubscription{
+			Topic:     subscription.Topic,
+			UserID:    subscription.UserID,
+			CreatedAt: subscription.CreatedAt,
+		}
+	}
+	return subscriptionsResponse
+}
+
+func BroadcastEntityToDTO(broadcast *entities.Broadcast) *Broadcast {
+	var segment *BroadcastSegment
+	if broadcast.Segment != nil {
+		segment = &BroadcastSegment{TimeZones: broadcast.Segment.TimeZones}
+	}
+	return &Broadcast{
+		ID:           broadcast.ID,
+		Topic:        broadcast.Topic,
+		Segment:      segment,
+		DeliveryType: broadcast.DeliveryType,
+		Content:      broadcast.Content,
+		Priority:     broadcast.Priority,
+		Category:     broadcast.Category,
+		Status:       broadcast.Status,
+		Total:        broadcast.Total,
+		Processed:    broadcast.Processed,
+		CreatedAt:    broadcast.CreatedAt,
+		StartedAt:    broadcast.StartedAt,
+		CompletedAt:  broadcast.CompletedAt,
+	}
+}
+
+func BroadcastEntitiesToDTOs(broadcasts []*entities.Broadcast) []*Broadcast {
+	broadcastsResponse := make([]*Broadcast, len(broadcasts))
+	for i, broadcast := range broadcasts {
+		broadcastsResponse[i] = BroadcastEntityToDTO(broadcast)
+	}
+	return broadcastsResponse
+}
@@ -0,0 +1,91 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/entities"
+)
+
+type (
+	ClientCreate struct {
+		Name string `json:"name"`
+		// OAuthClientID links the tokens issued to this OAuth client to the client
+		OAuthClientID string `json:"oauth_client_id,omitempty"`
+		// TenantID puts the client into a tenant, it is in the default tenant without one
+		TenantID uuid.UUID `json:"tenant_id,omitempty"`
+	}
+
+	Client struct {
+		ID            uuid.UUID  `json:"id"`
+		Name          string     `json:"name"`
+		OAuthClientID *string    `json:"oauth_client_id,omitempty"`
+		TenantID      *uuid.UUID `json:"tenant_id,omitempty"`
+		CreatedAt     time.Time  `json:"created_at"`
+	}
+
+	OAuthClientLink struct {
+		// OAuthClientID is the client_id (or azp) claim of the tokens, empty to unlink
+		OAuthClientID string `json:"oauth_client_id"`
+	}
+
+	// ClientCreated carries the first API key of a new client.
+	ClientCreated struct {
+		Client
+		APIKey *APIKeyCreated `json:"api_key"`
+	}
+
+	APIKey struct {
+		ID     uuid.UUID `json:"id"`
+		Prefix string    `json:"prefix"`
+		// Active is false once the key is revoked or, after a rotation, expired
+		Active    bool       `json:"active"`
+		CreatedAt time.Time  `json:"created_at"`
+		ExpiresAt *time.Time `json:"expires_at,omitempty"`
+		RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	}
+
+	// APIKeyCreated is the only time the key itself is returned, it cannot be retrieved later.
+	APIKeyCreated struct {
+		APIKey
+		Key string `json:"key"`
+	}
+)
+
+func ClientEntityToDTO(client *entities.Client) *Client {
+	return &Client{
+		ID:            client.ID,
+		Name:          client.Name,
+		OAuthClientID: client.OAuthClientID,
+		TenantID:      client.TenantID,
+		CreatedAt:     client.CreatedAt,
+	}
+}
+
+func ClientEntitiesToDTOs(clients []*entities.Client) []*Client {
+	clientsResponse := make([]*Client, len(clients))
+	for i, client := range clients {
+		clientsResponse[i] = ClientEntityToDTO(client)
+	}
+	return clientsResponse
+}
+
+func APIKeyEntityToDTO(key *entities.APIKey) *APIKey {
+	return &APIKey{
+		ID:        key.ID,
+		Prefix:    key.Prefix,
+		Active:    key.Active(time.Now()),
+		CreatedAt: key.CreatedAt,
+		ExpiresAt: key.ExpiresAt,
+		RevokedAt: key.RevokedAt,
+	}
+}
+
+func APIKeyEntitiesToDTOs(keys []*entities.APIKey) []*APIKey {
+	keysResponse := make([]*APIKey, len(keys))
+	for i, key := range keys {
+		keysResponse[i] = APIKeyEntityToDTO(key)
+	}
+	return keysResponse
+}
@@ -0,0 +1,89 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/entities"
+)
+
+type (
+	ContactCreate struct {
+		UserID string `json:"user_id"`
+		Name   string `json:"name"`
+		// TimeZone is an IANA time zone like Europe/Berlin used for quiet hours
+		TimeZone  string                 `json:"time_zone,omitempty"`
+		Addresses []ContactAddressCreate `json:"addresses,omitempty"`
+	}
+
+	ContactUpdate struct {
+		Name     string `json:"name"`
+		TimeZone string `json:"time_zone,omitempty"`
+	}
+
+	Contact struct {
+		UserID    string            `json:"user_id"`
+		Name      string            `json:"name"`
+		TimeZone  string            `json:"time_zone"`
+		Addresses []*ContactAddress `json:"addresses"`
+		CreatedAt time.Time         `json:"created_at"`
+		UpdatedAt time.Time         `json:"updated_at"`
+	}
+
+	ContactAddressCreate struct {
+		DeliveryType string `json:"delivery_type"`
+		Address      string `json:"address"`
+		Primary      bool   `json:"primary"`
+	}
+
+	ContactAddressUpdate struct {
+		Address string `json:"address"`
+		Primary bool   `json:"primary"`
+	}
+
+	ContactAddressVerify struct {
+		Code string `json:"code"`
+	}
+
+	ContactAddress struct {
+		ID           uuid.UUID  `json:"id"`
+		DeliveryType string     `json:"delivery_type"`
+		Address      string     `json:"address"`
+		Primary      bool       `json:"primary"`
+		Verified     bool       `json:"verified"`
+		VerifiedAt   *time.Time `json:"verified_at"`
+		CreatedAt    time.Time  `json:"created_at"`
+	}
+)
+
+func ContactEntityToDTO(contact *entities.Contact, addresses []*entities.ContactAddress) *Contact {
+	return &Contact{
+		UserID:    contact.UserID,
+		Name:      contact.Name,
+		TimeZone:  contact.TimeZone,
+		Addresses: ContactAddressEntitiesToDTOs(addresses),
+		CreatedAt: contact.CreatedAt,
+		UpdatedAt: contact.UpdatedAt,
+	}
+}
+
+func ContactAddressEntityToDTO(address *entities.ContactAddress) *ContactAddress {
+	return &ContactAddress{
+		ID:           address.ID,
+		DeliveryType: address.DeliveryType,
+		Address:      address.Address,
+		Primary:      address.Primary,
+		Verified:     address.Verified(),
+		VerifiedAt:   address.VerifiedAt,
+		CreatedAt:    address.CreatedAt,
+	}
+}
+
+func ContactAddressEntitiesToDTOs(addresses []*entities.ContactAddress) []*ContactAddress {
+	addressesResponse := make([]*ContactAddress, len(addresses))
+	for i, address := range addresses {
+		addressesResponse[i] = ContactAddressEntityToDTO(address)
+	}
+	return addressesResponse
+}
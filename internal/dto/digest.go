@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"time"
+
+	"notification_system/internal/entities"
+)
+
+type (
+	// DigestTemplateUpdate is a text/template executed with the key, the recipient,
+	// the item count, the items (ID, Content, Category, CreatedAt) and the First and Last item times
+	DigestTemplateUpdate struct {
+		Template string `json:"template" example:"{{.Count}} new comments:{{range .Items}} {{.Content}}{{end}}"`
+	}
+
+	DigestTemplate struct {
+		DigestKey string    `json:"digest_key"`
+		Template  string    `json:"template"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+)
+
+func DigestTemplateEntityToDTO(template *entities.DigestTemplate) *DigestTemplate {
+	return &DigestTemplate{
+		DigestKey: template.Key,
+		Template:  template.Template,
+		UpdatedAt: template.UpdatedAt,
+	}
+}
+
+func DigestTemplateEntitiesToDTOs(templates []*entities.DigestTemplate) []*DigestTemplate {
+	templatesResponse := make([]*DigestTemplate, len(templates))
+	for i, template := range templates {
+		templatesResponse[i] = DigestTemplateEntityToDTO(template)
+	}
+	return templatesResponse
+}
@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/entities"
+)
+
+type (
+	// FrequencyCapUpdate sets the cap for a channel and a category. An omitted or "*"
+	// delivery type or category applies to all of them.
+	FrequencyCapUpdate struct {
+		DeliveryType  string `json:"delivery_type" default:"*"`
+		Category      string `json:"category" default:"*"`
+		MaxCount      int32  `json:"max_count" example:"10"`
+		PeriodSeconds int32  `json:"period_seconds" example:"3600"`
+		// Policy decides what happens to notifications over the cap
+		Policy string `json:"policy" enums:"delay,drop" default:"delay"`
+	}
+
+	FrequencyCap struct {
+		ID            uuid.UUID `json:"id"`
+		DeliveryType  string    `json:"delivery_type"`
+		Category      string    `json:"category"`
+		MaxCount      int32     `json:"max_count"`
+		PeriodSeconds int32     `json:"period_seconds"`
+		Policy        string    `json:"policy"`
+		UpdatedAt     time.Time `json:"updated_at"`
+	}
+)
+
+func FrequencyCapEntityToDTO(frequencyCap *entities.FrequencyCap) *FrequencyCap {
+	return &FrequencyCap{
+		ID:            frequencyCap.ID,
+		DeliveryType:  frequencyCap.DeliveryType,
+		Category:      frequencyCap.Category,
+		MaxCount:      frequencyCap.MaxCount,
+		PeriodSeconds: frequencyCap.PeriodSeconds,
+		Policy:        frequencyCap.Policy,
+		UpdatedAt:     frequencyCap.UpdatedAt,
+	}
+}
+
+func FrequencyCapEntitiesToDTOs(caps []*entities.FrequencyCap) []*FrequencyCap {
+	capsResponse := make([]*FrequencyCap, len(caps))
+	for i, frequencyCap := range caps {
+		capsResponse[i] = FrequencyCapEntityToDTO(frequencyCap)
+	}
+	return capsResponse
+}
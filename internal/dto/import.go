@@ -0,0 +1,97 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/entities"
+)
+
+type (
+	// ImportCreate describes how the rows of the file uploaded afterwards become notifications.
+	// Every row needs a recipient or a user ID, Template is a text/template executed with the
+	// variables of the row.
+	ImportCreate struct {
+		Format       string `json:"format" enums:"csv,ndjson"`
+		DeliveryType string `json:"delivery_type"`
+		// RecipientColumn and UserIDColumn name the columns, at least one is required
+		RecipientColumn string `json:"recipient_column,omitempty"`
+		UserIDColumn    string `json:"user_id_column,omitempty"`
+		// Variables maps a template variable to a column,
+		// without it every column is a variable named after it
+		Variables map[string]string `json:"variables,omitempty"`
+		Template  string            `json:"template"`
+		Priority  string            `json:"priority" enums:"low,normal,high,critical" default:"normal"`
+		Category  string            `json:"category,omitempty"`
+	}
+
+	Import struct {
+		ID              uuid.UUID         `json:"id"`
+		Status          string            `json:"status" enums:"awaiting_upload,processing,completed,failed"`
+		Format          string            `json:"format"`
+		DeliveryType    string            `json:"delivery_type"`
+		RecipientColumn string            `json:"recipient_column,omitempty"`
+		UserIDColumn    string            `json:"user_id_column,omitempty"`
+		Variables       map[string]string `json:"variables,omitempty"`
+		Template        string            `json:"template"`
+		Priority        string            `json:"priority"`
+		Category        *string           `json:"category,omitempty"`
+		// TotalRows counts the rows read so far, each of them is created or failed
+		TotalRows   int32      `json:"total_rows"`
+		CreatedRows int32      `json:"created_rows"`
+		FailedRows  int32      `json:"failed_rows"`
+		Error       *string    `json:"error,omitempty"`
+		CreatedAt   time.Time  `json:"created_at"`
+		UpdatedAt   time.Time  `json:"updated_at"`
+		StartedAt   *time.Time `json:"started_at,omitempty"`
+		CompletedAt *time.Time `json:"completed_at,omitempty"`
+	}
+
+	ImportRowError struct {
+		Line    int32  `json:"line"`
+		Message string `json:"message"`
+	}
+)
+
+func ImportEntityToDTO(imp *entities.Import) *Import {
+	return &Import{
+		ID:              imp.ID,
+		Status:          imp.Status,
+		Format:          imp.Format,
+		DeliveryType:    imp.DeliveryType,
+		RecipientColumn: imp.Mapping.RecipientColumn,
+		UserIDColumn:    imp.Mapping.UserIDColumn,
+		Variables:       imp.Mapping.Variables,
+		Template:        imp.Template,
+		Priority:        imp.Priority,
+		Category:        imp.Category,
+		TotalRows:       imp.TotalRows,
+		CreatedRows:     imp.CreatedRows,
+		FailedRows:      imp.FailedRows,
+		Error:           imp.Error,
+		CreatedAt:       imp.CreatedAt,
+		UpdatedAt:       imp.UpdatedAt,
+		StartedAt:       imp.StartedAt,
+		CompletedAt:     imp.CompletedAt,
+	}
+}
+
+func ImportEntitiesToDTOs(imps []*entities.Import) []*Import {
+	impsResponse := make([]*Import, len(imps))
+	for i, imp := range imps {
+		impsResponse[i] = ImportEntityToDTO(imp)
+	}
+	return impsResponse
+}
+
+func ImportRowErrorEntitiesToDTOs(rowErrors []*entities.ImportRowError) []*ImportRowError {
+	rowErrorsResponse := make([]*ImportRowError, len(rowErrors))
+	for i, rowError := range rowErrors {
+		rowErrorsResponse[i] = &ImportRowError{
+			Line:    rowError.Line,
+			Message: rowError.Message,
+		}
+	}
+	return rowErrorsResponse
+}
@@ -12,31 +12,101 @@ type (
 	NotificationCreate struct {
 		DeliveryType string `json:"delivery_type"`
 		Recipient    string `json:"recipient"`
-		Content      string `json:"content"`
+		// UserID targets a registered user, the recipient is then resolved from
+		// the user's addresses when the notification is sent
+		UserID   string `json:"user_id,omitempty"`
+		Content  string `json:"content"`
+		Priority string `json:"priority" enums:"low,normal,high,critical" default:"normal"`
+		// Category is matched against the preferences of the recipient
+		Category string `json:"category,omitempty"`
+		// Channels turns the notification into a fallback chain: the channels are tried in order
+		// and the next one is used when the previous step fails or times out.
+		Channels []NotificationChannelCreate `json:"channels,omitempty"`
+		// DigestKey collects the notification with the other notifications of the recipient
+		// with the same key into one summary sent when the digest window of the oldest one ends
+		DigestKey           string `json:"digest_key,omitempty"`
+		DigestWindowSeconds *int32 `json:"digest_window_seconds,omitempty" default:"3600"`
+	}
+
+	NotificationChannelCreate struct {
+		DeliveryType string `json:"delivery_type"`
+		// Recipient defaults to the recipient of the notification
+		Recipient string `json:"recipient,omitempty"`
+		// Content defaults to the content of the notification
+		Content        *string `json:"content,omitempty"`
+		TimeoutSeconds *int32  `json:"timeout_seconds,omitempty"`
+		Condition      string  `json:"condition,omitempty" enums:"failed_or_timeout,failed,timeout" default:"failed_or_timeout"`
+	}
+
+	NotificationChannel struct {
+		Step           int16   `json:"step"`
+		DeliveryType   string  `json:"delivery_type"`
+		Recipient      string  `json:"recipient"`
+		Content        *string `json:"content,omitempty"`
+		TimeoutSeconds *int32  `json:"timeout_seconds,omitempty"`
+		Condition      string  `json:"condition"`
 	}
 
 	Notification struct {
-		ID           uuid.UUID  `json:"id"`
-		DeliveryType string     `json:"delivery_type"`
-		Recipient    string     `json:"recipient"`
-		Content      string     `json:"content"`
-		Status       string     `json:"status"`
-		Retries      uint8      `json:"retries"`
-		CreatedAt    time.Time  `json:"created_at"`
-		SentAt       *time.Time `json:"sent_at"`
+		ID            uuid.UUID  `json:"id"`
+		DeliveryType  string     `json:"delivery_type"`
+		Recipient     string     `json:"recipient"`
+		Content       string     `json:"content"`
+		Status        string     `json:"status"`
+		Priority      string     `json:"priority"`
+		Retries       uint8      `json:"retries"`
+		CreatedAt     time.Time  `json:"created_at"`
+		SentAt        *time.Time `json:"sent_at"`
+		NextAttemptAt *time.Time `json:"next_attempt_at"`
+		ParentID      *uuid.UUID `json:"parent_id,omitempty"`
+		ChainStep     *int16     `json:"chain_step,omitempty"`
+		UserID        *string    `json:"user_id,omitempty"`
+		Category      *string    `json:"category,omitempty"`
+		StatusReason  *string    `json:"status_reason,omitempty"`
+		// ReleaseAt is set while the notification is held back by quiet hours or a frequency cap
+		ReleaseAt *time.Time `json:"release_at,omitempty"`
+		DigestKey *string    `json:"digest_key,omitempty"`
+		// SummaryID links a digested notification to the summary that was sent instead
+		SummaryID *uuid.UUID `json:"summary_id,omitempty"`
+		// RecurringID links an occurrence to its recurring notification
+		RecurringID *uuid.UUID `json:"recurring_id,omitempty"`
+		// BroadcastID links a notification to the broadcast it was fanned out from
+		BroadcastID *uuid.UUID `json:"broadcast_id,omitempty"`
+		// ImportID links a notification to the import it was created from
+		ImportID *uuid.UUID `json:"import_id,omitempty"`
+		// Channels and Attempts are filled for chain notifications
+		Channels []*NotificationChannel `json:"channels,omitempty"`
+		Attempts []*Notification        `json:"attempts,omitempty"`
 	}
 )
 
 func NotificationEntityToDTO(notification *entities.Notification) *Notification {
+	var releaseAt *time.Time
+	if notification.Status == entities.StatusPending && notification.StatusReason != nil {
+		releaseAt = notification.NextAttemptAt
+	}
 	return &Notification{
-		ID:           notification.ID,
-		DeliveryType: notification.DeliveryType,
-		Recipient:    notification.Recipient,
-		Content:      notification.Content,
-		Status:       notification.Status,
-		Retries:      notification.Retries,
-		CreatedAt:    notification.CreatedAt,
-		SentAt:       notification.SentAt,
+		ID:            notification.ID,
+		DeliveryType:  notification.DeliveryType,
+		Recipient:     notification.Recipient,
+		Content:       notification.Content,
+		Status:        notification.Status,
+		Priority:      notification.Priority,
+		Retries:       notification.Retries,
+		CreatedAt:     notification.CreatedAt,
+		SentAt:        notification.SentAt,
+		NextAttemptAt: notification.NextAttemptAt,
+		ParentID:      notification.ParentID,
+		ChainStep:     notification.ChainStep,
+		UserID:        notification.UserID,
+		Category:      notification.Category,
+		StatusReason:  notification.StatusReason,
+		ReleaseAt:     releaseAt,
+		DigestKey:     notification.DigestKey,
+		SummaryID:     notification.SummaryID,
+		RecurringID:   notification.RecurringID,
+		BroadcastID:   notification.BroadcastID,
+		ImportID:      notification.ImportID,
 	}
 }
 
@@ -47,3 +117,18 @@ func NotificationEntitiesToDTOs(notifications []*entities.Notification) []*Notif
 	}
 	return notificationsResponse
 }
+
+func NotificationChannelEntitiesToDTOs(channels []*entities.NotificationChannel) []*NotificationChannel {
+	channelsResponse := make([]*NotificationChannel, len(channels))
+	for i, channel := range channels {
+		channelsResponse[i] = &NotificationChannel{
+			Step:           channel.Step,
+			DeliveryType:   channel.DeliveryType,
+			Recipient:      channel.Recipient,
+			Content:        channel.Content,
+			TimeoutSeconds: channel.TimeoutSeconds,
+			Condition:      channel.Condition,
+		}
+	}
+	return channelsResponse
+}
@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"time"
+
+	"notification_system/internal/entities"
+)
+
+type (
+	// PreferenceUpdate sets the preference of a user. An omitted or "*" category or
+	// delivery type applies to all of them, the most specific preference wins.
+	PreferenceUpdate struct {
+		Category     string     `json:"category" default:"*"`
+		DeliveryType string     `json:"delivery_type" default:"*"`
+		OptedIn      *bool      `json:"opted_in" default:"true"`
+		MutedUntil   *time.Time `json:"muted_until"`
+	}
+
+	Preference struct {
+		Category     string     `json:"category"`
+		DeliveryType string     `json:"delivery_type"`
+		OptedIn      bool       `json:"opted_in"`
+		MutedUntil   *time.Time `json:"muted_until"`
+		UpdatedAt    time.Time  `json:"updated_at"`
+	}
+
+	CategoryUpdate struct {
+		Description string `json:"description"`
+		// Suppressible false makes notifications of the category ignore preferences
+		Suppressible *bool `json:"suppressible" default:"true"`
+	}
+
+	Category struct {
+		Name         string    `json:"name"`
+		Description  string    `json:"description"`
+		Suppressible bool      `json:"suppressible"`
+		CreatedAt    time.Time `json:"created_at"`
+	}
+)
+
+func PreferenceEntitiesToDTOs(preferences []*entities.NotificationPreference) []*Preference {
+	preferencesResponse := make([]*Preference, len(preferences))
+	for i, preference := range preferences {
+		preferencesResponse[i] = &Preference{
+			Category:     preference.Category,
+			DeliveryType: preference.DeliveryType,
+			OptedIn:      preference.OptedIn,
+			MutedUntil:   preference.MutedUntil,
+			UpdatedAt:    preference.UpdatedAt,
+		}
+	}
+	return preferencesResponse
+}
+
+func CategoryEntityToDTO(category *entities.NotificationCategory) *Category {
+	return &Category{
+		Name:         category.Name,
+		Description:  category.Description,
+		Suppressible: category.Suppressible,
+		CreatedAt:    category.CreatedAt,
+	}
+}
+
+func CategoryEntitiesToDTOs(categories []*entities.NotificationCategory) []*Category {
+	categoriesResponse := make([]*Category, len(categories))
+	for i, category := range categories {
+		categoriesResponse[i] = CategoryEntityToDTO(category)
+	}
+	return categoriesResponse
+}
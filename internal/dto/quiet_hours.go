@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"fmt"
+	"time"
+
+	"notification_system/internal/entities"
+)
+
+type (
+	// QuietHoursUpdate sets a quiet hours rule. An omitted or "*" user or category
+	// makes the rule global or apply to every category of the user.
+	QuietHoursUpdate struct {
+		UserID   string `json:"user_id" default:"*"`
+		Category string `json:"category" default:"*"`
+		// Start and End are local times in the HH:MM format, the window wraps
+		// around midnight when it starts later than it ends
+		Start string `json:"start" example:"22:00"`
+		End   string `json:"end" example:"07:00"`
+		// TimeZone is used for recipients without a time zone of their own, defaults to UTC
+		TimeZone string `json:"time_zone,omitempty" example:"Europe/Berlin"`
+	}
+
+	QuietHours struct {
+		UserID    string    `json:"user_id"`
+		Category  string    `json:"category"`
+		Start     string    `json:"start"`
+		End       string    `json:"end"`
+		TimeZone  string    `json:"time_zone"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+)
+
+func QuietHoursEntityToDTO(quietHours *entities.QuietHours) *QuietHours {
+	return &QuietHours{
+		UserID:    quietHours.UserID,
+		Category:  quietHours.Category,
+		Start:     formatMinute(quietHours.StartMinute),
+		End:       formatMinute(quietHours.EndMinute),
+		TimeZone:  quietHours.TimeZone,
+		UpdatedAt: quietHours.UpdatedAt,
+	}
+}
+
+func QuietHoursEntitiesToDTOs(quietHours []*entities.QuietHours) []*QuietHours {
+	quietHoursResponse := make([]*QuietHours, len(quietHours))
+	for i, rule := range quietHours {
+		quietHoursResponse[i] = QuietHoursEntityToDTO(rule)
+	}
+	return quietHoursResponse
+}
+
+func formatMinute(minute int16) string {
+	return fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+}
@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/entities"
+)
+
+type (
+	ClientQuota struct {
+		DeliveryType string    `json:"delivery_type"`
+		DailyLimit   *int32    `json:"daily_limit,omitempty"`
+		MonthlyLimit *int32    `json:"monthly_limit,omitempty"`
+		UpdatedAt    time.Time `json:"updated_at"`
+	}
+
+	// ClientQuotaUpdate sets the limits of a channel, an omitted limit does not apply.
+	ClientQuotaUpdate struct {
+		DailyLimit   *int32 `json:"daily_limit"`
+		MonthlyLimit *int32 `json:"monthly_limit"`
+	}
+
+	Usage struct {
+		ClientID uuid.UUID       `json:"client_id"`
+		Channels []*ChannelUsage `json:"channels"`
+	}
+
+	ChannelUsage struct {
+		DeliveryType string      `json:"delivery_type"`
+		Daily        PeriodUsage `json:"daily"`
+		Monthly      PeriodUsage `json:"monthly"`
+	}
+
+	// PeriodUsage is the count of notifications of the current UTC day or month,
+	// Limit and Remaining are omitted when no quota applies.
+	PeriodUsage struct {
+		Used      int32     `json:"used"`
+		Limit     *int32    `json:"limit,omitempty"`
+		Remaining *int32    `json:"remaining,omitempty"`
+		ResetsAt  time.Time `json:"resets_at"`
+	}
+)
+
+func ClientQuotaEntityToDTO(quota *entities.ClientQuota) *ClientQuota {
+	return &ClientQuota{
+		DeliveryType: quota.DeliveryType,
+		DailyLimit:   quota.DailyLimit,
+		MonthlyLimit: quota.MonthlyLimit,
+		UpdatedAt:    quota.UpdatedAt,
+	}
+}
+
+func ClientQuotaEntitiesToDTOs(quotas []*entities.ClientQuota) []*ClientQuota {
+	quotasResponse := make([]*ClientQuota, len(quotas))
+	for i, quota := range quotas {
+		quotasResponse[i] = ClientQuotaEntityToDTO(quota)
+	}
+	return quotasResponse
+}
@@ -0,0 +1,89 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/entities"
+)
+
+type (
+	// RecurringNotificationCreate defines a notification sent to every recipient and user
+	// on each occurrence of the schedule. Template is a text/template executed with
+	// .Name, .Recipient, .UserID, .Occurrence, .Sequence and .Variables.
+	RecurringNotificationCreate struct {
+		Name         string `json:"name"`
+		ScheduleType string `json:"schedule_type" enums:"cron,rrule"`
+		// Schedule is a five-field cron expression or an RRULE anchored at StartsAt
+		Schedule     string         `json:"schedule" example:"0 9 * * mon-fri"`
+		TimeZone     string         `json:"time_zone" default:"UTC" example:"Europe/Berlin"`
+		StartsAt     *time.Time     `json:"starts_at,omitempty"`
+		EndsAt       *time.Time     `json:"ends_at,omitempty"`
+		DeliveryType string         `json:"delivery_type"`
+		Recipients   []string       `json:"recipients,omitempty"`
+		UserIDs      []string       `json:"user_ids,omitempty"`
+		Template     string         `json:"template" example:"Standup in 5 minutes, {{.Variables.team}}"`
+		Variables    map[string]any `json:"variables,omitempty"`
+		Priority     string         `json:"priority" enums:"low,normal,high,critical" default:"normal"`
+		Category     string         `json:"category,omitempty"`
+		Enabled      *bool          `json:"enabled,omitempty" default:"true"`
+	}
+
+	RecurringNotification struct {
+		ID           uuid.UUID      `json:"id"`
+		Name         string         `json:"name"`
+		ScheduleType string         `json:"schedule_type"`
+		Schedule     string         `json:"schedule"`
+		TimeZone     string         `json:"time_zone"`
+		StartsAt     time.Time      `json:"starts_at"`
+		EndsAt       *time.Time     `json:"ends_at,omitempty"`
+		DeliveryType string         `json:"delivery_type"`
+		Recipients   []string       `json:"recipients"`
+		UserIDs      []string       `json:"user_ids"`
+		Template     string         `json:"template"`
+		Variables    map[string]any `json:"variables"`
+		Priority     string         `json:"priority"`
+		Category     *string        `json:"category,omitempty"`
+		Enabled      bool           `json:"enabled"`
+		// NextRunAt is omitted once the schedule is exhausted
+		NextRunAt   *time.Time `json:"next_run_at,omitempty"`
+		LastRunAt   *time.Time `json:"last_run_at,omitempty"`
+		Occurrences int32      `json:"occurrences"`
+		CreatedAt   time.Time  `json:"created_at"`
+		UpdatedAt   time.Time  `json:"updated_at"`
+	}
+)
+
+func RecurringNotificationEntityToDTO(recurring *entities.RecurringNotification) *RecurringNotification {
+	return &RecurringNotification{
+		ID:           recurring.ID,
+		Name:         recurring.Name,
+		ScheduleType: recurring.ScheduleType,
+		Schedule:     recurring.Schedule,
+		TimeZone:     recurring.TimeZone,
+		StartsAt:     recurring.StartsAt,
+		EndsAt:       recurring.EndsAt,
+		DeliveryType: recurring.DeliveryType,
+		Recipients:   recurring.Recipients,
+		UserIDs:      recurring.UserIDs,
+		Template:     recurring.Template,
+		Variables:    recurring.Variables,
+		Priority:     recurring.Priority,
+		Category:     recurring.Category,
+		Enabled:      recurring.Enabled,
+		NextRunAt:    recurring.NextRunAt,
+		LastRunAt:    recurring.LastRunAt,
+		Occurrences:  recurring.Occurrences,
+		CreatedAt:    recurring.CreatedAt,
+		UpdatedAt:    recurring.UpdatedAt,
+	}
+}
+
+func RecurringNotificationEntitiesToDTOs(recurringNotifications []*entities.RecurringNotification) []*RecurringNotification {
+	recurringResponse := make([]*RecurringNotification, len(recurringNotifications))
+	for i, recurring := range recurringNotifications {
+		recurringResponse[i] = RecurringNotificationEntityToDTO(recurring)
+	}
+	return recurringResponse
+}
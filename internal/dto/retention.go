@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/entities"
+)
+
+type (
+	RetentionPolicy struct {
+		TenantID      *uuid.UUID `json:"tenant_id,omitempty"`
+		Status        string     `json:"status"`
+		RetentionDays int32      `json:"retention_days"`
+		UpdatedAt     time.Time  `json:"updated_at"`
+	}
+
+	RetentionPolicyUpdate struct {
+		RetentionDays int32 `json:"retention_days"`
+	}
+)
+
+func RetentionPolicyEntityToDTO(policy *entities.RetentionPolicy) *RetentionPolicy {
+	return &RetentionPolicy{
+		TenantID:      policy.TenantID,
+		Status:        policy.Status,
+		RetentionDays: policy.RetentionDays,
+		UpdatedAt:     policy.UpdatedAt,
+	}
+}
+
+func RetentionPolicyEntitiesToDTOs(policies []*entities.RetentionPolicy) []*RetentionPolicy {
+	policiesResponse := make([]*RetentionPolicy, len(policies))
+	for i, policy := range policies {
+		policiesResponse[i] = RetentionPolicyEntityToDTO(policy)
+	}
+	return policiesResponse
+}
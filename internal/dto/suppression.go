@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/entities"
+)
+
+type (
+	SuppressionCreate struct {
+		DeliveryType string     `json:"delivery_type"`
+		Address      string     `json:"address"`
+		Reason       string     `json:"reason" enums:"hard_bounce,complaint,manual" default:"manual"`
+		Details      string     `json:"details"`
+		ExpiresAt    *time.Time `json:"expires_at"`
+	}
+
+	Suppression struct {
+		ID           uuid.UUID  `json:"id"`
+		DeliveryType string     `json:"delivery_type"`
+		Address      string     `json:"address"`
+		Reason       string     `json:"reason"`
+		Details      string     `json:"details"`
+		ExpiresAt    *time.Time `json:"expires_at"`
+		CreatedAt    time.Time  `json:"created_at"`
+	}
+
+	SuppressionSearch struct {
+		DeliveryType string
+		Address      string
+		Reason       string
+		Limit        uint
+		Offset       uint
+	}
+
+	SuppressionImportResult struct {
+		Imported int `json:"imported"`
+	}
+)
+
+func SuppressionEntityToDTO(suppression *entities.Suppression) *Suppression {
+	return &Suppression{
+		ID:           suppression.ID,
+		DeliveryType: suppression.DeliveryType,
+		Address:      suppression.Address,
+		Reason:       suppression.Reason,
+		Details:      suppression.Details,
+		ExpiresAt:    suppression.ExpiresAt,
+		CreatedAt:    suppression.CreatedAt,
+	}
+}
+
+func SuppressionEntitiesToDTOs(suppressions []*entities.Suppression) []*Suppression {
+	suppressionsResponse := make([]*Suppression, len(suppressions))
+	for i, suppression := range suppressions {
+		suppressionsResponse[i] = SuppressionEntityToDTO(suppression)
+	}
+	return suppressionsResponse
+}
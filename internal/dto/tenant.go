@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/entities"
+)
+
+type (
+	TenantCreate struct {
+		Name string `json:"name"`
+		// EmailFrom is the From address of the email of the tenant
+		EmailFrom string `json:"email_from,omitempty"`
+		// SMTPHost sends the email of the tenant through its own server instead of the service one
+		SMTPHost     string `json:"smtp_host,omitempty"`
+		SMTPPort     int32  `json:"smtp_port,omitempty"`
+		SMTPUsername string `json:"smtp_username,omitempty"`
+		SMTPPassword string `json:"smtp_password,omitempty"`
+	}
+
+	// Tenant never carries the SMTP password.
+	Tenant struct {
+		ID           uuid.UUID `json:"id"`
+		Name         string    `json:"name"`
+		EmailFrom    *string   `json:"email_from,omitempty"`
+		SMTPHost     *string   `json:"smtp_host,omitempty"`
+		SMTPPort     *int32    `json:"smtp_port,omitempty"`
+		SMTPUsername *string   `json:"smtp_username,omitempty"`
+		CreatedAt    time.Time `json:"created_at"`
+	}
+)
+
+func TenantEntityToDTO(tenant *entities.Tenant) *Tenant {
+	return &Tenant{
+		ID:           tenant.ID,
+		Name:         tenant.Name,
+		EmailFrom:    tenant.EmailFrom,
+		SMTPHost:     tenant.SMTPHost,
+		SMTPPort:     tenant.SMTPPort,
+		SMTPUsername: tenant.SMTPUsername,
+		CreatedAt:    tenant.CreatedAt,
+	}
+}
+
+func TenantEntitiesToDTOs(tenants []*entities.Tenant) []*Tenant {
+	tenantsResponse := make([]*Tenant, len(tenants))
+	for i, tenant := range tenants {
+		tenantsResponse[i] = TenantEntityToDTO(tenant)
+	}
+	return tenantsResponse
+}
@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/entities"
+)
+
+type (
+	// WebPushSubscriptionCreate mirrors the browser PushSubscription JSON.
+	WebPushSubscriptionCreate struct {
+		Endpoint       string `json:"endpoint"`
+		ExpirationTime *int64 `json:"expirationTime"`
+		Keys           struct {
+			P256dh string `json:"p256dh"`
+			Auth   string `json:"auth"`
+		} `json:"keys"`
+	}
+
+	WebPushSubscription struct {
+		ID             uuid.UUID  `json:"id"`
+		UserID         string     `json:"user_id"`
+		Endpoint       string     `json:"endpoint"`
+		ExpirationTime *time.Time `json:"expiration_time"`
+		CreatedAt      time.Time  `json:"created_at"`
+	}
+
+	VAPIDPublicKey struct {
+		PublicKey string `json:"public_key"`
+	}
+)
+
+func WebPushSubscriptionEntityToDTO(subscription *entities.WebPushSubscription) *WebPushSubscription {
+	return &WebPushSubscription{
+		ID:             subscription.ID,
+		UserID:         subscription.UserID,
+		Endpoint:       subscription.Endpoint,
+		ExpirationTime: subscription.ExpirationTime,
+		CreatedAt:      subscription.CreatedAt,
+	}
+}
+
+func WebPushSubscriptionEntitiesToDTOs(subscriptions []*entities.WebPushSubscription) []*WebPushSubscription {
+	subscriptionsResponse := make([]*WebPushSubscription, len(subscriptions))
+	for i, subscription := range subscriptions {
+		subscriptionsResponse[i] = WebPushSubscriptionEntityToDTO(subscription)
+	}
+	return subscriptionsResponse
+}
@@ -0,0 +1,48 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditEntry records a state-changing API request: who sent it, from where, and the
+// resource it changed with its state before and after.
+type AuditEntry struct {
+	ID         uuid.UUID `db:"id"`
+	OccurredAt time.Time `db:"occurred_at"`
+	RequestID  string    `db:"request_id"`
+	// ActorMethod and ActorSubject identify the caller, empty for anonymous requests
+	ActorMethod  string     `db:"actor_method"`
+	ActorSubject string     `db:"actor_subject"`
+	ClientID     *uuid.UUID `db:"client_id"`
+	TenantID     *uuid.UUID `db:"tenant_id"`
+	IP           string     `db:"ip"`
+	Method       string     `db:"method"`
+	Path         string     `db:"path"`
+	StatusCode   int        `db:"status_code"`
+	// Action is what the request did, like api_key.revoke, or its method and route
+	Action       string  `db:"action"`
+	ResourceType *string `db:"resource_type"`
+	ResourceID   *string `db:"resource_id"`
+	// Before, After and Diff are JSON, Diff holds the top-level fields that changed
+	Before json.RawMessage `db:"before"`
+	After  json.RawMessage `db:"after"`
+	Diff   json.RawMessage `db:"diff"`
+}
+
+// AuditFilter narrows the search of the audit log, empty fields match everything.
+type AuditFilter struct {
+	TenantID     *uuid.UUID
+	ClientID     *uuid.UUID
+	ActorSubject string
+	Action       string
+	ResourceType string
+	ResourceID   string
+	RequestID    string
+	From         *time.Time
+	To           *time.Time
+	Limit        uint
+	Offset       uint
+}
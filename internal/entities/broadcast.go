@@ -0,0 +1,62 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	BroadcastStatusPending   = "pending"
+	BroadcastStatusRunning   = "running"
+	BroadcastStatusPaused    = "paused"
+	BroadcastStatusCompleted = "completed"
+	BroadcastStatusCancelled = "cancelled"
+)
+
+// Topic is something users subscribe to, like the updates of a product.
+type Topic struct {
+	Name        string     `db:"name"`
+	TenantID    *uuid.UUID `db:"tenant_id"`
+	Description string     `db:"description"`
+	Subscribers int64      `db:"subscribers"`
+	CreatedAt   time.Time  `db:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+}
+
+type TopicSubscription struct {
+	Topic     string     `db:"topic"`
+	TenantID  *uuid.UUID `db:"tenant_id"`
+	UserID    string     `db:"user_id"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+// BroadcastSegment selects the contacts with a verified address on the delivery type
+// of the broadcast, optionally only those in one of the time zones.
+type BroadcastSegment struct {
+	TimeZones []string `json:"time_zones,omitempty"`
+}
+
+// Broadcast is fanned out into one notification per user of its topic or segment.
+// Users are expanded in chunks ordered by user ID, Cursor is the last one expanded.
+type Broadcast struct {
+	ID           uuid.UUID         `db:"id"`
+	Topic        *string           `db:"topic"`
+	Segment      *BroadcastSegment `db:"segment"`
+	DeliveryType string            `db:"delivery_type"`
+	Content      string            `db:"content"`
+	Priority     string            `db:"priority"`
+	Category     *string           `db:"category"`
+	Status       string            `db:"status"`
+	// Total is the number of users when the fan-out started
+	Total       *int32     `db:"total"`
+	Processed   int32      `db:"processed"`
+	Cursor      *string    `db:"cursor"`
+	CreatedAt   time.Time  `db:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+	StartedAt   *time.Time `db:"started_at"`
+	CompletedAt *time.Time `db:"completed_at"`
+	// ClientID and TenantID are the owner, the notifications are sent on its behalf
+	ClientID *uuid.UUID `db:"client_id"`
+	TenantID *uuid.UUID `db:"tenant_id"`
+}
@@ -0,0 +1,35 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Client is an application or team calling the API, it sees only its own notifications.
+type Client struct {
+	ID   uuid.UUID `db:"id"`
+	Name string    `db:"name"`
+	// OAuthClientID links the client to the OAuth client its tokens are issued to
+	OAuthClientID *string `db:"oauth_client_id"`
+	// TenantID is the tenant of the client, nil for the default tenant
+	TenantID  *uuid.UUID `db:"tenant_id"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+// APIKey authenticates a client. Only the hash of the key is stored, Prefix identifies it.
+type APIKey struct {
+	ID        uuid.UUID `db:"id"`
+	ClientID  uuid.UUID `db:"client_id"`
+	Prefix    string    `db:"prefix"`
+	KeyHash   string    `db:"key_hash"`
+	CreatedAt time.Time `db:"created_at"`
+	// ExpiresAt is set on a rotated key, it keeps working until then
+	ExpiresAt *time.Time `db:"expires_at"`
+	RevokedAt *time.Time `db:"revoked_at"`
+}
+
+// Active tells if the key can authenticate at the time.
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
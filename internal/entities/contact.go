@@ -0,0 +1,34 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Contact is a user known to the system. Notifications can target the user
+// instead of a raw address, the address is then resolved at send time.
+type Contact struct {
+	UserID   string     `db:"user_id"`
+	TenantID *uuid.UUID `db:"tenant_id"`
+	Name     string     `db:"name"`
+	// TimeZone is the IANA time zone quiet hours are evaluated in, empty means the zone of the rule
+	TimeZone  string    `db:"time_zone"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+type ContactAddress struct {
+	ID           uuid.UUID  `db:"id"`
+	UserID       string     `db:"user_id"`
+	TenantID     *uuid.UUID `db:"tenant_id"`
+	DeliveryType string     `db:"delivery_type"`
+	Address      string     `db:"address"`
+	Primary      bool       `db:"is_primary"`
+	VerifiedAt   *time.Time `db:"verified_at"`
+	CreatedAt    time.Time  `db:"created_at"`
+}
+
+func (a *ContactAddress) Verified() bool {
+	return a.VerifiedAt != nil
+}
@@ -0,0 +1,26 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Digest is the group of notifications of a recipient with the same digest key
+// whose window has ended.
+type Digest struct {
+	Key          string
+	DeliveryType string
+	Recipient    string
+	UserID       *string
+	TenantID     *uuid.UUID
+	Items        []*Notification
+}
+
+// DigestTemplate renders the summary of the digests with the key.
+type DigestTemplate struct {
+	Key       string     `db:"digest_key"`
+	TenantID  *uuid.UUID `db:"tenant_id"`
+	Template  string     `db:"template"`
+	UpdatedAt time.Time  `db:"updated_at"`
+}
@@ -0,0 +1,37 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// FrequencyCapDelay postpones a notification over the cap until a token is available
+	FrequencyCapDelay = "delay"
+	// FrequencyCapDrop suppresses a notification over the cap
+	FrequencyCapDrop = "drop"
+
+	// SuppressionReasonFrequencyCap and DeferReasonFrequencyCap are the status reasons
+	// of dropped and delayed notifications
+	SuppressionReasonFrequencyCap = "frequency_cap"
+	DeferReasonFrequencyCap       = "frequency_cap"
+)
+
+// FrequencyCap limits how many notifications a recipient address gets per period.
+type FrequencyCap struct {
+	ID            uuid.UUID  `db:"id"`
+	TenantID      *uuid.UUID `db:"tenant_id"`
+	DeliveryType  string     `db:"delivery_type"`
+	Category      string     `db:"category"`
+	MaxCount      int32      `db:"max_count"`
+	PeriodSeconds int32      `db:"period_seconds"`
+	Policy        string     `db:"policy"`
+	UpdatedAt     time.Time  `db:"updated_at"`
+}
+
+// FrequencyCapExceeded is the first cap a notification ran into and when its bucket has a token again.
+type FrequencyCapExceeded struct {
+	Cap        *FrequencyCap
+	RetryAfter time.Duration
+}
@@ -0,0 +1,55 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ImportStatusAwaitingUpload = "awaiting_upload"
+	ImportStatusProcessing     = "processing"
+	ImportStatusCompleted      = "completed"
+	ImportStatusFailed         = "failed"
+)
+
+// ImportMapping names the columns of an upload that hold the recipient, the user ID
+// and the template variables, Variables maps a variable name to a column.
+// Without Variables every column is a variable named after it.
+type ImportMapping struct {
+	RecipientColumn string            `json:"recipient_column,omitempty"`
+	UserIDColumn    string            `json:"user_id_column,omitempty"`
+	Variables       map[string]string `json:"variables,omitempty"`
+}
+
+// Import creates one notification for every valid row of an uploaded file,
+// the content is rendered from Template with the variables of the row.
+type Import struct {
+	ID           uuid.UUID     `db:"id"`
+	Status       string        `db:"status"`
+	Format       string        `db:"format"`
+	DeliveryType string        `db:"delivery_type"`
+	Mapping      ImportMapping `db:"mapping"`
+	Template     string        `db:"template"`
+	Priority     string        `db:"priority"`
+	Category     *string       `db:"category"`
+	TotalRows    int32         `db:"total_rows"`
+	CreatedRows  int32         `db:"created_rows"`
+	FailedRows   int32         `db:"failed_rows"`
+	// Error is why the upload could not be processed to the end
+	Error       *string    `db:"error"`
+	CreatedAt   time.Time  `db:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+	StartedAt   *time.Time `db:"started_at"`
+	CompletedAt *time.Time `db:"completed_at"`
+	// ClientID and TenantID are the owner, the notifications are sent on its behalf
+	ClientID *uuid.UUID `db:"client_id"`
+	TenantID *uuid.UUID `db:"tenant_id"`
+}
+
+// ImportRowError is a row of an upload that was skipped, Line is its line in the file.
+type ImportRowError struct {
+	ImportID uuid.UUID `db:"import_id"`
+	Line     int32     `db:"line"`
+	Message  string    `db:"message"`
+}
@@ -1,27 +1,142 @@
 package entities
 
 import (
+	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type Notification struct {
-	ID           uuid.UUID  `db:"id"`
-	DeliveryType string     `db:"delivery_type"`
-	Recipient    string     `db:"recipient"`
-	Content      string     `db:"content"`
-	Status       string     `db:"status"`
-	Retries      uint8      `db:"retries"`
-	CreatedAt    time.Time  `db:"created_at"`
-	SentAt       *time.Time `db:"sent_at"`
+	ID            uuid.UUID  `db:"id"`
+	DeliveryType  string     `db:"delivery_type"`
+	Recipient     string     `db:"recipient"`
+	Content       string     `db:"content"`
+	Status        string     `db:"status"`
+	Priority      string     `db:"priority"`
+	Retries       uint8      `db:"retries"`
+	CreatedAt     time.Time  `db:"created_at"`
+	SentAt        *time.Time `db:"sent_at"`
+	NextAttemptAt *time.Time `db:"next_attempt_at"`
+	ParentID      *uuid.UUID `db:"parent_id"`
+	ChainStep     *int16     `db:"chain_step"`
+	UserID        *string    `db:"user_id"`
+	Category      *string    `db:"category"`
+	StatusReason  *string    `db:"status_reason"`
+	// DigestKey collects the notification into a summary with the other notifications
+	// of the recipient with the same key, the summary carries the key as well
+	DigestKey           *string    `db:"digest_key"`
+	DigestWindowSeconds *int32     `db:"digest_window_seconds"`
+	SummaryID           *uuid.UUID `db:"summary_id"`
+	// RecurringID is the recurring notification the notification is an occurrence of
+	RecurringID *uuid.UUID `db:"recurring_id"`
+	// BroadcastID is the broadcast the notification was fanned out from
+	BroadcastID *uuid.UUID `db:"broadcast_id"`
+	// ImportID is the import the notification was created from
+	ImportID *uuid.UUID `db:"import_id"`
+	// ClientID is the API client that created the notification
+	ClientID *uuid.UUID `db:"client_id"`
+	// TenantID is the tenant the notification belongs to, nil for the default tenant
+	TenantID *uuid.UUID `db:"tenant_id"`
+}
+
+// LogValue leaves the recipient and the content out of the logs, only the length of the content is kept.
+func (n Notification) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("id", n.ID.String()),
+		slog.String("delivery_type", n.DeliveryType),
+		slog.String("status", n.Status),
+		slog.String("priority", n.Priority),
+		slog.Int("retries", int(n.Retries)),
+		slog.Int("content_length", len(n.Content)),
+	}
+	if n.ParentID != nil {
+		attrs = append(attrs, slog.String("parent_id", n.ParentID.String()))
+	}
+	if n.ChainStep != nil {
+		attrs = append(attrs, slog.Int("chain_step", int(*n.ChainStep)))
+	}
+	if n.UserID != nil {
+		attrs = append(attrs, slog.String("user_id", *n.UserID))
+	}
+	if n.Category != nil {
+		attrs = append(attrs, slog.String("category", *n.Category))
+	}
+	if n.ClientID != nil {
+		attrs = append(attrs, slog.String("client_id", n.ClientID.String()))
+	}
+	if n.TenantID != nil {
+		attrs = append(attrs, slog.String("tenant_id", n.TenantID.String()))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// NotificationMessage is the message of a queued notification. It carries only the ID so
+// that recipients and contents never leave the database, the receiver reads the rest.
+type NotificationMessage struct {
+	ID uuid.UUID
+}
+
+// NotificationChannel is a step of a fallback chain. The chain itself is stored
+// as a notification with the chain delivery type, every step is sent as its child.
+type NotificationChannel struct {
+	NotificationID uuid.UUID `db:"notification_id"`
+	Step           int16     `db:"step"`
+	DeliveryType   string    `db:"delivery_type"`
+	Recipient      string    `db:"recipient"`
+	Content        *string   `db:"content"`
+	TimeoutSeconds *int32    `db:"timeout_seconds"`
+	Condition      string    `db:"condition"`
+}
+
+// NotificationChain is a chain notification to create with the channels it falls back through.
+type NotificationChain struct {
+	Notification *Notification
+	Channels     []*NotificationChannel
 }
 
 const (
-	DeliveryTypeEmail = "email"
+	DeliveryTypeTest     = "test"
+	DeliveryTypeEmail    = "email"
+	DeliveryTypeTelegram = "telegram"
+	DeliveryTypeWebhook  = "webhook"
+	DeliveryTypeSlack    = "slack"
+	DeliveryTypeTeams    = "teams"
+	DeliveryTypeDiscord  = "discord"
+	DeliveryTypePush     = "push"
+	DeliveryTypeWebPush  = "web_push"
+	DeliveryTypeChain    = "chain"
 
-	StatusPending   = "pending"
-	StatusInQueue   = "in_queue"
-	StatusDelivered = "delivered"
-	StatusFailed    = "failed"
+	StatusPending    = "pending"
+	StatusInQueue    = "in_queue"
+	StatusDelivered  = "delivered"
+	StatusFailed     = "failed"
+	StatusInProgress = "in_progress"
+	StatusExpired    = "expired"
+	StatusSuppressed = "suppressed"
+	StatusBounced    = "bounced"
+	StatusDigested   = "digested"
+)
+
+const (
+	PriorityLow      = "low"
+	PriorityNormal   = "normal"
+	PriorityHigh     = "high"
+	PriorityCritical = "critical"
+)
+
+const (
+	SuppressionReasonOptedOut = "opted_out"
+	SuppressionReasonMuted    = "muted"
+	// SuppressionReasonUnsubscribed is set when the address used an unsubscribe link
+	SuppressionReasonUnsubscribed = "unsubscribed"
+)
+
+const (
+	// ChainConditionFailedOrTimeout falls back when the step fails or times out
+	ChainConditionFailedOrTimeout = "failed_or_timeout"
+	// ChainConditionFailed falls back only when the step fails, the timeout is ignored
+	ChainConditionFailed = "failed"
+	// ChainConditionTimeout falls back only when the step times out, a failure ends the chain
+	ChainConditionTimeout = "timeout"
 )
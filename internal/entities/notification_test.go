@@ -0,0 +1,32 @@
+package entities
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNotification_LogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	notification := &Notification{
+		ID:           uuid.New(),
+		DeliveryType: DeliveryTypeEmail,
+		Recipient:    "alice@example.com",
+		Content:      "Your code is 123456",
+		Status:       StatusPending,
+	}
+
+	logger.Info("send notification", slog.Any("notification", notification))
+
+	line := buf.String()
+	if strings.Contains(line, "alice@example.com") || strings.Contains(line, "123456") {
+		t.Errorf("log line %q contains the recipient or the content", line)
+	}
+	if !strings.Contains(line, "notification.id="+notification.ID.String()) || !strings.Contains(line, "notification.content_length=19") {
+		t.Errorf("log line %q lacks the ID or the content length", line)
+	}
+}
@@ -0,0 +1,30 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PreferenceAny in the category or the delivery type of a preference matches every value.
+const PreferenceAny = "*"
+
+// NotificationPreference is the choice of a user for a category and a channel.
+type NotificationPreference struct {
+	UserID       string     `db:"user_id"`
+	TenantID     *uuid.UUID `db:"tenant_id"`
+	Category     string     `db:"category"`
+	DeliveryType string     `db:"delivery_type"`
+	OptedIn      bool       `db:"opted_in"`
+	MutedUntil   *time.Time `db:"muted_until"`
+	UpdatedAt    time.Time  `db:"updated_at"`
+}
+
+// NotificationCategory groups notifications for preferences. Notifications of
+// a category that is not suppressible, e.g. transactional ones, ignore preferences.
+type NotificationCategory struct {
+	Name         string    `db:"name"`
+	Description  string    `db:"description"`
+	Suppressible bool      `db:"suppressible"`
+	CreatedAt    time.Time `db:"created_at"`
+}
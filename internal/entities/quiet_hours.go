@@ -0,0 +1,47 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeferReasonQuietHours is the status reason of a pending notification held back by quiet hours.
+const DeferReasonQuietHours = "quiet_hours"
+
+// QuietHours is a daily window in which non-critical notifications are held back.
+// The window wraps around midnight when it starts later than it ends.
+type QuietHours struct {
+	UserID      string     `db:"user_id"`
+	TenantID    *uuid.UUID `db:"tenant_id"`
+	Category    string     `db:"category"`
+	StartMinute int16      `db:"start_minute"`
+	EndMinute   int16      `db:"end_minute"`
+	TimeZone    string     `db:"time_zone"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+}
+
+// ReleaseAt returns when the window that contains now ends in the given location.
+// It returns false when now is outside of the window.
+func (q *QuietHours) ReleaseAt(now time.Time, loc *time.Location) (time.Time, bool) {
+	local := now.In(loc)
+	minute := int16(local.Hour()*60 + local.Minute())
+	start, end := q.StartMinute, q.EndMinute
+
+	var inWindow bool
+	if start < end {
+		inWindow = minute >= start && minute < end
+	} else {
+		inWindow = minute >= start || minute < end
+	}
+	if !inWindow {
+		return time.Time{}, false
+	}
+	day := local.Day()
+	if start > end && minute >= start {
+		day++
+	}
+	// time.Date normalizes the day overflow and DST gaps
+	release := time.Date(local.Year(), local.Month(), day, int(end/60), int(end%60), 0, 0, loc)
+	return release, true
+}
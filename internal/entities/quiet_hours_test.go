@@ -0,0 +1,65 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuietHours_ReleaseAt(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	overnight := &QuietHours{StartMinute: 22 * 60, EndMinute: 7 * 60}
+	daytime := &QuietHours{StartMinute: 12 * 60, EndMinute: 13*60 + 30}
+
+	tests := []struct {
+		name        string
+		quietHours  *QuietHours
+		now         time.Time
+		wantRelease time.Time
+		wantDefer   bool
+	}{
+		{
+			name:        "before midnight",
+			quietHours:  overnight,
+			now:         time.Date(2026, 10, 19, 23, 15, 0, 0, berlin),
+			wantRelease: time.Date(2026, 10, 20, 7, 0, 0, 0, berlin),
+			wantDefer:   true,
+		},
+		{
+			name:        "after midnight in UTC",
+			quietHours:  overnight,
+			now:         time.Date(2026, 10, 20, 1, 0, 0, 0, time.UTC),
+			wantRelease: time.Date(2026, 10, 20, 7, 0, 0, 0, berlin),
+			wantDefer:   true,
+		},
+		{
+			name:       "outside the window",
+			quietHours: overnight,
+			now:        time.Date(2026, 10, 19, 7, 0, 0, 0, berlin),
+		},
+		{
+			name:        "daytime window",
+			quietHours:  daytime,
+			now:         time.Date(2026, 10, 19, 12, 59, 0, 0, berlin),
+			wantRelease: time.Date(2026, 10, 19, 13, 30, 0, 0, berlin),
+			wantDefer:   true,
+		},
+		{
+			name:        "across the end of daylight saving time",
+			quietHours:  overnight,
+			now:         time.Date(2026, 10, 24, 23, 0, 0, 0, berlin),
+			wantRelease: time.Date(2026, 10, 25, 7, 0, 0, 0, berlin),
+			wantDefer:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release, deferred := tt.quietHours.ReleaseAt(tt.now, berlin)
+			if deferred != tt.wantDefer || !release.Equal(tt.wantRelease) {
+				t.Errorf("ReleaseAt() = %v, %v, want %v, %v", release, deferred, tt.wantRelease, tt.wantDefer)
+			}
+		})
+	}
+}
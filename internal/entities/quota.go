@@ -0,0 +1,57 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	QuotaPeriodDay   = "day"
+	QuotaPeriodMonth = "month"
+)
+
+// ClientQuota limits the notifications a client creates on a channel per UTC day and month,
+// a nil limit does not apply.
+type ClientQuota struct {
+	ClientID     uuid.UUID `db:"client_id"`
+	DeliveryType string    `db:"delivery_type"`
+	DailyLimit   *int32    `db:"daily_limit"`
+	MonthlyLimit *int32    `db:"monthly_limit"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+// ClientUsage counts the notifications a client created on a channel in a period.
+type ClientUsage struct {
+	DeliveryType string    `db:"delivery_type"`
+	Period       string    `db:"period"`
+	PeriodStart  time.Time `db:"period_start"`
+	Count        int32     `db:"count"`
+}
+
+// QuotaExceeded is the first quota a batch of notifications ran into.
+type QuotaExceeded struct {
+	DeliveryType string
+	Period       string
+	Limit        int32
+	Used         int32
+	ResetAt      time.Time
+}
+
+// QuotaPeriodStart returns the start of the UTC day or month of the time.
+func QuotaPeriodStart(period string, t time.Time) time.Time {
+	t = t.UTC()
+	if period == QuotaPeriodMonth {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// QuotaPeriodEnd returns when the UTC day or month of the time ends and its counter resets.
+func QuotaPeriodEnd(period string, t time.Time) time.Time {
+	start := QuotaPeriodStart(period, t)
+	if period == QuotaPeriodMonth {
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
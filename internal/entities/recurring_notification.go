@@ -0,0 +1,41 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ScheduleTypeCron  = "cron"
+	ScheduleTypeRRule = "rrule"
+)
+
+// RecurringNotification materializes a notification for every recipient on each
+// occurrence of its schedule. The content is rendered from Template and Variables.
+type RecurringNotification struct {
+	ID           uuid.UUID      `db:"id"`
+	Name         string         `db:"name"`
+	ScheduleType string         `db:"schedule_type"`
+	Schedule     string         `db:"schedule"`
+	TimeZone     string         `db:"time_zone"`
+	StartsAt     time.Time      `db:"starts_at"`
+	EndsAt       *time.Time     `db:"ends_at"`
+	DeliveryType string         `db:"delivery_type"`
+	Recipients   []string       `db:"recipients"`
+	UserIDs      []string       `db:"user_ids"`
+	Template     string         `db:"template"`
+	Variables    map[string]any `db:"variables"`
+	Priority     string         `db:"priority"`
+	Category     *string        `db:"category"`
+	Enabled      bool           `db:"enabled"`
+	// NextRunAt is the next occurrence to fire, nil once the schedule is exhausted
+	NextRunAt   *time.Time `db:"next_run_at"`
+	LastRunAt   *time.Time `db:"last_run_at"`
+	Occurrences int32      `db:"occurrences"`
+	CreatedAt   time.Time  `db:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+	// ClientID and TenantID are the owner, the notifications are sent on its behalf
+	ClientID *uuid.UUID `db:"client_id"`
+	TenantID *uuid.UUID `db:"tenant_id"`
+}
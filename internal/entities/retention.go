@@ -0,0 +1,26 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RetentionStatuses are the final statuses a retention policy may be set for.
+var RetentionStatuses = []string{StatusDelivered, StatusFailed, StatusExpired, StatusSuppressed, StatusBounced}
+
+// RetentionPolicy deletes the notifications of a tenant in a final status RetentionDays after
+// they were created. The policies without a tenant apply to the default tenant and to every
+// tenant without its own policy for the status.
+type RetentionPolicy struct {
+	ID            uuid.UUID  `db:"id"`
+	TenantID      *uuid.UUID `db:"tenant_id"`
+	Status        string     `db:"status"`
+	RetentionDays int32      `db:"retention_days"`
+	UpdatedAt     time.Time  `db:"updated_at"`
+}
+
+// Cutoff returns the creation time before which the notifications of the policy are expired.
+func (p *RetentionPolicy) Cutoff(now time.Time) time.Time {
+	return now.AddDate(0, 0, -int(p.RetentionDays))
+}
@@ -0,0 +1,35 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Suppression blocks every send to the address on the channel until it expires.
+type Suppression struct {
+	ID           uuid.UUID  `db:"id"`
+	TenantID     *uuid.UUID `db:"tenant_id"`
+	DeliveryType string     `db:"delivery_type"`
+	Address      string     `db:"address"`
+	Reason       string     `db:"reason"`
+	Details      string     `db:"details"`
+	ExpiresAt    *time.Time `db:"expires_at"`
+	CreatedAt    time.Time  `db:"created_at"`
+}
+
+// SuppressionFilter narrows the search of the suppression list, empty fields match everything.
+type SuppressionFilter struct {
+	TenantID     *uuid.UUID
+	DeliveryType string
+	Address      string
+	Reason       string
+	Limit        uint
+	Offset       uint
+}
+
+const (
+	SuppressionHardBounce = "hard_bounce"
+	SuppressionComplaint  = "complaint"
+	SuppressionManual     = "manual"
+)
@@ -0,0 +1,22 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tenant isolates the clients and notifications of a business unit. Notifications
+// without a tenant belong to the default tenant, which sends with the service configuration.
+type Tenant struct {
+	ID   uuid.UUID `db:"id"`
+	Name string    `db:"name"`
+	// EmailFrom and the SMTP settings replace the service sender for the email of the tenant,
+	// a tenant without them sends with the service sender
+	EmailFrom    *string   `db:"email_from"`
+	SMTPHost     *string   `db:"smtp_host"`
+	SMTPPort     *int32    `db:"smtp_port"`
+	SMTPUsername *string   `db:"smtp_username"`
+	SMTPPassword *string   `db:"smtp_password"`
+	CreatedAt    time.Time `db:"created_at"`
+}
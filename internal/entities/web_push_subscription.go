@@ -0,0 +1,18 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebPushSubscription struct {
+	ID             uuid.UUID  `db:"id"`
+	TenantID       *uuid.UUID `db:"tenant_id"`
+	UserID         string     `db:"user_id"`
+	Endpoint       string     `db:"endpoint"`
+	P256dh         string     `db:"p256dh"`
+	Auth           string     `db:"auth"`
+	ExpirationTime *time.Time `db:"expiration_time"`
+	CreatedAt      time.Time  `db:"created_at"`
+}
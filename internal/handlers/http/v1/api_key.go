@@ -0,0 +1,145 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"notification_system/internal/auth"
+	"notification_system/internal/services"
+)
+
+type APIKeyHTTPHandlers struct {
+	clientService services.ClientService
+}
+
+func NewAPIKeyHTTPHandlers(clientService services.ClientService) APIKeyHandlers {
+	return &APIKeyHTTPHandlers{clientService: clientService}
+}
+
+// GetAPIKeys godoc
+// @Summary List the API keys of the client
+// @Description List the keys of the client the request is authenticated as, the keys themselves are never returned
+// @Tags api-keys
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Success 200 {array} dto.APIKey
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/api-keys [get]
+func (h *APIKeyHTTPHandlers) GetAPIKeys(c *gin.Context) {
+	clientID, _ := auth.ClientIDFromContext(c)
+	keys, err := h.clientService.GetAPIKeys(c, clientID)
+	if err != nil {
+		apiKeyErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, keys)
+}
+
+// CreateAPIKey godoc
+// @Summary Create an API key
+// @Description Create another key for the client, the key is returned only in this response
+// @Tags api-keys
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Success 201 {object} dto.APIKeyCreated
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/api-keys [post]
+func (h *APIKeyHTTPHandlers) CreateAPIKey(c *gin.Context) {
+	clientID, _ := auth.ClientIDFromContext(c)
+	key, err := h.clientService.CreateAPIKey(c, clientID)
+	if err != nil {
+		apiKeyErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusCreated, key)
+}
+
+// RotateAPIKey godoc
+// @Summary Rotate an API key
+// @Description Create a new key that replaces the given one. The old key keeps working for the grace period so it can be rolled out, the new key is returned only in this response
+// @Tags api-keys
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param id path string true "API key ID"
+// @Param grace_seconds query int false "Seconds the old key keeps working, at most 30 days" default(86400)
+// @Success 201 {object} dto.APIKeyCreated
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/api-keys/{id}/rotate [post]
+func (h *APIKeyHTTPHandlers) RotateAPIKey(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid API key ID"})
+		return
+	}
+	grace := services.DefaultRotationGracePeriod
+	if graceStr := c.Query("grace_seconds"); graceStr != "" {
+		value, err := strconv.Atoi(graceStr)
+		if err != nil || value < 0 {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid grace_seconds value"})
+			return
+		}
+		grace = time.Duration(value) * time.Second
+	}
+	clientID, _ := auth.ClientIDFromContext(c)
+	key, err := h.clientService.RotateAPIKey(c, clientID, id, grace)
+	if err != nil {
+		apiKeyErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusCreated, key)
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke an API key
+// @Description Stop the key at once, requests with it are rejected
+// @Tags api-keys
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param id path string true "API key ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/api-keys/{id} [delete]
+func (h *APIKeyHTTPHandlers) RevokeAPIKey(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid API key ID"})
+		return
+	}
+	clientID, _ := auth.ClientIDFromContext(c)
+	if err := h.clientService.RevokeAPIKey(c, clientID, id); err != nil {
+		apiKeyErrorResponse(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func apiKeyErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidGracePeriod):
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrAPIKeyNotFound), errors.Is(err, services.ErrClientNotFound):
+		c.IndentedJSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	default:
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
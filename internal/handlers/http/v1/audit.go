@@ -0,0 +1,172 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"notification_system/internal/dto"
+	"notification_system/internal/services"
+)
+
+type AuditHTTPHandlers struct {
+	auditService services.AuditService
+}
+
+func NewAuditHTTPHandlers(auditService services.AuditService) AuditHandlers {
+	return &AuditHTTPHandlers{auditService: auditService}
+}
+
+// SearchAuditLog godoc
+// @Summary Search the audit log
+// @Description Search the state-changing requests, newest first. Admins of a tenant see the entries of their tenant only
+// @Tags audit
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param client_id query string false "Client ID"
+// @Param actor query string false "Subject of the caller, the API key prefix or the token subject"
+// @Param action query string false "Action, like api_key.revoke"
+// @Param resource_type query string false "Resource type"
+// @Param resource_id query string false "Resource ID"
+// @Param request_id query string false "Request ID"
+// @Param from query string false "Start of the period, RFC 3339"
+// @Param to query string false "End of the period, exclusive, RFC 3339"
+// @Param limit query int false "Limit of entries to return" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {array} dto.AuditEntry
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/audit-log [get]
+func (h *AuditHTTPHandlers) SearchAuditLog(c *gin.Context) {
+	search, ok := auditSearch(c)
+	if !ok {
+		return
+	}
+	const defaultLimit = 50
+	search.Limit = defaultLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 0 {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid limit value"})
+			return
+		}
+		search.Limit = uint(limit)
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid offset value"})
+			return
+		}
+		search.Offset = uint(offset)
+	}
+
+	entries, err := h.auditService.SearchAuditLog(c, search)
+	if err != nil {
+		auditErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, entries)
+}
+
+// ExportAuditLog godoc
+// @Summary Export the audit log
+// @Description Stream every entry matching the filters, oldest first, as CSV or NDJSON
+// @Tags audit
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param format query string false "Export format" Enums(csv, ndjson) default(ndjson)
+// @Param client_id query string false "Client ID"
+// @Param actor query string false "Subject of the caller, the API key prefix or the token subject"
+// @Param action query string false "Action, like api_key.revoke"
+// @Param resource_type query string false "Resource type"
+// @Param resource_id query string false "Resource ID"
+// @Param request_id query string false "Request ID"
+// @Param from query string false "Start of the period, RFC 3339"
+// @Param to query string false "End of the period, exclusive, RFC 3339"
+// @Success 200 {string} string
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/audit-log/export [get]
+func (h *AuditHTTPHandlers) ExportAuditLog(c *gin.Context) {
+	search, ok := auditSearch(c)
+	if !ok {
+		return
+	}
+	format := c.DefaultQuery("format", services.AuditExportNDJSON)
+	contentType := "application/x-ndjson"
+	switch format {
+	case services.AuditExportNDJSON:
+	case services.AuditExportCSV:
+		contentType = csvContentType
+	default:
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid format"})
+		return
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", `attachment; filename="audit-log.`+format+`"`)
+	c.Status(http.StatusOK)
+	if err := h.auditService.ExportAuditLog(c, search, format, c.Writer); err != nil {
+		// an error before the first entry can still be reported, later the export is cut short
+		if !c.Writer.Written() {
+			c.Header("Content-Type", "")
+			c.Header("Content-Disposition", "")
+			auditErrorResponse(c, err)
+		}
+	}
+}
+
+func auditSearch(c *gin.Context) (*dto.AuditSearch, bool) {
+	search := &dto.AuditSearch{
+		ActorSubject: c.Query("actor"),
+		Action:       c.Query("action"),
+		ResourceType: c.Query("resource_type"),
+		ResourceID:   c.Query("resource_id"),
+		RequestID:    c.Query("request_id"),
+	}
+	if clientIDStr := c.Query("client_id"); clientIDStr != "" {
+		clientID, err := uuid.Parse(clientIDStr)
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid client ID"})
+			return nil, false
+		}
+		search.ClientID = &clientID
+	}
+	for _, bound := range []struct {
+		name string
+		time **time.Time
+	}{{"from", &search.From}, {"to", &search.To}} {
+		value := c.Query(bound.name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid " + bound.name + " value"})
+			return nil, false
+		}
+		*bound.time = &t
+	}
+	return search, true
+}
+
+func auditErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidAuditSearch), errors.Is(err, services.ErrTooManyAuditEntries):
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	default:
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
@@ -0,0 +1,61 @@
+package v1
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"notification_system/internal/services"
+)
+
+// maxBounceSize bounds a delivery status notification including the returned original message
+const maxBounceSize = 10 << 20
+
+type BounceHTTPHandlers struct {
+	bounceService services.BounceService
+}
+
+func NewBounceHTTPHandlers(bounceService services.BounceService) BounceHandlers {
+	return &BounceHTTPHandlers{bounceService: bounceService}
+}
+
+// ProcessBounce godoc
+// @Summary Process an email bounce
+// @Description Ingest a raw delivery status notification (RFC 3464). Permanently bounced addresses are added to the suppression list and the notification matched by the Message-ID is marked as bounced. Requires the admin scope
+// @Tags bounces
+// @Accept message/rfc822
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param message body string true "Raw delivery status notification"
+// @Success 200 {object} dto.BounceResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/bounces [post]
+func (h *BounceHTTPHandlers) ProcessBounce(c *gin.Context) {
+	message, err := io.ReadAll(io.LimitReader(c.Request.Body, maxBounceSize+1))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+	if len(message) > maxBounceSize {
+		c.IndentedJSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: "Message too large"})
+		return
+	}
+	result, err := h.bounceService.ProcessBounce(c, message)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidBounce) {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, result)
+}
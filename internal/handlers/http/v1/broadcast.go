@@ -0,0 +1,219 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"notification_system/internal/dto"
+	"notification_system/internal/services"
+)
+
+type BroadcastHTTPHandlers struct {
+	broadcastService services.BroadcastService
+}
+
+func NewBroadcastHTTPHandlers(broadcastService services.BroadcastService) BroadcastHandlers {
+	return &BroadcastHTTPHandlers{broadcastService: broadcastService}
+}
+
+// CreateBroadcast godoc
+// @Summary Broadcast a notification to a topic or a segment
+// @Description Fan out one notification per subscriber of the topic or per contact of the segment. The fan-out runs asynchronously in chunks, poll the broadcast for its progress
+// @Tags broadcasts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param broadcast body dto.BroadcastCreate true "Broadcast"
+// @Success 202 {object} dto.Broadcast
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/broadcasts [post]
+func (h *BroadcastHTTPHandlers) CreateBroadcast(c *gin.Context) {
+	var broadcastCreate dto.BroadcastCreate
+	if err := c.ShouldBindJSON(&broadcastCreate); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+	broadcast, err := h.broadcastService.CreateBroadcast(c, &broadcastCreate)
+	if err != nil {
+		broadcastErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusAccepted, broadcast)
+}
+
+// GetBroadcasts godoc
+// @Summary List broadcasts
+// @Description List broadcasts, the newest first
+// @Tags broadcasts
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param limit query int false "Limit of entries to return" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {array} dto.Broadcast
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/broadcasts [get]
+func (h *BroadcastHTTPHandlers) GetBroadcasts(c *gin.Context) {
+	const defaultLimit = 50
+	limit, offset := uint(defaultLimit), uint(0)
+	if limitStr := c.Query("limit"); limitStr != "" {
+		value, err := strconv.Atoi(limitStr)
+		if err != nil || value < 0 {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid limit value"})
+			return
+		}
+		limit = uint(value)
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		value, err := strconv.Atoi(offsetStr)
+		if err != nil || value < 0 {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid offset value"})
+			return
+		}
+		offset = uint(value)
+	}
+	broadcasts, err := h.broadcastService.GetBroadcasts(c, limit, offset)
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, broadcasts)
+}
+
+// GetBroadcast godoc
+// @Summary Get a broadcast and its progress
+// @Tags broadcasts
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param id path string true "Broadcast ID"
+// @Success 200 {object} dto.Broadcast
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/broadcasts/{id} [get]
+func (h *BroadcastHTTPHandlers) GetBroadcast(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid broadcast ID"})
+		return
+	}
+	broadcast, err := h.broadcastService.GetBroadcast(c, id)
+	if err != nil {
+		broadcastErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, broadcast)
+}
+
+// PauseBroadcast godoc
+// @Summary Pause a broadcast
+// @Description Stop the fan-out after the chunk in progress, notifications already created are sent
+// @Tags broadcasts
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param id path string true "Broadcast ID"
+// @Success 200 {object} dto.Broadcast
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/broadcasts/{id}/pause [post]
+func (h *BroadcastHTTPHandlers) PauseBroadcast(c *gin.Context) {
+	h.changeStatus(c, h.broadcastService.PauseBroadcast)
+}
+
+// ResumeBroadcast godoc
+// @Summary Resume a paused broadcast
+// @Description Continue the fan-out after the last user reached
+// @Tags broadcasts
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param id path string true "Broadcast ID"
+// @Success 200 {object} dto.Broadcast
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/broadcasts/{id}/resume [post]
+func (h *BroadcastHTTPHandlers) ResumeBroadcast(c *gin.Context) {
+	h.changeStatus(c, h.broadcastService.ResumeBroadcast)
+}
+
+// CancelBroadcast godoc
+// @Summary Cancel a broadcast
+// @Description Stop the fan-out for good, notifications already created are sent
+// @Tags broadcasts
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param id path string true "Broadcast ID"
+// @Success 200 {object} dto.Broadcast
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/broadcasts/{id}/cancel [post]
+func (h *BroadcastHTTPHandlers) CancelBroadcast(c *gin.Context) {
+	h.changeStatus(c, h.broadcastService.CancelBroadcast)
+}
+
+func (h *BroadcastHTTPHandlers) changeStatus(c *gin.Context, change func(ctx context.Context, id uuid.UUID) (*dto.Broadcast, error)) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid broadcast ID"})
+		return
+	}
+	broadcast, err := change(c, id)
+	if err != nil {
+		broadcastErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, broadcast)
+}
+
+func broadcastErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidBroadcast),
+		errors.Is(err, services.ErrInvalidPriority),
+		errors.Is(err, services.ErrInvalidCategory),
+		errors.Is(err, services.ErrInvalidTimeZone),
+		errors.Is(err, services.ErrReservedValue):
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrBroadcastNotFound), errors.Is(err, services.ErrTopicNotFound):
+		c.IndentedJSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrInvalidBroadcastTransition):
+		c.IndentedJSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	default:
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
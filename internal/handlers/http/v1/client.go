@@ -0,0 +1,166 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"notification_system/internal/dto"
+	"notification_system/internal/services"
+)
+
+type ClientHTTPHandlers struct {
+	clientService services.ClientService
+}
+
+func NewClientHTTPHandlers(clientService services.ClientService) ClientHandlers {
+	return &ClientHTTPHandlers{clientService: clientService}
+}
+
+// GetClients godoc
+// @Summary List the API clients
+// @Tags clients
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.Client
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/clients [get]
+func (h *ClientHTTPHandlers) GetClients(c *gin.Context) {
+	clients, err := h.clientService.GetClients(c)
+	if err != nil {
+		clientErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, clients)
+}
+
+// CreateClient godoc
+// @Summary Create an API client
+// @Description Create a client with its first API key, the key is returned only in this response
+// @Tags clients
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param client body dto.ClientCreate true "Client"
+// @Success 201 {object} dto.ClientCreated
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/clients [post]
+func (h *ClientHTTPHandlers) CreateClient(c *gin.Context) {
+	var clientCreate dto.ClientCreate
+	if err := c.ShouldBindJSON(&clientCreate); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+	client, err := h.clientService.CreateClient(c, &clientCreate)
+	if err != nil {
+		clientErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusCreated, client)
+}
+
+// LinkOAuthClient godoc
+// @Summary Link an OAuth client
+// @Description Scope the tokens issued to the OAuth client (client_id or azp claim) to the client, an empty ID unlinks it
+// @Tags clients
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Client ID"
+// @Param link body dto.OAuthClientLink true "OAuth client"
+// @Success 200 {object} dto.Client
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/clients/{id}/oauth-client [put]
+func (h *ClientHTTPHandlers) LinkOAuthClient(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid client ID"})
+		return
+	}
+	var link dto.OAuthClientLink
+	if err := c.ShouldBindJSON(&link); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+	client, err := h.clientService.LinkOAuthClient(c, id, link.OAuthClientID)
+	if err != nil {
+		clientErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, client)
+}
+
+// GetTenants godoc
+// @Summary List the tenants
+// @Tags clients
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.Tenant
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/tenants [get]
+func (h *ClientHTTPHandlers) GetTenants(c *gin.Context) {
+	tenants, err := h.clientService.GetTenants(c)
+	if err != nil {
+		clientErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, tenants)
+}
+
+// CreateTenant godoc
+// @Summary Create a tenant
+// @Description Create a tenant with its own email sender, clients are put into it with tenant_id
+// @Tags clients
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param tenant body dto.TenantCreate true "Tenant"
+// @Success 201 {object} dto.Tenant
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/tenants [post]
+func (h *ClientHTTPHandlers) CreateTenant(c *gin.Context) {
+	var tenantCreate dto.TenantCreate
+	if err := c.ShouldBindJSON(&tenantCreate); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+	tenant, err := h.clientService.CreateTenant(c, &tenantCreate)
+	if err != nil {
+		clientErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusCreated, tenant)
+}
+
+func clientErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidClient), errors.Is(err, services.ErrInvalidTenant):
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrClientNotFound), errors.Is(err, services.ErrTenantNotFound):
+		c.IndentedJSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrClientAlreadyExists), errors.Is(err, services.ErrOAuthClientAlreadyLinked),
+		errors.Is(err, services.ErrTenantAlreadyExists):
+		c.IndentedJSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	default:
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
@@ -0,0 +1,314 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"notification_system/internal/dto"
+	"notification_system/internal/services"
+)
+
+type ContactHTTPHandlers struct {
+	contactService services.ContactService
+}
+
+func NewContactHTTPHandlers(contactService services.ContactService) ContactHandlers {
+	return &ContactHTTPHandlers{contactService: contactService}
+}
+
+// CreateContact godoc
+// @Summary Register a user
+// @Description Register a user with the addresses notifications to the user are sent to
+// @Tags contacts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param contact body dto.ContactCreate true "User and addresses"
+// @Success 201 {object} dto.Contact
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users [post]
+func (h *ContactHTTPHandlers) CreateContact(c *gin.Context) {
+	var contactCreate dto.ContactCreate
+	if err := c.ShouldBindJSON(&contactCreate); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+	contact, err := h.contactService.CreateContact(c, &contactCreate)
+	if err != nil {
+		contactErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusCreated, *contact)
+}
+
+// GetContact godoc
+// @Summary Get a user
+// @Description Get a registered user with the addresses
+// @Tags contacts
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Success 200 {object} dto.Contact
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/{user_id} [get]
+func (h *ContactHTTPHandlers) GetContact(c *gin.Context) {
+	contact, err := h.contactService.GetContact(c, c.Param("user_id"))
+	if err != nil {
+		contactErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, *contact)
+}
+
+// UpdateContact godoc
+// @Summary Update a user
+// @Description Update the registered user
+// @Tags contacts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Param contact body dto.ContactUpdate true "User data"
+// @Success 200 {object} dto.Contact
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/{user_id} [put]
+func (h *ContactHTTPHandlers) UpdateContact(c *gin.Context) {
+	var contactUpdate dto.ContactUpdate
+	if err := c.ShouldBindJSON(&contactUpdate); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+	contact, err := h.contactService.UpdateContact(c, c.Param("user_id"), &contactUpdate)
+	if err != nil {
+		contactErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, *contact)
+}
+
+// DeleteContact godoc
+// @Summary Delete a user
+// @Description Delete the registered user with all addresses
+// @Tags contacts
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Success 204
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/{user_id} [delete]
+func (h *ContactHTTPHandlers) DeleteContact(c *gin.Context) {
+	if err := h.contactService.DeleteContact(c, c.Param("user_id")); err != nil {
+		contactErrorResponse(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// AddAddress godoc
+// @Summary Add an address
+// @Description Add an address of the user for a delivery channel
+// @Tags contacts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Param address body dto.ContactAddressCreate true "Address"
+// @Success 201 {object} dto.ContactAddress
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/{user_id}/addresses [post]
+func (h *ContactHTTPHandlers) AddAddress(c *gin.Context) {
+	var addressCreate dto.ContactAddressCreate
+	if err := c.ShouldBindJSON(&addressCreate); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+	address, err := h.contactService.AddAddress(c, c.Param("user_id"), &addressCreate)
+	if err != nil {
+		contactErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusCreated, *address)
+}
+
+// UpdateAddress godoc
+// @Summary Update an address
+// @Description Update the address of the user. A changed address has to be verified again
+// @Tags contacts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Param address_id path string true "Address UUID"
+// @Param address body dto.ContactAddressUpdate true "Address"
+// @Success 200 {object} dto.ContactAddress
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/{user_id}/addresses/{address_id} [put]
+func (h *ContactHTTPHandlers) UpdateAddress(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("address_id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID"})
+		return
+	}
+	var addressUpdate dto.ContactAddressUpdate
+	if err := c.ShouldBindJSON(&addressUpdate); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+	address, err := h.contactService.UpdateAddress(c, c.Param("user_id"), id, &addressUpdate)
+	if err != nil {
+		contactErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, *address)
+}
+
+// DeleteAddress godoc
+// @Summary Delete an address
+// @Description Delete the address of the user
+// @Tags contacts
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Param address_id path string true "Address UUID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/{user_id}/addresses/{address_id} [delete]
+func (h *ContactHTTPHandlers) DeleteAddress(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("address_id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID"})
+		return
+	}
+	if err := h.contactService.DeleteAddress(c, c.Param("user_id"), id); err != nil {
+		contactErrorResponse(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// SendVerificationCode godoc
+// @Summary Send a verification code
+// @Description Send a one-time code to the address. The code expires in 15 minutes
+// @Tags contacts
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Param address_id path string true "Address UUID"
+// @Success 202
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/{user_id}/addresses/{address_id}/verification [post]
+func (h *ContactHTTPHandlers) SendVerificationCode(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("address_id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID"})
+		return
+	}
+	if err := h.contactService.SendVerificationCode(c, c.Param("user_id"), id); err != nil {
+		contactErrorResponse(c, err)
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+// VerifyAddress godoc
+// @Summary Verify an address
+// @Description Confirm the address with the code sent to it
+// @Tags contacts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Param address_id path string true "Address UUID"
+// @Param code body dto.ContactAddressVerify true "Verification code"
+// @Success 200 {object} dto.ContactAddress
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/{user_id}/addresses/{address_id}/verification/confirm [post]
+func (h *ContactHTTPHandlers) VerifyAddress(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("address_id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid ID"})
+		return
+	}
+	var verify dto.ContactAddressVerify
+	if err := c.ShouldBindJSON(&verify); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+	address, err := h.contactService.VerifyAddress(c, c.Param("user_id"), id, &verify)
+	if err != nil {
+		contactErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, *address)
+}
+
+func contactErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidContact),
+		errors.Is(err, services.ErrInvalidContactAddress),
+		errors.Is(err, services.ErrInvalidVerificationCode),
+		errors.Is(err, services.ErrInvalidTimeZone):
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrContactNotFound),
+		errors.Is(err, services.ErrContactAddressNotFound):
+		c.IndentedJSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrContactAlreadyExists),
+		errors.Is(err, services.ErrContactAddressAlreadyExists):
+		c.IndentedJSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	default:
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
@@ -0,0 +1,102 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"notification_system/internal/dto"
+	"notification_system/internal/services"
+)
+
+type DigestHTTPHandlers struct {
+	digestService services.DigestService
+}
+
+func NewDigestHTTPHandlers(digestService services.DigestService) DigestHandlers {
+	return &DigestHTTPHandlers{digestService: digestService}
+}
+
+// GetDigestTemplates godoc
+// @Summary Get digest templates
+// @Description List the templates summaries are rendered with. Keys without a template use a plain list of the items
+// @Tags digests
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Success 200 {array} dto.DigestTemplate
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/digest-templates [get]
+func (h *DigestHTTPHandlers) GetDigestTemplates(c *gin.Context) {
+	templates, err := h.digestService.GetDigestTemplates(c)
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, templates)
+}
+
+// UpdateDigestTemplate godoc
+// @Summary Set a digest template
+// @Description Set the text/template the summaries of the digest key are rendered with
+// @Tags digests
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param digest_key path string true "Digest key"
+// @Param template body dto.DigestTemplateUpdate true "Template"
+// @Success 200 {object} dto.DigestTemplate
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/digest-templates/{digest_key} [put]
+func (h *DigestHTTPHandlers) UpdateDigestTemplate(c *gin.Context) {
+	var templateUpdate dto.DigestTemplateUpdate
+	if err := c.ShouldBindJSON(&templateUpdate); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+	template, err := h.digestService.UpdateDigestTemplate(c, c.Param("digest_key"), &templateUpdate)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidDigestTemplate) {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, template)
+}
+
+// DeleteDigestTemplate godoc
+// @Summary Delete a digest template
+// @Description Summaries of the digest key fall back to the default template
+// @Tags digests
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param digest_key path string true "Digest key"
+// @Success 204
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/digest-templates/{digest_key} [delete]
+func (h *DigestHTTPHandlers) DeleteDigestTemplate(c *gin.Context) {
+	if err := h.digestService.DeleteDigestTemplate(c, c.Param("digest_key")); err != nil {
+		if errors.Is(err, services.ErrDigestTemplateNotFound) {
+			c.IndentedJSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,104 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"notification_system/internal/dto"
+	"notification_system/internal/services"
+)
+
+type FrequencyCapHTTPHandlers struct {
+	frequencyCapService services.FrequencyCapService
+}
+
+func NewFrequencyCapHTTPHandlers(frequencyCapService services.FrequencyCapService) FrequencyCapHandlers {
+	return &FrequencyCapHTTPHandlers{frequencyCapService: frequencyCapService}
+}
+
+// GetFrequencyCaps godoc
+// @Summary Get frequency caps
+// @Description List the caps on notifications per recipient address
+// @Tags frequency-caps
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Success 200 {array} dto.FrequencyCap
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/frequency-caps [get]
+func (h *FrequencyCapHTTPHandlers) GetFrequencyCaps(c *gin.Context) {
+	caps, err := h.frequencyCapService.GetFrequencyCaps(c)
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, caps)
+}
+
+// UpdateFrequencyCap godoc
+// @Summary Set a frequency cap
+// @Description Allow at most max_count notifications per recipient address and channel in period_seconds.
+// @Description Every matching cap applies, notifications over a cap are delayed or dropped as suppressed
+// @Tags frequency-caps
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param frequency_cap body dto.FrequencyCapUpdate true "Frequency cap"
+// @Success 200 {object} dto.FrequencyCap
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/frequency-caps [put]
+func (h *FrequencyCapHTTPHandlers) UpdateFrequencyCap(c *gin.Context) {
+	var capUpdate dto.FrequencyCapUpdate
+	if err := c.ShouldBindJSON(&capUpdate); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+	frequencyCap, err := h.frequencyCapService.UpdateFrequencyCap(c, &capUpdate)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidFrequencyCap) {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, frequencyCap)
+}
+
+// DeleteFrequencyCap godoc
+// @Summary Delete a frequency cap
+// @Description Delete the cap for the channel and the category
+// @Tags frequency-caps
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param delivery_type query string false "Delivery type" default(*)
+// @Param category query string false "Category" default(*)
+// @Success 204
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/frequency-caps [delete]
+func (h *FrequencyCapHTTPHandlers) DeleteFrequencyCap(c *gin.Context) {
+	err := h.frequencyCapService.DeleteFrequencyCap(c, c.Query("delivery_type"), c.Query("category"))
+	if err != nil {
+		if errors.Is(err, services.ErrFrequencyCapNotFound) {
+			c.IndentedJSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -11,6 +11,136 @@ type NotificationHandlers interface {
 	CreateNotifications(c *gin.Context)
 }
 
+type WebPushHandlers interface {
+	GetVAPIDPublicKey(c *gin.Context)
+	Subscribe(c *gin.Context)
+	GetSubscriptions(c *gin.Context)
+	Unsubscribe(c *gin.Context)
+}
+
+type ContactHandlers interface {
+	CreateContact(c *gin.Context)
+	GetContact(c *gin.Context)
+	UpdateContact(c *gin.Context)
+	DeleteContact(c *gin.Context)
+	AddAddress(c *gin.Context)
+	UpdateAddress(c *gin.Context)
+	DeleteAddress(c *gin.Context)
+	SendVerificationCode(c *gin.Context)
+	VerifyAddress(c *gin.Context)
+}
+
+type PreferenceHandlers interface {
+	GetPreferences(c *gin.Context)
+	UpdatePreferences(c *gin.Context)
+	DeletePreference(c *gin.Context)
+	GetCategories(c *gin.Context)
+	UpdateCategory(c *gin.Context)
+}
+
+type UnsubscribeHandlers interface {
+	ConfirmUnsubscribe(c *gin.Context)
+	Unsubscribe(c *gin.Context)
+}
+
+type SuppressionHandlers interface {
+	SearchSuppressions(c *gin.Context)
+	AddSuppression(c *gin.Context)
+	ImportSuppressions(c *gin.Context)
+	DeleteSuppression(c *gin.Context)
+}
+
+type BounceHandlers interface {
+	ProcessBounce(c *gin.Context)
+}
+
+type QuietHoursHandlers interface {
+	GetQuietHours(c *gin.Context)
+	UpdateQuietHours(c *gin.Context)
+	DeleteQuietHours(c *gin.Context)
+}
+
+type FrequencyCapHandlers interface {
+	GetFrequencyCaps(c *gin.Context)
+	UpdateFrequencyCap(c *gin.Context)
+	DeleteFrequencyCap(c *gin.Context)
+}
+
+type DigestHandlers interface {
+	GetDigestTemplates(c *gin.Context)
+	UpdateDigestTemplate(c *gin.Context)
+	DeleteDigestTemplate(c *gin.Context)
+}
+
+type RecurringNotificationHandlers interface {
+	GetRecurringNotifications(c *gin.Context)
+	GetRecurringNotification(c *gin.Context)
+	CreateRecurringNotification(c *gin.Context)
+	UpdateRecurringNotification(c *gin.Context)
+	DeleteRecurringNotification(c *gin.Context)
+}
+
+type TopicHandlers interface {
+	GetTopics(c *gin.Context)
+	UpdateTopic(c *gin.Context)
+	DeleteTopic(c *gin.Context)
+	GetTopicSubscriptions(c *gin.Context)
+	GetUserSubscriptions(c *gin.Context)
+	Subscribe(c *gin.Context)
+	Unsubscribe(c *gin.Context)
+}
+
+type BroadcastHandlers interface {
+	CreateBroadcast(c *gin.Context)
+	GetBroadcasts(c *gin.Context)
+	GetBroadcast(c *gin.Context)
+	PauseBroadcast(c *gin.Context)
+	ResumeBroadcast(c *gin.Context)
+	CancelBroadcast(c *gin.Context)
+}
+
+type ImportHandlers interface {
+	CreateImport(c *gin.Context)
+	UploadImport(c *gin.Context)
+	GetImports(c *gin.Context)
+	GetImport(c *gin.Context)
+	GetImportErrors(c *gin.Context)
+}
+
+type APIKeyHandlers interface {
+	GetAPIKeys(c *gin.Context)
+	CreateAPIKey(c *gin.Context)
+	RotateAPIKey(c *gin.Context)
+	RevokeAPIKey(c *gin.Context)
+}
+
+type ClientHandlers interface {
+	GetClients(c *gin.Context)
+	CreateClient(c *gin.Context)
+	LinkOAuthClient(c *gin.Context)
+	GetTenants(c *gin.Context)
+	CreateTenant(c *gin.Context)
+}
+
+type QuotaHandlers interface {
+	GetUsage(c *gin.Context)
+	GetClientUsage(c *gin.Context)
+	GetQuotas(c *gin.Context)
+	UpdateQuota(c *gin.Context)
+	DeleteQuota(c *gin.Context)
+}
+
+type AuditHandlers interface {
+	SearchAuditLog(c *gin.Context)
+	ExportAuditLog(c *gin.Context)
+}
+
+type RetentionHandlers interface {
+	GetRetentionPolicies(c *gin.Context)
+	UpdateRetentionPolicy(c *gin.Context)
+	DeleteRetentionPolicy(c *gin.Context)
+}
+
 type ErrorResponse struct {
 	Error string `json:"error"`
 }
@@ -0,0 +1,222 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"notification_system/internal/dto"
+	"notification_system/internal/services"
+)
+
+type ImportHTTPHandlers struct {
+	importService services.ImportService
+}
+
+func NewImportHTTPHandlers(importService services.ImportService) ImportHandlers {
+	return &ImportHTTPHandlers{importService: importService}
+}
+
+// CreateImport godoc
+// @Summary Create an import
+// @Description Create an import with the format of the file and the mapping of its columns to the recipient, the user ID and the template variables. Upload the file to the import afterwards
+// @Tags imports
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param import body dto.ImportCreate true "Import"
+// @Success 201 {object} dto.Import
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/imports [post]
+func (h *ImportHTTPHandlers) CreateImport(c *gin.Context) {
+	var importCreate dto.ImportCreate
+	if err := c.ShouldBindJSON(&importCreate); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+	imp, err := h.importService.CreateImport(c, &importCreate)
+	if err != nil {
+		importErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusCreated, imp)
+}
+
+// UploadImport godoc
+// @Summary Upload the file of an import
+// @Description Stream a CSV file with a header row or an NDJSON file with one object per line. Rows are processed while the file is uploaded and their notifications created in batches; invalid rows are skipped and reported. The import is returned with its final status, poll it from another request for the progress
+// @Tags imports
+// @Accept text/csv
+// @Accept application/x-ndjson
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param id path string true "Import ID"
+// @Param file body string true "CSV or NDJSON file"
+// @Success 200 {object} dto.Import
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/imports/{id}/data [put]
+func (h *ImportHTTPHandlers) UploadImport(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid import ID"})
+		return
+	}
+	imp, err := h.importService.UploadImport(c, id, c.Request.Body)
+	if err != nil {
+		importErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, imp)
+}
+
+// GetImports godoc
+// @Summary List imports
+// @Description List imports, the newest first
+// @Tags imports
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param limit query int false "Limit of entries to return" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {array} dto.Import
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/imports [get]
+func (h *ImportHTTPHandlers) GetImports(c *gin.Context) {
+	const defaultLimit = 50
+	limit, offset := uint(defaultLimit), uint(0)
+	if limitStr := c.Query("limit"); limitStr != "" {
+		value, err := strconv.Atoi(limitStr)
+		if err != nil || value < 0 {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid limit value"})
+			return
+		}
+		limit = uint(value)
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		value, err := strconv.Atoi(offsetStr)
+		if err != nil || value < 0 {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid offset value"})
+			return
+		}
+		offset = uint(value)
+	}
+	imps, err := h.importService.GetImports(c, limit, offset)
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, imps)
+}
+
+// GetImport godoc
+// @Summary Get an import and its progress
+// @Tags imports
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param id path string true "Import ID"
+// @Success 200 {object} dto.Import
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/imports/{id} [get]
+func (h *ImportHTTPHandlers) GetImport(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid import ID"})
+		return
+	}
+	imp, err := h.importService.GetImport(c, id)
+	if err != nil {
+		importErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, imp)
+}
+
+// GetImportErrors godoc
+// @Summary List the rows of an import that failed
+// @Description List the failed rows by line, only the first 1000 are kept
+// @Tags imports
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param id path string true "Import ID"
+// @Param limit query int false "Limit of entries to return" default(100)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {array} dto.ImportRowError
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/imports/{id}/errors [get]
+func (h *ImportHTTPHandlers) GetImportErrors(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid import ID"})
+		return
+	}
+	const defaultLimit = 100
+	limit, offset := uint(defaultLimit), uint(0)
+	if limitStr := c.Query("limit"); limitStr != "" {
+		value, err := strconv.Atoi(limitStr)
+		if err != nil || value < 0 {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid limit value"})
+			return
+		}
+		limit = uint(value)
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		value, err := strconv.Atoi(offsetStr)
+		if err != nil || value < 0 {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid offset value"})
+			return
+		}
+		offset = uint(value)
+	}
+	rowErrors, err := h.importService.GetImportErrors(c, id, limit, offset)
+	if err != nil {
+		importErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, rowErrors)
+}
+
+func importErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidImport),
+		errors.Is(err, services.ErrInvalidPriority),
+		errors.Is(err, services.ErrInvalidCategory):
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrImportNotFound):
+		c.IndentedJSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrImportAlreadyUploaded):
+		c.IndentedJSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	default:
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
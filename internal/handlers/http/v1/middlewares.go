@@ -1,15 +1,32 @@
 package v1
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
+	"notification_system/internal/audit"
+	"notification_system/internal/auth"
+	"notification_system/internal/dto"
+	"notification_system/internal/ratelimit"
+	"notification_system/internal/services"
+	"notification_system/pkg/jwt"
 	slogger "notification_system/pkg/logger"
 )
 
-const RequestIDKey = "RequestID"
+const (
+	RequestIDKey = "RequestID"
+	APIKeyHeader = "X-API-Key"
+)
 
 func RequestIDMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -26,3 +43,168 @@ func SetLoggerMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// AuditMiddleware appends every state-changing request to the audit log once it is handled,
+// with the change the service recorded through audit.Record. Reads are not audited.
+func AuditMiddleware(auditService services.AuditService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+		c.Set(audit.ChangeKey, &audit.Change{})
+		c.Next()
+
+		path := c.FullPath()
+		if path == "" {
+			path = c.Request.URL.Path
+		}
+		// the response is sent already, a failure is only logged by the service
+		_ = auditService.RecordRequest(context.WithoutCancel(c), &dto.AuditRequest{
+			RequestID:  c.GetString(RequestIDKey),
+			IP:         c.ClientIP(),
+			Method:     c.Request.Method,
+			Path:       path,
+			StatusCode: c.Writer.Status(),
+		})
+	}
+}
+
+// AuthMiddleware authenticates the request by the API key in the X-API-Key header or a
+// bearer token and puts the caller into the context, see auth.IdentityFromContext. Bearer
+// tokens that are not API keys are verified as JWTs when a verifier is configured.
+func AuthMiddleware(clientService services.ClientService, verifier *jwt.Verifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(APIKeyHeader)
+		token := ""
+		if bearer, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok && key == "" {
+			token = strings.TrimSpace(bearer)
+			if _, ok := auth.Prefix(token); ok {
+				key, token = token, ""
+			}
+		}
+
+		var identity *auth.Identity
+		var err error
+		switch {
+		case key != "":
+			identity, err = clientService.Authenticate(c, key)
+		case token != "" && verifier != nil:
+			identity, err = authenticateToken(c, clientService, verifier, token)
+		case token != "":
+			err = services.ErrInvalidAPIKey
+		default:
+			c.Header("WWW-Authenticate", `Bearer realm="api"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Error: "API key or bearer token required"})
+			return
+		}
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidAPIKey), errors.Is(err, errInvalidToken):
+				c.Header("WWW-Authenticate", `Bearer realm="api", error="invalid_token"`)
+				c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Error: err.Error()})
+			case errors.Is(err, services.ErrClientNotLinked):
+				c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+			default:
+				c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+			}
+			return
+		}
+
+		logger := slogger.GetLoggerFromContext(c).With(
+			slog.String("auth_method", identity.Method),
+			slog.String("subject", identity.Subject),
+		)
+		c.Set(auth.IdentityKey, identity)
+		if identity.ClientID != nil {
+			c.Set(auth.ClientIDKey, *identity.ClientID)
+			logger = logger.With(slog.String("client_id", identity.ClientID.String()))
+		}
+		if identity.TenantID != nil {
+			c.Set(auth.TenantIDKey, *identity.TenantID)
+			logger = logger.With(slog.String("tenant_id", identity.TenantID.String()))
+		}
+		c.Set(slogger.LoggerKey, logger)
+		c.Next()
+	}
+}
+
+var errInvalidToken = errors.New("invalid token")
+
+func authenticateToken(c *gin.Context, clientService services.ClientService, verifier *jwt.Verifier, token string) (*auth.Identity, error) {
+	claims, err := verifier.Verify(c, token)
+	if err != nil {
+		slogger.GetLoggerFromContext(c).Info("token rejected", slog.Any("error", err))
+		return nil, errInvalidToken
+	}
+	return clientService.AuthenticateToken(c, claims)
+}
+
+// RequireScope lets through only callers granted the scope, it must run after AuthMiddleware.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity, ok := auth.IdentityFromContext(c)
+		if !ok || !identity.HasScope(scope) {
+			c.Header("WWW-Authenticate", fmt.Sprintf(`Bearer realm="api", error="insufficient_scope", scope=%q`, scope))
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Error: "insufficient scope, " + scope + " required"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RateLimitMiddleware limits the request rate of every client, callers without a client
+// are limited by their subject. It must run after AuthMiddleware, a nil limiter does not limit.
+func RateLimitMiddleware(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limiter == nil {
+			c.Next()
+			return
+		}
+		key := "anonymous"
+		if clientID, ok := auth.ClientIDFromContext(c); ok {
+			key = "client:" + clientID.String()
+		} else if identity, ok := auth.IdentityFromContext(c); ok {
+			key = identity.Method + ":" + identity.Subject
+		}
+		result := limiter.Allow(key)
+		c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(int(math.Ceil(result.Reset.Seconds()))))
+		if !result.Allowed {
+			setRetryAfter(c, result.RetryAfter)
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, ErrorResponse{Error: "rate limit exceeded"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// setRetryAfter sets the Retry-After header in whole seconds, rounded up.
+func setRetryAfter(c *gin.Context, after time.Duration) {
+	c.Header("Retry-After", strconv.Itoa(max(int(math.Ceil(after.Seconds())), 1)))
+}
+
+// RequireOperator rejects the callers of a tenant, the default tenant operates the service
+// and alone manages the clients and tenants of every tenant.
+func RequireOperator() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if auth.TenantIDFromContext(c) != nil {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Error: "only the default tenant can manage clients and tenants"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireClient rejects callers not scoped to a client, such as admin tokens without a linked client.
+func RequireClient() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := auth.ClientIDFromContext(c); !ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Error: "caller is not linked to a client"})
+			return
+		}
+		c.Next()
+	}
+}
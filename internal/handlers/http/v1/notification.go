@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -25,12 +26,16 @@ func NewNotificationHTTPHandlers(notificationService services.NotificationServic
 
 // GetNotificationByID godoc
 // @Summary Get a notification by its ID
-// @Description Get a notification by its ID
+// @Description Get a notification by its ID. Recipients and contents are masked for callers without the notifications:pii scope when MASK_PII is set.
 // @Tags notifications
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Param id path string true "Notification UUID"
 // @Produce json
 // @Success 200 {object} dto.Notification
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /api/v1/notifications/{id} [get]
@@ -56,12 +61,16 @@ func (h *NotificationHTTPHandlers) GetNotificationByID(c *gin.Context) {
 
 // GetNewNotifications godoc
 // @Summary Get new notifications
-// @Description Get a limited number of the notifications with pending status
+// @Description Get a limited number of the notifications with pending status. Recipients and contents are masked for callers without the notifications:pii scope when MASK_PII is set.
 // @Tags notifications
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Param limit query int false "Limit of notifications to return" default(50)
 // @Produce json
 // @Success 200 {array} dto.Notification
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /api/v1/notifications/new [get]
 func (h *NotificationHTTPHandlers) GetNewNotifications(c *gin.Context) {
@@ -89,12 +98,16 @@ func (h *NotificationHTTPHandlers) GetNewNotifications(c *gin.Context) {
 
 // GetNotificationsByIDs godoc
 // @Summary Get multiple notifications by their IDs
-// @Description Get notifications using a comma-separated list of UUIDs
+// @Description Get notifications using a comma-separated list of UUIDs. Recipients and contents are masked for callers without the notifications:pii scope when MASK_PII is set.
 // @Tags notifications
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Param ids query string true "Comma-separated list of notification UUIDs"
 // @Produce json
 // @Success 200 {array} dto.Notification
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /api/v1/notifications/batch [get]
@@ -129,11 +142,16 @@ func (h *NotificationHTTPHandlers) GetNotificationsByIDs(c *gin.Context) {
 // @Summary Create multiple notifications
 // @Description Accepts a list of notifications to create
 // @Tags notifications
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param notifications body []dto.NotificationCreate true "Data to create notifications"
 // @Success 200 {array} string
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse "Rate limit or daily/monthly quota of a channel reached, see Retry-After"
 // @Failure 500 {object} ErrorResponse
 // @Router /api/v1/notifications [post]
 func (h *NotificationHTTPHandlers) CreateNotifications(c *gin.Context) {
@@ -152,10 +170,20 @@ func (h *NotificationHTTPHandlers) CreateNotifications(c *gin.Context) {
 	}
 	IDs, err := h.notificationService.CreateNotifications(c, notifications)
 	if err != nil {
-		if errors.Is(err, services.ErrTooManyNotificationsToCreate) {
+		if errors.Is(err, services.ErrTooManyNotificationsToCreate) || errors.Is(err, services.ErrInvalidPriority) ||
+			errors.Is(err, services.ErrInvalidChannels) ||
+			errors.Is(err, services.ErrInvalidCategory) ||
+			errors.Is(err, services.ErrInvalidDigest) ||
+			errors.Is(err, services.ErrReservedValue) {
 			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 			return
 		}
+		var quotaErr *services.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			setRetryAfter(c, time.Until(quotaErr.ResetAt))
+			c.IndentedJSON(http.StatusTooManyRequests, ErrorResponse{Error: err.Error()})
+			return
+		}
 		logger.Error("failed to create notifications", slog.Any("error", err))
 		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
@@ -0,0 +1,163 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"notification_system/internal/dto"
+	"notification_system/internal/services"
+)
+
+type PreferenceHTTPHandlers struct {
+	preferenceService services.PreferenceService
+}
+
+func NewPreferenceHTTPHandlers(preferenceService services.PreferenceService) PreferenceHandlers {
+	return &PreferenceHTTPHandlers{preferenceService: preferenceService}
+}
+
+// GetPreferences godoc
+// @Summary Get preferences of a user
+// @Description Get the notification preferences of the user. "*" matches every category or channel
+// @Tags preferences
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Success 200 {array} dto.Preference
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/{user_id}/preferences [get]
+func (h *PreferenceHTTPHandlers) GetPreferences(c *gin.Context) {
+	preferences, err := h.preferenceService.GetPreferences(c, c.Param("user_id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, preferences)
+}
+
+// UpdatePreferences godoc
+// @Summary Update preferences of a user
+// @Description Opt the user in or out of categories and channels or mute them until a time
+// @Tags preferences
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Param preferences body []dto.PreferenceUpdate true "Preferences"
+// @Success 200 {array} dto.Preference
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/{user_id}/preferences [put]
+func (h *PreferenceHTTPHandlers) UpdatePreferences(c *gin.Context) {
+	var preferencesUpdate []*dto.PreferenceUpdate
+	if err := c.ShouldBindJSON(&preferencesUpdate); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+	preferences, err := h.preferenceService.UpdatePreferences(c, c.Param("user_id"), preferencesUpdate)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPreference) {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, preferences)
+}
+
+// DeletePreference godoc
+// @Summary Delete a preference of a user
+// @Description Delete the preference for the category and the channel
+// @Tags preferences
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Param category query string false "Category" default(*)
+// @Param delivery_type query string false "Delivery type" default(*)
+// @Success 204
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/{user_id}/preferences [delete]
+func (h *PreferenceHTTPHandlers) DeletePreference(c *gin.Context) {
+	err := h.preferenceService.DeletePreference(c, c.Param("user_id"), c.Query("category"), c.Query("delivery_type"))
+	if err != nil {
+		if errors.Is(err, services.ErrPreferenceNotFound) {
+			c.IndentedJSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetCategories godoc
+// @Summary Get notification categories
+// @Description Get the categories notifications and preferences refer to
+// @Tags preferences
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Success 200 {array} dto.Category
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/categories [get]
+func (h *PreferenceHTTPHandlers) GetCategories(c *gin.Context) {
+	categories, err := h.preferenceService.GetCategories(c)
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, categories)
+}
+
+// UpdateCategory godoc
+// @Summary Create or update a category
+// @Description Notifications of a non-suppressible category, e.g. transactional ones, ignore preferences. Categories are shared by all tenants, only admins of the default tenant change them
+// @Tags preferences
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param name path string true "Category name"
+// @Param category body dto.CategoryUpdate true "Category"
+// @Success 200 {object} dto.Category
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/categories/{name} [put]
+func (h *PreferenceHTTPHandlers) UpdateCategory(c *gin.Context) {
+	var categoryUpdate dto.CategoryUpdate
+	if err := c.ShouldBindJSON(&categoryUpdate); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+	category, err := h.preferenceService.UpdateCategory(c, c.Param("name"), &categoryUpdate)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCategory) {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, *category)
+}
@@ -0,0 +1,106 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"notification_system/internal/dto"
+	"notification_system/internal/services"
+)
+
+type QuietHoursHTTPHandlers struct {
+	quietHoursService services.QuietHoursService
+}
+
+func NewQuietHoursHTTPHandlers(quietHoursService services.QuietHoursService) QuietHoursHandlers {
+	return &QuietHoursHTTPHandlers{quietHoursService: quietHoursService}
+}
+
+// GetQuietHours godoc
+// @Summary Get quiet hours rules
+// @Description List the quiet hours rules. "*" is the user or category of global rules
+// @Tags quiet-hours
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param user_id query string false "User ID"
+// @Param category query string false "Category"
+// @Success 200 {array} dto.QuietHours
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/quiet-hours [get]
+func (h *QuietHoursHTTPHandlers) GetQuietHours(c *gin.Context) {
+	quietHours, err := h.quietHoursService.GetQuietHours(c, c.Query("user_id"), c.Query("category"))
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, quietHours)
+}
+
+// UpdateQuietHours godoc
+// @Summary Set a quiet hours rule
+// @Description Hold back non-critical notifications during a daily window in the recipient's time zone.
+// @Description A rule for the user wins over a rule for the category, which wins over the global rule
+// @Tags quiet-hours
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param quiet_hours body dto.QuietHoursUpdate true "Quiet hours"
+// @Success 200 {object} dto.QuietHours
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/quiet-hours [put]
+func (h *QuietHoursHTTPHandlers) UpdateQuietHours(c *gin.Context) {
+	var quietHoursUpdate dto.QuietHoursUpdate
+	if err := c.ShouldBindJSON(&quietHoursUpdate); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+	quietHours, err := h.quietHoursService.UpdateQuietHours(c, &quietHoursUpdate)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidQuietHours) || errors.Is(err, services.ErrInvalidTimeZone) {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, quietHours)
+}
+
+// DeleteQuietHours godoc
+// @Summary Delete a quiet hours rule
+// @Description Delete the rule for the user and the category
+// @Tags quiet-hours
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param user_id query string false "User ID" default(*)
+// @Param category query string false "Category" default(*)
+// @Success 204
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/quiet-hours [delete]
+func (h *QuietHoursHTTPHandlers) DeleteQuietHours(c *gin.Context) {
+	err := h.quietHoursService.DeleteQuietHours(c, c.Query("user_id"), c.Query("category"))
+	if err != nil {
+		if errors.Is(err, services.ErrQuietHoursNotFound) {
+			c.IndentedJSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,170 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"notification_system/internal/auth"
+	"notification_system/internal/dto"
+	"notification_system/internal/services"
+)
+
+type QuotaHTTPHandlers struct {
+	quotaService services.QuotaService
+}
+
+func NewQuotaHTTPHandlers(quotaService services.QuotaService) QuotaHandlers {
+	return &QuotaHTTPHandlers{quotaService: quotaService}
+}
+
+// GetUsage godoc
+// @Summary Get the usage of the client
+// @Description Count the notifications the client created per channel in the current UTC day and month, with the quotas that apply
+// @Tags quotas
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Success 200 {object} dto.Usage
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/usage [get]
+func (h *QuotaHTTPHandlers) GetUsage(c *gin.Context) {
+	clientID, _ := auth.ClientIDFromContext(c)
+	usage, err := h.quotaService.GetUsage(c, clientID)
+	if err != nil {
+		quotaErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, usage)
+}
+
+// GetClientUsage godoc
+// @Summary Get the usage of a client
+// @Tags quotas
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Client ID"
+// @Success 200 {object} dto.Usage
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/clients/{id}/usage [get]
+func (h *QuotaHTTPHandlers) GetClientUsage(c *gin.Context) {
+	clientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid client ID"})
+		return
+	}
+	usage, err := h.quotaService.GetUsage(c, clientID)
+	if err != nil {
+		quotaErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, usage)
+}
+
+// GetQuotas godoc
+// @Summary Get the quotas of a client
+// @Tags quotas
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Client ID"
+// @Success 200 {array} dto.ClientQuota
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/clients/{id}/quotas [get]
+func (h *QuotaHTTPHandlers) GetQuotas(c *gin.Context) {
+	clientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid client ID"})
+		return
+	}
+	quotas, err := h.quotaService.GetQuotas(c, clientID)
+	if err != nil {
+		quotaErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, quotas)
+}
+
+// UpdateQuota godoc
+// @Summary Set the quota of a client on a channel
+// @Description Allow the client at most daily_limit notifications per UTC day and monthly_limit per UTC month on the channel.
+// @Description Notifications are counted when they are created, a fallback chain counts against every channel it lists
+// @Tags quotas
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Client ID"
+// @Param delivery_type path string true "Delivery type"
+// @Param quota body dto.ClientQuotaUpdate true "Quota"
+// @Success 200 {object} dto.ClientQuota
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/clients/{id}/quotas/{delivery_type} [put]
+func (h *QuotaHTTPHandlers) UpdateQuota(c *gin.Context) {
+	clientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid client ID"})
+		return
+	}
+	var quotaUpdate dto.ClientQuotaUpdate
+	if err := c.ShouldBindJSON(&quotaUpdate); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+	quota, err := h.quotaService.UpdateQuota(c, clientID, c.Param("delivery_type"), &quotaUpdate)
+	if err != nil {
+		quotaErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, quota)
+}
+
+// DeleteQuota godoc
+// @Summary Delete the quota of a client on a channel
+// @Tags quotas
+// @Security BearerAuth
+// @Param id path string true "Client ID"
+// @Param delivery_type path string true "Delivery type"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/clients/{id}/quotas/{delivery_type} [delete]
+func (h *QuotaHTTPHandlers) DeleteQuota(c *gin.Context) {
+	clientID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid client ID"})
+		return
+	}
+	if err := h.quotaService.DeleteQuota(c, clientID, c.Param("delivery_type")); err != nil {
+		quotaErrorResponse(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func quotaErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidQuota):
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrQuotaNotFound), errors.Is(err, services.ErrClientNotFound):
+		c.IndentedJSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	default:
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
@@ -0,0 +1,204 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"notification_system/internal/dto"
+	"notification_system/internal/services"
+)
+
+type RecurringNotificationHTTPHandlers struct {
+	recurringService services.RecurringNotificationService
+}
+
+func NewRecurringNotificationHTTPHandlers(recurringService services.RecurringNotificationService) RecurringNotificationHandlers {
+	return &RecurringNotificationHTTPHandlers{recurringService: recurringService}
+}
+
+// GetRecurringNotifications godoc
+// @Summary List recurring notifications
+// @Description List recurring notifications in the order they were created
+// @Tags recurring-notifications
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param limit query int false "Limit of entries to return" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {array} dto.RecurringNotification
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/recurring-notifications [get]
+func (h *RecurringNotificationHTTPHandlers) GetRecurringNotifications(c *gin.Context) {
+	const defaultLimit = 50
+	limit, offset := uint(defaultLimit), uint(0)
+	if limitStr := c.Query("limit"); limitStr != "" {
+		value, err := strconv.Atoi(limitStr)
+		if err != nil || value < 0 {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid limit value"})
+			return
+		}
+		limit = uint(value)
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		value, err := strconv.Atoi(offsetStr)
+		if err != nil || value < 0 {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid offset value"})
+			return
+		}
+		offset = uint(value)
+	}
+	recurringNotifications, err := h.recurringService.GetRecurringNotifications(c, limit, offset)
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, recurringNotifications)
+}
+
+// GetRecurringNotification godoc
+// @Summary Get a recurring notification
+// @Tags recurring-notifications
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param id path string true "Recurring notification ID"
+// @Success 200 {object} dto.RecurringNotification
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/recurring-notifications/{id} [get]
+func (h *RecurringNotificationHTTPHandlers) GetRecurringNotification(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid recurring notification ID"})
+		return
+	}
+	recurringNotification, err := h.recurringService.GetRecurringNotification(c, id)
+	if err != nil {
+		recurringNotificationErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, recurringNotification)
+}
+
+// CreateRecurringNotification godoc
+// @Summary Create a recurring notification
+// @Description Schedule a notification to every recipient and user on each occurrence of a cron expression or an RRULE in the time zone
+// @Tags recurring-notifications
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param recurring_notification body dto.RecurringNotificationCreate true "Recurring notification"
+// @Success 201 {object} dto.RecurringNotification
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/recurring-notifications [post]
+func (h *RecurringNotificationHTTPHandlers) CreateRecurringNotification(c *gin.Context) {
+	var recurringCreate dto.RecurringNotificationCreate
+	if err := c.ShouldBindJSON(&recurringCreate); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+	recurringNotification, err := h.recurringService.CreateRecurringNotification(c, &recurringCreate)
+	if err != nil {
+		recurringNotificationErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusCreated, recurringNotification)
+}
+
+// UpdateRecurringNotification godoc
+// @Summary Replace a recurring notification
+// @Description Replace the definition, the next run is computed from now. An omitted starts_at keeps the current one
+// @Tags recurring-notifications
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param id path string true "Recurring notification ID"
+// @Param recurring_notification body dto.RecurringNotificationCreate true "Recurring notification"
+// @Success 200 {object} dto.RecurringNotification
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/recurring-notifications/{id} [put]
+func (h *RecurringNotificationHTTPHandlers) UpdateRecurringNotification(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid recurring notification ID"})
+		return
+	}
+	var recurringUpdate dto.RecurringNotificationCreate
+	if err := c.ShouldBindJSON(&recurringUpdate); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+	recurringNotification, err := h.recurringService.UpdateRecurringNotification(c, id, &recurringUpdate)
+	if err != nil {
+		recurringNotificationErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, recurringNotification)
+}
+
+// DeleteRecurringNotification godoc
+// @Summary Delete a recurring notification
+// @Description Stop the schedule, notifications already materialized are kept
+// @Tags recurring-notifications
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param id path string true "Recurring notification ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/recurring-notifications/{id} [delete]
+func (h *RecurringNotificationHTTPHandlers) DeleteRecurringNotification(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid recurring notification ID"})
+		return
+	}
+	if err := h.recurringService.DeleteRecurringNotification(c, id); err != nil {
+		recurringNotificationErrorResponse(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func recurringNotificationErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidRecurringNotification),
+		errors.Is(err, services.ErrInvalidSchedule),
+		errors.Is(err, services.ErrInvalidTimeZone),
+		errors.Is(err, services.ErrInvalidPriority),
+		errors.Is(err, services.ErrInvalidCategory),
+		errors.Is(err, services.ErrReservedValue):
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrRecurringNotificationNotFound):
+		c.IndentedJSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	default:
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
@@ -0,0 +1,107 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"notification_system/internal/dto"
+	"notification_system/internal/services"
+)
+
+type RetentionHTTPHandlers struct {
+	retentionService services.RetentionService
+}
+
+func NewRetentionHTTPHandlers(retentionService services.RetentionService) RetentionHandlers {
+	return &RetentionHTTPHandlers{retentionService: retentionService}
+}
+
+// GetRetentionPolicies godoc
+// @Summary Get the retention policies
+// @Description Get the retention policies of the tenant of the caller. The policies of the operator apply to the default tenant and to every tenant without its own policy for a status
+// @Tags retention
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Success 200 {array} dto.RetentionPolicy
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/retention-policies [get]
+func (h *RetentionHTTPHandlers) GetRetentionPolicies(c *gin.Context) {
+	policies, err := h.retentionService.GetRetentionPolicies(c)
+	if err != nil {
+		retentionErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, policies)
+}
+
+// UpdateRetentionPolicy godoc
+// @Summary Set the retention of a status
+// @Description Delete the notifications in the final status retention_days after they were created, with their chain steps, digest items and channels.
+// @Description Expired notifications are deleted in batches and written to compressed NDJSON files first when an archive directory is configured. Statuses without a policy are kept
+// @Tags retention
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param status path string true "Status" Enums(delivered, failed, expired, suppressed, bounced)
+// @Param policy body dto.RetentionPolicyUpdate true "Retention policy"
+// @Success 200 {object} dto.RetentionPolicy
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/retention-policies/{status} [put]
+func (h *RetentionHTTPHandlers) UpdateRetentionPolicy(c *gin.Context) {
+	var policyUpdate dto.RetentionPolicyUpdate
+	if err := c.ShouldBindJSON(&policyUpdate); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+	policy, err := h.retentionService.UpdateRetentionPolicy(c, c.Param("status"), &policyUpdate)
+	if err != nil {
+		retentionErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, policy)
+}
+
+// DeleteRetentionPolicy godoc
+// @Summary Delete the retention of a status
+// @Description The notifications of a tenant fall back to the policy of the operator for the status, the ones of the operator are kept
+// @Tags retention
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param status path string true "Status"
+// @Success 204
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/retention-policies/{status} [delete]
+func (h *RetentionHTTPHandlers) DeleteRetentionPolicy(c *gin.Context) {
+	if err := h.retentionService.DeleteRetentionPolicy(c, c.Param("status")); err != nil {
+		retentionErrorResponse(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func retentionErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidRetentionPolicy):
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrRetentionPolicyNotFound), errors.Is(err, services.ErrTenantNotFound):
+		c.IndentedJSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	default:
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
@@ -0,0 +1,243 @@
+package v1
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"notification_system/internal/dto"
+	"notification_system/internal/services"
+)
+
+const csvContentType = "text/csv"
+
+type SuppressionHTTPHandlers struct {
+	suppressionService services.SuppressionService
+}
+
+func NewSuppressionHTTPHandlers(suppressionService services.SuppressionService) SuppressionHandlers {
+	return &SuppressionHTTPHandlers{suppressionService: suppressionService}
+}
+
+// SearchSuppressions godoc
+// @Summary Search the suppression list
+// @Description Search suppressed addresses. The address matches as a case-insensitive substring
+// @Tags suppressions
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param delivery_type query string false "Delivery type"
+// @Param address query string false "Address"
+// @Param reason query string false "Reason" Enums(hard_bounce, complaint, manual)
+// @Param limit query int false "Limit of entries to return" default(50)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {array} dto.Suppression
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/suppressions [get]
+func (h *SuppressionHTTPHandlers) SearchSuppressions(c *gin.Context) {
+	const defaultLimit = 50
+	search := &dto.SuppressionSearch{
+		DeliveryType: c.Query("delivery_type"),
+		Address:      c.Query("address"),
+		Reason:       c.Query("reason"),
+		Limit:        defaultLimit,
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 0 {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid limit value"})
+			return
+		}
+		search.Limit = uint(limit)
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid offset value"})
+			return
+		}
+		search.Offset = uint(offset)
+	}
+
+	suppressions, err := h.suppressionService.SearchSuppressions(c, search)
+	if err != nil {
+		if errors.Is(err, services.ErrTooManySuppressions) {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, suppressions)
+}
+
+// AddSuppression godoc
+// @Summary Add an address to the suppression list
+// @Description Suppress every notification to the address on the channel until the entry expires or is removed
+// @Tags suppressions
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param suppression body dto.SuppressionCreate true "Suppression"
+// @Success 201 {object} dto.Suppression
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/suppressions [post]
+func (h *SuppressionHTTPHandlers) AddSuppression(c *gin.Context) {
+	var suppressionCreate dto.SuppressionCreate
+	if err := c.ShouldBindJSON(&suppressionCreate); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+	suppression, err := h.suppressionService.AddSuppression(c, &suppressionCreate)
+	if err != nil {
+		suppressionErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusCreated, suppression)
+}
+
+// ImportSuppressions godoc
+// @Summary Import addresses into the suppression list
+// @Description Import a JSON array or a CSV file with a header row. CSV columns are delivery_type, address and optionally reason, details and expires_at (RFC 3339)
+// @Tags suppressions
+// @Accept json
+// @Accept text/csv
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param suppressions body []dto.SuppressionCreate true "Suppressions"
+// @Success 200 {object} dto.SuppressionImportResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/suppressions/import [post]
+func (h *SuppressionHTTPHandlers) ImportSuppressions(c *gin.Context) {
+	var suppressionsCreate []*dto.SuppressionCreate
+	if c.ContentType() == csvContentType {
+		var err error
+		suppressionsCreate, err = parseSuppressionsCSV(c.Request.Body)
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+	} else if err := c.ShouldBindJSON(&suppressionsCreate); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+	result, err := h.suppressionService.ImportSuppressions(c, suppressionsCreate)
+	if err != nil {
+		suppressionErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, result)
+}
+
+// DeleteSuppression godoc
+// @Summary Remove an address from the suppression list
+// @Description Remove the entry so notifications to the address are delivered again
+// @Tags suppressions
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param id path string true "Suppression ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/suppressions/{id} [delete]
+func (h *SuppressionHTTPHandlers) DeleteSuppression(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid suppression ID"})
+		return
+	}
+	if err := h.suppressionService.DeleteSuppression(c, id); err != nil {
+		suppressionErrorResponse(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func suppressionErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidSuppression), errors.Is(err, services.ErrTooManySuppressions):
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrSuppressionNotFound):
+		c.IndentedJSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	default:
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
+
+func parseSuppressionsCSV(r io.Reader) ([]*dto.SuppressionCreate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["delivery_type"]; !ok {
+		return nil, errors.New("csv column delivery_type is required")
+	}
+	if _, ok := columns["address"]; !ok {
+		return nil, errors.New("csv column address is required")
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var suppressions []*dto.SuppressionCreate
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return suppressions, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv: %w", err)
+		}
+		suppression := &dto.SuppressionCreate{
+			DeliveryType: field(record, "delivery_type"),
+			Address:      field(record, "address"),
+			Reason:       field(record, "reason"),
+			Details:      field(record, "details"),
+		}
+		if expiresAt := field(record, "expires_at"); expiresAt != "" {
+			t, err := time.Parse(time.RFC3339, expiresAt)
+			if err != nil {
+				return nil, fmt.Errorf("invalid expires_at on line %d", line)
+			}
+			suppression.ExpiresAt = &t
+		}
+		suppressions = append(suppressions, suppression)
+	}
+}
@@ -0,0 +1,220 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"notification_system/internal/dto"
+	"notification_system/internal/services"
+)
+
+type TopicHTTPHandlers struct {
+	topicService services.TopicService
+}
+
+func NewTopicHTTPHandlers(topicService services.TopicService) TopicHandlers {
+	return &TopicHTTPHandlers{topicService: topicService}
+}
+
+// GetTopics godoc
+// @Summary List topics
+// @Description List topics with their number of subscribers
+// @Tags topics
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Success 200 {array} dto.Topic
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/topics [get]
+func (h *TopicHTTPHandlers) GetTopics(c *gin.Context) {
+	topics, err := h.topicService.GetTopics(c)
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, topics)
+}
+
+// UpdateTopic godoc
+// @Summary Create or update a topic
+// @Tags topics
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param topic path string true "Topic name"
+// @Param body body dto.TopicUpdate true "Topic"
+// @Success 200 {object} dto.Topic
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/topics/{topic} [put]
+func (h *TopicHTTPHandlers) UpdateTopic(c *gin.Context) {
+	var topicUpdate dto.TopicUpdate
+	if err := c.ShouldBindJSON(&topicUpdate); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+	topic, err := h.topicService.UpdateTopic(c, c.Param("topic"), &topicUpdate)
+	if err != nil {
+		topicErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, topic)
+}
+
+// DeleteTopic godoc
+// @Summary Delete a topic
+// @Description Delete the topic and all of its subscriptions
+// @Tags topics
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param topic path string true "Topic name"
+// @Success 204
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/topics/{topic} [delete]
+func (h *TopicHTTPHandlers) DeleteTopic(c *gin.Context) {
+	if err := h.topicService.DeleteTopic(c, c.Param("topic")); err != nil {
+		topicErrorResponse(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetTopicSubscriptions godoc
+// @Summary List the subscribers of a topic
+// @Tags topics
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param topic path string true "Topic name"
+// @Param limit query int false "Limit of entries to return" default(100)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {array} dto.TopicSubscription
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/topics/{topic}/subscribers [get]
+func (h *TopicHTTPHandlers) GetTopicSubscriptions(c *gin.Context) {
+	const defaultLimit = 100
+	limit, offset := uint(defaultLimit), uint(0)
+	if limitStr := c.Query("limit"); limitStr != "" {
+		value, err := strconv.Atoi(limitStr)
+		if err != nil || value < 0 {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid limit value"})
+			return
+		}
+		limit = uint(value)
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		value, err := strconv.Atoi(offsetStr)
+		if err != nil || value < 0 {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid offset value"})
+			return
+		}
+		offset = uint(value)
+	}
+	subscriptions, err := h.topicService.GetTopicSubscriptions(c, c.Param("topic"), limit, offset)
+	if err != nil {
+		topicErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, subscriptions)
+}
+
+// GetUserSubscriptions godoc
+// @Summary List the topics of a user
+// @Tags topics
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Success 200 {array} dto.TopicSubscription
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/{user_id}/topics [get]
+func (h *TopicHTTPHandlers) GetUserSubscriptions(c *gin.Context) {
+	subscriptions, err := h.topicService.GetUserSubscriptions(c, c.Param("user_id"))
+	if err != nil {
+		topicErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, subscriptions)
+}
+
+// Subscribe godoc
+// @Summary Subscribe a user to a topic
+// @Description Subscribing twice is a no-op. The user must be a registered contact
+// @Tags topics
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Param topic path string true "Topic name"
+// @Success 200 {object} dto.TopicSubscription
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/{user_id}/topics/{topic} [put]
+func (h *TopicHTTPHandlers) Subscribe(c *gin.Context) {
+	subscription, err := h.topicService.Subscribe(c, c.Param("topic"), c.Param("user_id"))
+	if err != nil {
+		topicErrorResponse(c, err)
+		return
+	}
+	c.IndentedJSON(http.StatusOK, subscription)
+}
+
+// Unsubscribe godoc
+// @Summary Unsubscribe a user from a topic
+// @Tags topics
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Param topic path string true "Topic name"
+// @Success 204
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/{user_id}/topics/{topic} [delete]
+func (h *TopicHTTPHandlers) Unsubscribe(c *gin.Context) {
+	if err := h.topicService.Unsubscribe(c, c.Param("topic"), c.Param("user_id")); err != nil {
+		topicErrorResponse(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func topicErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidTopic):
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, services.ErrTopicNotFound),
+		errors.Is(err, services.ErrTopicOrContactNotFound),
+		errors.Is(err, services.ErrTopicSubscriptionNotFound):
+		c.IndentedJSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	default:
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	}
+}
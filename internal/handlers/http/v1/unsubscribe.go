@@ -0,0 +1,79 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"notification_system/internal/services"
+)
+
+const unsubscribePage = `<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Unsubscribe</title></head>
+<body>%s</body></html>`
+
+type UnsubscribeHTTPHandlers struct {
+	unsubscribeService services.UnsubscribeService
+}
+
+func NewUnsubscribeHTTPHandlers(unsubscribeService services.UnsubscribeService) UnsubscribeHandlers {
+	return &UnsubscribeHTTPHandlers{unsubscribeService: unsubscribeService}
+}
+
+// ConfirmUnsubscribe godoc
+// @Summary Unsubscribe confirmation page
+// @Description Page opened from the footer link. It does not unsubscribe by itself so link scanners cannot opt recipients out
+// @Tags unsubscribe
+// @Produce html
+// @Param token query string true "Signed unsubscribe token"
+// @Success 200 {string} string
+// @Failure 400 {string} string
+// @Router /api/v1/unsubscribe [get]
+func (h *UnsubscribeHTTPHandlers) ConfirmUnsubscribe(c *gin.Context) {
+	token := c.Query("token")
+	if err := h.unsubscribeService.CheckToken(c, token); err != nil {
+		unsubscribeErrorPage(c, err)
+		return
+	}
+	form := fmt.Sprintf(`<form method="post" action="?token=%s">
+<p>Do you want to stop receiving these notifications?</p>
+<button type="submit" name="List-Unsubscribe" value="One-Click">Unsubscribe</button>
+</form>`, html.EscapeString(token))
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(fmt.Sprintf(unsubscribePage, form)))
+}
+
+// Unsubscribe godoc
+// @Summary Unsubscribe
+// @Description One-click unsubscribe (RFC 8058) used by mail clients and by the confirmation page
+// @Tags unsubscribe
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param token query string true "Signed unsubscribe token"
+// @Success 200 {string} string
+// @Failure 400 {string} string
+// @Failure 500 {string} string
+// @Router /api/v1/unsubscribe [post]
+func (h *UnsubscribeHTTPHandlers) Unsubscribe(c *gin.Context) {
+	if err := h.unsubscribeService.Unsubscribe(c, c.Query("token")); err != nil {
+		unsubscribeErrorPage(c, err)
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8",
+		[]byte(fmt.Sprintf(unsubscribePage, "<p>You have been unsubscribed.</p>")))
+}
+
+func unsubscribeErrorPage(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrInvalidUnsubscribeToken),
+		errors.Is(err, services.ErrUnsubscribeTokenExpired):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrUnsubscribeNotConfigured):
+		status = http.StatusNotFound
+	}
+	message := fmt.Sprintf("<p>%s.</p>", html.EscapeString(err.Error()))
+	c.Data(status, "text/html; charset=utf-8", []byte(fmt.Sprintf(unsubscribePage, message)))
+}
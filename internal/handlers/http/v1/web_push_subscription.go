@@ -0,0 +1,128 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"notification_system/internal/dto"
+	"notification_system/internal/services"
+)
+
+type WebPushHTTPHandlers struct {
+	webPushService services.WebPushService
+}
+
+func NewWebPushHTTPHandlers(webPushService services.WebPushService) WebPushHandlers {
+	return &WebPushHTTPHandlers{webPushService: webPushService}
+}
+
+// GetVAPIDPublicKey godoc
+// @Summary Get the VAPID public key
+// @Description Get the application server key browsers need to create a push subscription
+// @Tags web-push
+// @Produce json
+// @Success 200 {object} dto.VAPIDPublicKey
+// @Failure 404 {object} ErrorResponse
+// @Router /api/v1/web-push/vapid-public-key [get]
+func (h *WebPushHTTPHandlers) GetVAPIDPublicKey(c *gin.Context) {
+	publicKey, err := h.webPushService.GetVAPIDPublicKey(c)
+	if err != nil {
+		c.IndentedJSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, *publicKey)
+}
+
+// Subscribe godoc
+// @Summary Register a push subscription
+// @Description Register a browser PushSubscription for the user
+// @Tags web-push
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Param subscription body dto.WebPushSubscriptionCreate true "Browser PushSubscription"
+// @Success 201 {object} dto.WebPushSubscription
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/{user_id}/web-push-subscriptions [post]
+func (h *WebPushHTTPHandlers) Subscribe(c *gin.Context) {
+	var subscriptionCreate dto.WebPushSubscriptionCreate
+	if err := c.ShouldBindJSON(&subscriptionCreate); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request body"})
+		return
+	}
+	subscription, err := h.webPushService.Subscribe(c, c.Param("user_id"), &subscriptionCreate)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidWebPushSubscription) {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusCreated, *subscription)
+}
+
+// GetSubscriptions godoc
+// @Summary Get push subscriptions of a user
+// @Description Get active browser push subscriptions of the user
+// @Tags web-push
+// @Produce json
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Success 200 {array} dto.WebPushSubscription
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/{user_id}/web-push-subscriptions [get]
+func (h *WebPushHTTPHandlers) GetSubscriptions(c *gin.Context) {
+	subscriptions, err := h.webPushService.GetSubscriptions(c, c.Param("user_id"))
+	if err != nil {
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.IndentedJSON(http.StatusOK, subscriptions)
+}
+
+// Unsubscribe godoc
+// @Summary Unregister a push subscription
+// @Description Remove the browser push subscription with the given endpoint
+// @Tags web-push
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Param endpoint query string true "Push subscription endpoint"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/{user_id}/web-push-subscriptions [delete]
+func (h *WebPushHTTPHandlers) Unsubscribe(c *gin.Context) {
+	endpoint := c.Query("endpoint")
+	if endpoint == "" {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid endpoint"})
+		return
+	}
+	err := h.webPushService.Unsubscribe(c, c.Param("user_id"), endpoint)
+	if err != nil {
+		if errors.Is(err, services.ErrWebPushSubscriptionNotFound) {
+			c.IndentedJSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,155 @@
+package imports
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/entities"
+)
+
+// readAll collects the rows and the lines of the row errors.
+func readAll(t *testing.T, reader Reader) ([]*Row, []int) {
+	t.Helper()
+	var rows []*Row
+	var failed []int
+	for {
+		row, err := reader.Next()
+		var rowErr *RowError
+		switch {
+		case errors.Is(err, io.EOF):
+			return rows, failed
+		case errors.As(err, &rowErr):
+			failed = append(failed, rowErr.Line)
+		case err != nil:
+			t.Fatalf("Next() error = %v", err)
+		default:
+			rows = append(rows, row)
+		}
+	}
+}
+
+func TestCSVReader(t *testing.T) {
+	data := "\ufeffemail, first_name\n" +
+		"ann@example.com,Ann\n" +
+		"bob@example.com,Bob,extra\n" +
+		"\"multi\nline@example.com\",Carl\n" +
+		"dan@example.com,\"Dan\n"
+	reader, err := NewCSVReader(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("NewCSVReader() error = %v", err)
+	}
+	rows, failed := readAll(t, reader)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(rows))
+	}
+	if rows[0].Line != 2 || rows[0].Fields["email"] != "ann@example.com" || rows[0].Fields["first_name"] != "Ann" {
+		t.Errorf("first row = %+v", rows[0])
+	}
+	if rows[1].Line != 4 || rows[1].Fields["first_name"] != "Carl" {
+		t.Errorf("quoted row = %+v", rows[1])
+	}
+	if len(failed) != 2 || failed[0] != 3 || failed[1] != 6 {
+		t.Errorf("failed lines = %v, want [3 6]", failed)
+	}
+
+	if _, err := NewCSVReader(strings.NewReader("")); err == nil {
+		t.Error("NewCSVReader() without a header error = nil")
+	}
+}
+
+func TestNDJSONReader(t *testing.T) {
+	data := `{"email": "ann@example.com", "age": 31, "vip": true, "note": null}
+
+not json
+{"email": "bob@example.com", "tags": ["a"]}
+`
+	rows, failed := readAll(t, NewNDJSONReader(strings.NewReader(data)))
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(rows))
+	}
+	fields := rows[0].Fields
+	if fields["email"] != "ann@example.com" || fields["age"] != "31" || fields["vip"] != "true" {
+		t.Errorf("fields = %v", fields)
+	}
+	if _, ok := fields["note"]; ok {
+		t.Error("null value is kept")
+	}
+	if rows[1].Line != 4 || rows[1].Fields["tags"] != `["a"]` {
+		t.Errorf("second row = %+v", rows[1])
+	}
+	if len(failed) != 1 || failed[0] != 3 {
+		t.Errorf("failed lines = %v, want [3]", failed)
+	}
+
+	long := strings.Repeat("x", maxLineSize+1)
+	if _, err := NewNDJSONReader(strings.NewReader(long)).Next(); err == nil || errors.As(err, new(*RowError)) {
+		t.Errorf("Next() on a too long line error = %v, want a file error", err)
+	}
+}
+
+func TestMapper(t *testing.T) {
+	category := "marketing"
+	imp := &entities.Import{
+		ID:           uuid.New(),
+		DeliveryType: entities.DeliveryTypeEmail,
+		Mapping: entities.ImportMapping{
+			RecipientColumn: "email",
+			UserIDColumn:    "user",
+			Variables:       map[string]string{"name": "first_name"},
+		},
+		Template: "Hi {{.name}}",
+		Priority: entities.PriorityLow,
+		Category: &category,
+	}
+	mapper, err := NewMapper(imp)
+	if err != nil {
+		t.Fatalf("NewMapper() error = %v", err)
+	}
+
+	notification, err := mapper.Notification(&Row{Fields: map[string]string{"email": " ann@example.com ", "first_name": "Ann"}})
+	if err != nil {
+		t.Fatalf("Notification() error = %v", err)
+	}
+	if notification.Recipient != "ann@example.com" || notification.Content != "Hi Ann" || notification.UserID != nil {
+		t.Errorf("notification = %+v", notification)
+	}
+	if *notification.ImportID != imp.ID || notification.Priority != entities.PriorityLow || notification.Category != &category {
+		t.Errorf("notification does not carry the import settings: %+v", notification)
+	}
+
+	notification, err = mapper.Notification(&Row{Fields: map[string]string{"user": "u1", "first_name": "Bob"}})
+	if err != nil || notification.UserID == nil || *notification.UserID != "u1" || notification.Recipient != "" {
+		t.Errorf("Notification() by user = %+v, %v", notification, err)
+	}
+
+	failing := []map[string]string{
+		{"first_name": "Ann"},
+		{"email": "not an address", "first_name": "Ann"},
+		{"email": "ann@example.com"},
+	}
+	for _, fields := range failing {
+		if _, err := mapper.Notification(&Row{Fields: fields}); err == nil {
+			t.Errorf("Notification(%v) error = nil", fields)
+		}
+	}
+
+	// without variables every column is one, a missing one fails the row
+	imp.Mapping.Variables = nil
+	imp.Template = "{{.first_name}} {{.last_name}}"
+	imp.DeliveryType = entities.DeliveryTypeWebhook
+	mapper, _ = NewMapper(imp)
+	notification, err = mapper.Notification(&Row{Fields: map[string]string{"email": "https://example.com/hook", "first_name": "Ann", "last_name": "Lee"}})
+	if err != nil || notification.Content != "Ann Lee" {
+		t.Errorf("Notification() = %+v, %v", notification, err)
+	}
+	if _, err := mapper.Notification(&Row{Fields: map[string]string{"email": "https://example.com/hook", "first_name": "Ann"}}); err == nil {
+		t.Error("Notification() with a missing variable error = nil")
+	}
+	if _, err := mapper.Notification(&Row{Fields: map[string]string{"email": "ftp://example.com", "first_name": "A", "last_name": "B"}}); !errors.Is(err, ErrInvalidRecipient) {
+		t.Errorf("Notification() with an ftp URL error = %v, want %v", err, ErrInvalidRecipient)
+	}
+}
@@ -0,0 +1,114 @@
+package imports
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"text/template"
+
+	"notification_system/internal/entities"
+	"notification_system/pkg/envelope"
+)
+
+var (
+	ErrMissingRecipient = errors.New("row has neither a recipient nor a user ID")
+	ErrInvalidRecipient = errors.New("invalid recipient")
+	ErrReservedValue    = errors.New("recipient or content starts with the reserved prefix enc:v1:")
+)
+
+// Mapper turns the rows of an upload into the notifications of the import.
+type Mapper struct {
+	imp  *entities.Import
+	tmpl *template.Template
+}
+
+// Parse checks the template text. Variables missing from a row are an error.
+func Parse(text string) (*template.Template, error) {
+	tmpl, err := template.New("import").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("imports.Parse error: %w", err)
+	}
+	return tmpl, nil
+}
+
+func NewMapper(imp *entities.Import) (*Mapper, error) {
+	tmpl, err := Parse(imp.Template)
+	if err != nil {
+		return nil, err
+	}
+	return &Mapper{imp: imp, tmpl: tmpl}, nil
+}
+
+// Notification validates the row and renders its notification.
+// The error is meant to be reported for the row, the mapper can go on with the next one.
+func (m *Mapper) Notification(row *Row) (*entities.Notification, error) {
+	mapping := m.imp.Mapping
+	recipient := strings.TrimSpace(row.Fields[mapping.RecipientColumn])
+	userID := strings.TrimSpace(row.Fields[mapping.UserIDColumn])
+	if recipient == "" && userID == "" {
+		return nil, ErrMissingRecipient
+	}
+	if recipient != "" {
+		if err := validateRecipient(m.imp.DeliveryType, recipient); err != nil {
+			return nil, err
+		}
+	}
+
+	variables := make(map[string]string, len(row.Fields))
+	if len(mapping.Variables) == 0 {
+		for column, value := range row.Fields {
+			variables[column] = value
+		}
+	} else {
+		for name, column := range mapping.Variables {
+			value, ok := row.Fields[column]
+			if !ok {
+				return nil, fmt.Errorf("missing column %q", column)
+			}
+			variables[name] = value
+		}
+	}
+	var b strings.Builder
+	if err := m.tmpl.Execute(&b, variables); err != nil {
+		return nil, fmt.Errorf("render error: %w", err)
+	}
+	if strings.TrimSpace(b.String()) == "" {
+		return nil, errors.New("rendered content is empty")
+	}
+	if envelope.IsEncrypted(recipient) || envelope.IsEncrypted(b.String()) {
+		return nil, ErrReservedValue
+	}
+
+	notification := &entities.Notification{
+		DeliveryType: m.imp.DeliveryType,
+		Recipient:    recipient,
+		Content:      b.String(),
+		Priority:     m.imp.Priority,
+		Category:     m.imp.Category,
+		ImportID:     &m.imp.ID,
+	}
+	if userID != "" {
+		notification.UserID = &userID
+	}
+	return notification, nil
+}
+
+// validateRecipient checks the addresses whose shape is known up front,
+// the others are left to the notifier.
+func validateRecipient(deliveryType, recipient string) error {
+	switch deliveryType {
+	case entities.DeliveryTypeEmail:
+		address, err := mail.ParseAddress(recipient)
+		if err != nil || address.Address != recipient {
+			return fmt.Errorf("%w: %q is not an email address", ErrInvalidRecipient, recipient)
+		}
+	case entities.DeliveryTypeWebhook, entities.DeliveryTypeTeams, entities.DeliveryTypeDiscord:
+		u, err := url.ParseRequestURI(recipient)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: %q is not an http(s) URL", ErrInvalidRecipient, recipient)
+		}
+	}
+	return nil
+}
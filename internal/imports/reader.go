@@ -0,0 +1,145 @@
+// Package imports reads campaign uploads row by row and maps the rows to notifications.
+package imports
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+
+	// maxLineSize bounds a single NDJSON line
+	maxLineSize = 1 << 20
+)
+
+// Row is one record of an upload, Line is its 1-based line number in the file.
+type Row struct {
+	Line   int
+	Fields map[string]string
+}
+
+// RowError is a record that cannot be read. The reader can go on with the next record.
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// Reader streams the rows of an upload. Next returns io.EOF after the last row,
+// a *RowError for a malformed row and any other error when the upload cannot be read further.
+type Reader interface {
+	Next() (*Row, error)
+}
+
+// NewReader returns the reader for the format.
+func NewReader(format string, r io.Reader) (Reader, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVReader(r)
+	case FormatNDJSON:
+		return NewNDJSONReader(r), nil
+	}
+	return nil, fmt.Errorf("imports.NewReader error: unknown format %q", format)
+}
+
+type csvReader struct {
+	reader *csv.Reader
+	header []string
+}
+
+// NewCSVReader reads the header row, the following rows are keyed by the header.
+func NewCSVReader(r io.Reader) (Reader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("imports.NewCSVReader header error: %w", err)
+	}
+	columns := make([]string, len(header))
+	for i, name := range header {
+		columns[i] = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+	}
+	return &csvReader{reader: reader, header: columns}, nil
+}
+
+func (r *csvReader) Next() (*Row, error) {
+	record, err := r.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, &RowError{Line: parseErr.StartLine, Err: parseErr.Err}
+		}
+		return nil, err
+	}
+	line, _ := r.reader.FieldPos(0)
+	if len(record) != len(r.header) {
+		return nil, &RowError{Line: line, Err: fmt.Errorf("expected %d fields, got %d", len(r.header), len(record))}
+	}
+	fields := make(map[string]string, len(record))
+	for i, value := range record {
+		fields[r.header[i]] = value
+	}
+	return &Row{Line: line, Fields: fields}, nil
+}
+
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// NewNDJSONReader reads one JSON object per line, blank lines are skipped.
+// Values that are not strings are kept in their JSON form.
+func NewNDJSONReader(r io.Reader) Reader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	return &ndjsonReader{scanner: scanner}
+}
+
+func (r *ndjsonReader) Next() (*Row, error) {
+	for r.scanner.Scan() {
+		r.line++
+		data := bytes.TrimSpace(r.scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(data, &object); err != nil {
+			return nil, &RowError{Line: r.line, Err: err}
+		}
+		fields := make(map[string]string, len(object))
+		for name, raw := range object {
+			var s string
+			switch {
+			case string(raw) == "null":
+			case json.Unmarshal(raw, &s) == nil:
+				fields[name] = s
+			default:
+				fields[name] = string(raw)
+			}
+		}
+		return &Row{Line: r.line, Fields: fields}, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, fmt.Errorf("line %d: longer than %d bytes", r.line+1, maxLineSize)
+		}
+		return nil, err
+	}
+	return nil, io.EOF
+}
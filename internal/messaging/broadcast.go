@@ -0,0 +1,87 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"notification_system/config"
+	"notification_system/internal/entities"
+	"notification_system/internal/repositories"
+	"notification_system/pkg/database"
+	"notification_system/pkg/envelope"
+)
+
+// maxChunksPerTick bounds the work of one tick so a large broadcast does not
+// starve the ones created after it on a single replica
+const maxChunksPerTick = 10
+
+// BroadcastWorker fans broadcasts out into notifications in chunks. Every replica runs one,
+// each chunk is expanded by exactly one of them.
+type BroadcastWorker struct {
+	broadcastRepo repositories.BroadcastRepository
+	cfg           *config.Config
+}
+
+func NewBroadcastWorker(cfg *config.Config, db *database.PostgresDatabase, cipher *envelope.Cipher) *BroadcastWorker {
+	return &BroadcastWorker{
+		broadcastRepo: repositories.NewBroadcastPostgresRepository(db, cipher),
+		cfg:           cfg,
+	}
+}
+
+func (w *BroadcastWorker) StartFanOut(ctx context.Context, handlePeriod time.Duration) {
+	const op = "messaging.broadcast.StartFanOut"
+	log := slog.With(slog.String("op", op))
+
+	ticker := time.NewTicker(handlePeriod)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				log.Info("stopping broadcast fan-out")
+				return
+			case <-ticker.C:
+			}
+			w.processChunks(ctx)
+		}
+	}()
+}
+
+func (w *BroadcastWorker) processChunks(ctx context.Context) {
+	const op = "messaging.broadcast.processChunks"
+	log := slog.With(slog.String("op", op))
+
+	for i := 0; i < maxChunksPerTick && ctx.Err() == nil; i++ {
+		broadcast, count, err := w.broadcastRepo.ProcessBroadcastChunk(ctx, w.cfg.BroadcastChunkSize)
+		if errors.Is(err, repositories.ErrNotFound) {
+			return
+		}
+		if errors.Is(err, repositories.ErrQuotaExceeded) {
+			// the chunk is not created, the other broadcasts go on
+			log.Warn("broadcast paused",
+				slog.String("id", broadcast.ID.String()),
+				slog.Any("error", err),
+			)
+			continue
+		}
+		if err != nil {
+			log.Error("failed to process broadcast chunk", slog.Any("error", err))
+			return
+		}
+		log.Debug("broadcast chunk processed",
+			slog.String("id", broadcast.ID.String()),
+			slog.Int("count", count),
+			slog.Int("processed", int(broadcast.Processed)),
+		)
+		if broadcast.Status == entities.BroadcastStatusCompleted {
+			log.Info("broadcast completed",
+				slog.String("id", broadcast.ID.String()),
+				slog.Int("processed", int(broadcast.Processed)),
+			)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+package messaging
+
+import "expvar"
+
+// Frequency cap counters keyed by delivery type, published on /debug/vars.
+var (
+	frequencyCapDelayed = expvar.NewMap("frequency_cap_delayed")
+	frequencyCapDropped = expvar.NewMap("frequency_cap_dropped")
+)
@@ -3,7 +3,10 @@ package messaging
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
+	"net/http"
 	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
@@ -13,16 +16,29 @@ import (
 	"notification_system/internal/entities"
 	"notification_system/internal/notifiers"
 	"notification_system/internal/repositories"
+	"notification_system/internal/unsubscribe"
 	"notification_system/pkg/database"
+	"notification_system/pkg/envelope"
 )
 
+var ErrNoContactAddress = errors.New("user has no verified address for the delivery type")
+
+// tenantCacheTTL is how long a change of the email settings of a tenant takes to apply
+const tenantCacheTTL = time.Minute
+
 type NotificationReceiver struct {
 	consumer         *kafka.Consumer
 	notificationRepo repositories.NotificationRepository
+	suppressionRepo  repositories.SuppressionRepository
+	chainRepo        repositories.NotificationChainRepository
+	contactRepo      repositories.ContactRepository
+	preferenceRepo   repositories.PreferenceRepository
+	frequencyCapRepo repositories.FrequencyCapRepository
+	notifiers        map[string]notifiers.Notifier
 	cfg              *config.Config
 }
 
-func NewNotificationReceiver(cfg *config.Config, db *database.PostgresDatabase) *NotificationReceiver {
+func NewNotificationReceiver(cfg *config.Config, db *database.PostgresDatabase, cipher *envelope.Cipher) *NotificationReceiver {
 	const op = "messaging.sender.NewNotificationReceiver"
 	log := slog.With(slog.String("op", op))
 
@@ -40,21 +56,136 @@ func NewNotificationReceiver(cfg *config.Config, db *database.PostgresDatabase)
 		log.Error("error subscribing to topic", slog.Any("error", err))
 		panic("failed to subscribe to topic")
 	}
-	notificationRepo := repositories.NewNotificationPostgresRepository(db)
+	notificationRepo := repositories.NewNotificationPostgresRepository(db, cipher)
 	return &NotificationReceiver{
 		consumer:         consumer,
 		notificationRepo: notificationRepo,
+		suppressionRepo:  repositories.NewSuppressionPostgresRepository(db),
+		chainRepo:        repositories.NewNotificationChainPostgresRepository(db, cipher),
+		contactRepo:      repositories.NewContactPostgresRepository(db),
+		preferenceRepo:   repositories.NewPreferencePostgresRepository(db),
+		frequencyCapRepo: repositories.NewFrequencyCapPostgresRepository(db, cipher),
+		notifiers:        newNotifiers(cfg, db, cipher),
 		cfg:              cfg,
 	}
 }
 
-func (r *NotificationReceiver) StartProcessNotifications(ctx context.Context) {
-	const op = "messaging.receiver.StartProcessNotifications"
+func newNotifiers(cfg *config.Config, db *database.PostgresDatabase, cipher *envelope.Cipher) map[string]notifiers.Notifier {
+	const op = "messaging.receiver.newNotifiers"
 	log := slog.With(slog.String("op", op))
 
-	gmailNotifier := notifiers.GmailNotifier{
-		From: r.cfg.Gmail,
+	allowedNetworks, err := notifiers.ParseNetworks(cfg.WebhookAllowedNetworks)
+	if err != nil {
+		log.Error("error parsing webhook allowed networks", slog.Any("error", err))
+		panic("failed to parse webhook allowed networks")
+	}
+	webhookClient, err := notifiers.NewHTTPClient(
+		time.Duration(cfg.WebhookTimeoutMs)*time.Millisecond,
+		cfg.WebhookClientCert,
+		cfg.WebhookClientKey,
+		cfg.WebhookCACert,
+		allowedNetworks,
+	)
+	if err != nil {
+		log.Error("error creating webhook client", slog.Any("error", err))
+		panic("failed to create webhook client")
+	}
+	webhookSuccessCodes, err := notifiers.ParseStatusCodes(cfg.WebhookSuccessCodes)
+	if err != nil {
+		log.Error("error parsing webhook success codes", slog.Any("error", err))
+		panic("failed to parse webhook success codes")
+	}
+
+	httpClient := &http.Client{Timeout: time.Duration(cfg.Timeout) * time.Second}
+	// the Slack, Teams and Discord recipients are URLs chosen by the caller
+	publicClient := notifiers.NewPublicHTTPClient(time.Duration(cfg.Timeout)*time.Second, allowedNetworks)
+
+	pushNotifier := &notifiers.PushNotifier{}
+	if cfg.FCMServiceAccountFile != "" {
+		pushNotifier.FCM, err = notifiers.NewFCMNotifier(cfg.FCMServiceAccountFile, cfg.FCMEndpoint, cfg.FCMTokenURI, httpClient)
+		if err != nil {
+			log.Error("error creating fcm notifier", slog.Any("error", err))
+			panic("failed to create fcm notifier")
+		}
+	}
+	if cfg.APNsKeyFile != "" {
+		pushNotifier.APNs, err = notifiers.NewAPNsNotifier(
+			cfg.APNsKeyFile,
+			cfg.APNsKeyID,
+			cfg.APNsTeamID,
+			cfg.APNsTopic,
+			cfg.APNsEndpoint,
+			httpClient,
+		)
+		if err != nil {
+			log.Error("error creating apns notifier", slog.Any("error", err))
+			panic("failed to create apns notifier")
+		}
+	}
+
+	webPushNotifier := &notifiers.WebPushNotifier{
+		Subscriptions: repositories.NewWebPushSubscriptionPostgresRepository(db),
+		VAPIDSubject:  cfg.WebPushVAPIDSubject,
+		Client:        httpClient,
+	}
+	if cfg.WebPushVAPIDPrivateKey != "" {
+		webPushNotifier.VAPIDKey, err = notifiers.ParseVAPIDPrivateKey(cfg.WebPushVAPIDPrivateKey)
+		if err != nil {
+			log.Error("error parsing VAPID private key", slog.Any("error", err))
+			panic("failed to parse VAPID private key")
+		}
+	}
+
+	emailNotifier := &notifiers.GmailNotifier{
+		From:              cfg.Gmail,
+		Password:          cfg.GmailAppPassword,
+		Tenants:           notifiers.NewTenantCache(repositories.NewClientPostgresRepository(db, cipher), tenantCacheTTL),
+		UnsubscribeMailto: cfg.UnsubscribeMailto,
+		UnsubscribeFooter: cfg.UnsubscribeFooter,
+		Categories:        repositories.NewPreferencePostgresRepository(db),
+	}
+	if cfg.UnsubscribeSecret != "" && cfg.UnsubscribeBaseURL != "" {
+		emailNotifier.Unsubscribe = &unsubscribe.Signer{
+			Secret:  []byte(cfg.UnsubscribeSecret),
+			BaseURL: cfg.UnsubscribeBaseURL,
+			TTL:     time.Duration(cfg.UnsubscribeTTLHours) * time.Hour,
+		}
+	}
+
+	return map[string]notifiers.Notifier{
+		entities.DeliveryTypeEmail: emailNotifier,
+		entities.DeliveryTypeTest:  &notifiers.NoopNotifier{},
+		entities.DeliveryTypeTelegram: &notifiers.TelegramNotifier{
+			BotToken: cfg.TelegramBotToken,
+			APIURL:   cfg.TelegramAPIURL,
+			Client:   httpClient,
+		},
+		entities.DeliveryTypeWebhook: &notifiers.WebhookNotifier{
+			Method:             cfg.WebhookMethod,
+			Headers:            cfg.WebhookHeaders,
+			SigningSecret:      cfg.WebhookSigningSecret,
+			SuccessStatusCodes: webhookSuccessCodes,
+			Client:             webhookClient,
+		},
+		entities.DeliveryTypeSlack: &notifiers.SlackNotifier{
+			BotToken: cfg.SlackBotToken,
+			APIURL:   cfg.SlackAPIURL,
+			Client:   publicClient,
+		},
+		entities.DeliveryTypeTeams: &notifiers.TeamsNotifier{
+			Client: publicClient,
+		},
+		entities.DeliveryTypeDiscord: &notifiers.DiscordNotifier{
+			Client: publicClient,
+		},
+		entities.DeliveryTypePush:    pushNotifier,
+		entities.DeliveryTypeWebPush: webPushNotifier,
 	}
+}
+
+func (r *NotificationReceiver) StartProcessNotifications(ctx context.Context) {
+	const op = "messaging.receiver.StartProcessNotifications"
+	log := slog.With(slog.String("op", op))
 
 	go func() {
 		for {
@@ -68,38 +199,25 @@ func (r *NotificationReceiver) StartProcessNotifications(ctx context.Context) {
 					log.Debug("Got message from kafka",
 						slog.String("topic-partition", msg.TopicPartition.String()),
 						slog.String("message", string(msg.Value)))
-					var notification entities.Notification
-					notificationBytes := msg.Value
-					err = json.Unmarshal(notificationBytes, &notification)
+					var message entities.NotificationMessage
+					err = json.Unmarshal(msg.Value, &message)
 					if err != nil {
 						log.Error("error unmarshalling notification", slog.Any("error", err))
+						continue
 					}
-					switch notification.DeliveryType {
-					case entities.DeliveryTypeEmail:
-						err = gmailNotifier.Notify(notification.Recipient, notification.Content)
-						// there may be more notifiers here
-					}
-					if err == nil {
-						log.Info("send notification", slog.Any("notification", notification))
-						err = r.notificationRepo.UpdateNotificationsStatus(ctx, []uuid.UUID{notification.ID}, entities.StatusDelivered)
-						if err != nil {
-							log.Error("cannot update notification status", slog.Any("notification", notification))
-						}
-					} else {
-						log.Error("error sending notification", slog.Any("error", err))
-						newStatus := entities.StatusPending
-						if notification.Retries > r.cfg.MaxRetries {
-							newStatus = entities.StatusFailed
-						}
-						err = r.notificationRepo.UpdateNotificationsStatus(ctx, []uuid.UUID{notification.ID}, newStatus)
-						if err != nil {
-							log.Error("cannot update notification status", slog.Any("notification", notification))
-						}
+					notification, err := r.notificationRepo.ClaimNotification(ctx, message.ID)
+					if errors.Is(err, repositories.ErrNotFound) {
+						log.Info("notification no longer due, skipped", slog.String("id", message.ID.String()))
+						continue
 					}
-					err := r.notificationRepo.UpdateNotificationRetries(ctx, notification.ID, notification.Retries+1)
 					if err != nil {
-						log.Error("cannot update notification retries", slog.Any("notification", notification))
+						log.Error("error claiming notification",
+							slog.String("id", message.ID.String()),
+							slog.Any("error", err),
+						)
+						continue
 					}
+					r.processNotification(ctx, notification)
 				} else if !err.(kafka.Error).IsTimeout() {
 					log.Error("Consumer error", slog.Any("error", err))
 				}
@@ -108,6 +226,197 @@ func (r *NotificationReceiver) StartProcessNotifications(ctx context.Context) {
 	}()
 }
 
+func (r *NotificationReceiver) processNotification(ctx context.Context, notification *entities.Notification) {
+	const op = "messaging.receiver.processNotification"
+	log := slog.With(slog.String("op", op))
+
+	err := r.resolveRecipient(ctx, notification)
+	var reason string
+	if err == nil {
+		reason, err = r.suppressionReason(ctx, notification)
+	}
+	if reason != "" {
+		r.suppressNotification(ctx, notification, reason)
+		return
+	}
+	// a notification counts against the frequency caps once, retries of a failed attempt do not
+	// take another token. A notification delayed by a cap has not taken one yet.
+	if err == nil && notification.Retries == 0 {
+		var exceeded *entities.FrequencyCapExceeded
+		exceeded, err = r.takeFrequencyTokens(ctx, notification)
+		if exceeded != nil {
+			r.capNotification(ctx, notification, exceeded)
+			return
+		}
+	}
+	if err == nil {
+		err = r.sendNotification(ctx, notification)
+	}
+	if err == nil {
+		log.Info("send notification", slog.Any("notification", notification))
+		err = r.notificationRepo.UpdateNotificationsStatus(ctx, []uuid.UUID{notification.ID}, entities.StatusDelivered)
+		if err != nil {
+			log.Error("cannot update notification status", slog.Any("notification", notification))
+		}
+		r.updateChain(ctx, notification, entities.StatusDelivered)
+	} else {
+		log.Error("error sending notification", slog.Any("error", err))
+		newStatus := entities.StatusPending
+		if notification.Retries > r.cfg.MaxRetries || notifiers.IsPermanent(err) {
+			newStatus = entities.StatusFailed
+		}
+		if errors.Is(err, notifiers.ErrRecipientUnreachable) {
+			suppression := &entities.Suppression{
+				TenantID:     notification.TenantID,
+				DeliveryType: notification.DeliveryType,
+				Address:      notification.Recipient,
+				Reason:       entities.SuppressionHardBounce,
+				Details:      err.Error(),
+			}
+			if err := r.suppressionRepo.CreateSuppression(ctx, suppression); err != nil {
+				log.Error("cannot add recipient to the suppression list", slog.Any("error", err))
+			}
+		}
+		var retryAfterErr *notifiers.RetryAfterError
+		if newStatus == entities.StatusPending && errors.As(err, &retryAfterErr) {
+			nextAttemptAt := time.Now().Add(retryAfterErr.RetryAfter)
+			err := r.notificationRepo.UpdateNotificationNextAttemptAt(ctx, notification.ID, nextAttemptAt)
+			if err != nil {
+				log.Error("cannot schedule notification retry", slog.Any("notification", notification))
+			}
+		}
+		err = r.notificationRepo.UpdateNotificationsStatus(ctx, []uuid.UUID{notification.ID}, newStatus)
+		if err != nil {
+			log.Error("cannot update notification status", slog.Any("notification", notification))
+		}
+		if newStatus == entities.StatusFailed {
+			r.updateChain(ctx, notification, entities.StatusFailed)
+		}
+	}
+	err = r.notificationRepo.UpdateNotificationRetries(ctx, notification.ID, notification.Retries+1)
+	if err != nil {
+		log.Error("cannot update notification retries", slog.Any("notification", notification))
+	}
+}
+
+// suppressionReason checks the suppression list and the preferences of the recipient.
+// An empty reason means the notification may be sent.
+func (r *NotificationReceiver) suppressionReason(ctx context.Context, notification *entities.Notification) (string, error) {
+	suppression, err := r.suppressionRepo.GetActiveSuppression(ctx, notification.TenantID, notification.DeliveryType, notification.Recipient)
+	if err == nil {
+		return suppression.Reason, nil
+	}
+	if !errors.Is(err, repositories.ErrNotFound) {
+		return "", err
+	}
+	return r.preferenceRepo.GetSuppressionReason(ctx, notification)
+}
+
+// suppressNotification records the notification as suppressed instead of sending it.
+// A suppressed notification is final.
+func (r *NotificationReceiver) suppressNotification(ctx context.Context, notification *entities.Notification, reason string) {
+	const op = "messaging.receiver.suppressNotification"
+	log := slog.With(slog.String("op", op))
+
+	log.Info("notification suppressed",
+		slog.String("id", notification.ID.String()),
+		slog.String("reason", reason),
+	)
+	err := r.notificationRepo.UpdateNotificationStatusWithReason(ctx, notification.ID, entities.StatusSuppressed, reason)
+	if err != nil {
+		log.Error("cannot update notification status", slog.Any("notification", notification))
+	}
+	r.updateChain(ctx, notification, entities.StatusSuppressed)
+}
+
+func (r *NotificationReceiver) takeFrequencyTokens(ctx context.Context, notification *entities.Notification) (*entities.FrequencyCapExceeded, error) {
+	category := ""
+	if notification.Category != nil {
+		category = *notification.Category
+	}
+	return r.frequencyCapRepo.TakeFrequencyTokens(ctx, notification.TenantID, notification.DeliveryType, category, notification.Recipient)
+}
+
+// capNotification applies the policy of the exceeded frequency cap: the notification
+// is either dropped as suppressed or put back until the bucket has a token again.
+func (r *NotificationReceiver) capNotification(ctx context.Context, notification *entities.Notification, exceeded *entities.FrequencyCapExceeded) {
+	const op = "messaging.receiver.capNotification"
+	log := slog.With(slog.String("op", op))
+
+	if exceeded.Cap.Policy == entities.FrequencyCapDrop {
+		frequencyCapDropped.Add(notification.DeliveryType, 1)
+		r.suppressNotification(ctx, notification, entities.SuppressionReasonFrequencyCap)
+		return
+	}
+	frequencyCapDelayed.Add(notification.DeliveryType, 1)
+	releaseAt := time.Now().Add(exceeded.RetryAfter)
+	log.Info("notification delayed by frequency cap",
+		slog.String("id", notification.ID.String()),
+		slog.Time("release_at", releaseAt),
+	)
+	err := r.notificationRepo.UpdateNotificationsStatus(ctx, []uuid.UUID{notification.ID}, entities.StatusPending)
+	if err == nil {
+		err = r.notificationRepo.DeferNotification(ctx, notification.ID, releaseAt.UTC(), entities.DeferReasonFrequencyCap)
+	}
+	if err != nil {
+		log.Error("cannot delay notification", slog.Any("error", err))
+	}
+}
+
+// updateChain moves the fallback chain of a step forward once the step is finished.
+func (r *NotificationReceiver) updateChain(ctx context.Context, notification *entities.Notification, status string) {
+	const op = "messaging.receiver.updateChain"
+	log := slog.With(slog.String("op", op))
+
+	if notification.ParentID == nil || notification.ChainStep == nil {
+		return
+	}
+	var err error
+	if status == entities.StatusDelivered {
+		err = r.chainRepo.CompleteNotificationChain(ctx, *notification.ParentID, entities.StatusDelivered)
+	} else {
+		err = r.chainRepo.AdvanceNotificationChain(ctx, *notification.ParentID, *notification.ChainStep, false)
+	}
+	if err != nil {
+		log.Error("cannot update notification chain",
+			slog.String("chain_id", notification.ParentID.String()),
+			slog.Any("error", err),
+		)
+	}
+}
+
+func (r *NotificationReceiver) sendNotification(ctx context.Context, notification *entities.Notification) error {
+	notifier, ok := r.notifiers[notification.DeliveryType]
+	if !ok {
+		return &notifiers.PermanentError{
+			Err: fmt.Errorf("unsupported delivery type %q", notification.DeliveryType),
+		}
+	}
+	return notifier.Notify(ctx, notification)
+}
+
+// resolveRecipient looks the address of a user notification up on every attempt,
+// so a changed address applies to notifications that are already queued.
+func (r *NotificationReceiver) resolveRecipient(ctx context.Context, notification *entities.Notification) error {
+	if notification.UserID == nil || notification.Recipient != "" {
+		return nil
+	}
+	userID := *notification.UserID
+	if notification.DeliveryType == entities.DeliveryTypeWebPush {
+		notification.Recipient = userID
+		return nil
+	}
+	recipient, err := r.contactRepo.ResolveAddress(ctx, notification.TenantID, userID, notification.DeliveryType)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return &notifiers.PermanentError{Err: fmt.Errorf("%w: user %q", ErrNoContactAddress, userID)}
+		}
+		return err
+	}
+	notification.Recipient = recipient
+	return nil
+}
+
 func (r *NotificationReceiver) Close() error {
 	err := r.consumer.Close()
 	return err
@@ -0,0 +1,75 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"notification_system/config"
+	"notification_system/internal/entities"
+	"notification_system/internal/notifiers"
+	"notification_system/internal/repositories"
+	"notification_system/internal/repositories/mocks"
+)
+
+type notifierFunc func(ctx context.Context, notification *entities.Notification) error
+
+func (f notifierFunc) Notify(ctx context.Context, notification *entities.Notification) error {
+	return f(ctx, notification)
+}
+
+func TestNotificationReceiver_processNotification_HardBounce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockNotificationRepo := repomocks.NewMockNotificationRepository(ctrl)
+	mockSuppressionRepo := repomocks.NewMockSuppressionRepository(ctrl)
+	mockPreferenceRepo := repomocks.NewMockPreferenceRepository(ctrl)
+	mockFrequencyCapRepo := repomocks.NewMockFrequencyCapRepository(ctrl)
+	tenantID := uuid.New()
+	notification := &entities.Notification{
+		ID:           uuid.New(),
+		TenantID:     &tenantID,
+		DeliveryType: entities.DeliveryTypeEmail,
+		Recipient:    "gone@example.com",
+	}
+
+	mockSuppressionRepo.
+		EXPECT().
+		GetActiveSuppression(gomock.Any(), &tenantID, notification.DeliveryType, notification.Recipient).
+		Return(nil, repositories.ErrNotFound)
+	mockPreferenceRepo.EXPECT().GetSuppressionReason(gomock.Any(), notification).Return("", nil)
+	mockFrequencyCapRepo.
+		EXPECT().
+		TakeFrequencyTokens(gomock.Any(), &tenantID, notification.DeliveryType, "", notification.Recipient).
+		Return(nil, nil)
+	mockSuppressionRepo.
+		EXPECT().
+		CreateSuppression(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, suppression *entities.Suppression) error {
+			if suppression.TenantID == nil || *suppression.TenantID != tenantID {
+				t.Errorf("suppression tenant = %v, want %v", suppression.TenantID, tenantID)
+			}
+			if suppression.Address != notification.Recipient || suppression.Reason != entities.SuppressionHardBounce {
+				t.Errorf("unexpected suppression %+v", suppression)
+			}
+			return nil
+		})
+	mockNotificationRepo.EXPECT().UpdateNotificationsStatus(gomock.Any(), []uuid.UUID{notification.ID}, entities.StatusFailed).Return(nil)
+	mockNotificationRepo.EXPECT().UpdateNotificationRetries(gomock.Any(), notification.ID, uint8(1)).Return(nil)
+
+	r := &NotificationReceiver{
+		notificationRepo: mockNotificationRepo,
+		suppressionRepo:  mockSuppressionRepo,
+		preferenceRepo:   mockPreferenceRepo,
+		frequencyCapRepo: mockFrequencyCapRepo,
+		notifiers: map[string]notifiers.Notifier{
+			entities.DeliveryTypeEmail: notifierFunc(func(context.Context, *entities.Notification) error {
+				return &notifiers.PermanentError{Err: fmt.Errorf("%w: 550 no such user", notifiers.ErrRecipientUnreachable)}
+			}),
+		},
+		cfg: &config.Config{MaxRetries: 3},
+	}
+	r.processNotification(context.Background(), notification)
+}
@@ -0,0 +1,129 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"notification_system/config"
+	"notification_system/internal/repositories"
+	"notification_system/pkg/archive"
+	"notification_system/pkg/database"
+)
+
+const (
+	// maxPurgeBatchesPerPolicy bounds the work of one tick per policy so a large backlog is
+	// worked off over several ticks instead of in one long run
+	maxPurgeBatchesPerPolicy = 10
+	// partitionsAhead is how many monthly partitions exist ahead of the current month
+	partitionsAhead = 3
+)
+
+// RetentionWorker deletes the notifications expired under the retention policies in small
+// batches, archiving them first when an archive directory is configured, and maintains the
+// monthly partitions of the notifications. Every replica runs one, a notification is purged
+// by exactly one of them.
+type RetentionWorker struct {
+	retentionRepo repositories.RetentionRepository
+	archive       *archive.Writer
+	cfg           *config.Config
+}
+
+func NewRetentionWorker(cfg *config.Config, db *database.PostgresDatabase) *RetentionWorker {
+	worker := &RetentionWorker{
+		retentionRepo: repositories.NewRetentionPostgresRepository(db),
+		cfg:           cfg,
+	}
+	if cfg.ArchiveDir != "" {
+		worker.archive = archive.NewWriter(cfg.ArchiveDir, "notifications")
+	}
+	return worker
+}
+
+func (w *RetentionWorker) StartRetention(ctx context.Context, handlePeriod time.Duration) {
+	const op = "messaging.retention.StartRetention"
+	log := slog.With(slog.String("op", op))
+
+	ticker := time.NewTicker(handlePeriod)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				log.Info("stopping notification retention")
+				return
+			case <-ticker.C:
+			}
+			w.maintainPartitions(ctx, time.Now())
+			w.purgeExpiredNotifications(ctx, time.Now())
+		}
+	}()
+}
+
+// maintainPartitions creates the partitions of the coming months and drops the ones of the
+// past months the purges have emptied.
+func (w *RetentionWorker) maintainPartitions(ctx context.Context, now time.Time) {
+	const op = "messaging.retention.maintainPartitions"
+	log := slog.With(slog.String("op", op))
+
+	now = now.UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if err := w.retentionRepo.CreateNotificationPartitions(ctx, month, partitionsAhead+1); err != nil {
+		log.Error("failed to create notification partitions", slog.Any("error", err))
+	}
+	dropped, err := w.retentionRepo.DropEmptyNotificationPartitions(ctx, month)
+	if err != nil {
+		log.Warn("failed to drop empty notification partitions", slog.Any("error", err))
+	}
+	for _, partition := range dropped {
+		log.Info("empty notification partition dropped", slog.String("partition", partition))
+	}
+}
+
+// purgeExpiredNotifications applies every retention policy. A batch is archived before its
+// deletion is committed, a batch whose commit fails is archived again by the next purge.
+func (w *RetentionWorker) purgeExpiredNotifications(ctx context.Context, now time.Time) {
+	const op = "messaging.retention.purgeExpiredNotifications"
+	log := slog.With(slog.String("op", op))
+
+	policies, err := w.retentionRepo.GetAllRetentionPolicies(ctx)
+	if err != nil {
+		log.Error("failed to get retention policies", slog.Any("error", err))
+		return
+	}
+	var archiveBatch func([]json.RawMessage) error
+	if w.archive != nil {
+		archiveBatch = func(notifications []json.RawMessage) error {
+			path, err := w.archive.Write(notifications)
+			if err != nil {
+				return err
+			}
+			log.Debug("notifications archived", slog.String("file", path), slog.Int("count", len(notifications)))
+			return nil
+		}
+	}
+
+	for _, policy := range policies {
+		purged := 0
+		for i := 0; i < maxPurgeBatchesPerPolicy && ctx.Err() == nil; i++ {
+			count, err := w.retentionRepo.PurgeNotifications(ctx, policy, now, w.cfg.RetentionBatchSize, archiveBatch)
+			if err != nil {
+				log.Error("failed to purge notifications", slog.String("status", policy.Status), slog.Any("error", err))
+				break
+			}
+			purged += count
+			if count < int(w.cfg.RetentionBatchSize) {
+				break
+			}
+		}
+		if purged > 0 {
+			attrs := []any{slog.String("status", policy.Status), slog.Int("count", purged)}
+			if policy.TenantID != nil {
+				attrs = append(attrs, slog.String("tenant_id", policy.TenantID.String()))
+			}
+			log.Info("expired notifications purged", attrs...)
+		}
+	}
+}
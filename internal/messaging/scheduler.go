@@ -0,0 +1,99 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"notification_system/config"
+	"notification_system/internal/recurring"
+	"notification_system/internal/repositories"
+	"notification_system/pkg/database"
+	"notification_system/pkg/envelope"
+)
+
+// RecurringScheduler materializes the notifications of recurring notifications on each
+// occurrence. Every replica runs one, an occurrence is fired by exactly one of them.
+type RecurringScheduler struct {
+	recurringRepo repositories.RecurringNotificationRepository
+	cfg           *config.Config
+}
+
+func NewRecurringScheduler(cfg *config.Config, db *database.PostgresDatabase, cipher *envelope.Cipher) *RecurringScheduler {
+	return &RecurringScheduler{
+		recurringRepo: repositories.NewRecurringNotificationPostgresRepository(db, cipher),
+		cfg:           cfg,
+	}
+}
+
+func (s *RecurringScheduler) StartScheduling(ctx context.Context, handlePeriod time.Duration) {
+	const op = "messaging.scheduler.StartScheduling"
+	log := slog.With(slog.String("op", op))
+
+	ticker := time.NewTicker(handlePeriod)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				log.Info("stopping recurring notification scheduling")
+				return
+			case <-ticker.C:
+			}
+			s.fireDueRecurringNotifications(ctx, s.cfg.MaxBatchSize)
+		}
+	}()
+}
+
+// fireDueRecurringNotifications fires the due occurrence of every recurring notification once.
+// Occurrences missed while the service was down are collapsed into that one, the next run
+// is the first occurrence after now.
+func (s *RecurringScheduler) fireDueRecurringNotifications(ctx context.Context, limit uint) {
+	const op = "messaging.scheduler.fireDueRecurringNotifications"
+	log := slog.With(slog.String("op", op))
+
+	due, err := s.recurringRepo.GetDueRecurringNotifications(ctx, limit)
+	if err != nil {
+		log.Error("failed to get due recurring notifications", slog.Any("error", err))
+		return
+	}
+	now := time.Now()
+	for _, recurringNotification := range due {
+		log := log.With(slog.String("recurring_id", recurringNotification.ID.String()))
+		scheduledAt := *recurringNotification.NextRunAt
+		notifications, err := recurring.Notifications(recurringNotification, scheduledAt)
+		if err != nil {
+			// the definition was validated when saved, skip the occurrence instead of retrying it forever
+			log.Warn("failed to render recurring notification, skipping occurrence", slog.Any("error", err))
+			notifications = nil
+		}
+		after := now
+		if scheduledAt.After(after) {
+			after = scheduledAt
+		}
+		nextRunAt, err := recurring.NextRun(recurringNotification, after)
+		if err != nil {
+			log.Error("failed to compute next run", slog.Any("error", err))
+			continue
+		}
+		err = s.recurringRepo.FireRecurringNotification(ctx, recurringNotification, notifications, nextRunAt)
+		if errors.Is(err, repositories.ErrNotFound) {
+			// another replica fired it or the definition changed meanwhile
+			continue
+		}
+		if errors.Is(err, repositories.ErrQuotaExceeded) {
+			log.Warn("recurring notification over quota, skipping occurrence", slog.Any("error", err))
+			continue
+		}
+		if err != nil {
+			log.Error("failed to fire recurring notification", slog.Any("error", err))
+			continue
+		}
+		log.Info("recurring notification fired",
+			slog.Time("scheduled_at", scheduledAt),
+			slog.Int("count", len(notifications)),
+		)
+	}
+}
@@ -3,6 +3,7 @@ package messaging
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"time"
 
@@ -10,18 +11,24 @@ import (
 	"github.com/google/uuid"
 
 	"notification_system/config"
+	"notification_system/internal/digests"
 	"notification_system/internal/entities"
 	"notification_system/internal/repositories"
 	"notification_system/pkg/database"
+	"notification_system/pkg/envelope"
 )
 
 type NotificationSender struct {
 	producer         *kafka.Producer
 	notificationRepo repositories.NotificationRepository
+	chainRepo        repositories.NotificationChainRepository
+	quietHoursRepo   repositories.QuietHoursRepository
+	frequencyCapRepo repositories.FrequencyCapRepository
+	digestRepo       repositories.DigestRepository
 	cfg              *config.Config
 }
 
-func NewNotificationSender(cfg *config.Config, db *database.PostgresDatabase) *NotificationSender {
+func NewNotificationSender(cfg *config.Config, db *database.PostgresDatabase, cipher *envelope.Cipher) *NotificationSender {
 	const op = "messaging.sender.NewNotificationSender"
 	log := slog.With(slog.String("op", op))
 
@@ -33,10 +40,14 @@ func NewNotificationSender(cfg *config.Config, db *database.PostgresDatabase) *N
 		log.Error("error connecting to kafka", slog.Any("error", err))
 		panic("failed to connect kafka")
 	}
-	notificationRepo := repositories.NewNotificationPostgresRepository(db)
+	notificationRepo := repositories.NewNotificationPostgresRepository(db, cipher)
 	return &NotificationSender{
 		producer:         producer,
 		notificationRepo: notificationRepo,
+		chainRepo:        repositories.NewNotificationChainPostgresRepository(db, cipher),
+		quietHoursRepo:   repositories.NewQuietHoursPostgresRepository(db),
+		frequencyCapRepo: repositories.NewFrequencyCapPostgresRepository(db, cipher),
+		digestRepo:       repositories.NewDigestPostgresRepository(db, cipher),
 		cfg:              cfg,
 	}
 }
@@ -76,16 +87,27 @@ func (s *NotificationSender) StartProcessNotifications(ctx context.Context, hand
 			case <-ticker.C:
 			}
 			limit := s.cfg.MaxBatchSize
-			notifications, err := s.notificationRepo.GetNewNotifications(ctx, limit)
+			expired, err := s.chainRepo.ExpireTimedOutChainSteps(ctx, limit)
+			if err != nil {
+				log.Error("failed to expire timed out chain steps", slog.Any("error", err))
+			} else if expired != 0 {
+				log.Info("expired timed out chain steps", slog.Int("count", expired))
+			}
+			s.summarizeDueDigests(ctx, limit)
+			if _, err := s.frequencyCapRepo.DeleteIdleFrequencyBuckets(ctx, limit); err != nil {
+				log.Error("failed to delete idle frequency buckets", slog.Any("error", err))
+			}
+			notifications, err := s.notificationRepo.GetNewNotifications(ctx, limit, nil)
 			if err != nil {
 				log.Error("failed to get new notifications", slog.Any("error", err))
 				continue
 			}
+			notifications = s.deferQuietHours(ctx, notifications)
 			notificationsBytes := make([][]byte, len(notifications))
 			ids := make([]uuid.UUID, len(notifications))
 			for i, notification := range notifications {
 				ids[i] = notification.ID
-				notificationBytes, err := json.Marshal(*notification)
+				notificationBytes, err := json.Marshal(entities.NotificationMessage{ID: notification.ID})
 				if err != nil {
 					log.Error("failed to convert notification", slog.Any("error", err))
 					continue
@@ -96,7 +118,7 @@ func (s *NotificationSender) StartProcessNotifications(ctx context.Context, hand
 			if err != nil {
 				log.Error("failed to enqueue kafka message", slog.Any("error", err))
 			} else {
-				err = s.notificationRepo.UpdateNotificationsStatus(ctx, ids, entities.StatusInQueue)
+				err = s.notificationRepo.QueueNotifications(ctx, ids)
 				if err != nil {
 					log.Error("failed to update notification statuses", slog.Any("error", err))
 				}
@@ -105,6 +127,140 @@ func (s *NotificationSender) StartProcessNotifications(ctx context.Context, hand
 	}()
 }
 
+// deferQuietHours holds back the notifications whose recipient is in quiet hours until
+// the window ends and returns the ones to send now. Critical notifications are never held back.
+func (s *NotificationSender) deferQuietHours(ctx context.Context, notifications []*entities.Notification) []*entities.Notification {
+	const op = "messaging.sender.deferQuietHours"
+	log := slog.With(slog.String("op", op))
+
+	deferrable := make([]*entities.Notification, 0, len(notifications))
+	for _, notification := range notifications {
+		if notification.Priority != entities.PriorityCritical {
+			deferrable = append(deferrable, notification)
+		}
+	}
+	if len(deferrable) == 0 {
+		return notifications
+	}
+	quietHours, err := s.quietHoursRepo.GetQuietHoursForNotifications(ctx, deferrable)
+	if err != nil {
+		// quiet hours are a courtesy, a failed lookup must not stop delivery
+		log.Error("failed to get quiet hours", slog.Any("error", err))
+		return notifications
+	}
+
+	now := time.Now()
+	ready := notifications[:0]
+	for _, notification := range notifications {
+		rule, ok := quietHours[notification.ID]
+		if !ok || notification.Priority == entities.PriorityCritical {
+			ready = append(ready, notification)
+			continue
+		}
+		loc, err := time.LoadLocation(rule.TimeZone)
+		if err != nil {
+			log.Warn("unknown time zone, using UTC", slog.String("time_zone", rule.TimeZone))
+			loc = time.UTC
+		}
+		releaseAt, deferred := rule.ReleaseAt(now, loc)
+		if !deferred {
+			ready = append(ready, notification)
+			continue
+		}
+		err = s.notificationRepo.DeferNotification(ctx, notification.ID, releaseAt.UTC(), entities.DeferReasonQuietHours)
+		if err != nil {
+			log.Error("failed to defer notification", slog.Any("error", err))
+			ready = append(ready, notification)
+			continue
+		}
+		log.Info("notification deferred by quiet hours",
+			slog.String("id", notification.ID.String()),
+			slog.Time("release_at", releaseAt),
+		)
+	}
+	return ready
+}
+
+// summarizeDueDigests renders every digest whose window has ended into a summary
+// notification that is sent like any other pending notification.
+func (s *NotificationSender) summarizeDueDigests(ctx context.Context, limit uint) {
+	const op = "messaging.sender.summarizeDueDigests"
+	log := slog.With(slog.String("op", op))
+
+	due, err := s.digestRepo.GetDueDigests(ctx, limit)
+	if err != nil {
+		log.Error("failed to get due digests", slog.Any("error", err))
+		return
+	}
+	for _, digest := range due {
+		text := ""
+		template, err := s.digestRepo.GetDigestTemplate(ctx, digest.TenantID, digest.Key)
+		if err == nil {
+			text = template.Template
+		} else if !errors.Is(err, repositories.ErrNotFound) {
+			log.Error("failed to get digest template", slog.Any("error", err))
+			continue
+		}
+		content, err := digests.Render(text, digest)
+		if err != nil {
+			log.Warn("failed to render digest template, using the default",
+				slog.String("digest_key", digest.Key),
+				slog.Any("error", err),
+			)
+			content, err = digests.Render("", digest)
+			if err != nil {
+				log.Error("failed to render digest", slog.Any("error", err))
+				continue
+			}
+		}
+
+		summary := digestSummary(digest, content)
+		ids := make([]uuid.UUID, len(digest.Items))
+		for i, item := range digest.Items {
+			ids[i] = item.ID
+		}
+		if err := s.digestRepo.CreateDigestSummary(ctx, summary, ids); err != nil {
+			log.Error("failed to create digest summary", slog.Any("error", err))
+			continue
+		}
+		log.Info("digest summarized",
+			slog.String("digest_key", digest.Key),
+			slog.String("summary_id", summary.ID.String()),
+			slog.Int("count", len(ids)),
+		)
+	}
+}
+
+// digestSummary takes the highest priority of the items and their category when they share one.
+func digestSummary(digest *entities.Digest, content string) *entities.Notification {
+	priorities := map[string]int{
+		entities.PriorityLow:      0,
+		entities.PriorityNormal:   1,
+		entities.PriorityHigh:     2,
+		entities.PriorityCritical: 3,
+	}
+	summary := &entities.Notification{
+		DeliveryType: digest.DeliveryType,
+		Recipient:    digest.Recipient,
+		Content:      content,
+		Priority:     entities.PriorityLow,
+		UserID:       digest.UserID,
+		TenantID:     digest.TenantID,
+		DigestKey:    &digest.Key,
+	}
+	for i, item := range digest.Items {
+		if priorities[item.Priority] > priorities[summary.Priority] {
+			summary.Priority = item.Priority
+		}
+		if i == 0 {
+			summary.Category = item.Category
+		} else if summary.Category != nil && (item.Category == nil || *item.Category != *summary.Category) {
+			summary.Category = nil
+		}
+	}
+	return summary
+}
+
 func (s *NotificationSender) Close() {
 	s.producer.Close()
 }
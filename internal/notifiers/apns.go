@@ -0,0 +1,164 @@
+package notifiers
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"notification_system/pkg/jwt"
+)
+
+const (
+	defaultAPNsEndpoint = "https://api.push.apple.com"
+	// APNs rejects provider tokens older than an hour and throttles refreshes
+	// more frequent than every 20 minutes
+	apnsTokenLifetime = 50 * time.Minute
+)
+
+// APNsNotifier sends over HTTP/2 authorized by a token-based provider JWT.
+type APNsNotifier struct {
+	key      crypto.Signer
+	keyID    string
+	teamID   string
+	topic    string
+	endpoint string
+	client   *http.Client
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+type apnsErrorResponse struct {
+	Reason string `json:"reason"`
+}
+
+// NewAPNsNotifier loads the .p8 signing key. An empty endpoint means the production APNs.
+func NewAPNsNotifier(keyFile, keyID, teamID, topic, endpoint string, client *http.Client) (*APNsNotifier, error) {
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("notifiers.NewAPNsNotifier read key error: %w", err)
+	}
+	key, err := jwt.ParsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("notifiers.NewAPNsNotifier parse key error: %w", err)
+	}
+	if endpoint == "" {
+		endpoint = defaultAPNsEndpoint
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &APNsNotifier{
+		key:      key,
+		keyID:    keyID,
+		teamID:   teamID,
+		topic:    topic,
+		endpoint: strings.TrimRight(endpoint, "/"),
+		client:   client,
+	}, nil
+}
+
+func (notifier *APNsNotifier) Send(ctx context.Context, token string, content *PushContent) error {
+	providerToken, err := notifier.getProviderToken()
+	if err != nil {
+		return err
+	}
+
+	aps := map[string]any{}
+	if content.Title != "" || content.Body != "" {
+		aps["alert"] = map[string]string{"title": content.Title, "body": content.Body}
+	} else {
+		aps["content-available"] = 1
+	}
+	if content.Badge != nil {
+		aps["badge"] = *content.Badge
+	}
+	if content.Sound != "" {
+		aps["sound"] = content.Sound
+	}
+	payload := map[string]any{"aps": aps}
+	for key, value := range content.Data {
+		if key != "aps" {
+			payload[key] = value
+		}
+	}
+
+	headers := map[string]string{
+		"Authorization":  "bearer " + providerToken,
+		"apns-topic":     notifier.topic,
+		"apns-push-type": "alert",
+	}
+	if _, ok := aps["content-available"]; ok {
+		headers["apns-push-type"] = "background"
+		headers["apns-priority"] = "5"
+	}
+	if content.CollapseKey != "" {
+		headers["apns-collapse-id"] = content.CollapseKey
+	}
+	if content.TTLSeconds != nil {
+		expiration := int64(0)
+		if *content.TTLSeconds > 0 {
+			expiration = time.Now().Add(time.Duration(*content.TTLSeconds) * time.Second).Unix()
+		}
+		headers["apns-expiration"] = strconv.FormatInt(expiration, 10)
+	}
+
+	url := fmt.Sprintf("%s/3/device/%s", notifier.endpoint, token)
+	resp, body, err := postJSON(ctx, notifier.client, url, payload, headers)
+	if err != nil {
+		return fmt.Errorf("notifiers.apns error: %w", err)
+	}
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var apnsErr apnsErrorResponse
+	_ = json.Unmarshal(body, &apnsErr)
+	err = fmt.Errorf("notifiers.apns error %d: %s", resp.StatusCode, apnsErr.Reason)
+	switch apnsErr.Reason {
+	case "Unregistered", "BadDeviceToken", "DeviceTokenNotForTopic":
+		return &PermanentError{Err: fmt.Errorf("%w: %w", ErrRecipientUnreachable, err)}
+	case "ExpiredProviderToken", "InvalidProviderToken":
+		notifier.resetProviderToken()
+		return err
+	case "TooManyRequests", "TooManyProviderTokenUpdates":
+		return &RetryAfterError{RetryAfter: retryAfter(resp.Header, time.Minute), Err: err}
+	}
+	if resp.StatusCode == http.StatusGone {
+		return &PermanentError{Err: fmt.Errorf("%w: %w", ErrRecipientUnreachable, err)}
+	}
+	return httpStatusError("notifiers.apns", resp, body)
+}
+
+func (notifier *APNsNotifier) getProviderToken() (string, error) {
+	notifier.mu.Lock()
+	defer notifier.mu.Unlock()
+	if notifier.token != "" && time.Now().Before(notifier.expiresAt) {
+		return notifier.token, nil
+	}
+	now := time.Now()
+	token, err := jwt.Sign(notifier.key, notifier.keyID, map[string]any{
+		"iss": notifier.teamID,
+		"iat": now.Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("notifiers.apns sign provider token error: %w", err)
+	}
+	notifier.token = token
+	notifier.expiresAt = now.Add(apnsTokenLifetime)
+	return token, nil
+}
+
+func (notifier *APNsNotifier) resetProviderToken() {
+	notifier.mu.Lock()
+	defer notifier.mu.Unlock()
+	notifier.token = ""
+}
@@ -0,0 +1,87 @@
+package notifiers
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrAddressNotAllowed is returned when a recipient URL resolves to an internal address.
+var ErrAddressNotAllowed = errors.New("address not allowed")
+
+// ParseNetworks parses a comma-separated list of CIDR prefixes or single addresses, e.g. "10.1.0.0/16,192.168.1.5".
+func ParseNetworks(s string) ([]netip.Prefix, error) {
+	var networks []netip.Prefix
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if !strings.Contains(part, "/") {
+			addr, err := netip.ParseAddr(part)
+			if err != nil {
+				return nil, fmt.Errorf("invalid network %q", part)
+			}
+			networks = append(networks, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network %q", part)
+		}
+		networks = append(networks, prefix.Masked())
+	}
+	return networks, nil
+}
+
+// PublicDialer returns a dialer that refuses to connect to loopback, private, link-local,
+// multicast and unspecified addresses unless they are in one of the allowed networks.
+// The check runs on the resolved address of every connection, so neither a host name
+// nor a redirect can reach the internal network.
+func PublicDialer(allowed []netip.Prefix) *net.Dialer {
+	return &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return fmt.Errorf("%w: %s", ErrAddressNotAllowed, address)
+			}
+			addr, err := netip.ParseAddr(host)
+			if err != nil {
+				return fmt.Errorf("%w: %s", ErrAddressNotAllowed, address)
+			}
+			if !publicAddr(addr.Unmap(), allowed) {
+				return fmt.Errorf("%w: %s", ErrAddressNotAllowed, addr)
+			}
+			return nil
+		},
+	}
+}
+
+// NewPublicHTTPClient creates a client with the given timeout that connects only through
+// a PublicDialer, for the notifiers that post to URLs given as recipients.
+func NewPublicHTTPClient(timeout time.Duration, allowed []netip.Prefix) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = PublicDialer(allowed).DialContext
+	// a proxy would make the connection for us to any address
+	transport.Proxy = nil
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+func publicAddr(addr netip.Addr, allowed []netip.Prefix) bool {
+	for _, network := range allowed {
+		if network.Contains(addr) {
+			return true
+		}
+	}
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !sharedAddressSpace.Contains(addr)
+}
+
+// sharedAddressSpace is the carrier-grade NAT range of RFC 6598, internal like the private ranges
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
@@ -0,0 +1,82 @@
+package notifiers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"notification_system/internal/entities"
+)
+
+func TestParseNetworks(t *testing.T) {
+	networks, err := ParseNetworks(" 10.1.2.3/16, 192.168.1.5 ,fd00::/8")
+	if err != nil {
+		t.Fatalf("ParseNetworks() error = %v", err)
+	}
+	want := []string{"10.1.0.0/16", "192.168.1.5/32", "fd00::/8"}
+	if len(networks) != len(want) {
+		t.Fatalf("ParseNetworks() = %v, want %v", networks, want)
+	}
+	for i, network := range networks {
+		if network.String() != want[i] {
+			t.Errorf("network %d = %s, want %s", i, network, want[i])
+		}
+	}
+	for _, s := range []string{"10.0.0.0/33", "localhost", "10.0.0.0/8,x"} {
+		if _, err := ParseNetworks(s); err == nil {
+			t.Errorf("ParseNetworks(%q) error = nil", s)
+		}
+	}
+}
+
+func TestPublicAddr(t *testing.T) {
+	allowed := []netip.Prefix{netip.MustParsePrefix("10.20.0.0/16")}
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"10.20.1.1", true},
+		{"10.21.1.1", false},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"172.16.0.1", false},
+		{"192.168.0.1", false},
+		{"100.64.0.1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"224.0.0.1", false},
+	}
+	for _, tt := range tests {
+		if got := publicAddr(netip.MustParseAddr(tt.addr), allowed); got != tt.want {
+			t.Errorf("publicAddr(%s) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestNewPublicHTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	notification := &entities.Notification{Recipient: server.URL, Content: `{"text":"hello"}`}
+
+	notifier := &WebhookNotifier{Client: NewPublicHTTPClient(time.Second, nil)}
+	err := notifier.Notify(context.Background(), notification)
+	if !errors.Is(err, ErrAddressNotAllowed) || !IsPermanent(err) {
+		t.Errorf("Notify() to loopback error = %v, want a permanent ErrAddressNotAllowed", err)
+	}
+
+	allowed := []netip.Prefix{netip.MustParsePrefix("127.0.0.1/32")}
+	notifier = &WebhookNotifier{Client: NewPublicHTTPClient(time.Second, allowed)}
+	if err := notifier.Notify(context.Background(), notification); err != nil {
+		t.Errorf("Notify() to an allowed network error = %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package notifiers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"notification_system/internal/entities"
+)
+
+const discordUnknownWebhookCode = 10015
+
+// DiscordNotifier executes a Discord webhook given as the recipient.
+type DiscordNotifier struct {
+	Client *http.Client
+}
+
+// DiscordContent is the structured form of a discord notification content.
+type DiscordContent struct {
+	Content   string         `json:"content,omitempty"`
+	Username  string         `json:"username,omitempty"`
+	AvatarURL string         `json:"avatar_url,omitempty"`
+	Embeds    []DiscordEmbed `json:"embeds,omitempty"`
+}
+
+type DiscordEmbed struct {
+	Title       string              `json:"title,omitempty"`
+	Description string              `json:"description,omitempty"`
+	URL         string              `json:"url,omitempty"`
+	Color       int                 `json:"color,omitempty"`
+	Timestamp   string              `json:"timestamp,omitempty"`
+	Fields      []DiscordEmbedField `json:"fields,omitempty"`
+	Footer      *DiscordEmbedFooter `json:"footer,omitempty"`
+}
+
+type DiscordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+type DiscordEmbedFooter struct {
+	Text string `json:"text"`
+}
+
+type discordError struct {
+	Code       int     `json:"code"`
+	Message    string  `json:"message"`
+	RetryAfter float64 `json:"retry_after"`
+	Global     bool    `json:"global"`
+}
+
+func (notifier *DiscordNotifier) Notify(ctx context.Context, notification *entities.Notification) error {
+	to, message := notification.Recipient, notification.Content
+	content := DiscordContent{Content: message}
+	if isJSONObject(message) {
+		content = DiscordContent{}
+		if err := json.Unmarshal([]byte(message), &content); err != nil {
+			return &PermanentError{Err: fmt.Errorf("notifiers.discord error: %w: %w", ErrInvalidContent, err)}
+		}
+	}
+	if content.Content == "" && len(content.Embeds) == 0 {
+		return &PermanentError{Err: fmt.Errorf("notifiers.discord error: %w: content or embeds are required", ErrInvalidContent)}
+	}
+
+	resp, body, err := postJSON(ctx, notifier.Client, to, content, nil)
+	if err != nil {
+		return fmt.Errorf("notifiers.discord error: %w", err)
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	var discordErr discordError
+	_ = json.Unmarshal(body, &discordErr)
+	err = fmt.Errorf("notifiers.discord error %d: %s", resp.StatusCode, body)
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return &RetryAfterError{RetryAfter: discordRetryAfter(resp.Header, &discordErr), Err: err}
+	case resp.StatusCode == http.StatusNotFound, discordErr.Code == discordUnknownWebhookCode:
+		return &PermanentError{Err: fmt.Errorf("%w: %w", ErrRecipientUnreachable, err)}
+	}
+	return httpStatusError("notifiers.discord", resp, body)
+}
+
+// discordRetryAfter prefers the precise bucket reset from the rate limit headers
+// over the body and the Retry-After header which are rounded.
+func discordRetryAfter(header http.Header, discordErr *discordError) time.Duration {
+	if resetAfter, err := strconv.ParseFloat(header.Get("X-RateLimit-Reset-After"), 64); err == nil {
+		return time.Duration(resetAfter * float64(time.Second))
+	}
+	if discordErr.RetryAfter > 0 {
+		return time.Duration(discordErr.RetryAfter * float64(time.Second))
+	}
+	return retryAfter(header, time.Second)
+}
@@ -0,0 +1,63 @@
+package notifiers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"notification_system/internal/entities"
+)
+
+func TestDiscordNotifier_Notify(t *testing.T) {
+	tests := []struct {
+		name            string
+		message         string
+		status          int
+		headers         map[string]string
+		response        string
+		wantErr         bool
+		wantUnreachable bool
+		wantRetryAfter  time.Duration
+	}{
+		{"plain content", "hello", http.StatusNoContent, nil, "", false, false, 0},
+		{"embeds", `{"embeds":[{"title":"Deploy","fields":[{"name":"env","value":"prod"}]}]}`, http.StatusNoContent, nil, "", false, false, 0},
+		{"missing content", `{"username":"bot"}`, http.StatusNoContent, nil, "", true, false, 0},
+		{"unknown webhook", "hello", http.StatusNotFound, nil, `{"message":"Unknown Webhook","code":10015}`, true, true, 0},
+		{"rate limited by body", "hello", http.StatusTooManyRequests, nil, `{"retry_after":1.5,"global":false}`, true, false, 1500 * time.Millisecond},
+		{"rate limited by header", "hello", http.StatusTooManyRequests, map[string]string{"X-RateLimit-Reset-After": "0.25"}, `{"retry_after":1}`, true, false, 250 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var content DiscordContent
+				if err := json.NewDecoder(r.Body).Decode(&content); err != nil {
+					t.Errorf("invalid payload: %v", err)
+				}
+				for key, value := range tt.headers {
+					w.Header().Set(key, value)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			notifier := &DiscordNotifier{}
+			err := notifier.Notify(context.Background(), &entities.Notification{Recipient: server.URL, Content: tt.message})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Notify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrRecipientUnreachable) != tt.wantUnreachable {
+				t.Errorf("Notify() unreachable = %v, want %v", !tt.wantUnreachable, tt.wantUnreachable)
+			}
+			var retryAfterErr *RetryAfterError
+			if errors.As(err, &retryAfterErr) != (tt.wantRetryAfter != 0) ||
+				(retryAfterErr != nil && retryAfterErr.RetryAfter != tt.wantRetryAfter) {
+				t.Errorf("Notify() error = %v, want retry after %s", err, tt.wantRetryAfter)
+			}
+		})
+	}
+}
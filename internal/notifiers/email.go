@@ -0,0 +1,236 @@
+package notifiers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EmailContent is the structured form of an email notification content.
+// A plain content is sent as text, a content starting with RFC 5322 headers
+// is sent as a raw message with its own headers.
+type EmailContent struct {
+	Subject string `json:"subject,omitempty"`
+	Text    string `json:"text,omitempty"`
+	HTML    string `json:"html,omitempty"`
+}
+
+// emailMessage collects what is needed to render a message.
+type emailMessage struct {
+	From      string
+	To        string
+	MessageID string
+	Content   string
+	// UnsubscribeURL and UnsubscribeMailto fill the List-Unsubscribe header
+	UnsubscribeURL    string
+	UnsubscribeMailto string
+	// Footer appends a visible unsubscribe link to text and html bodies
+	Footer bool
+}
+
+// headers the service always sets itself
+var managedEmailHeaders = map[string]bool{
+	"From":                  true,
+	"To":                    true,
+	"Date":                  true,
+	"Message-Id":            true,
+	"Mime-Version":          true,
+	"List-Unsubscribe":      true,
+	"List-Unsubscribe-Post": true,
+}
+
+func (m *emailMessage) Bytes() ([]byte, error) {
+	header := textproto.MIMEHeader{}
+	var body []byte
+
+	raw, isRaw := parseRawEmail(m.Content)
+	switch {
+	case isRaw:
+		for key, values := range raw.Header {
+			if !managedEmailHeaders[textproto.CanonicalMIMEHeaderKey(key)] {
+				header[textproto.CanonicalMIMEHeaderKey(key)] = values
+			}
+		}
+		var buf bytes.Buffer
+		if _, err := buf.ReadFrom(raw.Body); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidContent, err)
+		}
+		body = buf.Bytes()
+		mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+		encoding := strings.ToLower(header.Get("Content-Transfer-Encoding"))
+		plain := mediaType == "" || mediaType == "text/plain"
+		if m.Footer && m.UnsubscribeURL != "" && plain && (encoding == "" || encoding == "7bit" || encoding == "8bit") {
+			body = append(body, []byte(textFooter(m.UnsubscribeURL))...)
+		}
+	default:
+		content := EmailContent{Text: m.Content}
+		if isJSONObject(m.Content) {
+			content = EmailContent{}
+			if err := json.Unmarshal([]byte(m.Content), &content); err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidContent, err)
+			}
+			if content.Text == "" && content.HTML == "" {
+				return nil, fmt.Errorf("%w: text or html is required", ErrInvalidContent)
+			}
+		}
+		if m.Footer && m.UnsubscribeURL != "" {
+			if content.Text != "" {
+				content.Text += textFooter(m.UnsubscribeURL)
+			}
+			if content.HTML != "" {
+				content.HTML = withHTMLFooter(content.HTML, m.UnsubscribeURL)
+			}
+		}
+		if content.Subject != "" {
+			header.Set("Subject", mime.QEncoding.Encode("utf-8", content.Subject))
+		}
+		var err error
+		body, err = renderEmailBody(header, &content)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	header.Set("From", m.From)
+	header.Set("To", m.To)
+	header.Set("Date", time.Now().Format(time.RFC1123Z))
+	header.Set("Message-ID", m.MessageID)
+	header.Set("MIME-Version", "1.0")
+	if m.UnsubscribeURL != "" || m.UnsubscribeMailto != "" {
+		var links []string
+		if m.UnsubscribeURL != "" {
+			links = append(links, "<"+m.UnsubscribeURL+">")
+		}
+		if m.UnsubscribeMailto != "" {
+			links = append(links, "<mailto:"+m.UnsubscribeMailto+"?subject=unsubscribe>")
+		}
+		header.Set("List-Unsubscribe", strings.Join(links, ", "))
+		// RFC 8058 one-click unsubscribe needs an https link
+		if strings.HasPrefix(m.UnsubscribeURL, "https://") {
+			header.Set("List-Unsubscribe-Post", "List-Unsubscribe=One-Click")
+		}
+	}
+
+	var buf bytes.Buffer
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, value := range header[key] {
+			fmt.Fprintf(&buf, "%s: %s\r\n", key, value)
+		}
+	}
+	buf.WriteString("\r\n")
+	buf.Write(body)
+	return buf.Bytes(), nil
+}
+
+// parseRawEmail accepts content that already is a message with headers. Plain text
+// that happens to start with "Word: " is not taken for a header block because a raw
+// message must carry at least one of the usual message headers.
+func parseRawEmail(content string) (*mail.Message, bool) {
+	if !strings.Contains(content, "\n\n") && !strings.Contains(content, "\r\n\r\n") {
+		return nil, false
+	}
+	message, err := mail.ReadMessage(strings.NewReader(content))
+	if err != nil {
+		return nil, false
+	}
+	for _, key := range []string{"Subject", "Content-Type", "From", "To"} {
+		if message.Header.Get(key) != "" {
+			return message, true
+		}
+	}
+	return nil, false
+}
+
+func renderEmailBody(header textproto.MIMEHeader, content *EmailContent) ([]byte, error) {
+	if content.Text != "" && content.HTML != "" {
+		var buf bytes.Buffer
+		writer := multipart.NewWriter(&buf)
+		for _, part := range []struct{ mediaType, body string }{
+			{"text/plain", content.Text},
+			{"text/html", content.HTML},
+		} {
+			partHeader := textproto.MIMEHeader{}
+			partHeader.Set("Content-Type", part.mediaType+"; charset=utf-8")
+			partHeader.Set("Content-Transfer-Encoding", "quoted-printable")
+			w, err := writer.CreatePart(partHeader)
+			if err != nil {
+				return nil, err
+			}
+			if err := writeQuotedPrintable(w, part.body); err != nil {
+				return nil, err
+			}
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+		header.Set("Content-Type", "multipart/alternative; boundary="+writer.Boundary())
+		return buf.Bytes(), nil
+	}
+
+	mediaType, body := "text/plain", content.Text
+	if content.HTML != "" {
+		mediaType, body = "text/html", content.HTML
+	}
+	header.Set("Content-Type", mediaType+"; charset=utf-8")
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+	var buf bytes.Buffer
+	if err := writeQuotedPrintable(&buf, body); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintable(w io.Writer, body string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+func textFooter(unsubscribeURL string) string {
+	return "\r\n\r\n--\r\nUnsubscribe: " + unsubscribeURL + "\r\n"
+}
+
+func withHTMLFooter(body, unsubscribeURL string) string {
+	footer := fmt.Sprintf(`<p style="font-size:12px;color:#888888"><a href="%s">Unsubscribe</a></p>`,
+		html.EscapeString(unsubscribeURL))
+	if i := strings.LastIndex(strings.ToLower(body), "</body>"); i >= 0 {
+		return body[:i] + footer + body[i:]
+	}
+	return body + footer
+}
+
+// emailMessageID builds the Message-ID from the notification ID so replies
+// and delivery reports can be matched to the notification.
+func emailMessageID(id, from string) string {
+	if id == "" {
+		random := make([]byte, 16)
+		_, _ = rand.Read(random)
+		id = hex.EncodeToString(random)
+	}
+	domain := "localhost"
+	if address, err := mail.ParseAddress(from); err == nil {
+		if _, host, ok := strings.Cut(address.Address, "@"); ok {
+			domain = host
+		}
+	}
+	return "<" + id + "@" + domain + ">"
+}
@@ -0,0 +1,226 @@
+package notifiers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/entities"
+	"notification_system/internal/unsubscribe"
+)
+
+type fakeCategories map[string]bool
+
+func (c fakeCategories) IsCategorySuppressible(ctx context.Context, name string) (bool, error) {
+	suppressible, ok := c[name]
+	return suppressible || !ok, nil
+}
+
+func sendEmail(t *testing.T, notifier *GmailNotifier, notification *entities.Notification) *mail.Message {
+	t.Helper()
+	var sent []byte
+	notifier.SendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		sent = msg
+		return nil
+	}
+	if err := notifier.Notify(context.Background(), notification); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	message, err := mail.ReadMessage(strings.NewReader(string(sent)))
+	if err != nil {
+		t.Fatalf("sent message is not valid: %v", err)
+	}
+	return message
+}
+
+func TestGmailNotifier_Unsubscribe(t *testing.T) {
+	signer := &unsubscribe.Signer{Secret: []byte("secret"), BaseURL: "https://notify.example.com", TTL: time.Hour}
+	notifier := &GmailNotifier{
+		From:              "sender@example.com",
+		Unsubscribe:       signer,
+		UnsubscribeMailto: "unsubscribe@example.com",
+		UnsubscribeFooter: true,
+		Categories:        fakeCategories{"receipts": false},
+	}
+	id := uuid.New()
+	category := "marketing"
+	message := sendEmail(t, notifier, &entities.Notification{
+		ID:           id,
+		DeliveryType: entities.DeliveryTypeEmail,
+		Recipient:    "user@example.com",
+		Content:      `{"subject":"News","text":"Hello","html":"<html><body><p>Hello</p></body></html>"}`,
+		Category:     &category,
+	})
+
+	if got := message.Header.Get("Message-Id"); got != "<"+id.String()+"@example.com>" {
+		t.Errorf("Message-ID = %q", got)
+	}
+	if got := message.Header.Get("Subject"); got != "News" {
+		t.Errorf("Subject = %q", got)
+	}
+	listUnsubscribe := message.Header.Get("List-Unsubscribe")
+	if !strings.HasPrefix(listUnsubscribe, "<https://notify.example.com/api/v1/unsubscribe?token=") ||
+		!strings.HasSuffix(listUnsubscribe, ", <mailto:unsubscribe@example.com?subject=unsubscribe>") {
+		t.Errorf("List-Unsubscribe = %q", listUnsubscribe)
+	}
+	if got := message.Header.Get("List-Unsubscribe-Post"); got != "List-Unsubscribe=One-Click" {
+		t.Errorf("List-Unsubscribe-Post = %q", got)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q", message.Header.Get("Content-Type"))
+	}
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(part)
+		if !strings.Contains(string(body), "/api/v1/unsubscribe?token=") {
+			t.Errorf("%s part has no unsubscribe footer: %s", part.Header.Get("Content-Type"), body)
+		}
+		if strings.HasPrefix(part.Header.Get("Content-Type"), "text/html") &&
+			!strings.HasSuffix(strings.TrimSpace(string(body)), "</body></html>") {
+			t.Errorf("footer is not inside the html body: %s", body)
+		}
+	}
+
+	receipt := "receipts"
+	message = sendEmail(t, notifier, &entities.Notification{
+		DeliveryType: entities.DeliveryTypeEmail,
+		Recipient:    "user@example.com",
+		Content:      "Your receipt",
+		Category:     &receipt,
+	})
+	if got := message.Header.Get("List-Unsubscribe"); got != "" {
+		t.Errorf("transactional mail has List-Unsubscribe = %q", got)
+	}
+}
+
+func TestGmailNotifier_RawMessage(t *testing.T) {
+	notifier := &GmailNotifier{From: "sender@example.com"}
+	message := sendEmail(t, notifier, &entities.Notification{
+		DeliveryType: entities.DeliveryTypeEmail,
+		Recipient:    "user@example.com",
+		Content:      "Subject: Hi\r\nX-Campaign: spring\r\n\r\nBody",
+	})
+	if message.Header.Get("Subject") != "Hi" || message.Header.Get("X-Campaign") != "spring" {
+		t.Errorf("raw headers were not kept: %v", message.Header)
+	}
+	if message.Header.Get("List-Unsubscribe") != "" {
+		t.Errorf("List-Unsubscribe set without configuration")
+	}
+	body, _ := io.ReadAll(message.Body)
+	if string(body) != "Body" {
+		t.Errorf("body = %q", body)
+	}
+
+	message = sendEmail(t, notifier, &entities.Notification{
+		DeliveryType: entities.DeliveryTypeEmail,
+		Recipient:    "user@example.com",
+		Content:      "Note: plain text",
+	})
+	if message.Header.Get("Note") != "" {
+		t.Errorf("plain text was parsed as headers")
+	}
+}
+
+func TestGmailNotifier_SMTPErrors(t *testing.T) {
+	tests := []struct {
+		name            string
+		err             error
+		wantPermanent   bool
+		wantUnreachable bool
+	}{
+		{"mailbox unavailable", &textproto.Error{Code: 550, Msg: "5.1.1 user unknown"}, true, true},
+		{"message rejected", &textproto.Error{Code: 554, Msg: "5.7.1 rejected"}, true, false},
+		{"greylisted", &textproto.Error{Code: 451, Msg: "4.7.1 try again later"}, false, false},
+		{"connection", errors.New("dial tcp: timeout"), false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifier := &GmailNotifier{
+				From: "sender@example.com",
+				SendMail: func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+					return tt.err
+				},
+			}
+			err := notifier.Notify(context.Background(), &entities.Notification{Recipient: "user@example.com", Content: "hi"})
+			if IsPermanent(err) != tt.wantPermanent || errors.Is(err, ErrRecipientUnreachable) != tt.wantUnreachable {
+				t.Errorf("Notify() error = %v, permanent %v, unreachable %v", err, IsPermanent(err), errors.Is(err, ErrRecipientUnreachable))
+			}
+		})
+	}
+}
+
+type fakeTenants map[uuid.UUID]*entities.Tenant
+
+func (f fakeTenants) GetTenant(ctx context.Context, id uuid.UUID) (*entities.Tenant, error) {
+	tenant, ok := f[id]
+	if !ok {
+		return nil, errors.New("tenant not found")
+	}
+	return tenant, nil
+}
+
+func TestGmailNotifier_TenantSender(t *testing.T) {
+	from, host, password := "Retail <noreply@retail.example.com>", "smtp.retail.example.com", "retail-secret"
+	port := int32(2525)
+	withServer, fromOnly := uuid.New(), uuid.New()
+	notifier := &GmailNotifier{
+		From:     "service@example.com",
+		Password: "service-secret",
+		Tenants: NewTenantCache(fakeTenants{
+			withServer: {ID: withServer, EmailFrom: &from, SMTPHost: &host, SMTPPort: &port, SMTPPassword: &password},
+			fromOnly:   {ID: fromOnly, EmailFrom: &from},
+		}, time.Minute),
+	}
+
+	tests := []struct {
+		name     string
+		tenantID *uuid.UUID
+		wantAddr string
+		wantFrom string
+	}{
+		{"default tenant", nil, "smtp.gmail.com:587", "service@example.com"},
+		{"tenant with its own server", &withServer, "smtp.retail.example.com:2525", "noreply@retail.example.com"},
+		{"tenant with its own from", &fromOnly, "smtp.gmail.com:587", "noreply@retail.example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var addr, envelopeFrom string
+			notifier.SendMail = func(a string, _ smtp.Auth, f string, to []string, msg []byte) error {
+				addr, envelopeFrom = a, f
+				return nil
+			}
+			notification := &entities.Notification{ID: uuid.New(), Recipient: "user@example.com", Content: "hi", TenantID: tt.tenantID}
+			if err := notifier.Notify(context.Background(), notification); err != nil {
+				t.Fatalf("Notify() error = %v", err)
+			}
+			if addr != tt.wantAddr || envelopeFrom != tt.wantFrom {
+				t.Errorf("sent through %s from %q, want %s from %q", addr, envelopeFrom, tt.wantAddr, tt.wantFrom)
+			}
+		})
+	}
+
+	unknown := uuid.New()
+	err := notifier.Notify(context.Background(), &entities.Notification{Recipient: "user@example.com", Content: "hi", TenantID: &unknown})
+	if err == nil || IsPermanent(err) {
+		t.Errorf("Notify() of an unknown tenant error = %v, want a retryable error", err)
+	}
+}
@@ -0,0 +1,238 @@
+package notifiers
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"notification_system/pkg/jwt"
+)
+
+const (
+	defaultFCMEndpoint = "https://fcm.googleapis.com"
+	fcmScope           = "https://www.googleapis.com/auth/firebase.messaging"
+	fcmTokenLifetime   = time.Hour
+)
+
+// FCMNotifier sends through the FCM HTTP v1 API authorized by a service account.
+type FCMNotifier struct {
+	projectID   string
+	clientEmail string
+	tokenURI    string
+	privateKey  crypto.Signer
+	privateKID  string
+	endpoint    string
+	client      *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+type fcmServiceAccount struct {
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+type fcmRequest struct {
+	Message fcmMessage `json:"message"`
+}
+
+type fcmMessage struct {
+	Token        string            `json:"token"`
+	Notification *fcmNotification  `json:"notification,omitempty"`
+	Data         map[string]string `json:"data,omitempty"`
+	Android      *fcmAndroid       `json:"android,omitempty"`
+	APNs         *fcmAPNs          `json:"apns,omitempty"`
+}
+
+type fcmNotification struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+}
+
+type fcmAndroid struct {
+	CollapseKey  string                  `json:"collapse_key,omitempty"`
+	TTL          string                  `json:"ttl,omitempty"`
+	Notification *fcmAndroidNotification `json:"notification,omitempty"`
+}
+
+type fcmAndroidNotification struct {
+	Sound string `json:"sound,omitempty"`
+}
+
+type fcmAPNs struct {
+	Headers map[string]string `json:"headers,omitempty"`
+	Payload map[string]any    `json:"payload,omitempty"`
+}
+
+type fcmErrorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+		Details []struct {
+			Type      string `json:"@type"`
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+// NewFCMNotifier loads the service account key file. An empty endpoint means
+// the public FCM API, tokenURI overrides the OAuth endpoint of the service account.
+func NewFCMNotifier(serviceAccountFile, endpoint, tokenURI string, client *http.Client) (*FCMNotifier, error) {
+	data, err := os.ReadFile(serviceAccountFile)
+	if err != nil {
+		return nil, fmt.Errorf("notifiers.NewFCMNotifier read service account error: %w", err)
+	}
+	var account fcmServiceAccount
+	if err := json.Unmarshal(data, &account); err != nil {
+		return nil, fmt.Errorf("notifiers.NewFCMNotifier parse service account error: %w", err)
+	}
+	key, err := jwt.ParsePrivateKey([]byte(account.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("notifiers.NewFCMNotifier parse private key error: %w", err)
+	}
+	if endpoint == "" {
+		endpoint = defaultFCMEndpoint
+	}
+	if tokenURI == "" {
+		tokenURI = account.TokenURI
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &FCMNotifier{
+		projectID:   account.ProjectID,
+		clientEmail: account.ClientEmail,
+		tokenURI:    tokenURI,
+		privateKey:  key,
+		privateKID:  account.PrivateKeyID,
+		endpoint:    strings.TrimRight(endpoint, "/"),
+		client:      client,
+	}, nil
+}
+
+func (notifier *FCMNotifier) Send(ctx context.Context, token string, content *PushContent) error {
+	accessToken, err := notifier.getAccessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	message := fcmMessage{
+		Token: token,
+		Data:  content.Data,
+	}
+	if content.Title != "" || content.Body != "" {
+		message.Notification = &fcmNotification{Title: content.Title, Body: content.Body}
+	}
+	android := &fcmAndroid{CollapseKey: content.CollapseKey}
+	if content.TTLSeconds != nil {
+		android.TTL = fmt.Sprintf("%ds", *content.TTLSeconds)
+	}
+	if content.Sound != "" {
+		android.Notification = &fcmAndroidNotification{Sound: content.Sound}
+	}
+	message.Android = android
+	if content.Badge != nil || content.Sound != "" {
+		aps := map[string]any{}
+		if content.Badge != nil {
+			aps["badge"] = *content.Badge
+		}
+		if content.Sound != "" {
+			aps["sound"] = content.Sound
+		}
+		message.APNs = &fcmAPNs{Payload: map[string]any{"aps": aps}}
+	}
+
+	url := fmt.Sprintf("%s/v1/projects/%s/messages:send", notifier.endpoint, notifier.projectID)
+	headers := map[string]string{"Authorization": "Bearer " + accessToken}
+	resp, body, err := postJSON(ctx, notifier.client, url, fcmRequest{Message: message}, headers)
+	if err != nil {
+		return fmt.Errorf("notifiers.fcm error: %w", err)
+	}
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var fcmErr fcmErrorResponse
+	_ = json.Unmarshal(body, &fcmErr)
+	errorCode := fcmErr.Error.Status
+	for _, detail := range fcmErr.Error.Details {
+		if detail.ErrorCode != "" {
+			errorCode = detail.ErrorCode
+		}
+	}
+	err = fmt.Errorf("notifiers.fcm error %d %s: %s", resp.StatusCode, errorCode, fcmErr.Error.Message)
+	switch {
+	case errorCode == "UNREGISTERED", resp.StatusCode == http.StatusNotFound:
+		return &PermanentError{Err: fmt.Errorf("%w: %w", ErrRecipientUnreachable, err)}
+	case resp.StatusCode == http.StatusUnauthorized:
+		notifier.resetAccessToken()
+		return err
+	case errorCode == "QUOTA_EXCEEDED", resp.StatusCode == http.StatusTooManyRequests:
+		return &RetryAfterError{RetryAfter: retryAfter(resp.Header, time.Minute), Err: err}
+	}
+	return httpStatusError("notifiers.fcm", resp, body)
+}
+
+func (notifier *FCMNotifier) getAccessToken(ctx context.Context) (string, error) {
+	notifier.mu.Lock()
+	defer notifier.mu.Unlock()
+	if notifier.accessToken != "" && time.Now().Before(notifier.expiresAt) {
+		return notifier.accessToken, nil
+	}
+
+	now := time.Now()
+	assertion, err := jwt.Sign(notifier.privateKey, notifier.privateKID, map[string]any{
+		"iss":   notifier.clientEmail,
+		"scope": fcmScope,
+		"aud":   notifier.tokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(fcmTokenLifetime).Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("notifiers.fcm sign assertion error: %w", err)
+	}
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, notifier.tokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("notifiers.fcm token request error: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := notifier.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("notifiers.fcm token request error: %w", err)
+	}
+	defer resp.Body.Close()
+	var tokenResp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil || resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("notifiers.fcm token request failed with status %d", resp.StatusCode)
+	}
+	notifier.accessToken = tokenResp.AccessToken
+	// refresh a minute early so in-flight requests do not use an expired token
+	notifier.expiresAt = now.Add(time.Duration(tokenResp.ExpiresIn)*time.Second - time.Minute)
+	return notifier.accessToken, nil
+}
+
+func (notifier *FCMNotifier) resetAccessToken() {
+	notifier.mu.Lock()
+	defer notifier.mu.Unlock()
+	notifier.accessToken = ""
+}
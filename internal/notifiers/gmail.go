@@ -1,23 +1,185 @@
 package notifiers
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/mail"
 	"net/smtp"
+	"net/textproto"
+	"strconv"
 
-	"notification_system/config"
+	"github.com/google/uuid"
+
+	"notification_system/internal/entities"
+	"notification_system/internal/unsubscribe"
 )
 
+// CategoryStore tells whether notifications of a category honour opt-outs.
+type CategoryStore interface {
+	IsCategorySuppressible(ctx context.Context, name string) (bool, error)
+}
+
+// TenantStore returns the tenant whose email settings replace the ones of the notifier.
+type TenantStore interface {
+	GetTenant(ctx context.Context, id uuid.UUID) (*entities.Tenant, error)
+}
+
 type GmailNotifier struct {
-	From string
+	From     string
+	Password string
+	// Tenants sends the email of a tenant with its own From address and SMTP server,
+	// nil sends every email with the notifier settings
+	Tenants TenantStore
+	// Unsubscribe enables List-Unsubscribe links, nil disables them
+	Unsubscribe       *unsubscribe.Signer
+	UnsubscribeMailto string
+	UnsubscribeFooter bool
+	Categories        CategoryStore
+	// SendMail defaults to smtp.SendMail
+	SendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
 }
 
-func (notifier *GmailNotifier) Notify(to, message string) error {
-	smtpHost := "smtp.gmail.com"
-	smtpPort := "587"
-	auth := smtp.PlainAuth("", notifier.From, config.Cfg.GmailAppPassword, smtpHost)
-	err := smtp.SendMail(smtpHost+":"+smtpPort, auth, notifier.From, []string{to}, []byte(message))
+// smtpSender is the server and account an email is sent with.
+type smtpSender struct {
+	From     string
+	Host     string
+	Port     string
+	Username string
+	Password string
+}
+
+func (notifier *GmailNotifier) Notify(ctx context.Context, notification *entities.Notification) error {
+	sender, err := notifier.sender(ctx, notification)
+	if err != nil {
+		return fmt.Errorf("notifiers.gmail error: %w", err)
+	}
+	to := notification.Recipient
+	message := &emailMessage{
+		From:              sender.From,
+		To:                to,
+		MessageID:         emailMessageID(notification.ID.String(), sender.From),
+		Content:           notification.Content,
+		UnsubscribeMailto: notifier.UnsubscribeMailto,
+		Footer:            notifier.UnsubscribeFooter,
+	}
+	unsubscribeURL, err := notifier.unsubscribeURL(ctx, notification)
 	if err != nil {
 		return fmt.Errorf("notifiers.gmail error: %w", err)
 	}
+	message.UnsubscribeURL = unsubscribeURL
+	if unsubscribeURL == "" && notifier.Unsubscribe != nil {
+		// transactional mail carries no unsubscribe links at all
+		message.UnsubscribeMailto = ""
+	}
+	data, err := message.Bytes()
+	if err != nil {
+		return &PermanentError{Err: fmt.Errorf("notifiers.gmail error: %w", err)}
+	}
+
+	var auth smtp.Auth
+	if sender.Password != "" {
+		auth = smtp.PlainAuth("", sender.Username, sender.Password, sender.Host)
+	}
+	sendMail := notifier.SendMail
+	if sendMail == nil {
+		sendMail = smtp.SendMail
+	}
+	err = sendMail(net.JoinHostPort(sender.Host, sender.Port), auth, sender.envelopeFrom(), []string{to}, data)
+	if err != nil {
+		return smtpError(err)
+	}
 	return nil
 }
+
+// sender returns the Gmail account of the notifier unless the tenant of the notification
+// has its own From address, and its own server when it set one.
+func (notifier *GmailNotifier) sender(ctx context.Context, notification *entities.Notification) (*smtpSender, error) {
+	sender := &smtpSender{
+		From:     notifier.From,
+		Host:     "smtp.gmail.com",
+		Port:     "587",
+		Username: notifier.From,
+		Password: notifier.Password,
+	}
+	if notification.TenantID == nil || notifier.Tenants == nil {
+		return sender, nil
+	}
+	tenant, err := notifier.Tenants.GetTenant(ctx, *notification.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	if tenant.EmailFrom != nil {
+		sender.From = *tenant.EmailFrom
+	}
+	if tenant.SMTPHost == nil {
+		return sender, nil
+	}
+	sender.Host = *tenant.SMTPHost
+	if tenant.SMTPPort != nil {
+		sender.Port = strconv.Itoa(int(*tenant.SMTPPort))
+	}
+	sender.Username = sender.envelopeFrom()
+	if tenant.SMTPUsername != nil {
+		sender.Username = *tenant.SMTPUsername
+	}
+	sender.Password = ""
+	if tenant.SMTPPassword != nil {
+		sender.Password = *tenant.SMTPPassword
+	}
+	return sender, nil
+}
+
+// envelopeFrom is the bare address of From, which may carry a display name.
+func (sender *smtpSender) envelopeFrom() string {
+	if address, err := mail.ParseAddress(sender.From); err == nil {
+		return address.Address
+	}
+	return sender.From
+}
+
+// smtpError classifies the SMTP reply: 4xx replies are retried, 5xx replies are permanent
+// and the mailbox replies 550, 551 and 553 mark the recipient unreachable.
+func smtpError(err error) error {
+	err = fmt.Errorf("notifiers.gmail error: %w", err)
+	var protoErr *textproto.Error
+	if !errors.As(err, &protoErr) || protoErr.Code < 500 {
+		return err
+	}
+	switch protoErr.Code {
+	case 550, 551, 553:
+		return &PermanentError{Err: fmt.Errorf("%w: %w", ErrRecipientUnreachable, err)}
+	}
+	return &PermanentError{Err: err}
+}
+
+func (notifier *GmailNotifier) unsubscribeURL(ctx context.Context, notification *entities.Notification) (string, error) {
+	if notifier.Unsubscribe == nil {
+		return "", nil
+	}
+	claims := unsubscribe.Claims{
+		DeliveryType: notification.DeliveryType,
+		Address:      notification.Recipient,
+		Category:     entities.PreferenceAny,
+	}
+	if notification.Category != nil {
+		claims.Category = *notification.Category
+		if notifier.Categories != nil {
+			suppressible, err := notifier.Categories.IsCategorySuppressible(ctx, claims.Category)
+			if err != nil {
+				return "", err
+			}
+			if !suppressible {
+				return "", nil
+			}
+		}
+	}
+	if notification.UserID != nil {
+		claims.UserID = *notification.UserID
+	}
+	if notification.TenantID != nil {
+		claims.TenantID = notification.TenantID.String()
+	}
+	return notifier.Unsubscribe.URL(claims)
+}
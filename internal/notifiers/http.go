@@ -0,0 +1,174 @@
+package notifiers
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxResponseBody = 4096
+
+// StatusCodes is a set of HTTP status codes treated as a successful delivery.
+type StatusCodes []statusCodeRange
+
+type statusCodeRange struct {
+	from, to int
+}
+
+// ParseStatusCodes parses a comma-separated list of codes and ranges, e.g. "200-299,304".
+func ParseStatusCodes(s string) (StatusCodes, error) {
+	var codes StatusCodes
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		fromStr, toStr, isRange := strings.Cut(part, "-")
+		if !isRange {
+			toStr = fromStr
+		}
+		from, err := strconv.Atoi(strings.TrimSpace(fromStr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid status code %q", part)
+		}
+		to, err := strconv.Atoi(strings.TrimSpace(toStr))
+		if err != nil || to < from {
+			return nil, fmt.Errorf("invalid status code range %q", part)
+		}
+		codes = append(codes, statusCodeRange{from: from, to: to})
+	}
+	return codes, nil
+}
+
+func (codes StatusCodes) Contains(code int) bool {
+	if len(codes) == 0 {
+		return code >= 200 && code < 300
+	}
+	for _, r := range codes {
+		if code >= r.from && code <= r.to {
+			return true
+		}
+	}
+	return false
+}
+
+// NewHTTPClient creates a client like NewPublicHTTPClient and, when certFile and keyFile
+// are set, a client certificate for mutual TLS. caFile replaces the system root CAs.
+func NewHTTPClient(timeout time.Duration, certFile, keyFile, caFile string, allowed []netip.Prefix) (*http.Client, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("notifiers.NewHTTPClient load client certificate error: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if caFile != "" {
+		caPEM, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("notifiers.NewHTTPClient read CA error: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("notifiers.NewHTTPClient no certificates found in %s", caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	client := NewPublicHTTPClient(timeout, allowed)
+	client.Transport.(*http.Transport).TLSClientConfig = tlsConfig
+	return client, nil
+}
+
+// retryAfter reads the Retry-After header which holds either seconds or an HTTP date.
+func retryAfter(header http.Header, fallback time.Duration) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return fallback
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return fallback
+}
+
+// httpStatusError classifies a failed HTTP delivery: rate limits and server errors
+// can be retried, gone endpoints are unreachable and other client errors are permanent.
+func httpStatusError(op string, resp *http.Response, body []byte) error {
+	err := fmt.Errorf("%s error: unexpected status %d: %s", op, resp.StatusCode, strings.TrimSpace(string(body)))
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode == http.StatusServiceUnavailable && resp.Header.Get("Retry-After") != "":
+		return &RetryAfterError{RetryAfter: retryAfter(resp.Header, time.Minute), Err: err}
+	case resp.StatusCode == http.StatusGone:
+		return &PermanentError{Err: fmt.Errorf("%w: %w", ErrRecipientUnreachable, err)}
+	case resp.StatusCode == http.StatusRequestTimeout:
+		return err
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		return &PermanentError{Err: err}
+	}
+	return err
+}
+
+// withoutURL strips the request URL from an error of the HTTP client. Webhook URLs and
+// the bot token in the path of the Telegram API are secrets that must not end up in the
+// logs or the failure details of a notification.
+func withoutURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return fmt.Errorf("%s: %w", urlErr.Op, urlErr.Err)
+	}
+	return err
+}
+
+// postJSON marshals payload, posts it to url and returns the response with a bounded body.
+func postJSON(ctx context.Context, client *http.Client, url string, payload any, headers map[string]string) (*http.Response, []byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, nil, fmt.Errorf("marshal error: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, &PermanentError{Err: fmt.Errorf("%w: invalid URL", ErrRecipientUnreachable)}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		err = withoutURL(err)
+		if errors.Is(err, ErrAddressNotAllowed) {
+			return nil, nil, &PermanentError{Err: fmt.Errorf("%w: %w", ErrRecipientUnreachable, err)}
+		}
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, respBody, nil
+}
+
+// isJSONObject reports whether the content is a structured JSON payload rather than plain text.
+func isJSONObject(message string) bool {
+	return strings.HasPrefix(strings.TrimSpace(message), "{")
+}
@@ -1,5 +1,19 @@
 package notifiers
 
+import (
+	"context"
+
+	"notification_system/internal/entities"
+)
+
 type Notifier interface {
-	Notify(to, message string) error
+	Notify(ctx context.Context, notification *entities.Notification) error
+}
+
+// NoopNotifier accepts every notification without sending it anywhere.
+// It backs the "test" delivery type used by the end-to-end tests.
+type NoopNotifier struct{}
+
+func (notifier *NoopNotifier) Notify(ctx context.Context, notification *entities.Notification) error {
+	return nil
 }
@@ -0,0 +1,44 @@
+package notifiers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrRecipientUnreachable = errors.New("recipient is unreachable")
+	ErrInvalidContent       = errors.New("invalid notification content")
+)
+
+// RetryAfterError is returned when the provider asks to postpone the next attempt.
+type RetryAfterError struct {
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("retry after %s: %v", e.RetryAfter, e.Err)
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// PermanentError is returned when retrying the notification cannot succeed.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return fmt.Sprintf("permanent failure: %v", e.Err)
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+func IsPermanent(err error) bool {
+	var permanentErr *PermanentError
+	return errors.As(err, &permanentErr)
+}
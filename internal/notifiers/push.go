@@ -0,0 +1,79 @@
+package notifiers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"notification_system/internal/entities"
+)
+
+const (
+	PushProviderFCM  = "fcm"
+	PushProviderAPNs = "apns"
+)
+
+var ErrProviderNotConfigured = errors.New("push provider is not configured")
+
+// PushNotifier routes device tokens given as "<provider>:<token>" to FCM or APNs.
+type PushNotifier struct {
+	FCM  *FCMNotifier
+	APNs *APNsNotifier
+}
+
+// PushContent is the structured form of a push notification content.
+// Plain text content is sent as the body.
+type PushContent struct {
+	Title       string            `json:"title,omitempty"`
+	Body        string            `json:"body"`
+	Data        map[string]string `json:"data,omitempty"`
+	Badge       *int              `json:"badge,omitempty"`
+	Sound       string            `json:"sound,omitempty"`
+	CollapseKey string            `json:"collapse_key,omitempty"`
+	TTLSeconds  *int              `json:"ttl_seconds,omitempty"`
+}
+
+func (notifier *PushNotifier) Notify(ctx context.Context, notification *entities.Notification) error {
+	to, message := notification.Recipient, notification.Content
+	provider, token, ok := strings.Cut(to, ":")
+	if !ok || token == "" {
+		return &PermanentError{Err: fmt.Errorf("notifiers.push error: %w: invalid device token %q", ErrRecipientUnreachable, to)}
+	}
+	content, err := parsePushContent(message)
+	if err != nil {
+		return &PermanentError{Err: fmt.Errorf("notifiers.push error: %w", err)}
+	}
+
+	switch provider {
+	case PushProviderFCM:
+		if notifier.FCM == nil {
+			return &PermanentError{Err: fmt.Errorf("notifiers.push error: %w: %s", ErrProviderNotConfigured, provider)}
+		}
+		return notifier.FCM.Send(ctx, token, content)
+	case PushProviderAPNs:
+		if notifier.APNs == nil {
+			return &PermanentError{Err: fmt.Errorf("notifiers.push error: %w: %s", ErrProviderNotConfigured, provider)}
+		}
+		return notifier.APNs.Send(ctx, token, content)
+	}
+	return &PermanentError{Err: fmt.Errorf("notifiers.push error: unknown provider %q", provider)}
+}
+
+func parsePushContent(message string) (*PushContent, error) {
+	if !isJSONObject(message) {
+		return &PushContent{Body: message}, nil
+	}
+	var content PushContent
+	if err := json.Unmarshal([]byte(message), &content); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidContent, err)
+	}
+	if content.Title == "" && content.Body == "" && len(content.Data) == 0 {
+		return nil, fmt.Errorf("%w: title, body or data is required", ErrInvalidContent)
+	}
+	if content.TTLSeconds != nil && *content.TTLSeconds < 0 {
+		return nil, fmt.Errorf("%w: ttl_seconds must not be negative", ErrInvalidContent)
+	}
+	return &content, nil
+}
@@ -0,0 +1,153 @@
+package notifiers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"notification_system/internal/entities"
+)
+
+func newHTTP2Server(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	server := httptest.NewUnstartedServer(handler)
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func writeKey(t *testing.T, name string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestPushNotifier_APNs(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile := writeKey(t, "AuthKey.p8", der)
+
+	server := newHTTP2Server(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor != 2 {
+			t.Errorf("proto = %s, want HTTP/2", r.Proto)
+		}
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "bearer ") || r.Header.Get("apns-topic") != "com.example.app" {
+			t.Errorf("unexpected headers %v", r.Header)
+		}
+		var payload map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		if _, ok := payload["aps"]; !ok {
+			t.Errorf("payload without aps: %v", payload)
+		}
+		switch r.URL.Path {
+		case "/3/device/valid":
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusGone)
+			_, _ = w.Write([]byte(`{"reason":"Unregistered"}`))
+		}
+	})
+
+	apns, err := NewAPNsNotifier(keyFile, "KEYID", "TEAMID", "com.example.app", server.URL, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	notifier := &PushNotifier{APNs: apns}
+	message := `{"title":"Hi","body":"there","badge":3,"sound":"default","collapse_key":"c","ttl_seconds":60}`
+
+	if err := notifier.Notify(context.Background(), &entities.Notification{Recipient: "apns:valid", Content: message}); err != nil {
+		t.Errorf("Notify() error = %v", err)
+	}
+	err = notifier.Notify(context.Background(), &entities.Notification{Recipient: "apns:stale", Content: message})
+	if !errors.Is(err, ErrRecipientUnreachable) || !IsPermanent(err) {
+		t.Errorf("Notify() error = %v, want unreachable recipient", err)
+	}
+	if err := notifier.Notify(context.Background(), &entities.Notification{Recipient: "fcm:token", Content: message}); !errors.Is(err, ErrProviderNotConfigured) {
+		t.Errorf("Notify() error = %v, want %v", err, ErrProviderNotConfigured)
+	}
+}
+
+func TestPushNotifier_FCM(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenRequests := 0
+	server := newHTTP2Server(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			tokenRequests++
+			if r.FormValue("assertion") == "" {
+				t.Errorf("token request without assertion")
+			}
+			_, _ = w.Write([]byte(`{"access_token":"access","expires_in":3600}`))
+		case "/v1/projects/project/messages:send":
+			if r.Header.Get("Authorization") != "Bearer access" {
+				t.Errorf("unexpected authorization %q", r.Header.Get("Authorization"))
+			}
+			var request fcmRequest
+			_ = json.NewDecoder(r.Body).Decode(&request)
+			if request.Message.Token == "valid" {
+				_, _ = w.Write([]byte(`{"name":"projects/project/messages/1"}`))
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"code":404,"status":"NOT_FOUND","details":[{"errorCode":"UNREGISTERED"}]}}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+
+	account, _ := json.Marshal(fcmServiceAccount{
+		ProjectID:   "project",
+		PrivateKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		ClientEmail: "sender@project.iam.gserviceaccount.com",
+	})
+	accountFile := filepath.Join(t.TempDir(), "service-account.json")
+	if err := os.WriteFile(accountFile, account, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fcm, err := NewFCMNotifier(accountFile, server.URL, server.URL+"/token", server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	notifier := &PushNotifier{FCM: fcm}
+
+	if err := notifier.Notify(context.Background(), &entities.Notification{Recipient: "fcm:valid", Content: "hello"}); err != nil {
+		t.Errorf("Notify() error = %v", err)
+	}
+	err = notifier.Notify(context.Background(), &entities.Notification{Recipient: "fcm:stale", Content: "hello"})
+	if !errors.Is(err, ErrRecipientUnreachable) || !IsPermanent(err) {
+		t.Errorf("Notify() error = %v, want unreachable recipient", err)
+	}
+	if tokenRequests != 1 {
+		t.Errorf("token requests = %d, want cached access token", tokenRequests)
+	}
+}
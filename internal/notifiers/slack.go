@@ -0,0 +1,117 @@
+package notifiers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"notification_system/internal/entities"
+)
+
+const defaultSlackAPIURL = "https://slack.com/api"
+
+// SlackNotifier sends to an incoming webhook when the recipient is a URL
+// and through chat.postMessage when the recipient is a channel ID.
+type SlackNotifier struct {
+	BotToken string
+	APIURL   string
+	Client   *http.Client
+}
+
+// SlackContent is the structured form of a slack notification content.
+// Blocks are passed to Slack as is, see https://api.slack.com/block-kit.
+type SlackContent struct {
+	Text      string          `json:"text"`
+	Blocks    json.RawMessage `json:"blocks,omitempty"`
+	ThreadTS  string          `json:"thread_ts,omitempty"`
+	Username  string          `json:"username,omitempty"`
+	IconEmoji string          `json:"icon_emoji,omitempty"`
+}
+
+type slackPostMessageRequest struct {
+	SlackContent
+	Channel string `json:"channel,omitempty"`
+}
+
+type slackResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+}
+
+func (notifier *SlackNotifier) Notify(ctx context.Context, notification *entities.Notification) error {
+	to, message := notification.Recipient, notification.Content
+	content := SlackContent{Text: message}
+	if isJSONObject(message) {
+		if err := json.Unmarshal([]byte(message), &content); err != nil {
+			return &PermanentError{Err: fmt.Errorf("notifiers.slack error: %w: %w", ErrInvalidContent, err)}
+		}
+	}
+	if content.Text == "" && len(content.Blocks) == 0 {
+		return &PermanentError{Err: fmt.Errorf("notifiers.slack error: %w: text or blocks are required", ErrInvalidContent)}
+	}
+
+	if strings.HasPrefix(to, "https://") {
+		return notifier.notifyWebhook(ctx, to, content)
+	}
+	return notifier.postMessage(ctx, to, content)
+}
+
+func (notifier *SlackNotifier) notifyWebhook(ctx context.Context, url string, content SlackContent) error {
+	resp, body, err := postJSON(ctx, notifier.Client, url, content, nil)
+	if err != nil {
+		return fmt.Errorf("notifiers.slack webhook error: %w", err)
+	}
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	switch strings.TrimSpace(string(body)) {
+	case "no_service", "channel_not_found", "channel_is_archived", "action_prohibited", "no_active_hooks":
+		return &PermanentError{
+			Err: fmt.Errorf("%w: notifiers.slack webhook error: %s", ErrRecipientUnreachable, body),
+		}
+	}
+	return httpStatusError("notifiers.slack webhook", resp, body)
+}
+
+func (notifier *SlackNotifier) postMessage(ctx context.Context, channel string, content SlackContent) error {
+	apiURL := notifier.APIURL
+	if apiURL == "" {
+		apiURL = defaultSlackAPIURL
+	}
+	headers := map[string]string{"Authorization": "Bearer " + notifier.BotToken}
+	request := slackPostMessageRequest{SlackContent: content, Channel: channel}
+	resp, body, err := postJSON(ctx, notifier.Client, strings.TrimRight(apiURL, "/")+"/chat.postMessage", request, headers)
+	if err != nil {
+		return fmt.Errorf("notifiers.slack chat.postMessage error: %w", err)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return &RetryAfterError{
+			RetryAfter: retryAfter(resp.Header, time.Minute),
+			Err:        fmt.Errorf("notifiers.slack chat.postMessage error: rate limited"),
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return httpStatusError("notifiers.slack chat.postMessage", resp, body)
+	}
+
+	var slackResp slackResponse
+	if err := json.Unmarshal(body, &slackResp); err != nil {
+		return fmt.Errorf("notifiers.slack chat.postMessage decode error: %w", err)
+	}
+	if slackResp.OK {
+		return nil
+	}
+	err = fmt.Errorf("notifiers.slack chat.postMessage error: %s", slackResp.Error)
+	switch slackResp.Error {
+	case "ratelimited":
+		return &RetryAfterError{RetryAfter: retryAfter(resp.Header, time.Minute), Err: err}
+	case "channel_not_found", "is_archived", "not_in_channel", "account_inactive", "user_not_found":
+		return &PermanentError{Err: fmt.Errorf("%w: %w", ErrRecipientUnreachable, err)}
+	case "internal_error", "fatal_error", "service_unavailable", "request_timeout":
+		return err
+	}
+	return &PermanentError{Err: err}
+}
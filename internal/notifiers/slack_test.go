@@ -0,0 +1,85 @@
+package notifiers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"notification_system/internal/entities"
+)
+
+func TestSlackNotifier_Notify(t *testing.T) {
+	tests := []struct {
+		name            string
+		webhook         bool
+		message         string
+		status          int
+		response        string
+		wantErr         bool
+		wantUnreachable bool
+		wantRetryAfter  time.Duration
+	}{
+		{"webhook", true, "hello", http.StatusOK, "ok", false, false, 0},
+		{"webhook archived channel", true, "hello", http.StatusGone, "channel_is_archived", true, true, 0},
+		{"post message with blocks", false, `{"text":"hi","blocks":[{"type":"divider"}]}`, http.StatusOK, `{"ok":true}`, false, false, 0},
+		{"post message channel not found", false, "hello", http.StatusOK, `{"ok":false,"error":"channel_not_found"}`, true, true, 0},
+		{"post message rate limited", false, "hello", http.StatusTooManyRequests, ``, true, false, 30 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var request slackPostMessageRequest
+				_ = json.NewDecoder(r.Body).Decode(&request)
+				if !tt.webhook {
+					if r.URL.Path != "/chat.postMessage" || r.Header.Get("Authorization") != "Bearer token" {
+						t.Errorf("unexpected request %s", r.URL.Path)
+					}
+					if request.Channel != "C123" {
+						t.Errorf("channel = %q, want %q", request.Channel, "C123")
+					}
+				}
+				w.Header().Set("Retry-After", "30")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			notifier := &SlackNotifier{BotToken: "token", APIURL: server.URL, Client: server.Client()}
+			to := "C123"
+			if tt.webhook {
+				to = server.URL + "/services/T/B/X"
+			}
+			err := notifier.Notify(context.Background(), &entities.Notification{Recipient: to, Content: tt.message})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Notify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrRecipientUnreachable) != tt.wantUnreachable {
+				t.Errorf("Notify() unreachable = %v, want %v", !tt.wantUnreachable, tt.wantUnreachable)
+			}
+			var retryAfterErr *RetryAfterError
+			if errors.As(err, &retryAfterErr) != (tt.wantRetryAfter != 0) ||
+				(retryAfterErr != nil && retryAfterErr.RetryAfter != tt.wantRetryAfter) {
+				t.Errorf("Notify() error = %v, want retry after %s", err, tt.wantRetryAfter)
+			}
+		})
+	}
+}
+
+func TestSlackNotifier_Notify_HidesWebhookURL(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	server.Close()
+	notifier := &SlackNotifier{}
+
+	err := notifier.Notify(context.Background(), &entities.Notification{
+		Recipient: server.URL + "/services/T000/B000/secret-path",
+		Content:   "hello",
+	})
+	if err == nil || strings.Contains(err.Error(), "secret-path") {
+		t.Errorf("Notify() error = %v, want a transport error without the webhook URL", err)
+	}
+}
@@ -0,0 +1,132 @@
+package notifiers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"notification_system/internal/entities"
+)
+
+const (
+	adaptiveCardContentType = "application/vnd.microsoft.card.adaptive"
+	adaptiveCardSchema      = "http://adaptivecards.io/schemas/adaptive-card.json"
+	adaptiveCardVersion     = "1.4"
+)
+
+// TeamsNotifier posts Adaptive Cards to a Microsoft Teams webhook given as the recipient.
+type TeamsNotifier struct {
+	Client *http.Client
+}
+
+// TeamsContent is the structured form of a teams notification content.
+// Card, when set, is a complete Adaptive Card and takes precedence over the other fields.
+type TeamsContent struct {
+	Title   string          `json:"title,omitempty"`
+	Text    string          `json:"text"`
+	Actions []TeamsAction   `json:"actions,omitempty"`
+	Card    json.RawMessage `json:"card,omitempty"`
+}
+
+type TeamsAction struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+type teamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+type teamsAttachment struct {
+	ContentType string          `json:"contentType"`
+	Content     json.RawMessage `json:"content"`
+}
+
+type adaptiveCard struct {
+	Schema  string           `json:"$schema"`
+	Type    string           `json:"type"`
+	Version string           `json:"version"`
+	Body    []map[string]any `json:"body"`
+	Actions []map[string]any `json:"actions,omitempty"`
+}
+
+func (notifier *TeamsNotifier) Notify(ctx context.Context, notification *entities.Notification) error {
+	to, message := notification.Recipient, notification.Content
+	content := TeamsContent{Text: message}
+	if isJSONObject(message) {
+		if err := json.Unmarshal([]byte(message), &content); err != nil {
+			return &PermanentError{Err: fmt.Errorf("notifiers.teams error: %w: %w", ErrInvalidContent, err)}
+		}
+	}
+	card, err := teamsCard(&content)
+	if err != nil {
+		return &PermanentError{Err: fmt.Errorf("notifiers.teams error: %w", err)}
+	}
+	payload := teamsMessage{
+		Type: "message",
+		Attachments: []teamsAttachment{
+			{ContentType: adaptiveCardContentType, Content: card},
+		},
+	}
+
+	resp, body, err := postJSON(ctx, notifier.Client, to, payload, nil)
+	if err != nil {
+		return fmt.Errorf("notifiers.teams error: %w", err)
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		// legacy connectors answer 200 and report throttling in the body
+		if strings.Contains(string(body), "HTTP error 429") {
+			return &RetryAfterError{
+				RetryAfter: retryAfter(resp.Header, time.Minute),
+				Err:        fmt.Errorf("notifiers.teams error: %s", body),
+			}
+		}
+		return nil
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return &PermanentError{
+			Err: fmt.Errorf("%w: notifiers.teams error: webhook not found", ErrRecipientUnreachable),
+		}
+	}
+	return httpStatusError("notifiers.teams", resp, body)
+}
+
+func teamsCard(content *TeamsContent) (json.RawMessage, error) {
+	if len(content.Card) != 0 {
+		return content.Card, nil
+	}
+	if content.Text == "" {
+		return nil, fmt.Errorf("%w: text or card is required", ErrInvalidContent)
+	}
+	card := adaptiveCard{
+		Schema:  adaptiveCardSchema,
+		Type:    "AdaptiveCard",
+		Version: adaptiveCardVersion,
+	}
+	if content.Title != "" {
+		card.Body = append(card.Body, map[string]any{
+			"type":   "TextBlock",
+			"text":   content.Title,
+			"size":   "Large",
+			"weight": "Bolder",
+			"wrap":   true,
+		})
+	}
+	card.Body = append(card.Body, map[string]any{
+		"type": "TextBlock",
+		"text": content.Text,
+		"wrap": true,
+	})
+	for _, action := range content.Actions {
+		card.Actions = append(card.Actions, map[string]any{
+			"type":  "Action.OpenUrl",
+			"title": action.Title,
+			"url":   action.URL,
+		})
+	}
+	return json.Marshal(card)
+}
@@ -0,0 +1,152 @@
+package notifiers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"notification_system/internal/entities"
+)
+
+const (
+	TelegramParseModeMarkdown   = "Markdown"
+	TelegramParseModeMarkdownV2 = "MarkdownV2"
+	TelegramParseModeHTML       = "HTML"
+
+	defaultTelegramAPIURL = "https://api.telegram.org"
+)
+
+type TelegramNotifier struct {
+	BotToken string
+	APIURL   string
+	Client   *http.Client
+}
+
+// TelegramContent is the structured form of a telegram notification content.
+// Plain text content is sent as is.
+type TelegramContent struct {
+	Text                  string                     `json:"text"`
+	ParseMode             string                     `json:"parse_mode,omitempty"`
+	InlineKeyboard        [][]TelegramInlineKeyboard `json:"inline_keyboard,omitempty"`
+	DisableWebPagePreview bool                       `json:"disable_web_page_preview,omitempty"`
+}
+
+type TelegramInlineKeyboard struct {
+	Text         string `json:"text"`
+	URL          string `json:"url,omitempty"`
+	CallbackData string `json:"callback_data,omitempty"`
+}
+
+type telegramSendMessageRequest struct {
+	ChatID                string                `json:"chat_id"`
+	Text                  string                `json:"text"`
+	ParseMode             string                `json:"parse_mode,omitempty"`
+	DisableWebPagePreview bool                  `json:"disable_web_page_preview,omitempty"`
+	ReplyMarkup           *telegramInlineMarkup `json:"reply_markup,omitempty"`
+}
+
+type telegramInlineMarkup struct {
+	InlineKeyboard [][]TelegramInlineKeyboard `json:"inline_keyboard"`
+}
+
+type telegramResponse struct {
+	OK          bool   `json:"ok"`
+	ErrorCode   int    `json:"error_code"`
+	Description string `json:"description"`
+	Parameters  struct {
+		RetryAfter int `json:"retry_after"`
+	} `json:"parameters"`
+}
+
+func (notifier *TelegramNotifier) Notify(ctx context.Context, notification *entities.Notification) error {
+	to, message := notification.Recipient, notification.Content
+	content, err := parseTelegramContent(message)
+	if err != nil {
+		return &PermanentError{Err: fmt.Errorf("notifiers.telegram error: %w", err)}
+	}
+	request := telegramSendMessageRequest{
+		ChatID:                to,
+		Text:                  content.Text,
+		ParseMode:             content.ParseMode,
+		DisableWebPagePreview: content.DisableWebPagePreview,
+	}
+	if len(content.InlineKeyboard) != 0 {
+		request.ReplyMarkup = &telegramInlineMarkup{InlineKeyboard: content.InlineKeyboard}
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("notifiers.telegram marshal error: %w", err)
+	}
+
+	apiURL := notifier.APIURL
+	if apiURL == "" {
+		apiURL = defaultTelegramAPIURL
+	}
+	url := fmt.Sprintf("%s/bot%s/sendMessage", strings.TrimRight(apiURL, "/"), notifier.BotToken)
+	client := notifier.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("notifiers.telegram request error: %w", withoutURL(err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("notifiers.telegram request error: %w", withoutURL(err))
+	}
+	defer resp.Body.Close()
+
+	var telegramResp telegramResponse
+	if err := json.NewDecoder(resp.Body).Decode(&telegramResp); err != nil {
+		return fmt.Errorf("notifiers.telegram decode error: %w (status %d)", err, resp.StatusCode)
+	}
+	if telegramResp.OK {
+		return nil
+	}
+	return telegramError(&telegramResp)
+}
+
+func parseTelegramContent(message string) (*TelegramContent, error) {
+	if !isJSONObject(message) {
+		return &TelegramContent{Text: message}, nil
+	}
+	var content TelegramContent
+	if err := json.Unmarshal([]byte(message), &content); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidContent, err)
+	}
+	if content.Text == "" {
+		return nil, fmt.Errorf("%w: text is required", ErrInvalidContent)
+	}
+	switch content.ParseMode {
+	case "", TelegramParseModeMarkdown, TelegramParseModeMarkdownV2, TelegramParseModeHTML:
+	default:
+		return nil, fmt.Errorf("%w: unknown parse mode %q", ErrInvalidContent, content.ParseMode)
+	}
+	return &content, nil
+}
+
+func telegramError(resp *telegramResponse) error {
+	err := fmt.Errorf("notifiers.telegram error %d: %s", resp.ErrorCode, resp.Description)
+	description := strings.ToLower(resp.Description)
+	switch {
+	case resp.ErrorCode == http.StatusTooManyRequests:
+		return &RetryAfterError{
+			RetryAfter: time.Duration(resp.Parameters.RetryAfter) * time.Second,
+			Err:        err,
+		}
+	case resp.ErrorCode == http.StatusForbidden,
+		strings.Contains(description, "chat not found"),
+		strings.Contains(description, "user is deactivated"):
+		// the bot was blocked, kicked or the user deleted the account
+		return &PermanentError{Err: fmt.Errorf("%w: %w", ErrRecipientUnreachable, err)}
+	case resp.ErrorCode >= 400 && resp.ErrorCode < 500:
+		return &PermanentError{Err: err}
+	}
+	return err
+}
@@ -0,0 +1,100 @@
+package notifiers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"notification_system/internal/entities"
+)
+
+func TestTelegramNotifier_Notify(t *testing.T) {
+	tests := []struct {
+		name            string
+		message         string
+		response        string
+		wantErr         bool
+		wantPermanent   bool
+		wantUnreachable bool
+		wantRetryAfter  time.Duration
+	}{
+		{
+			"plain text",
+			"hello",
+			`{"ok":true}`,
+			false, false, false, 0,
+		},
+		{
+			"structured content",
+			`{"text":"<b>hi</b>","parse_mode":"HTML","inline_keyboard":[[{"text":"open","url":"https://example.com"}]]}`,
+			`{"ok":true}`,
+			false, false, false, 0,
+		},
+		{
+			"unknown parse mode",
+			`{"text":"hi","parse_mode":"RST"}`,
+			`{"ok":true}`,
+			true, true, false, 0,
+		},
+		{
+			"rate limited",
+			"hello",
+			`{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 7","parameters":{"retry_after":7}}`,
+			true, false, false, 7 * time.Second,
+		},
+		{
+			"bot blocked",
+			"hello",
+			`{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`,
+			true, true, true, 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got telegramSendMessageRequest
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/bottoken/sendMessage" {
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+				_ = json.NewDecoder(r.Body).Decode(&got)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			notifier := &TelegramNotifier{BotToken: "token", APIURL: server.URL}
+			err := notifier.Notify(context.Background(), &entities.Notification{Recipient: "42", Content: tt.message})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Notify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if IsPermanent(err) != tt.wantPermanent {
+				t.Errorf("Notify() permanent = %v, want %v", IsPermanent(err), tt.wantPermanent)
+			}
+			if errors.Is(err, ErrRecipientUnreachable) != tt.wantUnreachable {
+				t.Errorf("Notify() unreachable = %v, want %v", !tt.wantUnreachable, tt.wantUnreachable)
+			}
+			var retryAfterErr *RetryAfterError
+			if errors.As(err, &retryAfterErr) && retryAfterErr.RetryAfter != tt.wantRetryAfter {
+				t.Errorf("Notify() retry after = %s, want %s", retryAfterErr.RetryAfter, tt.wantRetryAfter)
+			}
+			if err == nil && got.ChatID != "42" {
+				t.Errorf("Notify() chat id = %q, want %q", got.ChatID, "42")
+			}
+		})
+	}
+}
+
+func TestTelegramNotifier_Notify_HidesToken(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	notifier := &TelegramNotifier{BotToken: "123:secret-token", APIURL: server.URL}
+
+	err := notifier.Notify(context.Background(), &entities.Notification{Recipient: "42", Content: "hello"})
+	if err == nil || strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("Notify() error = %v, want a transport error without the bot token", err)
+	}
+}
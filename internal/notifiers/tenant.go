@@ -0,0 +1,53 @@
+package notifiers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/entities"
+)
+
+// TenantCache keeps the tenants read from the store for a while, so the sender settings
+// are not read for every email. A changed tenant is picked up once its entry expires.
+type TenantCache struct {
+	store TenantStore
+	ttl   time.Duration
+
+	mu      sync.Mutex
+	tenants map[uuid.UUID]cachedTenant
+	now     func() time.Time
+}
+
+type cachedTenant struct {
+	tenant    *entities.Tenant
+	expiresAt time.Time
+}
+
+func NewTenantCache(store TenantStore, ttl time.Duration) *TenantCache {
+	return &TenantCache{
+		store:   store,
+		ttl:     ttl,
+		tenants: make(map[uuid.UUID]cachedTenant),
+		now:     time.Now,
+	}
+}
+
+func (c *TenantCache) GetTenant(ctx context.Context, id uuid.UUID) (*entities.Tenant, error) {
+	c.mu.Lock()
+	cached, ok := c.tenants[id]
+	c.mu.Unlock()
+	if ok && c.now().Before(cached.expiresAt) {
+		return cached.tenant, nil
+	}
+	tenant, err := c.store.GetTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.tenants[id] = cachedTenant{tenant: tenant, expiresAt: c.now().Add(c.ttl)}
+	c.mu.Unlock()
+	return tenant, nil
+}
@@ -0,0 +1,89 @@
+package notifiers
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"notification_system/internal/entities"
+)
+
+const WebhookSignatureHeader = "X-Webhook-Signature"
+
+type WebhookNotifier struct {
+	Method             string
+	Headers            map[string]string
+	SigningSecret      string
+	SuccessStatusCodes StatusCodes
+	Client             *http.Client
+}
+
+// Notify sends the content as a JSON body to the recipient URL.
+func (notifier *WebhookNotifier) Notify(ctx context.Context, notification *entities.Notification) error {
+	to, message := notification.Recipient, notification.Content
+	endpoint, err := url.Parse(to)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return &PermanentError{Err: fmt.Errorf("notifiers.webhook error: %w: invalid URL", ErrRecipientUnreachable)}
+	}
+	body := []byte(message)
+	if !json.Valid(body) {
+		return &PermanentError{Err: fmt.Errorf("notifiers.webhook error: %w: content is not valid JSON", ErrInvalidContent)}
+	}
+
+	method := notifier.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return &PermanentError{Err: fmt.Errorf("notifiers.webhook request error: %w", withoutURL(err))}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range notifier.Headers {
+		req.Header.Set(key, value)
+	}
+	if notifier.SigningSecret != "" {
+		req.Header.Set(WebhookSignatureHeader, SignWebhook(notifier.SigningSecret, time.Now(), body))
+	}
+
+	client := notifier.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		err = withoutURL(err)
+		if errors.Is(err, ErrAddressNotAllowed) {
+			return &PermanentError{Err: fmt.Errorf("notifiers.webhook error: %w: %w", ErrRecipientUnreachable, err)}
+		}
+		return fmt.Errorf("notifiers.webhook request error: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+
+	if notifier.SuccessStatusCodes.Contains(resp.StatusCode) {
+		return nil
+	}
+	return httpStatusError("notifiers.webhook", resp, respBody)
+}
+
+// SignWebhook returns the signature header value "t=<unix>,v1=<hex>" where v1 is
+// HMAC-SHA256 of "<unix>.<body>" so receivers can reject replayed requests.
+func SignWebhook(secret string, timestamp time.Time, body []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return fmt.Sprintf("t=%s,v1=%s", unix, hex.EncodeToString(mac.Sum(nil)))
+}
@@ -0,0 +1,84 @@
+package notifiers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"notification_system/internal/entities"
+)
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	const secret = "secret"
+	tests := []struct {
+		name            string
+		message         string
+		status          int
+		successCodes    string
+		wantErr         bool
+		wantPermanent   bool
+		wantUnreachable bool
+	}{
+		{"delivered", `{"event":"created"}`, http.StatusOK, "", false, false, false},
+		{"custom success code", `{"event":"created"}`, http.StatusAccepted, "202", false, false, false},
+		{"unexpected success code", `{"event":"created"}`, http.StatusOK, "202", true, false, false},
+		{"invalid json", `event`, http.StatusOK, "", true, true, false},
+		{"server error", `{"event":"created"}`, http.StatusBadGateway, "", true, false, false},
+		{"gone", `{"event":"created"}`, http.StatusGone, "", true, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				signature := r.Header.Get(WebhookSignatureHeader)
+				unix, err := strconv.ParseInt(strings.TrimPrefix(strings.Split(signature, ",")[0], "t="), 10, 64)
+				if err != nil || signature != SignWebhook(secret, time.Unix(unix, 0), body) {
+					t.Errorf("unexpected signature %q", signature)
+				}
+				if r.Header.Get("X-Team") != "ops" {
+					t.Errorf("missing configured header")
+				}
+				if string(body) != tt.message {
+					t.Errorf("body = %q, want %q", body, tt.message)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			successCodes, err := ParseStatusCodes(tt.successCodes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			notifier := &WebhookNotifier{
+				Headers:            map[string]string{"X-Team": "ops"},
+				SigningSecret:      secret,
+				SuccessStatusCodes: successCodes,
+			}
+			err = notifier.Notify(context.Background(), &entities.Notification{Recipient: server.URL, Content: tt.message})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Notify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if IsPermanent(err) != tt.wantPermanent {
+				t.Errorf("Notify() permanent = %v, want %v", IsPermanent(err), tt.wantPermanent)
+			}
+			if errors.Is(err, ErrRecipientUnreachable) != tt.wantUnreachable {
+				t.Errorf("Notify() unreachable = %v, want %v", !tt.wantUnreachable, tt.wantUnreachable)
+			}
+		})
+	}
+}
+
+func TestSignWebhook(t *testing.T) {
+	// echo -n '1700000000.{"a":1}' | openssl dgst -sha256 -hmac secret
+	want := "t=1700000000,v1=49f24e537407743fa4a0242bb63b94b9a47ee99cbbe071ccd8a22550ae411686"
+	got := SignWebhook("secret", time.Unix(1700000000, 0), []byte(`{"a":1}`))
+	if got != want {
+		t.Errorf("SignWebhook() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,297 @@
+package notifiers
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/entities"
+	"notification_system/pkg/jwt"
+)
+
+const (
+	// a single aes128gcm record: 4096 bytes minus the header, the padding delimiter and the tag
+	webPushRecordSize     = 4096
+	maxWebPushPayloadSize = webPushRecordSize - 86 - 1 - 16
+	vapidTokenLifetime    = 12 * time.Hour
+)
+
+var ErrNoWebPushSubscriptions = errors.New("user has no web push subscriptions")
+
+type WebPushSubscriptionStore interface {
+	GetWebPushSubscriptionsByUserID(ctx context.Context, tenantID *uuid.UUID, userID string) ([]*entities.WebPushSubscription, error)
+	DeleteWebPushSubscriptionByEndpoint(ctx context.Context, tenantID *uuid.UUID, endpoint string) error
+}
+
+// WebPushNotifier delivers the content to every browser subscription of the recipient user.
+type WebPushNotifier struct {
+	Subscriptions WebPushSubscriptionStore
+	VAPIDKey      *ecdsa.PrivateKey
+	VAPIDSubject  string
+	Client        *http.Client
+}
+
+func (notifier *WebPushNotifier) Notify(ctx context.Context, notification *entities.Notification) error {
+	if notifier.VAPIDKey == nil {
+		return &PermanentError{Err: fmt.Errorf("notifiers.webpush error: %w", ErrProviderNotConfigured)}
+	}
+	if len(notification.Content) > maxWebPushPayloadSize {
+		return &PermanentError{Err: fmt.Errorf("notifiers.webpush error: %w: payload exceeds %d bytes", ErrInvalidContent, maxWebPushPayloadSize)}
+	}
+	subscriptions, err := notifier.Subscriptions.GetWebPushSubscriptionsByUserID(ctx, notification.TenantID, notification.Recipient)
+	if err != nil {
+		return fmt.Errorf("notifiers.webpush error: %w", err)
+	}
+	if len(subscriptions) == 0 {
+		return &PermanentError{Err: fmt.Errorf("notifiers.webpush error: %w", ErrNoWebPushSubscriptions)}
+	}
+
+	// the notification counts as delivered when at least one browser accepted it
+	var errs []error
+	delivered := false
+	for _, subscription := range subscriptions {
+		err := notifier.send(ctx, subscription, notification)
+		if err == nil {
+			delivered = true
+			continue
+		}
+		if errors.Is(err, ErrRecipientUnreachable) {
+			if err := notifier.Subscriptions.DeleteWebPushSubscriptionByEndpoint(ctx, notification.TenantID, subscription.Endpoint); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		errs = append(errs, err)
+	}
+	if delivered {
+		return nil
+	}
+	if len(errs) == 0 {
+		return &PermanentError{Err: fmt.Errorf("notifiers.webpush error: %w: all subscriptions expired", ErrNoWebPushSubscriptions)}
+	}
+	return errors.Join(errs...)
+}
+
+func (notifier *WebPushNotifier) send(ctx context.Context, subscription *entities.WebPushSubscription, notification *entities.Notification) error {
+	uaPublic, err := decodeBase64(subscription.P256dh)
+	if err != nil {
+		return &PermanentError{Err: fmt.Errorf("%w: invalid p256dh key", ErrRecipientUnreachable)}
+	}
+	authSecret, err := decodeBase64(subscription.Auth)
+	if err != nil {
+		return &PermanentError{Err: fmt.Errorf("%w: invalid auth secret", ErrRecipientUnreachable)}
+	}
+	body, err := EncryptWebPushPayload(uaPublic, authSecret, []byte(notification.Content))
+	if err != nil {
+		return &PermanentError{Err: fmt.Errorf("%w: %w", ErrRecipientUnreachable, err)}
+	}
+	authorization, err := notifier.vapidAuthorization(subscription.Endpoint)
+	if err != nil {
+		return &PermanentError{Err: err}
+	}
+
+	urgency, ttl := webPushUrgency(notification.Priority)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return &PermanentError{Err: fmt.Errorf("%w: %w", ErrRecipientUnreachable, withoutURL(err))}
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("TTL", strconv.Itoa(int(ttl.Seconds())))
+	req.Header.Set("Urgency", urgency)
+
+	client := notifier.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("notifiers.webpush request error: %w", withoutURL(err))
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		return &PermanentError{Err: fmt.Errorf("%w: notifiers.webpush subscription expired", ErrRecipientUnreachable)}
+	}
+	return httpStatusError("notifiers.webpush", resp, respBody)
+}
+
+// webPushUrgency maps the notification priority onto the Urgency header (RFC 8030)
+// and a TTL after which a message that could not reach the browser is dropped.
+func webPushUrgency(priority string) (string, time.Duration) {
+	switch priority {
+	case entities.PriorityLow:
+		return "low", 7 * 24 * time.Hour
+	case entities.PriorityHigh:
+		return "high", time.Hour
+	case entities.PriorityCritical:
+		return "high", 15 * time.Minute
+	}
+	return "normal", 24 * time.Hour
+}
+
+func (notifier *WebPushNotifier) vapidAuthorization(endpoint string) (string, error) {
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	token, err := jwt.Sign(notifier.VAPIDKey, "", map[string]any{
+		"aud": endpointURL.Scheme + "://" + endpointURL.Host,
+		"exp": time.Now().Add(vapidTokenLifetime).Unix(),
+		"sub": notifier.VAPIDSubject,
+	})
+	if err != nil {
+		return "", fmt.Errorf("notifiers.webpush sign vapid token error: %w", err)
+	}
+	publicKey, err := VAPIDPublicKey(notifier.VAPIDKey)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("vapid t=%s, k=%s", token, publicKey), nil
+}
+
+// EncryptWebPushPayload encrypts the payload for a subscription as described in RFC 8291.
+func EncryptWebPushPayload(uaPublic, authSecret, plaintext []byte) ([]byte, error) {
+	asPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return encryptWebPushPayload(uaPublic, authSecret, plaintext, asPrivate, salt)
+}
+
+func encryptWebPushPayload(uaPublic, authSecret, plaintext []byte, asPrivate *ecdh.PrivateKey, salt []byte) ([]byte, error) {
+	uaKey, err := ecdh.P256().NewPublicKey(uaPublic)
+	if err != nil {
+		return nil, fmt.Errorf("invalid p256dh key: %w", err)
+	}
+	ecdhSecret, err := asPrivate.ECDH(uaKey)
+	if err != nil {
+		return nil, err
+	}
+	asPublic := asPrivate.PublicKey().Bytes()
+
+	keyInfo := append([]byte("WebPush: info\x00"), uaPublic...)
+	keyInfo = append(keyInfo, asPublic...)
+	prkKey := hmacSHA256(authSecret, ecdhSecret)
+	ikm := hmacSHA256(prkKey, append(keyInfo, 0x01))
+
+	prk, err := hkdf.Extract(sha256.New, ikm, salt)
+	if err != nil {
+		return nil, err
+	}
+	cek, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	// 0x02 marks the last and only record
+	record := append(append([]byte{}, plaintext...), 0x02)
+
+	header := make([]byte, 0, 16+4+1+len(asPublic))
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, webPushRecordSize)
+	header = append(header, byte(len(asPublic)))
+	header = append(header, asPublic...)
+	return gcm.Seal(header, nonce, record, nil), nil
+}
+
+// ValidateWebPushKeys checks the keys of a browser PushSubscription.
+func ValidateWebPushKeys(p256dh, auth string) error {
+	uaPublic, err := decodeBase64(p256dh)
+	if err != nil {
+		return err
+	}
+	if _, err := ecdh.P256().NewPublicKey(uaPublic); err != nil {
+		return err
+	}
+	authSecret, err := decodeBase64(auth)
+	if err != nil {
+		return err
+	}
+	if len(authSecret) != 16 {
+		return errors.New("auth secret must be 16 bytes")
+	}
+	return nil
+}
+
+// ParseVAPIDPrivateKey parses the base64url encoded raw P-256 private key
+// used by the web-push tooling.
+func ParseVAPIDPrivateKey(s string) (*ecdsa.PrivateKey, error) {
+	d, err := decodeBase64(s)
+	if err != nil {
+		return nil, fmt.Errorf("notifiers.ParseVAPIDPrivateKey error: %w", err)
+	}
+	if _, err := ecdh.P256().NewPrivateKey(d); err != nil {
+		return nil, fmt.Errorf("notifiers.ParseVAPIDPrivateKey error: %w", err)
+	}
+	key := &ecdsa.PrivateKey{D: new(big.Int).SetBytes(d)}
+	key.Curve = elliptic.P256()
+	key.X, key.Y = key.Curve.ScalarBaseMult(d)
+	return key, nil
+}
+
+// VAPIDPublicKey returns the base64url encoded uncompressed public key
+// browsers expect as the applicationServerKey.
+func VAPIDPublicKey(key *ecdsa.PrivateKey) (string, error) {
+	publicKey, err := key.PublicKey.ECDH()
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(publicKey.Bytes()), nil
+}
+
+func hmacSHA256(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// decodeBase64 accepts both the base64url form used by browsers and standard base64.
+func decodeBase64(s string) ([]byte, error) {
+	s = strings.TrimRight(s, "=")
+	if data, err := base64.RawURLEncoding.DecodeString(s); err == nil {
+		return data, nil
+	}
+	return base64.RawStdEncoding.DecodeString(s)
+}
@@ -0,0 +1,100 @@
+package notifiers
+
+import (
+	"context"
+	"crypto/ecdh"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/entities"
+)
+
+func TestEncryptWebPushPayload(t *testing.T) {
+	// example from RFC 8291, section 5
+	decode := func(s string) []byte {
+		data, err := base64.RawURLEncoding.DecodeString(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	asPrivate, err := ecdh.P256().NewPrivateKey(decode("yfWPiYE-n46HLnH0KqZOF1fJJU3MYrct3AELtAQ-oRw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := encryptWebPushPayload(
+		decode("BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4"),
+		decode("BTBZMqHH6r4Tts7J_aSIgg"),
+		[]byte("When I grow up, I want to be a watermelon"),
+		asPrivate,
+		decode("DGv6ra1nlYgDCS1FRnbzlw"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A_yl95bQpu6cVPTpK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN"
+	if base64.RawURLEncoding.EncodeToString(got) != want {
+		t.Errorf("encryptWebPushPayload() = %s, want %s", base64.RawURLEncoding.EncodeToString(got), want)
+	}
+}
+
+type fakeWebPushSubscriptionStore struct {
+	subscriptions []*entities.WebPushSubscription
+	deleted       []string
+}
+
+func (s *fakeWebPushSubscriptionStore) GetWebPushSubscriptionsByUserID(ctx context.Context, tenantID *uuid.UUID, userID string) ([]*entities.WebPushSubscription, error) {
+	return s.subscriptions, nil
+}
+
+func (s *fakeWebPushSubscriptionStore) DeleteWebPushSubscriptionByEndpoint(ctx context.Context, tenantID *uuid.UUID, endpoint string) error {
+	s.deleted = append(s.deleted, endpoint)
+	return nil
+}
+
+func TestWebPushNotifier_Notify(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Urgency") != "high" || r.Header.Get("TTL") != "900" {
+			t.Errorf("unexpected headers %v", r.Header)
+		}
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "vapid t=") {
+			t.Errorf("unexpected authorization %q", r.Header.Get("Authorization"))
+		}
+		if r.URL.Path == "/gone" {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	vapidKey, err := ParseVAPIDPrivateKey("yfWPiYE-n46HLnH0KqZOF1fJJU3MYrct3AELtAQ-oRw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p256dh := "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4"
+	store := &fakeWebPushSubscriptionStore{
+		subscriptions: []*entities.WebPushSubscription{
+			{Endpoint: server.URL + "/gone", P256dh: p256dh, Auth: "BTBZMqHH6r4Tts7J_aSIgg"},
+			{Endpoint: server.URL + "/active", P256dh: p256dh, Auth: "BTBZMqHH6r4Tts7J_aSIgg"},
+		},
+	}
+	notifier := &WebPushNotifier{
+		Subscriptions: store,
+		VAPIDKey:      vapidKey,
+		VAPIDSubject:  "mailto:ops@example.com",
+		Client:        server.Client(),
+	}
+	notification := &entities.Notification{Recipient: "user", Content: `{"title":"hi"}`, Priority: entities.PriorityCritical}
+	if err := notifier.Notify(context.Background(), notification); err != nil {
+		t.Errorf("Notify() error = %v", err)
+	}
+	if len(store.deleted) != 1 || store.deleted[0] != server.URL+"/gone" {
+		t.Errorf("pruned subscriptions = %v, want the gone endpoint", store.deleted)
+	}
+}
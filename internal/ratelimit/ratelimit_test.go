@@ -0,0 +1,51 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewLimiter(Limit{Rate: 2, Burst: 3})
+	limiter.now = func() time.Time { return now }
+
+	for i := range 3 {
+		result := limiter.Allow("a")
+		if !result.Allowed || result.Remaining != 2-i || result.Limit != 3 {
+			t.Fatalf("request %d = %+v, want allowed with %d remaining", i, result, 2-i)
+		}
+	}
+	result := limiter.Allow("a")
+	if result.Allowed || result.RetryAfter != 500*time.Millisecond || result.Reset != 1500*time.Millisecond {
+		t.Errorf("request over the burst = %+v, want rejected, retry after 500ms, reset in 1.5s", result)
+	}
+	if result := limiter.Allow("b"); !result.Allowed {
+		t.Errorf("another key = %+v, want its own bucket", result)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if result := limiter.Allow("a"); !result.Allowed || result.Remaining != 0 {
+		t.Errorf("request after a token refilled = %+v, want allowed", result)
+	}
+	now = now.Add(time.Hour)
+	if result := limiter.Allow("a"); !result.Allowed || result.Remaining != 2 {
+		t.Errorf("request after a long pause = %+v, want a full bucket", result)
+	}
+}
+
+func TestLimiter_Sweep(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewLimiter(Limit{Rate: 1, Burst: 1})
+	limiter.now = func() time.Time { return now }
+
+	limiter.Allow("idle")
+	now = now.Add(sweepInterval)
+	limiter.Allow("busy")
+	if _, ok := limiter.buckets["idle"]; ok {
+		t.Error("refilled bucket was not dropped")
+	}
+	if _, ok := limiter.buckets["busy"]; !ok {
+		t.Error("bucket in use was dropped")
+	}
+}
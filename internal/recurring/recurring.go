@@ -0,0 +1,133 @@
+// Package recurring computes the occurrences of recurring notifications and
+// renders the notifications materialized for them.
+package recurring
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"notification_system/internal/entities"
+	"notification_system/pkg/cron"
+)
+
+// Data is what the template of a recurring notification is executed with.
+type Data struct {
+	Name      string
+	Recipient string
+	UserID    string
+	// Occurrence is the scheduled time in the time zone of the schedule,
+	// Sequence counts the occurrences starting at 1
+	Occurrence time.Time
+	Sequence   int
+	Variables  map[string]any
+}
+
+// Schedule parses the schedule of the recurring notification, read in its time zone.
+func Schedule(recurring *entities.RecurringNotification) (cron.Schedule, *time.Location, error) {
+	loc, err := time.LoadLocation(recurring.TimeZone)
+	if err != nil {
+		return nil, nil, fmt.Errorf("recurring.Schedule error: %w", err)
+	}
+	switch recurring.ScheduleType {
+	case entities.ScheduleTypeCron:
+		expression, err := cron.Parse(recurring.Schedule)
+		if err != nil {
+			return nil, nil, fmt.Errorf("recurring.Schedule error: %w", err)
+		}
+		return expression, loc, nil
+	case entities.ScheduleTypeRRule:
+		rule, err := cron.ParseRule(recurring.Schedule, recurring.StartsAt.In(loc))
+		if err != nil {
+			return nil, nil, fmt.Errorf("recurring.Schedule error: %w", err)
+		}
+		return rule, loc, nil
+	}
+	return nil, nil, fmt.Errorf("recurring.Schedule error: unknown schedule type %q", recurring.ScheduleType)
+}
+
+// NextRun returns the first occurrence after the given time, not before StartsAt, in UTC.
+// It is nil when the schedule is exhausted or the occurrence is past EndsAt.
+func NextRun(recurring *entities.RecurringNotification, after time.Time) (*time.Time, error) {
+	schedule, loc, err := Schedule(recurring)
+	if err != nil {
+		return nil, err
+	}
+	// the start itself is an occurrence
+	if start := recurring.StartsAt.Add(-time.Nanosecond); after.Before(start) {
+		after = start
+	}
+	next := schedule.Next(after.In(loc))
+	if next.IsZero() || (recurring.EndsAt != nil && next.After(*recurring.EndsAt)) {
+		return nil, nil
+	}
+	next = next.UTC()
+	return &next, nil
+}
+
+// Parse checks the template text.
+func Parse(text string) (*template.Template, error) {
+	tmpl, err := template.New("recurring").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("recurring.Parse error: %w", err)
+	}
+	return tmpl, nil
+}
+
+// Notifications renders one notification per recipient and user for the occurrence.
+func Notifications(recurring *entities.RecurringNotification, occurrence time.Time) ([]*entities.Notification, error) {
+	tmpl, err := Parse(recurring.Template)
+	if err != nil {
+		return nil, err
+	}
+	if loc, err := time.LoadLocation(recurring.TimeZone); err == nil {
+		occurrence = occurrence.In(loc)
+	}
+	data := Data{
+		Name:       recurring.Name,
+		Occurrence: occurrence,
+		Sequence:   int(recurring.Occurrences) + 1,
+		Variables:  recurring.Variables,
+	}
+	notifications := make([]*entities.Notification, 0, len(recurring.Recipients)+len(recurring.UserIDs))
+	for _, recipient := range recurring.Recipients {
+		data.Recipient, data.UserID = recipient, ""
+		content, err := execute(tmpl, data)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, occurrenceNotification(recurring, recipient, nil, content))
+	}
+	for _, userID := range recurring.UserIDs {
+		data.Recipient, data.UserID = "", userID
+		content, err := execute(tmpl, data)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, occurrenceNotification(recurring, "", &userID, content))
+	}
+	return notifications, nil
+}
+
+func execute(tmpl *template.Template, data Data) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("recurring.Notifications render error: %w", err)
+	}
+	return b.String(), nil
+}
+
+func occurrenceNotification(recurring *entities.RecurringNotification, recipient string, userID *string, content string) *entities.Notification {
+	return &entities.Notification{
+		DeliveryType: recurring.DeliveryType,
+		Recipient:    recipient,
+		Content:      content,
+		Priority:     recurring.Priority,
+		UserID:       userID,
+		Category:     recurring.Category,
+		RecurringID:  &recurring.ID,
+		ClientID:     recurring.ClientID,
+		TenantID:     recurring.TenantID,
+	}
+}
@@ -0,0 +1,82 @@
+package recurring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/entities"
+)
+
+func TestNextRun(t *testing.T) {
+	endsAt := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	recurring := &entities.RecurringNotification{
+		ScheduleType: entities.ScheduleTypeCron,
+		Schedule:     "0 9 * * *",
+		TimeZone:     "America/New_York",
+		StartsAt:     time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC),
+		EndsAt:       &endsAt,
+	}
+
+	// before the start the first occurrence is on the start day
+	next, err := NextRun(recurring, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("NextRun() error = %v", err)
+	}
+	if want := time.Date(2026, 10, 20, 13, 0, 0, 0, time.UTC); next == nil || !next.Equal(want) {
+		t.Errorf("NextRun() = %v, want %v", next, want)
+	}
+	if next, _ := NextRun(recurring, endsAt); next != nil {
+		t.Errorf("NextRun() after the end = %v, want nil", next)
+	}
+
+	recurring.ScheduleType, recurring.Schedule, recurring.EndsAt = entities.ScheduleTypeRRule, "FREQ=WEEKLY;COUNT=2", nil
+	next, _ = NextRun(recurring, recurring.StartsAt)
+	// DTSTART is midnight UTC, the previous evening in New York
+	if want := time.Date(2026, 10, 27, 0, 0, 0, 0, time.UTC); next == nil || !next.Equal(want) {
+		t.Errorf("NextRun() = %v, want %v", next, want)
+	}
+	if next, _ := NextRun(recurring, *next); next != nil {
+		t.Errorf("NextRun() after the last occurrence = %v, want nil", next)
+	}
+}
+
+func TestNotifications(t *testing.T) {
+	category := "reports"
+	recurring := &entities.RecurringNotification{
+		ID:           uuid.New(),
+		Name:         "weekly report",
+		DeliveryType: entities.DeliveryTypeEmail,
+		TimeZone:     "Europe/Berlin",
+		Recipients:   []string{"a@example.com"},
+		UserIDs:      []string{"user-1"},
+		Template:     `{{.Name}} #{{.Sequence}} for {{if .UserID}}{{.UserID}}{{else}}{{.Recipient}}{{end}} at {{.Occurrence.Format "15:04"}}: {{.Variables.url}}`,
+		Variables:    map[string]any{"url": "https://example.com/r"},
+		Priority:     entities.PriorityLow,
+		Category:     &category,
+		Occurrences:  2,
+	}
+	notifications, err := Notifications(recurring, time.Date(2026, 10, 19, 7, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Notifications() error = %v", err)
+	}
+	if len(notifications) != 2 {
+		t.Fatalf("Notifications() = %d notifications, want 2", len(notifications))
+	}
+	if want := "weekly report #3 for a@example.com at 09:00: https://example.com/r"; notifications[0].Content != want {
+		t.Errorf("content = %q, want %q", notifications[0].Content, want)
+	}
+	user := notifications[1]
+	if user.UserID == nil || *user.UserID != "user-1" || user.Recipient != "" || user.Content != "weekly report #3 for user-1 at 09:00: https://example.com/r" {
+		t.Errorf("unexpected user notification %+v", user)
+	}
+	if *user.RecurringID != recurring.ID || user.Priority != entities.PriorityLow || user.Category != &category {
+		t.Errorf("unexpected user notification %+v", user)
+	}
+
+	recurring.Template = "{{.Variables.missing}}"
+	if _, err := Notifications(recurring, time.Now()); err == nil {
+		t.Error("Notifications() with a missing variable succeeded")
+	}
+}
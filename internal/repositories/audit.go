@@ -0,0 +1,181 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"notification_system/config"
+	"notification_system/internal/entities"
+	"notification_system/pkg/database"
+)
+
+const auditColumns = `id, occurred_at, request_id, coalesce(actor_method, ''), coalesce(actor_subject, ''),
+	client_id, tenant_id, ip, method, path, status_code, action, resource_type, resource_id, before, after, diff`
+
+type AuditPostgresRepository struct {
+	db *database.PostgresDatabase
+}
+
+func NewAuditPostgresRepository(db *database.PostgresDatabase) AuditRepository {
+	return &AuditPostgresRepository{db: db}
+}
+
+// CreateAuditEntry appends the entry, the log cannot be changed afterwards.
+func (r *AuditPostgresRepository) CreateAuditEntry(ctx context.Context, entry *entities.AuditEntry) error {
+	query := fmt.Sprintf(`
+		insert into audit_log (request_id, actor_method, actor_subject, client_id, tenant_id, ip, method,
+			path, status_code, action, resource_type, resource_id, before, after, diff)
+		values ($1, nullif($2, ''), nullif($3, ''), $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		returning %s
+	`, auditColumns)
+	row := r.db.Pool.QueryRow(ctx, query,
+		entry.RequestID,
+		entry.ActorMethod,
+		entry.ActorSubject,
+		entry.ClientID,
+		entry.TenantID,
+		entry.IP,
+		entry.Method,
+		entry.Path,
+		entry.StatusCode,
+		entry.Action,
+		entry.ResourceType,
+		entry.ResourceID,
+		entry.Before,
+		entry.After,
+		entry.Diff,
+	)
+	if err := scanAuditEntry(row, entry); err != nil {
+		return fmt.Errorf("AuditPostgresRepository.CreateAuditEntry error: %w", err)
+	}
+	return nil
+}
+
+// SearchAuditEntries returns a page of the entries matching the filter, newest first.
+func (r *AuditPostgresRepository) SearchAuditEntries(ctx context.Context, filter *entities.AuditFilter) ([]*entities.AuditEntry, error) {
+	if filter.Limit > config.Cfg.MaxBatchSize {
+		return nil, ErrMaxBatchSizeExceeded
+	}
+	conditions, args := auditConditions(filter)
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`
+		select %s
+		from audit_log
+		where %s
+		order by occurred_at desc, id
+		limit $%d offset $%d
+	`, auditColumns, conditions, len(args)-1, len(args))
+
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("AuditPostgresRepository.SearchAuditEntries query error: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]*entities.AuditEntry, 0)
+	for rows.Next() {
+		entry := &entities.AuditEntry{}
+		if err := scanAuditEntry(rows, entry); err != nil {
+			return nil, fmt.Errorf("AuditPostgresRepository.SearchAuditEntries scan error: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("AuditPostgresRepository.SearchAuditEntries rows error: %w", err)
+	}
+	return entries, nil
+}
+
+// ExportAuditEntries passes every entry matching the filter to fn, oldest first, without
+// holding them in memory. Limit and Offset of the filter are ignored.
+func (r *AuditPostgresRepository) ExportAuditEntries(ctx context.Context, filter *entities.AuditFilter, fn func(*entities.AuditEntry) error) error {
+	conditions, args := auditConditions(filter)
+	query := fmt.Sprintf(`
+		select %s
+		from audit_log
+		where %s
+		order by occurred_at, id
+	`, auditColumns, conditions)
+
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("AuditPostgresRepository.ExportAuditEntries query error: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry := &entities.AuditEntry{}
+		if err := scanAuditEntry(rows, entry); err != nil {
+			return fmt.Errorf("AuditPostgresRepository.ExportAuditEntries scan error: %w", err)
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("AuditPostgresRepository.ExportAuditEntries rows error: %w", err)
+	}
+	return nil
+}
+
+func auditConditions(filter *entities.AuditFilter) (string, []any) {
+	conditions := []string{"true"}
+	args := []any{}
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.TenantID != nil {
+		add("tenant_id = $%d", *filter.TenantID)
+	}
+	if filter.ClientID != nil {
+		add("client_id = $%d", *filter.ClientID)
+	}
+	if filter.ActorSubject != "" {
+		add("actor_subject = $%d", filter.ActorSubject)
+	}
+	if filter.Action != "" {
+		add("action = $%d", filter.Action)
+	}
+	if filter.ResourceType != "" {
+		add("resource_type = $%d", filter.ResourceType)
+	}
+	if filter.ResourceID != "" {
+		add("resource_id = $%d", filter.ResourceID)
+	}
+	if filter.RequestID != "" {
+		add("request_id = $%d", filter.RequestID)
+	}
+	if filter.From != nil {
+		add("occurred_at >= $%d", filter.From.UTC())
+	}
+	if filter.To != nil {
+		add("occurred_at < $%d", filter.To.UTC())
+	}
+	return strings.Join(conditions, " and "), args
+}
+
+func scanAuditEntry(row pgx.Row, entry *entities.AuditEntry) error {
+	return row.Scan(
+		&entry.ID,
+		&entry.OccurredAt,
+		&entry.RequestID,
+		&entry.ActorMethod,
+		&entry.ActorSubject,
+		&entry.ClientID,
+		&entry.TenantID,
+		&entry.IP,
+		&entry.Method,
+		&entry.Path,
+		&entry.StatusCode,
+		&entry.Action,
+		&entry.ResourceType,
+		&entry.ResourceID,
+		&entry.Before,
+		&entry.After,
+		&entry.Diff,
+	)
+}
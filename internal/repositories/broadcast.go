@@ -0,0 +1,320 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"notification_system/internal/entities"
+	"notification_system/pkg/database"
+	"notification_system/pkg/envelope"
+)
+
+const broadcastColumns = `id, topic, segment, delivery_type, content, priority, category, status,
+	total, processed, cursor, created_at, updated_at, started_at, completed_at, client_id, tenant_id`
+
+// broadcastUsersQuery selects the user IDs of a broadcast after the cursor. The parameters are
+// $1 topic, $2 delivery type, $3 segment time zones, $4 cursor and $5 tenant key; the guards on
+// $1 pick the subscribers of the topic or the contacts with a verified address on the channel,
+// both of the tenant of the broadcast.
+const broadcastUsersQuery = `
+	select s.user_id
+	from topic_subscriptions s
+	where $1::text is not null and s.tenant_key = $5 and s.topic = $1
+		and ($4::text is null or s.user_id > $4)
+	union all
+	select c.user_id
+	from contacts c
+	where $1::text is null and c.tenant_key = $5
+		and (cardinality($3::text[]) = 0 or c.time_zone = any($3::text[]))
+		and ($4::text is null or c.user_id > $4)
+		and exists (
+			select 1
+			from contact_addresses a
+			where a.tenant_key = c.tenant_key and a.user_id = c.user_id
+				and a.delivery_type = $2 and a.verified_at is not null
+		)`
+
+type BroadcastPostgresRepository struct {
+	db     *database.PostgresDatabase
+	cipher *envelope.Cipher
+}
+
+func NewBroadcastPostgresRepository(db *database.PostgresDatabase, cipher *envelope.Cipher) BroadcastRepository {
+	return &BroadcastPostgresRepository{db: db, cipher: cipher}
+}
+
+func (r *BroadcastPostgresRepository) CreateBroadcast(ctx context.Context, broadcast *entities.Broadcast) error {
+	query := fmt.Sprintf(`
+		insert into broadcasts (topic, segment, delivery_type, content, priority, category, client_id, tenant_id)
+		values ($1, $2, $3, $4, $5, $6, $7, $8)
+		returning %s
+	`, broadcastColumns)
+	row := r.db.Pool.QueryRow(ctx, query,
+		broadcast.Topic,
+		broadcast.Segment,
+		broadcast.DeliveryType,
+		broadcast.Content,
+		broadcast.Priority,
+		broadcast.Category,
+		broadcast.ClientID,
+		broadcast.TenantID,
+	)
+	if err := scanBroadcast(row, broadcast); err != nil {
+		return fmt.Errorf("BroadcastPostgresRepository.CreateBroadcast error: %w", err)
+	}
+	return nil
+}
+
+// GetBroadcasts reads the broadcasts of the tenant and the client, a nil filter reads all of them.
+func (r *BroadcastPostgresRepository) GetBroadcasts(ctx context.Context, tenantID, clientID *uuid.UUID, limit, offset uint) ([]*entities.Broadcast, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from broadcasts
+		where ($1::uuid is null or tenant_id = $1)
+			and ($2::uuid is null or client_id = $2)
+		order by created_at desc, id
+		limit $3 offset $4
+	`, broadcastColumns)
+	rows, err := r.db.Pool.Query(ctx, query, tenantID, clientID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("BroadcastPostgresRepository.GetBroadcasts query error: %w", err)
+	}
+	defer rows.Close()
+
+	broadcasts := make([]*entities.Broadcast, 0)
+	for rows.Next() {
+		broadcast := &entities.Broadcast{}
+		if err := scanBroadcast(rows, broadcast); err != nil {
+			return nil, fmt.Errorf("BroadcastPostgresRepository.GetBroadcasts scan error: %w", err)
+		}
+		broadcasts = append(broadcasts, broadcast)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("BroadcastPostgresRepository.GetBroadcasts rows error: %w", err)
+	}
+	return broadcasts, nil
+}
+
+// GetBroadcast returns the broadcast of the tenant, a nil tenant reads the broadcasts of every tenant.
+func (r *BroadcastPostgresRepository) GetBroadcast(ctx context.Context, id uuid.UUID, tenantID *uuid.UUID) (*entities.Broadcast, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from broadcasts
+		where id = $1
+			and ($2::uuid is null or tenant_id = $2)
+	`, broadcastColumns)
+	broadcast := &entities.Broadcast{}
+	if err := scanBroadcast(r.db.Pool.QueryRow(ctx, query, id, tenantID), broadcast); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("BroadcastPostgresRepository.GetBroadcast error: %w", err)
+	}
+	return broadcast, nil
+}
+
+// UpdateBroadcastStatus moves the broadcast to the status when it is in one of the from statuses,
+// otherwise it returns ErrNotFound. A chunk being expanded finishes before the status changes.
+func (r *BroadcastPostgresRepository) UpdateBroadcastStatus(ctx context.Context, id uuid.UUID, status string, from []string) (*entities.Broadcast, error) {
+	query := fmt.Sprintf(`
+		update broadcasts
+		set status = $2,
+			updated_at = now(),
+			completed_at = case when $2 = '%s' then now() else completed_at end
+		where id = $1 and status = any($3)
+		returning %s
+	`, entities.BroadcastStatusCancelled, broadcastColumns)
+	broadcast := &entities.Broadcast{}
+	if err := scanBroadcast(r.db.Pool.QueryRow(ctx, query, id, status, from), broadcast); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("BroadcastPostgresRepository.UpdateBroadcastStatus error: %w", err)
+	}
+	return broadcast, nil
+}
+
+// ProcessBroadcastChunk expands the next chunk of the oldest pending or running broadcast
+// that no other replica is expanding. The notifications and the new cursor are written in
+// one transaction, so a chunk is expanded exactly once. The broadcast completes with the
+// first chunk shorter than chunkSize. It returns ErrNotFound when there is nothing to expand.
+// A chunk that would exceed a quota of the client of the broadcast pauses the broadcast and
+// returns ErrQuotaExceeded.
+func (r *BroadcastPostgresRepository) ProcessBroadcastChunk(ctx context.Context, chunkSize uint) (*entities.Broadcast, int, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("BroadcastPostgresRepository.ProcessBroadcastChunk begin error: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	lockQuery := fmt.Sprintf(`
+		select %s
+		from broadcasts
+		where status in ($1, $2)
+		order by created_at
+		limit 1
+		for update skip locked
+	`, broadcastColumns)
+	broadcast := &entities.Broadcast{}
+	row := tx.QueryRow(ctx, lockQuery, entities.BroadcastStatusPending, entities.BroadcastStatusRunning)
+	if err := scanBroadcast(row, broadcast); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, 0, ErrNotFound
+		}
+		return nil, 0, fmt.Errorf("BroadcastPostgresRepository.ProcessBroadcastChunk lock error: %w", err)
+	}
+	var timeZones []string
+	if broadcast.Segment != nil {
+		timeZones = broadcast.Segment.TimeZones
+	}
+	if timeZones == nil {
+		timeZones = []string{}
+	}
+	if broadcast.Status == entities.BroadcastStatusPending {
+		if err := startBroadcast(ctx, tx, broadcast, timeZones); err != nil {
+			return nil, 0, fmt.Errorf("BroadcastPostgresRepository.ProcessBroadcastChunk %w", err)
+		}
+	}
+
+	// the notifications of a chunk share one encrypted content
+	content, err := r.cipher.Encrypt(ctx, fieldContent, broadcast.Content)
+	if err != nil {
+		return nil, 0, fmt.Errorf("BroadcastPostgresRepository.ProcessBroadcastChunk encrypt error: %w", err)
+	}
+	chunkQuery := fmt.Sprintf(`
+		with chunk as (
+			select user_id from (%s) users
+			order by user_id
+			limit $6
+		), inserted as (
+			insert into notifications (delivery_type, recipient, content, priority, user_id, category, broadcast_id, client_id, tenant_id)
+			select $2, '', $7, $8, user_id, $9, $10, $11, $12
+			from chunk
+		)
+		select count(*), max(user_id)
+		from chunk
+	`, broadcastUsersQuery)
+	var count int
+	var last *string
+	err = tx.QueryRow(ctx, chunkQuery,
+		broadcast.Topic,
+		broadcast.DeliveryType,
+		timeZones,
+		broadcast.Cursor,
+		tenantKey(broadcast.TenantID),
+		chunkSize,
+		content,
+		broadcast.Priority,
+		broadcast.Category,
+		broadcast.ID,
+		broadcast.ClientID,
+		broadcast.TenantID,
+	).Scan(&count, &last)
+	if err != nil {
+		return nil, 0, fmt.Errorf("BroadcastPostgresRepository.ProcessBroadcastChunk insert error: %w", err)
+	}
+	if count != 0 {
+		err := consumeOwnerQuota(ctx, tx, broadcast.ClientID, map[string]int32{broadcast.DeliveryType: int32(count)})
+		if errors.Is(err, ErrQuotaExceeded) {
+			tx.Rollback(ctx)
+			return r.pauseBroadcast(ctx, broadcast, err)
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("BroadcastPostgresRepository.ProcessBroadcastChunk quota %w", err)
+		}
+	}
+
+	status := entities.BroadcastStatusRunning
+	if count < int(chunkSize) {
+		status = entities.BroadcastStatusCompleted
+	}
+	updateQuery := fmt.Sprintf(`
+		update broadcasts
+		set processed = processed + $2,
+			cursor = coalesce($3, cursor),
+			status = $4,
+			updated_at = now(),
+			completed_at = case when $4 = '%s' then now() else completed_at end
+		where id = $1
+		returning %s
+	`, entities.BroadcastStatusCompleted, broadcastColumns)
+	row = tx.QueryRow(ctx, updateQuery, broadcast.ID, count, last, status)
+	if err := scanBroadcast(row, broadcast); err != nil {
+		return nil, 0, fmt.Errorf("BroadcastPostgresRepository.ProcessBroadcastChunk update error: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, 0, fmt.Errorf("BroadcastPostgresRepository.ProcessBroadcastChunk commit error: %w", err)
+	}
+	return broadcast, count, nil
+}
+
+// pauseBroadcast pauses a broadcast whose chunk would exceed a quota of its client, nothing of
+// the chunk is created. It returns the paused broadcast with the quota error, the broadcast
+// is resumed like any other paused one once the quota allows it.
+func (r *BroadcastPostgresRepository) pauseBroadcast(ctx context.Context, broadcast *entities.Broadcast, quotaErr error) (*entities.Broadcast, int, error) {
+	query := fmt.Sprintf(`
+		update broadcasts
+		set status = $2,
+			updated_at = now()
+		where id = $1 and status in ($3, $4)
+		returning %s
+	`, broadcastColumns)
+	row := r.db.Pool.QueryRow(ctx, query, broadcast.ID,
+		entities.BroadcastStatusPaused, entities.BroadcastStatusPending, entities.BroadcastStatusRunning)
+	if err := scanBroadcast(row, broadcast); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, 0, fmt.Errorf("BroadcastPostgresRepository.ProcessBroadcastChunk pause error: %w", err)
+	}
+	return broadcast, 0, quotaErr
+}
+
+// startBroadcast counts the users of a pending broadcast and marks it running.
+func startBroadcast(ctx context.Context, tx pgx.Tx, broadcast *entities.Broadcast, timeZones []string) error {
+	query := fmt.Sprintf(`
+		update broadcasts
+		set status = $6,
+			total = (select count(*) from (%s) users),
+			started_at = now(),
+			updated_at = now()
+		where id = $7
+		returning status, total, started_at
+	`, broadcastUsersQuery)
+	err := tx.QueryRow(ctx, query,
+		broadcast.Topic,
+		broadcast.DeliveryType,
+		timeZones,
+		broadcast.Cursor,
+		tenantKey(broadcast.TenantID),
+		entities.BroadcastStatusRunning,
+		broadcast.ID,
+	).Scan(&broadcast.Status, &broadcast.Total, &broadcast.StartedAt)
+	if err != nil {
+		return fmt.Errorf("start broadcast error: %w", err)
+	}
+	return nil
+}
+
+func scanBroadcast(row pgx.Row, broadcast *entities.Broadcast) error {
+	return row.Scan(
+		&broadcast.ID,
+		&broadcast.Topic,
+		&broadcast.Segment,
+		&broadcast.DeliveryType,
+		&broadcast.Content,
+		&broadcast.Priority,
+		&broadcast.Category,
+		&broadcast.Status,
+		&broadcast.Total,
+		&broadcast.Processed,
+		&broadcast.Cursor,
+		&broadcast.CreatedAt,
+		&broadcast.UpdatedAt,
+		&broadcast.StartedAt,
+		&broadcast.CompletedAt,
+		&broadcast.ClientID,
+		&broadcast.TenantID,
+	)
+}
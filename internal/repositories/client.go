@@ -0,0 +1,401 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"notification_system/internal/entities"
+	"notification_system/pkg/database"
+	"notification_system/pkg/envelope"
+)
+
+const clientColumns = `id, name, oauth_client_id, tenant_id, created_at`
+
+const tenantColumns = `id, name, email_from, smtp_host, smtp_port, smtp_username, smtp_password, created_at`
+
+const apiKeyColumns = `id, client_id, prefix, key_hash, created_at, expires_at, revoked_at`
+
+type ClientPostgresRepository struct {
+	db     *database.PostgresDatabase
+	cipher *envelope.Cipher
+}
+
+func NewClientPostgresRepository(db *database.PostgresDatabase, cipher *envelope.Cipher) ClientRepository {
+	return &ClientPostgresRepository{db: db, cipher: cipher}
+}
+
+// CreateClient inserts the client with its first key in one transaction.
+func (r *ClientPostgresRepository) CreateClient(ctx context.Context, client *entities.Client, key *entities.APIKey) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ClientPostgresRepository.CreateClient begin error: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := fmt.Sprintf(`
+		insert into clients (name, oauth_client_id, tenant_id)
+		values ($1, $2, $3)
+		returning %s
+	`, clientColumns)
+	if err := scanClient(tx.QueryRow(ctx, query, client.Name, client.OAuthClientID, client.TenantID), client); err != nil {
+		if isUniqueViolation(err) {
+			return ErrAlreadyExists
+		}
+		if isForeignKeyViolation(err) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("ClientPostgresRepository.CreateClient insert error: %w", err)
+	}
+	key.ClientID = client.ID
+	if err := insertAPIKey(ctx, tx, key); err != nil {
+		return fmt.Errorf("ClientPostgresRepository.CreateClient %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("ClientPostgresRepository.CreateClient commit error: %w", err)
+	}
+	return nil
+}
+
+func (r *ClientPostgresRepository) GetClients(ctx context.Context) ([]*entities.Client, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from clients
+		order by name
+	`, clientColumns)
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("ClientPostgresRepository.GetClients query error: %w", err)
+	}
+	defer rows.Close()
+
+	clients := make([]*entities.Client, 0)
+	for rows.Next() {
+		client := &entities.Client{}
+		if err := scanClient(rows, client); err != nil {
+			return nil, fmt.Errorf("ClientPostgresRepository.GetClients scan error: %w", err)
+		}
+		clients = append(clients, client)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ClientPostgresRepository.GetClients rows error: %w", err)
+	}
+	return clients, nil
+}
+
+func (r *ClientPostgresRepository) GetClient(ctx context.Context, id uuid.UUID) (*entities.Client, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from clients
+		where id = $1
+	`, clientColumns)
+	client := &entities.Client{}
+	if err := scanClient(r.db.Pool.QueryRow(ctx, query, id), client); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("ClientPostgresRepository.GetClient error: %w", err)
+	}
+	return client, nil
+}
+
+func (r *ClientPostgresRepository) GetClientByOAuthClientID(ctx context.Context, oauthClientID string) (*entities.Client, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from clients
+		where oauth_client_id = $1
+	`, clientColumns)
+	client := &entities.Client{}
+	if err := scanClient(r.db.Pool.QueryRow(ctx, query, oauthClientID), client); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("ClientPostgresRepository.GetClientByOAuthClientID error: %w", err)
+	}
+	return client, nil
+}
+
+// UpdateClientOAuthClientID links the client to an OAuth client, nil unlinks it.
+func (r *ClientPostgresRepository) UpdateClientOAuthClientID(ctx context.Context, id uuid.UUID, oauthClientID *string) (*entities.Client, error) {
+	query := fmt.Sprintf(`
+		update clients
+		set oauth_client_id = $2
+		where id = $1
+		returning %s
+	`, clientColumns)
+	client := &entities.Client{}
+	if err := scanClient(r.db.Pool.QueryRow(ctx, query, id, oauthClientID), client); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		if isUniqueViolation(err) {
+			return nil, ErrAlreadyExists
+		}
+		return nil, fmt.Errorf("ClientPostgresRepository.UpdateClientOAuthClientID error: %w", err)
+	}
+	return client, nil
+}
+
+// CreateAPIKey adds a key to a client, ErrNotFound when the client does not exist.
+func (r *ClientPostgresRepository) CreateAPIKey(ctx context.Context, key *entities.APIKey) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ClientPostgresRepository.CreateAPIKey begin error: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := insertAPIKey(ctx, tx, key); err != nil {
+		if isForeignKeyViolation(err) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("ClientPostgresRepository.CreateAPIKey %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("ClientPostgresRepository.CreateAPIKey commit error: %w", err)
+	}
+	return nil
+}
+
+func (r *ClientPostgresRepository) GetAPIKeys(ctx context.Context, clientID uuid.UUID) ([]*entities.APIKey, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from api_keys
+		where client_id = $1
+		order by created_at desc
+	`, apiKeyColumns)
+	rows, err := r.db.Pool.Query(ctx, query, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("ClientPostgresRepository.GetAPIKeys query error: %w", err)
+	}
+	defer rows.Close()
+
+	keys := make([]*entities.APIKey, 0)
+	for rows.Next() {
+		key := &entities.APIKey{}
+		if err := scanAPIKey(rows, key); err != nil {
+			return nil, fmt.Errorf("ClientPostgresRepository.GetAPIKeys scan error: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ClientPostgresRepository.GetAPIKeys rows error: %w", err)
+	}
+	return keys, nil
+}
+
+func (r *ClientPostgresRepository) GetAPIKeyByPrefix(ctx context.Context, prefix string) (*entities.APIKey, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from api_keys
+		where prefix = $1
+	`, apiKeyColumns)
+	key := &entities.APIKey{}
+	if err := scanAPIKey(r.db.Pool.QueryRow(ctx, query, prefix), key); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("ClientPostgresRepository.GetAPIKeyByPrefix error: %w", err)
+	}
+	return key, nil
+}
+
+// RotateAPIKey inserts the new key and lets the old one expire at expiresAt, or earlier
+// when it was already set to. It returns ErrNotFound when the old key of the client
+// does not exist or is revoked.
+func (r *ClientPostgresRepository) RotateAPIKey(ctx context.Context, clientID, oldID uuid.UUID, key *entities.APIKey, expiresAt time.Time) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ClientPostgresRepository.RotateAPIKey begin error: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		update api_keys
+		set expires_at = least(coalesce(expires_at, $3), $3)
+		where id = $1 and client_id = $2 and revoked_at is null
+	`
+	tag, err := tx.Exec(ctx, query, oldID, clientID, expiresAt)
+	if err != nil {
+		return fmt.Errorf("ClientPostgresRepository.RotateAPIKey update error: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	key.ClientID = clientID
+	if err := insertAPIKey(ctx, tx, key); err != nil {
+		return fmt.Errorf("ClientPostgresRepository.RotateAPIKey %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("ClientPostgresRepository.RotateAPIKey commit error: %w", err)
+	}
+	return nil
+}
+
+// RevokeAPIKey stops a key of the client at once, ErrNotFound when it does not exist or is revoked.
+func (r *ClientPostgresRepository) RevokeAPIKey(ctx context.Context, clientID, id uuid.UUID) error {
+	query := `
+		update api_keys
+		set revoked_at = now()
+		where id = $1 and client_id = $2 and revoked_at is null
+	`
+	tag, err := r.db.Pool.Exec(ctx, query, id, clientID)
+	if err != nil {
+		return fmt.Errorf("ClientPostgresRepository.RevokeAPIKey error: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func insertAPIKey(ctx context.Context, tx pgx.Tx, key *entities.APIKey) error {
+	query := fmt.Sprintf(`
+		insert into api_keys (client_id, prefix, key_hash)
+		values ($1, $2, $3)
+		returning %s
+	`, apiKeyColumns)
+	if err := scanAPIKey(tx.QueryRow(ctx, query, key.ClientID, key.Prefix, key.KeyHash), key); err != nil {
+		return fmt.Errorf("insert api key error: %w", err)
+	}
+	return nil
+}
+
+func scanClient(row pgx.Row, client *entities.Client) error {
+	return row.Scan(&client.ID, &client.Name, &client.OAuthClientID, &client.TenantID, &client.CreatedAt)
+}
+
+// CreateTenant stores the SMTP password of the tenant encrypted, the tenant keeps it as plaintext.
+func (r *ClientPostgresRepository) CreateTenant(ctx context.Context, tenant *entities.Tenant) error {
+	password := tenant.SMTPPassword
+	if password != nil {
+		sealed, err := r.cipher.Encrypt(ctx, fieldSMTPPassword, *password)
+		if err != nil {
+			return fmt.Errorf("ClientPostgresRepository.CreateTenant encrypt error: %w", err)
+		}
+		password = &sealed
+	}
+	query := fmt.Sprintf(`
+		insert into tenants (name, email_from, smtp_host, smtp_port, smtp_username, smtp_password)
+		values ($1, $2, $3, $4, $5, $6)
+		returning %s
+	`, tenantColumns)
+	row := r.db.Pool.QueryRow(ctx, query,
+		tenant.Name,
+		tenant.EmailFrom,
+		tenant.SMTPHost,
+		tenant.SMTPPort,
+		tenant.SMTPUsername,
+		password,
+	)
+	if err := scanTenant(row, tenant); err != nil {
+		if isUniqueViolation(err) {
+			return ErrAlreadyExists
+		}
+		return fmt.Errorf("ClientPostgresRepository.CreateTenant error: %w", err)
+	}
+	if err := r.openTenant(ctx, tenant); err != nil {
+		return fmt.Errorf("ClientPostgresRepository.CreateTenant decrypt error: %w", err)
+	}
+	return nil
+}
+
+func (r *ClientPostgresRepository) GetTenants(ctx context.Context) ([]*entities.Tenant, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from tenants
+		order by name
+	`, tenantColumns)
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("ClientPostgresRepository.GetTenants query error: %w", err)
+	}
+	defer rows.Close()
+
+	tenants := make([]*entities.Tenant, 0)
+	for rows.Next() {
+		tenant := &entities.Tenant{}
+		if err := scanTenant(rows, tenant); err != nil {
+			return nil, fmt.Errorf("ClientPostgresRepository.GetTenants scan error: %w", err)
+		}
+		if err := r.openTenant(ctx, tenant); err != nil {
+			return nil, fmt.Errorf("ClientPostgresRepository.GetTenants decrypt error: %w", err)
+		}
+		tenants = append(tenants, tenant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ClientPostgresRepository.GetTenants rows error: %w", err)
+	}
+	return tenants, nil
+}
+
+func (r *ClientPostgresRepository) GetTenant(ctx context.Context, id uuid.UUID) (*entities.Tenant, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from tenants
+		where id = $1
+	`, tenantColumns)
+	tenant := &entities.Tenant{}
+	if err := scanTenant(r.db.Pool.QueryRow(ctx, query, id), tenant); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("ClientPostgresRepository.GetTenant error: %w", err)
+	}
+	if err := r.openTenant(ctx, tenant); err != nil {
+		return nil, fmt.Errorf("ClientPostgresRepository.GetTenant decrypt error: %w", err)
+	}
+	return tenant, nil
+}
+
+// openTenant decrypts the SMTP password of the tenant in place, a password stored before
+// the encryption at rest was enabled is read as it is.
+func (r *ClientPostgresRepository) openTenant(ctx context.Context, tenant *entities.Tenant) error {
+	if tenant.SMTPPassword == nil {
+		return nil
+	}
+	password, err := r.cipher.Decrypt(ctx, fieldSMTPPassword, *tenant.SMTPPassword)
+	if err != nil {
+		return err
+	}
+	tenant.SMTPPassword = &password
+	return nil
+}
+
+func scanTenant(row pgx.Row, tenant *entities.Tenant) error {
+	return row.Scan(
+		&tenant.ID,
+		&tenant.Name,
+		&tenant.EmailFrom,
+		&tenant.SMTPHost,
+		&tenant.SMTPPort,
+		&tenant.SMTPUsername,
+		&tenant.SMTPPassword,
+		&tenant.CreatedAt,
+	)
+}
+
+func scanAPIKey(row pgx.Row, key *entities.APIKey) error {
+	return row.Scan(
+		&key.ID,
+		&key.ClientID,
+		&key.Prefix,
+		&key.KeyHash,
+		&key.CreatedAt,
+		&key.ExpiresAt,
+		&key.RevokedAt,
+	)
+}
+
+// tenantKey is the tenant_key of the rows of the tenant in the tables keyed by tenant, the
+// nil UUID for the default tenant. Lookups compare it instead of the nullable tenant_id so
+// they use the keys.
+func tenantKey(tenantID *uuid.UUID) uuid.UUID {
+	if tenantID == nil {
+		return uuid.Nil
+	}
+	return *tenantID
+}
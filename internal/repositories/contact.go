@@ -0,0 +1,388 @@
+package repositories
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"notification_system/internal/entities"
+	"notification_system/pkg/database"
+)
+
+const contactAddressColumns = "id, user_id, tenant_id, delivery_type, address, is_primary, verified_at, created_at"
+
+type ContactPostgresRepository struct {
+	db *database.PostgresDatabase
+}
+
+func NewContactPostgresRepository(db *database.PostgresDatabase) ContactRepository {
+	return &ContactPostgresRepository{db: db}
+}
+
+// CreateContact creates the contact with its addresses in one transaction, so a rejected
+// address leaves no contact behind.
+func (r *ContactPostgresRepository) CreateContact(ctx context.Context, contact *entities.Contact, addresses []*entities.ContactAddress) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ContactPostgresRepository.CreateContact begin error: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		insert into contacts (user_id, tenant_id, name, time_zone)
+		values ($1, $2, $3, $4)
+		returning created_at, updated_at
+	`
+	err = tx.QueryRow(ctx, query, contact.UserID, contact.TenantID, contact.Name, contact.TimeZone).Scan(&contact.CreatedAt, &contact.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrAlreadyExists
+		}
+		return fmt.Errorf("ContactPostgresRepository.CreateContact error: %w", err)
+	}
+	for _, address := range addresses {
+		if err := insertContactAddress(ctx, tx, address); err != nil {
+			return fmt.Errorf("ContactPostgresRepository.CreateContact %w", err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("ContactPostgresRepository.CreateContact commit error: %w", err)
+	}
+	return nil
+}
+
+func (r *ContactPostgresRepository) GetContact(ctx context.Context, tenantID *uuid.UUID, userID string) (*entities.Contact, error) {
+	query := `
+		select user_id, tenant_id, name, time_zone, created_at, updated_at
+		from contacts
+		where tenant_key = $1 and user_id = $2
+	`
+	contact := &entities.Contact{}
+	err := r.db.Pool.QueryRow(ctx, query, tenantKey(tenantID), userID).Scan(
+		&contact.UserID,
+		&contact.TenantID,
+		&contact.Name,
+		&contact.TimeZone,
+		&contact.CreatedAt,
+		&contact.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("ContactPostgresRepository.GetContact error: %w", err)
+	}
+	return contact, nil
+}
+
+func (r *ContactPostgresRepository) UpdateContact(ctx context.Context, contact *entities.Contact) error {
+	query := `
+		update contacts
+		set name = $2,
+			time_zone = $3,
+			updated_at = now()
+		where tenant_key = $4 and user_id = $1
+		returning created_at, updated_at
+	`
+	err := r.db.Pool.QueryRow(ctx, query, contact.UserID, contact.Name, contact.TimeZone, tenantKey(contact.TenantID)).Scan(&contact.CreatedAt, &contact.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("ContactPostgresRepository.UpdateContact error: %w", err)
+	}
+	return nil
+}
+
+func (r *ContactPostgresRepository) DeleteContact(ctx context.Context, tenantID *uuid.UUID, userID string) error {
+	query := `
+		delete from contacts
+		where tenant_key = $1 and user_id = $2
+	`
+	tag, err := r.db.Pool.Exec(ctx, query, tenantKey(tenantID), userID)
+	if err != nil {
+		return fmt.Errorf("ContactPostgresRepository.DeleteContact error: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *ContactPostgresRepository) CreateContactAddress(ctx context.Context, address *entities.ContactAddress) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ContactPostgresRepository.CreateContactAddress begin error: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := insertContactAddress(ctx, tx, address); err != nil {
+		if errors.Is(err, ErrAlreadyExists) || errors.Is(err, ErrNotFound) {
+			return err
+		}
+		return fmt.Errorf("ContactPostgresRepository.CreateContactAddress %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("ContactPostgresRepository.CreateContactAddress commit error: %w", err)
+	}
+	return nil
+}
+
+func (r *ContactPostgresRepository) GetContactAddresses(ctx context.Context, tenantID *uuid.UUID, userID string) ([]*entities.ContactAddress, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from contact_addresses
+		where tenant_key = $1 and user_id = $2
+		order by delivery_type, is_primary desc, created_at
+	`, contactAddressColumns)
+	rows, err := r.db.Pool.Query(ctx, query, tenantKey(tenantID), userID)
+	if err != nil {
+		return nil, fmt.Errorf("ContactPostgresRepository.GetContactAddresses query error: %w", err)
+	}
+	defer rows.Close()
+
+	addresses := make([]*entities.ContactAddress, 0)
+	for rows.Next() {
+		address := &entities.ContactAddress{}
+		if err := scanContactAddress(rows, address); err != nil {
+			return nil, fmt.Errorf("ContactPostgresRepository.GetContactAddresses scan error: %w", err)
+		}
+		addresses = append(addresses, address)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ContactPostgresRepository.GetContactAddresses rows error: %w", err)
+	}
+	return addresses, nil
+}
+
+// UpdateContactAddress changes the address and the primary flag. A changed address
+// has to be verified again.
+func (r *ContactPostgresRepository) UpdateContactAddress(ctx context.Context, address *entities.ContactAddress) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ContactPostgresRepository.UpdateContactAddress begin error: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if address.Primary {
+		query := `
+			update contact_addresses
+			set is_primary = false
+			where tenant_key = $3 and user_id = $1 and is_primary and id <> $2
+				and delivery_type = (select delivery_type from contact_addresses where id = $2)
+		`
+		if _, err := tx.Exec(ctx, query, address.UserID, address.ID, tenantKey(address.TenantID)); err != nil {
+			return fmt.Errorf("ContactPostgresRepository.UpdateContactAddress reset primary error: %w", err)
+		}
+	}
+	query := fmt.Sprintf(`
+		update contact_addresses
+		set address = $3,
+			is_primary = $4,
+			verified_at = case when address = $3 then verified_at end,
+			verification_code_hash = case when address = $3 then verification_code_hash end,
+			verification_expires_at = case when address = $3 then verification_expires_at end
+		where id = $1 and tenant_key = $5 and user_id = $2
+		returning %s
+	`, contactAddressColumns)
+	row := tx.QueryRow(ctx, query, address.ID, address.UserID, address.Address, address.Primary, tenantKey(address.TenantID))
+	if err := scanContactAddress(row, address); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return ErrNotFound
+		}
+		if isUniqueViolation(err) {
+			return ErrAlreadyExists
+		}
+		return fmt.Errorf("ContactPostgresRepository.UpdateContactAddress update error: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("ContactPostgresRepository.UpdateContactAddress commit error: %w", err)
+	}
+	return nil
+}
+
+func (r *ContactPostgresRepository) DeleteContactAddress(ctx context.Context, tenantID *uuid.UUID, userID string, id uuid.UUID) error {
+	query := `
+		delete from contact_addresses
+		where id = $1 and tenant_key = $2 and user_id = $3
+	`
+	tag, err := r.db.Pool.Exec(ctx, query, id, tenantKey(tenantID), userID)
+	if err != nil {
+		return fmt.Errorf("ContactPostgresRepository.DeleteContactAddress error: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// SetVerificationCode stores the hash of a verification code for the address and resets
+// the failed attempts. It returns the address the code has to be sent to.
+func (r *ContactPostgresRepository) SetVerificationCode(ctx context.Context, tenantID *uuid.UUID, userID string, id uuid.UUID, codeHash string, expiresAt time.Time) (*entities.ContactAddress, error) {
+	query := fmt.Sprintf(`
+		update contact_addresses
+		set verification_code_hash = $3,
+			verification_expires_at = $4,
+			verification_attempts = 0
+		where id = $1 and tenant_key = $5 and user_id = $2
+		returning %s
+	`, contactAddressColumns)
+	address := &entities.ContactAddress{}
+	err := scanContactAddress(r.db.Pool.QueryRow(ctx, query, id, userID, codeHash, expiresAt, tenantKey(tenantID)), address)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("ContactPostgresRepository.SetVerificationCode error: %w", err)
+	}
+	return address, nil
+}
+
+// VerifyContactAddress marks the address verified when the code hash matches an unexpired code.
+// A wrong code counts as a failed attempt and the code is invalidated at maxAttempts, so it
+// cannot be guessed.
+func (r *ContactPostgresRepository) VerifyContactAddress(ctx context.Context, tenantID *uuid.UUID, userID string, id uuid.UUID, codeHash string, maxAttempts int32) (*entities.ContactAddress, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ContactPostgresRepository.VerifyContactAddress begin error: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		select verification_code_hash, verification_attempts
+		from contact_addresses
+		where id = $1 and tenant_key = $2 and user_id = $3 and verification_expires_at > now()
+		for update
+	`
+	var storedHash *string
+	var attempts int32
+	err = tx.QueryRow(ctx, query, id, tenantKey(tenantID), userID).Scan(&storedHash, &attempts)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInvalidVerificationCode
+		}
+		return nil, fmt.Errorf("ContactPostgresRepository.VerifyContactAddress select error: %w", err)
+	}
+	if storedHash == nil {
+		return nil, ErrInvalidVerificationCode
+	}
+	if subtle.ConstantTimeCompare([]byte(*storedHash), []byte(codeHash)) != 1 {
+		query = `
+			update contact_addresses
+			set verification_attempts = verification_attempts + 1,
+				verification_code_hash = case when verification_attempts + 1 >= $2 then null else verification_code_hash end,
+				verification_expires_at = case when verification_attempts + 1 >= $2 then null else verification_expires_at end
+			where id = $1
+		`
+		if _, err := tx.Exec(ctx, query, id, maxAttempts); err != nil {
+			return nil, fmt.Errorf("ContactPostgresRepository.VerifyContactAddress attempt error: %w", err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("ContactPostgresRepository.VerifyContactAddress commit error: %w", err)
+		}
+		return nil, ErrInvalidVerificationCode
+	}
+	query = fmt.Sprintf(`
+		update contact_addresses
+		set verified_at = now(),
+			verification_code_hash = null,
+			verification_expires_at = null,
+			verification_attempts = 0
+		where id = $1
+		returning %s
+	`, contactAddressColumns)
+	address := &entities.ContactAddress{}
+	if err := scanContactAddress(tx.QueryRow(ctx, query, id), address); err != nil {
+		return nil, fmt.Errorf("ContactPostgresRepository.VerifyContactAddress update error: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("ContactPostgresRepository.VerifyContactAddress commit error: %w", err)
+	}
+	return address, nil
+}
+
+// ResolveAddress returns the address a notification for the user is sent to:
+// the primary verified address of the channel, otherwise the latest verified one.
+func (r *ContactPostgresRepository) ResolveAddress(ctx context.Context, tenantID *uuid.UUID, userID, deliveryType string) (string, error) {
+	query := `
+		select address
+		from contact_addresses
+		where tenant_key = $1 and user_id = $2 and delivery_type = $3 and verified_at is not null
+		order by is_primary desc, verified_at desc
+		limit 1
+	`
+	var address string
+	err := r.db.Pool.QueryRow(ctx, query, tenantKey(tenantID), userID, deliveryType).Scan(&address)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("ContactPostgresRepository.ResolveAddress error: %w", err)
+	}
+	return address, nil
+}
+
+func insertContactAddress(ctx context.Context, tx pgx.Tx, address *entities.ContactAddress) error {
+	if address.Primary {
+		if err := resetPrimaryAddress(ctx, tx, address.TenantID, address.UserID, address.DeliveryType); err != nil {
+			return err
+		}
+	}
+	query := fmt.Sprintf(`
+		insert into contact_addresses (user_id, tenant_id, delivery_type, address, is_primary, verified_at)
+		values ($1, $2, $3, $4, $5, $6)
+		returning %s
+	`, contactAddressColumns)
+	row := tx.QueryRow(ctx, query,
+		address.UserID,
+		address.TenantID,
+		address.DeliveryType,
+		address.Address,
+		address.Primary,
+		address.VerifiedAt,
+	)
+	if err := scanContactAddress(row, address); err != nil {
+		if isUniqueViolation(err) {
+			return ErrAlreadyExists
+		}
+		if isForeignKeyViolation(err) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("insert address error: %w", err)
+	}
+	return nil
+}
+
+func resetPrimaryAddress(ctx context.Context, tx pgx.Tx, tenantID *uuid.UUID, userID, deliveryType string) error {
+	query := `
+		update contact_addresses
+		set is_primary = false
+		where tenant_key = $1 and user_id = $2 and delivery_type = $3 and is_primary
+	`
+	if _, err := tx.Exec(ctx, query, tenantKey(tenantID), userID, deliveryType); err != nil {
+		return fmt.Errorf("reset primary error: %w", err)
+	}
+	return nil
+}
+
+func scanContactAddress(row pgx.Row, address *entities.ContactAddress) error {
+	err := row.Scan(
+		&address.ID,
+		&address.UserID,
+		&address.TenantID,
+		&address.DeliveryType,
+		&address.Address,
+		&address.Primary,
+		&address.VerifiedAt,
+		&address.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,223 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"notification_system/internal/entities"
+	"notification_system/pkg/database"
+	"notification_system/pkg/envelope"
+)
+
+type DigestPostgresRepository struct {
+	db     *database.PostgresDatabase
+	cipher *envelope.Cipher
+}
+
+func NewDigestPostgresRepository(db *database.PostgresDatabase, cipher *envelope.Cipher) DigestRepository {
+	return &DigestPostgresRepository{db: db, cipher: cipher}
+}
+
+func (r *DigestPostgresRepository) GetDigestTemplates(ctx context.Context, tenantID *uuid.UUID) ([]*entities.DigestTemplate, error) {
+	query := `
+		select digest_key, tenant_id, template, updated_at
+		from digest_templates
+		where tenant_key = $1
+		order by digest_key
+	`
+	rows, err := r.db.Pool.Query(ctx, query, tenantKey(tenantID))
+	if err != nil {
+		return nil, fmt.Errorf("DigestPostgresRepository.GetDigestTemplates query error: %w", err)
+	}
+	defer rows.Close()
+
+	templates := make([]*entities.DigestTemplate, 0)
+	for rows.Next() {
+		template := &entities.DigestTemplate{}
+		if err := rows.Scan(&template.Key, &template.TenantID, &template.Template, &template.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("DigestPostgresRepository.GetDigestTemplates scan error: %w", err)
+		}
+		templates = append(templates, template)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("DigestPostgresRepository.GetDigestTemplates rows error: %w", err)
+	}
+	return templates, nil
+}
+
+func (r *DigestPostgresRepository) GetDigestTemplate(ctx context.Context, tenantID *uuid.UUID, key string) (*entities.DigestTemplate, error) {
+	query := `
+		select digest_key, tenant_id, template, updated_at
+		from digest_templates
+		where tenant_key = $1 and digest_key = $2
+	`
+	template := &entities.DigestTemplate{}
+	err := r.db.Pool.QueryRow(ctx, query, tenantKey(tenantID), key).Scan(&template.Key, &template.TenantID, &template.Template, &template.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("DigestPostgresRepository.GetDigestTemplate error: %w", err)
+	}
+	return template, nil
+}
+
+func (r *DigestPostgresRepository) UpsertDigestTemplate(ctx context.Context, template *entities.DigestTemplate) error {
+	query := `
+		insert into digest_templates (digest_key, tenant_id, template)
+		values ($1, $2, $3)
+		on conflict (tenant_key, digest_key) do update
+		set template = excluded.template,
+			updated_at = now()
+		returning updated_at
+	`
+	if err := r.db.Pool.QueryRow(ctx, query, template.Key, template.TenantID, template.Template).Scan(&template.UpdatedAt); err != nil {
+		return fmt.Errorf("DigestPostgresRepository.UpsertDigestTemplate error: %w", err)
+	}
+	return nil
+}
+
+func (r *DigestPostgresRepository) DeleteDigestTemplate(ctx context.Context, tenantID *uuid.UUID, key string) error {
+	query := `
+		delete from digest_templates
+		where tenant_key = $1 and digest_key = $2
+	`
+	tag, err := r.db.Pool.Exec(ctx, query, tenantKey(tenantID), key)
+	if err != nil {
+		return fmt.Errorf("DigestPostgresRepository.DeleteDigestTemplate error: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetDueDigests returns up to limit digests whose window, opened by their oldest item, has ended.
+func (r *DigestPostgresRepository) GetDueDigests(ctx context.Context, limit uint) ([]*entities.Digest, error) {
+	query := fmt.Sprintf(`
+		with due as (
+			select digest_key, delivery_type, coalesce(recipient_hash, recipient) as recipient,
+				coalesce(user_id, '') as user_id, tenant_id
+			from notifications
+			where status = $1 and summary_id is null
+			group by digest_key, delivery_type, coalesce(recipient_hash, recipient), coalesce(user_id, ''), tenant_id
+			having min(created_at + make_interval(secs => digest_window_seconds)) <= now()
+			limit $2
+		)
+		select %s
+		from notifications
+		where id in (
+			select n.id
+			from notifications n
+			join due d on d.digest_key = n.digest_key
+				and d.delivery_type = n.delivery_type
+				and d.recipient = coalesce(n.recipient_hash, n.recipient)
+				and d.user_id = coalesce(n.user_id, '')
+				and d.tenant_id is not distinct from n.tenant_id
+			where n.status = $1 and n.summary_id is null
+		)
+		order by tenant_id, digest_key, delivery_type, coalesce(recipient_hash, recipient), user_id, created_at
+	`, notificationColumns)
+	rows, err := r.db.Pool.Query(ctx, query, entities.StatusDigested, limit)
+	if err != nil {
+		return nil, fmt.Errorf("DigestPostgresRepository.GetDueDigests query error: %w", err)
+	}
+	defer rows.Close()
+
+	digests := make([]*entities.Digest, 0)
+	var digest *entities.Digest
+	var undecryptable []uuid.UUID
+	for rows.Next() {
+		notification := &entities.Notification{}
+		if err := scanNotification(rows, notification); err != nil {
+			return nil, fmt.Errorf("DigestPostgresRepository.GetDueDigests scan error: %w", err)
+		}
+		if err := openNotifications(ctx, r.cipher, notification); err != nil {
+			undecryptable = append(undecryptable, notification.ID)
+			continue
+		}
+		if digest == nil || !digestContains(digest, notification) {
+			digest = &entities.Digest{
+				Key:          *notification.DigestKey,
+				DeliveryType: notification.DeliveryType,
+				Recipient:    notification.Recipient,
+				UserID:       notification.UserID,
+				TenantID:     notification.TenantID,
+			}
+			digests = append(digests, digest)
+		}
+		digest.Items = append(digest.Items, notification)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("DigestPostgresRepository.GetDueDigests rows error: %w", err)
+	}
+	rows.Close()
+	if err := failUndecryptable(ctx, r.db, undecryptable); err != nil {
+		return nil, fmt.Errorf("DigestPostgresRepository.GetDueDigests %w", err)
+	}
+	return digests, nil
+}
+
+// CreateDigestSummary inserts the summary and links the items to it in one transaction.
+func (r *DigestPostgresRepository) CreateDigestSummary(ctx context.Context, summary *entities.Notification, itemIDs []uuid.UUID) error {
+	sealed, err := sealFields(ctx, r.cipher, summary.Recipient, summary.Content)
+	if err != nil {
+		return fmt.Errorf("DigestPostgresRepository.CreateDigestSummary encrypt error: %w", err)
+	}
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("DigestPostgresRepository.CreateDigestSummary begin error: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := fmt.Sprintf(`
+		insert into notifications (delivery_type, recipient, content, priority, user_id, category, digest_key, tenant_id,
+			recipient_hash)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		returning %s
+	`, notificationColumns)
+	row := tx.QueryRow(ctx, query,
+		summary.DeliveryType,
+		sealed.recipient,
+		sealed.content,
+		summary.Priority,
+		summary.UserID,
+		summary.Category,
+		summary.DigestKey,
+		summary.TenantID,
+		sealed.recipientHash,
+	)
+	if err := scanNotification(row, summary); err != nil {
+		return fmt.Errorf("DigestPostgresRepository.CreateDigestSummary insert error: %w", err)
+	}
+	linkQuery := `
+		update notifications
+		set summary_id = $1
+		where id = any($2) and status = $3 and summary_id is null
+	`
+	if _, err := tx.Exec(ctx, linkQuery, summary.ID, itemIDs, entities.StatusDigested); err != nil {
+		return fmt.Errorf("DigestPostgresRepository.CreateDigestSummary link error: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("DigestPostgresRepository.CreateDigestSummary commit error: %w", err)
+	}
+	if err := openNotifications(ctx, r.cipher, summary); err != nil {
+		return fmt.Errorf("DigestPostgresRepository.CreateDigestSummary decrypt error: %w", err)
+	}
+	return nil
+}
+
+func digestContains(digest *entities.Digest, notification *entities.Notification) bool {
+	sameUser := (digest.UserID == nil && notification.UserID == nil) ||
+		(digest.UserID != nil && notification.UserID != nil && *digest.UserID == *notification.UserID)
+	sameTenant := (digest.TenantID == nil && notification.TenantID == nil) ||
+		(digest.TenantID != nil && notification.TenantID != nil && *digest.TenantID == *notification.TenantID)
+	return sameUser && sameTenant &&
+		digest.Key == *notification.DigestKey &&
+		digest.DeliveryType == notification.DeliveryType &&
+		digest.Recipient == notification.Recipient
+}
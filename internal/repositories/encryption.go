@@ -0,0 +1,373 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"notification_system/config"
+	"notification_system/internal/entities"
+	"notification_system/pkg/database"
+	"notification_system/pkg/envelope"
+)
+
+// the field names are authenticated with the values, notifications and their channels
+// share them so chain steps copy the stored values as they are
+const (
+	fieldRecipient = "recipient"
+	fieldContent   = "content"
+)
+
+// fieldSMTPPassword authenticates the SMTP password of a tenant
+const fieldSMTPPassword = "smtp_password"
+
+// reasonUndecryptable is the status reason of a notification failed because its recipient
+// or content cannot be decrypted
+const reasonUndecryptable = "recipient or content cannot be decrypted"
+
+// sealedFields is the stored form of the recipient and the content of a notification.
+type sealedFields struct {
+	recipient     string
+	content       string
+	recipientHash *string
+}
+
+func sealFields(ctx context.Context, c *envelope.Cipher, recipient, content string) (sealedFields, error) {
+	sealed := sealedFields{recipientHash: recipientHash(c, recipient)}
+	var err error
+	if sealed.recipient, err = c.Encrypt(ctx, fieldRecipient, recipient); err != nil {
+		return sealedFields{}, err
+	}
+	if sealed.content, err = c.Encrypt(ctx, fieldContent, content); err != nil {
+		return sealedFields{}, err
+	}
+	return sealed, nil
+}
+
+// sealChannel returns a copy of the channel with its stored recipient and content.
+func sealChannel(ctx context.Context, c *envelope.Cipher, channel *entities.NotificationChannel) (*entities.NotificationChannel, error) {
+	sealed := *channel
+	content := ""
+	if channel.Content != nil {
+		content = *channel.Content
+	}
+	fields, err := sealFields(ctx, c, channel.Recipient, content)
+	if err != nil {
+		return nil, err
+	}
+	sealed.Recipient = fields.recipient
+	if channel.Content != nil {
+		sealed.Content = &fields.content
+	}
+	return &sealed, nil
+}
+
+func recipientHash(c *envelope.Cipher, recipient string) *string {
+	hash := c.BlindIndex(recipient)
+	if hash == "" {
+		return nil
+	}
+	return &hash
+}
+
+// openNotifications decrypts the recipients and contents of the notifications in place.
+func openNotifications(ctx context.Context, c *envelope.Cipher, notifications ...*entities.Notification) error {
+	for _, notification := range notifications {
+		var err error
+		if notification.Recipient, err = c.Decrypt(ctx, fieldRecipient, notification.Recipient); err != nil {
+			return fmt.Errorf("notification %s: %w", notification.ID, err)
+		}
+		if notification.Content, err = c.Decrypt(ctx, fieldContent, notification.Content); err != nil {
+			return fmt.Errorf("notification %s: %w", notification.ID, err)
+		}
+	}
+	return nil
+}
+
+// openDeliverable decrypts the notifications read for delivery in place and returns those
+// it could decrypt with the IDs of the others. A value of a removed master key must not stop
+// the delivery of the rest of the batch.
+func openDeliverable(ctx context.Context, c *envelope.Cipher, notifications []*entities.Notification) ([]*entities.Notification, []uuid.UUID) {
+	deliverable := notifications[:0]
+	var undecryptable []uuid.UUID
+	for _, notification := range notifications {
+		if err := openNotifications(ctx, c, notification); err != nil {
+			undecryptable = append(undecryptable, notification.ID)
+			continue
+		}
+		deliverable = append(deliverable, notification)
+	}
+	return deliverable, undecryptable
+}
+
+// failUndecryptable marks the notifications that cannot be decrypted as failed, they would
+// be read again with every batch otherwise.
+func failUndecryptable(ctx context.Context, db *database.PostgresDatabase, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	query := `
+		update notifications
+		set status = $1,
+			status_reason = $2
+		where id = any($3)
+	`
+	if _, err := db.Pool.Exec(ctx, query, entities.StatusFailed, reasonUndecryptable, ids); err != nil {
+		return fmt.Errorf("fail undecryptable notifications error: %w", err)
+	}
+	return nil
+}
+
+func openNotificationChannels(ctx context.Context, c *envelope.Cipher, channels []*entities.NotificationChannel) error {
+	for _, channel := range channels {
+		var err error
+		if channel.Recipient, err = c.Decrypt(ctx, fieldRecipient, channel.Recipient); err != nil {
+			return fmt.Errorf("notification channel %s/%d: %w", channel.NotificationID, channel.Step, err)
+		}
+		if channel.Content != nil {
+			content, err := c.Decrypt(ctx, fieldContent, *channel.Content)
+			if err != nil {
+				return fmt.Errorf("notification channel %s/%d: %w", channel.NotificationID, channel.Step, err)
+			}
+			channel.Content = &content
+		}
+	}
+	return nil
+}
+
+type EncryptionPostgresRepository struct {
+	db     *database.PostgresDatabase
+	cipher *envelope.Cipher
+}
+
+func NewEncryptionPostgresRepository(db *database.PostgresDatabase, cipher *envelope.Cipher) EncryptionRepository {
+	return &EncryptionPostgresRepository{db: db, cipher: cipher}
+}
+
+// storedFields are the encrypted fields of a notification or a notification channel as stored.
+type storedFields struct {
+	id            uuid.UUID
+	step          int16
+	recipient     string
+	content       *string
+	recipientHash *string
+	changed       bool
+}
+
+// ReencryptNotifications re-encrypts the page of notifications after the ID, and their
+// channels, whose values are not under the primary master key or whose recipient hash is
+// not of the current index key. It returns the last ID of the page, uuid.Nil when there
+// are no more notifications, and the number of re-encrypted rows.
+func (r *EncryptionPostgresRepository) ReencryptNotifications(ctx context.Context, after uuid.UUID, limit uint) (uuid.UUID, int, error) {
+	if limit > config.Cfg.MaxBatchSize {
+		return uuid.Nil, 0, ErrMaxBatchSizeExceeded
+	}
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, 0, fmt.Errorf("EncryptionPostgresRepository.ReencryptNotifications begin error: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		select id, 0::smallint, recipient, content, recipient_hash
+		from notifications
+		where id > $1
+		order by id
+		limit $2
+		for update
+	`
+	rows, err := tx.Query(ctx, query, after, limit)
+	if err != nil {
+		return uuid.Nil, 0, fmt.Errorf("EncryptionPostgresRepository.ReencryptNotifications query error: %w", err)
+	}
+	notifications, err := scanStoredFields(rows)
+	if err != nil {
+		return uuid.Nil, 0, fmt.Errorf("EncryptionPostgresRepository.ReencryptNotifications scan error: %w", err)
+	}
+	if len(notifications) == 0 {
+		return uuid.Nil, 0, nil
+	}
+	ids := make([]uuid.UUID, len(notifications))
+	for i, notification := range notifications {
+		ids[i] = notification.id
+	}
+	query = `
+		select notification_id, step, recipient, content, recipient_hash
+		from notification_channels
+		where notification_id = any($1)
+		for update
+	`
+	rows, err = tx.Query(ctx, query, ids)
+	if err != nil {
+		return uuid.Nil, 0, fmt.Errorf("EncryptionPostgresRepository.ReencryptNotifications query channels error: %w", err)
+	}
+	channels, err := scanStoredFields(rows)
+	if err != nil {
+		return uuid.Nil, 0, fmt.Errorf("EncryptionPostgresRepository.ReencryptNotifications scan channels error: %w", err)
+	}
+	for _, fields := range append(notifications, channels...) {
+		if err := r.reencryptFields(ctx, fields); err != nil {
+			return uuid.Nil, 0, fmt.Errorf("EncryptionPostgresRepository.ReencryptNotifications %s/%d: %w", fields.id, fields.step, err)
+		}
+	}
+
+	changed := 0
+	if ids, recipients, contents, hashes, _ := changedFields(notifications); len(ids) != 0 {
+		query := `
+			update notifications n
+			set recipient = u.recipient, content = u.content, recipient_hash = u.recipient_hash
+			from unnest($1::uuid[], $2::text[], $3::text[], $4::text[]) as u(id, recipient, content, recipient_hash)
+			where n.id = u.id
+		`
+		if _, err := tx.Exec(ctx, query, ids, recipients, contents, hashes); err != nil {
+			return uuid.Nil, 0, fmt.Errorf("EncryptionPostgresRepository.ReencryptNotifications update error: %w", err)
+		}
+		changed += len(ids)
+	}
+	if ids, recipients, contents, hashes, steps := changedFields(channels); len(ids) != 0 {
+		query := `
+			update notification_channels c
+			set recipient = u.recipient, content = u.content, recipient_hash = u.recipient_hash
+			from unnest($1::uuid[], $2::text[], $3::text[], $4::text[], $5::smallint[])
+				as u(notification_id, recipient, content, recipient_hash, step)
+			where c.notification_id = u.notification_id and c.step = u.step
+		`
+		if _, err := tx.Exec(ctx, query, ids, recipients, contents, hashes, steps); err != nil {
+			return uuid.Nil, 0, fmt.Errorf("EncryptionPostgresRepository.ReencryptNotifications update channels error: %w", err)
+		}
+		changed += len(ids)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, 0, fmt.Errorf("EncryptionPostgresRepository.ReencryptNotifications commit error: %w", err)
+	}
+	return notifications[len(notifications)-1].id, changed, nil
+}
+
+// ReencryptTenants re-encrypts the SMTP passwords of the tenants that are not under the
+// primary master key, passwords stored as plaintext are sealed. It returns the number of
+// re-encrypted tenants.
+func (r *EncryptionPostgresRepository) ReencryptTenants(ctx context.Context) (int, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("EncryptionPostgresRepository.ReencryptTenants begin error: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		select id, smtp_password
+		from tenants
+		where smtp_password is not null
+		for update
+	`
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("EncryptionPostgresRepository.ReencryptTenants query error: %w", err)
+	}
+	var ids []uuid.UUID
+	var passwords []string
+	for rows.Next() {
+		var id uuid.UUID
+		var password string
+		if err := rows.Scan(&id, &password); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("EncryptionPostgresRepository.ReencryptTenants scan error: %w", err)
+		}
+		if !r.cipher.IsCurrent(password) {
+			ids = append(ids, id)
+			passwords = append(passwords, password)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("EncryptionPostgresRepository.ReencryptTenants rows error: %w", err)
+	}
+	for i, id := range ids {
+		password, err := r.cipher.Decrypt(ctx, fieldSMTPPassword, passwords[i])
+		if err != nil {
+			return 0, fmt.Errorf("EncryptionPostgresRepository.ReencryptTenants %s: %w", id, err)
+		}
+		if passwords[i], err = r.cipher.Encrypt(ctx, fieldSMTPPassword, password); err != nil {
+			return 0, fmt.Errorf("EncryptionPostgresRepository.ReencryptTenants %s: %w", id, err)
+		}
+	}
+	if len(ids) != 0 {
+		query := `
+			update tenants t
+			set smtp_password = u.smtp_password
+			from unnest($1::uuid[], $2::text[]) as u(id, smtp_password)
+			where t.id = u.id
+		`
+		if _, err := tx.Exec(ctx, query, ids, passwords); err != nil {
+			return 0, fmt.Errorf("EncryptionPostgresRepository.ReencryptTenants update error: %w", err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("EncryptionPostgresRepository.ReencryptTenants commit error: %w", err)
+	}
+	return len(ids), nil
+}
+
+// reencryptFields encrypts the values again under the primary master key and recomputes
+// the recipient hash when they are not current.
+func (r *EncryptionPostgresRepository) reencryptFields(ctx context.Context, fields *storedFields) error {
+	recipient, err := r.cipher.Decrypt(ctx, fieldRecipient, fields.recipient)
+	if err != nil {
+		return err
+	}
+	hash := recipientHash(r.cipher, recipient)
+	if !r.cipher.IsCurrent(fields.recipient) || !equalOptional(hash, fields.recipientHash) {
+		if fields.recipient, err = r.cipher.Encrypt(ctx, fieldRecipient, recipient); err != nil {
+			return err
+		}
+		fields.recipientHash = hash
+		fields.changed = true
+	}
+	if fields.content != nil && !r.cipher.IsCurrent(*fields.content) {
+		content, err := r.cipher.Decrypt(ctx, fieldContent, *fields.content)
+		if err != nil {
+			return err
+		}
+		if content, err = r.cipher.Encrypt(ctx, fieldContent, content); err != nil {
+			return err
+		}
+		fields.content = &content
+		fields.changed = true
+	}
+	return nil
+}
+
+func scanStoredFields(rows pgx.Rows) ([]*storedFields, error) {
+	defer rows.Close()
+	var stored []*storedFields
+	for rows.Next() {
+		fields := &storedFields{}
+		if err := rows.Scan(&fields.id, &fields.step, &fields.recipient, &fields.content, &fields.recipientHash); err != nil {
+			return nil, err
+		}
+		stored = append(stored, fields)
+	}
+	return stored, rows.Err()
+}
+
+// changedFields returns the changed rows as columns for unnest.
+func changedFields(fields []*storedFields) (ids []uuid.UUID, recipients []string, contents, hashes []*string, steps []int16) {
+	for _, f := range fields {
+		if f.changed {
+			ids = append(ids, f.id)
+			recipients = append(recipients, f.recipient)
+			contents = append(contents, f.content)
+			hashes = append(hashes, f.recipientHash)
+			steps = append(steps, f.step)
+		}
+	}
+	return ids, recipients, contents, hashes, steps
+}
+
+func equalOptional(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
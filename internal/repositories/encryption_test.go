@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/entities"
+	"notification_system/pkg/envelope"
+)
+
+func newTestCipher(t *testing.T, file *envelope.KeyFile) *envelope.Cipher {
+	t.Helper()
+	provider, err := file.Provider()
+	if err != nil {
+		t.Fatalf("Provider() error = %v", err)
+	}
+	indexKey, err := file.BlindIndexKey()
+	if err != nil {
+		t.Fatalf("BlindIndexKey() error = %v", err)
+	}
+	return envelope.New(provider, indexKey)
+}
+
+func TestEncryptionPostgresRepository_reencryptFields(t *testing.T) {
+	ctx := context.Background()
+	file, _ := envelope.NewKeyFile("k1")
+	old := newTestCipher(t, file)
+	_ = file.AddKey("k2")
+	r := &EncryptionPostgresRepository{cipher: newTestCipher(t, file)}
+
+	oldFields, _ := sealFields(ctx, old, "user@example.com", "hello")
+	current, _ := sealFields(ctx, r.cipher, "other@example.com", "hi")
+	content := "legacy content"
+	fields := []*storedFields{
+		{id: uuid.New(), recipient: oldFields.recipient, content: &oldFields.content, recipientHash: oldFields.recipientHash},
+		{id: uuid.New(), recipient: current.recipient, content: &current.content, recipientHash: current.recipientHash},
+		{id: uuid.New(), step: 1, recipient: "legacy@example.com", content: &content},
+	}
+	for _, f := range fields {
+		if err := r.reencryptFields(ctx, f); err != nil {
+			t.Fatalf("reencryptFields() error = %v", err)
+		}
+	}
+
+	if !fields[0].changed || !strings.HasPrefix(fields[0].recipient, "enc:v1:k2:") || !r.cipher.IsCurrent(*fields[0].content) {
+		t.Errorf("value of the previous key %+v, want it under k2", fields[0])
+	}
+	if recipient, _ := r.cipher.Decrypt(ctx, fieldRecipient, fields[0].recipient); recipient != "user@example.com" {
+		t.Errorf("re-encrypted recipient = %q", recipient)
+	}
+	if fields[1].changed || fields[1].recipient != current.recipient {
+		t.Errorf("current value %+v was re-encrypted", fields[1])
+	}
+	if !fields[2].changed || !envelope.IsEncrypted(fields[2].recipient) || fields[2].recipientHash == nil {
+		t.Errorf("plaintext value %+v, want it sealed with a recipient hash", fields[2])
+	}
+
+	ids, recipients, contents, hashes, steps := changedFields(fields)
+	if len(ids) != 2 || ids[0] != fields[0].id || ids[1] != fields[2].id {
+		t.Fatalf("changedFields() ids = %v, want the changed rows", ids)
+	}
+	if recipients[1] != fields[2].recipient || contents[1] != fields[2].content || hashes[1] != fields[2].recipientHash || steps[1] != 1 {
+		t.Errorf("changedFields() columns do not line up with the rows")
+	}
+}
+
+func TestOpenDeliverable(t *testing.T) {
+	ctx := context.Background()
+	// without keys a value with the prefix of encrypted values cannot be read
+	poisoned := &entities.Notification{ID: uuid.New(), Recipient: "user@example.com", Content: "enc:v1:k1:a:b"}
+	notifications := []*entities.Notification{
+		{ID: uuid.New(), Recipient: "first@example.com", Content: "hello"},
+		poisoned,
+		{ID: uuid.New(), Recipient: "last@example.com", Content: "hi"},
+	}
+	first, last := notifications[0], notifications[2]
+
+	deliverable, undecryptable := openDeliverable(ctx, nil, notifications)
+	if len(deliverable) != 2 || deliverable[0] != first || deliverable[1] != last {
+		t.Errorf("openDeliverable() = %v, want the rows around the poisoned one", deliverable)
+	}
+	if len(undecryptable) != 1 || undecryptable[0] != poisoned.ID {
+		t.Errorf("openDeliverable() undecryptable = %v, want %v", undecryptable, poisoned.ID)
+	}
+}
@@ -0,0 +1,217 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"notification_system/internal/entities"
+	"notification_system/pkg/database"
+	"notification_system/pkg/envelope"
+)
+
+const frequencyCapColumns = "id, tenant_id, delivery_type, category, max_count, period_seconds, policy, updated_at"
+
+type FrequencyCapPostgresRepository struct {
+	db     *database.PostgresDatabase
+	cipher *envelope.Cipher
+}
+
+func NewFrequencyCapPostgresRepository(db *database.PostgresDatabase, cipher *envelope.Cipher) FrequencyCapRepository {
+	return &FrequencyCapPostgresRepository{db: db, cipher: cipher}
+}
+
+func (r *FrequencyCapPostgresRepository) GetFrequencyCaps(ctx context.Context, tenantID *uuid.UUID) ([]*entities.FrequencyCap, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from frequency_caps
+		where tenant_key = $1
+		order by delivery_type, category
+	`, frequencyCapColumns)
+	rows, err := r.db.Pool.Query(ctx, query, tenantKey(tenantID))
+	if err != nil {
+		return nil, fmt.Errorf("FrequencyCapPostgresRepository.GetFrequencyCaps query error: %w", err)
+	}
+	defer rows.Close()
+
+	caps := make([]*entities.FrequencyCap, 0)
+	for rows.Next() {
+		frequencyCap := &entities.FrequencyCap{}
+		if err := scanFrequencyCap(rows, frequencyCap); err != nil {
+			return nil, fmt.Errorf("FrequencyCapPostgresRepository.GetFrequencyCaps scan error: %w", err)
+		}
+		caps = append(caps, frequencyCap)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("FrequencyCapPostgresRepository.GetFrequencyCaps rows error: %w", err)
+	}
+	return caps, nil
+}
+
+func (r *FrequencyCapPostgresRepository) UpsertFrequencyCap(ctx context.Context, frequencyCap *entities.FrequencyCap) error {
+	query := fmt.Sprintf(`
+		insert into frequency_caps (tenant_id, delivery_type, category, max_count, period_seconds, policy)
+		values ($1, $2, $3, $4, $5, $6)
+		on conflict (tenant_key, delivery_type, category) do update
+		set max_count = excluded.max_count,
+			period_seconds = excluded.period_seconds,
+			policy = excluded.policy,
+			updated_at = now()
+		returning %s
+	`, frequencyCapColumns)
+	row := r.db.Pool.QueryRow(ctx, query,
+		frequencyCap.TenantID,
+		frequencyCap.DeliveryType,
+		frequencyCap.Category,
+		frequencyCap.MaxCount,
+		frequencyCap.PeriodSeconds,
+		frequencyCap.Policy,
+	)
+	if err := scanFrequencyCap(row, frequencyCap); err != nil {
+		return fmt.Errorf("FrequencyCapPostgresRepository.UpsertFrequencyCap error: %w", err)
+	}
+	return nil
+}
+
+func (r *FrequencyCapPostgresRepository) DeleteFrequencyCap(ctx context.Context, tenantID *uuid.UUID, deliveryType, category string) error {
+	query := `
+		delete from frequency_caps
+		where tenant_key = $1 and delivery_type = $2 and category = $3
+	`
+	tag, err := r.db.Pool.Exec(ctx, query, tenantKey(tenantID), deliveryType, category)
+	if err != nil {
+		return fmt.Errorf("FrequencyCapPostgresRepository.DeleteFrequencyCap error: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// TakeFrequencyTokens takes a token from the bucket of the recipient for every matching cap
+// of the tenant. Either all tokens are taken or none, in which case the first exceeded cap is
+// returned. With encryption at rest the buckets are keyed by the recipient hash instead of the address.
+func (r *FrequencyCapPostgresRepository) TakeFrequencyTokens(ctx context.Context, tenantID *uuid.UUID, deliveryType, category, recipient string) (*entities.FrequencyCapExceeded, error) {
+	if hash := recipientHash(r.cipher, recipient); hash != nil {
+		recipient = *hash
+	}
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("FrequencyCapPostgresRepository.TakeFrequencyTokens begin error: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	caps, err := matchingFrequencyCaps(ctx, tx, tenantID, deliveryType, category)
+	if err != nil {
+		return nil, fmt.Errorf("FrequencyCapPostgresRepository.TakeFrequencyTokens error: %w", err)
+	}
+	if len(caps) == 0 {
+		return nil, nil
+	}
+
+	takeQuery := `
+		insert into frequency_buckets (cap_id, delivery_type, recipient, tokens)
+		values ($1, $2, $3, $4::double precision - 1)
+		on conflict (cap_id, delivery_type, recipient) do update
+		set tokens = least($4, frequency_buckets.tokens
+				+ extract(epoch from now() - frequency_buckets.updated_at)::double precision * $5) - 1,
+			updated_at = now()
+		where least($4, frequency_buckets.tokens
+				+ extract(epoch from now() - frequency_buckets.updated_at)::double precision * $5) >= 1
+		returning tokens
+	`
+	waitQuery := `
+		select (1 - least($4, tokens + extract(epoch from now() - updated_at)::double precision * $5)) / $5
+		from frequency_buckets
+		where cap_id = $1 and delivery_type = $2 and recipient = $3
+	`
+	for _, frequencyCap := range caps {
+		capacity := float64(frequencyCap.MaxCount)
+		rate := capacity / float64(frequencyCap.PeriodSeconds)
+
+		var tokens float64
+		err := tx.QueryRow(ctx, takeQuery, frequencyCap.ID, deliveryType, recipient, capacity, rate).Scan(&tokens)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("FrequencyCapPostgresRepository.TakeFrequencyTokens take error: %w", err)
+		}
+		var waitSeconds float64
+		err = tx.QueryRow(ctx, waitQuery, frequencyCap.ID, deliveryType, recipient, capacity, rate).Scan(&waitSeconds)
+		if err != nil {
+			return nil, fmt.Errorf("FrequencyCapPostgresRepository.TakeFrequencyTokens wait error: %w", err)
+		}
+		return &entities.FrequencyCapExceeded{
+			Cap:        frequencyCap,
+			RetryAfter: time.Duration(waitSeconds * float64(time.Second)),
+		}, nil
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("FrequencyCapPostgresRepository.TakeFrequencyTokens commit error: %w", err)
+	}
+	return nil, nil
+}
+
+// DeleteIdleFrequencyBuckets removes buckets that have refilled completely, they are
+// recreated full on the next take.
+func (r *FrequencyCapPostgresRepository) DeleteIdleFrequencyBuckets(ctx context.Context, limit uint) (int, error) {
+	query := `
+		delete from frequency_buckets
+		where (cap_id, delivery_type, recipient) in (
+			select b.cap_id, b.delivery_type, b.recipient
+			from frequency_buckets b
+			join frequency_caps c on c.id = b.cap_id
+			where b.updated_at < now() - make_interval(secs => c.period_seconds)
+			limit $1
+		)
+	`
+	tag, err := r.db.Pool.Exec(ctx, query, limit)
+	if err != nil {
+		return 0, fmt.Errorf("FrequencyCapPostgresRepository.DeleteIdleFrequencyBuckets error: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// matchingFrequencyCaps returns the caps of the tenant for the channel and category in a stable
+// order so concurrent takes do not lock the buckets in opposite orders.
+func matchingFrequencyCaps(ctx context.Context, tx pgx.Tx, tenantID *uuid.UUID, deliveryType, category string) ([]*entities.FrequencyCap, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from frequency_caps
+		where tenant_key = $3 and delivery_type in ($1, '*') and category in ($2, '*')
+		order by id
+	`, frequencyCapColumns)
+	rows, err := tx.Query(ctx, query, deliveryType, category, tenantKey(tenantID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	caps := make([]*entities.FrequencyCap, 0)
+	for rows.Next() {
+		frequencyCap := &entities.FrequencyCap{}
+		if err := scanFrequencyCap(rows, frequencyCap); err != nil {
+			return nil, err
+		}
+		caps = append(caps, frequencyCap)
+	}
+	return caps, rows.Err()
+}
+
+func scanFrequencyCap(row pgx.Row, frequencyCap *entities.FrequencyCap) error {
+	return row.Scan(
+		&frequencyCap.ID,
+		&frequencyCap.TenantID,
+		&frequencyCap.DeliveryType,
+		&frequencyCap.Category,
+		&frequencyCap.MaxCount,
+		&frequencyCap.PeriodSeconds,
+		&frequencyCap.Policy,
+		&frequencyCap.UpdatedAt,
+	)
+}
@@ -0,0 +1,262 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"notification_system/internal/entities"
+	"notification_system/pkg/database"
+	"notification_system/pkg/envelope"
+)
+
+const importColumns = `id, status, format, delivery_type, mapping, template, priority, category,
+	total_rows, created_rows, failed_rows, error, created_at, updated_at, started_at, completed_at,
+	client_id, tenant_id`
+
+// importNotificationColumns are copied for every notification of an import
+var importNotificationColumns = []string{
+	"delivery_type", "recipient", "content", "priority", "user_id", "category", "status", "import_id", "recipient_hash",
+	"client_id", "tenant_id",
+}
+
+type ImportPostgresRepository struct {
+	db     *database.PostgresDatabase
+	cipher *envelope.Cipher
+}
+
+func NewImportPostgresRepository(db *database.PostgresDatabase, cipher *envelope.Cipher) ImportRepository {
+	return &ImportPostgresRepository{db: db, cipher: cipher}
+}
+
+func (r *ImportPostgresRepository) CreateImport(ctx context.Context, imp *entities.Import) error {
+	query := fmt.Sprintf(`
+		insert into imports (format, delivery_type, mapping, template, priority, category, client_id, tenant_id)
+		values ($1, $2, $3, $4, $5, $6, $7, $8)
+		returning %s
+	`, importColumns)
+	row := r.db.Pool.QueryRow(ctx, query,
+		imp.Format,
+		imp.DeliveryType,
+		imp.Mapping,
+		imp.Template,
+		imp.Priority,
+		imp.Category,
+		imp.ClientID,
+		imp.TenantID,
+	)
+	if err := scanImport(row, imp); err != nil {
+		return fmt.Errorf("ImportPostgresRepository.CreateImport error: %w", err)
+	}
+	return nil
+}
+
+// GetImports reads the imports of the tenant and the client, a nil filter reads all of them.
+func (r *ImportPostgresRepository) GetImports(ctx context.Context, tenantID, clientID *uuid.UUID, limit, offset uint) ([]*entities.Import, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from imports
+		where ($1::uuid is null or tenant_id = $1)
+			and ($2::uuid is null or client_id = $2)
+		order by created_at desc, id
+		limit $3 offset $4
+	`, importColumns)
+	rows, err := r.db.Pool.Query(ctx, query, tenantID, clientID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("ImportPostgresRepository.GetImports query error: %w", err)
+	}
+	defer rows.Close()
+
+	imps := make([]*entities.Import, 0)
+	for rows.Next() {
+		imp := &entities.Import{}
+		if err := scanImport(rows, imp); err != nil {
+			return nil, fmt.Errorf("ImportPostgresRepository.GetImports scan error: %w", err)
+		}
+		imps = append(imps, imp)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ImportPostgresRepository.GetImports rows error: %w", err)
+	}
+	return imps, nil
+}
+
+// GetImport returns the import of the tenant, a nil tenant reads the imports of every tenant.
+func (r *ImportPostgresRepository) GetImport(ctx context.Context, id uuid.UUID, tenantID *uuid.UUID) (*entities.Import, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from imports
+		where id = $1
+			and ($2::uuid is null or tenant_id = $2)
+	`, importColumns)
+	imp := &entities.Import{}
+	if err := scanImport(r.db.Pool.QueryRow(ctx, query, id, tenantID), imp); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("ImportPostgresRepository.GetImport error: %w", err)
+	}
+	return imp, nil
+}
+
+// StartImport marks an import awaiting its upload as processing, so a file is processed
+// at most once. It returns ErrNotFound when the import is not awaiting an upload.
+func (r *ImportPostgresRepository) StartImport(ctx context.Context, id uuid.UUID) (*entities.Import, error) {
+	query := fmt.Sprintf(`
+		update imports
+		set status = $2,
+			started_at = now(),
+			updated_at = now()
+		where id = $1 and status = $3
+		returning %s
+	`, importColumns)
+	imp := &entities.Import{}
+	row := r.db.Pool.QueryRow(ctx, query, id, entities.ImportStatusProcessing, entities.ImportStatusAwaitingUpload)
+	if err := scanImport(row, imp); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("ImportPostgresRepository.StartImport error: %w", err)
+	}
+	return imp, nil
+}
+
+// CopyImportBatch copies the notifications of a batch of rows with COPY, stores the row errors
+// and adds the batch to the counters of the import in one transaction. failed counts the
+// failed rows of the batch, which may be more than the row errors kept.
+// The notifications count against the quotas of the client of the import, a batch that
+// would exceed one is not copied and ErrQuotaExceeded is returned.
+func (r *ImportPostgresRepository) CopyImportBatch(ctx context.Context, imp *entities.Import, notifications []*entities.Notification, rowErrors []*entities.ImportRowError, failed int) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ImportPostgresRepository.CopyImportBatch begin error: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	source := pgx.CopyFromSlice(len(notifications), func(i int) ([]any, error) {
+		notification := notifications[i]
+		sealed, err := sealFields(ctx, r.cipher, notification.Recipient, notification.Content)
+		if err != nil {
+			return nil, err
+		}
+		return []any{
+			notification.DeliveryType,
+			sealed.recipient,
+			sealed.content,
+			notification.Priority,
+			notification.UserID,
+			notification.Category,
+			entities.StatusPending,
+			imp.ID,
+			sealed.recipientHash,
+			imp.ClientID,
+			imp.TenantID,
+		}, nil
+	})
+	created, err := tx.CopyFrom(ctx, pgx.Identifier{"notifications"}, importNotificationColumns, source)
+	if err != nil {
+		return fmt.Errorf("ImportPostgresRepository.CopyImportBatch copy error: %w", err)
+	}
+	if err := consumeOwnerQuota(ctx, tx, imp.ClientID, notificationCounts(notifications)); err != nil {
+		if errors.Is(err, ErrQuotaExceeded) {
+			return err
+		}
+		return fmt.Errorf("ImportPostgresRepository.CopyImportBatch quota %w", err)
+	}
+	if len(rowErrors) != 0 {
+		errorSource := pgx.CopyFromSlice(len(rowErrors), func(i int) ([]any, error) {
+			return []any{imp.ID, rowErrors[i].Line, rowErrors[i].Message}, nil
+		})
+		_, err := tx.CopyFrom(ctx, pgx.Identifier{"import_errors"}, []string{"import_id", "line", "message"}, errorSource)
+		if err != nil {
+			return fmt.Errorf("ImportPostgresRepository.CopyImportBatch copy errors error: %w", err)
+		}
+	}
+	query := fmt.Sprintf(`
+		update imports
+		set total_rows = total_rows + $2 + $3,
+			created_rows = created_rows + $2,
+			failed_rows = failed_rows + $3,
+			updated_at = now()
+		where id = $1
+		returning %s
+	`, importColumns)
+	if err := scanImport(tx.QueryRow(ctx, query, imp.ID, created, failed), imp); err != nil {
+		return fmt.Errorf("ImportPostgresRepository.CopyImportBatch update error: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("ImportPostgresRepository.CopyImportBatch commit error: %w", err)
+	}
+	return nil
+}
+
+// FinishImport sets the final status of a processing import, errMessage explains a failed one.
+func (r *ImportPostgresRepository) FinishImport(ctx context.Context, imp *entities.Import, status string, errMessage *string) error {
+	query := fmt.Sprintf(`
+		update imports
+		set status = $2,
+			error = $3,
+			completed_at = now(),
+			updated_at = now()
+		where id = $1
+		returning %s
+	`, importColumns)
+	if err := scanImport(r.db.Pool.QueryRow(ctx, query, imp.ID, status, errMessage), imp); err != nil {
+		return fmt.Errorf("ImportPostgresRepository.FinishImport error: %w", err)
+	}
+	return nil
+}
+
+func (r *ImportPostgresRepository) GetImportErrors(ctx context.Context, id uuid.UUID, limit, offset uint) ([]*entities.ImportRowError, error) {
+	query := `
+		select import_id, line, message
+		from import_errors
+		where import_id = $1
+		order by line
+		limit $2 offset $3
+	`
+	rows, err := r.db.Pool.Query(ctx, query, id, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("ImportPostgresRepository.GetImportErrors query error: %w", err)
+	}
+	defer rows.Close()
+
+	rowErrors := make([]*entities.ImportRowError, 0)
+	for rows.Next() {
+		rowError := &entities.ImportRowError{}
+		if err := rows.Scan(&rowError.ImportID, &rowError.Line, &rowError.Message); err != nil {
+			return nil, fmt.Errorf("ImportPostgresRepository.GetImportErrors scan error: %w", err)
+		}
+		rowErrors = append(rowErrors, rowError)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ImportPostgresRepository.GetImportErrors rows error: %w", err)
+	}
+	return rowErrors, nil
+}
+
+func scanImport(row pgx.Row, imp *entities.Import) error {
+	return row.Scan(
+		&imp.ID,
+		&imp.Status,
+		&imp.Format,
+		&imp.DeliveryType,
+		&imp.Mapping,
+		&imp.Template,
+		&imp.Priority,
+		&imp.Category,
+		&imp.TotalRows,
+		&imp.CreatedRows,
+		&imp.FailedRows,
+		&imp.Error,
+		&imp.CreatedAt,
+		&imp.UpdatedAt,
+		&imp.StartedAt,
+		&imp.CompletedAt,
+		&imp.ClientID,
+		&imp.TenantID,
+	)
+}
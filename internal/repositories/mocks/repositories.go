@@ -11,8 +11,10 @@ package repomocks
 
 import (
 	context "context"
+	json "encoding/json"
 	entities "notification_system/internal/entities"
 	reflect "reflect"
+	time "time"
 
 	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
@@ -42,6 +44,35 @@ func (m *MockNotificationRepository) EXPECT() *MockNotificationRepositoryMockRec
 	return m.recorder
 }
 
+// ClaimNotification mocks base method.
+func (m *MockNotificationRepository) ClaimNotification(ctx context.Context, id uuid.UUID) (*entities.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimNotification", ctx, id)
+	ret0, _ := ret[0].(*entities.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimNotification indicates an expected call of ClaimNotification.
+func (mr *MockNotificationRepositoryMockRecorder) ClaimNotification(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimNotification", reflect.TypeOf((*MockNotificationRepository)(nil).ClaimNotification), ctx, id)
+}
+
+// CreateNotificationBatch mocks base method.
+func (m *MockNotificationRepository) CreateNotificationBatch(ctx context.Context, notifications []*entities.Notification, chains []*entities.NotificationChain) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNotificationBatch", ctx, notifications, chains)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateNotificationBatch indicates an expected call of CreateNotificationBatch.
+func (mr *MockNotificationRepositoryMockRecorder) CreateNotificationBatch(ctx, notifications, chains any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNotificationBatch", reflect.TypeOf((*MockNotificationRepository)(nil).CreateNotificationBatch), ctx, notifications, chains)
+}
+
 // CreateNotifications mocks base method.
 func (m *MockNotificationRepository) CreateNotifications(ctx context.Context, notifications []*entities.Notification) error {
 	m.ctrl.T.Helper()
@@ -56,49 +87,106 @@ func (mr *MockNotificationRepositoryMockRecorder) CreateNotifications(ctx, notif
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNotifications", reflect.TypeOf((*MockNotificationRepository)(nil).CreateNotifications), ctx, notifications)
 }
 
+// DeferNotification mocks base method.
+func (m *MockNotificationRepository) DeferNotification(ctx context.Context, id uuid.UUID, until time.Time, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeferNotification", ctx, id, until, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeferNotification indicates an expected call of DeferNotification.
+func (mr *MockNotificationRepositoryMockRecorder) DeferNotification(ctx, id, until, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeferNotification", reflect.TypeOf((*MockNotificationRepository)(nil).DeferNotification), ctx, id, until, reason)
+}
+
 // GetNewNotifications mocks base method.
-func (m *MockNotificationRepository) GetNewNotifications(ctx context.Context, limit uint) ([]*entities.Notification, error) {
+func (m *MockNotificationRepository) GetNewNotifications(ctx context.Context, limit uint, tenantID *uuid.UUID) ([]*entities.Notification, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetNewNotifications", ctx, limit)
+	ret := m.ctrl.Call(m, "GetNewNotifications", ctx, limit, tenantID)
 	ret0, _ := ret[0].([]*entities.Notification)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetNewNotifications indicates an expected call of GetNewNotifications.
-func (mr *MockNotificationRepositoryMockRecorder) GetNewNotifications(ctx, limit any) *gomock.Call {
+func (mr *MockNotificationRepositoryMockRecorder) GetNewNotifications(ctx, limit, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNewNotifications", reflect.TypeOf((*MockNotificationRepository)(nil).GetNewNotifications), ctx, limit, tenantID)
+}
+
+// GetNewNotificationsByClientID mocks base method.
+func (m *MockNotificationRepository) GetNewNotificationsByClientID(ctx context.Context, clientID uuid.UUID, limit uint) ([]*entities.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNewNotificationsByClientID", ctx, clientID, limit)
+	ret0, _ := ret[0].([]*entities.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNewNotificationsByClientID indicates an expected call of GetNewNotificationsByClientID.
+func (mr *MockNotificationRepositoryMockRecorder) GetNewNotificationsByClientID(ctx, clientID, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNewNotifications", reflect.TypeOf((*MockNotificationRepository)(nil).GetNewNotifications), ctx, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNewNotificationsByClientID", reflect.TypeOf((*MockNotificationRepository)(nil).GetNewNotificationsByClientID), ctx, clientID, limit)
 }
 
 // GetNotificationByID mocks base method.
-func (m *MockNotificationRepository) GetNotificationByID(ctx context.Context, id uuid.UUID) (*entities.Notification, error) {
+func (m *MockNotificationRepository) GetNotificationByID(ctx context.Context, id uuid.UUID, tenantID *uuid.UUID) (*entities.Notification, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetNotificationByID", ctx, id)
+	ret := m.ctrl.Call(m, "GetNotificationByID", ctx, id, tenantID)
 	ret0, _ := ret[0].(*entities.Notification)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetNotificationByID indicates an expected call of GetNotificationByID.
-func (mr *MockNotificationRepositoryMockRecorder) GetNotificationByID(ctx, id any) *gomock.Call {
+func (mr *MockNotificationRepositoryMockRecorder) GetNotificationByID(ctx, id, tenantID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationByID", reflect.TypeOf((*MockNotificationRepository)(nil).GetNotificationByID), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationByID", reflect.TypeOf((*MockNotificationRepository)(nil).GetNotificationByID), ctx, id, tenantID)
 }
 
 // GetNotificationsByIDs mocks base method.
-func (m *MockNotificationRepository) GetNotificationsByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.Notification, error) {
+func (m *MockNotificationRepository) GetNotificationsByIDs(ctx context.Context, ids []uuid.UUID, tenantID *uuid.UUID) ([]*entities.Notification, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetNotificationsByIDs", ctx, ids)
+	ret := m.ctrl.Call(m, "GetNotificationsByIDs", ctx, ids, tenantID)
 	ret0, _ := ret[0].([]*entities.Notification)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetNotificationsByIDs indicates an expected call of GetNotificationsByIDs.
-func (mr *MockNotificationRepositoryMockRecorder) GetNotificationsByIDs(ctx, ids any) *gomock.Call {
+func (mr *MockNotificationRepositoryMockRecorder) GetNotificationsByIDs(ctx, ids, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationsByIDs", reflect.TypeOf((*MockNotificationRepository)(nil).GetNotificationsByIDs), ctx, ids, tenantID)
+}
+
+// QueueNotifications mocks base method.
+func (m *MockNotificationRepository) QueueNotifications(ctx context.Context, ids []uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueNotifications", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// QueueNotifications indicates an expected call of QueueNotifications.
+func (mr *MockNotificationRepositoryMockRecorder) QueueNotifications(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueNotifications", reflect.TypeOf((*MockNotificationRepository)(nil).QueueNotifications), ctx, ids)
+}
+
+// UpdateNotificationNextAttemptAt mocks base method.
+func (m *MockNotificationRepository) UpdateNotificationNextAttemptAt(ctx context.Context, id uuid.UUID, nextAttemptAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNotificationNextAttemptAt", ctx, id, nextAttemptAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateNotificationNextAttemptAt indicates an expected call of UpdateNotificationNextAttemptAt.
+func (mr *MockNotificationRepositoryMockRecorder) UpdateNotificationNextAttemptAt(ctx, id, nextAttemptAt any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationsByIDs", reflect.TypeOf((*MockNotificationRepository)(nil).GetNotificationsByIDs), ctx, ids)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNotificationNextAttemptAt", reflect.TypeOf((*MockNotificationRepository)(nil).UpdateNotificationNextAttemptAt), ctx, id, nextAttemptAt)
 }
 
 // UpdateNotificationRetries mocks base method.
@@ -115,6 +203,20 @@ func (mr *MockNotificationRepositoryMockRecorder) UpdateNotificationRetries(ctx,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNotificationRetries", reflect.TypeOf((*MockNotificationRepository)(nil).UpdateNotificationRetries), ctx, id, retries)
 }
 
+// UpdateNotificationStatusWithReason mocks base method.
+func (m *MockNotificationRepository) UpdateNotificationStatusWithReason(ctx context.Context, id uuid.UUID, status, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNotificationStatusWithReason", ctx, id, status, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateNotificationStatusWithReason indicates an expected call of UpdateNotificationStatusWithReason.
+func (mr *MockNotificationRepositoryMockRecorder) UpdateNotificationStatusWithReason(ctx, id, status, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNotificationStatusWithReason", reflect.TypeOf((*MockNotificationRepository)(nil).UpdateNotificationStatusWithReason), ctx, id, status, reason)
+}
+
 // UpdateNotificationsStatus mocks base method.
 func (m *MockNotificationRepository) UpdateNotificationsStatus(ctx context.Context, ids []uuid.UUID, status string) error {
 	m.ctrl.T.Helper()
@@ -128,3 +230,1969 @@ func (mr *MockNotificationRepositoryMockRecorder) UpdateNotificationsStatus(ctx,
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNotificationsStatus", reflect.TypeOf((*MockNotificationRepository)(nil).UpdateNotificationsStatus), ctx, ids, status)
 }
+
+// MockSuppressionRepository is a mock of SuppressionRepository interface.
+type MockSuppressionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSuppressionRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockSuppressionRepositoryMockRecorder is the mock recorder for MockSuppressionRepository.
+type MockSuppressionRepositoryMockRecorder struct {
+	mock *MockSuppressionRepository
+}
+
+// NewMockSuppressionRepository creates a new mock instance.
+func NewMockSuppressionRepository(ctrl *gomock.Controller) *MockSuppressionRepository {
+	mock := &MockSuppressionRepository{ctrl: ctrl}
+	mock.recorder = &MockSuppressionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSuppressionRepository) EXPECT() *MockSuppressionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateSuppression mocks base method.
+func (m *MockSuppressionRepository) CreateSuppression(ctx context.Context, suppression *entities.Suppression) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSuppression", ctx, suppression)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSuppression indicates an expected call of CreateSuppression.
+func (mr *MockSuppressionRepositoryMockRecorder) CreateSuppression(ctx, suppression any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSuppression", reflect.TypeOf((*MockSuppressionRepository)(nil).CreateSuppression), ctx, suppression)
+}
+
+// DeleteSuppression mocks base method.
+func (m *MockSuppressionRepository) DeleteSuppression(ctx context.Context, tenantID *uuid.UUID, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSuppression", ctx, tenantID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSuppression indicates an expected call of DeleteSuppression.
+func (mr *MockSuppressionRepositoryMockRecorder) DeleteSuppression(ctx, tenantID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSuppression", reflect.TypeOf((*MockSuppressionRepository)(nil).DeleteSuppression), ctx, tenantID, id)
+}
+
+// GetActiveSuppression mocks base method.
+func (m *MockSuppressionRepository) GetActiveSuppression(ctx context.Context, tenantID *uuid.UUID, deliveryType, address string) (*entities.Suppression, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveSuppression", ctx, tenantID, deliveryType, address)
+	ret0, _ := ret[0].(*entities.Suppression)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveSuppression indicates an expected call of GetActiveSuppression.
+func (mr *MockSuppressionRepositoryMockRecorder) GetActiveSuppression(ctx, tenantID, deliveryType, address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveSuppression", reflect.TypeOf((*MockSuppressionRepository)(nil).GetActiveSuppression), ctx, tenantID, deliveryType, address)
+}
+
+// GetSuppression mocks base method.
+func (m *MockSuppressionRepository) GetSuppression(ctx context.Context, tenantID *uuid.UUID, id uuid.UUID) (*entities.Suppression, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSuppression", ctx, tenantID, id)
+	ret0, _ := ret[0].(*entities.Suppression)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSuppression indicates an expected call of GetSuppression.
+func (mr *MockSuppressionRepositoryMockRecorder) GetSuppression(ctx, tenantID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSuppression", reflect.TypeOf((*MockSuppressionRepository)(nil).GetSuppression), ctx, tenantID, id)
+}
+
+// ImportSuppressions mocks base method.
+func (m *MockSuppressionRepository) ImportSuppressions(ctx context.Context, tenantID *uuid.UUID, suppressions []*entities.Suppression) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportSuppressions", ctx, tenantID, suppressions)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportSuppressions indicates an expected call of ImportSuppressions.
+func (mr *MockSuppressionRepositoryMockRecorder) ImportSuppressions(ctx, tenantID, suppressions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportSuppressions", reflect.TypeOf((*MockSuppressionRepository)(nil).ImportSuppressions), ctx, tenantID, suppressions)
+}
+
+// SearchSuppressions mocks base method.
+func (m *MockSuppressionRepository) SearchSuppressions(ctx context.Context, filter *entities.SuppressionFilter) ([]*entities.Suppression, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchSuppressions", ctx, filter)
+	ret0, _ := ret[0].([]*entities.Suppression)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchSuppressions indicates an expected call of SearchSuppressions.
+func (mr *MockSuppressionRepositoryMockRecorder) SearchSuppressions(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchSuppressions", reflect.TypeOf((*MockSuppressionRepository)(nil).SearchSuppressions), ctx, filter)
+}
+
+// MockWebPushSubscriptionRepository is a mock of WebPushSubscriptionRepository interface.
+type MockWebPushSubscriptionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebPushSubscriptionRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockWebPushSubscriptionRepositoryMockRecorder is the mock recorder for MockWebPushSubscriptionRepository.
+type MockWebPushSubscriptionRepositoryMockRecorder struct {
+	mock *MockWebPushSubscriptionRepository
+}
+
+// NewMockWebPushSubscriptionRepository creates a new mock instance.
+func NewMockWebPushSubscriptionRepository(ctrl *gomock.Controller) *MockWebPushSubscriptionRepository {
+	mock := &MockWebPushSubscriptionRepository{ctrl: ctrl}
+	mock.recorder = &MockWebPushSubscriptionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebPushSubscriptionRepository) EXPECT() *MockWebPushSubscriptionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateWebPushSubscription mocks base method.
+func (m *MockWebPushSubscriptionRepository) CreateWebPushSubscription(ctx context.Context, subscription *entities.WebPushSubscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebPushSubscription", ctx, subscription)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWebPushSubscription indicates an expected call of CreateWebPushSubscription.
+func (mr *MockWebPushSubscriptionRepositoryMockRecorder) CreateWebPushSubscription(ctx, subscription any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebPushSubscription", reflect.TypeOf((*MockWebPushSubscriptionRepository)(nil).CreateWebPushSubscription), ctx, subscription)
+}
+
+// DeleteWebPushSubscription mocks base method.
+func (m *MockWebPushSubscriptionRepository) DeleteWebPushSubscription(ctx context.Context, tenantID *uuid.UUID, userID, endpoint string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebPushSubscription", ctx, tenantID, userID, endpoint)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebPushSubscription indicates an expected call of DeleteWebPushSubscription.
+func (mr *MockWebPushSubscriptionRepositoryMockRecorder) DeleteWebPushSubscription(ctx, tenantID, userID, endpoint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebPushSubscription", reflect.TypeOf((*MockWebPushSubscriptionRepository)(nil).DeleteWebPushSubscription), ctx, tenantID, userID, endpoint)
+}
+
+// DeleteWebPushSubscriptionByEndpoint mocks base method.
+func (m *MockWebPushSubscriptionRepository) DeleteWebPushSubscriptionByEndpoint(ctx context.Context, tenantID *uuid.UUID, endpoint string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebPushSubscriptionByEndpoint", ctx, tenantID, endpoint)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebPushSubscriptionByEndpoint indicates an expected call of DeleteWebPushSubscriptionByEndpoint.
+func (mr *MockWebPushSubscriptionRepositoryMockRecorder) DeleteWebPushSubscriptionByEndpoint(ctx, tenantID, endpoint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebPushSubscriptionByEndpoint", reflect.TypeOf((*MockWebPushSubscriptionRepository)(nil).DeleteWebPushSubscriptionByEndpoint), ctx, tenantID, endpoint)
+}
+
+// GetWebPushSubscriptionsByUserID mocks base method.
+func (m *MockWebPushSubscriptionRepository) GetWebPushSubscriptionsByUserID(ctx context.Context, tenantID *uuid.UUID, userID string) ([]*entities.WebPushSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebPushSubscriptionsByUserID", ctx, tenantID, userID)
+	ret0, _ := ret[0].([]*entities.WebPushSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebPushSubscriptionsByUserID indicates an expected call of GetWebPushSubscriptionsByUserID.
+func (mr *MockWebPushSubscriptionRepositoryMockRecorder) GetWebPushSubscriptionsByUserID(ctx, tenantID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebPushSubscriptionsByUserID", reflect.TypeOf((*MockWebPushSubscriptionRepository)(nil).GetWebPushSubscriptionsByUserID), ctx, tenantID, userID)
+}
+
+// MockNotificationChainRepository is a mock of NotificationChainRepository interface.
+type MockNotificationChainRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationChainRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockNotificationChainRepositoryMockRecorder is the mock recorder for MockNotificationChainRepository.
+type MockNotificationChainRepositoryMockRecorder struct {
+	mock *MockNotificationChainRepository
+}
+
+// NewMockNotificationChainRepository creates a new mock instance.
+func NewMockNotificationChainRepository(ctrl *gomock.Controller) *MockNotificationChainRepository {
+	mock := &MockNotificationChainRepository{ctrl: ctrl}
+	mock.recorder = &MockNotificationChainRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationChainRepository) EXPECT() *MockNotificationChainRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AdvanceNotificationChain mocks base method.
+func (m *MockNotificationChainRepository) AdvanceNotificationChain(ctx context.Context, parentID uuid.UUID, step int16, timedOut bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdvanceNotificationChain", ctx, parentID, step, timedOut)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdvanceNotificationChain indicates an expected call of AdvanceNotificationChain.
+func (mr *MockNotificationChainRepositoryMockRecorder) AdvanceNotificationChain(ctx, parentID, step, timedOut any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdvanceNotificationChain", reflect.TypeOf((*MockNotificationChainRepository)(nil).AdvanceNotificationChain), ctx, parentID, step, timedOut)
+}
+
+// CompleteNotificationChain mocks base method.
+func (m *MockNotificationChainRepository) CompleteNotificationChain(ctx context.Context, parentID uuid.UUID, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteNotificationChain", ctx, parentID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteNotificationChain indicates an expected call of CompleteNotificationChain.
+func (mr *MockNotificationChainRepositoryMockRecorder) CompleteNotificationChain(ctx, parentID, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteNotificationChain", reflect.TypeOf((*MockNotificationChainRepository)(nil).CompleteNotificationChain), ctx, parentID, status)
+}
+
+// ExpireTimedOutChainSteps mocks base method.
+func (m *MockNotificationChainRepository) ExpireTimedOutChainSteps(ctx context.Context, limit uint) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireTimedOutChainSteps", ctx, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireTimedOutChainSteps indicates an expected call of ExpireTimedOutChainSteps.
+func (mr *MockNotificationChainRepositoryMockRecorder) ExpireTimedOutChainSteps(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireTimedOutChainSteps", reflect.TypeOf((*MockNotificationChainRepository)(nil).ExpireTimedOutChainSteps), ctx, limit)
+}
+
+// GetNotificationChannels mocks base method.
+func (m *MockNotificationChainRepository) GetNotificationChannels(ctx context.Context, notificationID uuid.UUID) ([]*entities.NotificationChannel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationChannels", ctx, notificationID)
+	ret0, _ := ret[0].([]*entities.NotificationChannel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationChannels indicates an expected call of GetNotificationChannels.
+func (mr *MockNotificationChainRepositoryMockRecorder) GetNotificationChannels(ctx, notificationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationChannels", reflect.TypeOf((*MockNotificationChainRepository)(nil).GetNotificationChannels), ctx, notificationID)
+}
+
+// GetNotificationsByParentID mocks base method.
+func (m *MockNotificationChainRepository) GetNotificationsByParentID(ctx context.Context, parentID uuid.UUID) ([]*entities.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationsByParentID", ctx, parentID)
+	ret0, _ := ret[0].([]*entities.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationsByParentID indicates an expected call of GetNotificationsByParentID.
+func (mr *MockNotificationChainRepositoryMockRecorder) GetNotificationsByParentID(ctx, parentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationsByParentID", reflect.TypeOf((*MockNotificationChainRepository)(nil).GetNotificationsByParentID), ctx, parentID)
+}
+
+// MockContactRepository is a mock of ContactRepository interface.
+type MockContactRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockContactRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockContactRepositoryMockRecorder is the mock recorder for MockContactRepository.
+type MockContactRepositoryMockRecorder struct {
+	mock *MockContactRepository
+}
+
+// NewMockContactRepository creates a new mock instance.
+func NewMockContactRepository(ctrl *gomock.Controller) *MockContactRepository {
+	mock := &MockContactRepository{ctrl: ctrl}
+	mock.recorder = &MockContactRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContactRepository) EXPECT() *MockContactRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateContact mocks base method.
+func (m *MockContactRepository) CreateContact(ctx context.Context, contact *entities.Contact, addresses []*entities.ContactAddress) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateContact", ctx, contact, addresses)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateContact indicates an expected call of CreateContact.
+func (mr *MockContactRepositoryMockRecorder) CreateContact(ctx, contact, addresses any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateContact", reflect.TypeOf((*MockContactRepository)(nil).CreateContact), ctx, contact, addresses)
+}
+
+// CreateContactAddress mocks base method.
+func (m *MockContactRepository) CreateContactAddress(ctx context.Context, address *entities.ContactAddress) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateContactAddress", ctx, address)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateContactAddress indicates an expected call of CreateContactAddress.
+func (mr *MockContactRepositoryMockRecorder) CreateContactAddress(ctx, address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateContactAddress", reflect.TypeOf((*MockContactRepository)(nil).CreateContactAddress), ctx, address)
+}
+
+// DeleteContact mocks base method.
+func (m *MockContactRepository) DeleteContact(ctx context.Context, tenantID *uuid.UUID, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteContact", ctx, tenantID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteContact indicates an expected call of DeleteContact.
+func (mr *MockContactRepositoryMockRecorder) DeleteContact(ctx, tenantID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteContact", reflect.TypeOf((*MockContactRepository)(nil).DeleteContact), ctx, tenantID, userID)
+}
+
+// DeleteContactAddress mocks base method.
+func (m *MockContactRepository) DeleteContactAddress(ctx context.Context, tenantID *uuid.UUID, userID string, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteContactAddress", ctx, tenantID, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteContactAddress indicates an expected call of DeleteContactAddress.
+func (mr *MockContactRepositoryMockRecorder) DeleteContactAddress(ctx, tenantID, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteContactAddress", reflect.TypeOf((*MockContactRepository)(nil).DeleteContactAddress), ctx, tenantID, userID, id)
+}
+
+// GetContact mocks base method.
+func (m *MockContactRepository) GetContact(ctx context.Context, tenantID *uuid.UUID, userID string) (*entities.Contact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContact", ctx, tenantID, userID)
+	ret0, _ := ret[0].(*entities.Contact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContact indicates an expected call of GetContact.
+func (mr *MockContactRepositoryMockRecorder) GetContact(ctx, tenantID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContact", reflect.TypeOf((*MockContactRepository)(nil).GetContact), ctx, tenantID, userID)
+}
+
+// GetContactAddresses mocks base method.
+func (m *MockContactRepository) GetContactAddresses(ctx context.Context, tenantID *uuid.UUID, userID string) ([]*entities.ContactAddress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContactAddresses", ctx, tenantID, userID)
+	ret0, _ := ret[0].([]*entities.ContactAddress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContactAddresses indicates an expected call of GetContactAddresses.
+func (mr *MockContactRepositoryMockRecorder) GetContactAddresses(ctx, tenantID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContactAddresses", reflect.TypeOf((*MockContactRepository)(nil).GetContactAddresses), ctx, tenantID, userID)
+}
+
+// ResolveAddress mocks base method.
+func (m *MockContactRepository) ResolveAddress(ctx context.Context, tenantID *uuid.UUID, userID, deliveryType string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveAddress", ctx, tenantID, userID, deliveryType)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveAddress indicates an expected call of ResolveAddress.
+func (mr *MockContactRepositoryMockRecorder) ResolveAddress(ctx, tenantID, userID, deliveryType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveAddress", reflect.TypeOf((*MockContactRepository)(nil).ResolveAddress), ctx, tenantID, userID, deliveryType)
+}
+
+// SetVerificationCode mocks base method.
+func (m *MockContactRepository) SetVerificationCode(ctx context.Context, tenantID *uuid.UUID, userID string, id uuid.UUID, codeHash string, expiresAt time.Time) (*entities.ContactAddress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetVerificationCode", ctx, tenantID, userID, id, codeHash, expiresAt)
+	ret0, _ := ret[0].(*entities.ContactAddress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetVerificationCode indicates an expected call of SetVerificationCode.
+func (mr *MockContactRepositoryMockRecorder) SetVerificationCode(ctx, tenantID, userID, id, codeHash, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVerificationCode", reflect.TypeOf((*MockContactRepository)(nil).SetVerificationCode), ctx, tenantID, userID, id, codeHash, expiresAt)
+}
+
+// UpdateContact mocks base method.
+func (m *MockContactRepository) UpdateContact(ctx context.Context, contact *entities.Contact) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateContact", ctx, contact)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateContact indicates an expected call of UpdateContact.
+func (mr *MockContactRepositoryMockRecorder) UpdateContact(ctx, contact any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateContact", reflect.TypeOf((*MockContactRepository)(nil).UpdateContact), ctx, contact)
+}
+
+// UpdateContactAddress mocks base method.
+func (m *MockContactRepository) UpdateContactAddress(ctx context.Context, address *entities.ContactAddress) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateContactAddress", ctx, address)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateContactAddress indicates an expected call of UpdateContactAddress.
+func (mr *MockContactRepositoryMockRecorder) UpdateContactAddress(ctx, address any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateContactAddress", reflect.TypeOf((*MockContactRepository)(nil).UpdateContactAddress), ctx, address)
+}
+
+// VerifyContactAddress mocks base method.
+func (m *MockContactRepository) VerifyContactAddress(ctx context.Context, tenantID *uuid.UUID, userID string, id uuid.UUID, codeHash string, maxAttempts int32) (*entities.ContactAddress, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyContactAddress", ctx, tenantID, userID, id, codeHash, maxAttempts)
+	ret0, _ := ret[0].(*entities.ContactAddress)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyContactAddress indicates an expected call of VerifyContactAddress.
+func (mr *MockContactRepositoryMockRecorder) VerifyContactAddress(ctx, tenantID, userID, id, codeHash, maxAttempts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyContactAddress", reflect.TypeOf((*MockContactRepository)(nil).VerifyContactAddress), ctx, tenantID, userID, id, codeHash, maxAttempts)
+}
+
+// MockPreferenceRepository is a mock of PreferenceRepository interface.
+type MockPreferenceRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPreferenceRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockPreferenceRepositoryMockRecorder is the mock recorder for MockPreferenceRepository.
+type MockPreferenceRepositoryMockRecorder struct {
+	mock *MockPreferenceRepository
+}
+
+// NewMockPreferenceRepository creates a new mock instance.
+func NewMockPreferenceRepository(ctrl *gomock.Controller) *MockPreferenceRepository {
+	mock := &MockPreferenceRepository{ctrl: ctrl}
+	mock.recorder = &MockPreferenceRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPreferenceRepository) EXPECT() *MockPreferenceRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateUnsubscribe mocks base method.
+func (m *MockPreferenceRepository) CreateUnsubscribe(ctx context.Context, tenantID *uuid.UUID, deliveryType, address, category string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUnsubscribe", ctx, tenantID, deliveryType, address, category)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUnsubscribe indicates an expected call of CreateUnsubscribe.
+func (mr *MockPreferenceRepositoryMockRecorder) CreateUnsubscribe(ctx, tenantID, deliveryType, address, category any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUnsubscribe", reflect.TypeOf((*MockPreferenceRepository)(nil).CreateUnsubscribe), ctx, tenantID, deliveryType, address, category)
+}
+
+// DeletePreference mocks base method.
+func (m *MockPreferenceRepository) DeletePreference(ctx context.Context, tenantID *uuid.UUID, userID, category, deliveryType string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePreference", ctx, tenantID, userID, category, deliveryType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePreference indicates an expected call of DeletePreference.
+func (mr *MockPreferenceRepositoryMockRecorder) DeletePreference(ctx, tenantID, userID, category, deliveryType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePreference", reflect.TypeOf((*MockPreferenceRepository)(nil).DeletePreference), ctx, tenantID, userID, category, deliveryType)
+}
+
+// GetCategories mocks base method.
+func (m *MockPreferenceRepository) GetCategories(ctx context.Context) ([]*entities.NotificationCategory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategories", ctx)
+	ret0, _ := ret[0].([]*entities.NotificationCategory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategories indicates an expected call of GetCategories.
+func (mr *MockPreferenceRepositoryMockRecorder) GetCategories(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategories", reflect.TypeOf((*MockPreferenceRepository)(nil).GetCategories), ctx)
+}
+
+// GetPreferences mocks base method.
+func (m *MockPreferenceRepository) GetPreferences(ctx context.Context, tenantID *uuid.UUID, userID string) ([]*entities.NotificationPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPreferences", ctx, tenantID, userID)
+	ret0, _ := ret[0].([]*entities.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPreferences indicates an expected call of GetPreferences.
+func (mr *MockPreferenceRepositoryMockRecorder) GetPreferences(ctx, tenantID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreferences", reflect.TypeOf((*MockPreferenceRepository)(nil).GetPreferences), ctx, tenantID, userID)
+}
+
+// GetSuppressionReason mocks base method.
+func (m *MockPreferenceRepository) GetSuppressionReason(ctx context.Context, notification *entities.Notification) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSuppressionReason", ctx, notification)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSuppressionReason indicates an expected call of GetSuppressionReason.
+func (mr *MockPreferenceRepositoryMockRecorder) GetSuppressionReason(ctx, notification any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSuppressionReason", reflect.TypeOf((*MockPreferenceRepository)(nil).GetSuppressionReason), ctx, notification)
+}
+
+// IsCategorySuppressible mocks base method.
+func (m *MockPreferenceRepository) IsCategorySuppressible(ctx context.Context, name string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsCategorySuppressible", ctx, name)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsCategorySuppressible indicates an expected call of IsCategorySuppressible.
+func (mr *MockPreferenceRepositoryMockRecorder) IsCategorySuppressible(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsCategorySuppressible", reflect.TypeOf((*MockPreferenceRepository)(nil).IsCategorySuppressible), ctx, name)
+}
+
+// UpsertCategory mocks base method.
+func (m *MockPreferenceRepository) UpsertCategory(ctx context.Context, category *entities.NotificationCategory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertCategory", ctx, category)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertCategory indicates an expected call of UpsertCategory.
+func (mr *MockPreferenceRepositoryMockRecorder) UpsertCategory(ctx, category any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertCategory", reflect.TypeOf((*MockPreferenceRepository)(nil).UpsertCategory), ctx, category)
+}
+
+// UpsertPreferences mocks base method.
+func (m *MockPreferenceRepository) UpsertPreferences(ctx context.Context, preferences []*entities.NotificationPreference) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertPreferences", ctx, preferences)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertPreferences indicates an expected call of UpsertPreferences.
+func (mr *MockPreferenceRepositoryMockRecorder) UpsertPreferences(ctx, preferences any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertPreferences", reflect.TypeOf((*MockPreferenceRepository)(nil).UpsertPreferences), ctx, preferences)
+}
+
+// MockQuietHoursRepository is a mock of QuietHoursRepository interface.
+type MockQuietHoursRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuietHoursRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockQuietHoursRepositoryMockRecorder is the mock recorder for MockQuietHoursRepository.
+type MockQuietHoursRepositoryMockRecorder struct {
+	mock *MockQuietHoursRepository
+}
+
+// NewMockQuietHoursRepository creates a new mock instance.
+func NewMockQuietHoursRepository(ctrl *gomock.Controller) *MockQuietHoursRepository {
+	mock := &MockQuietHoursRepository{ctrl: ctrl}
+	mock.recorder = &MockQuietHoursRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuietHoursRepository) EXPECT() *MockQuietHoursRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteQuietHours mocks base method.
+func (m *MockQuietHoursRepository) DeleteQuietHours(ctx context.Context, tenantID *uuid.UUID, userID, category string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteQuietHours", ctx, tenantID, userID, category)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteQuietHours indicates an expected call of DeleteQuietHours.
+func (mr *MockQuietHoursRepositoryMockRecorder) DeleteQuietHours(ctx, tenantID, userID, category any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteQuietHours", reflect.TypeOf((*MockQuietHoursRepository)(nil).DeleteQuietHours), ctx, tenantID, userID, category)
+}
+
+// GetQuietHours mocks base method.
+func (m *MockQuietHoursRepository) GetQuietHours(ctx context.Context, tenantID *uuid.UUID, userID, category string) ([]*entities.QuietHours, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuietHours", ctx, tenantID, userID, category)
+	ret0, _ := ret[0].([]*entities.QuietHours)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuietHours indicates an expected call of GetQuietHours.
+func (mr *MockQuietHoursRepositoryMockRecorder) GetQuietHours(ctx, tenantID, userID, category any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuietHours", reflect.TypeOf((*MockQuietHoursRepository)(nil).GetQuietHours), ctx, tenantID, userID, category)
+}
+
+// GetQuietHoursForNotifications mocks base method.
+func (m *MockQuietHoursRepository) GetQuietHoursForNotifications(ctx context.Context, notifications []*entities.Notification) (map[uuid.UUID]*entities.QuietHours, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuietHoursForNotifications", ctx, notifications)
+	ret0, _ := ret[0].(map[uuid.UUID]*entities.QuietHours)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuietHoursForNotifications indicates an expected call of GetQuietHoursForNotifications.
+func (mr *MockQuietHoursRepositoryMockRecorder) GetQuietHoursForNotifications(ctx, notifications any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuietHoursForNotifications", reflect.TypeOf((*MockQuietHoursRepository)(nil).GetQuietHoursForNotifications), ctx, notifications)
+}
+
+// UpsertQuietHours mocks base method.
+func (m *MockQuietHoursRepository) UpsertQuietHours(ctx context.Context, quietHours *entities.QuietHours) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertQuietHours", ctx, quietHours)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertQuietHours indicates an expected call of UpsertQuietHours.
+func (mr *MockQuietHoursRepositoryMockRecorder) UpsertQuietHours(ctx, quietHours any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertQuietHours", reflect.TypeOf((*MockQuietHoursRepository)(nil).UpsertQuietHours), ctx, quietHours)
+}
+
+// MockFrequencyCapRepository is a mock of FrequencyCapRepository interface.
+type MockFrequencyCapRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFrequencyCapRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockFrequencyCapRepositoryMockRecorder is the mock recorder for MockFrequencyCapRepository.
+type MockFrequencyCapRepositoryMockRecorder struct {
+	mock *MockFrequencyCapRepository
+}
+
+// NewMockFrequencyCapRepository creates a new mock instance.
+func NewMockFrequencyCapRepository(ctrl *gomock.Controller) *MockFrequencyCapRepository {
+	mock := &MockFrequencyCapRepository{ctrl: ctrl}
+	mock.recorder = &MockFrequencyCapRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFrequencyCapRepository) EXPECT() *MockFrequencyCapRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteFrequencyCap mocks base method.
+func (m *MockFrequencyCapRepository) DeleteFrequencyCap(ctx context.Context, tenantID *uuid.UUID, deliveryType, category string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFrequencyCap", ctx, tenantID, deliveryType, category)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFrequencyCap indicates an expected call of DeleteFrequencyCap.
+func (mr *MockFrequencyCapRepositoryMockRecorder) DeleteFrequencyCap(ctx, tenantID, deliveryType, category any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFrequencyCap", reflect.TypeOf((*MockFrequencyCapRepository)(nil).DeleteFrequencyCap), ctx, tenantID, deliveryType, category)
+}
+
+// DeleteIdleFrequencyBuckets mocks base method.
+func (m *MockFrequencyCapRepository) DeleteIdleFrequencyBuckets(ctx context.Context, limit uint) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteIdleFrequencyBuckets", ctx, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteIdleFrequencyBuckets indicates an expected call of DeleteIdleFrequencyBuckets.
+func (mr *MockFrequencyCapRepositoryMockRecorder) DeleteIdleFrequencyBuckets(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteIdleFrequencyBuckets", reflect.TypeOf((*MockFrequencyCapRepository)(nil).DeleteIdleFrequencyBuckets), ctx, limit)
+}
+
+// GetFrequencyCaps mocks base method.
+func (m *MockFrequencyCapRepository) GetFrequencyCaps(ctx context.Context, tenantID *uuid.UUID) ([]*entities.FrequencyCap, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFrequencyCaps", ctx, tenantID)
+	ret0, _ := ret[0].([]*entities.FrequencyCap)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFrequencyCaps indicates an expected call of GetFrequencyCaps.
+func (mr *MockFrequencyCapRepositoryMockRecorder) GetFrequencyCaps(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFrequencyCaps", reflect.TypeOf((*MockFrequencyCapRepository)(nil).GetFrequencyCaps), ctx, tenantID)
+}
+
+// TakeFrequencyTokens mocks base method.
+func (m *MockFrequencyCapRepository) TakeFrequencyTokens(ctx context.Context, tenantID *uuid.UUID, deliveryType, category, recipient string) (*entities.FrequencyCapExceeded, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeFrequencyTokens", ctx, tenantID, deliveryType, category, recipient)
+	ret0, _ := ret[0].(*entities.FrequencyCapExceeded)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeFrequencyTokens indicates an expected call of TakeFrequencyTokens.
+func (mr *MockFrequencyCapRepositoryMockRecorder) TakeFrequencyTokens(ctx, tenantID, deliveryType, category, recipient any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeFrequencyTokens", reflect.TypeOf((*MockFrequencyCapRepository)(nil).TakeFrequencyTokens), ctx, tenantID, deliveryType, category, recipient)
+}
+
+// UpsertFrequencyCap mocks base method.
+func (m *MockFrequencyCapRepository) UpsertFrequencyCap(ctx context.Context, frequencyCap *entities.FrequencyCap) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertFrequencyCap", ctx, frequencyCap)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertFrequencyCap indicates an expected call of UpsertFrequencyCap.
+func (mr *MockFrequencyCapRepositoryMockRecorder) UpsertFrequencyCap(ctx, frequencyCap any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertFrequencyCap", reflect.TypeOf((*MockFrequencyCapRepository)(nil).UpsertFrequencyCap), ctx, frequencyCap)
+}
+
+// MockDigestRepository is a mock of DigestRepository interface.
+type MockDigestRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDigestRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockDigestRepositoryMockRecorder is the mock recorder for MockDigestRepository.
+type MockDigestRepositoryMockRecorder struct {
+	mock *MockDigestRepository
+}
+
+// NewMockDigestRepository creates a new mock instance.
+func NewMockDigestRepository(ctrl *gomock.Controller) *MockDigestRepository {
+	mock := &MockDigestRepository{ctrl: ctrl}
+	mock.recorder = &MockDigestRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDigestRepository) EXPECT() *MockDigestRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateDigestSummary mocks base method.
+func (m *MockDigestRepository) CreateDigestSummary(ctx context.Context, summary *entities.Notification, itemIDs []uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDigestSummary", ctx, summary, itemIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDigestSummary indicates an expected call of CreateDigestSummary.
+func (mr *MockDigestRepositoryMockRecorder) CreateDigestSummary(ctx, summary, itemIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDigestSummary", reflect.TypeOf((*MockDigestRepository)(nil).CreateDigestSummary), ctx, summary, itemIDs)
+}
+
+// DeleteDigestTemplate mocks base method.
+func (m *MockDigestRepository) DeleteDigestTemplate(ctx context.Context, tenantID *uuid.UUID, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDigestTemplate", ctx, tenantID, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDigestTemplate indicates an expected call of DeleteDigestTemplate.
+func (mr *MockDigestRepositoryMockRecorder) DeleteDigestTemplate(ctx, tenantID, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDigestTemplate", reflect.TypeOf((*MockDigestRepository)(nil).DeleteDigestTemplate), ctx, tenantID, key)
+}
+
+// GetDigestTemplate mocks base method.
+func (m *MockDigestRepository) GetDigestTemplate(ctx context.Context, tenantID *uuid.UUID, key string) (*entities.DigestTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDigestTemplate", ctx, tenantID, key)
+	ret0, _ := ret[0].(*entities.DigestTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDigestTemplate indicates an expected call of GetDigestTemplate.
+func (mr *MockDigestRepositoryMockRecorder) GetDigestTemplate(ctx, tenantID, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDigestTemplate", reflect.TypeOf((*MockDigestRepository)(nil).GetDigestTemplate), ctx, tenantID, key)
+}
+
+// GetDigestTemplates mocks base method.
+func (m *MockDigestRepository) GetDigestTemplates(ctx context.Context, tenantID *uuid.UUID) ([]*entities.DigestTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDigestTemplates", ctx, tenantID)
+	ret0, _ := ret[0].([]*entities.DigestTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDigestTemplates indicates an expected call of GetDigestTemplates.
+func (mr *MockDigestRepositoryMockRecorder) GetDigestTemplates(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDigestTemplates", reflect.TypeOf((*MockDigestRepository)(nil).GetDigestTemplates), ctx, tenantID)
+}
+
+// GetDueDigests mocks base method.
+func (m *MockDigestRepository) GetDueDigests(ctx context.Context, limit uint) ([]*entities.Digest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueDigests", ctx, limit)
+	ret0, _ := ret[0].([]*entities.Digest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueDigests indicates an expected call of GetDueDigests.
+func (mr *MockDigestRepositoryMockRecorder) GetDueDigests(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueDigests", reflect.TypeOf((*MockDigestRepository)(nil).GetDueDigests), ctx, limit)
+}
+
+// UpsertDigestTemplate mocks base method.
+func (m *MockDigestRepository) UpsertDigestTemplate(ctx context.Context, template *entities.DigestTemplate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertDigestTemplate", ctx, template)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertDigestTemplate indicates an expected call of UpsertDigestTemplate.
+func (mr *MockDigestRepositoryMockRecorder) UpsertDigestTemplate(ctx, template any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertDigestTemplate", reflect.TypeOf((*MockDigestRepository)(nil).UpsertDigestTemplate), ctx, template)
+}
+
+// MockRecurringNotificationRepository is a mock of RecurringNotificationRepository interface.
+type MockRecurringNotificationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecurringNotificationRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRecurringNotificationRepositoryMockRecorder is the mock recorder for MockRecurringNotificationRepository.
+type MockRecurringNotificationRepositoryMockRecorder struct {
+	mock *MockRecurringNotificationRepository
+}
+
+// NewMockRecurringNotificationRepository creates a new mock instance.
+func NewMockRecurringNotificationRepository(ctrl *gomock.Controller) *MockRecurringNotificationRepository {
+	mock := &MockRecurringNotificationRepository{ctrl: ctrl}
+	mock.recorder = &MockRecurringNotificationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecurringNotificationRepository) EXPECT() *MockRecurringNotificationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateRecurringNotification mocks base method.
+func (m *MockRecurringNotificationRepository) CreateRecurringNotification(ctx context.Context, recurring *entities.RecurringNotification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRecurringNotification", ctx, recurring)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRecurringNotification indicates an expected call of CreateRecurringNotification.
+func (mr *MockRecurringNotificationRepositoryMockRecorder) CreateRecurringNotification(ctx, recurring any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecurringNotification", reflect.TypeOf((*MockRecurringNotificationRepository)(nil).CreateRecurringNotification), ctx, recurring)
+}
+
+// DeleteRecurringNotification mocks base method.
+func (m *MockRecurringNotificationRepository) DeleteRecurringNotification(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRecurringNotification", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRecurringNotification indicates an expected call of DeleteRecurringNotification.
+func (mr *MockRecurringNotificationRepositoryMockRecorder) DeleteRecurringNotification(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecurringNotification", reflect.TypeOf((*MockRecurringNotificationRepository)(nil).DeleteRecurringNotification), ctx, id)
+}
+
+// FireRecurringNotification mocks base method.
+func (m *MockRecurringNotificationRepository) FireRecurringNotification(ctx context.Context, recurring *entities.RecurringNotification, notifications []*entities.Notification, nextRunAt *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FireRecurringNotification", ctx, recurring, notifications, nextRunAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FireRecurringNotification indicates an expected call of FireRecurringNotification.
+func (mr *MockRecurringNotificationRepositoryMockRecorder) FireRecurringNotification(ctx, recurring, notifications, nextRunAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FireRecurringNotification", reflect.TypeOf((*MockRecurringNotificationRepository)(nil).FireRecurringNotification), ctx, recurring, notifications, nextRunAt)
+}
+
+// GetDueRecurringNotifications mocks base method.
+func (m *MockRecurringNotificationRepository) GetDueRecurringNotifications(ctx context.Context, limit uint) ([]*entities.RecurringNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueRecurringNotifications", ctx, limit)
+	ret0, _ := ret[0].([]*entities.RecurringNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueRecurringNotifications indicates an expected call of GetDueRecurringNotifications.
+func (mr *MockRecurringNotificationRepositoryMockRecorder) GetDueRecurringNotifications(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueRecurringNotifications", reflect.TypeOf((*MockRecurringNotificationRepository)(nil).GetDueRecurringNotifications), ctx, limit)
+}
+
+// GetRecurringNotification mocks base method.
+func (m *MockRecurringNotificationRepository) GetRecurringNotification(ctx context.Context, id uuid.UUID, tenantID *uuid.UUID) (*entities.RecurringNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecurringNotification", ctx, id, tenantID)
+	ret0, _ := ret[0].(*entities.RecurringNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecurringNotification indicates an expected call of GetRecurringNotification.
+func (mr *MockRecurringNotificationRepositoryMockRecorder) GetRecurringNotification(ctx, id, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecurringNotification", reflect.TypeOf((*MockRecurringNotificationRepository)(nil).GetRecurringNotification), ctx, id, tenantID)
+}
+
+// GetRecurringNotifications mocks base method.
+func (m *MockRecurringNotificationRepository) GetRecurringNotifications(ctx context.Context, tenantID, clientID *uuid.UUID, limit, offset uint) ([]*entities.RecurringNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecurringNotifications", ctx, tenantID, clientID, limit, offset)
+	ret0, _ := ret[0].([]*entities.RecurringNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecurringNotifications indicates an expected call of GetRecurringNotifications.
+func (mr *MockRecurringNotificationRepositoryMockRecorder) GetRecurringNotifications(ctx, tenantID, clientID, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecurringNotifications", reflect.TypeOf((*MockRecurringNotificationRepository)(nil).GetRecurringNotifications), ctx, tenantID, clientID, limit, offset)
+}
+
+// UpdateRecurringNotification mocks base method.
+func (m *MockRecurringNotificationRepository) UpdateRecurringNotification(ctx context.Context, recurring *entities.RecurringNotification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRecurringNotification", ctx, recurring)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRecurringNotification indicates an expected call of UpdateRecurringNotification.
+func (mr *MockRecurringNotificationRepositoryMockRecorder) UpdateRecurringNotification(ctx, recurring any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRecurringNotification", reflect.TypeOf((*MockRecurringNotificationRepository)(nil).UpdateRecurringNotification), ctx, recurring)
+}
+
+// MockTopicRepository is a mock of TopicRepository interface.
+type MockTopicRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTopicRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockTopicRepositoryMockRecorder is the mock recorder for MockTopicRepository.
+type MockTopicRepositoryMockRecorder struct {
+	mock *MockTopicRepository
+}
+
+// NewMockTopicRepository creates a new mock instance.
+func NewMockTopicRepository(ctrl *gomock.Controller) *MockTopicRepository {
+	mock := &MockTopicRepository{ctrl: ctrl}
+	mock.recorder = &MockTopicRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTopicRepository) EXPECT() *MockTopicRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteTopic mocks base method.
+func (m *MockTopicRepository) DeleteTopic(ctx context.Context, tenantID *uuid.UUID, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTopic", ctx, tenantID, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTopic indicates an expected call of DeleteTopic.
+func (mr *MockTopicRepositoryMockRecorder) DeleteTopic(ctx, tenantID, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTopic", reflect.TypeOf((*MockTopicRepository)(nil).DeleteTopic), ctx, tenantID, name)
+}
+
+// GetTopic mocks base method.
+func (m *MockTopicRepository) GetTopic(ctx context.Context, tenantID *uuid.UUID, name string) (*entities.Topic, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTopic", ctx, tenantID, name)
+	ret0, _ := ret[0].(*entities.Topic)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTopic indicates an expected call of GetTopic.
+func (mr *MockTopicRepositoryMockRecorder) GetTopic(ctx, tenantID, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTopic", reflect.TypeOf((*MockTopicRepository)(nil).GetTopic), ctx, tenantID, name)
+}
+
+// GetTopicSubscriptions mocks base method.
+func (m *MockTopicRepository) GetTopicSubscriptions(ctx context.Context, tenantID *uuid.UUID, topic string, limit, offset uint) ([]*entities.TopicSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTopicSubscriptions", ctx, tenantID, topic, limit, offset)
+	ret0, _ := ret[0].([]*entities.TopicSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTopicSubscriptions indicates an expected call of GetTopicSubscriptions.
+func (mr *MockTopicRepositoryMockRecorder) GetTopicSubscriptions(ctx, tenantID, topic, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTopicSubscriptions", reflect.TypeOf((*MockTopicRepository)(nil).GetTopicSubscriptions), ctx, tenantID, topic, limit, offset)
+}
+
+// GetTopics mocks base method.
+func (m *MockTopicRepository) GetTopics(ctx context.Context, tenantID *uuid.UUID) ([]*entities.Topic, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTopics", ctx, tenantID)
+	ret0, _ := ret[0].([]*entities.Topic)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTopics indicates an expected call of GetTopics.
+func (mr *MockTopicRepositoryMockRecorder) GetTopics(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTopics", reflect.TypeOf((*MockTopicRepository)(nil).GetTopics), ctx, tenantID)
+}
+
+// GetUserSubscriptions mocks base method.
+func (m *MockTopicRepository) GetUserSubscriptions(ctx context.Context, tenantID *uuid.UUID, userID string) ([]*entities.TopicSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserSubscriptions", ctx, tenantID, userID)
+	ret0, _ := ret[0].([]*entities.TopicSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserSubscriptions indicates an expected call of GetUserSubscriptions.
+func (mr *MockTopicRepositoryMockRecorder) GetUserSubscriptions(ctx, tenantID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSubscriptions", reflect.TypeOf((*MockTopicRepository)(nil).GetUserSubscriptions), ctx, tenantID, userID)
+}
+
+// Subscribe mocks base method.
+func (m *MockTopicRepository) Subscribe(ctx context.Context, subscription *entities.TopicSubscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, subscription)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockTopicRepositoryMockRecorder) Subscribe(ctx, subscription any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockTopicRepository)(nil).Subscribe), ctx, subscription)
+}
+
+// Unsubscribe mocks base method.
+func (m *MockTopicRepository) Unsubscribe(ctx context.Context, tenantID *uuid.UUID, topic, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unsubscribe", ctx, tenantID, topic, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unsubscribe indicates an expected call of Unsubscribe.
+func (mr *MockTopicRepositoryMockRecorder) Unsubscribe(ctx, tenantID, topic, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsubscribe", reflect.TypeOf((*MockTopicRepository)(nil).Unsubscribe), ctx, tenantID, topic, userID)
+}
+
+// UpsertTopic mocks base method.
+func (m *MockTopicRepository) UpsertTopic(ctx context.Context, topic *entities.Topic) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTopic", ctx, topic)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertTopic indicates an expected call of UpsertTopic.
+func (mr *MockTopicRepositoryMockRecorder) UpsertTopic(ctx, topic any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTopic", reflect.TypeOf((*MockTopicRepository)(nil).UpsertTopic), ctx, topic)
+}
+
+// MockBroadcastRepository is a mock of BroadcastRepository interface.
+type MockBroadcastRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBroadcastRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockBroadcastRepositoryMockRecorder is the mock recorder for MockBroadcastRepository.
+type MockBroadcastRepositoryMockRecorder struct {
+	mock *MockBroadcastRepository
+}
+
+// NewMockBroadcastRepository creates a new mock instance.
+func NewMockBroadcastRepository(ctrl *gomock.Controller) *MockBroadcastRepository {
+	mock := &MockBroadcastRepository{ctrl: ctrl}
+	mock.recorder = &MockBroadcastRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBroadcastRepository) EXPECT() *MockBroadcastRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateBroadcast mocks base method.
+func (m *MockBroadcastRepository) CreateBroadcast(ctx context.Context, broadcast *entities.Broadcast) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBroadcast", ctx, broadcast)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBroadcast indicates an expected call of CreateBroadcast.
+func (mr *MockBroadcastRepositoryMockRecorder) CreateBroadcast(ctx, broadcast any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBroadcast", reflect.TypeOf((*MockBroadcastRepository)(nil).CreateBroadcast), ctx, broadcast)
+}
+
+// GetBroadcast mocks base method.
+func (m *MockBroadcastRepository) GetBroadcast(ctx context.Context, id uuid.UUID, tenantID *uuid.UUID) (*entities.Broadcast, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBroadcast", ctx, id, tenantID)
+	ret0, _ := ret[0].(*entities.Broadcast)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBroadcast indicates an expected call of GetBroadcast.
+func (mr *MockBroadcastRepositoryMockRecorder) GetBroadcast(ctx, id, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBroadcast", reflect.TypeOf((*MockBroadcastRepository)(nil).GetBroadcast), ctx, id, tenantID)
+}
+
+// GetBroadcasts mocks base method.
+func (m *MockBroadcastRepository) GetBroadcasts(ctx context.Context, tenantID, clientID *uuid.UUID, limit, offset uint) ([]*entities.Broadcast, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBroadcasts", ctx, tenantID, clientID, limit, offset)
+	ret0, _ := ret[0].([]*entities.Broadcast)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBroadcasts indicates an expected call of GetBroadcasts.
+func (mr *MockBroadcastRepositoryMockRecorder) GetBroadcasts(ctx, tenantID, clientID, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBroadcasts", reflect.TypeOf((*MockBroadcastRepository)(nil).GetBroadcasts), ctx, tenantID, clientID, limit, offset)
+}
+
+// ProcessBroadcastChunk mocks base method.
+func (m *MockBroadcastRepository) ProcessBroadcastChunk(ctx context.Context, chunkSize uint) (*entities.Broadcast, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessBroadcastChunk", ctx, chunkSize)
+	ret0, _ := ret[0].(*entities.Broadcast)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ProcessBroadcastChunk indicates an expected call of ProcessBroadcastChunk.
+func (mr *MockBroadcastRepositoryMockRecorder) ProcessBroadcastChunk(ctx, chunkSize any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessBroadcastChunk", reflect.TypeOf((*MockBroadcastRepository)(nil).ProcessBroadcastChunk), ctx, chunkSize)
+}
+
+// UpdateBroadcastStatus mocks base method.
+func (m *MockBroadcastRepository) UpdateBroadcastStatus(ctx context.Context, id uuid.UUID, status string, from []string) (*entities.Broadcast, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBroadcastStatus", ctx, id, status, from)
+	ret0, _ := ret[0].(*entities.Broadcast)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateBroadcastStatus indicates an expected call of UpdateBroadcastStatus.
+func (mr *MockBroadcastRepositoryMockRecorder) UpdateBroadcastStatus(ctx, id, status, from any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBroadcastStatus", reflect.TypeOf((*MockBroadcastRepository)(nil).UpdateBroadcastStatus), ctx, id, status, from)
+}
+
+// MockImportRepository is a mock of ImportRepository interface.
+type MockImportRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockImportRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockImportRepositoryMockRecorder is the mock recorder for MockImportRepository.
+type MockImportRepositoryMockRecorder struct {
+	mock *MockImportRepository
+}
+
+// NewMockImportRepository creates a new mock instance.
+func NewMockImportRepository(ctrl *gomock.Controller) *MockImportRepository {
+	mock := &MockImportRepository{ctrl: ctrl}
+	mock.recorder = &MockImportRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImportRepository) EXPECT() *MockImportRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CopyImportBatch mocks base method.
+func (m *MockImportRepository) CopyImportBatch(ctx context.Context, imp *entities.Import, notifications []*entities.Notification, rowErrors []*entities.ImportRowError, failed int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyImportBatch", ctx, imp, notifications, rowErrors, failed)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CopyImportBatch indicates an expected call of CopyImportBatch.
+func (mr *MockImportRepositoryMockRecorder) CopyImportBatch(ctx, imp, notifications, rowErrors, failed any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyImportBatch", reflect.TypeOf((*MockImportRepository)(nil).CopyImportBatch), ctx, imp, notifications, rowErrors, failed)
+}
+
+// CreateImport mocks base method.
+func (m *MockImportRepository) CreateImport(ctx context.Context, imp *entities.Import) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateImport", ctx, imp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateImport indicates an expected call of CreateImport.
+func (mr *MockImportRepositoryMockRecorder) CreateImport(ctx, imp any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateImport", reflect.TypeOf((*MockImportRepository)(nil).CreateImport), ctx, imp)
+}
+
+// FinishImport mocks base method.
+func (m *MockImportRepository) FinishImport(ctx context.Context, imp *entities.Import, status string, errMessage *string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishImport", ctx, imp, status, errMessage)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishImport indicates an expected call of FinishImport.
+func (mr *MockImportRepositoryMockRecorder) FinishImport(ctx, imp, status, errMessage any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishImport", reflect.TypeOf((*MockImportRepository)(nil).FinishImport), ctx, imp, status, errMessage)
+}
+
+// GetImport mocks base method.
+func (m *MockImportRepository) GetImport(ctx context.Context, id uuid.UUID, tenantID *uuid.UUID) (*entities.Import, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImport", ctx, id, tenantID)
+	ret0, _ := ret[0].(*entities.Import)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImport indicates an expected call of GetImport.
+func (mr *MockImportRepositoryMockRecorder) GetImport(ctx, id, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImport", reflect.TypeOf((*MockImportRepository)(nil).GetImport), ctx, id, tenantID)
+}
+
+// GetImportErrors mocks base method.
+func (m *MockImportRepository) GetImportErrors(ctx context.Context, id uuid.UUID, limit, offset uint) ([]*entities.ImportRowError, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImportErrors", ctx, id, limit, offset)
+	ret0, _ := ret[0].([]*entities.ImportRowError)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImportErrors indicates an expected call of GetImportErrors.
+func (mr *MockImportRepositoryMockRecorder) GetImportErrors(ctx, id, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImportErrors", reflect.TypeOf((*MockImportRepository)(nil).GetImportErrors), ctx, id, limit, offset)
+}
+
+// GetImports mocks base method.
+func (m *MockImportRepository) GetImports(ctx context.Context, tenantID, clientID *uuid.UUID, limit, offset uint) ([]*entities.Import, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImports", ctx, tenantID, clientID, limit, offset)
+	ret0, _ := ret[0].([]*entities.Import)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImports indicates an expected call of GetImports.
+func (mr *MockImportRepositoryMockRecorder) GetImports(ctx, tenantID, clientID, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImports", reflect.TypeOf((*MockImportRepository)(nil).GetImports), ctx, tenantID, clientID, limit, offset)
+}
+
+// StartImport mocks base method.
+func (m *MockImportRepository) StartImport(ctx context.Context, id uuid.UUID) (*entities.Import, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartImport", ctx, id)
+	ret0, _ := ret[0].(*entities.Import)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartImport indicates an expected call of StartImport.
+func (mr *MockImportRepositoryMockRecorder) StartImport(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartImport", reflect.TypeOf((*MockImportRepository)(nil).StartImport), ctx, id)
+}
+
+// MockClientRepository is a mock of ClientRepository interface.
+type MockClientRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockClientRepositoryMockRecorder is the mock recorder for MockClientRepository.
+type MockClientRepositoryMockRecorder struct {
+	mock *MockClientRepository
+}
+
+// NewMockClientRepository creates a new mock instance.
+func NewMockClientRepository(ctrl *gomock.Controller) *MockClientRepository {
+	mock := &MockClientRepository{ctrl: ctrl}
+	mock.recorder = &MockClientRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClientRepository) EXPECT() *MockClientRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockClientRepository) CreateAPIKey(ctx context.Context, key *entities.APIKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockClientRepositoryMockRecorder) CreateAPIKey(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockClientRepository)(nil).CreateAPIKey), ctx, key)
+}
+
+// CreateClient mocks base method.
+func (m *MockClientRepository) CreateClient(ctx context.Context, client *entities.Client, key *entities.APIKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateClient", ctx, client, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateClient indicates an expected call of CreateClient.
+func (mr *MockClientRepositoryMockRecorder) CreateClient(ctx, client, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateClient", reflect.TypeOf((*MockClientRepository)(nil).CreateClient), ctx, client, key)
+}
+
+// CreateTenant mocks base method.
+func (m *MockClientRepository) CreateTenant(ctx context.Context, tenant *entities.Tenant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTenant", ctx, tenant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTenant indicates an expected call of CreateTenant.
+func (mr *MockClientRepositoryMockRecorder) CreateTenant(ctx, tenant any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTenant", reflect.TypeOf((*MockClientRepository)(nil).CreateTenant), ctx, tenant)
+}
+
+// GetAPIKeyByPrefix mocks base method.
+func (m *MockClientRepository) GetAPIKeyByPrefix(ctx context.Context, prefix string) (*entities.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeyByPrefix", ctx, prefix)
+	ret0, _ := ret[0].(*entities.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeyByPrefix indicates an expected call of GetAPIKeyByPrefix.
+func (mr *MockClientRepositoryMockRecorder) GetAPIKeyByPrefix(ctx, prefix any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyByPrefix", reflect.TypeOf((*MockClientRepository)(nil).GetAPIKeyByPrefix), ctx, prefix)
+}
+
+// GetAPIKeys mocks base method.
+func (m *MockClientRepository) GetAPIKeys(ctx context.Context, clientID uuid.UUID) ([]*entities.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeys", ctx, clientID)
+	ret0, _ := ret[0].([]*entities.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeys indicates an expected call of GetAPIKeys.
+func (mr *MockClientRepositoryMockRecorder) GetAPIKeys(ctx, clientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeys", reflect.TypeOf((*MockClientRepository)(nil).GetAPIKeys), ctx, clientID)
+}
+
+// GetClient mocks base method.
+func (m *MockClientRepository) GetClient(ctx context.Context, id uuid.UUID) (*entities.Client, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClient", ctx, id)
+	ret0, _ := ret[0].(*entities.Client)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClient indicates an expected call of GetClient.
+func (mr *MockClientRepositoryMockRecorder) GetClient(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClient", reflect.TypeOf((*MockClientRepository)(nil).GetClient), ctx, id)
+}
+
+// GetClientByOAuthClientID mocks base method.
+func (m *MockClientRepository) GetClientByOAuthClientID(ctx context.Context, oauthClientID string) (*entities.Client, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClientByOAuthClientID", ctx, oauthClientID)
+	ret0, _ := ret[0].(*entities.Client)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClientByOAuthClientID indicates an expected call of GetClientByOAuthClientID.
+func (mr *MockClientRepositoryMockRecorder) GetClientByOAuthClientID(ctx, oauthClientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClientByOAuthClientID", reflect.TypeOf((*MockClientRepository)(nil).GetClientByOAuthClientID), ctx, oauthClientID)
+}
+
+// GetClients mocks base method.
+func (m *MockClientRepository) GetClients(ctx context.Context) ([]*entities.Client, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClients", ctx)
+	ret0, _ := ret[0].([]*entities.Client)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClients indicates an expected call of GetClients.
+func (mr *MockClientRepositoryMockRecorder) GetClients(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClients", reflect.TypeOf((*MockClientRepository)(nil).GetClients), ctx)
+}
+
+// GetTenant mocks base method.
+func (m *MockClientRepository) GetTenant(ctx context.Context, id uuid.UUID) (*entities.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTenant", ctx, id)
+	ret0, _ := ret[0].(*entities.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTenant indicates an expected call of GetTenant.
+func (mr *MockClientRepositoryMockRecorder) GetTenant(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTenant", reflect.TypeOf((*MockClientRepository)(nil).GetTenant), ctx, id)
+}
+
+// GetTenants mocks base method.
+func (m *MockClientRepository) GetTenants(ctx context.Context) ([]*entities.Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTenants", ctx)
+	ret0, _ := ret[0].([]*entities.Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTenants indicates an expected call of GetTenants.
+func (mr *MockClientRepositoryMockRecorder) GetTenants(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTenants", reflect.TypeOf((*MockClientRepository)(nil).GetTenants), ctx)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockClientRepository) RevokeAPIKey(ctx context.Context, clientID, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, clientID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockClientRepositoryMockRecorder) RevokeAPIKey(ctx, clientID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockClientRepository)(nil).RevokeAPIKey), ctx, clientID, id)
+}
+
+// RotateAPIKey mocks base method.
+func (m *MockClientRepository) RotateAPIKey(ctx context.Context, clientID, oldID uuid.UUID, key *entities.APIKey, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateAPIKey", ctx, clientID, oldID, key, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RotateAPIKey indicates an expected call of RotateAPIKey.
+func (mr *MockClientRepositoryMockRecorder) RotateAPIKey(ctx, clientID, oldID, key, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateAPIKey", reflect.TypeOf((*MockClientRepository)(nil).RotateAPIKey), ctx, clientID, oldID, key, expiresAt)
+}
+
+// UpdateClientOAuthClientID mocks base method.
+func (m *MockClientRepository) UpdateClientOAuthClientID(ctx context.Context, id uuid.UUID, oauthClientID *string) (*entities.Client, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateClientOAuthClientID", ctx, id, oauthClientID)
+	ret0, _ := ret[0].(*entities.Client)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateClientOAuthClientID indicates an expected call of UpdateClientOAuthClientID.
+func (mr *MockClientRepositoryMockRecorder) UpdateClientOAuthClientID(ctx, id, oauthClientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateClientOAuthClientID", reflect.TypeOf((*MockClientRepository)(nil).UpdateClientOAuthClientID), ctx, id, oauthClientID)
+}
+
+// MockQuotaRepository is a mock of QuotaRepository interface.
+type MockQuotaRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuotaRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockQuotaRepositoryMockRecorder is the mock recorder for MockQuotaRepository.
+type MockQuotaRepositoryMockRecorder struct {
+	mock *MockQuotaRepository
+}
+
+// NewMockQuotaRepository creates a new mock instance.
+func NewMockQuotaRepository(ctrl *gomock.Controller) *MockQuotaRepository {
+	mock := &MockQuotaRepository{ctrl: ctrl}
+	mock.recorder = &MockQuotaRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuotaRepository) EXPECT() *MockQuotaRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ConsumeQuota mocks base method.
+func (m *MockQuotaRepository) ConsumeQuota(ctx context.Context, clientID uuid.UUID, counts map[string]int32, now time.Time) (*entities.QuotaExceeded, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeQuota", ctx, clientID, counts, now)
+	ret0, _ := ret[0].(*entities.QuotaExceeded)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeQuota indicates an expected call of ConsumeQuota.
+func (mr *MockQuotaRepositoryMockRecorder) ConsumeQuota(ctx, clientID, counts, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeQuota", reflect.TypeOf((*MockQuotaRepository)(nil).ConsumeQuota), ctx, clientID, counts, now)
+}
+
+// DeleteClientQuota mocks base method.
+func (m *MockQuotaRepository) DeleteClientQuota(ctx context.Context, clientID uuid.UUID, deliveryType string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteClientQuota", ctx, clientID, deliveryType)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteClientQuota indicates an expected call of DeleteClientQuota.
+func (mr *MockQuotaRepositoryMockRecorder) DeleteClientQuota(ctx, clientID, deliveryType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClientQuota", reflect.TypeOf((*MockQuotaRepository)(nil).DeleteClientQuota), ctx, clientID, deliveryType)
+}
+
+// GetClientQuotas mocks base method.
+func (m *MockQuotaRepository) GetClientQuotas(ctx context.Context, clientID uuid.UUID) ([]*entities.ClientQuota, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClientQuotas", ctx, clientID)
+	ret0, _ := ret[0].([]*entities.ClientQuota)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClientQuotas indicates an expected call of GetClientQuotas.
+func (mr *MockQuotaRepositoryMockRecorder) GetClientQuotas(ctx, clientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClientQuotas", reflect.TypeOf((*MockQuotaRepository)(nil).GetClientQuotas), ctx, clientID)
+}
+
+// GetClientUsage mocks base method.
+func (m *MockQuotaRepository) GetClientUsage(ctx context.Context, clientID uuid.UUID, now time.Time) ([]*entities.ClientUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClientUsage", ctx, clientID, now)
+	ret0, _ := ret[0].([]*entities.ClientUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClientUsage indicates an expected call of GetClientUsage.
+func (mr *MockQuotaRepositoryMockRecorder) GetClientUsage(ctx, clientID, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClientUsage", reflect.TypeOf((*MockQuotaRepository)(nil).GetClientUsage), ctx, clientID, now)
+}
+
+// ReleaseQuota mocks base method.
+func (m *MockQuotaRepository) ReleaseQuota(ctx context.Context, clientID uuid.UUID, counts map[string]int32, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseQuota", ctx, clientID, counts, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseQuota indicates an expected call of ReleaseQuota.
+func (mr *MockQuotaRepositoryMockRecorder) ReleaseQuota(ctx, clientID, counts, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseQuota", reflect.TypeOf((*MockQuotaRepository)(nil).ReleaseQuota), ctx, clientID, counts, now)
+}
+
+// UpsertClientQuota mocks base method.
+func (m *MockQuotaRepository) UpsertClientQuota(ctx context.Context, quota *entities.ClientQuota) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertClientQuota", ctx, quota)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertClientQuota indicates an expected call of UpsertClientQuota.
+func (mr *MockQuotaRepositoryMockRecorder) UpsertClientQuota(ctx, quota any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertClientQuota", reflect.TypeOf((*MockQuotaRepository)(nil).UpsertClientQuota), ctx, quota)
+}
+
+// MockAuditRepository is a mock of AuditRepository interface.
+type MockAuditRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAuditRepositoryMockRecorder is the mock recorder for MockAuditRepository.
+type MockAuditRepositoryMockRecorder struct {
+	mock *MockAuditRepository
+}
+
+// NewMockAuditRepository creates a new mock instance.
+func NewMockAuditRepository(ctrl *gomock.Controller) *MockAuditRepository {
+	mock := &MockAuditRepository{ctrl: ctrl}
+	mock.recorder = &MockAuditRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditRepository) EXPECT() *MockAuditRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateAuditEntry mocks base method.
+func (m *MockAuditRepository) CreateAuditEntry(ctx context.Context, entry *entities.AuditEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditEntry", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAuditEntry indicates an expected call of CreateAuditEntry.
+func (mr *MockAuditRepositoryMockRecorder) CreateAuditEntry(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditEntry", reflect.TypeOf((*MockAuditRepository)(nil).CreateAuditEntry), ctx, entry)
+}
+
+// ExportAuditEntries mocks base method.
+func (m *MockAuditRepository) ExportAuditEntries(ctx context.Context, filter *entities.AuditFilter, fn func(*entities.AuditEntry) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportAuditEntries", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportAuditEntries indicates an expected call of ExportAuditEntries.
+func (mr *MockAuditRepositoryMockRecorder) ExportAuditEntries(ctx, filter, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportAuditEntries", reflect.TypeOf((*MockAuditRepository)(nil).ExportAuditEntries), ctx, filter, fn)
+}
+
+// SearchAuditEntries mocks base method.
+func (m *MockAuditRepository) SearchAuditEntries(ctx context.Context, filter *entities.AuditFilter) ([]*entities.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchAuditEntries", ctx, filter)
+	ret0, _ := ret[0].([]*entities.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchAuditEntries indicates an expected call of SearchAuditEntries.
+func (mr *MockAuditRepositoryMockRecorder) SearchAuditEntries(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchAuditEntries", reflect.TypeOf((*MockAuditRepository)(nil).SearchAuditEntries), ctx, filter)
+}
+
+// MockEncryptionRepository is a mock of EncryptionRepository interface.
+type MockEncryptionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockEncryptionRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockEncryptionRepositoryMockRecorder is the mock recorder for MockEncryptionRepository.
+type MockEncryptionRepositoryMockRecorder struct {
+	mock *MockEncryptionRepository
+}
+
+// NewMockEncryptionRepository creates a new mock instance.
+func NewMockEncryptionRepository(ctrl *gomock.Controller) *MockEncryptionRepository {
+	mock := &MockEncryptionRepository{ctrl: ctrl}
+	mock.recorder = &MockEncryptionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEncryptionRepository) EXPECT() *MockEncryptionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ReencryptNotifications mocks base method.
+func (m *MockEncryptionRepository) ReencryptNotifications(ctx context.Context, after uuid.UUID, limit uint) (uuid.UUID, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReencryptNotifications", ctx, after, limit)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ReencryptNotifications indicates an expected call of ReencryptNotifications.
+func (mr *MockEncryptionRepositoryMockRecorder) ReencryptNotifications(ctx, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReencryptNotifications", reflect.TypeOf((*MockEncryptionRepository)(nil).ReencryptNotifications), ctx, after, limit)
+}
+
+// ReencryptTenants mocks base method.
+func (m *MockEncryptionRepository) ReencryptTenants(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReencryptTenants", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReencryptTenants indicates an expected call of ReencryptTenants.
+func (mr *MockEncryptionRepositoryMockRecorder) ReencryptTenants(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReencryptTenants", reflect.TypeOf((*MockEncryptionRepository)(nil).ReencryptTenants), ctx)
+}
+
+// MockRetentionRepository is a mock of RetentionRepository interface.
+type MockRetentionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRetentionRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRetentionRepositoryMockRecorder is the mock recorder for MockRetentionRepository.
+type MockRetentionRepositoryMockRecorder struct {
+	mock *MockRetentionRepository
+}
+
+// NewMockRetentionRepository creates a new mock instance.
+func NewMockRetentionRepository(ctrl *gomock.Controller) *MockRetentionRepository {
+	mock := &MockRetentionRepository{ctrl: ctrl}
+	mock.recorder = &MockRetentionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRetentionRepository) EXPECT() *MockRetentionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateNotificationPartitions mocks base method.
+func (m *MockRetentionRepository) CreateNotificationPartitions(ctx context.Context, from time.Time, months int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNotificationPartitions", ctx, from, months)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateNotificationPartitions indicates an expected call of CreateNotificationPartitions.
+func (mr *MockRetentionRepositoryMockRecorder) CreateNotificationPartitions(ctx, from, months any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNotificationPartitions", reflect.TypeOf((*MockRetentionRepository)(nil).CreateNotificationPartitions), ctx, from, months)
+}
+
+// DeleteRetentionPolicy mocks base method.
+func (m *MockRetentionRepository) DeleteRetentionPolicy(ctx context.Context, tenantID *uuid.UUID, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRetentionPolicy", ctx, tenantID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRetentionPolicy indicates an expected call of DeleteRetentionPolicy.
+func (mr *MockRetentionRepositoryMockRecorder) DeleteRetentionPolicy(ctx, tenantID, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRetentionPolicy", reflect.TypeOf((*MockRetentionRepository)(nil).DeleteRetentionPolicy), ctx, tenantID, status)
+}
+
+// DropEmptyNotificationPartitions mocks base method.
+func (m *MockRetentionRepository) DropEmptyNotificationPartitions(ctx context.Context, before time.Time) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DropEmptyNotificationPartitions", ctx, before)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DropEmptyNotificationPartitions indicates an expected call of DropEmptyNotificationPartitions.
+func (mr *MockRetentionRepositoryMockRecorder) DropEmptyNotificationPartitions(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropEmptyNotificationPartitions", reflect.TypeOf((*MockRetentionRepository)(nil).DropEmptyNotificationPartitions), ctx, before)
+}
+
+// GetAllRetentionPolicies mocks base method.
+func (m *MockRetentionRepository) GetAllRetentionPolicies(ctx context.Context) ([]*entities.RetentionPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllRetentionPolicies", ctx)
+	ret0, _ := ret[0].([]*entities.RetentionPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllRetentionPolicies indicates an expected call of GetAllRetentionPolicies.
+func (mr *MockRetentionRepositoryMockRecorder) GetAllRetentionPolicies(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllRetentionPolicies", reflect.TypeOf((*MockRetentionRepository)(nil).GetAllRetentionPolicies), ctx)
+}
+
+// GetRetentionPolicies mocks base method.
+func (m *MockRetentionRepository) GetRetentionPolicies(ctx context.Context, tenantID *uuid.UUID) ([]*entities.RetentionPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRetentionPolicies", ctx, tenantID)
+	ret0, _ := ret[0].([]*entities.RetentionPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRetentionPolicies indicates an expected call of GetRetentionPolicies.
+func (mr *MockRetentionRepositoryMockRecorder) GetRetentionPolicies(ctx, tenantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRetentionPolicies", reflect.TypeOf((*MockRetentionRepository)(nil).GetRetentionPolicies), ctx, tenantID)
+}
+
+// PurgeNotifications mocks base method.
+func (m *MockRetentionRepository) PurgeNotifications(ctx context.Context, policy *entities.RetentionPolicy, now time.Time, limit uint, archive func([]json.RawMessage) error) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeNotifications", ctx, policy, now, limit, archive)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeNotifications indicates an expected call of PurgeNotifications.
+func (mr *MockRetentionRepositoryMockRecorder) PurgeNotifications(ctx, policy, now, limit, archive any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeNotifications", reflect.TypeOf((*MockRetentionRepository)(nil).PurgeNotifications), ctx, policy, now, limit, archive)
+}
+
+// UpsertRetentionPolicy mocks base method.
+func (m *MockRetentionRepository) UpsertRetentionPolicy(ctx context.Context, policy *entities.RetentionPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertRetentionPolicy", ctx, policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertRetentionPolicy indicates an expected call of UpsertRetentionPolicy.
+func (mr *MockRetentionRepositoryMockRecorder) UpsertRetentionPolicy(ctx, policy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertRetentionPolicy", reflect.TypeOf((*MockRetentionRepository)(nil).UpsertRetentionPolicy), ctx, policy)
+}
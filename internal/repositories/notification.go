@@ -2,26 +2,37 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 
 	"notification_system/config"
 	"notification_system/internal/entities"
 	"notification_system/pkg/database"
+	"notification_system/pkg/envelope"
 )
 
+const notificationColumns = `id, delivery_type, recipient, content, status, priority, retries, created_at,
+	sent_at, next_attempt_at, parent_id, chain_step, user_id, category, status_reason,
+	digest_key, digest_window_seconds, summary_id, recurring_id, broadcast_id, import_id, client_id, tenant_id`
+
 type NotificationPostgresRepository struct {
-	db *database.PostgresDatabase
+	db     *database.PostgresDatabase
+	cipher *envelope.Cipher
 }
 
-func NewNotificationPostgresRepository(db *database.PostgresDatabase) NotificationRepository {
-	return &NotificationPostgresRepository{db: db}
+func NewNotificationPostgresRepository(db *database.PostgresDatabase, cipher *envelope.Cipher) NotificationRepository {
+	return &NotificationPostgresRepository{db: db, cipher: cipher}
 }
 
-func (r *NotificationPostgresRepository) GetNotificationByID(ctx context.Context, id uuid.UUID) (*entities.Notification, error) {
-	notifications, err := r.GetNotificationsByIDs(ctx, []uuid.UUID{id})
+// GetNotificationByID returns the notification of the tenant, a nil tenant reads the
+// notifications of every tenant.
+func (r *NotificationPostgresRepository) GetNotificationByID(ctx context.Context, id uuid.UUID, tenantID *uuid.UUID) (*entities.Notification, error) {
+	notifications, err := r.GetNotificationsByIDs(ctx, []uuid.UUID{id}, tenantID)
 	if err != nil {
 		return nil, err
 	}
@@ -31,49 +42,116 @@ func (r *NotificationPostgresRepository) GetNotificationByID(ctx context.Context
 	return notifications[0], nil
 }
 
-func (r *NotificationPostgresRepository) GetNewNotifications(ctx context.Context, limit uint) ([]*entities.Notification, error) {
+// ClaimNotification moves a pending or queued notification to in progress and returns it, so
+// it is sent once and only while it is still due. It returns ErrNotFound for a notification in
+// any other status, like a chain step expired while it was queued or one another replica took.
+func (r *NotificationPostgresRepository) ClaimNotification(ctx context.Context, id uuid.UUID) (*entities.Notification, error) {
+	query := fmt.Sprintf(`
+		update notifications
+		set status = $2
+		where id = $1 and status in ($3, $4)
+		returning %s
+	`, notificationColumns)
+	notification := &entities.Notification{}
+	row := r.db.Pool.QueryRow(ctx, query, id, entities.StatusInProgress, entities.StatusPending, entities.StatusInQueue)
+	if err := scanNotification(row, notification); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("NotificationPostgresRepository.ClaimNotification error: %w", err)
+	}
+	if err := openNotifications(ctx, r.cipher, notification); err != nil {
+		// the claimed notification would stay in progress
+		if failErr := failUndecryptable(ctx, r.db, []uuid.UUID{notification.ID}); failErr != nil {
+			return nil, fmt.Errorf("NotificationPostgresRepository.ClaimNotification %w", failErr)
+		}
+		return nil, fmt.Errorf("NotificationPostgresRepository.ClaimNotification decrypt error: %w", err)
+	}
+	return notification, nil
+}
+
+// GetNewNotifications returns the pending notifications of the tenant, a nil tenant reads
+// the notifications of every tenant.
+func (r *NotificationPostgresRepository) GetNewNotifications(ctx context.Context, limit uint, tenantID *uuid.UUID) ([]*entities.Notification, error) {
 	if limit > config.Cfg.MaxBatchSize {
 		return nil, ErrMaxBatchSizeExceeded
 	}
 
-	query := `
-		select *
+	query := fmt.Sprintf(`
+		select %s
 		from notifications
 		where status = $1
+			and (next_attempt_at is null or next_attempt_at <= now())
+			and ($3::uuid is null or tenant_id = $3)
 		order by created_at
 		limit $2
-	`
+	`, notificationColumns)
 	notifications := make([]*entities.Notification, 0, limit)
-	rows, err := r.db.Pool.Query(ctx, query, entities.StatusPending, limit)
+	rows, err := r.db.Pool.Query(ctx, query, entities.StatusPending, limit, tenantID)
 	if err != nil {
 		return nil, fmt.Errorf("NotificationPostgresRepository.GetNotifications query error: %w", err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var notification entities.Notification
-		err := rows.Scan(
-			&notification.ID,
-			&notification.DeliveryType,
-			&notification.Recipient,
-			&notification.Content,
-			&notification.Status,
-			&notification.Retries,
-			&notification.CreatedAt,
-			&notification.SentAt,
-		)
+		notification := &entities.Notification{}
+		err := scanNotification(rows, notification)
 		if err != nil {
 			return nil, fmt.Errorf("NotificationPostgresRepository.GetNotifications scan error: %w", err)
 		}
-		notifications = append(notifications, &notification)
+		notifications = append(notifications, notification)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("NotificationPostgresRepository.GetNotifications rows iteration error: %w", err)
 	}
+	notifications, undecryptable := openDeliverable(ctx, r.cipher, notifications)
+	if err := failUndecryptable(ctx, r.db, undecryptable); err != nil {
+		return nil, fmt.Errorf("NotificationPostgresRepository.GetNotifications %w", err)
+	}
 	return notifications, nil
 }
 
-func (r *NotificationPostgresRepository) GetNotificationsByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.Notification, error) {
+// GetNewNotificationsByClientID is GetNewNotifications for the notifications of one API client.
+func (r *NotificationPostgresRepository) GetNewNotificationsByClientID(ctx context.Context, clientID uuid.UUID, limit uint) ([]*entities.Notification, error) {
+	if limit > config.Cfg.MaxBatchSize {
+		return nil, ErrMaxBatchSizeExceeded
+	}
+
+	query := fmt.Sprintf(`
+		select %s
+		from notifications
+		where status = $1 and client_id = $2
+			and (next_attempt_at is null or next_attempt_at <= now())
+		order by created_at
+		limit $3
+	`, notificationColumns)
+	notifications := make([]*entities.Notification, 0, limit)
+	rows, err := r.db.Pool.Query(ctx, query, entities.StatusPending, clientID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("NotificationPostgresRepository.GetNewNotificationsByClientID query error: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		notification := &entities.Notification{}
+		if err := scanNotification(rows, notification); err != nil {
+			return nil, fmt.Errorf("NotificationPostgresRepository.GetNewNotificationsByClientID scan error: %w", err)
+		}
+		notifications = append(notifications, notification)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("NotificationPostgresRepository.GetNewNotificationsByClientID rows iteration error: %w", err)
+	}
+	notifications, undecryptable := openDeliverable(ctx, r.cipher, notifications)
+	if err := failUndecryptable(ctx, r.db, undecryptable); err != nil {
+		return nil, fmt.Errorf("NotificationPostgresRepository.GetNewNotificationsByClientID %w", err)
+	}
+	return notifications, nil
+}
+
+// GetNotificationsByIDs returns the notifications of the tenant, a nil tenant reads the
+// notifications of every tenant.
+func (r *NotificationPostgresRepository) GetNotificationsByIDs(ctx context.Context, ids []uuid.UUID, tenantID *uuid.UUID) ([]*entities.Notification, error) {
 	if len(ids) == 0 {
 		return []*entities.Notification{}, nil
 	}
@@ -81,16 +159,19 @@ func (r *NotificationPostgresRepository) GetNotificationsByIDs(ctx context.Conte
 		return nil, ErrMaxBatchSizeExceeded
 	}
 	placeholders := make([]string, len(ids))
-	args := make([]any, len(ids))
+	args := make([]any, 0, len(ids)+1)
+	args = append(args, tenantID)
 	for i, id := range ids {
-		placeholders[i] = fmt.Sprintf("$%d", i+1)
-		args[i] = id
+		placeholders[i] = fmt.Sprintf("$%d", i+2)
+		args = append(args, id)
 	}
 
 	query := fmt.Sprintf(`
-		select *
+		select %s
 		from notifications
-		where id in (%s)`,
+		where id in (%s)
+			and ($1::uuid is null or tenant_id = $1)`,
+		notificationColumns,
 		strings.Join(placeholders, ","),
 	)
 	rows, err := r.db.Pool.Query(ctx, query, args...)
@@ -101,76 +182,137 @@ func (r *NotificationPostgresRepository) GetNotificationsByIDs(ctx context.Conte
 
 	var notifications []*entities.Notification
 	for rows.Next() {
-		var notification entities.Notification
-		err := rows.Scan(
-			&notification.ID,
-			&notification.DeliveryType,
-			&notification.Recipient,
-			&notification.Content,
-			&notification.Status,
-			&notification.Retries,
-			&notification.CreatedAt,
-			&notification.SentAt,
-		)
+		notification := &entities.Notification{}
+		err := scanNotification(rows, notification)
 		if err != nil {
 			return nil, fmt.Errorf("NotificationPostgresRepository.GetNotificationsByIDs scan error: %w", err)
 		}
-		notifications = append(notifications, &notification)
+		notifications = append(notifications, notification)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("NotificationPostgresRepository.GetNotificationsByIDs rows error: %w", err)
 	}
+	if err := openNotifications(ctx, r.cipher, notifications...); err != nil {
+		return nil, fmt.Errorf("NotificationPostgresRepository.GetNotificationsByIDs decrypt error: %w", err)
+	}
 	return notifications, nil
 }
 
 func (r *NotificationPostgresRepository) CreateNotifications(ctx context.Context, notifications []*entities.Notification) error {
-	if len(notifications) == 0 {
+	return r.CreateNotificationBatch(ctx, notifications, nil)
+}
+
+// CreateNotificationBatch creates the notifications and the fallback chains of a batch in one
+// transaction, so a batch is created entirely or not at all. Every step of a chain counts
+// against the maximum batch size like a notification.
+func (r *NotificationPostgresRepository) CreateNotificationBatch(ctx context.Context, notifications []*entities.Notification, chains []*entities.NotificationChain) error {
+	size := len(notifications)
+	for _, chain := range chains {
+		if len(chain.Channels) == 0 {
+			return ErrEmptyChain
+		}
+		size += len(chain.Channels)
+	}
+	if size == 0 {
 		return nil
 	}
-	if len(notifications) > int(config.Cfg.MaxBatchSize) {
+	if size > int(config.Cfg.MaxBatchSize) {
 		return ErrMaxBatchSizeExceeded
 	}
 
-	query := "insert into notifications (delivery_type, recipient, content) values "
-	args := make([]any, 0, len(notifications)*3)
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("NotificationPostgresRepository.CreateNotificationBatch begin error: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := insertNotifications(ctx, tx, r.cipher, notifications); err != nil {
+		return fmt.Errorf("NotificationPostgresRepository.CreateNotificationBatch %w", err)
+	}
+	for _, chain := range chains {
+		if err := insertNotificationChain(ctx, tx, r.cipher, chain.Notification, chain.Channels); err != nil {
+			return fmt.Errorf("NotificationPostgresRepository.CreateNotificationBatch %w", err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("NotificationPostgresRepository.CreateNotificationBatch commit error: %w", err)
+	}
+	for _, chain := range chains {
+		if err := openNotifications(ctx, r.cipher, chain.Notification); err != nil {
+			return fmt.Errorf("NotificationPostgresRepository.CreateNotificationBatch decrypt error: %w", err)
+		}
+	}
+	return nil
+}
+
+// insertNotifications inserts the notifications with one statement and scans them back decrypted.
+func insertNotifications(ctx context.Context, tx pgx.Tx, c *envelope.Cipher, notifications []*entities.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	const columnCount = 12
+	query := `insert into notifications (delivery_type, recipient, content, priority, user_id, category,
+		status, digest_key, digest_window_seconds, client_id, tenant_id, recipient_hash) values `
+	args := make([]any, 0, len(notifications)*columnCount)
 	values := make([]string, 0, len(notifications))
 	for i, notification := range notifications {
-		values = append(values, fmt.Sprintf("($%d, $%d, $%d)", i*3+1, i*3+2, i*3+3))
-		args = append(args, notification.DeliveryType, notification.Recipient, notification.Content)
+		placeholders := make([]string, columnCount)
+		for j := range placeholders {
+			placeholders[j] = fmt.Sprintf("$%d", i*columnCount+j+1)
+		}
+		values = append(values, "("+strings.Join(placeholders, ", ")+")")
+		status := notification.Status
+		if status == "" {
+			status = entities.StatusPending
+		}
+		sealed, err := sealFields(ctx, c, notification.Recipient, notification.Content)
+		if err != nil {
+			return fmt.Errorf("encrypt error: %w", err)
+		}
+		args = append(args,
+			notification.DeliveryType,
+			sealed.recipient,
+			sealed.content,
+			notification.Priority,
+			notification.UserID,
+			notification.Category,
+			status,
+			notification.DigestKey,
+			notification.DigestWindowSeconds,
+			notification.ClientID,
+			notification.TenantID,
+			sealed.recipientHash,
+		)
 	}
 	query += strings.Join(values, ",")
-	query += " returning *"
+	query += " returning " + notificationColumns
 
-	rows, err := r.db.Pool.Query(ctx, query, args...)
+	rows, err := tx.Query(ctx, query, args...)
 	if err != nil {
-		return fmt.Errorf("NotificationPostgresRepository.CreateNotifications query error: %w", err)
+		return fmt.Errorf("insert error: %w", err)
 	}
 	defer rows.Close()
 
 	i := 0
 	for rows.Next() {
 		notification := notifications[i]
-		err := rows.Scan(
-			&notification.ID,
-			&notification.DeliveryType,
-			&notification.Recipient,
-			&notification.Content,
-			&notification.Status,
-			&notification.Retries,
-			&notification.CreatedAt,
-			&notification.SentAt,
-		)
+		err := scanNotification(rows, notification)
 		if err != nil {
-			return fmt.Errorf("NotificationPostgresRepository.CreateNotifications scan error: %w", err)
+			return fmt.Errorf("scan error: %w", err)
 		}
 		i++
 	}
 	if err := rows.Err(); err != nil {
-		return fmt.Errorf("NotificationPostgresRepository.CreateNotifications rows error: %w", err)
+		return fmt.Errorf("rows error: %w", err)
+	}
+	if err := openNotifications(ctx, c, notifications...); err != nil {
+		return fmt.Errorf("decrypt error: %w", err)
 	}
 	return nil
 }
 
+// UpdateNotificationsStatus moves the notifications to the status and clears
+// the reason a pending notification was deferred for.
 func (r *NotificationPostgresRepository) UpdateNotificationsStatus(ctx context.Context, ids []uuid.UUID, status string) error {
 	if len(ids) == 0 {
 		return nil
@@ -178,6 +320,7 @@ func (r *NotificationPostgresRepository) UpdateNotificationsStatus(ctx context.C
 	query := fmt.Sprintf(`
 		update notifications
 		set status = $1,
+			status_reason = null,
 			sent_at = case when $1 = '%s' then now() else sent_at end
 		where id = any($2)
 	`, entities.StatusDelivered)
@@ -188,6 +331,25 @@ func (r *NotificationPostgresRepository) UpdateNotificationsStatus(ctx context.C
 	return nil
 }
 
+// QueueNotifications moves the notifications still pending to in queue. A notification
+// a receiver claimed before the sender got here keeps its newer status.
+func (r *NotificationPostgresRepository) QueueNotifications(ctx context.Context, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	query := `
+		update notifications
+		set status = $1,
+			status_reason = null
+		where id = any($2) and status = $3
+	`
+	_, err := r.db.Pool.Exec(ctx, query, entities.StatusInQueue, ids, entities.StatusPending)
+	if err != nil {
+		return fmt.Errorf("NotificationPostgresRepository.QueueNotifications error: %w", err)
+	}
+	return nil
+}
+
 func (r *NotificationPostgresRepository) UpdateNotificationRetries(ctx context.Context, id uuid.UUID, retries uint8) error {
 	query := `
 		update notifications
@@ -200,3 +362,74 @@ func (r *NotificationPostgresRepository) UpdateNotificationRetries(ctx context.C
 	}
 	return nil
 }
+
+func (r *NotificationPostgresRepository) UpdateNotificationNextAttemptAt(ctx context.Context, id uuid.UUID, nextAttemptAt time.Time) error {
+	query := `
+		update notifications
+		set next_attempt_at = $1
+		where id = $2
+	`
+	_, err := r.db.Pool.Exec(ctx, query, nextAttemptAt, id)
+	if err != nil {
+		return fmt.Errorf("NotificationPostgresRepository.UpdateNotificationNextAttemptAt error: %w", err)
+	}
+	return nil
+}
+
+// DeferNotification keeps a pending notification back until the time and records why.
+func (r *NotificationPostgresRepository) DeferNotification(ctx context.Context, id uuid.UUID, until time.Time, reason string) error {
+	query := `
+		update notifications
+		set next_attempt_at = $1,
+			status_reason = $2
+		where id = $3 and status = $4
+	`
+	_, err := r.db.Pool.Exec(ctx, query, until, reason, id, entities.StatusPending)
+	if err != nil {
+		return fmt.Errorf("NotificationPostgresRepository.DeferNotification error: %w", err)
+	}
+	return nil
+}
+
+// UpdateNotificationStatusWithReason sets a final status that needs an explanation, e.g. suppressed.
+func (r *NotificationPostgresRepository) UpdateNotificationStatusWithReason(ctx context.Context, id uuid.UUID, status, reason string) error {
+	query := `
+		update notifications
+		set status = $1,
+			status_reason = $2
+		where id = $3
+	`
+	_, err := r.db.Pool.Exec(ctx, query, status, reason, id)
+	if err != nil {
+		return fmt.Errorf("NotificationPostgresRepository.UpdateNotificationStatusWithReason error: %w", err)
+	}
+	return nil
+}
+
+func scanNotification(row pgx.Row, notification *entities.Notification) error {
+	return row.Scan(
+		&notification.ID,
+		&notification.DeliveryType,
+		&notification.Recipient,
+		&notification.Content,
+		&notification.Status,
+		&notification.Priority,
+		&notification.Retries,
+		&notification.CreatedAt,
+		&notification.SentAt,
+		&notification.NextAttemptAt,
+		&notification.ParentID,
+		&notification.ChainStep,
+		&notification.UserID,
+		&notification.Category,
+		&notification.StatusReason,
+		&notification.DigestKey,
+		&notification.DigestWindowSeconds,
+		&notification.SummaryID,
+		&notification.RecurringID,
+		&notification.BroadcastID,
+		&notification.ImportID,
+		&notification.ClientID,
+		&notification.TenantID,
+	)
+}
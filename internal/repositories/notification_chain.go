@@ -0,0 +1,380 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"notification_system/internal/entities"
+	"notification_system/pkg/database"
+	"notification_system/pkg/envelope"
+)
+
+type NotificationChainPostgresRepository struct {
+	db     *database.PostgresDatabase
+	cipher *envelope.Cipher
+}
+
+func NewNotificationChainPostgresRepository(db *database.PostgresDatabase, cipher *envelope.Cipher) NotificationChainRepository {
+	return &NotificationChainPostgresRepository{db: db, cipher: cipher}
+}
+
+// insertNotificationChain stores the chain notification with its channels and creates the first step.
+// The chain is scanned back with its content sealed, the caller opens it after the commit.
+func insertNotificationChain(ctx context.Context, tx pgx.Tx, c *envelope.Cipher, chain *entities.Notification, channels []*entities.NotificationChannel) error {
+	if len(channels) == 0 {
+		return ErrEmptyChain
+	}
+	sealed, err := sealFields(ctx, c, chain.Recipient, chain.Content)
+	if err != nil {
+		return fmt.Errorf("encrypt chain error: %w", err)
+	}
+	sealedChannels := make([]*entities.NotificationChannel, len(channels))
+	for i, channel := range channels {
+		if sealedChannels[i], err = sealChannel(ctx, c, channel); err != nil {
+			return fmt.Errorf("encrypt channel error: %w", err)
+		}
+	}
+
+	query := fmt.Sprintf(`
+		insert into notifications (delivery_type, recipient, content, priority, status, user_id, category, client_id,
+			tenant_id, recipient_hash)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		returning %s
+	`, notificationColumns)
+	row := tx.QueryRow(ctx, query,
+		entities.DeliveryTypeChain,
+		sealed.recipient,
+		sealed.content,
+		chain.Priority,
+		entities.StatusInProgress,
+		chain.UserID,
+		chain.Category,
+		chain.ClientID,
+		chain.TenantID,
+		sealed.recipientHash,
+	)
+	if err := scanNotification(row, chain); err != nil {
+		return fmt.Errorf("insert chain error: %w", err)
+	}
+
+	for i, channel := range sealedChannels {
+		channel.NotificationID = chain.ID
+		channels[i].NotificationID = chain.ID
+		query := `
+			insert into notification_channels
+				(notification_id, step, delivery_type, recipient, content, timeout_seconds, condition, recipient_hash)
+			values ($1, $2, $3, $4, $5, $6, $7, $8)
+		`
+		_, err := tx.Exec(ctx, query,
+			channel.NotificationID,
+			channel.Step,
+			channel.DeliveryType,
+			channel.Recipient,
+			channel.Content,
+			channel.TimeoutSeconds,
+			channel.Condition,
+			recipientHash(c, channels[i].Recipient),
+		)
+		if err != nil {
+			return fmt.Errorf("insert channel error: %w", err)
+		}
+	}
+	return insertChainStep(ctx, tx, chain, sealedChannels[0])
+}
+
+func (r *NotificationChainPostgresRepository) GetNotificationChannels(ctx context.Context, notificationID uuid.UUID) ([]*entities.NotificationChannel, error) {
+	query := `
+		select notification_id, step, delivery_type, recipient, content, timeout_seconds, condition
+		from notification_channels
+		where notification_id = $1
+		order by step
+	`
+	rows, err := r.db.Pool.Query(ctx, query, notificationID)
+	if err != nil {
+		return nil, fmt.Errorf("NotificationChainPostgresRepository.GetNotificationChannels query error: %w", err)
+	}
+	defer rows.Close()
+
+	channels := make([]*entities.NotificationChannel, 0)
+	for rows.Next() {
+		channel := &entities.NotificationChannel{}
+		if err := scanNotificationChannel(rows, channel); err != nil {
+			return nil, fmt.Errorf("NotificationChainPostgresRepository.GetNotificationChannels scan error: %w", err)
+		}
+		channels = append(channels, channel)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("NotificationChainPostgresRepository.GetNotificationChannels rows error: %w", err)
+	}
+	if err := openNotificationChannels(ctx, r.cipher, channels); err != nil {
+		return nil, fmt.Errorf("NotificationChainPostgresRepository.GetNotificationChannels decrypt error: %w", err)
+	}
+	return channels, nil
+}
+
+func (r *NotificationChainPostgresRepository) GetNotificationsByParentID(ctx context.Context, parentID uuid.UUID) ([]*entities.Notification, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from notifications
+		where parent_id = $1
+		order by chain_step, created_at
+	`, notificationColumns)
+	rows, err := r.db.Pool.Query(ctx, query, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("NotificationChainPostgresRepository.GetNotificationsByParentID query error: %w", err)
+	}
+	defer rows.Close()
+
+	notifications := make([]*entities.Notification, 0)
+	for rows.Next() {
+		notification := &entities.Notification{}
+		if err := scanNotification(rows, notification); err != nil {
+			return nil, fmt.Errorf("NotificationChainPostgresRepository.GetNotificationsByParentID scan error: %w", err)
+		}
+		notifications = append(notifications, notification)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("NotificationChainPostgresRepository.GetNotificationsByParentID rows error: %w", err)
+	}
+	if err := openNotifications(ctx, r.cipher, notifications...); err != nil {
+		return nil, fmt.Errorf("NotificationChainPostgresRepository.GetNotificationsByParentID decrypt error: %w", err)
+	}
+	return notifications, nil
+}
+
+// AdvanceNotificationChain is called when the step ended without delivery. Depending on the step
+// condition it creates the next step or fails the whole chain.
+func (r *NotificationChainPostgresRepository) AdvanceNotificationChain(ctx context.Context, parentID uuid.UUID, step int16, timedOut bool) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("NotificationChainPostgresRepository.AdvanceNotificationChain begin error: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := advanceNotificationChain(ctx, tx, parentID, step, timedOut); err != nil {
+		return fmt.Errorf("NotificationChainPostgresRepository.AdvanceNotificationChain %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("NotificationChainPostgresRepository.AdvanceNotificationChain commit error: %w", err)
+	}
+	return nil
+}
+
+// CompleteNotificationChain finishes the chain with the given status and
+// expires steps that have not been sent yet.
+func (r *NotificationChainPostgresRepository) CompleteNotificationChain(ctx context.Context, parentID uuid.UUID, status string) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("NotificationChainPostgresRepository.CompleteNotificationChain begin error: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := completeNotificationChain(ctx, tx, parentID, status); err != nil {
+		return fmt.Errorf("NotificationChainPostgresRepository.CompleteNotificationChain %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("NotificationChainPostgresRepository.CompleteNotificationChain commit error: %w", err)
+	}
+	return nil
+}
+
+// ExpireTimedOutChainSteps expires steps that were not delivered within their timeout
+// and advances their chains. It returns the number of expired steps.
+func (r *NotificationChainPostgresRepository) ExpireTimedOutChainSteps(ctx context.Context, limit uint) (int, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("NotificationChainPostgresRepository.ExpireTimedOutChainSteps begin error: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		update notifications
+		set status = $1
+		where id in (
+			select n.id
+			from notifications n
+			join notification_channels c on c.notification_id = n.parent_id and c.step = n.chain_step
+			where n.parent_id is not null
+				and n.status in ($2, $3)
+				and c.timeout_seconds is not null
+				and c.condition in ($4, $5)
+				and n.created_at + make_interval(secs => c.timeout_seconds) <= now()
+			limit $6
+			for update of n skip locked
+		)
+		returning parent_id, chain_step
+	`
+	rows, err := tx.Query(ctx, query,
+		entities.StatusExpired,
+		entities.StatusPending,
+		entities.StatusInQueue,
+		entities.ChainConditionFailedOrTimeout,
+		entities.ChainConditionTimeout,
+		limit,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("NotificationChainPostgresRepository.ExpireTimedOutChainSteps query error: %w", err)
+	}
+	type expiredStep struct {
+		parentID uuid.UUID
+		step     int16
+	}
+	var expired []expiredStep
+	for rows.Next() {
+		var step expiredStep
+		if err := rows.Scan(&step.parentID, &step.step); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("NotificationChainPostgresRepository.ExpireTimedOutChainSteps scan error: %w", err)
+		}
+		expired = append(expired, step)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("NotificationChainPostgresRepository.ExpireTimedOutChainSteps rows error: %w", err)
+	}
+
+	for _, step := range expired {
+		if err := advanceNotificationChain(ctx, tx, step.parentID, step.step, true); err != nil {
+			return 0, fmt.Errorf("NotificationChainPostgresRepository.ExpireTimedOutChainSteps %w", err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("NotificationChainPostgresRepository.ExpireTimedOutChainSteps commit error: %w", err)
+	}
+	return len(expired), nil
+}
+
+func advanceNotificationChain(ctx context.Context, tx pgx.Tx, parentID uuid.UUID, step int16, timedOut bool) error {
+	chain := &entities.Notification{}
+	query := fmt.Sprintf(`
+		select %s
+		from notifications
+		where id = $1
+		for update
+	`, notificationColumns)
+	if err := scanNotification(tx.QueryRow(ctx, query, parentID), chain); err != nil {
+		return fmt.Errorf("lock chain error: %w", err)
+	}
+	if chain.Status != entities.StatusInProgress {
+		return nil
+	}
+
+	query = `
+		select notification_id, step, delivery_type, recipient, content, timeout_seconds, condition
+		from notification_channels
+		where notification_id = $1 and step in ($2, $2 + 1)
+		order by step
+	`
+	rows, err := tx.Query(ctx, query, parentID, step)
+	if err != nil {
+		return fmt.Errorf("get channels error: %w", err)
+	}
+	var current, next *entities.NotificationChannel
+	for rows.Next() {
+		channel := &entities.NotificationChannel{}
+		if err := scanNotificationChannel(rows, channel); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan channel error: %w", err)
+		}
+		if channel.Step == step {
+			current = channel
+		} else {
+			next = channel
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("get channels rows error: %w", err)
+	}
+	if current == nil {
+		return fmt.Errorf("chain step %d: %w", step, ErrNotFound)
+	}
+
+	fallBack := (timedOut && current.Condition != entities.ChainConditionFailed) ||
+		(!timedOut && current.Condition != entities.ChainConditionTimeout)
+	if !fallBack || next == nil {
+		return completeNotificationChain(ctx, tx, parentID, entities.StatusFailed)
+	}
+	return insertChainStep(ctx, tx, chain, next)
+}
+
+func completeNotificationChain(ctx context.Context, tx pgx.Tx, parentID uuid.UUID, status string) error {
+	query := fmt.Sprintf(`
+		update notifications
+		set status = $1,
+			sent_at = case when $1 = '%s' then now() else sent_at end
+		where id = $2 and status = $3
+	`, entities.StatusDelivered)
+	tag, err := tx.Exec(ctx, query, status, parentID, entities.StatusInProgress)
+	if err != nil {
+		return fmt.Errorf("update chain status error: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil
+	}
+	query = `
+		update notifications
+		set status = $1
+		where parent_id = $2 and status = $3
+	`
+	_, err = tx.Exec(ctx, query, entities.StatusExpired, parentID, entities.StatusPending)
+	if err != nil {
+		return fmt.Errorf("expire pending steps error: %w", err)
+	}
+	return nil
+}
+
+// insertChainStep creates the step from the stored chain and channel, the encrypted values are
+// copied as they are together with the recipient hash of the channel.
+func insertChainStep(ctx context.Context, tx pgx.Tx, chain *entities.Notification, channel *entities.NotificationChannel) error {
+	content := chain.Content
+	if channel.Content != nil {
+		content = *channel.Content
+	}
+	query := `
+		insert into notifications (delivery_type, recipient, content, priority, parent_id, chain_step, user_id, category,
+			client_id, tenant_id, recipient_hash)
+		select $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, (
+			select recipient_hash from notification_channels where notification_id = $5 and step = $6
+		)
+		where not exists (
+			select 1 from notifications where parent_id = $5 and chain_step = $6
+		)
+	`
+	_, err := tx.Exec(ctx, query,
+		channel.DeliveryType,
+		channel.Recipient,
+		content,
+		chain.Priority,
+		chain.ID,
+		channel.Step,
+		chain.UserID,
+		chain.Category,
+		chain.ClientID,
+		chain.TenantID,
+	)
+	if err != nil {
+		return fmt.Errorf("insert chain step error: %w", err)
+	}
+	return nil
+}
+
+func scanNotificationChannel(row pgx.Row, channel *entities.NotificationChannel) error {
+	err := row.Scan(
+		&channel.NotificationID,
+		&channel.Step,
+		&channel.DeliveryType,
+		&channel.Recipient,
+		&channel.Content,
+		&channel.TimeoutSeconds,
+		&channel.Condition,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
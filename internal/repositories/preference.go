@@ -0,0 +1,267 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"notification_system/internal/entities"
+	"notification_system/pkg/database"
+)
+
+type PreferencePostgresRepository struct {
+	db *database.PostgresDatabase
+}
+
+func NewPreferencePostgresRepository(db *database.PostgresDatabase) PreferenceRepository {
+	return &PreferencePostgresRepository{db: db}
+}
+
+func (r *PreferencePostgresRepository) GetPreferences(ctx context.Context, tenantID *uuid.UUID, userID string) ([]*entities.NotificationPreference, error) {
+	query := `
+		select user_id, tenant_id, category, delivery_type, opted_in, muted_until, updated_at
+		from notification_preferences
+		where tenant_key = $1 and user_id = $2
+		order by category, delivery_type
+	`
+	rows, err := r.db.Pool.Query(ctx, query, tenantKey(tenantID), userID)
+	if err != nil {
+		return nil, fmt.Errorf("PreferencePostgresRepository.GetPreferences query error: %w", err)
+	}
+	defer rows.Close()
+
+	preferences := make([]*entities.NotificationPreference, 0)
+	for rows.Next() {
+		preference := &entities.NotificationPreference{}
+		err := rows.Scan(
+			&preference.UserID,
+			&preference.TenantID,
+			&preference.Category,
+			&preference.DeliveryType,
+			&preference.OptedIn,
+			&preference.MutedUntil,
+			&preference.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("PreferencePostgresRepository.GetPreferences scan error: %w", err)
+		}
+		preferences = append(preferences, preference)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("PreferencePostgresRepository.GetPreferences rows error: %w", err)
+	}
+	return preferences, nil
+}
+
+func (r *PreferencePostgresRepository) UpsertPreferences(ctx context.Context, preferences []*entities.NotificationPreference) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("PreferencePostgresRepository.UpsertPreferences begin error: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		insert into notification_preferences (user_id, tenant_id, category, delivery_type, opted_in, muted_until)
+		values ($1, $2, $3, $4, $5, $6)
+		on conflict (tenant_key, user_id, category, delivery_type) do update
+		set opted_in = excluded.opted_in,
+			muted_until = excluded.muted_until,
+			updated_at = now()
+		returning updated_at
+	`
+	for _, preference := range preferences {
+		err := tx.QueryRow(ctx, query,
+			preference.UserID,
+			preference.TenantID,
+			preference.Category,
+			preference.DeliveryType,
+			preference.OptedIn,
+			preference.MutedUntil,
+		).Scan(&preference.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("PreferencePostgresRepository.UpsertPreferences error: %w", err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("PreferencePostgresRepository.UpsertPreferences commit error: %w", err)
+	}
+	return nil
+}
+
+func (r *PreferencePostgresRepository) DeletePreference(ctx context.Context, tenantID *uuid.UUID, userID, category, deliveryType string) error {
+	query := `
+		delete from notification_preferences
+		where tenant_key = $1 and user_id = $2 and category = $3 and delivery_type = $4
+	`
+	tag, err := r.db.Pool.Exec(ctx, query, tenantKey(tenantID), userID, category, deliveryType)
+	if err != nil {
+		return fmt.Errorf("PreferencePostgresRepository.DeletePreference error: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PreferencePostgresRepository) GetCategories(ctx context.Context) ([]*entities.NotificationCategory, error) {
+	query := `
+		select name, description, suppressible, created_at
+		from notification_categories
+		order by name
+	`
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("PreferencePostgresRepository.GetCategories query error: %w", err)
+	}
+	defer rows.Close()
+
+	categories := make([]*entities.NotificationCategory, 0)
+	for rows.Next() {
+		category := &entities.NotificationCategory{}
+		err := rows.Scan(&category.Name, &category.Description, &category.Suppressible, &category.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("PreferencePostgresRepository.GetCategories scan error: %w", err)
+		}
+		categories = append(categories, category)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("PreferencePostgresRepository.GetCategories rows error: %w", err)
+	}
+	return categories, nil
+}
+
+func (r *PreferencePostgresRepository) UpsertCategory(ctx context.Context, category *entities.NotificationCategory) error {
+	query := `
+		insert into notification_categories (name, description, suppressible)
+		values ($1, $2, $3)
+		on conflict (name) do update
+		set description = excluded.description,
+			suppressible = excluded.suppressible
+		returning created_at
+	`
+	err := r.db.Pool.QueryRow(ctx, query, category.Name, category.Description, category.Suppressible).Scan(&category.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("PreferencePostgresRepository.UpsertCategory error: %w", err)
+	}
+	return nil
+}
+
+// IsCategorySuppressible reports whether preferences apply to the category. Unknown categories are suppressible.
+func (r *PreferencePostgresRepository) IsCategorySuppressible(ctx context.Context, name string) (bool, error) {
+	query := `
+		select suppressible
+		from notification_categories
+		where name = $1
+	`
+	var suppressible bool
+	err := r.db.Pool.QueryRow(ctx, query, name).Scan(&suppressible)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return true, nil
+		}
+		return false, fmt.Errorf("PreferencePostgresRepository.IsCategorySuppressible error: %w", err)
+	}
+	return suppressible, nil
+}
+
+// CreateUnsubscribe records that the address unsubscribed from the category of the tenant.
+func (r *PreferencePostgresRepository) CreateUnsubscribe(ctx context.Context, tenantID *uuid.UUID, deliveryType, address, category string) error {
+	query := `
+		insert into unsubscribes (tenant_id, delivery_type, address, category)
+		values ($1, $2, $3, $4)
+		on conflict do nothing
+	`
+	_, err := r.db.Pool.Exec(ctx, query, tenantID, deliveryType, address, category)
+	if err != nil {
+		return fmt.Errorf("PreferencePostgresRepository.CreateUnsubscribe error: %w", err)
+	}
+	return nil
+}
+
+// GetSuppressionReason checks the unsubscribes of the address and the preferences
+// of the user the notification is addressed to, both in the tenant of the notification.
+// Notifications sent to a raw address are matched to a user through the contact registry
+// of the tenant of the notification.
+// An empty reason means the notification may be sent.
+func (r *PreferencePostgresRepository) GetSuppressionReason(ctx context.Context, notification *entities.Notification) (string, error) {
+	category := entities.PreferenceAny
+	if notification.Category != nil {
+		category = *notification.Category
+		suppressible, err := r.IsCategorySuppressible(ctx, category)
+		if err != nil {
+			return "", fmt.Errorf("PreferencePostgresRepository.GetSuppressionReason %w", err)
+		}
+		if !suppressible {
+			return "", nil
+		}
+	}
+
+	if notification.Recipient != "" {
+		query := `
+			select exists (
+				select 1
+				from unsubscribes
+				where tenant_key = $5 and delivery_type = $1 and address = $2 and category in ($3, $4)
+			)
+		`
+		var unsubscribed bool
+		err := r.db.Pool.QueryRow(ctx, query,
+			notification.DeliveryType,
+			notification.Recipient,
+			category,
+			entities.PreferenceAny,
+			tenantKey(notification.TenantID),
+		).Scan(&unsubscribed)
+		if err != nil {
+			return "", fmt.Errorf("PreferencePostgresRepository.GetSuppressionReason unsubscribes error: %w", err)
+		}
+		if unsubscribed {
+			return entities.SuppressionReasonUnsubscribed, nil
+		}
+	}
+
+	query := `
+		with target as (
+			select coalesce($1::text, (
+				select user_id
+				from contact_addresses
+				where tenant_key = $6 and delivery_type = $3 and address = $4
+				order by verified_at desc nulls last
+				limit 1
+			)) as user_id
+		)
+		select p.opted_in, coalesce(p.muted_until > now(), false)
+		from notification_preferences p
+		join target t on t.user_id = p.user_id
+		where p.tenant_key = $6
+			and p.category in ($2, $5)
+			and p.delivery_type in ($3, $5)
+		order by (p.category <> $5) desc, (p.delivery_type <> $5) desc
+		limit 1
+	`
+	var optedIn, muted bool
+	err := r.db.Pool.QueryRow(ctx, query,
+		notification.UserID,
+		category,
+		notification.DeliveryType,
+		notification.Recipient,
+		entities.PreferenceAny,
+		tenantKey(notification.TenantID),
+	).Scan(&optedIn, &muted)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("PreferencePostgresRepository.GetSuppressionReason error: %w", err)
+	}
+	switch {
+	case !optedIn:
+		return entities.SuppressionReasonOptedOut, nil
+	case muted:
+		return entities.SuppressionReasonMuted, nil
+	}
+	return "", nil
+}
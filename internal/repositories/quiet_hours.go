@@ -0,0 +1,182 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"notification_system/internal/entities"
+	"notification_system/pkg/database"
+)
+
+const quietHoursColumns = "user_id, tenant_id, category, start_minute, end_minute, time_zone, updated_at"
+
+type QuietHoursPostgresRepository struct {
+	db *database.PostgresDatabase
+}
+
+func NewQuietHoursPostgresRepository(db *database.PostgresDatabase) QuietHoursRepository {
+	return &QuietHoursPostgresRepository{db: db}
+}
+
+// GetQuietHours lists the rules of the tenant, empty filters match every user or category.
+func (r *QuietHoursPostgresRepository) GetQuietHours(ctx context.Context, tenantID *uuid.UUID, userID, category string) ([]*entities.QuietHours, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from quiet_hours
+		where tenant_key = $3
+			and ($1 = '' or user_id = $1)
+			and ($2 = '' or category = $2)
+		order by user_id, category
+	`, quietHoursColumns)
+	rows, err := r.db.Pool.Query(ctx, query, userID, category, tenantKey(tenantID))
+	if err != nil {
+		return nil, fmt.Errorf("QuietHoursPostgresRepository.GetQuietHours query error: %w", err)
+	}
+	defer rows.Close()
+
+	quietHours := make([]*entities.QuietHours, 0)
+	for rows.Next() {
+		rule := &entities.QuietHours{}
+		if err := scanQuietHours(rows, rule); err != nil {
+			return nil, fmt.Errorf("QuietHoursPostgresRepository.GetQuietHours scan error: %w", err)
+		}
+		quietHours = append(quietHours, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("QuietHoursPostgresRepository.GetQuietHours rows error: %w", err)
+	}
+	return quietHours, nil
+}
+
+func (r *QuietHoursPostgresRepository) UpsertQuietHours(ctx context.Context, quietHours *entities.QuietHours) error {
+	query := `
+		insert into quiet_hours (user_id, tenant_id, category, start_minute, end_minute, time_zone)
+		values ($1, $2, $3, $4, $5, $6)
+		on conflict (tenant_key, user_id, category) do update
+		set start_minute = excluded.start_minute,
+			end_minute = excluded.end_minute,
+			time_zone = excluded.time_zone,
+			updated_at = now()
+		returning updated_at
+	`
+	err := r.db.Pool.QueryRow(ctx, query,
+		quietHours.UserID,
+		quietHours.TenantID,
+		quietHours.Category,
+		quietHours.StartMinute,
+		quietHours.EndMinute,
+		quietHours.TimeZone,
+	).Scan(&quietHours.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("QuietHoursPostgresRepository.UpsertQuietHours error: %w", err)
+	}
+	return nil
+}
+
+func (r *QuietHoursPostgresRepository) DeleteQuietHours(ctx context.Context, tenantID *uuid.UUID, userID, category string) error {
+	query := `
+		delete from quiet_hours
+		where tenant_key = $1 and user_id = $2 and category = $3
+	`
+	tag, err := r.db.Pool.Exec(ctx, query, tenantKey(tenantID), userID, category)
+	if err != nil {
+		return fmt.Errorf("QuietHoursPostgresRepository.DeleteQuietHours error: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetQuietHoursForNotifications returns the most specific rule of the tenant of each notification
+// that has one. The time zone of the returned rule is the one of the recipient when the contact has one.
+func (r *QuietHoursPostgresRepository) GetQuietHoursForNotifications(ctx context.Context, notifications []*entities.Notification) (map[uuid.UUID]*entities.QuietHours, error) {
+	// the recipients are encrypted at rest, so they are passed along decrypted
+	ids := make([]uuid.UUID, len(notifications))
+	deliveryTypes := make([]string, len(notifications))
+	recipients := make([]string, len(notifications))
+	userIDs := make([]*string, len(notifications))
+	categories := make([]string, len(notifications))
+	tenantKeys := make([]uuid.UUID, len(notifications))
+	for i, notification := range notifications {
+		ids[i] = notification.ID
+		tenantKeys[i] = tenantKey(notification.TenantID)
+		deliveryTypes[i] = notification.DeliveryType
+		recipients[i] = notification.Recipient
+		userIDs[i] = notification.UserID
+		if notification.Category != nil {
+			categories[i] = *notification.Category
+		}
+	}
+	query := `
+		with targets as (
+			select n.id, n.tenant_key,
+				coalesce(n.user_id, (
+					select a.user_id
+					from contact_addresses a
+					where a.tenant_key = n.tenant_key and a.delivery_type = n.delivery_type and a.address = n.recipient
+					order by a.is_primary desc, a.verified_at desc nulls last
+					limit 1
+				)) as user_id,
+				n.category
+			from unnest($1::uuid[], $2::text[], $3::text[], $4::text[], $5::text[], $6::uuid[])
+				as n(id, delivery_type, recipient, user_id, category, tenant_key)
+		)
+		select t.id, q.user_id, q.tenant_id, q.category, q.start_minute, q.end_minute,
+			coalesce(nullif(c.time_zone, ''), q.time_zone), q.updated_at
+		from targets t
+		join lateral (
+			select *
+			from quiet_hours q
+			where q.tenant_key = t.tenant_key
+				and q.user_id in (t.user_id, '*') and q.category in (t.category, '*')
+			order by q.user_id = '*', q.category = '*'
+			limit 1
+		) q on true
+		left join contacts c on c.tenant_key = t.tenant_key and c.user_id = t.user_id
+	`
+	rows, err := r.db.Pool.Query(ctx, query, ids, deliveryTypes, recipients, userIDs, categories, tenantKeys)
+	if err != nil {
+		return nil, fmt.Errorf("QuietHoursPostgresRepository.GetQuietHoursForNotifications query error: %w", err)
+	}
+	defer rows.Close()
+
+	quietHours := make(map[uuid.UUID]*entities.QuietHours)
+	for rows.Next() {
+		var id uuid.UUID
+		rule := &entities.QuietHours{}
+		err := rows.Scan(
+			&id,
+			&rule.UserID,
+			&rule.TenantID,
+			&rule.Category,
+			&rule.StartMinute,
+			&rule.EndMinute,
+			&rule.TimeZone,
+			&rule.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("QuietHoursPostgresRepository.GetQuietHoursForNotifications scan error: %w", err)
+		}
+		quietHours[id] = rule
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("QuietHoursPostgresRepository.GetQuietHoursForNotifications rows error: %w", err)
+	}
+	return quietHours, nil
+}
+
+func scanQuietHours(row pgx.Row, quietHours *entities.QuietHours) error {
+	return row.Scan(
+		&quietHours.UserID,
+		&quietHours.TenantID,
+		&quietHours.Category,
+		&quietHours.StartMinute,
+		&quietHours.EndMinute,
+		&quietHours.TimeZone,
+		&quietHours.UpdatedAt,
+	)
+}
@@ -0,0 +1,289 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"notification_system/internal/entities"
+	"notification_system/pkg/database"
+)
+
+const clientQuotaColumns = `client_id, delivery_type, daily_limit, monthly_limit, updated_at`
+
+type QuotaPostgresRepository struct {
+	db *database.PostgresDatabase
+}
+
+func NewQuotaPostgresRepository(db *database.PostgresDatabase) QuotaRepository {
+	return &QuotaPostgresRepository{db: db}
+}
+
+func (r *QuotaPostgresRepository) GetClientQuotas(ctx context.Context, clientID uuid.UUID) ([]*entities.ClientQuota, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from client_quotas
+		where client_id = $1
+		order by delivery_type
+	`, clientQuotaColumns)
+	rows, err := r.db.Pool.Query(ctx, query, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("QuotaPostgresRepository.GetClientQuotas query error: %w", err)
+	}
+	defer rows.Close()
+
+	quotas := make([]*entities.ClientQuota, 0)
+	for rows.Next() {
+		quota := &entities.ClientQuota{}
+		if err := scanClientQuota(rows, quota); err != nil {
+			return nil, fmt.Errorf("QuotaPostgresRepository.GetClientQuotas scan error: %w", err)
+		}
+		quotas = append(quotas, quota)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("QuotaPostgresRepository.GetClientQuotas rows error: %w", err)
+	}
+	return quotas, nil
+}
+
+func (r *QuotaPostgresRepository) UpsertClientQuota(ctx context.Context, quota *entities.ClientQuota) error {
+	query := fmt.Sprintf(`
+		insert into client_quotas (client_id, delivery_type, daily_limit, monthly_limit)
+		values ($1, $2, $3, $4)
+		on conflict (client_id, delivery_type) do update
+		set daily_limit = excluded.daily_limit,
+			monthly_limit = excluded.monthly_limit,
+			updated_at = now()
+		returning %s
+	`, clientQuotaColumns)
+	row := r.db.Pool.QueryRow(ctx, query, quota.ClientID, quota.DeliveryType, quota.DailyLimit, quota.MonthlyLimit)
+	if err := scanClientQuota(row, quota); err != nil {
+		if isForeignKeyViolation(err) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("QuotaPostgresRepository.UpsertClientQuota error: %w", err)
+	}
+	return nil
+}
+
+func (r *QuotaPostgresRepository) DeleteClientQuota(ctx context.Context, clientID uuid.UUID, deliveryType string) error {
+	query := `
+		delete from client_quotas
+		where client_id = $1 and delivery_type = $2
+	`
+	tag, err := r.db.Pool.Exec(ctx, query, clientID, deliveryType)
+	if err != nil {
+		return fmt.Errorf("QuotaPostgresRepository.DeleteClientQuota error: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ConsumeQuota adds the counts of notifications per channel to the usage of the client in
+// the day and month of now, all or nothing. The first quota the counts would exceed is
+// returned instead and nothing is counted. Channels without a quota are counted too so
+// the usage shows them.
+func (r *QuotaPostgresRepository) ConsumeQuota(ctx context.Context, clientID uuid.UUID, counts map[string]int32, now time.Time) (*entities.QuotaExceeded, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("QuotaPostgresRepository.ConsumeQuota begin error: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	exceeded, err := consumeQuota(ctx, tx, clientID, counts, now)
+	if err != nil || exceeded != nil {
+		if err != nil {
+			err = fmt.Errorf("QuotaPostgresRepository.ConsumeQuota %w", err)
+		}
+		return exceeded, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("QuotaPostgresRepository.ConsumeQuota commit error: %w", err)
+	}
+	return nil, nil
+}
+
+// consumeQuota counts the notifications against the quotas of the client in the transaction,
+// the transaction must be rolled back when a quota is exceeded.
+func consumeQuota(ctx context.Context, tx pgx.Tx, clientID uuid.UUID, counts map[string]int32, now time.Time) (*entities.QuotaExceeded, error) {
+	quotas := make(map[string]*entities.ClientQuota)
+	query := fmt.Sprintf(`
+		select %s
+		from client_quotas
+		where client_id = $1
+	`, clientQuotaColumns)
+	rows, err := tx.Query(ctx, query, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("quotas error: %w", err)
+	}
+	for rows.Next() {
+		quota := &entities.ClientQuota{}
+		if err := scanClientQuota(rows, quota); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("quotas scan error: %w", err)
+		}
+		quotas[quota.DeliveryType] = quota
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("quotas rows error: %w", err)
+	}
+
+	// a stable order keeps concurrent batches from locking the counters in different orders
+	deliveryTypes := make([]string, 0, len(counts))
+	for deliveryType := range counts {
+		deliveryTypes = append(deliveryTypes, deliveryType)
+	}
+	sort.Strings(deliveryTypes)
+	for _, deliveryType := range deliveryTypes {
+		var daily, monthly *int32
+		if quota, ok := quotas[deliveryType]; ok {
+			daily, monthly = quota.DailyLimit, quota.MonthlyLimit
+		}
+		for _, period := range []struct {
+			name  string
+			limit *int32
+		}{
+			{entities.QuotaPeriodDay, daily},
+			{entities.QuotaPeriodMonth, monthly},
+		} {
+			exceeded, err := addUsage(ctx, tx, clientID, deliveryType, period.name, period.limit, counts[deliveryType], now)
+			if err != nil || exceeded != nil {
+				return exceeded, err
+			}
+		}
+	}
+	return nil, nil
+}
+
+// consumeOwnerQuota counts notifications created in the background for their client, like
+// the notifications of a broadcast, an import or a recurring notification, against the quotas
+// of the client. Notifications without a client have no quotas. An exceeded quota is returned
+// as ErrQuotaExceeded.
+func consumeOwnerQuota(ctx context.Context, tx pgx.Tx, clientID *uuid.UUID, counts map[string]int32) error {
+	if clientID == nil || len(counts) == 0 {
+		return nil
+	}
+	exceeded, err := consumeQuota(ctx, tx, *clientID, counts, time.Now())
+	if err != nil {
+		return err
+	}
+	if exceeded != nil {
+		return fmt.Errorf("%w: %d %s notifications per %s", ErrQuotaExceeded, exceeded.Limit, exceeded.DeliveryType, exceeded.Period)
+	}
+	return nil
+}
+
+// notificationCounts counts the notifications per channel.
+func notificationCounts(notifications []*entities.Notification) map[string]int32 {
+	counts := make(map[string]int32)
+	for _, notification := range notifications {
+		counts[notification.DeliveryType]++
+	}
+	return counts
+}
+
+// addUsage adds the count to the counter of the period unless it would go over the limit.
+func addUsage(ctx context.Context, tx pgx.Tx, clientID uuid.UUID, deliveryType, period string, limit *int32, count int32, now time.Time) (*entities.QuotaExceeded, error) {
+	start := entities.QuotaPeriodStart(period, now)
+	query := `
+		insert into client_usage as u (client_id, delivery_type, period, period_start, count)
+		select $1, $2, $3, $4, $5
+		where $6::integer is null or $5 <= $6
+		on conflict (client_id, delivery_type, period, period_start) do update
+		set count = u.count + excluded.count
+		where $6::integer is null or u.count + excluded.count <= $6
+		returning count
+	`
+	var used int32
+	err := tx.QueryRow(ctx, query, clientID, deliveryType, period, start, count, limit).Scan(&used)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("add usage error: %w", err)
+	}
+	usedQuery := `
+		select count
+		from client_usage
+		where client_id = $1 and delivery_type = $2 and period = $3 and period_start = $4
+	`
+	if err := tx.QueryRow(ctx, usedQuery, clientID, deliveryType, period, start).Scan(&used); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("get usage error: %w", err)
+	}
+	return &entities.QuotaExceeded{
+		DeliveryType: deliveryType,
+		Period:       period,
+		Limit:        *limit,
+		Used:         used,
+		ResetAt:      entities.QuotaPeriodEnd(period, now),
+	}, nil
+}
+
+// ReleaseQuota takes back counts consumed for notifications that were not created after all.
+func (r *QuotaPostgresRepository) ReleaseQuota(ctx context.Context, clientID uuid.UUID, counts map[string]int32, now time.Time) error {
+	deliveryTypes := make([]string, 0, len(counts))
+	released := make([]int32, 0, len(counts))
+	for deliveryType, count := range counts {
+		deliveryTypes = append(deliveryTypes, deliveryType)
+		released = append(released, count)
+	}
+	query := `
+		update client_usage u
+		set count = greatest(u.count - c.count, 0)
+		from unnest($2::text[], $3::integer[]) as c (delivery_type, count)
+		where u.client_id = $1 and u.delivery_type = c.delivery_type
+			and ((u.period = 'day' and u.period_start = $4) or (u.period = 'month' and u.period_start = $5))
+	`
+	_, err := r.db.Pool.Exec(ctx, query, clientID, deliveryTypes, released,
+		entities.QuotaPeriodStart(entities.QuotaPeriodDay, now),
+		entities.QuotaPeriodStart(entities.QuotaPeriodMonth, now),
+	)
+	if err != nil {
+		return fmt.Errorf("QuotaPostgresRepository.ReleaseQuota error: %w", err)
+	}
+	return nil
+}
+
+// GetClientUsage returns the counters of the client for the day and month of now.
+func (r *QuotaPostgresRepository) GetClientUsage(ctx context.Context, clientID uuid.UUID, now time.Time) ([]*entities.ClientUsage, error) {
+	query := `
+		select delivery_type, period, period_start, count
+		from client_usage
+		where client_id = $1
+			and ((period = 'day' and period_start = $2) or (period = 'month' and period_start = $3))
+		order by delivery_type, period
+	`
+	rows, err := r.db.Pool.Query(ctx, query, clientID,
+		entities.QuotaPeriodStart(entities.QuotaPeriodDay, now),
+		entities.QuotaPeriodStart(entities.QuotaPeriodMonth, now),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("QuotaPostgresRepository.GetClientUsage query error: %w", err)
+	}
+	defer rows.Close()
+
+	usage := make([]*entities.ClientUsage, 0)
+	for rows.Next() {
+		counter := &entities.ClientUsage{}
+		if err := rows.Scan(&counter.DeliveryType, &counter.Period, &counter.PeriodStart, &counter.Count); err != nil {
+			return nil, fmt.Errorf("QuotaPostgresRepository.GetClientUsage scan error: %w", err)
+		}
+		usage = append(usage, counter)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("QuotaPostgresRepository.GetClientUsage rows error: %w", err)
+	}
+	return usage, nil
+}
+
+func scanClientQuota(row pgx.Row, quota *entities.ClientQuota) error {
+	return row.Scan(&quota.ClientID, &quota.DeliveryType, &quota.DailyLimit, &quota.MonthlyLimit, &quota.UpdatedAt)
+}
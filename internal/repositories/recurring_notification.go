@@ -0,0 +1,345 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"notification_system/internal/entities"
+	"notification_system/pkg/database"
+	"notification_system/pkg/envelope"
+)
+
+const recurringNotificationColumns = `id, name, schedule_type, schedule, time_zone, starts_at, ends_at,
+	delivery_type, recipients, user_ids, template, variables, priority, category, enabled,
+	next_run_at, last_run_at, occurrences, created_at, updated_at, client_id, tenant_id`
+
+type RecurringNotificationPostgresRepository struct {
+	db     *database.PostgresDatabase
+	cipher *envelope.Cipher
+}
+
+func NewRecurringNotificationPostgresRepository(db *database.PostgresDatabase, cipher *envelope.Cipher) RecurringNotificationRepository {
+	return &RecurringNotificationPostgresRepository{db: db, cipher: cipher}
+}
+
+func (r *RecurringNotificationPostgresRepository) CreateRecurringNotification(ctx context.Context, recurring *entities.RecurringNotification) error {
+	query := fmt.Sprintf(`
+		insert into recurring_notifications (name, schedule_type, schedule, time_zone, starts_at, ends_at,
+			delivery_type, recipients, user_ids, template, variables, priority, category, enabled, next_run_at,
+			client_id, tenant_id)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		returning %s
+	`, recurringNotificationColumns)
+	row := r.db.Pool.QueryRow(ctx, query,
+		recurring.Name,
+		recurring.ScheduleType,
+		recurring.Schedule,
+		recurring.TimeZone,
+		recurring.StartsAt,
+		recurring.EndsAt,
+		recurring.DeliveryType,
+		recurring.Recipients,
+		recurring.UserIDs,
+		recurring.Template,
+		recurring.Variables,
+		recurring.Priority,
+		recurring.Category,
+		recurring.Enabled,
+		recurring.NextRunAt,
+		recurring.ClientID,
+		recurring.TenantID,
+	)
+	if err := scanRecurringNotification(row, recurring); err != nil {
+		return fmt.Errorf("RecurringNotificationPostgresRepository.CreateRecurringNotification error: %w", err)
+	}
+	return nil
+}
+
+// GetRecurringNotifications reads the recurring notifications of the tenant and the client,
+// a nil filter reads all of them.
+func (r *RecurringNotificationPostgresRepository) GetRecurringNotifications(ctx context.Context, tenantID, clientID *uuid.UUID, limit, offset uint) ([]*entities.RecurringNotification, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from recurring_notifications
+		where ($1::uuid is null or tenant_id = $1)
+			and ($2::uuid is null or client_id = $2)
+		order by created_at, id
+		limit $3 offset $4
+	`, recurringNotificationColumns)
+	rows, err := r.db.Pool.Query(ctx, query, tenantID, clientID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("RecurringNotificationPostgresRepository.GetRecurringNotifications query error: %w", err)
+	}
+	defer rows.Close()
+
+	recurringNotifications := make([]*entities.RecurringNotification, 0)
+	for rows.Next() {
+		recurring := &entities.RecurringNotification{}
+		if err := scanRecurringNotification(rows, recurring); err != nil {
+			return nil, fmt.Errorf("RecurringNotificationPostgresRepository.GetRecurringNotifications scan error: %w", err)
+		}
+		recurringNotifications = append(recurringNotifications, recurring)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("RecurringNotificationPostgresRepository.GetRecurringNotifications rows error: %w", err)
+	}
+	return recurringNotifications, nil
+}
+
+// GetRecurringNotification returns the recurring notification of the tenant, a nil tenant reads the recurring notifications of every tenant.
+func (r *RecurringNotificationPostgresRepository) GetRecurringNotification(ctx context.Context, id uuid.UUID, tenantID *uuid.UUID) (*entities.RecurringNotification, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from recurring_notifications
+		where id = $1
+			and ($2::uuid is null or tenant_id = $2)
+	`, recurringNotificationColumns)
+	recurring := &entities.RecurringNotification{}
+	if err := scanRecurringNotification(r.db.Pool.QueryRow(ctx, query, id, tenantID), recurring); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("RecurringNotificationPostgresRepository.GetRecurringNotification error: %w", err)
+	}
+	return recurring, nil
+}
+
+func (r *RecurringNotificationPostgresRepository) UpdateRecurringNotification(ctx context.Context, recurring *entities.RecurringNotification) error {
+	query := fmt.Sprintf(`
+		update recurring_notifications
+		set name = $2,
+			schedule_type = $3,
+			schedule = $4,
+			time_zone = $5,
+			starts_at = $6,
+			ends_at = $7,
+			delivery_type = $8,
+			recipients = $9,
+			user_ids = $10,
+			template = $11,
+			variables = $12,
+			priority = $13,
+			category = $14,
+			enabled = $15,
+			next_run_at = $16,
+			updated_at = now()
+		where id = $1
+		returning %s
+	`, recurringNotificationColumns)
+	row := r.db.Pool.QueryRow(ctx, query,
+		recurring.ID,
+		recurring.Name,
+		recurring.ScheduleType,
+		recurring.Schedule,
+		recurring.TimeZone,
+		recurring.StartsAt,
+		recurring.EndsAt,
+		recurring.DeliveryType,
+		recurring.Recipients,
+		recurring.UserIDs,
+		recurring.Template,
+		recurring.Variables,
+		recurring.Priority,
+		recurring.Category,
+		recurring.Enabled,
+		recurring.NextRunAt,
+	)
+	if err := scanRecurringNotification(row, recurring); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("RecurringNotificationPostgresRepository.UpdateRecurringNotification error: %w", err)
+	}
+	return nil
+}
+
+func (r *RecurringNotificationPostgresRepository) DeleteRecurringNotification(ctx context.Context, id uuid.UUID) error {
+	query := `
+		delete from recurring_notifications
+		where id = $1
+	`
+	tag, err := r.db.Pool.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("RecurringNotificationPostgresRepository.DeleteRecurringNotification error: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetDueRecurringNotifications returns up to limit enabled recurring notifications whose next run has come.
+func (r *RecurringNotificationPostgresRepository) GetDueRecurringNotifications(ctx context.Context, limit uint) ([]*entities.RecurringNotification, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from recurring_notifications
+		where enabled and next_run_at <= now()
+		order by next_run_at
+		limit $1
+	`, recurringNotificationColumns)
+	rows, err := r.db.Pool.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("RecurringNotificationPostgresRepository.GetDueRecurringNotifications query error: %w", err)
+	}
+	defer rows.Close()
+
+	due := make([]*entities.RecurringNotification, 0)
+	for rows.Next() {
+		recurring := &entities.RecurringNotification{}
+		if err := scanRecurringNotification(rows, recurring); err != nil {
+			return nil, fmt.Errorf("RecurringNotificationPostgresRepository.GetDueRecurringNotifications scan error: %w", err)
+		}
+		due = append(due, recurring)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("RecurringNotificationPostgresRepository.GetDueRecurringNotifications rows error: %w", err)
+	}
+	return due, nil
+}
+
+// FireRecurringNotification inserts the notifications of the occurrence at the current
+// next run of the recurring notification and moves it to nextRunAt in one transaction.
+// The row is locked with skip locked and the next run is compared, so an occurrence that
+// another replica is firing or has already fired returns ErrNotFound without inserting.
+// An occurrence that would exceed a quota of the client is skipped and ErrQuotaExceeded
+// is returned.
+func (r *RecurringNotificationPostgresRepository) FireRecurringNotification(
+	ctx context.Context,
+	recurring *entities.RecurringNotification,
+	notifications []*entities.Notification,
+	nextRunAt *time.Time,
+) error {
+	if recurring.NextRunAt == nil {
+		return ErrNotFound
+	}
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("RecurringNotificationPostgresRepository.FireRecurringNotification begin error: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	lockQuery := `
+		select id
+		from recurring_notifications
+		where id = $1 and enabled and next_run_at = $2
+		for update skip locked
+	`
+	var id uuid.UUID
+	if err := tx.QueryRow(ctx, lockQuery, recurring.ID, *recurring.NextRunAt).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("RecurringNotificationPostgresRepository.FireRecurringNotification lock error: %w", err)
+	}
+	quotaErr := consumeOwnerQuota(ctx, tx, recurring.ClientID, notificationCounts(notifications))
+	if quotaErr != nil && !errors.Is(quotaErr, ErrQuotaExceeded) {
+		return fmt.Errorf("RecurringNotificationPostgresRepository.FireRecurringNotification quota %w", quotaErr)
+	}
+	if quotaErr != nil {
+		// the counts added for other channels before the exceeded one are rolled back as well
+		tx.Rollback(ctx)
+		return r.skipOccurrence(ctx, recurring, nextRunAt, quotaErr)
+	}
+	for _, notification := range notifications {
+		if err := insertRecurringOccurrence(ctx, tx, r.cipher, notification); err != nil {
+			return fmt.Errorf("RecurringNotificationPostgresRepository.FireRecurringNotification %w", err)
+		}
+	}
+	updateQuery := `
+		update recurring_notifications
+		set next_run_at = $2,
+			last_run_at = next_run_at,
+			occurrences = occurrences + 1
+		where id = $1
+	`
+	if _, err := tx.Exec(ctx, updateQuery, recurring.ID, nextRunAt); err != nil {
+		return fmt.Errorf("RecurringNotificationPostgresRepository.FireRecurringNotification update error: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("RecurringNotificationPostgresRepository.FireRecurringNotification commit error: %w", err)
+	}
+	return nil
+}
+
+// skipOccurrence moves a recurring notification whose occurrence would exceed a quota of its
+// client to nextRunAt without creating the notifications, so the occurrence is not retried
+// late once the quota allows it. It returns the quota error.
+func (r *RecurringNotificationPostgresRepository) skipOccurrence(ctx context.Context, recurring *entities.RecurringNotification, nextRunAt *time.Time, quotaErr error) error {
+	query := `
+		update recurring_notifications
+		set next_run_at = $3
+		where id = $1 and enabled and next_run_at = $2
+	`
+	tag, err := r.db.Pool.Exec(ctx, query, recurring.ID, *recurring.NextRunAt, nextRunAt)
+	if err != nil {
+		return fmt.Errorf("RecurringNotificationPostgresRepository.FireRecurringNotification skip error: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return quotaErr
+}
+
+func insertRecurringOccurrence(ctx context.Context, tx pgx.Tx, c *envelope.Cipher, notification *entities.Notification) error {
+	sealed, err := sealFields(ctx, c, notification.Recipient, notification.Content)
+	if err != nil {
+		return fmt.Errorf("encrypt occurrence error: %w", err)
+	}
+	query := fmt.Sprintf(`
+		insert into notifications (delivery_type, recipient, content, priority, user_id, category, recurring_id,
+			recipient_hash, client_id, tenant_id)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		returning %s
+	`, notificationColumns)
+	row := tx.QueryRow(ctx, query,
+		notification.DeliveryType,
+		sealed.recipient,
+		sealed.content,
+		notification.Priority,
+		notification.UserID,
+		notification.Category,
+		notification.RecurringID,
+		sealed.recipientHash,
+		notification.ClientID,
+		notification.TenantID,
+	)
+	if err := scanNotification(row, notification); err != nil {
+		return fmt.Errorf("insert occurrence error: %w", err)
+	}
+	if err := openNotifications(ctx, c, notification); err != nil {
+		return fmt.Errorf("decrypt occurrence error: %w", err)
+	}
+	return nil
+}
+
+func scanRecurringNotification(row pgx.Row, recurring *entities.RecurringNotification) error {
+	return row.Scan(
+		&recurring.ID,
+		&recurring.Name,
+		&recurring.ScheduleType,
+		&recurring.Schedule,
+		&recurring.TimeZone,
+		&recurring.StartsAt,
+		&recurring.EndsAt,
+		&recurring.DeliveryType,
+		&recurring.Recipients,
+		&recurring.UserIDs,
+		&recurring.Template,
+		&recurring.Variables,
+		&recurring.Priority,
+		&recurring.Category,
+		&recurring.Enabled,
+		&recurring.NextRunAt,
+		&recurring.LastRunAt,
+		&recurring.Occurrences,
+		&recurring.CreatedAt,
+		&recurring.UpdatedAt,
+		&recurring.ClientID,
+		&recurring.TenantID,
+	)
+}
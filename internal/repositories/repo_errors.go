@@ -1,8 +1,31 @@
 package repositories
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	uniqueViolationCode     = "23505"
+	foreignKeyViolationCode = "23503"
+)
 
 var (
-	ErrMaxBatchSizeExceeded = errors.New("batch size exceeds max allowed limit")
-	ErrNotFound             = errors.New("not found")
+	ErrMaxBatchSizeExceeded    = errors.New("batch size exceeds max allowed limit")
+	ErrNotFound                = errors.New("not found")
+	ErrAlreadyExists           = errors.New("already exists")
+	ErrEmptyChain              = errors.New("notification chain has no channels")
+	ErrInvalidVerificationCode = errors.New("invalid or expired verification code")
+	ErrQuotaExceeded           = errors.New("quota exceeded")
 )
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode
+}
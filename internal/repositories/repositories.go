@@ -2,6 +2,8 @@ package repositories
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -10,10 +12,175 @@ import (
 
 //go:generate mockgen -source=repositories.go -destination=mocks/repositories.go -package=mocks_repositories
 type NotificationRepository interface {
-	GetNotificationByID(ctx context.Context, id uuid.UUID) (*entities.Notification, error)
-	GetNewNotifications(ctx context.Context, limit uint) ([]*entities.Notification, error)
-	GetNotificationsByIDs(ctx context.Context, ids []uuid.UUID) ([]*entities.Notification, error)
+	GetNotificationByID(ctx context.Context, id uuid.UUID, tenantID *uuid.UUID) (*entities.Notification, error)
+	GetNewNotifications(ctx context.Context, limit uint, tenantID *uuid.UUID) ([]*entities.Notification, error)
+	GetNewNotificationsByClientID(ctx context.Context, clientID uuid.UUID, limit uint) ([]*entities.Notification, error)
+	GetNotificationsByIDs(ctx context.Context, ids []uuid.UUID, tenantID *uuid.UUID) ([]*entities.Notification, error)
+	ClaimNotification(ctx context.Context, id uuid.UUID) (*entities.Notification, error)
 	CreateNotifications(ctx context.Context, notifications []*entities.Notification) error
+	CreateNotificationBatch(ctx context.Context, notifications []*entities.Notification, chains []*entities.NotificationChain) error
 	UpdateNotificationsStatus(ctx context.Context, ids []uuid.UUID, status string) error
+	QueueNotifications(ctx context.Context, ids []uuid.UUID) error
 	UpdateNotificationRetries(ctx context.Context, id uuid.UUID, retries uint8) error
+	UpdateNotificationNextAttemptAt(ctx context.Context, id uuid.UUID, nextAttemptAt time.Time) error
+	UpdateNotificationStatusWithReason(ctx context.Context, id uuid.UUID, status, reason string) error
+	DeferNotification(ctx context.Context, id uuid.UUID, until time.Time, reason string) error
+}
+
+type SuppressionRepository interface {
+	CreateSuppression(ctx context.Context, suppression *entities.Suppression) error
+	ImportSuppressions(ctx context.Context, tenantID *uuid.UUID, suppressions []*entities.Suppression) (int, error)
+	GetSuppression(ctx context.Context, tenantID *uuid.UUID, id uuid.UUID) (*entities.Suppression, error)
+	DeleteSuppression(ctx context.Context, tenantID *uuid.UUID, id uuid.UUID) error
+	SearchSuppressions(ctx context.Context, filter *entities.SuppressionFilter) ([]*entities.Suppression, error)
+	GetActiveSuppression(ctx context.Context, tenantID *uuid.UUID, deliveryType, address string) (*entities.Suppression, error)
+}
+
+type WebPushSubscriptionRepository interface {
+	CreateWebPushSubscription(ctx context.Context, subscription *entities.WebPushSubscription) error
+	GetWebPushSubscriptionsByUserID(ctx context.Context, tenantID *uuid.UUID, userID string) ([]*entities.WebPushSubscription, error)
+	DeleteWebPushSubscription(ctx context.Context, tenantID *uuid.UUID, userID, endpoint string) error
+	DeleteWebPushSubscriptionByEndpoint(ctx context.Context, tenantID *uuid.UUID, endpoint string) error
+}
+
+type NotificationChainRepository interface {
+	GetNotificationChannels(ctx context.Context, notificationID uuid.UUID) ([]*entities.NotificationChannel, error)
+	GetNotificationsByParentID(ctx context.Context, parentID uuid.UUID) ([]*entities.Notification, error)
+	AdvanceNotificationChain(ctx context.Context, parentID uuid.UUID, step int16, timedOut bool) error
+	CompleteNotificationChain(ctx context.Context, parentID uuid.UUID, status string) error
+	ExpireTimedOutChainSteps(ctx context.Context, limit uint) (int, error)
+}
+
+type ContactRepository interface {
+	CreateContact(ctx context.Context, contact *entities.Contact, addresses []*entities.ContactAddress) error
+	GetContact(ctx context.Context, tenantID *uuid.UUID, userID string) (*entities.Contact, error)
+	UpdateContact(ctx context.Context, contact *entities.Contact) error
+	DeleteContact(ctx context.Context, tenantID *uuid.UUID, userID string) error
+	CreateContactAddress(ctx context.Context, address *entities.ContactAddress) error
+	GetContactAddresses(ctx context.Context, tenantID *uuid.UUID, userID string) ([]*entities.ContactAddress, error)
+	UpdateContactAddress(ctx context.Context, address *entities.ContactAddress) error
+	DeleteContactAddress(ctx context.Context, tenantID *uuid.UUID, userID string, id uuid.UUID) error
+	SetVerificationCode(ctx context.Context, tenantID *uuid.UUID, userID string, id uuid.UUID, codeHash string, expiresAt time.Time) (*entities.ContactAddress, error)
+	VerifyContactAddress(ctx context.Context, tenantID *uuid.UUID, userID string, id uuid.UUID, codeHash string, maxAttempts int32) (*entities.ContactAddress, error)
+	ResolveAddress(ctx context.Context, tenantID *uuid.UUID, userID, deliveryType string) (string, error)
+}
+
+type PreferenceRepository interface {
+	GetPreferences(ctx context.Context, tenantID *uuid.UUID, userID string) ([]*entities.NotificationPreference, error)
+	UpsertPreferences(ctx context.Context, preferences []*entities.NotificationPreference) error
+	DeletePreference(ctx context.Context, tenantID *uuid.UUID, userID, category, deliveryType string) error
+	GetCategories(ctx context.Context) ([]*entities.NotificationCategory, error)
+	UpsertCategory(ctx context.Context, category *entities.NotificationCategory) error
+	IsCategorySuppressible(ctx context.Context, name string) (bool, error)
+	CreateUnsubscribe(ctx context.Context, tenantID *uuid.UUID, deliveryType, address, category string) error
+	GetSuppressionReason(ctx context.Context, notification *entities.Notification) (string, error)
+}
+
+type QuietHoursRepository interface {
+	GetQuietHours(ctx context.Context, tenantID *uuid.UUID, userID, category string) ([]*entities.QuietHours, error)
+	UpsertQuietHours(ctx context.Context, quietHours *entities.QuietHours) error
+	DeleteQuietHours(ctx context.Context, tenantID *uuid.UUID, userID, category string) error
+	GetQuietHoursForNotifications(ctx context.Context, notifications []*entities.Notification) (map[uuid.UUID]*entities.QuietHours, error)
+}
+
+type FrequencyCapRepository interface {
+	GetFrequencyCaps(ctx context.Context, tenantID *uuid.UUID) ([]*entities.FrequencyCap, error)
+	UpsertFrequencyCap(ctx context.Context, frequencyCap *entities.FrequencyCap) error
+	DeleteFrequencyCap(ctx context.Context, tenantID *uuid.UUID, deliveryType, category string) error
+	TakeFrequencyTokens(ctx context.Context, tenantID *uuid.UUID, deliveryType, category, recipient string) (*entities.FrequencyCapExceeded, error)
+	DeleteIdleFrequencyBuckets(ctx context.Context, limit uint) (int, error)
+}
+
+type DigestRepository interface {
+	GetDigestTemplates(ctx context.Context, tenantID *uuid.UUID) ([]*entities.DigestTemplate, error)
+	GetDigestTemplate(ctx context.Context, tenantID *uuid.UUID, key string) (*entities.DigestTemplate, error)
+	UpsertDigestTemplate(ctx context.Context, template *entities.DigestTemplate) error
+	DeleteDigestTemplate(ctx context.Context, tenantID *uuid.UUID, key string) error
+	GetDueDigests(ctx context.Context, limit uint) ([]*entities.Digest, error)
+	CreateDigestSummary(ctx context.Context, summary *entities.Notification, itemIDs []uuid.UUID) error
+}
+
+type RecurringNotificationRepository interface {
+	CreateRecurringNotification(ctx context.Context, recurring *entities.RecurringNotification) error
+	GetRecurringNotifications(ctx context.Context, tenantID, clientID *uuid.UUID, limit, offset uint) ([]*entities.RecurringNotification, error)
+	GetRecurringNotification(ctx context.Context, id uuid.UUID, tenantID *uuid.UUID) (*entities.RecurringNotification, error)
+	UpdateRecurringNotification(ctx context.Context, recurring *entities.RecurringNotification) error
+	DeleteRecurringNotification(ctx context.Context, id uuid.UUID) error
+	GetDueRecurringNotifications(ctx context.Context, limit uint) ([]*entities.RecurringNotification, error)
+	FireRecurringNotification(ctx context.Context, recurring *entities.RecurringNotification, notifications []*entities.Notification, nextRunAt *time.Time) error
+}
+
+type TopicRepository interface {
+	GetTopics(ctx context.Context, tenantID *uuid.UUID) ([]*entities.Topic, error)
+	GetTopic(ctx context.Context, tenantID *uuid.UUID, name string) (*entities.Topic, error)
+	UpsertTopic(ctx context.Context, topic *entities.Topic) error
+	DeleteTopic(ctx context.Context, tenantID *uuid.UUID, name string) error
+	GetTopicSubscriptions(ctx context.Context, tenantID *uuid.UUID, topic string, limit, offset uint) ([]*entities.TopicSubscription, error)
+	GetUserSubscriptions(ctx context.Context, tenantID *uuid.UUID, userID string) ([]*entities.TopicSubscription, error)
+	Subscribe(ctx context.Context, subscription *entities.TopicSubscription) error
+	Unsubscribe(ctx context.Context, tenantID *uuid.UUID, topic, userID string) error
+}
+
+type BroadcastRepository interface {
+	CreateBroadcast(ctx context.Context, broadcast *entities.Broadcast) error
+	GetBroadcasts(ctx context.Context, tenantID, clientID *uuid.UUID, limit, offset uint) ([]*entities.Broadcast, error)
+	GetBroadcast(ctx context.Context, id uuid.UUID, tenantID *uuid.UUID) (*entities.Broadcast, error)
+	UpdateBroadcastStatus(ctx context.Context, id uuid.UUID, status string, from []string) (*entities.Broadcast, error)
+	ProcessBroadcastChunk(ctx context.Context, chunkSize uint) (*entities.Broadcast, int, error)
+}
+
+type ImportRepository interface {
+	CreateImport(ctx context.Context, imp *entities.Import) error
+	GetImports(ctx context.Context, tenantID, clientID *uuid.UUID, limit, offset uint) ([]*entities.Import, error)
+	GetImport(ctx context.Context, id uuid.UUID, tenantID *uuid.UUID) (*entities.Import, error)
+	StartImport(ctx context.Context, id uuid.UUID) (*entities.Import, error)
+	CopyImportBatch(ctx context.Context, imp *entities.Import, notifications []*entities.Notification, rowErrors []*entities.ImportRowError, failed int) error
+	FinishImport(ctx context.Context, imp *entities.Import, status string, errMessage *string) error
+	GetImportErrors(ctx context.Context, id uuid.UUID, limit, offset uint) ([]*entities.ImportRowError, error)
+}
+
+type ClientRepository interface {
+	CreateClient(ctx context.Context, client *entities.Client, key *entities.APIKey) error
+	GetClients(ctx context.Context) ([]*entities.Client, error)
+	GetClient(ctx context.Context, id uuid.UUID) (*entities.Client, error)
+	GetClientByOAuthClientID(ctx context.Context, oauthClientID string) (*entities.Client, error)
+	UpdateClientOAuthClientID(ctx context.Context, id uuid.UUID, oauthClientID *string) (*entities.Client, error)
+	CreateTenant(ctx context.Context, tenant *entities.Tenant) error
+	GetTenants(ctx context.Context) ([]*entities.Tenant, error)
+	GetTenant(ctx context.Context, id uuid.UUID) (*entities.Tenant, error)
+	CreateAPIKey(ctx context.Context, key *entities.APIKey) error
+	GetAPIKeys(ctx context.Context, clientID uuid.UUID) ([]*entities.APIKey, error)
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (*entities.APIKey, error)
+	RotateAPIKey(ctx context.Context, clientID, oldID uuid.UUID, key *entities.APIKey, expiresAt time.Time) error
+	RevokeAPIKey(ctx context.Context, clientID, id uuid.UUID) error
+}
+
+type QuotaRepository interface {
+	GetClientQuotas(ctx context.Context, clientID uuid.UUID) ([]*entities.ClientQuota, error)
+	UpsertClientQuota(ctx context.Context, quota *entities.ClientQuota) error
+	DeleteClientQuota(ctx context.Context, clientID uuid.UUID, deliveryType string) error
+	ConsumeQuota(ctx context.Context, clientID uuid.UUID, counts map[string]int32, now time.Time) (*entities.QuotaExceeded, error)
+	ReleaseQuota(ctx context.Context, clientID uuid.UUID, counts map[string]int32, now time.Time) error
+	GetClientUsage(ctx context.Context, clientID uuid.UUID, now time.Time) ([]*entities.ClientUsage, error)
+}
+
+type AuditRepository interface {
+	CreateAuditEntry(ctx context.Context, entry *entities.AuditEntry) error
+	SearchAuditEntries(ctx context.Context, filter *entities.AuditFilter) ([]*entities.AuditEntry, error)
+	ExportAuditEntries(ctx context.Context, filter *entities.AuditFilter, fn func(*entities.AuditEntry) error) error
+}
+
+type EncryptionRepository interface {
+	ReencryptNotifications(ctx context.Context, after uuid.UUID, limit uint) (uuid.UUID, int, error)
+	ReencryptTenants(ctx context.Context) (int, error)
+}
+
+type RetentionRepository interface {
+	GetRetentionPolicies(ctx context.Context, tenantID *uuid.UUID) ([]*entities.RetentionPolicy, error)
+	GetAllRetentionPolicies(ctx context.Context) ([]*entities.RetentionPolicy, error)
+	UpsertRetentionPolicy(ctx context.Context, policy *entities.RetentionPolicy) error
+	DeleteRetentionPolicy(ctx context.Context, tenantID *uuid.UUID, status string) error
+	PurgeNotifications(ctx context.Context, policy *entities.RetentionPolicy, now time.Time, limit uint, archive func([]json.RawMessage) error) (int, error)
+	CreateNotificationPartitions(ctx context.Context, from time.Time, months int) error
+	DropEmptyNotificationPartitions(ctx context.Context, before time.Time) ([]string, error)
 }
@@ -0,0 +1,287 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"notification_system/internal/entities"
+	"notification_system/pkg/database"
+)
+
+const (
+	retentionPolicyColumns = `id, tenant_id, status, retention_days, updated_at`
+
+	// monthly partitions are named notifications_YYYY_MM by create_notifications_partition
+	notificationPartitionPrefix = "notifications_"
+	notificationPartitionLayout = "2006_01"
+)
+
+type RetentionPostgresRepository struct {
+	db *database.PostgresDatabase
+}
+
+func NewRetentionPostgresRepository(db *database.PostgresDatabase) RetentionRepository {
+	return &RetentionPostgresRepository{db: db}
+}
+
+// GetRetentionPolicies returns the policies of the tenant, the ones of the operator for nil.
+func (r *RetentionPostgresRepository) GetRetentionPolicies(ctx context.Context, tenantID *uuid.UUID) ([]*entities.RetentionPolicy, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from retention_policies
+		where tenant_id is not distinct from $1
+		order by status
+	`, retentionPolicyColumns)
+	policies, err := r.queryRetentionPolicies(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("RetentionPostgresRepository.GetRetentionPolicies %w", err)
+	}
+	return policies, nil
+}
+
+// GetAllRetentionPolicies returns the policies of all tenants, the ones of the operator first.
+func (r *RetentionPostgresRepository) GetAllRetentionPolicies(ctx context.Context) ([]*entities.RetentionPolicy, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from retention_policies
+		order by tenant_id nulls first, status
+	`, retentionPolicyColumns)
+	policies, err := r.queryRetentionPolicies(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("RetentionPostgresRepository.GetAllRetentionPolicies %w", err)
+	}
+	return policies, nil
+}
+
+func (r *RetentionPostgresRepository) queryRetentionPolicies(ctx context.Context, query string, args ...any) ([]*entities.RetentionPolicy, error) {
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	policies := make([]*entities.RetentionPolicy, 0)
+	for rows.Next() {
+		policy := &entities.RetentionPolicy{}
+		if err := scanRetentionPolicy(rows, policy); err != nil {
+			return nil, fmt.Errorf("scan error: %w", err)
+		}
+		policies = append(policies, policy)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows error: %w", err)
+	}
+	return policies, nil
+}
+
+func (r *RetentionPostgresRepository) UpsertRetentionPolicy(ctx context.Context, policy *entities.RetentionPolicy) error {
+	query := fmt.Sprintf(`
+		insert into retention_policies (tenant_id, status, retention_days)
+		values ($1, $2, $3)
+		on conflict (tenant_id, status) do update
+		set retention_days = excluded.retention_days,
+			updated_at = now()
+		returning %s
+	`, retentionPolicyColumns)
+	row := r.db.Pool.QueryRow(ctx, query, policy.TenantID, policy.Status, policy.RetentionDays)
+	if err := scanRetentionPolicy(row, policy); err != nil {
+		if isForeignKeyViolation(err) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("RetentionPostgresRepository.UpsertRetentionPolicy error: %w", err)
+	}
+	return nil
+}
+
+func (r *RetentionPostgresRepository) DeleteRetentionPolicy(ctx context.Context, tenantID *uuid.UUID, status string) error {
+	query := `
+		delete from retention_policies
+		where tenant_id is not distinct from $1 and status = $2
+	`
+	tag, err := r.db.Pool.Exec(ctx, query, tenantID, status)
+	if err != nil {
+		return fmt.Errorf("RetentionPostgresRepository.DeleteRetentionPolicy error: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// PurgeNotifications deletes up to limit notifications expired under the policy at now, the
+// oldest first, with their chain steps, digest items and channels. The policies of the
+// operator skip the tenants with a policy of their own for the status. Rows locked by
+// another purge are skipped so replicas share the work.
+//
+// The deleted notifications are passed to archive as JSON, one object per notification with
+// its channels and children as stored, before the deletion is committed; an error of
+// archive rolls it back. It returns the number of deleted notifications, chain steps and
+// digest items not counted.
+func (r *RetentionPostgresRepository) PurgeNotifications(ctx context.Context, policy *entities.RetentionPolicy, now time.Time, limit uint, archive func([]json.RawMessage) error) (int, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("RetentionPostgresRepository.PurgeNotifications begin error: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		with expired as (
+			select n.id, n.created_at
+			from notifications n
+			where n.parent_id is null and n.status = $1 and n.created_at < $2
+				and (n.tenant_id = $3::uuid or ($3::uuid is null and (n.tenant_id is null or not exists (
+					select 1 from retention_policies p where p.tenant_id = n.tenant_id and p.status = $1
+				))))
+			order by n.created_at
+			limit $4
+			for update skip locked
+		), roots as (
+			delete from notifications n
+			using expired e
+			where n.id = e.id and n.created_at = e.created_at
+			returning n.*
+		), children as (
+			delete from notifications n
+			where n.parent_id in (select id from roots) or n.summary_id in (select id from roots)
+			returning n.*
+		), channels as (
+			delete from notification_channels c
+			where c.notification_id in (select id from roots)
+			returning c.*
+		)
+		select to_jsonb(r) || jsonb_build_object(
+			'channels', (select coalesce(jsonb_agg(to_jsonb(c) order by c.step), '[]') from channels c where c.notification_id = r.id),
+			'children', (select coalesce(jsonb_agg(to_jsonb(ch) order by ch.created_at), '[]') from children ch
+				where ch.parent_id = r.id or ch.summary_id = r.id)
+		)
+		from roots r
+		order by r.created_at
+	`
+	rows, err := tx.Query(ctx, query, policy.Status, policy.Cutoff(now), policy.TenantID, limit)
+	if err != nil {
+		return 0, fmt.Errorf("RetentionPostgresRepository.PurgeNotifications query error: %w", err)
+	}
+	deleted := make([]json.RawMessage, 0, limit)
+	for rows.Next() {
+		var notification json.RawMessage
+		if err := rows.Scan(&notification); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("RetentionPostgresRepository.PurgeNotifications scan error: %w", err)
+		}
+		deleted = append(deleted, notification)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("RetentionPostgresRepository.PurgeNotifications rows error: %w", err)
+	}
+	if len(deleted) == 0 {
+		return 0, nil
+	}
+	if archive != nil {
+		if err := archive(deleted); err != nil {
+			return 0, fmt.Errorf("RetentionPostgresRepository.PurgeNotifications archive error: %w", err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("RetentionPostgresRepository.PurgeNotifications commit error: %w", err)
+	}
+	return len(deleted), nil
+}
+
+// CreateNotificationPartitions creates the monthly partitions of the notifications for the
+// month of from and the months after it, the ones that exist are kept.
+func (r *RetentionPostgresRepository) CreateNotificationPartitions(ctx context.Context, from time.Time, months int) error {
+	query := `
+		select create_notifications_partition($1::timestamp + make_interval(months => i))
+		from generate_series(0, $2::integer - 1) as i
+	`
+	if _, err := r.db.Pool.Exec(ctx, query, from, months); err != nil {
+		return fmt.Errorf("RetentionPostgresRepository.CreateNotificationPartitions error: %w", err)
+	}
+	return nil
+}
+
+// DropEmptyNotificationPartitions drops the monthly partitions of the notifications that end
+// before the time and that the retention has emptied, and returns their names. A partition
+// still in use is left alone rather than waited for.
+func (r *RetentionPostgresRepository) DropEmptyNotificationPartitions(ctx context.Context, before time.Time) ([]string, error) {
+	query := `
+		select c.relname
+		from pg_inherits i
+		join pg_class c on c.oid = i.inhrelid
+		where i.inhparent = 'notifications'::regclass
+		order by c.relname
+	`
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("RetentionPostgresRepository.DropEmptyNotificationPartitions query error: %w", err)
+	}
+	var partitions []string
+	for rows.Next() {
+		var partition string
+		if err := rows.Scan(&partition); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("RetentionPostgresRepository.DropEmptyNotificationPartitions scan error: %w", err)
+		}
+		partitions = append(partitions, partition)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("RetentionPostgresRepository.DropEmptyNotificationPartitions rows error: %w", err)
+	}
+
+	dropped := make([]string, 0)
+	for _, partition := range partitions {
+		month, err := time.Parse(notificationPartitionLayout, strings.TrimPrefix(partition, notificationPartitionPrefix))
+		if err != nil || month.AddDate(0, 1, 0).After(before) {
+			continue
+		}
+		ok, err := r.dropEmptyPartition(ctx, partition)
+		if err != nil {
+			return dropped, fmt.Errorf("RetentionPostgresRepository.DropEmptyNotificationPartitions %s error: %w", partition, err)
+		}
+		if ok {
+			dropped = append(dropped, partition)
+		}
+	}
+	return dropped, nil
+}
+
+func (r *RetentionPostgresRepository) dropEmptyPartition(ctx context.Context, partition string) (bool, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	// dropping a partition locks the whole table, a short timeout keeps it from queueing
+	// the senders behind a long transaction
+	if _, err := tx.Exec(ctx, "set local lock_timeout = '1s'"); err != nil {
+		return false, err
+	}
+	table := pgx.Identifier{partition}.Sanitize()
+	if _, err := tx.Exec(ctx, "lock table "+table+" in access exclusive mode"); err != nil {
+		return false, err
+	}
+	var used bool
+	if err := tx.QueryRow(ctx, "select exists (select 1 from "+table+")").Scan(&used); err != nil {
+		return false, err
+	}
+	if used {
+		return false, nil
+	}
+	if _, err := tx.Exec(ctx, "drop table "+table); err != nil {
+		return false, err
+	}
+	return true, tx.Commit(ctx)
+}
+
+func scanRetentionPolicy(row pgx.Row, policy *entities.RetentionPolicy) error {
+	return row.Scan(&policy.ID, &policy.TenantID, &policy.Status, &policy.RetentionDays, &policy.UpdatedAt)
+}
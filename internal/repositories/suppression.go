@@ -0,0 +1,209 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"notification_system/config"
+	"notification_system/internal/entities"
+	"notification_system/pkg/database"
+)
+
+const suppressionColumns = "id, tenant_id, delivery_type, address, reason, details, expires_at, created_at"
+
+type SuppressionPostgresRepository struct {
+	db *database.PostgresDatabase
+}
+
+func NewSuppressionPostgresRepository(db *database.PostgresDatabase) SuppressionRepository {
+	return &SuppressionPostgresRepository{db: db}
+}
+
+// CreateSuppression adds the address to the list. An existing entry is replaced so
+// a manual entry can extend or lift the expiry of an automatic one.
+func (r *SuppressionPostgresRepository) CreateSuppression(ctx context.Context, suppression *entities.Suppression) error {
+	query := fmt.Sprintf(`
+		insert into suppressions (tenant_id, delivery_type, address, reason, details, expires_at)
+		values ($1, $2, $3, $4, $5, $6)
+		on conflict (tenant_key, delivery_type, address) do update
+		set reason = excluded.reason,
+			details = excluded.details,
+			expires_at = excluded.expires_at,
+			created_at = now()
+		returning %s
+	`, suppressionColumns)
+	row := r.db.Pool.QueryRow(ctx, query,
+		suppression.TenantID,
+		suppression.DeliveryType,
+		suppression.Address,
+		suppression.Reason,
+		suppression.Details,
+		suppression.ExpiresAt,
+	)
+	if err := scanSuppression(row, suppression); err != nil {
+		return fmt.Errorf("SuppressionPostgresRepository.CreateSuppression error: %w", err)
+	}
+	return nil
+}
+
+// ImportSuppressions inserts the entries into the list of the tenant in one statement and
+// returns how many were stored.
+func (r *SuppressionPostgresRepository) ImportSuppressions(ctx context.Context, tenantID *uuid.UUID, suppressions []*entities.Suppression) (int, error) {
+	if len(suppressions) == 0 {
+		return 0, nil
+	}
+	if len(suppressions) > int(config.Cfg.MaxBatchSize) {
+		return 0, ErrMaxBatchSizeExceeded
+	}
+	deliveryTypes := make([]string, len(suppressions))
+	addresses := make([]string, len(suppressions))
+	reasons := make([]string, len(suppressions))
+	details := make([]string, len(suppressions))
+	expiresAt := make([]*time.Time, len(suppressions))
+	for i, suppression := range suppressions {
+		deliveryTypes[i] = suppression.DeliveryType
+		addresses[i] = suppression.Address
+		reasons[i] = suppression.Reason
+		details[i] = suppression.Details
+		expiresAt[i] = suppression.ExpiresAt
+	}
+	// the last duplicate of an address in the batch wins
+	query := `
+		insert into suppressions (tenant_id, delivery_type, address, reason, details, expires_at)
+		select distinct on (delivery_type, address) $6::uuid, delivery_type, address, reason, details, expires_at
+		from unnest($1::text[], $2::text[], $3::text[], $4::text[], $5::timestamp[])
+			with ordinality as s(delivery_type, address, reason, details, expires_at, n)
+		order by delivery_type, address, n desc
+		on conflict (tenant_key, delivery_type, address) do update
+		set reason = excluded.reason,
+			details = excluded.details,
+			expires_at = excluded.expires_at,
+			created_at = now()
+	`
+	tag, err := r.db.Pool.Exec(ctx, query, deliveryTypes, addresses, reasons, details, expiresAt, tenantID)
+	if err != nil {
+		return 0, fmt.Errorf("SuppressionPostgresRepository.ImportSuppressions error: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+func (r *SuppressionPostgresRepository) GetSuppression(ctx context.Context, tenantID *uuid.UUID, id uuid.UUID) (*entities.Suppression, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from suppressions
+		where id = $1 and tenant_key = $2
+	`, suppressionColumns)
+	suppression := &entities.Suppression{}
+	if err := scanSuppression(r.db.Pool.QueryRow(ctx, query, id, tenantKey(tenantID)), suppression); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("SuppressionPostgresRepository.GetSuppression error: %w", err)
+	}
+	return suppression, nil
+}
+
+func (r *SuppressionPostgresRepository) DeleteSuppression(ctx context.Context, tenantID *uuid.UUID, id uuid.UUID) error {
+	query := `
+		delete from suppressions
+		where id = $1 and tenant_key = $2
+	`
+	tag, err := r.db.Pool.Exec(ctx, query, id, tenantKey(tenantID))
+	if err != nil {
+		return fmt.Errorf("SuppressionPostgresRepository.DeleteSuppression error: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// SearchSuppressions matches the address as a case-insensitive substring.
+func (r *SuppressionPostgresRepository) SearchSuppressions(ctx context.Context, filter *entities.SuppressionFilter) ([]*entities.Suppression, error) {
+	if filter.Limit > config.Cfg.MaxBatchSize {
+		return nil, ErrMaxBatchSizeExceeded
+	}
+	args := []any{tenantKey(filter.TenantID)}
+	conditions := []string{"tenant_key = $1"}
+	if filter.DeliveryType != "" {
+		args = append(args, filter.DeliveryType)
+		conditions = append(conditions, fmt.Sprintf("delivery_type = $%d", len(args)))
+	}
+	if filter.Address != "" {
+		args = append(args, "%"+escapeLike(filter.Address)+"%")
+		conditions = append(conditions, fmt.Sprintf("address ilike $%d", len(args)))
+	}
+	if filter.Reason != "" {
+		args = append(args, filter.Reason)
+		conditions = append(conditions, fmt.Sprintf("reason = $%d", len(args)))
+	}
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`
+		select %s
+		from suppressions
+		where %s
+		order by created_at desc, id
+		limit $%d offset $%d
+	`, suppressionColumns, strings.Join(conditions, " and "), len(args)-1, len(args))
+
+	rows, err := r.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("SuppressionPostgresRepository.SearchSuppressions query error: %w", err)
+	}
+	defer rows.Close()
+
+	suppressions := make([]*entities.Suppression, 0)
+	for rows.Next() {
+		suppression := &entities.Suppression{}
+		if err := scanSuppression(rows, suppression); err != nil {
+			return nil, fmt.Errorf("SuppressionPostgresRepository.SearchSuppressions scan error: %w", err)
+		}
+		suppressions = append(suppressions, suppression)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("SuppressionPostgresRepository.SearchSuppressions rows error: %w", err)
+	}
+	return suppressions, nil
+}
+
+// GetActiveSuppression returns the unexpired entry of the address in the list of the tenant or ErrNotFound.
+func (r *SuppressionPostgresRepository) GetActiveSuppression(ctx context.Context, tenantID *uuid.UUID, deliveryType, address string) (*entities.Suppression, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from suppressions
+		where tenant_key = $1 and delivery_type = $2 and address = $3
+			and (expires_at is null or expires_at > now())
+	`, suppressionColumns)
+	suppression := &entities.Suppression{}
+	err := scanSuppression(r.db.Pool.QueryRow(ctx, query, tenantKey(tenantID), deliveryType, address), suppression)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("SuppressionPostgresRepository.GetActiveSuppression error: %w", err)
+	}
+	return suppression, nil
+}
+
+func scanSuppression(row pgx.Row, suppression *entities.Suppression) error {
+	return row.Scan(
+		&suppression.ID,
+		&suppression.TenantID,
+		&suppression.DeliveryType,
+		&suppression.Address,
+		&suppression.Reason,
+		&suppression.Details,
+		&suppression.ExpiresAt,
+		&suppression.CreatedAt,
+	)
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
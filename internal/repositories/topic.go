@@ -0,0 +1,199 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"notification_system/internal/entities"
+	"notification_system/pkg/database"
+)
+
+const topicColumns = `t.name, t.tenant_id, t.description,
+	(select count(*) from topic_subscriptions s where s.tenant_key = t.tenant_key and s.topic = t.name) as subscribers,
+	t.created_at, t.updated_at`
+
+type TopicPostgresRepository struct {
+	db *database.PostgresDatabase
+}
+
+func NewTopicPostgresRepository(db *database.PostgresDatabase) TopicRepository {
+	return &TopicPostgresRepository{db: db}
+}
+
+func (r *TopicPostgresRepository) GetTopics(ctx context.Context, tenantID *uuid.UUID) ([]*entities.Topic, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from topics t
+		where t.tenant_key = $1
+		order by t.name
+	`, topicColumns)
+	rows, err := r.db.Pool.Query(ctx, query, tenantKey(tenantID))
+	if err != nil {
+		return nil, fmt.Errorf("TopicPostgresRepository.GetTopics query error: %w", err)
+	}
+	defer rows.Close()
+
+	topics := make([]*entities.Topic, 0)
+	for rows.Next() {
+		topic := &entities.Topic{}
+		if err := scanTopic(rows, topic); err != nil {
+			return nil, fmt.Errorf("TopicPostgresRepository.GetTopics scan error: %w", err)
+		}
+		topics = append(topics, topic)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("TopicPostgresRepository.GetTopics rows error: %w", err)
+	}
+	return topics, nil
+}
+
+func (r *TopicPostgresRepository) GetTopic(ctx context.Context, tenantID *uuid.UUID, name string) (*entities.Topic, error) {
+	query := fmt.Sprintf(`
+		select %s
+		from topics t
+		where t.tenant_key = $1 and t.name = $2
+	`, topicColumns)
+	topic := &entities.Topic{}
+	if err := scanTopic(r.db.Pool.QueryRow(ctx, query, tenantKey(tenantID), name), topic); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("TopicPostgresRepository.GetTopic error: %w", err)
+	}
+	return topic, nil
+}
+
+func (r *TopicPostgresRepository) UpsertTopic(ctx context.Context, topic *entities.Topic) error {
+	query := `
+		insert into topics (name, tenant_id, description)
+		values ($1, $2, $3)
+		on conflict (tenant_key, name) do update
+		set description = excluded.description,
+			updated_at = now()
+		returning created_at, updated_at
+	`
+	err := r.db.Pool.QueryRow(ctx, query, topic.Name, topic.TenantID, topic.Description).Scan(&topic.CreatedAt, &topic.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("TopicPostgresRepository.UpsertTopic error: %w", err)
+	}
+	return nil
+}
+
+func (r *TopicPostgresRepository) DeleteTopic(ctx context.Context, tenantID *uuid.UUID, name string) error {
+	query := `
+		delete from topics
+		where tenant_key = $1 and name = $2
+	`
+	tag, err := r.db.Pool.Exec(ctx, query, tenantKey(tenantID), name)
+	if err != nil {
+		return fmt.Errorf("TopicPostgresRepository.DeleteTopic error: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *TopicPostgresRepository) GetTopicSubscriptions(ctx context.Context, tenantID *uuid.UUID, topic string, limit, offset uint) ([]*entities.TopicSubscription, error) {
+	query := `
+		select topic, tenant_id, user_id, created_at
+		from topic_subscriptions
+		where tenant_key = $4 and topic = $1
+		order by user_id
+		limit $2 offset $3
+	`
+	rows, err := r.db.Pool.Query(ctx, query, topic, limit, offset, tenantKey(tenantID))
+	if err != nil {
+		return nil, fmt.Errorf("TopicPostgresRepository.GetTopicSubscriptions query error: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := make([]*entities.TopicSubscription, 0)
+	for rows.Next() {
+		subscription := &entities.TopicSubscription{}
+		if err := rows.Scan(&subscription.Topic, &subscription.TenantID, &subscription.UserID, &subscription.CreatedAt); err != nil {
+			return nil, fmt.Errorf("TopicPostgresRepository.GetTopicSubscriptions scan error: %w", err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("TopicPostgresRepository.GetTopicSubscriptions rows error: %w", err)
+	}
+	return subscriptions, nil
+}
+
+func (r *TopicPostgresRepository) GetUserSubscriptions(ctx context.Context, tenantID *uuid.UUID, userID string) ([]*entities.TopicSubscription, error) {
+	query := `
+		select topic, tenant_id, user_id, created_at
+		from topic_subscriptions
+		where tenant_key = $1 and user_id = $2
+		order by topic
+	`
+	rows, err := r.db.Pool.Query(ctx, query, tenantKey(tenantID), userID)
+	if err != nil {
+		return nil, fmt.Errorf("TopicPostgresRepository.GetUserSubscriptions query error: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := make([]*entities.TopicSubscription, 0)
+	for rows.Next() {
+		subscription := &entities.TopicSubscription{}
+		if err := rows.Scan(&subscription.Topic, &subscription.TenantID, &subscription.UserID, &subscription.CreatedAt); err != nil {
+			return nil, fmt.Errorf("TopicPostgresRepository.GetUserSubscriptions scan error: %w", err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("TopicPostgresRepository.GetUserSubscriptions rows error: %w", err)
+	}
+	return subscriptions, nil
+}
+
+// Subscribe is idempotent, it returns ErrNotFound when the topic or the contact does not exist.
+func (r *TopicPostgresRepository) Subscribe(ctx context.Context, subscription *entities.TopicSubscription) error {
+	query := `
+		insert into topic_subscriptions (topic, tenant_id, user_id)
+		values ($1, $2, $3)
+		on conflict (tenant_key, topic, user_id) do update
+		set topic = excluded.topic
+		returning created_at
+	`
+	err := r.db.Pool.QueryRow(ctx, query, subscription.Topic, subscription.TenantID, subscription.UserID).Scan(&subscription.CreatedAt)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("TopicPostgresRepository.Subscribe error: %w", err)
+	}
+	return nil
+}
+
+func (r *TopicPostgresRepository) Unsubscribe(ctx context.Context, tenantID *uuid.UUID, topic, userID string) error {
+	query := `
+		delete from topic_subscriptions
+		where tenant_key = $1 and topic = $2 and user_id = $3
+	`
+	tag, err := r.db.Pool.Exec(ctx, query, tenantKey(tenantID), topic, userID)
+	if err != nil {
+		return fmt.Errorf("TopicPostgresRepository.Unsubscribe error: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanTopic(row pgx.Row, topic *entities.Topic) error {
+	return row.Scan(
+		&topic.Name,
+		&topic.TenantID,
+		&topic.Description,
+		&topic.Subscribers,
+		&topic.CreatedAt,
+		&topic.UpdatedAt,
+	)
+}
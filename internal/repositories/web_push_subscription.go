@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/entities"
+	"notification_system/pkg/database"
+)
+
+type WebPushSubscriptionPostgresRepository struct {
+	db *database.PostgresDatabase
+}
+
+func NewWebPushSubscriptionPostgresRepository(db *database.PostgresDatabase) WebPushSubscriptionRepository {
+	return &WebPushSubscriptionPostgresRepository{db: db}
+}
+
+func (r *WebPushSubscriptionPostgresRepository) CreateWebPushSubscription(ctx context.Context, subscription *entities.WebPushSubscription) error {
+	// browsers keep the endpoint when keys are refreshed, so the latest subscription wins
+	query := `
+		insert into web_push_subscriptions (user_id, tenant_id, endpoint, p256dh, auth, expiration_time)
+		values ($1, $2, $3, $4, $5, $6)
+		on conflict (tenant_key, endpoint) do update
+		set user_id = excluded.user_id,
+			p256dh = excluded.p256dh,
+			auth = excluded.auth,
+			expiration_time = excluded.expiration_time
+		returning id, created_at
+	`
+	err := r.db.Pool.QueryRow(ctx, query,
+		subscription.UserID,
+		subscription.TenantID,
+		subscription.Endpoint,
+		subscription.P256dh,
+		subscription.Auth,
+		subscription.ExpirationTime,
+	).Scan(&subscription.ID, &subscription.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("WebPushSubscriptionPostgresRepository.CreateWebPushSubscription error: %w", err)
+	}
+	return nil
+}
+
+func (r *WebPushSubscriptionPostgresRepository) GetWebPushSubscriptionsByUserID(ctx context.Context, tenantID *uuid.UUID, userID string) ([]*entities.WebPushSubscription, error) {
+	query := `
+		select id, user_id, tenant_id, endpoint, p256dh, auth, expiration_time, created_at
+		from web_push_subscriptions
+		where tenant_key = $1 and user_id = $2
+			and (expiration_time is null or expiration_time > now())
+		order by created_at
+	`
+	rows, err := r.db.Pool.Query(ctx, query, tenantKey(tenantID), userID)
+	if err != nil {
+		return nil, fmt.Errorf("WebPushSubscriptionPostgresRepository.GetWebPushSubscriptionsByUserID query error: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := make([]*entities.WebPushSubscription, 0)
+	for rows.Next() {
+		var subscription entities.WebPushSubscription
+		err := rows.Scan(
+			&subscription.ID,
+			&subscription.UserID,
+			&subscription.TenantID,
+			&subscription.Endpoint,
+			&subscription.P256dh,
+			&subscription.Auth,
+			&subscription.ExpirationTime,
+			&subscription.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("WebPushSubscriptionPostgresRepository.GetWebPushSubscriptionsByUserID scan error: %w", err)
+		}
+		subscriptions = append(subscriptions, &subscription)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("WebPushSubscriptionPostgresRepository.GetWebPushSubscriptionsByUserID rows error: %w", err)
+	}
+	return subscriptions, nil
+}
+
+func (r *WebPushSubscriptionPostgresRepository) DeleteWebPushSubscription(ctx context.Context, tenantID *uuid.UUID, userID, endpoint string) error {
+	query := `
+		delete from web_push_subscriptions
+		where tenant_key = $1 and user_id = $2 and endpoint = $3
+	`
+	tag, err := r.db.Pool.Exec(ctx, query, tenantKey(tenantID), userID, endpoint)
+	if err != nil {
+		return fmt.Errorf("WebPushSubscriptionPostgresRepository.DeleteWebPushSubscription error: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *WebPushSubscriptionPostgresRepository) DeleteWebPushSubscriptionByEndpoint(ctx context.Context, tenantID *uuid.UUID, endpoint string) error {
+	query := `
+		delete from web_push_subscriptions
+		where tenant_key = $1 and endpoint = $2
+	`
+	_, err := r.db.Pool.Exec(ctx, query, tenantKey(tenantID), endpoint)
+	if err != nil {
+		return fmt.Errorf("WebPushSubscriptionPostgresRepository.DeleteWebPushSubscriptionByEndpoint error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/audit"
+	"notification_system/internal/auth"
+	"notification_system/internal/dto"
+	"notification_system/internal/entities"
+	"notification_system/internal/repositories"
+	slogger "notification_system/pkg/logger"
+)
+
+const (
+	AuditExportCSV    = "csv"
+	AuditExportNDJSON = "ndjson"
+)
+
+var auditCSVHeader = []string{
+	"id", "occurred_at", "request_id", "actor_method", "actor_subject", "client_id", "tenant_id", "ip",
+	"method", "path", "status_code", "action", "resource_type", "resource_id", "before", "after", "diff",
+}
+
+type AuditServiceImpl struct {
+	auditRepo repositories.AuditRepository
+}
+
+func NewAuditServiceImpl(auditRepo repositories.AuditRepository) AuditService {
+	return &AuditServiceImpl{auditRepo: auditRepo}
+}
+
+// RecordRequest appends the request to the audit log with the caller and the change
+// a service recorded in the context. Requests without a recorded change are logged by
+// their method and route.
+func (s *AuditServiceImpl) RecordRequest(ctx context.Context, request *dto.AuditRequest) error {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	entry := &entities.AuditEntry{
+		RequestID:  request.RequestID,
+		IP:         request.IP,
+		Method:     request.Method,
+		Path:       request.Path,
+		StatusCode: request.StatusCode,
+		Action:     request.Method + " " + request.Path,
+		TenantID:   auth.TenantIDFromContext(ctx),
+	}
+	if identity, ok := auth.IdentityFromContext(ctx); ok {
+		entry.ActorMethod = identity.Method
+		entry.ActorSubject = identity.Subject
+		entry.ClientID = identity.ClientID
+	}
+	if change, ok := audit.FromContext(ctx); ok && change.Action != "" {
+		entry.Action = change.Action
+		entry.ResourceType = &change.ResourceType
+		if change.ResourceID != "" {
+			entry.ResourceID = &change.ResourceID
+		}
+		entry.Before = change.Before
+		entry.After = change.After
+		entry.Diff = change.Diff
+	}
+	if err := s.auditRepo.CreateAuditEntry(ctx, entry); err != nil {
+		logger.Error("failed to record audit log entry",
+			slog.String("action", entry.Action),
+			slog.Any("error", err),
+		)
+		return ErrCannotRecordAudit
+	}
+	return nil
+}
+
+// SearchAuditLog returns the entries of the tenant of the caller, the default tenant sees every entry.
+func (s *AuditServiceImpl) SearchAuditLog(ctx context.Context, search *dto.AuditSearch) ([]*dto.AuditEntry, error) {
+	filter, err := auditFilter(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.auditRepo.SearchAuditEntries(ctx, filter)
+	if err != nil {
+		if errors.Is(err, repositories.ErrMaxBatchSizeExceeded) {
+			return nil, ErrTooManyAuditEntries
+		}
+		return nil, ErrCannotGetAuditLog
+	}
+	return dto.AuditEntryEntitiesToDTOs(entries), nil
+}
+
+// ExportAuditLog writes every entry matching the search to w as CSV or NDJSON, oldest first.
+// Limit and Offset of the search are ignored.
+func (s *AuditServiceImpl) ExportAuditLog(ctx context.Context, search *dto.AuditSearch, format string, w io.Writer) error {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	filter, err := auditFilter(ctx, search)
+	if err != nil {
+		return err
+	}
+	var write func(*entities.AuditEntry) error
+	flush := func() error { return nil }
+	switch format {
+	case AuditExportNDJSON:
+		encoder := json.NewEncoder(w)
+		write = func(entry *entities.AuditEntry) error {
+			return encoder.Encode(dto.AuditEntryEntityToDTO(entry))
+		}
+	case AuditExportCSV:
+		csvWriter := csv.NewWriter(w)
+		if err := csvWriter.Write(auditCSVHeader); err != nil {
+			return ErrCannotExportAudit
+		}
+		write = func(entry *entities.AuditEntry) error {
+			return csvWriter.Write(auditCSVRecord(entry))
+		}
+		flush = func() error {
+			csvWriter.Flush()
+			return csvWriter.Error()
+		}
+	default:
+		return ErrInvalidAuditSearch
+	}
+
+	if err := s.auditRepo.ExportAuditEntries(ctx, filter, write); err != nil {
+		logger.Error("failed to export audit log", slog.Any("error", err))
+		return ErrCannotExportAudit
+	}
+	if err := flush(); err != nil {
+		logger.Error("failed to export audit log", slog.Any("error", err))
+		return ErrCannotExportAudit
+	}
+	return nil
+}
+
+func auditFilter(ctx context.Context, search *dto.AuditSearch) (*entities.AuditFilter, error) {
+	if search.From != nil && search.To != nil && !search.From.Before(*search.To) {
+		return nil, ErrInvalidAuditSearch
+	}
+	return &entities.AuditFilter{
+		// the tenant comes from the credentials only, a tenant admin cannot read other tenants
+		TenantID:     auth.TenantIDFromContext(ctx),
+		ClientID:     search.ClientID,
+		ActorSubject: search.ActorSubject,
+		Action:       search.Action,
+		ResourceType: search.ResourceType,
+		ResourceID:   search.ResourceID,
+		RequestID:    search.RequestID,
+		From:         search.From,
+		To:           search.To,
+		Limit:        search.Limit,
+		Offset:       search.Offset,
+	}, nil
+}
+
+func auditCSVRecord(entry *entities.AuditEntry) []string {
+	optional := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	optionalID := func(id *uuid.UUID) string {
+		if id == nil {
+			return ""
+		}
+		return id.String()
+	}
+	return []string{
+		entry.ID.String(),
+		entry.OccurredAt.UTC().Format(time.RFC3339Nano),
+		entry.RequestID,
+		entry.ActorMethod,
+		entry.ActorSubject,
+		optionalID(entry.ClientID),
+		optionalID(entry.TenantID),
+		entry.IP,
+		entry.Method,
+		entry.Path,
+		strconv.Itoa(entry.StatusCode),
+		entry.Action,
+		optional(entry.ResourceType),
+		optional(entry.ResourceID),
+		string(entry.Before),
+		string(entry.After),
+		string(entry.Diff),
+	}
+}
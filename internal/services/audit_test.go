@@ -0,0 +1,136 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"notification_system/internal/audit"
+	"notification_system/internal/auth"
+	"notification_system/internal/dto"
+	"notification_system/internal/entities"
+	"notification_system/internal/repositories/mocks"
+)
+
+func TestAuditServiceImpl_RecordRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repomocks.NewMockAuditRepository(ctrl)
+	mockClientRepo := repomocks.NewMockClientRepository(ctrl)
+	clientID, tenantID, keyID := uuid.New(), uuid.New(), uuid.New()
+	identity := &auth.Identity{Method: auth.MethodAPIKey, Subject: "nsk_abc", ClientID: &clientID, TenantID: &tenantID}
+	ctx := context.WithValue(context.Background(), auth.IdentityKey, identity)
+	ctx = context.WithValue(ctx, auth.TenantIDKey, tenantID)
+	ctx = context.WithValue(ctx, audit.ChangeKey, &audit.Change{})
+
+	mockClientRepo.
+		EXPECT().
+		GetAPIKeys(gomock.Any(), clientID).
+		Return([]*entities.APIKey{{ID: keyID, ClientID: clientID, Prefix: "nsk_abc"}}, nil)
+	mockClientRepo.
+		EXPECT().
+		RevokeAPIKey(gomock.Any(), clientID, keyID).
+		Return(nil)
+	if err := NewClientServiceImpl(mockClientRepo).RevokeAPIKey(ctx, clientID, keyID); err != nil {
+		t.Fatalf("RevokeAPIKey() error = %v", err)
+	}
+
+	mockRepo.
+		EXPECT().
+		CreateAuditEntry(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, entry *entities.AuditEntry) error {
+			if entry.Action != "api_key.revoke" || *entry.ResourceType != "api_key" || *entry.ResourceID != keyID.String() {
+				t.Errorf("unexpected change %q %v %v", entry.Action, entry.ResourceType, entry.ResourceID)
+			}
+			if entry.ActorSubject != "nsk_abc" || *entry.ClientID != clientID || *entry.TenantID != tenantID {
+				t.Errorf("unexpected caller %+v", entry)
+			}
+			if entry.RequestID != "req-1" || entry.IP != "192.0.2.1" || entry.StatusCode != 204 {
+				t.Errorf("unexpected request %+v", entry)
+			}
+			if !bytes.Contains(entry.Diff, []byte(`"active":{"before":true,"after":false}`)) {
+				t.Errorf("Diff = %s, want the key deactivated", entry.Diff)
+			}
+			return nil
+		})
+	err := NewAuditServiceImpl(mockRepo).RecordRequest(ctx, &dto.AuditRequest{
+		RequestID:  "req-1",
+		IP:         "192.0.2.1",
+		Method:     "DELETE",
+		Path:       "/api/v1/api-keys/:id",
+		StatusCode: 204,
+	})
+	if err != nil {
+		t.Fatalf("RecordRequest() error = %v", err)
+	}
+}
+
+func TestAuditServiceImpl_RecordRequest_WithoutChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repomocks.NewMockAuditRepository(ctrl)
+	ctx := context.WithValue(context.Background(), audit.ChangeKey, &audit.Change{})
+
+	mockRepo.
+		EXPECT().
+		CreateAuditEntry(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, entry *entities.AuditEntry) error {
+			if entry.Action != "POST /api/v1/bounces" || entry.ResourceType != nil || entry.ActorMethod != "" {
+				t.Errorf("unexpected entry %+v", entry)
+			}
+			return nil
+		})
+	s := NewAuditServiceImpl(mockRepo)
+	err := s.RecordRequest(ctx, &dto.AuditRequest{Method: "POST", Path: "/api/v1/bounces", StatusCode: 401})
+	if err != nil {
+		t.Fatalf("RecordRequest() error = %v", err)
+	}
+}
+
+func TestAuditServiceImpl_ExportAuditLog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repomocks.NewMockAuditRepository(ctrl)
+	tenantID := uuid.New()
+	ctx := context.WithValue(context.Background(), auth.TenantIDKey, tenantID)
+	resourceType := "api_key"
+
+	mockRepo.
+		EXPECT().
+		ExportAuditEntries(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, filter *entities.AuditFilter, fn func(*entities.AuditEntry) error) error {
+			if filter.TenantID == nil || *filter.TenantID != tenantID {
+				t.Errorf("filter tenant = %v, want %v", filter.TenantID, tenantID)
+			}
+			return fn(&entities.AuditEntry{
+				ID:           uuid.New(),
+				OccurredAt:   time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+				Action:       "api_key.create",
+				ResourceType: &resourceType,
+				After:        []byte(`{"prefix":"nsk_abc"}`),
+			})
+		})
+
+	s := NewAuditServiceImpl(mockRepo)
+	var out bytes.Buffer
+	if err := s.ExportAuditLog(ctx, &dto.AuditSearch{}, AuditExportCSV, &out); err != nil {
+		t.Fatalf("ExportAuditLog() error = %v", err)
+	}
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("export is not CSV: %v", err)
+	}
+	if len(records) != 2 || len(records[1]) != len(auditCSVHeader) {
+		t.Fatalf("unexpected records %v", records)
+	}
+	if records[1][1] != "2026-01-02T03:04:05Z" || records[1][11] != "api_key.create" || records[1][15] != `{"prefix":"nsk_abc"}` {
+		t.Errorf("unexpected record %v", records[1])
+	}
+
+	if err := s.ExportAuditLog(ctx, &dto.AuditSearch{}, "xml", &out); !errors.Is(err, ErrInvalidAuditSearch) {
+		t.Errorf("ExportAuditLog(xml) error = %v, want %v", err, ErrInvalidAuditSearch)
+	}
+}
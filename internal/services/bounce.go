@@ -0,0 +1,116 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"notification_system/internal/auth"
+	"notification_system/internal/bounces"
+	"notification_system/internal/dto"
+	"notification_system/internal/entities"
+	"notification_system/internal/repositories"
+	slogger "notification_system/pkg/logger"
+)
+
+type BounceServiceImpl struct {
+	notificationRepo repositories.NotificationRepository
+	suppressionRepo  repositories.SuppressionRepository
+}
+
+func NewBounceServiceImpl(
+	notificationRepo repositories.NotificationRepository,
+	suppressionRepo repositories.SuppressionRepository,
+) BounceService {
+	return &BounceServiceImpl{
+		notificationRepo: notificationRepo,
+		suppressionRepo:  suppressionRepo,
+	}
+}
+
+// ProcessBounce parses the delivery status notification, suppresses the addresses that
+// bounced permanently and marks the matching email notification as bounced.
+func (s *BounceServiceImpl) ProcessBounce(ctx context.Context, message []byte) (*dto.BounceResult, error) {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	report, err := bounces.ParseDSN(bytes.NewReader(message))
+	if err != nil {
+		logger.Warn("failed to parse bounce", slog.Any("error", err))
+		return nil, ErrInvalidBounce
+	}
+
+	// a tenant admin only bounces the notifications of its tenant, the operator and the
+	// bounces command those of every tenant
+	tenantID := auth.TenantIDFromContext(ctx)
+	result := &dto.BounceResult{Recipients: make([]*dto.BounceRecipient, 0, len(report.Recipients))}
+	var notification *entities.Notification
+	if id, ok := report.NotificationID(); ok {
+		notification, err = s.notificationRepo.GetNotificationByID(ctx, id, tenantID)
+		switch {
+		case errors.Is(err, repositories.ErrNotFound):
+			logger.Warn("bounce for unknown notification", slog.String("id", id.String()))
+		case err != nil:
+			logger.Error("failed to get bounced notification", slog.Any("error", err))
+			return nil, ErrCannotProcessBounce
+		case notification.DeliveryType != entities.DeliveryTypeEmail:
+			notification = nil
+		default:
+			result.NotificationID = &notification.ID
+			result.Status = notification.Status
+		}
+	}
+
+	var permanent []string
+	for _, recipient := range report.Recipients {
+		address := recipient.FinalRecipient
+		if address == "" && notification != nil {
+			address = notification.Recipient
+		}
+		bounceRecipient := &dto.BounceRecipient{
+			Address:        address,
+			Action:         recipient.Action,
+			Status:         recipient.Status,
+			DiagnosticCode: recipient.DiagnosticCode,
+		}
+		result.Recipients = append(result.Recipients, bounceRecipient)
+		if !recipient.Permanent() || address == "" {
+			continue
+		}
+
+		details := strings.TrimSpace(recipient.Status + " " + recipient.DiagnosticCode)
+		suppression := &entities.Suppression{
+			TenantID:     tenantID,
+			DeliveryType: entities.DeliveryTypeEmail,
+			Address:      address,
+			Reason:       entities.SuppressionHardBounce,
+			Details:      details,
+		}
+		// the address is suppressed in the tenant that sent the bounced notification
+		if notification != nil {
+			suppression.TenantID = notification.TenantID
+		}
+		if err := s.suppressionRepo.CreateSuppression(ctx, suppression); err != nil {
+			logger.Error("failed to suppress bounced address", slog.Any("error", err))
+			return nil, ErrCannotProcessBounce
+		}
+		bounceRecipient.Suppressed = true
+		permanent = append(permanent, details)
+	}
+
+	// a bounce only arrives after the server accepted the message, so only delivered notifications move
+	if notification != nil && len(permanent) != 0 && notification.Status == entities.StatusDelivered {
+		reason := strings.Join(permanent, "; ")
+		if err := s.notificationRepo.UpdateNotificationStatusWithReason(ctx, notification.ID, entities.StatusBounced, reason); err != nil {
+			logger.Error("failed to mark notification as bounced", slog.Any("error", err))
+			return nil, ErrCannotProcessBounce
+		}
+		result.Status = entities.StatusBounced
+	}
+	logger.Info("bounce processed",
+		slog.Int("recipients", len(report.Recipients)),
+		slog.Int("permanent", len(permanent)),
+	)
+	return result, nil
+}
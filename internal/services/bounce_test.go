@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"notification_system/internal/auth"
+	"notification_system/internal/entities"
+	"notification_system/internal/repositories"
+	"notification_system/internal/repositories/mocks"
+)
+
+func testBounce(messageID string) []byte {
+	return []byte("From: mailer-daemon@example.com\r\n" +
+		"Content-Type: multipart/report; report-type=delivery-status; boundary=b\r\n" +
+		"\r\n" +
+		"--b\r\n" +
+		"Content-Type: message/delivery-status\r\n" +
+		"\r\n" +
+		"Reporting-MTA: dns; mx.example.org\r\n" +
+		"\r\n" +
+		"Final-Recipient: rfc822; nobody@example.org\r\n" +
+		"Action: failed\r\n" +
+		"Status: 5.1.1\r\n" +
+		"Diagnostic-Code: smtp; 550 5.1.1 User unknown\r\n" +
+		"\r\n" +
+		"--b\r\n" +
+		"Content-Type: text/rfc822-headers\r\n" +
+		"\r\n" +
+		"Message-ID: " + messageID + "\r\n" +
+		"--b--\r\n")
+}
+
+func TestBounceServiceImpl_ProcessBounce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockNotificationRepo := repomocks.NewMockNotificationRepository(ctrl)
+	mockSuppressionRepo := repomocks.NewMockSuppressionRepository(ctrl)
+	id := uuid.New()
+
+	mockNotificationRepo.
+		EXPECT().
+		GetNotificationByID(gomock.Any(), id, gomock.Nil()).
+		Return(&entities.Notification{
+			ID:           id,
+			DeliveryType: entities.DeliveryTypeEmail,
+			Recipient:    "nobody@example.org",
+			Status:       entities.StatusDelivered,
+		}, nil)
+	mockSuppressionRepo.
+		EXPECT().
+		CreateSuppression(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, suppression *entities.Suppression) error {
+			if suppression.Address != "nobody@example.org" || suppression.Reason != entities.SuppressionHardBounce {
+				t.Errorf("unexpected suppression %+v", suppression)
+			}
+			return nil
+		})
+	mockNotificationRepo.
+		EXPECT().
+		UpdateNotificationStatusWithReason(gomock.Any(), id, entities.StatusBounced, "5.1.1 550 5.1.1 User unknown").
+		Return(nil)
+
+	s := NewBounceServiceImpl(mockNotificationRepo, mockSuppressionRepo)
+	result, err := s.ProcessBounce(context.Background(), testBounce("<"+id.String()+"@example.com>"))
+	if err != nil {
+		t.Fatalf("ProcessBounce() error = %v", err)
+	}
+	if result.Status != entities.StatusBounced || len(result.Recipients) != 1 || !result.Recipients[0].Suppressed {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	if _, err := s.ProcessBounce(context.Background(), []byte("Subject: hi\r\n\r\nhello")); !errors.Is(err, ErrInvalidBounce) {
+		t.Errorf("ProcessBounce() error = %v, want %v", err, ErrInvalidBounce)
+	}
+}
+
+func TestBounceServiceImpl_ProcessBounce_OtherTenant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockNotificationRepo := repomocks.NewMockNotificationRepository(ctrl)
+	mockSuppressionRepo := repomocks.NewMockSuppressionRepository(ctrl)
+	tenantID, id := uuid.New(), uuid.New()
+	ctx := context.WithValue(context.Background(), auth.TenantIDKey, tenantID)
+
+	// the notification of another tenant is not found in the tenant of the caller
+	mockNotificationRepo.
+		EXPECT().
+		GetNotificationByID(gomock.Any(), id, &tenantID).
+		Return(nil, repositories.ErrNotFound)
+	mockSuppressionRepo.
+		EXPECT().
+		CreateSuppression(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, suppression *entities.Suppression) error {
+			if suppression.TenantID == nil || *suppression.TenantID != tenantID {
+				t.Errorf("suppression tenant = %v, want the tenant of the caller %v", suppression.TenantID, tenantID)
+			}
+			return nil
+		})
+
+	s := NewBounceServiceImpl(mockNotificationRepo, mockSuppressionRepo)
+	result, err := s.ProcessBounce(ctx, testBounce("<"+id.String()+"@example.com>"))
+	if err != nil {
+		t.Fatalf("ProcessBounce() error = %v", err)
+	}
+	if result.NotificationID != nil || result.Status == entities.StatusBounced {
+		t.Errorf("result %+v, want the notification of the other tenant untouched", result)
+	}
+}
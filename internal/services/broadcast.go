@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/audit"
+	"notification_system/internal/auth"
+	"notification_system/internal/dto"
+	"notification_system/internal/entities"
+	"notification_system/internal/repositories"
+	slogger "notification_system/pkg/logger"
+)
+
+type BroadcastServiceImpl struct {
+	broadcastRepo repositories.BroadcastRepository
+	topicRepo     repositories.TopicRepository
+}
+
+func NewBroadcastServiceImpl(broadcastRepo repositories.BroadcastRepository, topicRepo repositories.TopicRepository) BroadcastService {
+	return &BroadcastServiceImpl{
+		broadcastRepo: broadcastRepo,
+		topicRepo:     topicRepo,
+	}
+}
+
+// CreateBroadcast only records the broadcast, the fan-out runs in the background.
+func (s *BroadcastServiceImpl) CreateBroadcast(ctx context.Context, broadcastCreate *dto.BroadcastCreate) (*dto.Broadcast, error) {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	if (broadcastCreate.Topic == "") == (broadcastCreate.Segment == nil) {
+		return nil, ErrInvalidBroadcast
+	}
+	if broadcastCreate.DeliveryType == "" || broadcastCreate.DeliveryType == entities.DeliveryTypeChain || broadcastCreate.Content == "" {
+		return nil, ErrInvalidBroadcast
+	}
+	if reservedValue(broadcastCreate.Content) {
+		return nil, ErrReservedValue
+	}
+	broadcast := &entities.Broadcast{
+		DeliveryType: broadcastCreate.DeliveryType,
+		Content:      broadcastCreate.Content,
+		Priority:     broadcastCreate.Priority,
+	}
+	broadcast.ClientID, broadcast.TenantID = callerOwner(ctx)
+	switch broadcast.Priority {
+	case "":
+		broadcast.Priority = entities.PriorityNormal
+	case entities.PriorityLow, entities.PriorityNormal, entities.PriorityHigh, entities.PriorityCritical:
+	default:
+		return nil, ErrInvalidPriority
+	}
+	if broadcastCreate.Category != "" {
+		if broadcastCreate.Category == entities.PreferenceAny {
+			return nil, ErrInvalidCategory
+		}
+		broadcast.Category = &broadcastCreate.Category
+	}
+	if broadcastCreate.Topic != "" {
+		if _, err := s.topicRepo.GetTopic(ctx, broadcast.TenantID, broadcastCreate.Topic); err != nil {
+			if errors.Is(err, repositories.ErrNotFound) {
+				return nil, ErrTopicNotFound
+			}
+			logger.Error("failed to get topic", slog.Any("error", err))
+			return nil, ErrCannotCreateBroadcast
+		}
+		broadcast.Topic = &broadcastCreate.Topic
+	} else {
+		for _, timeZone := range broadcastCreate.Segment.TimeZones {
+			if timeZone == "" {
+				return nil, ErrInvalidTimeZone
+			}
+			if err := validateTimeZone(timeZone); err != nil {
+				return nil, err
+			}
+		}
+		broadcast.Segment = &entities.BroadcastSegment{TimeZones: broadcastCreate.Segment.TimeZones}
+	}
+
+	if err := s.broadcastRepo.CreateBroadcast(ctx, broadcast); err != nil {
+		logger.Error("failed to create broadcast", slog.Any("error", err))
+		return nil, ErrCannotCreateBroadcast
+	}
+	logger.Info("broadcast created", slog.String("id", broadcast.ID.String()))
+	audit.Record(ctx, "broadcast.create", "broadcast", broadcast.ID.String(), nil, dto.BroadcastEntityToDTO(broadcast))
+	return dto.BroadcastEntityToDTO(broadcast), nil
+}
+
+func (s *BroadcastServiceImpl) GetBroadcasts(ctx context.Context, limit, offset uint) ([]*dto.Broadcast, error) {
+	clientID, tenantID := callerOwner(ctx)
+	broadcasts, err := s.broadcastRepo.GetBroadcasts(ctx, tenantID, clientID, limit, offset)
+	if err != nil {
+		return nil, ErrCannotGetBroadcasts
+	}
+	return dto.BroadcastEntitiesToDTOs(broadcasts), nil
+}
+
+func (s *BroadcastServiceImpl) GetBroadcast(ctx context.Context, id uuid.UUID) (*dto.Broadcast, error) {
+	broadcast, err := s.broadcastRepo.GetBroadcast(ctx, id, auth.TenantIDFromContext(ctx))
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrBroadcastNotFound
+		}
+		return nil, ErrCannotGetBroadcasts
+	}
+	if !callerOwns(ctx, broadcast.ClientID, broadcast.TenantID) {
+		return nil, ErrBroadcastNotFound
+	}
+	return dto.BroadcastEntityToDTO(broadcast), nil
+}
+
+func (s *BroadcastServiceImpl) PauseBroadcast(ctx context.Context, id uuid.UUID) (*dto.Broadcast, error) {
+	return s.updateStatus(ctx, id, entities.BroadcastStatusPaused,
+		entities.BroadcastStatusPending, entities.BroadcastStatusRunning)
+}
+
+// ResumeBroadcast continues the fan-out after the last expanded chunk, a broadcast
+// paused before it started is started from the beginning.
+func (s *BroadcastServiceImpl) ResumeBroadcast(ctx context.Context, id uuid.UUID) (*dto.Broadcast, error) {
+	broadcast, err := s.ownedBroadcast(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	status := entities.BroadcastStatusRunning
+	if broadcast.StartedAt == nil {
+		status = entities.BroadcastStatusPending
+	}
+	return s.changeStatus(ctx, broadcast, status, entities.BroadcastStatusPaused)
+}
+
+// CancelBroadcast stops the fan-out, notifications already created are still sent.
+func (s *BroadcastServiceImpl) CancelBroadcast(ctx context.Context, id uuid.UUID) (*dto.Broadcast, error) {
+	return s.updateStatus(ctx, id, entities.BroadcastStatusCancelled,
+		entities.BroadcastStatusPending, entities.BroadcastStatusRunning, entities.BroadcastStatusPaused)
+}
+
+// broadcastActions names the audited action that moves a broadcast to the status.
+var broadcastActions = map[string]string{
+	entities.BroadcastStatusPaused:    "pause",
+	entities.BroadcastStatusPending:   "resume",
+	entities.BroadcastStatusRunning:   "resume",
+	entities.BroadcastStatusCancelled: "cancel",
+}
+
+// ownedBroadcast returns the broadcast when the caller owns it, ErrBroadcastNotFound otherwise.
+func (s *BroadcastServiceImpl) ownedBroadcast(ctx context.Context, id uuid.UUID) (*entities.Broadcast, error) {
+	broadcast, err := s.broadcastRepo.GetBroadcast(ctx, id, auth.TenantIDFromContext(ctx))
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrBroadcastNotFound
+		}
+		return nil, ErrCannotUpdateBroadcast
+	}
+	if !callerOwns(ctx, broadcast.ClientID, broadcast.TenantID) {
+		return nil, ErrBroadcastNotFound
+	}
+	return broadcast, nil
+}
+
+func (s *BroadcastServiceImpl) updateStatus(ctx context.Context, id uuid.UUID, status string, from ...string) (*dto.Broadcast, error) {
+	current, err := s.ownedBroadcast(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.changeStatus(ctx, current, status, from...)
+}
+
+func (s *BroadcastServiceImpl) changeStatus(ctx context.Context, current *entities.Broadcast, status string, from ...string) (*dto.Broadcast, error) {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	broadcast, err := s.broadcastRepo.UpdateBroadcastStatus(ctx, current.ID, status, from)
+	if err != nil {
+		// broadcasts are never deleted, a missing one is in a status it cannot leave this way
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrInvalidBroadcastTransition
+		}
+		logger.Error("failed to update broadcast status", slog.Any("error", err))
+		return nil, ErrCannotUpdateBroadcast
+	}
+	logger.Info("broadcast status changed",
+		slog.String("id", current.ID.String()),
+		slog.String("status", status),
+	)
+	audit.Record(ctx, "broadcast."+broadcastActions[status], "broadcast", current.ID.String(),
+		dto.BroadcastEntityToDTO(current), dto.BroadcastEntityToDTO(broadcast))
+	return dto.BroadcastEntityToDTO(broadcast), nil
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"notification_system/internal/auth"
+	"notification_system/internal/dto"
+	"notification_system/internal/entities"
+	"notification_system/internal/repositories"
+	"notification_system/internal/repositories/mocks"
+)
+
+func TestBroadcastServiceImpl_CreateBroadcast(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockBroadcastRepo := repomocks.NewMockBroadcastRepository(ctrl)
+	mockTopicRepo := repomocks.NewMockTopicRepository(ctrl)
+
+	mockTopicRepo.EXPECT().GetTopic(gomock.Any(), gomock.Nil(), "product-x").Return(&entities.Topic{Name: "product-x"}, nil)
+	mockTopicRepo.EXPECT().GetTopic(gomock.Any(), gomock.Nil(), "missing").Return(nil, repositories.ErrNotFound)
+	mockBroadcastRepo.
+		EXPECT().
+		CreateBroadcast(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, broadcast *entities.Broadcast) error {
+			if broadcast.Topic == nil || *broadcast.Topic != "product-x" || broadcast.Segment != nil {
+				t.Errorf("unexpected target %+v", broadcast)
+			}
+			if broadcast.Priority != entities.PriorityNormal {
+				t.Errorf("priority = %q, want default %q", broadcast.Priority, entities.PriorityNormal)
+			}
+			broadcast.ID = uuid.New()
+			broadcast.Status = entities.BroadcastStatusPending
+			return nil
+		})
+
+	s := NewBroadcastServiceImpl(mockBroadcastRepo, mockTopicRepo)
+	broadcast, err := s.CreateBroadcast(context.Background(), &dto.BroadcastCreate{
+		Topic:        "product-x",
+		DeliveryType: "email",
+		Content:      "Version 2 is out",
+	})
+	if err != nil {
+		t.Fatalf("CreateBroadcast() error = %v", err)
+	}
+	if broadcast.Status != entities.BroadcastStatusPending {
+		t.Errorf("status = %q, want %q", broadcast.Status, entities.BroadcastStatusPending)
+	}
+
+	tests := []struct {
+		broadcast *dto.BroadcastCreate
+		want      error
+	}{
+		{&dto.BroadcastCreate{DeliveryType: "email", Content: "hi"}, ErrInvalidBroadcast},
+		{&dto.BroadcastCreate{Topic: "product-x", Segment: &dto.BroadcastSegment{}, DeliveryType: "email", Content: "hi"}, ErrInvalidBroadcast},
+		{&dto.BroadcastCreate{Topic: "product-x", DeliveryType: "email"}, ErrInvalidBroadcast},
+		{&dto.BroadcastCreate{Segment: &dto.BroadcastSegment{TimeZones: []string{"Mars/Olympus"}}, DeliveryType: "email", Content: "hi"}, ErrInvalidTimeZone},
+		{&dto.BroadcastCreate{Topic: "product-x", DeliveryType: "email", Content: "hi", Priority: "urgent"}, ErrInvalidPriority},
+		{&dto.BroadcastCreate{Topic: "missing", DeliveryType: "email", Content: "hi"}, ErrTopicNotFound},
+	}
+	for _, tt := range tests {
+		if _, err := s.CreateBroadcast(context.Background(), tt.broadcast); !errors.Is(err, tt.want) {
+			t.Errorf("CreateBroadcast(%+v) error = %v, want %v", tt.broadcast, err, tt.want)
+		}
+	}
+}
+
+func TestBroadcastServiceImpl_ChangeStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockBroadcastRepo := repomocks.NewMockBroadcastRepository(ctrl)
+	s := NewBroadcastServiceImpl(mockBroadcastRepo, repomocks.NewMockTopicRepository(ctrl))
+
+	started := time.Now()
+	paused := &entities.Broadcast{ID: uuid.New(), Status: entities.BroadcastStatusPaused, StartedAt: &started}
+	mockBroadcastRepo.EXPECT().GetBroadcast(gomock.Any(), paused.ID, gomock.Nil()).Return(paused, nil)
+	mockBroadcastRepo.
+		EXPECT().
+		UpdateBroadcastStatus(gomock.Any(), paused.ID, entities.BroadcastStatusRunning, []string{entities.BroadcastStatusPaused}).
+		Return(&entities.Broadcast{ID: paused.ID, Status: entities.BroadcastStatusRunning}, nil)
+	if broadcast, err := s.ResumeBroadcast(context.Background(), paused.ID); err != nil || broadcast.Status != entities.BroadcastStatusRunning {
+		t.Errorf("ResumeBroadcast() = %+v, %v, want running", broadcast, err)
+	}
+
+	// a completed broadcast cannot be cancelled, an unknown one is not found
+	completed := &entities.Broadcast{ID: uuid.New(), Status: entities.BroadcastStatusCompleted}
+	mockBroadcastRepo.EXPECT().GetBroadcast(gomock.Any(), completed.ID, gomock.Nil()).Return(completed, nil)
+	mockBroadcastRepo.EXPECT().UpdateBroadcastStatus(gomock.Any(), completed.ID, entities.BroadcastStatusCancelled, gomock.Any()).Return(nil, repositories.ErrNotFound)
+	if _, err := s.CancelBroadcast(context.Background(), completed.ID); !errors.Is(err, ErrInvalidBroadcastTransition) {
+		t.Errorf("CancelBroadcast() error = %v, want %v", err, ErrInvalidBroadcastTransition)
+	}
+	unknown := uuid.New()
+	mockBroadcastRepo.EXPECT().GetBroadcast(gomock.Any(), unknown, gomock.Nil()).Return(nil, repositories.ErrNotFound)
+	if _, err := s.PauseBroadcast(context.Background(), unknown); !errors.Is(err, ErrBroadcastNotFound) {
+		t.Errorf("PauseBroadcast() error = %v, want %v", err, ErrBroadcastNotFound)
+	}
+
+	// the broadcasts of another client are not found
+	otherClientID := uuid.New()
+	other := &entities.Broadcast{ID: uuid.New(), Status: entities.BroadcastStatusRunning, ClientID: &otherClientID}
+	mockBroadcastRepo.EXPECT().GetBroadcast(gomock.Any(), other.ID, gomock.Nil()).Return(other, nil)
+	ctx := context.WithValue(context.Background(), auth.ClientIDKey, uuid.New())
+	if _, err := s.PauseBroadcast(ctx, other.ID); !errors.Is(err, ErrBroadcastNotFound) {
+		t.Errorf("PauseBroadcast() of another client error = %v, want %v", err, ErrBroadcastNotFound)
+	}
+}
@@ -0,0 +1,366 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/audit"
+	"notification_system/internal/auth"
+	"notification_system/internal/dto"
+	"notification_system/internal/entities"
+	"notification_system/internal/repositories"
+	"notification_system/pkg/jwt"
+	slogger "notification_system/pkg/logger"
+)
+
+const (
+	// DefaultRotationGracePeriod is how long a rotated key keeps working by default
+	DefaultRotationGracePeriod = 24 * time.Hour
+	maxRotationGracePeriod     = 30 * 24 * time.Hour
+)
+
+type ClientServiceImpl struct {
+	clientRepo repositories.ClientRepository
+}
+
+func NewClientServiceImpl(clientRepo repositories.ClientRepository) ClientService {
+	return &ClientServiceImpl{clientRepo: clientRepo}
+}
+
+// CreateClient creates the client with its first API key.
+func (s *ClientServiceImpl) CreateClient(ctx context.Context, clientCreate *dto.ClientCreate) (*dto.ClientCreated, error) {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	name := strings.TrimSpace(clientCreate.Name)
+	if name == "" {
+		return nil, ErrInvalidClient
+	}
+	key, entity, err := newAPIKey()
+	if err != nil {
+		logger.Error("failed to generate api key", slog.Any("error", err))
+		return nil, ErrCannotCreateClient
+	}
+	client := &entities.Client{Name: name}
+	if clientCreate.OAuthClientID != "" {
+		client.OAuthClientID = &clientCreate.OAuthClientID
+	}
+	if clientCreate.TenantID != uuid.Nil {
+		client.TenantID = &clientCreate.TenantID
+	}
+	if err := s.clientRepo.CreateClient(ctx, client, entity); err != nil {
+		if errors.Is(err, repositories.ErrAlreadyExists) {
+			return nil, ErrClientAlreadyExists
+		}
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrTenantNotFound
+		}
+		logger.Error("failed to create client", slog.Any("error", err))
+		return nil, ErrCannotCreateClient
+	}
+	logger.Info("client created",
+		slog.String("id", client.ID.String()),
+		slog.String("prefix", entity.Prefix),
+	)
+	audit.Record(ctx, "client.create", "client", client.ID.String(), nil, dto.ClientEntityToDTO(client))
+	return &dto.ClientCreated{
+		Client: *dto.ClientEntityToDTO(client),
+		APIKey: &dto.APIKeyCreated{APIKey: *dto.APIKeyEntityToDTO(entity), Key: key},
+	}, nil
+}
+
+func (s *ClientServiceImpl) GetClients(ctx context.Context) ([]*dto.Client, error) {
+	clients, err := s.clientRepo.GetClients(ctx)
+	if err != nil {
+		return nil, ErrCannotGetClients
+	}
+	return dto.ClientEntitiesToDTOs(clients), nil
+}
+
+// LinkOAuthClient scopes the tokens issued to the OAuth client to the client, an empty ID unlinks it.
+func (s *ClientServiceImpl) LinkOAuthClient(ctx context.Context, clientID uuid.UUID, oauthClientID string) (*dto.Client, error) {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	var linked *string
+	if oauthClientID = strings.TrimSpace(oauthClientID); oauthClientID != "" {
+		linked = &oauthClientID
+	}
+	var before *dto.Client
+	if audit.Enabled(ctx) {
+		if client, err := s.clientRepo.GetClient(ctx, clientID); err == nil {
+			before = dto.ClientEntityToDTO(client)
+		}
+	}
+	client, err := s.clientRepo.UpdateClientOAuthClientID(ctx, clientID, linked)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrClientNotFound
+		}
+		if errors.Is(err, repositories.ErrAlreadyExists) {
+			return nil, ErrOAuthClientAlreadyLinked
+		}
+		logger.Error("failed to link oauth client", slog.Any("error", err))
+		return nil, ErrCannotUpdateClient
+	}
+	logger.Info("oauth client linked",
+		slog.String("id", clientID.String()),
+		slog.String("oauth_client_id", oauthClientID),
+	)
+	audit.Record(ctx, "client.link_oauth_client", "client", clientID.String(), before, dto.ClientEntityToDTO(client))
+	return dto.ClientEntityToDTO(client), nil
+}
+
+func (s *ClientServiceImpl) GetAPIKeys(ctx context.Context, clientID uuid.UUID) ([]*dto.APIKey, error) {
+	keys, err := s.clientRepo.GetAPIKeys(ctx, clientID)
+	if err != nil {
+		return nil, ErrCannotGetAPIKeys
+	}
+	return dto.APIKeyEntitiesToDTOs(keys), nil
+}
+
+func (s *ClientServiceImpl) CreateAPIKey(ctx context.Context, clientID uuid.UUID) (*dto.APIKeyCreated, error) {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	key, entity, err := newAPIKey()
+	if err != nil {
+		logger.Error("failed to generate api key", slog.Any("error", err))
+		return nil, ErrCannotCreateAPIKey
+	}
+	entity.ClientID = clientID
+	if err := s.clientRepo.CreateAPIKey(ctx, entity); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrClientNotFound
+		}
+		logger.Error("failed to create api key", slog.Any("error", err))
+		return nil, ErrCannotCreateAPIKey
+	}
+	logger.Info("api key created",
+		slog.String("client_id", clientID.String()),
+		slog.String("prefix", entity.Prefix),
+	)
+	audit.Record(ctx, "api_key.create", "api_key", entity.ID.String(), nil, dto.APIKeyEntityToDTO(entity))
+	return &dto.APIKeyCreated{APIKey: *dto.APIKeyEntityToDTO(entity), Key: key}, nil
+}
+
+// RotateAPIKey issues a new key, the old one keeps working for the grace period.
+func (s *ClientServiceImpl) RotateAPIKey(ctx context.Context, clientID, keyID uuid.UUID, grace time.Duration) (*dto.APIKeyCreated, error) {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	if grace < 0 || grace > maxRotationGracePeriod {
+		return nil, ErrInvalidGracePeriod
+	}
+	key, entity, err := newAPIKey()
+	if err != nil {
+		logger.Error("failed to generate api key", slog.Any("error", err))
+		return nil, ErrCannotRotateAPIKey
+	}
+	expiresAt := time.Now().Add(grace).UTC()
+	before := s.auditedAPIKey(ctx, clientID, keyID)
+	if err := s.clientRepo.RotateAPIKey(ctx, clientID, keyID, entity, expiresAt); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrAPIKeyNotFound
+		}
+		logger.Error("failed to rotate api key", slog.Any("error", err))
+		return nil, ErrCannotRotateAPIKey
+	}
+	logger.Info("api key rotated",
+		slog.String("client_id", clientID.String()),
+		slog.String("key_id", keyID.String()),
+		slog.String("prefix", entity.Prefix),
+		slog.Time("expires_at", expiresAt),
+	)
+	var rotated *rotatedAPIKey
+	if before != nil {
+		rotated = &rotatedAPIKey{APIKey: *before, ReplacedBy: entity.ID}
+		rotated.ExpiresAt = &expiresAt
+	}
+	audit.Record(ctx, "api_key.rotate", "api_key", keyID.String(), before, rotated)
+	return &dto.APIKeyCreated{APIKey: *dto.APIKeyEntityToDTO(entity), Key: key}, nil
+}
+
+func (s *ClientServiceImpl) RevokeAPIKey(ctx context.Context, clientID, keyID uuid.UUID) error {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	before := s.auditedAPIKey(ctx, clientID, keyID)
+	if err := s.clientRepo.RevokeAPIKey(ctx, clientID, keyID); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrAPIKeyNotFound
+		}
+		logger.Error("failed to revoke api key", slog.Any("error", err))
+		return ErrCannotRevokeAPIKey
+	}
+	logger.Info("api key revoked",
+		slog.String("client_id", clientID.String()),
+		slog.String("key_id", keyID.String()),
+	)
+	var revoked *dto.APIKey
+	if before != nil {
+		revokedAt := time.Now().UTC()
+		key := *before
+		key.Active = false
+		key.RevokedAt = &revokedAt
+		revoked = &key
+	}
+	audit.Record(ctx, "api_key.revoke", "api_key", keyID.String(), before, revoked)
+	return nil
+}
+
+// rotatedAPIKey is the audited state of a rotated key, it expires and names its successor.
+type rotatedAPIKey struct {
+	dto.APIKey
+	ReplacedBy uuid.UUID `json:"replaced_by"`
+}
+
+// auditedAPIKey returns the key of the client for the audit log, nil when the request
+// is not audited or the key cannot be read.
+func (s *ClientServiceImpl) auditedAPIKey(ctx context.Context, clientID, keyID uuid.UUID) *dto.APIKey {
+	if !audit.Enabled(ctx) {
+		return nil
+	}
+	keys, err := s.clientRepo.GetAPIKeys(ctx, clientID)
+	if err != nil {
+		return nil
+	}
+	for _, key := range keys {
+		if key.ID == keyID {
+			return dto.APIKeyEntityToDTO(key)
+		}
+	}
+	return nil
+}
+
+// Authenticate returns the caller of an active key, API keys may read and write
+// the notifications of their client.
+func (s *ClientServiceImpl) Authenticate(ctx context.Context, key string) (*auth.Identity, error) {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	prefix, ok := auth.Prefix(key)
+	if !ok {
+		return nil, ErrInvalidAPIKey
+	}
+	entity, err := s.clientRepo.GetAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		logger.Error("failed to get api key", slog.Any("error", err))
+		return nil, ErrCannotAuthenticate
+	}
+	if !auth.Verify(key, entity.KeyHash) || !entity.Active(time.Now()) {
+		return nil, ErrInvalidAPIKey
+	}
+	client, err := s.clientRepo.GetClient(ctx, entity.ClientID)
+	if err != nil {
+		logger.Error("failed to get client", slog.Any("error", err))
+		return nil, ErrCannotAuthenticate
+	}
+	return &auth.Identity{
+		Method:   auth.MethodAPIKey,
+		Subject:  prefix,
+		ClientID: &client.ID,
+		TenantID: client.TenantID,
+		Scopes: []string{
+			auth.ScopeNotificationsRead, auth.ScopeNotificationsWrite, auth.ScopeNotificationsPII,
+			auth.ScopeContactsRead, auth.ScopeContactsWrite,
+		},
+	}, nil
+}
+
+// AuthenticateToken returns the caller of verified token claims. The token is scoped to the
+// client linked to its OAuth client, only admin tokens may be used without a linked client.
+func (s *ClientServiceImpl) AuthenticateToken(ctx context.Context, claims *jwt.Claims) (*auth.Identity, error) {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	identity := &auth.Identity{
+		Method:  auth.MethodJWT,
+		Subject: claims.Subject,
+		Scopes:  claims.Scopes(),
+	}
+	if oauthClientID := claims.Client(); oauthClientID != "" {
+		client, err := s.clientRepo.GetClientByOAuthClientID(ctx, oauthClientID)
+		switch {
+		case err == nil:
+			identity.ClientID = &client.ID
+			identity.TenantID = client.TenantID
+		case !errors.Is(err, repositories.ErrNotFound):
+			logger.Error("failed to get client", slog.Any("error", err))
+			return nil, ErrCannotAuthenticate
+		}
+	}
+	if identity.ClientID == nil && !identity.HasScope(auth.ScopeAdmin) {
+		return nil, ErrClientNotLinked
+	}
+	return identity, nil
+}
+
+// defaultSMTPPort is the submission port used when a tenant sets its server without a port
+const defaultSMTPPort = 587
+
+// CreateTenant creates a tenant, its email is sent with its own From address and SMTP server when they are set.
+func (s *ClientServiceImpl) CreateTenant(ctx context.Context, tenantCreate *dto.TenantCreate) (*dto.Tenant, error) {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	name := strings.TrimSpace(tenantCreate.Name)
+	if name == "" {
+		return nil, ErrInvalidTenant
+	}
+	tenant := &entities.Tenant{Name: name}
+	if tenantCreate.EmailFrom != "" {
+		if _, err := mail.ParseAddress(tenantCreate.EmailFrom); err != nil {
+			return nil, ErrInvalidTenant
+		}
+		tenant.EmailFrom = &tenantCreate.EmailFrom
+	}
+	if tenantCreate.SMTPHost != "" {
+		// the server needs the From address to authenticate and to send from
+		if tenant.EmailFrom == nil || tenantCreate.SMTPPort < 0 || tenantCreate.SMTPPort > 65535 {
+			return nil, ErrInvalidTenant
+		}
+		port := tenantCreate.SMTPPort
+		if port == 0 {
+			port = defaultSMTPPort
+		}
+		tenant.SMTPHost = &tenantCreate.SMTPHost
+		tenant.SMTPPort = &port
+		if tenantCreate.SMTPUsername != "" {
+			tenant.SMTPUsername = &tenantCreate.SMTPUsername
+		}
+		if tenantCreate.SMTPPassword != "" {
+			tenant.SMTPPassword = &tenantCreate.SMTPPassword
+		}
+	} else if tenantCreate.SMTPPort != 0 || tenantCreate.SMTPUsername != "" || tenantCreate.SMTPPassword != "" {
+		return nil, ErrInvalidTenant
+	}
+
+	if err := s.clientRepo.CreateTenant(ctx, tenant); err != nil {
+		if errors.Is(err, repositories.ErrAlreadyExists) {
+			return nil, ErrTenantAlreadyExists
+		}
+		logger.Error("failed to create tenant", slog.Any("error", err))
+		return nil, ErrCannotCreateTenant
+	}
+	logger.Info("tenant created", slog.String("id", tenant.ID.String()))
+	audit.Record(ctx, "tenant.create", "tenant", tenant.ID.String(), nil, dto.TenantEntityToDTO(tenant))
+	return dto.TenantEntityToDTO(tenant), nil
+}
+
+func (s *ClientServiceImpl) GetTenants(ctx context.Context) ([]*dto.Tenant, error) {
+	tenants, err := s.clientRepo.GetTenants(ctx)
+	if err != nil {
+		return nil, ErrCannotGetTenants
+	}
+	return dto.TenantEntitiesToDTOs(tenants), nil
+}
+
+func newAPIKey() (string, *entities.APIKey, error) {
+	key, prefix, err := auth.GenerateKey()
+	if err != nil {
+		return "", nil, err
+	}
+	return key, &entities.APIKey{Prefix: prefix, KeyHash: auth.Hash(key)}, nil
+}
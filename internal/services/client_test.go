@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"notification_system/internal/auth"
+	"notification_system/internal/dto"
+	"notification_system/internal/entities"
+	"notification_system/internal/repositories"
+	"notification_system/internal/repositories/mocks"
+	"notification_system/pkg/jwt"
+)
+
+func TestClientServiceImpl_CreateClient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClientRepo := repomocks.NewMockClientRepository(ctrl)
+	s := NewClientServiceImpl(mockClientRepo)
+
+	var stored *entities.APIKey
+	mockClientRepo.
+		EXPECT().
+		CreateClient(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, client *entities.Client, key *entities.APIKey) error {
+			if client.Name != "billing" {
+				t.Errorf("name = %q, want trimmed %q", client.Name, "billing")
+			}
+			client.ID = uuid.New()
+			key.ID, key.ClientID = uuid.New(), client.ID
+			stored = key
+			return nil
+		})
+	created, err := s.CreateClient(context.Background(), &dto.ClientCreate{Name: " billing "})
+	if err != nil {
+		t.Fatalf("CreateClient() error = %v", err)
+	}
+	if created.APIKey.Key == "" || created.APIKey.Prefix != stored.Prefix {
+		t.Errorf("api key = %+v, want the generated key", created.APIKey)
+	}
+	if stored.KeyHash == created.APIKey.Key || !auth.Verify(created.APIKey.Key, stored.KeyHash) {
+		t.Error("the key is not stored as its hash")
+	}
+
+	mockClientRepo.EXPECT().CreateClient(gomock.Any(), gomock.Any(), gomock.Any()).Return(repositories.ErrAlreadyExists)
+	if _, err := s.CreateClient(context.Background(), &dto.ClientCreate{Name: "billing"}); !errors.Is(err, ErrClientAlreadyExists) {
+		t.Errorf("CreateClient() error = %v, want %v", err, ErrClientAlreadyExists)
+	}
+	if _, err := s.CreateClient(context.Background(), &dto.ClientCreate{Name: " "}); !errors.Is(err, ErrInvalidClient) {
+		t.Errorf("CreateClient() error = %v, want %v", err, ErrInvalidClient)
+	}
+}
+
+func TestClientServiceImpl_Authenticate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClientRepo := repomocks.NewMockClientRepository(ctrl)
+	s := NewClientServiceImpl(mockClientRepo)
+
+	newKey := func(expiresAt, revokedAt *time.Time) (string, *entities.APIKey) {
+		key, prefix, err := auth.GenerateKey()
+		if err != nil {
+			t.Fatalf("GenerateKey() error = %v", err)
+		}
+		entity := &entities.APIKey{
+			ID:        uuid.New(),
+			ClientID:  uuid.New(),
+			Prefix:    prefix,
+			KeyHash:   auth.Hash(key),
+			ExpiresAt: expiresAt,
+			RevokedAt: revokedAt,
+		}
+		mockClientRepo.EXPECT().GetAPIKeyByPrefix(gomock.Any(), prefix).Return(entity, nil).AnyTimes()
+		return key, entity
+	}
+	past, future := time.Now().Add(-time.Minute), time.Now().Add(time.Hour)
+	tenantID := uuid.New()
+	mockClientRepo.
+		EXPECT().
+		GetClient(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, id uuid.UUID) (*entities.Client, error) {
+			return &entities.Client{ID: id, TenantID: &tenantID}, nil
+		}).
+		AnyTimes()
+
+	key, entity := newKey(nil, nil)
+	identity, err := s.Authenticate(context.Background(), key)
+	if err != nil || *identity.ClientID != entity.ClientID {
+		t.Fatalf("Authenticate() = %v, %v, want client %v", identity, err, entity.ClientID)
+	}
+	if identity.TenantID == nil || *identity.TenantID != tenantID {
+		t.Errorf("Authenticate() tenant = %v, want the tenant of the client %v", identity.TenantID, tenantID)
+	}
+	if identity.Method != auth.MethodAPIKey || identity.Subject != entity.Prefix ||
+		!identity.HasScope(auth.ScopeNotificationsWrite) || identity.HasScope(auth.ScopeAdmin) {
+		t.Errorf("Authenticate() identity = %+v", identity)
+	}
+	rotated, entity := newKey(&future, nil)
+	if identity, err := s.Authenticate(context.Background(), rotated); err != nil || *identity.ClientID != entity.ClientID {
+		t.Errorf("Authenticate() of a rotated key in its grace period = %v, %v", identity, err)
+	}
+
+	expired, _ := newKey(&past, nil)
+	revoked, _ := newKey(nil, &past)
+	mockClientRepo.EXPECT().GetAPIKeyByPrefix(gomock.Any(), gomock.Any()).Return(nil, repositories.ErrNotFound)
+	unknown, _, _ := auth.GenerateKey()
+	prefix, _ := auth.Prefix(key)
+	for name, key := range map[string]string{
+		"expired":      expired,
+		"revoked":      revoked,
+		"unknown":      unknown,
+		"wrong secret": prefix + "_guessed",
+		"malformed":    "secret",
+	} {
+		if _, err := s.Authenticate(context.Background(), key); !errors.Is(err, ErrInvalidAPIKey) {
+			t.Errorf("Authenticate() of a %s key error = %v, want %v", name, err, ErrInvalidAPIKey)
+		}
+	}
+}
+
+func TestClientServiceImpl_AuthenticateToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClientRepo := repomocks.NewMockClientRepository(ctrl)
+	s := NewClientServiceImpl(mockClientRepo)
+
+	tenantID := uuid.New()
+	client := &entities.Client{ID: uuid.New(), Name: "billing", TenantID: &tenantID}
+	mockClientRepo.EXPECT().GetClientByOAuthClientID(gomock.Any(), "billing-app").Return(client, nil).AnyTimes()
+	mockClientRepo.EXPECT().GetClientByOAuthClientID(gomock.Any(), gomock.Any()).Return(nil, repositories.ErrNotFound).AnyTimes()
+
+	identity, err := s.AuthenticateToken(context.Background(), &jwt.Claims{
+		Subject:  "service-account",
+		ClientID: "billing-app",
+		Scope:    "notifications:read",
+	})
+	if err != nil {
+		t.Fatalf("AuthenticateToken() error = %v", err)
+	}
+	if identity.Method != auth.MethodJWT || identity.Subject != "service-account" ||
+		*identity.ClientID != client.ID || *identity.TenantID != tenantID {
+		t.Errorf("AuthenticateToken() identity = %+v", identity)
+	}
+	if !identity.HasScope(auth.ScopeNotificationsRead) || identity.HasScope(auth.ScopeNotificationsWrite) {
+		t.Errorf("AuthenticateToken() scopes = %v", identity.Scopes)
+	}
+
+	if _, err := s.AuthenticateToken(context.Background(), &jwt.Claims{
+		Subject:  "other",
+		ClientID: "unknown-app",
+		Scope:    "notifications:write",
+	}); !errors.Is(err, ErrClientNotLinked) {
+		t.Errorf("AuthenticateToken() of an unlinked client error = %v, want %v", err, ErrClientNotLinked)
+	}
+	identity, err = s.AuthenticateToken(context.Background(), &jwt.Claims{
+		Subject:  "operator",
+		ClientID: "unknown-app",
+		Scope:    "admin",
+	})
+	if err != nil || identity.ClientID != nil {
+		t.Errorf("AuthenticateToken() of an unlinked admin = %+v, %v, want unscoped", identity, err)
+	}
+}
+
+func TestClientServiceImpl_RotateAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClientRepo := repomocks.NewMockClientRepository(ctrl)
+	s := NewClientServiceImpl(mockClientRepo)
+
+	clientID, keyID := uuid.New(), uuid.New()
+	before := time.Now()
+	mockClientRepo.
+		EXPECT().
+		RotateAPIKey(gomock.Any(), clientID, keyID, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ uuid.UUID, key *entities.APIKey, expiresAt time.Time) error {
+			if expiresAt.Before(before.Add(time.Hour)) || expiresAt.After(time.Now().Add(time.Hour)) {
+				t.Errorf("old key expires at %v, want in an hour", expiresAt)
+			}
+			key.ID, key.ClientID = uuid.New(), clientID
+			return nil
+		})
+	created, err := s.RotateAPIKey(context.Background(), clientID, keyID, time.Hour)
+	if err != nil || created.Key == "" {
+		t.Fatalf("RotateAPIKey() = %+v, %v", created, err)
+	}
+
+	mockClientRepo.EXPECT().RotateAPIKey(gomock.Any(), clientID, keyID, gomock.Any(), gomock.Any()).Return(repositories.ErrNotFound)
+	if _, err := s.RotateAPIKey(context.Background(), clientID, keyID, 0); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Errorf("RotateAPIKey() error = %v, want %v", err, ErrAPIKeyNotFound)
+	}
+	if _, err := s.RotateAPIKey(context.Background(), clientID, keyID, -time.Second); !errors.Is(err, ErrInvalidGracePeriod) {
+		t.Errorf("RotateAPIKey() error = %v, want %v", err, ErrInvalidGracePeriod)
+	}
+}
+
+func TestClientServiceImpl_CreateTenant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClientRepo := repomocks.NewMockClientRepository(ctrl)
+	s := NewClientServiceImpl(mockClientRepo)
+
+	mockClientRepo.
+		EXPECT().
+		CreateTenant(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, tenant *entities.Tenant) error {
+			if tenant.SMTPPort == nil || *tenant.SMTPPort != defaultSMTPPort {
+				t.Errorf("smtp port = %v, want %d", tenant.SMTPPort, defaultSMTPPort)
+			}
+			tenant.ID = uuid.New()
+			return nil
+		})
+	tenant, err := s.CreateTenant(context.Background(), &dto.TenantCreate{
+		Name:         "retail",
+		EmailFrom:    "Retail <noreply@retail.example.com>",
+		SMTPHost:     "smtp.retail.example.com",
+		SMTPPassword: "secret",
+	})
+	if err != nil {
+		t.Fatalf("CreateTenant() error = %v", err)
+	}
+	if tenant.ID == uuid.Nil || tenant.SMTPHost == nil {
+		t.Errorf("CreateTenant() = %+v", tenant)
+	}
+
+	for name, tenantCreate := range map[string]*dto.TenantCreate{
+		"without a name":         {EmailFrom: "noreply@retail.example.com"},
+		"with an invalid from":   {Name: "retail", EmailFrom: "retail"},
+		"with a server, no from": {Name: "retail", SMTPHost: "smtp.retail.example.com"},
+		"with a password only":   {Name: "retail", SMTPPassword: "secret"},
+		"with an invalid port":   {Name: "retail", EmailFrom: "a@b.c", SMTPHost: "smtp", SMTPPort: 70000},
+	} {
+		if _, err := s.CreateTenant(context.Background(), tenantCreate); !errors.Is(err, ErrInvalidTenant) {
+			t.Errorf("CreateTenant() %s error = %v, want %v", name, err, ErrInvalidTenant)
+		}
+	}
+}
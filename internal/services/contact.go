@@ -0,0 +1,346 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/audit"
+	"notification_system/internal/auth"
+	"notification_system/internal/dto"
+	"notification_system/internal/entities"
+	"notification_system/internal/repositories"
+	slogger "notification_system/pkg/logger"
+)
+
+const (
+	verificationCodeDigits = 6
+	verificationCodeTTL    = 15 * time.Minute
+	// verificationMaxAttempts wrong codes invalidate the code, a new one has to be sent
+	verificationMaxAttempts = 5
+)
+
+type ContactServiceImpl struct {
+	contactRepo      repositories.ContactRepository
+	notificationRepo repositories.NotificationRepository
+}
+
+func NewContactServiceImpl(
+	contactRepo repositories.ContactRepository,
+	notificationRepo repositories.NotificationRepository,
+) ContactService {
+	return &ContactServiceImpl{
+		contactRepo:      contactRepo,
+		notificationRepo: notificationRepo,
+	}
+}
+
+func (s *ContactServiceImpl) CreateContact(ctx context.Context, contactCreate *dto.ContactCreate) (*dto.Contact, error) {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	if contactCreate.UserID == "" {
+		return nil, ErrInvalidContact
+	}
+	if err := validateTimeZone(contactCreate.TimeZone); err != nil {
+		return nil, err
+	}
+	addresses := make([]*entities.ContactAddress, 0, len(contactCreate.Addresses))
+	seen := make(map[[2]string]bool, len(contactCreate.Addresses))
+	for i := range contactCreate.Addresses {
+		addressCreate := &contactCreate.Addresses[i]
+		if err := validateContactAddress(addressCreate.DeliveryType, addressCreate.Address); err != nil {
+			return nil, err
+		}
+		key := [2]string{addressCreate.DeliveryType, addressCreate.Address}
+		if seen[key] {
+			return nil, ErrContactAddressAlreadyExists
+		}
+		seen[key] = true
+		addresses = append(addresses, contactAddressEntity(ctx, contactCreate.UserID, addressCreate))
+	}
+	contact := &entities.Contact{
+		UserID:   contactCreate.UserID,
+		TenantID: auth.TenantIDFromContext(ctx),
+		Name:     contactCreate.Name,
+		TimeZone: contactCreate.TimeZone,
+	}
+	if err := s.contactRepo.CreateContact(ctx, contact, addresses); err != nil {
+		if errors.Is(err, repositories.ErrAlreadyExists) {
+			return nil, ErrContactAlreadyExists
+		}
+		logger.Error("failed to create contact", slog.Any("error", err))
+		return nil, ErrCannotCreateContact
+	}
+	audit.Record(ctx, "contact.create", "contact", contact.UserID, nil, contactAuditState(contact, addresses))
+	return dto.ContactEntityToDTO(contact, addresses), nil
+}
+
+func (s *ContactServiceImpl) GetContact(ctx context.Context, userID string) (*dto.Contact, error) {
+	contact, err := s.contactRepo.GetContact(ctx, auth.TenantIDFromContext(ctx), userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrContactNotFound
+		}
+		return nil, ErrCannotGetContact
+	}
+	addresses, err := s.contactRepo.GetContactAddresses(ctx, auth.TenantIDFromContext(ctx), userID)
+	if err != nil {
+		return nil, ErrCannotGetContact
+	}
+	return dto.ContactEntityToDTO(contact, addresses), nil
+}
+
+func (s *ContactServiceImpl) UpdateContact(ctx context.Context, userID string, contactUpdate *dto.ContactUpdate) (*dto.Contact, error) {
+	if err := validateTimeZone(contactUpdate.TimeZone); err != nil {
+		return nil, err
+	}
+	contact := &entities.Contact{
+		UserID:   userID,
+		TenantID: auth.TenantIDFromContext(ctx),
+		Name:     contactUpdate.Name,
+		TimeZone: contactUpdate.TimeZone,
+	}
+	before := s.auditedContact(ctx, userID)
+	if err := s.contactRepo.UpdateContact(ctx, contact); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrContactNotFound
+		}
+		return nil, ErrCannotUpdateContact
+	}
+	addresses, err := s.contactRepo.GetContactAddresses(ctx, auth.TenantIDFromContext(ctx), userID)
+	if err != nil {
+		return nil, ErrCannotGetContact
+	}
+	audit.Record(ctx, "contact.update", "contact", userID, before, contactAuditState(contact, addresses))
+	return dto.ContactEntityToDTO(contact, addresses), nil
+}
+
+// DeleteContact removes the contact together with its addresses.
+func (s *ContactServiceImpl) DeleteContact(ctx context.Context, userID string) error {
+	before := s.auditedContact(ctx, userID)
+	if err := s.contactRepo.DeleteContact(ctx, auth.TenantIDFromContext(ctx), userID); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrContactNotFound
+		}
+		return ErrCannotDeleteContact
+	}
+	audit.Record(ctx, "contact.delete", "contact", userID, before, nil)
+	return nil
+}
+
+func (s *ContactServiceImpl) AddAddress(ctx context.Context, userID string, addressCreate *dto.ContactAddressCreate) (*dto.ContactAddress, error) {
+	if err := validateContactAddress(addressCreate.DeliveryType, addressCreate.Address); err != nil {
+		return nil, err
+	}
+	address, err := s.createAddress(ctx, userID, addressCreate)
+	if err != nil {
+		return nil, err
+	}
+	audit.Record(ctx, "contact_address.create", "contact_address", address.ID.String(), nil, contactAddressAuditState(address))
+	return dto.ContactAddressEntityToDTO(address), nil
+}
+
+func (s *ContactServiceImpl) UpdateAddress(ctx context.Context, userID string, id uuid.UUID, addressUpdate *dto.ContactAddressUpdate) (*dto.ContactAddress, error) {
+	if addressUpdate.Address == "" {
+		return nil, ErrInvalidContactAddress
+	}
+	address := &entities.ContactAddress{
+		ID:       id,
+		UserID:   userID,
+		TenantID: auth.TenantIDFromContext(ctx),
+		Address:  addressUpdate.Address,
+		Primary:  addressUpdate.Primary,
+	}
+	before := s.auditedAddress(ctx, userID, id)
+	if err := s.contactRepo.UpdateContactAddress(ctx, address); err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrNotFound):
+			return nil, ErrContactAddressNotFound
+		case errors.Is(err, repositories.ErrAlreadyExists):
+			return nil, ErrContactAddressAlreadyExists
+		}
+		return nil, ErrCannotUpdateContactAddress
+	}
+	audit.Record(ctx, "contact_address.update", "contact_address", id.String(), before, contactAddressAuditState(address))
+	return dto.ContactAddressEntityToDTO(address), nil
+}
+
+func (s *ContactServiceImpl) DeleteAddress(ctx context.Context, userID string, id uuid.UUID) error {
+	before := s.auditedAddress(ctx, userID, id)
+	if err := s.contactRepo.DeleteContactAddress(ctx, auth.TenantIDFromContext(ctx), userID, id); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrContactAddressNotFound
+		}
+		return ErrCannotDeleteContactAddress
+	}
+	audit.Record(ctx, "contact_address.delete", "contact_address", id.String(), before, nil)
+	return nil
+}
+
+// SendVerificationCode sends a one-time code to the address through the regular pipeline.
+func (s *ContactServiceImpl) SendVerificationCode(ctx context.Context, userID string, id uuid.UUID) error {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	code, err := generateVerificationCode()
+	if err != nil {
+		logger.Error("failed to generate verification code", slog.Any("error", err))
+		return ErrCannotSendVerificationCode
+	}
+	address, err := s.contactRepo.SetVerificationCode(ctx, auth.TenantIDFromContext(ctx), userID, id, hashVerificationCode(code), time.Now().Add(verificationCodeTTL))
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrContactAddressNotFound
+		}
+		logger.Error("failed to store verification code", slog.Any("error", err))
+		return ErrCannotSendVerificationCode
+	}
+	notification := &entities.Notification{
+		DeliveryType: address.DeliveryType,
+		Recipient:    address.Address,
+		Content: fmt.Sprintf("Your verification code is %s. It expires in %d minutes.",
+			code, int(verificationCodeTTL.Minutes())),
+		Priority: entities.PriorityHigh,
+	}
+	notification.ClientID, notification.TenantID = callerOwner(ctx)
+	if err := s.notificationRepo.CreateNotifications(ctx, []*entities.Notification{notification}); err != nil {
+		logger.Error("failed to send verification code", slog.Any("error", err))
+		return ErrCannotSendVerificationCode
+	}
+	return nil
+}
+
+func (s *ContactServiceImpl) VerifyAddress(ctx context.Context, userID string, id uuid.UUID, verify *dto.ContactAddressVerify) (*dto.ContactAddress, error) {
+	if verify.Code == "" {
+		return nil, ErrInvalidVerificationCode
+	}
+	before := s.auditedAddress(ctx, userID, id)
+	address, err := s.contactRepo.VerifyContactAddress(ctx, auth.TenantIDFromContext(ctx), userID, id, hashVerificationCode(verify.Code), verificationMaxAttempts)
+	if err != nil {
+		if errors.Is(err, repositories.ErrInvalidVerificationCode) {
+			return nil, ErrInvalidVerificationCode
+		}
+		return nil, ErrCannotVerifyContactAddress
+	}
+	audit.Record(ctx, "contact_address.verify", "contact_address", id.String(), before, contactAddressAuditState(address))
+	return dto.ContactAddressEntityToDTO(address), nil
+}
+
+// auditedContact returns the contact with its addresses for the audit log, nil when the
+// request is not audited or there is no contact.
+func (s *ContactServiceImpl) auditedContact(ctx context.Context, userID string) *dto.Contact {
+	if !audit.Enabled(ctx) {
+		return nil
+	}
+	tenantID := auth.TenantIDFromContext(ctx)
+	contact, err := s.contactRepo.GetContact(ctx, tenantID, userID)
+	if err != nil {
+		return nil
+	}
+	addresses, err := s.contactRepo.GetContactAddresses(ctx, tenantID, userID)
+	if err != nil {
+		return nil
+	}
+	return contactAuditState(contact, addresses)
+}
+
+// auditedAddress returns the address of the contact for the audit log, nil when the request
+// is not audited or there is no address.
+func (s *ContactServiceImpl) auditedAddress(ctx context.Context, userID string, id uuid.UUID) *dto.ContactAddress {
+	if !audit.Enabled(ctx) {
+		return nil
+	}
+	addresses, err := s.contactRepo.GetContactAddresses(ctx, auth.TenantIDFromContext(ctx), userID)
+	if err != nil {
+		return nil
+	}
+	for _, address := range addresses {
+		if address.ID == id {
+			return contactAddressAuditState(address)
+		}
+	}
+	return nil
+}
+
+// contactAuditState returns the contact for the audit log, which is kept without retention, with
+// its addresses masked.
+func contactAuditState(contact *entities.Contact, addresses []*entities.ContactAddress) *dto.Contact {
+	state := dto.ContactEntityToDTO(contact, addresses)
+	for _, address := range state.Addresses {
+		address.Address = slogger.Mask(address.Address)
+	}
+	return state
+}
+
+// contactAddressAuditState returns the address for the audit log, masked.
+func contactAddressAuditState(address *entities.ContactAddress) *dto.ContactAddress {
+	state := dto.ContactAddressEntityToDTO(address)
+	state.Address = slogger.Mask(state.Address)
+	return state
+}
+
+func (s *ContactServiceImpl) createAddress(ctx context.Context, userID string, addressCreate *dto.ContactAddressCreate) (*entities.ContactAddress, error) {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	address := contactAddressEntity(ctx, userID, addressCreate)
+	if err := s.contactRepo.CreateContactAddress(ctx, address); err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrNotFound):
+			return nil, ErrContactNotFound
+		case errors.Is(err, repositories.ErrAlreadyExists):
+			return nil, ErrContactAddressAlreadyExists
+		}
+		logger.Error("failed to create contact address", slog.Any("error", err))
+		return nil, ErrCannotCreateContactAddress
+	}
+	return address, nil
+}
+
+// contactAddressEntity returns the unverified address of the caller's tenant, it is verified
+// only with a code sent to it.
+func contactAddressEntity(ctx context.Context, userID string, addressCreate *dto.ContactAddressCreate) *entities.ContactAddress {
+	return &entities.ContactAddress{
+		UserID:       userID,
+		TenantID:     auth.TenantIDFromContext(ctx),
+		DeliveryType: addressCreate.DeliveryType,
+		Address:      addressCreate.Address,
+		Primary:      addressCreate.Primary,
+	}
+}
+
+// validateContactAddress rejects channels that are not addressed per user:
+// chains are a combination of channels and web push targets the user directly.
+func validateContactAddress(deliveryType, address string) error {
+	switch deliveryType {
+	case "", entities.DeliveryTypeChain, entities.DeliveryTypeWebPush:
+		return ErrInvalidContactAddress
+	}
+	if address == "" {
+		return ErrInvalidContactAddress
+	}
+	return nil
+}
+
+func generateVerificationCode() (string, error) {
+	limit := big.NewInt(1)
+	for range verificationCodeDigits {
+		limit.Mul(limit, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", verificationCodeDigits, n), nil
+}
+
+func hashVerificationCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
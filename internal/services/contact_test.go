@@ -0,0 +1,192 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"notification_system/internal/audit"
+	"notification_system/internal/auth"
+	"notification_system/internal/dto"
+	"notification_system/internal/entities"
+	"notification_system/internal/repositories"
+	"notification_system/internal/repositories/mocks"
+)
+
+func TestContactServiceImpl_SendVerificationCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	contactRepo := repomocks.NewMockContactRepository(ctrl)
+	notificationRepo := repomocks.NewMockNotificationRepository(ctrl)
+	userID, id := "user-1", uuid.New()
+
+	var storedHash string
+	contactRepo.
+		EXPECT().
+		SetVerificationCode(gomock.Any(), gomock.Nil(), userID, id, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ *uuid.UUID, _ string, _ uuid.UUID, codeHash string, expiresAt time.Time) (*entities.ContactAddress, error) {
+			storedHash = codeHash
+			if time.Until(expiresAt) > verificationCodeTTL {
+				t.Errorf("code expires at %v, later than the TTL", expiresAt)
+			}
+			return &entities.ContactAddress{ID: id, UserID: userID, DeliveryType: "email", Address: "user@example.com"}, nil
+		})
+	notificationRepo.
+		EXPECT().
+		CreateNotifications(gomock.Any(), gomock.Len(1)).
+		DoAndReturn(func(_ context.Context, notifications []*entities.Notification) error {
+			notification := notifications[0]
+			if notification.Recipient != "user@example.com" || notification.DeliveryType != "email" {
+				t.Errorf("verification sent to %s %s", notification.DeliveryType, notification.Recipient)
+			}
+			code := regexp.MustCompile(`\d{6}`).FindString(notification.Content)
+			if code == "" || hashVerificationCode(code) != storedHash {
+				t.Errorf("content %q does not contain the stored code", notification.Content)
+			}
+			return nil
+		})
+
+	s := NewContactServiceImpl(contactRepo, notificationRepo)
+	if err := s.SendVerificationCode(context.Background(), userID, id); err != nil {
+		t.Fatalf("SendVerificationCode() error = %v", err)
+	}
+}
+
+func TestContactServiceImpl_VerifyAddress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	contactRepo := repomocks.NewMockContactRepository(ctrl)
+	id := uuid.New()
+	contactRepo.
+		EXPECT().
+		VerifyContactAddress(gomock.Any(), gomock.Nil(), "user-1", id, hashVerificationCode("123456"), int32(verificationMaxAttempts)).
+		Return(nil, repositories.ErrInvalidVerificationCode)
+
+	s := NewContactServiceImpl(contactRepo, nil)
+	_, err := s.VerifyAddress(context.Background(), "user-1", id, &dto.ContactAddressVerify{Code: "123456"})
+	if !errors.Is(err, ErrInvalidVerificationCode) {
+		t.Errorf("VerifyAddress() error = %v, want %v", err, ErrInvalidVerificationCode)
+	}
+	_, err = s.VerifyAddress(context.Background(), "user-1", id, &dto.ContactAddressVerify{})
+	if !errors.Is(err, ErrInvalidVerificationCode) {
+		t.Errorf("VerifyAddress() error = %v, want %v", err, ErrInvalidVerificationCode)
+	}
+}
+
+func TestContactServiceImpl_CreateContact(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	contactRepo := repomocks.NewMockContactRepository(ctrl)
+	contactRepo.
+		EXPECT().
+		CreateContact(gomock.Any(), gomock.Any(), gomock.Len(2)).
+		DoAndReturn(func(_ context.Context, contact *entities.Contact, addresses []*entities.ContactAddress) error {
+			for _, address := range addresses {
+				if address.UserID != contact.UserID || address.VerifiedAt != nil {
+					t.Errorf("unexpected address %+v", address)
+				}
+			}
+			return nil
+		})
+
+	s := NewContactServiceImpl(contactRepo, nil)
+	contact, err := s.CreateContact(context.Background(), &dto.ContactCreate{
+		UserID: "user-1",
+		Addresses: []dto.ContactAddressCreate{
+			{DeliveryType: "email", Address: "user@example.com", Primary: true},
+			{DeliveryType: "sms", Address: "+15550100"},
+		},
+	})
+	if err != nil || len(contact.Addresses) != 2 {
+		t.Fatalf("CreateContact() = %v, %v, want the contact with its addresses", contact, err)
+	}
+
+	// a duplicate address is rejected before anything is stored
+	_, err = s.CreateContact(context.Background(), &dto.ContactCreate{
+		UserID: "user-2",
+		Addresses: []dto.ContactAddressCreate{
+			{DeliveryType: "email", Address: "user@example.com"},
+			{DeliveryType: "email", Address: "user@example.com"},
+		},
+	})
+	if !errors.Is(err, ErrContactAddressAlreadyExists) {
+		t.Errorf("CreateContact() error = %v, want %v", err, ErrContactAddressAlreadyExists)
+	}
+}
+
+func TestContactServiceImpl_CreateContact_Audited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	contactRepo := repomocks.NewMockContactRepository(ctrl)
+	contactRepo.EXPECT().CreateContact(gomock.Any(), gomock.Any(), gomock.Len(1)).Return(nil)
+	ctx := context.WithValue(context.Background(), audit.ChangeKey, &audit.Change{})
+
+	contact, err := NewContactServiceImpl(contactRepo, nil).CreateContact(ctx, &dto.ContactCreate{
+		UserID:    "user-1",
+		Addresses: []dto.ContactAddressCreate{{DeliveryType: "email", Address: "user@example.com"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateContact() error = %v", err)
+	}
+	if contact.Addresses[0].Address != "user@example.com" {
+		t.Errorf("address = %q, want it unmasked in the response", contact.Addresses[0].Address)
+	}
+	// the audit log is kept without retention, so it holds masked addresses only
+	change, _ := audit.FromContext(ctx)
+	masked := bytes.Contains(change.After, []byte(`"address":"u***@example.com"`)) &&
+		!bytes.Contains(change.After, []byte("user@example.com"))
+	if change.Action != "contact.create" || !masked {
+		t.Errorf("change %q After = %s, want the contact with its address masked", change.Action, change.After)
+	}
+}
+
+func TestContactServiceImpl_TenantIsolation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	contactRepo := repomocks.NewMockContactRepository(ctrl)
+	tenantID := uuid.New()
+	ctx := context.WithValue(context.Background(), auth.TenantIDKey, tenantID)
+
+	// contacts and addresses are created in the tenant of the caller
+	contactRepo.
+		EXPECT().
+		CreateContact(ctx, gomock.Any(), gomock.Len(1)).
+		DoAndReturn(func(_ context.Context, contact *entities.Contact, addresses []*entities.ContactAddress) error {
+			if contact.TenantID == nil || *contact.TenantID != tenantID {
+				t.Errorf("contact tenant = %v, want %v", contact.TenantID, tenantID)
+			}
+			if addresses[0].TenantID == nil || *addresses[0].TenantID != tenantID {
+				t.Errorf("address tenant = %v, want %v", addresses[0].TenantID, tenantID)
+			}
+			return nil
+		})
+	s := NewContactServiceImpl(contactRepo, nil)
+	_, err := s.CreateContact(ctx, &dto.ContactCreate{
+		UserID:    "user-1",
+		Addresses: []dto.ContactAddressCreate{{DeliveryType: "email", Address: "user@example.com"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateContact() error = %v", err)
+	}
+
+	// lookups are scoped to the tenant of the caller
+	contactRepo.EXPECT().GetContact(ctx, &tenantID, "user-1").Return(nil, repositories.ErrNotFound)
+	if _, err := s.GetContact(ctx, "user-1"); !errors.Is(err, ErrContactNotFound) {
+		t.Errorf("GetContact() error = %v, want %v", err, ErrContactNotFound)
+	}
+}
+
+func TestContactServiceImpl_AddAddress_Invalid(t *testing.T) {
+	s := NewContactServiceImpl(nil, nil)
+	for _, address := range []*dto.ContactAddressCreate{
+		{DeliveryType: "email"},
+		{Address: "user@example.com"},
+		{DeliveryType: entities.DeliveryTypeWebPush, Address: "user-1"},
+		{DeliveryType: entities.DeliveryTypeChain, Address: "user@example.com"},
+	} {
+		if _, err := s.AddAddress(context.Background(), "user-1", address); !errors.Is(err, ErrInvalidContactAddress) {
+			t.Errorf("AddAddress(%+v) error = %v, want %v", address, err, ErrInvalidContactAddress)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"notification_system/internal/audit"
+	"notification_system/internal/auth"
+	"notification_system/internal/digests"
+	"notification_system/internal/dto"
+	"notification_system/internal/entities"
+	"notification_system/internal/repositories"
+	slogger "notification_system/pkg/logger"
+)
+
+type DigestServiceImpl struct {
+	digestRepo repositories.DigestRepository
+}
+
+func NewDigestServiceImpl(digestRepo repositories.DigestRepository) DigestService {
+	return &DigestServiceImpl{digestRepo: digestRepo}
+}
+
+func (s *DigestServiceImpl) GetDigestTemplates(ctx context.Context) ([]*dto.DigestTemplate, error) {
+	templates, err := s.digestRepo.GetDigestTemplates(ctx, auth.TenantIDFromContext(ctx))
+	if err != nil {
+		return nil, ErrCannotGetDigestTemplates
+	}
+	return dto.DigestTemplateEntitiesToDTOs(templates), nil
+}
+
+func (s *DigestServiceImpl) UpdateDigestTemplate(ctx context.Context, key string, templateUpdate *dto.DigestTemplateUpdate) (*dto.DigestTemplate, error) {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	if key == "" || templateUpdate.Template == "" {
+		return nil, ErrInvalidDigestTemplate
+	}
+	// render a sample so templates that only fail on execution are rejected as well
+	if _, err := digests.Render(templateUpdate.Template, &entities.Digest{
+		Key:   key,
+		Items: []*entities.Notification{{}},
+	}); err != nil {
+		return nil, ErrInvalidDigestTemplate
+	}
+	template := &entities.DigestTemplate{
+		Key:      key,
+		TenantID: auth.TenantIDFromContext(ctx),
+		Template: templateUpdate.Template,
+	}
+	before := s.auditedDigestTemplate(ctx, key)
+	if err := s.digestRepo.UpsertDigestTemplate(ctx, template); err != nil {
+		logger.Error("failed to update digest template", slog.Any("error", err))
+		return nil, ErrCannotUpdateDigestTemplate
+	}
+	audit.Record(ctx, "digest_template.update", "digest_template", key, before, dto.DigestTemplateEntityToDTO(template))
+	return dto.DigestTemplateEntityToDTO(template), nil
+}
+
+func (s *DigestServiceImpl) DeleteDigestTemplate(ctx context.Context, key string) error {
+	before := s.auditedDigestTemplate(ctx, key)
+	if err := s.digestRepo.DeleteDigestTemplate(ctx, auth.TenantIDFromContext(ctx), key); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrDigestTemplateNotFound
+		}
+		return ErrCannotDeleteDigestTemplate
+	}
+	audit.Record(ctx, "digest_template.delete", "digest_template", key, before, nil)
+	return nil
+}
+
+// auditedDigestTemplate returns the template for the audit log, nil when the request
+// is not audited or there is no template.
+func (s *DigestServiceImpl) auditedDigestTemplate(ctx context.Context, key string) *dto.DigestTemplate {
+	if !audit.Enabled(ctx) {
+		return nil
+	}
+	template, err := s.digestRepo.GetDigestTemplate(ctx, auth.TenantIDFromContext(ctx), key)
+	if err != nil {
+		return nil
+	}
+	return dto.DigestTemplateEntityToDTO(template)
+}
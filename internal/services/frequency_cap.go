@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"notification_system/internal/audit"
+	"notification_system/internal/auth"
+	"notification_system/internal/dto"
+	"notification_system/internal/entities"
+	"notification_system/internal/repositories"
+	slogger "notification_system/pkg/logger"
+)
+
+type FrequencyCapServiceImpl struct {
+	frequencyCapRepo repositories.FrequencyCapRepository
+}
+
+func NewFrequencyCapServiceImpl(frequencyCapRepo repositories.FrequencyCapRepository) FrequencyCapService {
+	return &FrequencyCapServiceImpl{frequencyCapRepo: frequencyCapRepo}
+}
+
+func (s *FrequencyCapServiceImpl) GetFrequencyCaps(ctx context.Context) ([]*dto.FrequencyCap, error) {
+	caps, err := s.frequencyCapRepo.GetFrequencyCaps(ctx, auth.TenantIDFromContext(ctx))
+	if err != nil {
+		return nil, ErrCannotGetFrequencyCaps
+	}
+	return dto.FrequencyCapEntitiesToDTOs(caps), nil
+}
+
+func (s *FrequencyCapServiceImpl) UpdateFrequencyCap(ctx context.Context, capUpdate *dto.FrequencyCapUpdate) (*dto.FrequencyCap, error) {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	if capUpdate.MaxCount <= 0 || capUpdate.PeriodSeconds <= 0 {
+		return nil, ErrInvalidFrequencyCap
+	}
+	frequencyCap := &entities.FrequencyCap{
+		TenantID:      auth.TenantIDFromContext(ctx),
+		DeliveryType:  capUpdate.DeliveryType,
+		Category:      capUpdate.Category,
+		MaxCount:      capUpdate.MaxCount,
+		PeriodSeconds: capUpdate.PeriodSeconds,
+		Policy:        capUpdate.Policy,
+	}
+	if frequencyCap.DeliveryType == "" {
+		frequencyCap.DeliveryType = entities.PreferenceAny
+	}
+	if frequencyCap.Category == "" {
+		frequencyCap.Category = entities.PreferenceAny
+	}
+	switch frequencyCap.Policy {
+	case "":
+		frequencyCap.Policy = entities.FrequencyCapDelay
+	case entities.FrequencyCapDelay, entities.FrequencyCapDrop:
+	default:
+		return nil, ErrInvalidFrequencyCap
+	}
+	before := s.auditedFrequencyCap(ctx, frequencyCap.DeliveryType, frequencyCap.Category)
+	if err := s.frequencyCapRepo.UpsertFrequencyCap(ctx, frequencyCap); err != nil {
+		logger.Error("failed to update frequency cap", slog.Any("error", err))
+		return nil, ErrCannotUpdateFrequencyCap
+	}
+	audit.Record(ctx, "frequency_cap.update", "frequency_cap", frequencyCap.DeliveryType+"/"+frequencyCap.Category,
+		before, dto.FrequencyCapEntityToDTO(frequencyCap))
+	return dto.FrequencyCapEntityToDTO(frequencyCap), nil
+}
+
+func (s *FrequencyCapServiceImpl) DeleteFrequencyCap(ctx context.Context, deliveryType, category string) error {
+	if deliveryType == "" {
+		deliveryType = entities.PreferenceAny
+	}
+	if category == "" {
+		category = entities.PreferenceAny
+	}
+	before := s.auditedFrequencyCap(ctx, deliveryType, category)
+	if err := s.frequencyCapRepo.DeleteFrequencyCap(ctx, auth.TenantIDFromContext(ctx), deliveryType, category); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrFrequencyCapNotFound
+		}
+		return ErrCannotDeleteFrequencyCap
+	}
+	audit.Record(ctx, "frequency_cap.delete", "frequency_cap", deliveryType+"/"+category, before, nil)
+	return nil
+}
+
+// auditedFrequencyCap returns the cap of the channel and category for the audit log, nil
+// when the request is not audited or there is no cap.
+func (s *FrequencyCapServiceImpl) auditedFrequencyCap(ctx context.Context, deliveryType, category string) *dto.FrequencyCap {
+	if !audit.Enabled(ctx) {
+		return nil
+	}
+	caps, err := s.frequencyCapRepo.GetFrequencyCaps(ctx, auth.TenantIDFromContext(ctx))
+	if err != nil {
+		return nil
+	}
+	for _, frequencyCap := range caps {
+		if frequencyCap.DeliveryType == deliveryType && frequencyCap.Category == category {
+			return dto.FrequencyCapEntityToDTO(frequencyCap)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+
+	"notification_system/internal/dto"
+	"notification_system/internal/entities"
+	"notification_system/internal/repositories/mocks"
+)
+
+func TestFrequencyCapServiceImpl_UpdateFrequencyCap(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repomocks.NewMockFrequencyCapRepository(ctrl)
+
+	mockRepo.
+		EXPECT().
+		UpsertFrequencyCap(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, frequencyCap *entities.FrequencyCap) error {
+			if frequencyCap.DeliveryType != "email" || frequencyCap.Category != entities.PreferenceAny {
+				t.Errorf("unexpected scope %+v", frequencyCap)
+			}
+			if frequencyCap.Policy != entities.FrequencyCapDelay {
+				t.Errorf("policy = %q, want default %q", frequencyCap.Policy, entities.FrequencyCapDelay)
+			}
+			return nil
+		})
+
+	s := NewFrequencyCapServiceImpl(mockRepo)
+	_, err := s.UpdateFrequencyCap(context.Background(), &dto.FrequencyCapUpdate{
+		DeliveryType:  "email",
+		MaxCount:      10,
+		PeriodSeconds: 3600,
+	})
+	if err != nil {
+		t.Fatalf("UpdateFrequencyCap() error = %v", err)
+	}
+
+	invalid := []*dto.FrequencyCapUpdate{
+		{MaxCount: 0, PeriodSeconds: 3600},
+		{MaxCount: 10, PeriodSeconds: -1},
+		{MaxCount: 10, PeriodSeconds: 3600, Policy: "throttle"},
+	}
+	for _, capUpdate := range invalid {
+		if _, err := s.UpdateFrequencyCap(context.Background(), capUpdate); !errors.Is(err, ErrInvalidFrequencyCap) {
+			t.Errorf("UpdateFrequencyCap(%+v) error = %v, want %v", capUpdate, err, ErrInvalidFrequencyCap)
+		}
+	}
+}
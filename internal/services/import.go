@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/audit"
+	"notification_system/internal/auth"
+	"notification_system/internal/dto"
+	"notification_system/internal/entities"
+	"notification_system/internal/imports"
+	"notification_system/internal/repositories"
+	slogger "notification_system/pkg/logger"
+)
+
+const (
+	// importBatchSize is the number of rows copied in one transaction
+	importBatchSize = 5000
+	// maxImportErrors is the number of row errors kept for an import
+	maxImportErrors = 1000
+)
+
+type ImportServiceImpl struct {
+	importRepo repositories.ImportRepository
+}
+
+func NewImportServiceImpl(importRepo repositories.ImportRepository) ImportService {
+	return &ImportServiceImpl{importRepo: importRepo}
+}
+
+// CreateImport records the mapping, the file is uploaded to the import afterwards.
+func (s *ImportServiceImpl) CreateImport(ctx context.Context, importCreate *dto.ImportCreate) (*dto.Import, error) {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	switch importCreate.Format {
+	case imports.FormatCSV, imports.FormatNDJSON:
+	default:
+		return nil, ErrInvalidImport
+	}
+	if importCreate.DeliveryType == "" || importCreate.DeliveryType == entities.DeliveryTypeChain {
+		return nil, ErrInvalidImport
+	}
+	if importCreate.RecipientColumn == "" && importCreate.UserIDColumn == "" {
+		return nil, ErrInvalidImport
+	}
+	for name, column := range importCreate.Variables {
+		if name == "" || column == "" {
+			return nil, ErrInvalidImport
+		}
+	}
+	if importCreate.Template == "" {
+		return nil, ErrInvalidImport
+	}
+	if _, err := imports.Parse(importCreate.Template); err != nil {
+		return nil, ErrInvalidImport
+	}
+	imp := &entities.Import{
+		Format:       importCreate.Format,
+		DeliveryType: importCreate.DeliveryType,
+		Mapping: entities.ImportMapping{
+			RecipientColumn: importCreate.RecipientColumn,
+			UserIDColumn:    importCreate.UserIDColumn,
+			Variables:       importCreate.Variables,
+		},
+		Template: importCreate.Template,
+		Priority: importCreate.Priority,
+	}
+	imp.ClientID, imp.TenantID = callerOwner(ctx)
+	switch imp.Priority {
+	case "":
+		imp.Priority = entities.PriorityNormal
+	case entities.PriorityLow, entities.PriorityNormal, entities.PriorityHigh, entities.PriorityCritical:
+	default:
+		return nil, ErrInvalidPriority
+	}
+	if importCreate.Category != "" {
+		if importCreate.Category == entities.PreferenceAny {
+			return nil, ErrInvalidCategory
+		}
+		imp.Category = &importCreate.Category
+	}
+
+	if err := s.importRepo.CreateImport(ctx, imp); err != nil {
+		logger.Error("failed to create import", slog.Any("error", err))
+		return nil, ErrCannotCreateImport
+	}
+	logger.Info("import created", slog.String("id", imp.ID.String()))
+	audit.Record(ctx, "import.create", "import", imp.ID.String(), nil, dto.ImportEntityToDTO(imp))
+	return dto.ImportEntityToDTO(imp), nil
+}
+
+func (s *ImportServiceImpl) GetImports(ctx context.Context, limit, offset uint) ([]*dto.Import, error) {
+	clientID, tenantID := callerOwner(ctx)
+	imps, err := s.importRepo.GetImports(ctx, tenantID, clientID, limit, offset)
+	if err != nil {
+		return nil, ErrCannotGetImports
+	}
+	return dto.ImportEntitiesToDTOs(imps), nil
+}
+
+func (s *ImportServiceImpl) GetImport(ctx context.Context, id uuid.UUID) (*dto.Import, error) {
+	imp, err := s.importRepo.GetImport(ctx, id, auth.TenantIDFromContext(ctx))
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrImportNotFound
+		}
+		return nil, ErrCannotGetImports
+	}
+	if !callerOwns(ctx, imp.ClientID, imp.TenantID) {
+		return nil, ErrImportNotFound
+	}
+	return dto.ImportEntityToDTO(imp), nil
+}
+
+func (s *ImportServiceImpl) GetImportErrors(ctx context.Context, id uuid.UUID, limit, offset uint) ([]*dto.ImportRowError, error) {
+	if _, err := s.GetImport(ctx, id); err != nil {
+		return nil, err
+	}
+	rowErrors, err := s.importRepo.GetImportErrors(ctx, id, limit, offset)
+	if err != nil {
+		return nil, ErrCannotGetImports
+	}
+	return dto.ImportRowErrorEntitiesToDTOs(rowErrors), nil
+}
+
+// UploadImport reads the file row by row while it is uploaded and copies the notifications
+// of the valid rows in batches. Rows that fail are counted and reported, a file that cannot
+// be read to the end fails the import; batches copied before stay created.
+func (s *ImportServiceImpl) UploadImport(ctx context.Context, id uuid.UUID, body io.Reader) (*dto.Import, error) {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	before, err := s.GetImport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	imp, err := s.importRepo.StartImport(ctx, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrImportAlreadyUploaded
+		}
+		logger.Error("failed to start import", slog.Any("error", err))
+		return nil, ErrCannotProcessImport
+	}
+
+	fileErr, err := s.copyRows(ctx, imp, body)
+	status := entities.ImportStatusCompleted
+	var message *string
+	if errors.Is(err, repositories.ErrQuotaExceeded) {
+		// the batches copied before the quota ran out stay created like those before a broken file
+		logger.Warn("import over quota", slog.Any("error", err))
+		text := err.Error()
+		status, message, err = entities.ImportStatusFailed, &text, nil
+	}
+	if err != nil {
+		logger.Error("failed to copy import rows", slog.Any("error", err))
+		text := ErrCannotProcessImport.Error()
+		status, message = entities.ImportStatusFailed, &text
+	} else if fileErr != nil {
+		text := fileErr.Error()
+		status, message = entities.ImportStatusFailed, &text
+	}
+	// the upload may have been cut off with the request, the import still has to finish
+	if finishErr := s.importRepo.FinishImport(context.WithoutCancel(ctx), imp, status, message); finishErr != nil {
+		logger.Error("failed to finish import", slog.Any("error", finishErr))
+		return nil, ErrCannotProcessImport
+	}
+	audit.Record(ctx, "import.upload", "import", imp.ID.String(), before, dto.ImportEntityToDTO(imp))
+	if err != nil {
+		return nil, ErrCannotProcessImport
+	}
+	logger.Info("import processed",
+		slog.String("id", imp.ID.String()),
+		slog.String("status", imp.Status),
+		slog.Int("created", int(imp.CreatedRows)),
+		slog.Int("failed", int(imp.FailedRows)),
+	)
+	return dto.ImportEntityToDTO(imp), nil
+}
+
+// copyRows returns fileErr when the file cannot be read further and err when a batch cannot be copied.
+func (s *ImportServiceImpl) copyRows(ctx context.Context, imp *entities.Import, body io.Reader) (fileErr, err error) {
+	mapper, err := imports.NewMapper(imp)
+	if err != nil {
+		return err, nil
+	}
+	reader, err := imports.NewReader(imp.Format, body)
+	if err != nil {
+		return err, nil
+	}
+
+	notifications := make([]*entities.Notification, 0, importBatchSize)
+	rowErrors := make([]*entities.ImportRowError, 0)
+	failed := 0
+	fail := func(line int, message string) {
+		if int(imp.FailedRows)+failed < maxImportErrors {
+			rowErrors = append(rowErrors, &entities.ImportRowError{ImportID: imp.ID, Line: int32(line), Message: message})
+		}
+		failed++
+	}
+	flush := func() error {
+		if len(notifications) == 0 && failed == 0 {
+			return nil
+		}
+		if err := s.importRepo.CopyImportBatch(ctx, imp, notifications, rowErrors, failed); err != nil {
+			return err
+		}
+		notifications, rowErrors, failed = notifications[:0], rowErrors[:0], 0
+		return nil
+	}
+
+	for {
+		row, err := reader.Next()
+		var rowErr *imports.RowError
+		switch {
+		case errors.Is(err, io.EOF):
+			return nil, flush()
+		case errors.As(err, &rowErr):
+			fail(rowErr.Line, rowErr.Err.Error())
+		case err != nil:
+			return err, flush()
+		default:
+			notification, err := mapper.Notification(row)
+			if err != nil {
+				fail(row.Line, err.Error())
+			} else {
+				notifications = append(notifications, notification)
+			}
+		}
+		if len(notifications)+failed >= importBatchSize {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"notification_system/internal/dto"
+	"notification_system/internal/entities"
+	"notification_system/internal/repositories"
+	"notification_system/internal/repositories/mocks"
+)
+
+func TestImportServiceImpl_CreateImport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockImportRepo := repomocks.NewMockImportRepository(ctrl)
+	mockImportRepo.
+		EXPECT().
+		CreateImport(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, imp *entities.Import) error {
+			if imp.Priority != entities.PriorityNormal || imp.Mapping.RecipientColumn != "email" {
+				t.Errorf("unexpected import %+v", imp)
+			}
+			imp.ID = uuid.New()
+			imp.Status = entities.ImportStatusAwaitingUpload
+			return nil
+		})
+
+	s := NewImportServiceImpl(mockImportRepo)
+	imp, err := s.CreateImport(context.Background(), &dto.ImportCreate{
+		Format:          "csv",
+		DeliveryType:    "email",
+		RecipientColumn: "email",
+		Template:        "Hi {{.first_name}}",
+	})
+	if err != nil {
+		t.Fatalf("CreateImport() error = %v", err)
+	}
+	if imp.Status != entities.ImportStatusAwaitingUpload {
+		t.Errorf("status = %q, want %q", imp.Status, entities.ImportStatusAwaitingUpload)
+	}
+
+	tests := []struct {
+		imp  *dto.ImportCreate
+		want error
+	}{
+		{&dto.ImportCreate{Format: "xlsx", DeliveryType: "email", RecipientColumn: "email", Template: "hi"}, ErrInvalidImport},
+		{&dto.ImportCreate{Format: "csv", DeliveryType: "chain", RecipientColumn: "email", Template: "hi"}, ErrInvalidImport},
+		{&dto.ImportCreate{Format: "csv", DeliveryType: "email", Template: "hi"}, ErrInvalidImport},
+		{&dto.ImportCreate{Format: "csv", DeliveryType: "email", RecipientColumn: "email", Template: "{{.name"}, ErrInvalidImport},
+		{&dto.ImportCreate{Format: "csv", DeliveryType: "email", RecipientColumn: "email", Template: "hi", Variables: map[string]string{"name": ""}}, ErrInvalidImport},
+		{&dto.ImportCreate{Format: "ndjson", DeliveryType: "email", UserIDColumn: "id", Template: "hi", Priority: "urgent"}, ErrInvalidPriority},
+	}
+	for _, tt := range tests {
+		if _, err := s.CreateImport(context.Background(), tt.imp); !errors.Is(err, tt.want) {
+			t.Errorf("CreateImport(%+v) error = %v, want %v", tt.imp, err, tt.want)
+		}
+	}
+}
+
+func TestImportServiceImpl_UploadImport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockImportRepo := repomocks.NewMockImportRepository(ctrl)
+	s := NewImportServiceImpl(mockImportRepo)
+
+	imp := &entities.Import{
+		ID:           uuid.New(),
+		Status:       entities.ImportStatusProcessing,
+		Format:       "csv",
+		DeliveryType: entities.DeliveryTypeEmail,
+		Mapping:      entities.ImportMapping{RecipientColumn: "email"},
+		Template:     "Hi {{.name}}",
+		Priority:     entities.PriorityNormal,
+	}
+	// two full batches and a last one, every tenth row is invalid
+	rows := 2*importBatchSize + 10
+	var file strings.Builder
+	file.WriteString("email,name\n")
+	for i := range rows {
+		if i%10 == 9 {
+			file.WriteString("not an address,x\n")
+		} else {
+			fmt.Fprintf(&file, "user%d@example.com,User %d\n", i, i)
+		}
+	}
+
+	mockImportRepo.EXPECT().GetImport(gomock.Any(), imp.ID, gomock.Nil()).Return(imp, nil)
+	mockImportRepo.EXPECT().StartImport(gomock.Any(), imp.ID).Return(imp, nil)
+	batches := 0
+	mockImportRepo.
+		EXPECT().
+		CopyImportBatch(gomock.Any(), imp, gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, imp *entities.Import, notifications []*entities.Notification, rowErrors []*entities.ImportRowError, failed int) error {
+			batches++
+			if len(notifications)+failed > importBatchSize {
+				t.Errorf("batch of %d rows is larger than %d", len(notifications)+failed, importBatchSize)
+			}
+			if len(rowErrors) != failed && int(imp.FailedRows)+failed <= maxImportErrors {
+				t.Errorf("%d row errors kept for %d failed rows", len(rowErrors), failed)
+			}
+			for _, rowError := range rowErrors {
+				if rowError.Line%10 != 1 {
+					t.Errorf("row error on line %d: %s", rowError.Line, rowError.Message)
+				}
+			}
+			if notifications[0].ImportID == nil || !strings.HasPrefix(notifications[0].Content, "Hi User") {
+				t.Errorf("unexpected notification %+v", notifications[0])
+			}
+			imp.CreatedRows += int32(len(notifications))
+			imp.FailedRows += int32(failed)
+			imp.TotalRows += int32(len(notifications) + failed)
+			return nil
+		}).
+		Times(3)
+	mockImportRepo.
+		EXPECT().
+		FinishImport(gomock.Any(), imp, entities.ImportStatusCompleted, nil).
+		DoAndReturn(func(_ context.Context, imp *entities.Import, status string, _ *string) error {
+			imp.Status = status
+			return nil
+		})
+
+	result, err := s.UploadImport(context.Background(), imp.ID, strings.NewReader(file.String()))
+	if err != nil {
+		t.Fatalf("UploadImport() error = %v", err)
+	}
+	if result.Status != entities.ImportStatusCompleted || int(result.TotalRows) != rows || int(result.FailedRows) != rows/10 {
+		t.Errorf("import = %+v", result)
+	}
+
+	// a file that cannot be read to the end fails the import
+	broken := &entities.Import{ID: uuid.New(), Format: "ndjson", DeliveryType: "email", Mapping: entities.ImportMapping{UserIDColumn: "id"}, Template: "hi"}
+	mockImportRepo.EXPECT().GetImport(gomock.Any(), broken.ID, gomock.Nil()).Return(broken, nil)
+	mockImportRepo.EXPECT().StartImport(gomock.Any(), broken.ID).Return(broken, nil)
+	mockImportRepo.
+		EXPECT().
+		FinishImport(gomock.Any(), broken, entities.ImportStatusFailed, gomock.Not(gomock.Nil())).
+		DoAndReturn(func(_ context.Context, imp *entities.Import, status string, message *string) error {
+			imp.Status, imp.Error = status, message
+			return nil
+		})
+	result, err = s.UploadImport(context.Background(), broken.ID, strings.NewReader(strings.Repeat("x", 2<<20)))
+	if err != nil || result.Status != entities.ImportStatusFailed || result.Error == nil {
+		t.Errorf("UploadImport() = %+v, %v, want a failed import", result, err)
+	}
+
+	// a batch over the quota of the client fails the import with the quota
+	overQuota := &entities.Import{ID: uuid.New(), Format: "csv", DeliveryType: "email", Mapping: entities.ImportMapping{UserIDColumn: "id"}, Template: "hi"}
+	mockImportRepo.EXPECT().GetImport(gomock.Any(), overQuota.ID, gomock.Nil()).Return(overQuota, nil)
+	mockImportRepo.EXPECT().StartImport(gomock.Any(), overQuota.ID).Return(overQuota, nil)
+	mockImportRepo.
+		EXPECT().
+		CopyImportBatch(gomock.Any(), overQuota, gomock.Len(1), gomock.Any(), 0).
+		Return(fmt.Errorf("%w: 10 email notifications per day", repositories.ErrQuotaExceeded))
+	mockImportRepo.
+		EXPECT().
+		FinishImport(gomock.Any(), overQuota, entities.ImportStatusFailed, gomock.Not(gomock.Nil())).
+		DoAndReturn(func(_ context.Context, imp *entities.Import, status string, message *string) error {
+			imp.Status, imp.Error = status, message
+			return nil
+		})
+	result, err = s.UploadImport(context.Background(), overQuota.ID, strings.NewReader("id\nuser-1\n"))
+	if err != nil || result.Status != entities.ImportStatusFailed || result.Error == nil ||
+		!strings.Contains(*result.Error, repositories.ErrQuotaExceeded.Error()) {
+		t.Errorf("UploadImport() = %+v, %v, want an import failed on the quota", result, err)
+	}
+
+	// a file is processed once
+	uploaded := uuid.New()
+	mockImportRepo.EXPECT().GetImport(gomock.Any(), uploaded, gomock.Nil()).Return(&entities.Import{ID: uploaded}, nil)
+	mockImportRepo.EXPECT().StartImport(gomock.Any(), uploaded).Return(nil, repositories.ErrNotFound)
+	if _, err := s.UploadImport(context.Background(), uploaded, strings.NewReader("")); !errors.Is(err, ErrImportAlreadyUploaded) {
+		t.Errorf("UploadImport() error = %v, want %v", err, ErrImportAlreadyUploaded)
+	}
+	missing := uuid.New()
+	mockImportRepo.EXPECT().GetImport(gomock.Any(), missing, gomock.Nil()).Return(nil, repositories.ErrNotFound)
+	if _, err := s.UploadImport(context.Background(), missing, strings.NewReader("")); !errors.Is(err, ErrImportNotFound) {
+		t.Errorf("UploadImport() error = %v, want %v", err, ErrImportNotFound)
+	}
+}
@@ -4,39 +4,86 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"time"
 
 	"github.com/google/uuid"
 
+	"notification_system/internal/audit"
+	"notification_system/internal/auth"
 	"notification_system/internal/dto"
 	"notification_system/internal/entities"
 	"notification_system/internal/repositories"
+	"notification_system/pkg/envelope"
 	slogger "notification_system/pkg/logger"
 )
 
+const (
+	maxChainChannels    = 10
+	defaultDigestWindow = time.Hour
+	maxDigestWindow     = 7 * 24 * time.Hour
+)
+
 type NotificationServiceImpl struct {
 	notificationRepo repositories.NotificationRepository
+	chainRepo        repositories.NotificationChainRepository
+	// quotaRepo counts the notifications of API clients against their quotas
+	quotaRepo repositories.QuotaRepository
+	// maskPII hides recipients and contents from callers without the PII scope
+	maskPII bool
 }
 
-func NewNotificationServiceImpl(notificationRepo repositories.NotificationRepository) NotificationService {
+func NewNotificationServiceImpl(
+	notificationRepo repositories.NotificationRepository,
+	chainRepo repositories.NotificationChainRepository,
+	quotaRepo repositories.QuotaRepository,
+	maskPII bool,
+) NotificationService {
 	return &NotificationServiceImpl{
 		notificationRepo: notificationRepo,
+		chainRepo:        chainRepo,
+		quotaRepo:        quotaRepo,
+		maskPII:          maskPII,
 	}
 }
 
+// GetNotificationByID returns a notification of the API client in the context, callers
+// without a client, like the workers, see every notification.
 func (s *NotificationServiceImpl) GetNotificationByID(ctx context.Context, id uuid.UUID) (*dto.Notification, error) {
-	notification, err := s.notificationRepo.GetNotificationByID(ctx, id)
+	notification, err := s.notificationRepo.GetNotificationByID(ctx, id, auth.TenantIDFromContext(ctx))
 	if err != nil {
 		if errors.Is(err, repositories.ErrNotFound) {
 			return nil, ErrNotificationNotFound
 		}
 		return nil, ErrCannotGetNotificationByID
 	}
+	if !ownedByClient(ctx, notification) {
+		return nil, ErrNotificationNotFound
+	}
 	notificationResponse := dto.NotificationEntityToDTO(notification)
+	if notification.DeliveryType == entities.DeliveryTypeChain {
+		channels, err := s.chainRepo.GetNotificationChannels(ctx, id)
+		if err != nil {
+			return nil, ErrCannotGetNotificationByID
+		}
+		attempts, err := s.chainRepo.GetNotificationsByParentID(ctx, id)
+		if err != nil {
+			return nil, ErrCannotGetNotificationByID
+		}
+		notificationResponse.Channels = dto.NotificationChannelEntitiesToDTOs(channels)
+		notificationResponse.Attempts = dto.NotificationEntitiesToDTOs(attempts)
+	}
+	s.maskNotifications(ctx, notificationResponse)
 	return notificationResponse, nil
 }
 
 func (s *NotificationServiceImpl) GetNewNotifications(ctx context.Context, limit uint) ([]*dto.Notification, error) {
-	notifications, err := s.notificationRepo.GetNewNotifications(ctx, limit)
+	var notifications []*entities.Notification
+	var err error
+	if clientID, ok := auth.ClientIDFromContext(ctx); ok {
+		notifications, err = s.notificationRepo.GetNewNotificationsByClientID(ctx, clientID, limit)
+	} else {
+		notifications, err = s.notificationRepo.GetNewNotifications(ctx, limit, auth.TenantIDFromContext(ctx))
+	}
 	if err != nil {
 		if errors.Is(err, repositories.ErrMaxBatchSizeExceeded) {
 			return nil, ErrTooManyRequestedNotifications
@@ -44,21 +91,54 @@ func (s *NotificationServiceImpl) GetNewNotifications(ctx context.Context, limit
 		return nil, ErrCannotGetNotifications
 	}
 	notificationsResponse := dto.NotificationEntitiesToDTOs(notifications)
+	s.maskNotifications(ctx, notificationsResponse...)
 	return notificationsResponse, nil
 }
 
 func (s *NotificationServiceImpl) GetNotificationsByIDs(ctx context.Context, ids []uuid.UUID) ([]*dto.Notification, error) {
-	notifications, err := s.notificationRepo.GetNotificationsByIDs(ctx, ids)
+	notifications, err := s.notificationRepo.GetNotificationsByIDs(ctx, ids, auth.TenantIDFromContext(ctx))
 	if err != nil {
 		if errors.Is(err, repositories.ErrMaxBatchSizeExceeded) {
 			return nil, ErrTooManyRequestedNotifications
 		}
 		return nil, ErrCannotGetNotificationsByIDs
 	}
-	notificationsResponse := dto.NotificationEntitiesToDTOs(notifications)
+	owned := notifications[:0]
+	for _, notification := range notifications {
+		if ownedByClient(ctx, notification) {
+			owned = append(owned, notification)
+		}
+	}
+	notificationsResponse := dto.NotificationEntitiesToDTOs(owned)
+	s.maskNotifications(ctx, notificationsResponse...)
 	return notificationsResponse, nil
 }
 
+// maskNotifications hides the recipients and contents of the notifications, their channels and
+// attempts from callers without the PII scope when masking is enabled. Callers without an
+// identity, like the workers, see them.
+func (s *NotificationServiceImpl) maskNotifications(ctx context.Context, notifications ...*dto.Notification) {
+	if !s.maskPII {
+		return
+	}
+	identity, ok := auth.IdentityFromContext(ctx)
+	if !ok || identity.HasScope(auth.ScopeNotificationsPII) {
+		return
+	}
+	for _, notification := range notifications {
+		notification.Recipient = slogger.Mask(notification.Recipient)
+		notification.Content = slogger.Redacted
+		for _, channel := range notification.Channels {
+			channel.Recipient = slogger.Mask(channel.Recipient)
+			if channel.Content != nil {
+				redacted := slogger.Redacted
+				channel.Content = &redacted
+			}
+		}
+		s.maskNotifications(ctx, notification.Attempts...)
+	}
+}
+
 func (s *NotificationServiceImpl) CreateNotifications(ctx context.Context, notifications []*dto.NotificationCreate) ([]uuid.UUID, error) {
 	logger := slogger.GetLoggerFromContext(ctx)
 
@@ -66,16 +146,60 @@ func (s *NotificationServiceImpl) CreateNotifications(ctx context.Context, notif
 		slog.Int("count", len(notifications)),
 	)
 
-	notificationEntities := make([]*entities.Notification, len(notifications))
-	for i, notification := range notifications {
-		notificationEntities[i] = &entities.Notification{
+	clientID, tenantID := callerOwner(ctx)
+	notificationEntities := make([]*entities.Notification, 0, len(notifications))
+	chains := make([]*entities.NotificationChain, 0)
+	for _, notification := range notifications {
+		if reservedValue(notification.Recipient, notification.Content) {
+			return nil, ErrReservedValue
+		}
+		priority := notification.Priority
+		switch priority {
+		case "":
+			priority = entities.PriorityNormal
+		case entities.PriorityLow, entities.PriorityNormal, entities.PriorityHigh, entities.PriorityCritical:
+		default:
+			return nil, ErrInvalidPriority
+		}
+		entity := &entities.Notification{
 			DeliveryType: notification.DeliveryType,
 			Recipient:    notification.Recipient,
 			Content:      notification.Content,
+			Priority:     priority,
+			ClientID:     clientID,
+			TenantID:     tenantID,
 		}
+		if notification.UserID != "" {
+			entity.UserID = &notification.UserID
+		}
+		if notification.Category != "" {
+			if notification.Category == entities.PreferenceAny {
+				return nil, ErrInvalidCategory
+			}
+			entity.Category = &notification.Category
+		}
+		if notification.DigestKey != "" {
+			if err := setDigest(entity, notification); err != nil {
+				return nil, err
+			}
+		}
+		if len(notification.Channels) == 0 && notification.DeliveryType != entities.DeliveryTypeChain {
+			notificationEntities = append(notificationEntities, entity)
+			continue
+		}
+		channels, err := notificationChannels(notification)
+		if err != nil {
+			return nil, err
+		}
+		chains = append(chains, &entities.NotificationChain{Notification: entity, Channels: channels})
 	}
-	err := s.notificationRepo.CreateNotifications(ctx, notificationEntities)
+
+	usage, err := s.consumeQuota(ctx, notificationEntities, chains)
 	if err != nil {
+		return nil, err
+	}
+	if err := s.notificationRepo.CreateNotificationBatch(ctx, notificationEntities, chains); err != nil {
+		s.releaseQuota(ctx, usage)
 		if errors.Is(err, repositories.ErrMaxBatchSizeExceeded) {
 			logger.Warn("too many notifications in batch",
 				slog.Int("count", len(notifications)),
@@ -88,13 +212,182 @@ func (s *NotificationServiceImpl) CreateNotifications(ctx context.Context, notif
 		return nil, ErrCannotCreateNotifications
 	}
 	ids := make([]uuid.UUID, len(notifications))
-	for i, notification := range notificationEntities {
-		ids[i] = notification.ID
+	nextNotification, nextChain := 0, 0
+	for i, notification := range notifications {
+		if len(notification.Channels) != 0 || notification.DeliveryType == entities.DeliveryTypeChain {
+			ids[i] = chains[nextChain].Notification.ID
+			nextChain++
+			continue
+		}
+		ids[i] = notificationEntities[nextNotification].ID
+		nextNotification++
 	}
 
 	logger.Info("notifications sent successfully",
 		slog.Int("count", len(ids)),
 	)
+	resourceID := ""
+	if len(ids) == 1 {
+		resourceID = ids[0].String()
+	}
+	audit.Record(ctx, "notification.create", "notification", resourceID, nil, map[string]any{"ids": ids})
 
 	return ids, nil
 }
+
+// quotaUsage is what a batch counted against the quotas of a client.
+type quotaUsage struct {
+	clientID uuid.UUID
+	counts   map[string]int32
+	at       time.Time
+}
+
+// consumeQuota counts the notifications of an API client per channel against its daily and
+// monthly quotas, a fallback chain counts against every channel it lists. Callers without a
+// client, like the workers, have no quotas.
+func (s *NotificationServiceImpl) consumeQuota(
+	ctx context.Context,
+	notifications []*entities.Notification,
+	chains []*entities.NotificationChain,
+) (*quotaUsage, error) {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	clientID, ok := auth.ClientIDFromContext(ctx)
+	if !ok || s.quotaRepo == nil {
+		return nil, nil
+	}
+	usage := &quotaUsage{clientID: clientID, counts: make(map[string]int32), at: time.Now()}
+	for _, notification := range notifications {
+		usage.counts[notification.DeliveryType]++
+	}
+	for _, chain := range chains {
+		for _, channel := range chain.Channels {
+			usage.counts[channel.DeliveryType]++
+		}
+	}
+	exceeded, err := s.quotaRepo.ConsumeQuota(ctx, clientID, usage.counts, usage.at)
+	if err != nil {
+		logger.Error("failed to count notifications against the quota", slog.Any("error", err))
+		return nil, ErrCannotCreateNotifications
+	}
+	if exceeded != nil {
+		logger.Warn("quota exceeded",
+			slog.String("delivery_type", exceeded.DeliveryType),
+			slog.String("period", exceeded.Period),
+			slog.Int("limit", int(exceeded.Limit)),
+		)
+		return nil, &QuotaExceededError{QuotaExceeded: exceeded}
+	}
+	return usage, nil
+}
+
+// releaseQuota takes back the counts of a batch that was not created.
+func (s *NotificationServiceImpl) releaseQuota(ctx context.Context, usage *quotaUsage) {
+	if usage == nil {
+		return
+	}
+	if err := s.quotaRepo.ReleaseQuota(context.WithoutCancel(ctx), usage.clientID, usage.counts, usage.at); err != nil {
+		slogger.GetLoggerFromContext(ctx).Error("failed to release quota", slog.Any("error", err))
+	}
+}
+
+// ownedByClient tells if the caller may see the notification. The repository already
+// reads only the notifications of the tenant, the tenant is checked again so a query
+// that misses the filter cannot leak notifications across tenants.
+func ownedByClient(ctx context.Context, notification *entities.Notification) bool {
+	return callerOwns(ctx, notification.ClientID, notification.TenantID)
+}
+
+// callerOwns tells if the caller may work with a resource of the client and tenant. Callers
+// without a client, like admin tokens and the workers, may work with every client of their tenant.
+func callerOwns(ctx context.Context, clientID, tenantID *uuid.UUID) bool {
+	if callerTenantID := auth.TenantIDFromContext(ctx); callerTenantID != nil &&
+		(tenantID == nil || *tenantID != *callerTenantID) {
+		return false
+	}
+	callerClientID, ok := auth.ClientIDFromContext(ctx)
+	return !ok || (clientID != nil && *clientID == callerClientID)
+}
+
+// callerOwner returns the client and tenant that own what the caller creates. The tenant comes
+// from the credentials only, a request cannot create anything in another tenant.
+func callerOwner(ctx context.Context) (clientID, tenantID *uuid.UUID) {
+	if id, ok := auth.ClientIDFromContext(ctx); ok {
+		clientID = &id
+	}
+	return clientID, auth.TenantIDFromContext(ctx)
+}
+
+// setDigest holds the notification back as digested until its digest is summarized.
+func setDigest(entity *entities.Notification, notification *dto.NotificationCreate) error {
+	if len(notification.Channels) != 0 || notification.DeliveryType == entities.DeliveryTypeChain {
+		return ErrInvalidDigest
+	}
+	window := int32(defaultDigestWindow.Seconds())
+	if notification.DigestWindowSeconds != nil {
+		window = *notification.DigestWindowSeconds
+	}
+	if window <= 0 || window > int32(maxDigestWindow.Seconds()) {
+		return ErrInvalidDigest
+	}
+	entity.Status = entities.StatusDigested
+	entity.DigestKey = &notification.DigestKey
+	entity.DigestWindowSeconds = &window
+	return nil
+}
+
+// reservedValue reports whether one of the values would be read back as an encrypted value.
+func reservedValue(values ...string) bool {
+	for _, value := range values {
+		if envelope.IsEncrypted(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func notificationChannels(notification *dto.NotificationCreate) ([]*entities.NotificationChannel, error) {
+	if len(notification.Channels) == 0 || len(notification.Channels) > maxChainChannels {
+		return nil, ErrInvalidChannels
+	}
+	channels := make([]*entities.NotificationChannel, len(notification.Channels))
+	for i, channel := range notification.Channels {
+		recipient := channel.Recipient
+		if recipient == "" {
+			recipient = notification.Recipient
+		}
+		if channel.DeliveryType == "" || channel.DeliveryType == entities.DeliveryTypeChain {
+			return nil, ErrInvalidChannels
+		}
+		if reservedValue(recipient) || (channel.Content != nil && reservedValue(*channel.Content)) {
+			return nil, ErrReservedValue
+		}
+		if recipient == "" && notification.UserID == "" {
+			return nil, ErrInvalidChannels
+		}
+		if channel.TimeoutSeconds != nil && *channel.TimeoutSeconds <= 0 {
+			return nil, ErrInvalidChannels
+		}
+		condition := channel.Condition
+		switch condition {
+		case "":
+			condition = entities.ChainConditionFailedOrTimeout
+		case entities.ChainConditionFailedOrTimeout, entities.ChainConditionFailed:
+		case entities.ChainConditionTimeout:
+			if channel.TimeoutSeconds == nil {
+				return nil, ErrInvalidChannels
+			}
+		default:
+			return nil, ErrInvalidChannels
+		}
+		channels[i] = &entities.NotificationChannel{
+			Step:           int16(i),
+			DeliveryType:   channel.DeliveryType,
+			Recipient:      recipient,
+			Content:        channel.Content,
+			TimeoutSeconds: channel.TimeoutSeconds,
+			Condition:      condition,
+		}
+	}
+	return channels, nil
+}
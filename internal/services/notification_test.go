@@ -2,13 +2,18 @@ package services
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/brianvoe/gofakeit/v6"
+	"github.com/google/uuid"
 	"go.uber.org/mock/gomock"
 
+	"notification_system/internal/auth"
 	"notification_system/internal/dto"
+	"notification_system/internal/entities"
 	"notification_system/internal/repositories/mocks"
+	slogger "notification_system/pkg/logger"
 )
 
 func TestNotificationServiceImpl_CreateNotifications(t *testing.T) {
@@ -35,6 +40,11 @@ func TestNotificationServiceImpl_CreateNotifications(t *testing.T) {
 			args{context.Background(), notifications},
 			false,
 		},
+		{
+			"content read back as encrypted",
+			args{context.Background(), []*dto.NotificationCreate{{DeliveryType: "test", Recipient: "user@example.com", Content: "enc:v1:k1:a:b"}}},
+			true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -42,7 +52,7 @@ func TestNotificationServiceImpl_CreateNotifications(t *testing.T) {
 			mockRepo := repomocks.NewMockNotificationRepository(ctrl)
 			mockRepo.
 				EXPECT().
-				CreateNotifications(tt.args.ctx, gomock.Any()).
+				CreateNotificationBatch(tt.args.ctx, gomock.Any(), gomock.Len(0)).
 				Return(nil).
 				MaxTimes(1)
 			s := &NotificationServiceImpl{
@@ -56,3 +66,273 @@ func TestNotificationServiceImpl_CreateNotifications(t *testing.T) {
 		})
 	}
 }
+
+func TestNotificationServiceImpl_CreateNotifications_Chains(t *testing.T) {
+	timeout := int32(60)
+	tests := []struct {
+		name         string
+		notification *dto.NotificationCreate
+		wantErr      error
+	}{
+		{
+			"chain",
+			&dto.NotificationCreate{
+				Recipient: gofakeit.Email(),
+				Content:   gofakeit.Sentence(5),
+				Channels: []dto.NotificationChannelCreate{
+					{DeliveryType: "push", Recipient: "fcm:token", TimeoutSeconds: &timeout},
+					{DeliveryType: "email"},
+				},
+			},
+			nil,
+		},
+		{
+			"empty chain",
+			&dto.NotificationCreate{DeliveryType: "chain", Recipient: gofakeit.Email()},
+			ErrInvalidChannels,
+		},
+		{
+			"timeout condition without timeout",
+			&dto.NotificationCreate{
+				Recipient: gofakeit.Email(),
+				Channels:  []dto.NotificationChannelCreate{{DeliveryType: "email", Condition: "timeout"}},
+			},
+			ErrInvalidChannels,
+		},
+		{
+			"unknown condition",
+			&dto.NotificationCreate{
+				Recipient: gofakeit.Email(),
+				Channels:  []dto.NotificationChannelCreate{{DeliveryType: "email", Condition: "always"}},
+			},
+			ErrInvalidChannels,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRepo := repomocks.NewMockNotificationRepository(ctrl)
+			if tt.wantErr == nil {
+				mockRepo.
+					EXPECT().
+					CreateNotificationBatch(gomock.Any(), gomock.Len(0), gomock.Len(1)).
+					DoAndReturn(func(_ context.Context, _ []*entities.Notification, chains []*entities.NotificationChain) error {
+						channels := chains[0].Channels
+						if len(channels) != len(tt.notification.Channels) {
+							t.Errorf("got %d channels, want %d", len(channels), len(tt.notification.Channels))
+						}
+						if channels[1].Recipient != tt.notification.Recipient {
+							t.Errorf("channel recipient = %q, want %q", channels[1].Recipient, tt.notification.Recipient)
+						}
+						if channels[1].Condition != entities.ChainConditionFailedOrTimeout {
+							t.Errorf("channel condition = %q, want default", channels[1].Condition)
+						}
+						chains[0].Notification.ID = uuid.New()
+						return nil
+					})
+			}
+			s := &NotificationServiceImpl{
+				notificationRepo: mockRepo,
+			}
+			ids, err := s.CreateNotifications(context.Background(), []*dto.NotificationCreate{tt.notification})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CreateNotifications() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && ids[0] == uuid.Nil {
+				t.Errorf("CreateNotifications() returned empty chain ID")
+			}
+		})
+	}
+}
+
+func TestNotificationServiceImpl_CreateNotifications_Digests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repomocks.NewMockNotificationRepository(ctrl)
+	mockRepo.
+		EXPECT().
+		CreateNotificationBatch(gomock.Any(), gomock.Len(1), gomock.Len(0)).
+		DoAndReturn(func(_ context.Context, notifications []*entities.Notification, _ []*entities.NotificationChain) error {
+			notification := notifications[0]
+			if notification.Status != entities.StatusDigested || *notification.DigestKey != "post-42" {
+				t.Errorf("unexpected digest item %+v", notification)
+			}
+			if *notification.DigestWindowSeconds != int32(defaultDigestWindow.Seconds()) {
+				t.Errorf("digest window = %d, want default", *notification.DigestWindowSeconds)
+			}
+			return nil
+		})
+	s := &NotificationServiceImpl{notificationRepo: mockRepo}
+
+	_, err := s.CreateNotifications(context.Background(), []*dto.NotificationCreate{
+		{DeliveryType: "email", Recipient: gofakeit.Email(), Content: "Alice commented", DigestKey: "post-42"},
+	})
+	if err != nil {
+		t.Fatalf("CreateNotifications() error = %v", err)
+	}
+
+	window := int32(0)
+	invalid := []*dto.NotificationCreate{
+		{DeliveryType: "email", Recipient: gofakeit.Email(), DigestKey: "post-42", DigestWindowSeconds: &window},
+		{DeliveryType: "chain", Recipient: gofakeit.Email(), DigestKey: "post-42"},
+	}
+	for _, notification := range invalid {
+		if _, err := s.CreateNotifications(context.Background(), []*dto.NotificationCreate{notification}); !errors.Is(err, ErrInvalidDigest) {
+			t.Errorf("CreateNotifications() error = %v, want %v", err, ErrInvalidDigest)
+		}
+	}
+}
+
+func TestNotificationServiceImpl_ClientScope(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repomocks.NewMockNotificationRepository(ctrl)
+	s := &NotificationServiceImpl{notificationRepo: mockRepo}
+
+	clientID, otherID := uuid.New(), uuid.New()
+	ctx := context.WithValue(context.Background(), auth.ClientIDKey, clientID)
+	own := &entities.Notification{ID: uuid.New(), ClientID: &clientID}
+	other := &entities.Notification{ID: uuid.New(), ClientID: &otherID}
+	unowned := &entities.Notification{ID: uuid.New()}
+
+	mockRepo.
+		EXPECT().
+		CreateNotificationBatch(ctx, gomock.Any(), gomock.Len(0)).
+		DoAndReturn(func(_ context.Context, notifications []*entities.Notification, _ []*entities.NotificationChain) error {
+			if notifications[0].ClientID == nil || *notifications[0].ClientID != clientID {
+				t.Errorf("client = %v, want %v", notifications[0].ClientID, clientID)
+			}
+			return nil
+		})
+	if _, err := s.CreateNotifications(ctx, []*dto.NotificationCreate{{DeliveryType: "test", Recipient: "a", Content: "b"}}); err != nil {
+		t.Fatalf("CreateNotifications() error = %v", err)
+	}
+
+	mockRepo.EXPECT().GetNotificationByID(gomock.Any(), own.ID, gomock.Nil()).Return(own, nil).Times(2)
+	mockRepo.EXPECT().GetNotificationByID(gomock.Any(), other.ID, gomock.Nil()).Return(other, nil)
+	if _, err := s.GetNotificationByID(ctx, own.ID); err != nil {
+		t.Errorf("GetNotificationByID() of an own notification error = %v", err)
+	}
+	if _, err := s.GetNotificationByID(ctx, other.ID); !errors.Is(err, ErrNotificationNotFound) {
+		t.Errorf("GetNotificationByID() of another client's notification error = %v, want %v", err, ErrNotificationNotFound)
+	}
+	// without a client, like the workers, every notification is visible
+	if _, err := s.GetNotificationByID(context.Background(), own.ID); err != nil {
+		t.Errorf("GetNotificationByID() without a client error = %v", err)
+	}
+
+	ids := []uuid.UUID{own.ID, other.ID, unowned.ID}
+	mockRepo.EXPECT().GetNotificationsByIDs(ctx, ids, gomock.Nil()).Return([]*entities.Notification{own, other, unowned}, nil)
+	notifications, err := s.GetNotificationsByIDs(ctx, ids)
+	if err != nil || len(notifications) != 1 || notifications[0].ID != own.ID {
+		t.Errorf("GetNotificationsByIDs() = %v, %v, want only the own notification", notifications, err)
+	}
+
+	mockRepo.EXPECT().GetNewNotificationsByClientID(ctx, clientID, uint(10)).Return([]*entities.Notification{own}, nil)
+	if _, err := s.GetNewNotifications(ctx, 10); err != nil {
+		t.Errorf("GetNewNotifications() error = %v", err)
+	}
+}
+
+func TestNotificationServiceImpl_TenantIsolation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repomocks.NewMockNotificationRepository(ctrl)
+	s := &NotificationServiceImpl{notificationRepo: mockRepo}
+
+	tenantA, tenantB := uuid.New(), uuid.New()
+	clientA, clientB := uuid.New(), uuid.New()
+	ctxA := context.WithValue(context.WithValue(context.Background(),
+		auth.TenantIDKey, tenantA), auth.ClientIDKey, clientA)
+	// an admin of tenant A not linked to a client sees every notification of the tenant
+	adminCtxA := context.WithValue(context.Background(), auth.TenantIDKey, tenantA)
+	ofA := &entities.Notification{ID: uuid.New(), ClientID: &clientA, TenantID: &tenantA}
+	ofB := &entities.Notification{ID: uuid.New(), ClientID: &clientB, TenantID: &tenantB}
+	ofDefault := &entities.Notification{ID: uuid.New()}
+
+	t.Run("created notifications belong to the tenant of the caller", func(t *testing.T) {
+		checkTenant := func(notification *entities.Notification) {
+			if notification.TenantID == nil || *notification.TenantID != tenantA {
+				t.Errorf("tenant = %v, want %v", notification.TenantID, tenantA)
+			}
+		}
+		mockRepo.
+			EXPECT().
+			CreateNotificationBatch(ctxA, gomock.Len(1), gomock.Len(1)).
+			DoAndReturn(func(_ context.Context, notifications []*entities.Notification, chains []*entities.NotificationChain) error {
+				checkTenant(notifications[0])
+				checkTenant(chains[0].Notification)
+				return nil
+			})
+		_, err := s.CreateNotifications(ctxA, []*dto.NotificationCreate{
+			{DeliveryType: "test", Recipient: "a", Content: "b"},
+			{Content: "c", Channels: []dto.NotificationChannelCreate{{DeliveryType: "test", Recipient: "a"}}},
+		})
+		if err != nil {
+			t.Fatalf("CreateNotifications() error = %v", err)
+		}
+	})
+
+	t.Run("reads are filtered by the tenant of the caller", func(t *testing.T) {
+		mockRepo.EXPECT().GetNotificationByID(ctxA, ofA.ID, &tenantA).Return(ofA, nil)
+		if _, err := s.GetNotificationByID(ctxA, ofA.ID); err != nil {
+			t.Errorf("GetNotificationByID() of the tenant's notification error = %v", err)
+		}
+		mockRepo.EXPECT().GetNewNotifications(adminCtxA, uint(10), &tenantA).Return([]*entities.Notification{ofA}, nil)
+		if _, err := s.GetNewNotifications(adminCtxA, 10); err != nil {
+			t.Errorf("GetNewNotifications() error = %v", err)
+		}
+	})
+
+	t.Run("notifications of another tenant are never returned", func(t *testing.T) {
+		// the repository filters by tenant, a query missing the filter must not leak either
+		mockRepo.EXPECT().GetNotificationByID(adminCtxA, gomock.Any(), &tenantA).Return(ofB, nil)
+		if _, err := s.GetNotificationByID(adminCtxA, ofB.ID); !errors.Is(err, ErrNotificationNotFound) {
+			t.Errorf("GetNotificationByID() of another tenant's notification error = %v, want %v", err, ErrNotificationNotFound)
+		}
+		mockRepo.EXPECT().GetNotificationByID(adminCtxA, gomock.Any(), &tenantA).Return(ofDefault, nil)
+		if _, err := s.GetNotificationByID(adminCtxA, ofDefault.ID); !errors.Is(err, ErrNotificationNotFound) {
+			t.Errorf("GetNotificationByID() of a default tenant notification error = %v, want %v", err, ErrNotificationNotFound)
+		}
+		ids := []uuid.UUID{ofA.ID, ofB.ID, ofDefault.ID}
+		mockRepo.EXPECT().GetNotificationsByIDs(adminCtxA, ids, &tenantA).Return([]*entities.Notification{ofA, ofB, ofDefault}, nil)
+		notifications, err := s.GetNotificationsByIDs(adminCtxA, ids)
+		if err != nil || len(notifications) != 1 || notifications[0].ID != ofA.ID {
+			t.Errorf("GetNotificationsByIDs() = %v, %v, want only the notification of the tenant", notifications, err)
+		}
+	})
+}
+
+func TestNotificationServiceImpl_MaskPII(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repomocks.NewMockNotificationRepository(ctrl)
+	s := &NotificationServiceImpl{notificationRepo: mockRepo, maskPII: true}
+
+	notification := func() *entities.Notification {
+		return &entities.Notification{ID: uuid.New(), Recipient: "alice@example.com", Content: "Your code is 123456"}
+	}
+	reader := &auth.Identity{Scopes: []string{auth.ScopeNotificationsRead}}
+	piiReader := &auth.Identity{Scopes: []string{auth.ScopeNotificationsRead, auth.ScopeNotificationsPII}}
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		masked bool
+	}{
+		{"caller without the PII scope", context.WithValue(context.Background(), auth.IdentityKey, reader), true},
+		{"caller with the PII scope", context.WithValue(context.Background(), auth.IdentityKey, piiReader), false},
+		{"worker without an identity", context.Background(), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo.EXPECT().GetNotificationByID(tt.ctx, gomock.Any(), gomock.Nil()).Return(notification(), nil)
+			got, err := s.GetNotificationByID(tt.ctx, uuid.New())
+			if err != nil {
+				t.Fatalf("GetNotificationByID() error = %v", err)
+			}
+			if tt.masked && (got.Recipient != "a***@example.com" || got.Content != slogger.Redacted) {
+				t.Errorf("GetNotificationByID() = %q, %q, want them masked", got.Recipient, got.Content)
+			}
+			if !tt.masked && (got.Recipient != "alice@example.com" || got.Content != "Your code is 123456") {
+				t.Errorf("GetNotificationByID() = %q, %q, want them unmasked", got.Recipient, got.Content)
+			}
+		})
+	}
+}
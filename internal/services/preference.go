@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"notification_system/internal/audit"
+	"notification_system/internal/auth"
+	"notification_system/internal/dto"
+	"notification_system/internal/entities"
+	"notification_system/internal/repositories"
+	slogger "notification_system/pkg/logger"
+)
+
+type PreferenceServiceImpl struct {
+	preferenceRepo repositories.PreferenceRepository
+}
+
+func NewPreferenceServiceImpl(preferenceRepo repositories.PreferenceRepository) PreferenceService {
+	return &PreferenceServiceImpl{preferenceRepo: preferenceRepo}
+}
+
+func (s *PreferenceServiceImpl) GetPreferences(ctx context.Context, userID string) ([]*dto.Preference, error) {
+	preferences, err := s.preferenceRepo.GetPreferences(ctx, auth.TenantIDFromContext(ctx), userID)
+	if err != nil {
+		return nil, ErrCannotGetPreferences
+	}
+	return dto.PreferenceEntitiesToDTOs(preferences), nil
+}
+
+func (s *PreferenceServiceImpl) UpdatePreferences(ctx context.Context, userID string, preferencesUpdate []*dto.PreferenceUpdate) ([]*dto.Preference, error) {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	if userID == "" || len(preferencesUpdate) == 0 {
+		return nil, ErrInvalidPreference
+	}
+	preferences := make([]*entities.NotificationPreference, len(preferencesUpdate))
+	for i, preferenceUpdate := range preferencesUpdate {
+		preference := &entities.NotificationPreference{
+			UserID:       userID,
+			TenantID:     auth.TenantIDFromContext(ctx),
+			Category:     preferenceUpdate.Category,
+			DeliveryType: preferenceUpdate.DeliveryType,
+			OptedIn:      preferenceUpdate.OptedIn == nil || *preferenceUpdate.OptedIn,
+		}
+		if preference.Category == "" {
+			preference.Category = entities.PreferenceAny
+		}
+		if preference.DeliveryType == "" {
+			preference.DeliveryType = entities.PreferenceAny
+		}
+		if preferenceUpdate.MutedUntil != nil {
+			mutedUntil := preferenceUpdate.MutedUntil.UTC()
+			preference.MutedUntil = &mutedUntil
+		}
+		preferences[i] = preference
+	}
+	before := s.auditedPreferences(ctx, userID)
+	if err := s.preferenceRepo.UpsertPreferences(ctx, preferences); err != nil {
+		logger.Error("failed to update preferences", slog.Any("error", err))
+		return nil, ErrCannotUpdatePreferences
+	}
+	audit.Record(ctx, "preference.update", "preferences", userID, before, s.auditedPreferences(ctx, userID))
+	return dto.PreferenceEntitiesToDTOs(preferences), nil
+}
+
+func (s *PreferenceServiceImpl) DeletePreference(ctx context.Context, userID, category, deliveryType string) error {
+	if category == "" {
+		category = entities.PreferenceAny
+	}
+	if deliveryType == "" {
+		deliveryType = entities.PreferenceAny
+	}
+	before := s.auditedPreferences(ctx, userID)
+	if err := s.preferenceRepo.DeletePreference(ctx, auth.TenantIDFromContext(ctx), userID, category, deliveryType); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrPreferenceNotFound
+		}
+		return ErrCannotDeletePreference
+	}
+	audit.Record(ctx, "preference.delete", "preferences", userID, before, s.auditedPreferences(ctx, userID))
+	return nil
+}
+
+// auditedPreferences returns every preference of the user for the audit log, nil when
+// the request is not audited.
+func (s *PreferenceServiceImpl) auditedPreferences(ctx context.Context, userID string) []*dto.Preference {
+	if !audit.Enabled(ctx) {
+		return nil
+	}
+	preferences, err := s.preferenceRepo.GetPreferences(ctx, auth.TenantIDFromContext(ctx), userID)
+	if err != nil {
+		return nil
+	}
+	return dto.PreferenceEntitiesToDTOs(preferences)
+}
+
+func (s *PreferenceServiceImpl) GetCategories(ctx context.Context) ([]*dto.Category, error) {
+	categories, err := s.preferenceRepo.GetCategories(ctx)
+	if err != nil {
+		return nil, ErrCannotGetCategories
+	}
+	return dto.CategoryEntitiesToDTOs(categories), nil
+}
+
+func (s *PreferenceServiceImpl) UpdateCategory(ctx context.Context, name string, categoryUpdate *dto.CategoryUpdate) (*dto.Category, error) {
+	if name == "" || name == entities.PreferenceAny {
+		return nil, ErrInvalidCategory
+	}
+	category := &entities.NotificationCategory{
+		Name:         name,
+		Description:  categoryUpdate.Description,
+		Suppressible: categoryUpdate.Suppressible == nil || *categoryUpdate.Suppressible,
+	}
+	var before *dto.Category
+	if audit.Enabled(ctx) {
+		if categories, err := s.preferenceRepo.GetCategories(ctx); err == nil {
+			for _, current := range categories {
+				if current.Name == name {
+					before = dto.CategoryEntityToDTO(current)
+				}
+			}
+		}
+	}
+	if err := s.preferenceRepo.UpsertCategory(ctx, category); err != nil {
+		return nil, ErrCannotUpdateCategory
+	}
+	audit.Record(ctx, "category.update", "category", name, before, dto.CategoryEntityToDTO(category))
+	return dto.CategoryEntityToDTO(category), nil
+}
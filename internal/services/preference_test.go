@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+
+	"notification_system/internal/dto"
+	"notification_system/internal/entities"
+	"notification_system/internal/repositories/mocks"
+)
+
+func TestPreferenceServiceImpl_UpdatePreferences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repomocks.NewMockPreferenceRepository(ctrl)
+	optedOut := false
+	mutedUntil := time.Date(2030, 1, 1, 12, 0, 0, 0, time.FixedZone("UTC+3", 3*60*60))
+
+	mockRepo.
+		EXPECT().
+		UpsertPreferences(gomock.Any(), gomock.Len(2)).
+		DoAndReturn(func(_ context.Context, preferences []*entities.NotificationPreference) error {
+			all, muted := preferences[0], preferences[1]
+			if all.Category != entities.PreferenceAny || all.DeliveryType != "sms" || all.OptedIn {
+				t.Errorf("unexpected opt-out preference %+v", all)
+			}
+			if muted.Category != "marketing" || muted.DeliveryType != entities.PreferenceAny || !muted.OptedIn {
+				t.Errorf("unexpected mute preference %+v", muted)
+			}
+			if muted.MutedUntil == nil || muted.MutedUntil.Location() != time.UTC || !muted.MutedUntil.Equal(mutedUntil) {
+				t.Errorf("muted until = %v, want %v in UTC", muted.MutedUntil, mutedUntil)
+			}
+			return nil
+		})
+
+	s := NewPreferenceServiceImpl(mockRepo)
+	_, err := s.UpdatePreferences(context.Background(), "user-1", []*dto.PreferenceUpdate{
+		{DeliveryType: "sms", OptedIn: &optedOut},
+		{Category: "marketing", MutedUntil: &mutedUntil},
+	})
+	if err != nil {
+		t.Fatalf("UpdatePreferences() error = %v", err)
+	}
+	if _, err := s.UpdatePreferences(context.Background(), "user-1", nil); !errors.Is(err, ErrInvalidPreference) {
+		t.Errorf("UpdatePreferences() error = %v, want %v", err, ErrInvalidPreference)
+	}
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"notification_system/internal/audit"
+	"notification_system/internal/auth"
+	"notification_system/internal/dto"
+	"notification_system/internal/entities"
+	"notification_system/internal/repositories"
+	slogger "notification_system/pkg/logger"
+)
+
+type QuietHoursServiceImpl struct {
+	quietHoursRepo repositories.QuietHoursRepository
+}
+
+func NewQuietHoursServiceImpl(quietHoursRepo repositories.QuietHoursRepository) QuietHoursService {
+	return &QuietHoursServiceImpl{quietHoursRepo: quietHoursRepo}
+}
+
+func (s *QuietHoursServiceImpl) GetQuietHours(ctx context.Context, userID, category string) ([]*dto.QuietHours, error) {
+	quietHours, err := s.quietHoursRepo.GetQuietHours(ctx, auth.TenantIDFromContext(ctx), userID, category)
+	if err != nil {
+		return nil, ErrCannotGetQuietHours
+	}
+	return dto.QuietHoursEntitiesToDTOs(quietHours), nil
+}
+
+func (s *QuietHoursServiceImpl) UpdateQuietHours(ctx context.Context, quietHoursUpdate *dto.QuietHoursUpdate) (*dto.QuietHours, error) {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	start, err := parseMinute(quietHoursUpdate.Start)
+	if err != nil {
+		return nil, ErrInvalidQuietHours
+	}
+	end, err := parseMinute(quietHoursUpdate.End)
+	if err != nil || start == end {
+		return nil, ErrInvalidQuietHours
+	}
+	if err := validateTimeZone(quietHoursUpdate.TimeZone); err != nil {
+		return nil, err
+	}
+	quietHours := &entities.QuietHours{
+		UserID:      quietHoursUpdate.UserID,
+		TenantID:    auth.TenantIDFromContext(ctx),
+		Category:    quietHoursUpdate.Category,
+		StartMinute: start,
+		EndMinute:   end,
+		TimeZone:    quietHoursUpdate.TimeZone,
+	}
+	if quietHours.UserID == "" {
+		quietHours.UserID = entities.PreferenceAny
+	}
+	if quietHours.Category == "" {
+		quietHours.Category = entities.PreferenceAny
+	}
+	before := s.auditedQuietHours(ctx, quietHours.UserID, quietHours.Category)
+	if err := s.quietHoursRepo.UpsertQuietHours(ctx, quietHours); err != nil {
+		logger.Error("failed to update quiet hours", slog.Any("error", err))
+		return nil, ErrCannotUpdateQuietHours
+	}
+	audit.Record(ctx, "quiet_hours.update", "quiet_hours", quietHours.UserID+"/"+quietHours.Category,
+		before, dto.QuietHoursEntityToDTO(quietHours))
+	return dto.QuietHoursEntityToDTO(quietHours), nil
+}
+
+func (s *QuietHoursServiceImpl) DeleteQuietHours(ctx context.Context, userID, category string) error {
+	if userID == "" {
+		userID = entities.PreferenceAny
+	}
+	if category == "" {
+		category = entities.PreferenceAny
+	}
+	before := s.auditedQuietHours(ctx, userID, category)
+	if err := s.quietHoursRepo.DeleteQuietHours(ctx, auth.TenantIDFromContext(ctx), userID, category); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrQuietHoursNotFound
+		}
+		return ErrCannotDeleteQuietHours
+	}
+	audit.Record(ctx, "quiet_hours.delete", "quiet_hours", userID+"/"+category, before, nil)
+	return nil
+}
+
+// auditedQuietHours returns the rule of the user and category for the audit log, nil when
+// the request is not audited or there is no rule.
+func (s *QuietHoursServiceImpl) auditedQuietHours(ctx context.Context, userID, category string) *dto.QuietHours {
+	if !audit.Enabled(ctx) {
+		return nil
+	}
+	rules, err := s.quietHoursRepo.GetQuietHours(ctx, auth.TenantIDFromContext(ctx), userID, category)
+	if err != nil || len(rules) != 1 {
+		return nil
+	}
+	return dto.QuietHoursEntityToDTO(rules[0])
+}
+
+// parseMinute converts a HH:MM local time to minutes since midnight.
+func parseMinute(value string) (int16, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return int16(t.Hour()*60 + t.Minute()), nil
+}
+
+// validateTimeZone accepts an empty zone or an IANA name known to the time zone database.
+func validateTimeZone(timeZone string) error {
+	if timeZone == "" {
+		return nil
+	}
+	if _, err := time.LoadLocation(timeZone); err != nil {
+		return ErrInvalidTimeZone
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+
+	"notification_system/internal/dto"
+	"notification_system/internal/entities"
+	"notification_system/internal/repositories/mocks"
+)
+
+func TestQuietHoursServiceImpl_UpdateQuietHours(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repomocks.NewMockQuietHoursRepository(ctrl)
+
+	mockRepo.
+		EXPECT().
+		UpsertQuietHours(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, quietHours *entities.QuietHours) error {
+			if quietHours.UserID != entities.PreferenceAny || quietHours.Category != "marketing" {
+				t.Errorf("unexpected scope %+v", quietHours)
+			}
+			if quietHours.StartMinute != 22*60 || quietHours.EndMinute != 7*60+30 {
+				t.Errorf("window = %d-%d, want 1320-450", quietHours.StartMinute, quietHours.EndMinute)
+			}
+			return nil
+		})
+
+	s := NewQuietHoursServiceImpl(mockRepo)
+	quietHours, err := s.UpdateQuietHours(context.Background(), &dto.QuietHoursUpdate{
+		Category: "marketing",
+		Start:    "22:00",
+		End:      "07:30",
+		TimeZone: "UTC",
+	})
+	if err != nil {
+		t.Fatalf("UpdateQuietHours() error = %v", err)
+	}
+	if quietHours.Start != "22:00" || quietHours.End != "07:30" {
+		t.Errorf("window = %s-%s, want 22:00-07:30", quietHours.Start, quietHours.End)
+	}
+
+	invalid := []*dto.QuietHoursUpdate{
+		{Start: "22:00", End: "22:00"},
+		{Start: "25:00", End: "07:00"},
+		{Start: "22:00", End: "07:00", TimeZone: "Mars/Olympus"},
+	}
+	for _, quietHoursUpdate := range invalid {
+		if _, err := s.UpdateQuietHours(context.Background(), quietHoursUpdate); err == nil ||
+			!(errors.Is(err, ErrInvalidQuietHours) || errors.Is(err, ErrInvalidTimeZone)) {
+			t.Errorf("UpdateQuietHours(%+v) error = %v, want validation error", quietHoursUpdate, err)
+		}
+	}
+}
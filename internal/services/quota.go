@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/audit"
+	"notification_system/internal/dto"
+	"notification_system/internal/entities"
+	"notification_system/internal/repositories"
+	slogger "notification_system/pkg/logger"
+)
+
+// QuotaExceededError tells which quota a batch of notifications ran into, it matches ErrQuotaExceeded.
+type QuotaExceededError struct {
+	*entities.QuotaExceeded
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("%s: %s quota of %d %s notifications reached, %d used",
+		ErrQuotaExceeded, quotaPeriodName(e.Period), e.Limit, e.DeliveryType, e.Used)
+}
+
+func (e *QuotaExceededError) Unwrap() error {
+	return ErrQuotaExceeded
+}
+
+func quotaPeriodName(period string) string {
+	if period == entities.QuotaPeriodMonth {
+		return "monthly"
+	}
+	return "daily"
+}
+
+type QuotaServiceImpl struct {
+	quotaRepo repositories.QuotaRepository
+}
+
+func NewQuotaServiceImpl(quotaRepo repositories.QuotaRepository) QuotaService {
+	return &QuotaServiceImpl{quotaRepo: quotaRepo}
+}
+
+func (s *QuotaServiceImpl) GetQuotas(ctx context.Context, clientID uuid.UUID) ([]*dto.ClientQuota, error) {
+	quotas, err := s.quotaRepo.GetClientQuotas(ctx, clientID)
+	if err != nil {
+		return nil, ErrCannotGetQuotas
+	}
+	return dto.ClientQuotaEntitiesToDTOs(quotas), nil
+}
+
+func (s *QuotaServiceImpl) UpdateQuota(ctx context.Context, clientID uuid.UUID, deliveryType string, quotaUpdate *dto.ClientQuotaUpdate) (*dto.ClientQuota, error) {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	if deliveryType == "" || deliveryType == entities.DeliveryTypeChain || deliveryType == entities.PreferenceAny {
+		return nil, ErrInvalidQuota
+	}
+	if quotaUpdate.DailyLimit == nil && quotaUpdate.MonthlyLimit == nil {
+		return nil, ErrInvalidQuota
+	}
+	for _, limit := range []*int32{quotaUpdate.DailyLimit, quotaUpdate.MonthlyLimit} {
+		if limit != nil && *limit < 0 {
+			return nil, ErrInvalidQuota
+		}
+	}
+	quota := &entities.ClientQuota{
+		ClientID:     clientID,
+		DeliveryType: deliveryType,
+		DailyLimit:   quotaUpdate.DailyLimit,
+		MonthlyLimit: quotaUpdate.MonthlyLimit,
+	}
+	before := s.auditedQuota(ctx, clientID, deliveryType)
+	if err := s.quotaRepo.UpsertClientQuota(ctx, quota); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrClientNotFound
+		}
+		logger.Error("failed to update quota", slog.Any("error", err))
+		return nil, ErrCannotUpdateQuota
+	}
+	logger.Info("quota updated",
+		slog.String("client_id", clientID.String()),
+		slog.String("delivery_type", deliveryType),
+	)
+	audit.Record(ctx, "quota.update", "quota", clientID.String()+"/"+deliveryType, before, dto.ClientQuotaEntityToDTO(quota))
+	return dto.ClientQuotaEntityToDTO(quota), nil
+}
+
+func (s *QuotaServiceImpl) DeleteQuota(ctx context.Context, clientID uuid.UUID, deliveryType string) error {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	before := s.auditedQuota(ctx, clientID, deliveryType)
+	if err := s.quotaRepo.DeleteClientQuota(ctx, clientID, deliveryType); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrQuotaNotFound
+		}
+		logger.Error("failed to delete quota", slog.Any("error", err))
+		return ErrCannotDeleteQuota
+	}
+	audit.Record(ctx, "quota.delete", "quota", clientID.String()+"/"+deliveryType, before, nil)
+	return nil
+}
+
+// auditedQuota returns the quota of the client on the channel for the audit log, nil when
+// the request is not audited or there is no quota.
+func (s *QuotaServiceImpl) auditedQuota(ctx context.Context, clientID uuid.UUID, deliveryType string) *dto.ClientQuota {
+	if !audit.Enabled(ctx) {
+		return nil
+	}
+	quotas, err := s.quotaRepo.GetClientQuotas(ctx, clientID)
+	if err != nil {
+		return nil
+	}
+	for _, quota := range quotas {
+		if quota.DeliveryType == deliveryType {
+			return dto.ClientQuotaEntityToDTO(quota)
+		}
+	}
+	return nil
+}
+
+// GetUsage returns the usage of the client in the current UTC day and month for every
+// channel it used or has a quota on.
+func (s *QuotaServiceImpl) GetUsage(ctx context.Context, clientID uuid.UUID) (*dto.Usage, error) {
+	now := time.Now()
+	quotas, err := s.quotaRepo.GetClientQuotas(ctx, clientID)
+	if err != nil {
+		return nil, ErrCannotGetUsage
+	}
+	counters, err := s.quotaRepo.GetClientUsage(ctx, clientID, now)
+	if err != nil {
+		return nil, ErrCannotGetUsage
+	}
+
+	channels := make(map[string]*dto.ChannelUsage)
+	channel := func(deliveryType string) *dto.ChannelUsage {
+		if usage, ok := channels[deliveryType]; ok {
+			return usage
+		}
+		usage := &dto.ChannelUsage{
+			DeliveryType: deliveryType,
+			Daily:        dto.PeriodUsage{ResetsAt: entities.QuotaPeriodEnd(entities.QuotaPeriodDay, now)},
+			Monthly:      dto.PeriodUsage{ResetsAt: entities.QuotaPeriodEnd(entities.QuotaPeriodMonth, now)},
+		}
+		channels[deliveryType] = usage
+		return usage
+	}
+	for _, counter := range counters {
+		usage := channel(counter.DeliveryType)
+		if counter.Period == entities.QuotaPeriodMonth {
+			usage.Monthly.Used = counter.Count
+		} else {
+			usage.Daily.Used = counter.Count
+		}
+	}
+	for _, quota := range quotas {
+		usage := channel(quota.DeliveryType)
+		usage.Daily.Limit = quota.DailyLimit
+		usage.Monthly.Limit = quota.MonthlyLimit
+	}
+
+	response := &dto.Usage{ClientID: clientID, Channels: make([]*dto.ChannelUsage, 0, len(channels))}
+	for _, usage := range channels {
+		for _, period := range []*dto.PeriodUsage{&usage.Daily, &usage.Monthly} {
+			if period.Limit != nil {
+				remaining := max(*period.Limit-period.Used, 0)
+				period.Remaining = &remaining
+			}
+		}
+		response.Channels = append(response.Channels, usage)
+	}
+	sort.Slice(response.Channels, func(i, j int) bool {
+		return response.Channels[i].DeliveryType < response.Channels[j].DeliveryType
+	})
+	return response, nil
+}
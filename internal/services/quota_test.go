@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"notification_system/internal/auth"
+	"notification_system/internal/dto"
+	"notification_system/internal/entities"
+	"notification_system/internal/repositories/mocks"
+)
+
+func TestQuotaServiceImpl_GetUsage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repomocks.NewMockQuotaRepository(ctrl)
+	clientID := uuid.New()
+	dailyLimit, monthlyLimit := int32(10), int32(100)
+
+	mockRepo.
+		EXPECT().
+		GetClientQuotas(gomock.Any(), clientID).
+		Return([]*entities.ClientQuota{
+			{ClientID: clientID, DeliveryType: "email", DailyLimit: &dailyLimit, MonthlyLimit: &monthlyLimit},
+		}, nil)
+	mockRepo.
+		EXPECT().
+		GetClientUsage(gomock.Any(), clientID, gomock.Any()).
+		Return([]*entities.ClientUsage{
+			{DeliveryType: "email", Period: entities.QuotaPeriodDay, Count: 12},
+			{DeliveryType: "email", Period: entities.QuotaPeriodMonth, Count: 40},
+			{DeliveryType: "sms", Period: entities.QuotaPeriodDay, Count: 3},
+		}, nil)
+
+	s := NewQuotaServiceImpl(mockRepo)
+	usage, err := s.GetUsage(context.Background(), clientID)
+	if err != nil {
+		t.Fatalf("GetUsage() error = %v", err)
+	}
+	if len(usage.Channels) != 2 || usage.Channels[0].DeliveryType != "email" || usage.Channels[1].DeliveryType != "sms" {
+		t.Fatalf("unexpected channels %+v", usage.Channels)
+	}
+	email := usage.Channels[0]
+	if email.Daily.Used != 12 || email.Daily.Remaining == nil || *email.Daily.Remaining != 0 {
+		t.Errorf("daily email usage = %+v, want 12 used and 0 remaining", email.Daily)
+	}
+	if email.Monthly.Used != 40 || email.Monthly.Remaining == nil || *email.Monthly.Remaining != 60 {
+		t.Errorf("monthly email usage = %+v, want 40 used and 60 remaining", email.Monthly)
+	}
+	sms := usage.Channels[1]
+	if sms.Daily.Used != 3 || sms.Daily.Limit != nil || sms.Daily.Remaining != nil {
+		t.Errorf("daily sms usage = %+v, want 3 used without a limit", sms.Daily)
+	}
+	if !sms.Daily.ResetsAt.After(time.Now()) {
+		t.Errorf("daily usage resets at %v, want a future time", sms.Daily.ResetsAt)
+	}
+}
+
+func TestQuotaServiceImpl_UpdateQuota(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repomocks.NewMockQuotaRepository(ctrl)
+	clientID := uuid.New()
+	limit, negative := int32(10), int32(-1)
+
+	mockRepo.
+		EXPECT().
+		UpsertClientQuota(gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	s := NewQuotaServiceImpl(mockRepo)
+	if _, err := s.UpdateQuota(context.Background(), clientID, "email", &dto.ClientQuotaUpdate{DailyLimit: &limit}); err != nil {
+		t.Fatalf("UpdateQuota() error = %v", err)
+	}
+
+	invalid := []struct {
+		deliveryType string
+		quotaUpdate  *dto.ClientQuotaUpdate
+	}{
+		{"", &dto.ClientQuotaUpdate{DailyLimit: &limit}},
+		{entities.DeliveryTypeChain, &dto.ClientQuotaUpdate{DailyLimit: &limit}},
+		{"email", &dto.ClientQuotaUpdate{}},
+		{"email", &dto.ClientQuotaUpdate{MonthlyLimit: &negative}},
+	}
+	for _, tt := range invalid {
+		if _, err := s.UpdateQuota(context.Background(), clientID, tt.deliveryType, tt.quotaUpdate); !errors.Is(err, ErrInvalidQuota) {
+			t.Errorf("UpdateQuota(%q, %+v) error = %v, want %v", tt.deliveryType, tt.quotaUpdate, err, ErrInvalidQuota)
+		}
+	}
+}
+
+func TestNotificationServiceImpl_CreateNotifications_Quota(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repomocks.NewMockNotificationRepository(ctrl)
+	mockQuotaRepo := repomocks.NewMockQuotaRepository(ctrl)
+	clientID := uuid.New()
+	ctx := context.WithValue(context.Background(), auth.ClientIDKey, clientID)
+	s := &NotificationServiceImpl{notificationRepo: mockRepo, quotaRepo: mockQuotaRepo}
+	notifications := []*dto.NotificationCreate{
+		{DeliveryType: "email", Recipient: "a@example.com", Content: "a"},
+		{DeliveryType: "email", Recipient: "b@example.com", Content: "b"},
+		{DeliveryType: "sms", Recipient: "+10000000000", Content: "c"},
+	}
+	wantCounts := map[string]int32{"email": 2, "sms": 1}
+
+	exceeded := &entities.QuotaExceeded{
+		DeliveryType: "email",
+		Period:       entities.QuotaPeriodDay,
+		Limit:        1,
+		ResetAt:      time.Now().Add(time.Hour),
+	}
+	mockQuotaRepo.
+		EXPECT().
+		ConsumeQuota(gomock.Any(), clientID, wantCounts, gomock.Any()).
+		Return(exceeded, nil)
+	_, err := s.CreateNotifications(ctx, notifications)
+	var quotaErr *QuotaExceededError
+	if !errors.As(err, &quotaErr) || !errors.Is(err, ErrQuotaExceeded) || quotaErr.DeliveryType != "email" {
+		t.Fatalf("CreateNotifications() error = %v, want the email quota exceeded", err)
+	}
+
+	mockQuotaRepo.
+		EXPECT().
+		ConsumeQuota(gomock.Any(), clientID, wantCounts, gomock.Any()).
+		Return(nil, nil)
+	mockRepo.
+		EXPECT().
+		CreateNotificationBatch(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(errors.New("connection refused"))
+	mockQuotaRepo.
+		EXPECT().
+		ReleaseQuota(gomock.Any(), clientID, wantCounts, gomock.Any()).
+		Return(nil)
+	if _, err := s.CreateNotifications(ctx, notifications); !errors.Is(err, ErrCannotCreateNotifications) {
+		t.Fatalf("CreateNotifications() error = %v, want %v", err, ErrCannotCreateNotifications)
+	}
+}
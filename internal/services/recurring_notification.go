@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/audit"
+	"notification_system/internal/auth"
+	"notification_system/internal/dto"
+	"notification_system/internal/entities"
+	"notification_system/internal/recurring"
+	"notification_system/internal/repositories"
+	slogger "notification_system/pkg/logger"
+)
+
+const maxRecurringRecipients = 1000
+
+type RecurringNotificationServiceImpl struct {
+	recurringRepo repositories.RecurringNotificationRepository
+}
+
+func NewRecurringNotificationServiceImpl(recurringRepo repositories.RecurringNotificationRepository) RecurringNotificationService {
+	return &RecurringNotificationServiceImpl{recurringRepo: recurringRepo}
+}
+
+func (s *RecurringNotificationServiceImpl) CreateRecurringNotification(ctx context.Context, recurringCreate *dto.RecurringNotificationCreate) (*dto.RecurringNotification, error) {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	entity, err := recurringNotificationEntity(recurringCreate, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	entity.ClientID, entity.TenantID = callerOwner(ctx)
+	if err := s.recurringRepo.CreateRecurringNotification(ctx, entity); err != nil {
+		logger.Error("failed to create recurring notification", slog.Any("error", err))
+		return nil, ErrCannotCreateRecurringNotification
+	}
+	audit.Record(ctx, "recurring_notification.create", "recurring_notification", entity.ID.String(),
+		nil, dto.RecurringNotificationEntityToDTO(entity))
+	return dto.RecurringNotificationEntityToDTO(entity), nil
+}
+
+func (s *RecurringNotificationServiceImpl) GetRecurringNotifications(ctx context.Context, limit, offset uint) ([]*dto.RecurringNotification, error) {
+	clientID, tenantID := callerOwner(ctx)
+	recurringNotifications, err := s.recurringRepo.GetRecurringNotifications(ctx, tenantID, clientID, limit, offset)
+	if err != nil {
+		return nil, ErrCannotGetRecurringNotifications
+	}
+	return dto.RecurringNotificationEntitiesToDTOs(recurringNotifications), nil
+}
+
+func (s *RecurringNotificationServiceImpl) GetRecurringNotification(ctx context.Context, id uuid.UUID) (*dto.RecurringNotification, error) {
+	entity, err := s.recurringRepo.GetRecurringNotification(ctx, id, auth.TenantIDFromContext(ctx))
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrRecurringNotificationNotFound
+		}
+		return nil, ErrCannotGetRecurringNotifications
+	}
+	if !callerOwns(ctx, entity.ClientID, entity.TenantID) {
+		return nil, ErrRecurringNotificationNotFound
+	}
+	return dto.RecurringNotificationEntityToDTO(entity), nil
+}
+
+// UpdateRecurringNotification replaces the definition and schedules the next run
+// from now, occurrences missed while the notification was disabled are not fired.
+func (s *RecurringNotificationServiceImpl) UpdateRecurringNotification(ctx context.Context, id uuid.UUID, recurringUpdate *dto.RecurringNotificationCreate) (*dto.RecurringNotification, error) {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	current, err := s.recurringRepo.GetRecurringNotification(ctx, id, auth.TenantIDFromContext(ctx))
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrRecurringNotificationNotFound
+		}
+		return nil, ErrCannotUpdateRecurringNotification
+	}
+	if !callerOwns(ctx, current.ClientID, current.TenantID) {
+		return nil, ErrRecurringNotificationNotFound
+	}
+	if recurringUpdate.StartsAt == nil {
+		recurringUpdate.StartsAt = &current.StartsAt
+	}
+	entity, err := recurringNotificationEntity(recurringUpdate, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	entity.ID = id
+	if err := s.recurringRepo.UpdateRecurringNotification(ctx, entity); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrRecurringNotificationNotFound
+		}
+		logger.Error("failed to update recurring notification", slog.Any("error", err))
+		return nil, ErrCannotUpdateRecurringNotification
+	}
+	audit.Record(ctx, "recurring_notification.update", "recurring_notification", id.String(),
+		dto.RecurringNotificationEntityToDTO(current), dto.RecurringNotificationEntityToDTO(entity))
+	return dto.RecurringNotificationEntityToDTO(entity), nil
+}
+
+func (s *RecurringNotificationServiceImpl) DeleteRecurringNotification(ctx context.Context, id uuid.UUID) error {
+	current, err := s.recurringRepo.GetRecurringNotification(ctx, id, auth.TenantIDFromContext(ctx))
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrRecurringNotificationNotFound
+		}
+		return ErrCannotDeleteRecurringNotification
+	}
+	if !callerOwns(ctx, current.ClientID, current.TenantID) {
+		return ErrRecurringNotificationNotFound
+	}
+	if err := s.recurringRepo.DeleteRecurringNotification(ctx, id); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrRecurringNotificationNotFound
+		}
+		return ErrCannotDeleteRecurringNotification
+	}
+	audit.Record(ctx, "recurring_notification.delete", "recurring_notification", id.String(),
+		dto.RecurringNotificationEntityToDTO(current), nil)
+	return nil
+}
+
+// recurringNotificationEntity validates the definition and computes its first run after now.
+func recurringNotificationEntity(recurringCreate *dto.RecurringNotificationCreate, now time.Time) (*entities.RecurringNotification, error) {
+	entity := &entities.RecurringNotification{
+		Name:         recurringCreate.Name,
+		ScheduleType: recurringCreate.ScheduleType,
+		Schedule:     recurringCreate.Schedule,
+		TimeZone:     recurringCreate.TimeZone,
+		StartsAt:     now.UTC().Truncate(time.Second),
+		DeliveryType: recurringCreate.DeliveryType,
+		Recipients:   recurringCreate.Recipients,
+		UserIDs:      recurringCreate.UserIDs,
+		Template:     recurringCreate.Template,
+		Variables:    recurringCreate.Variables,
+		Priority:     recurringCreate.Priority,
+		Enabled:      true,
+	}
+	if entity.Name == "" || entity.Template == "" || entity.DeliveryType == "" ||
+		entity.DeliveryType == entities.DeliveryTypeChain {
+		return nil, ErrInvalidRecurringNotification
+	}
+	if entity.Recipients == nil {
+		entity.Recipients = []string{}
+	}
+	if entity.UserIDs == nil {
+		entity.UserIDs = []string{}
+	}
+	count := len(entity.Recipients) + len(entity.UserIDs)
+	if count == 0 || count > maxRecurringRecipients {
+		return nil, ErrInvalidRecurringNotification
+	}
+	if entity.Variables == nil {
+		entity.Variables = map[string]any{}
+	}
+	switch entity.Priority {
+	case "":
+		entity.Priority = entities.PriorityNormal
+	case entities.PriorityLow, entities.PriorityNormal, entities.PriorityHigh, entities.PriorityCritical:
+	default:
+		return nil, ErrInvalidPriority
+	}
+	if recurringCreate.Category != "" {
+		if recurringCreate.Category == entities.PreferenceAny {
+			return nil, ErrInvalidCategory
+		}
+		entity.Category = &recurringCreate.Category
+	}
+	if recurringCreate.Enabled != nil {
+		entity.Enabled = *recurringCreate.Enabled
+	}
+	if entity.ScheduleType == "" {
+		entity.ScheduleType = entities.ScheduleTypeCron
+	}
+	if entity.TimeZone == "" {
+		entity.TimeZone = "UTC"
+	}
+	if err := validateTimeZone(entity.TimeZone); err != nil {
+		return nil, err
+	}
+	if recurringCreate.StartsAt != nil {
+		entity.StartsAt = recurringCreate.StartsAt.UTC().Truncate(time.Second)
+	}
+	if recurringCreate.EndsAt != nil {
+		endsAt := recurringCreate.EndsAt.UTC()
+		if !endsAt.After(entity.StartsAt) {
+			return nil, ErrInvalidSchedule
+		}
+		entity.EndsAt = &endsAt
+	}
+
+	// an occurrence at the current second still fires, like the start of an RRULE starting now
+	nextRunAt, err := recurring.NextRun(entity, now.Truncate(time.Second).Add(-time.Nanosecond))
+	if err != nil || nextRunAt == nil {
+		// a schedule without a future occurrence would never fire
+		return nil, ErrInvalidSchedule
+	}
+	entity.NextRunAt = nextRunAt
+	// render once so that a template referring to a missing variable is rejected now
+	notifications, err := recurring.Notifications(entity, *nextRunAt)
+	if err != nil {
+		return nil, ErrInvalidRecurringNotification
+	}
+	for _, notification := range notifications {
+		if reservedValue(notification.Recipient, notification.Content) {
+			return nil, ErrReservedValue
+		}
+	}
+	return entity, nil
+}
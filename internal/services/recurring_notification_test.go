@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+
+	"notification_system/internal/dto"
+	"notification_system/internal/entities"
+	"notification_system/internal/repositories/mocks"
+)
+
+func TestRecurringNotificationServiceImpl_CreateRecurringNotification(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repomocks.NewMockRecurringNotificationRepository(ctrl)
+
+	mockRepo.
+		EXPECT().
+		CreateRecurringNotification(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, recurring *entities.RecurringNotification) error {
+			if recurring.ScheduleType != entities.ScheduleTypeCron || recurring.Priority != entities.PriorityNormal {
+				t.Errorf("unexpected defaults %+v", recurring)
+			}
+			if recurring.NextRunAt == nil {
+				t.Fatal("next run is not set")
+			}
+			loc, _ := time.LoadLocation("Europe/Berlin")
+			if next := recurring.NextRunAt.In(loc); next.Hour() != 9 || next.Minute() != 0 || next.Location() == time.UTC {
+				t.Errorf("next run = %v, want 09:00 in Europe/Berlin", next)
+			}
+			return nil
+		})
+
+	s := NewRecurringNotificationServiceImpl(mockRepo)
+	_, err := s.CreateRecurringNotification(context.Background(), &dto.RecurringNotificationCreate{
+		Name:         "standup",
+		Schedule:     "0 9 * * mon-fri",
+		TimeZone:     "Europe/Berlin",
+		DeliveryType: "email",
+		Recipients:   []string{"team@example.com"},
+		Template:     "Standup of {{.Variables.team}} #{{.Sequence}}",
+		Variables:    map[string]any{"team": "core"},
+	})
+	if err != nil {
+		t.Fatalf("CreateRecurringNotification() error = %v", err)
+	}
+
+	past := time.Now().Add(-time.Hour)
+	valid := func() dto.RecurringNotificationCreate {
+		return dto.RecurringNotificationCreate{
+			Name:         "standup",
+			Schedule:     "@daily",
+			DeliveryType: "email",
+			Recipients:   []string{"team@example.com"},
+			Template:     "Standup",
+		}
+	}
+	tests := []struct {
+		modify func(*dto.RecurringNotificationCreate)
+		want   error
+	}{
+		{func(r *dto.RecurringNotificationCreate) { r.Recipients = nil }, ErrInvalidRecurringNotification},
+		{func(r *dto.RecurringNotificationCreate) { r.Template = "{{.Variables.missing}}" }, ErrInvalidRecurringNotification},
+		{func(r *dto.RecurringNotificationCreate) { r.DeliveryType = entities.DeliveryTypeChain }, ErrInvalidRecurringNotification},
+		{func(r *dto.RecurringNotificationCreate) { r.Schedule = "0 25 * * *" }, ErrInvalidSchedule},
+		{func(r *dto.RecurringNotificationCreate) {
+			r.ScheduleType, r.Schedule = entities.ScheduleTypeRRule, "FREQ=DAILY;COUNT=1"
+		}, nil},
+		{func(r *dto.RecurringNotificationCreate) {
+			r.ScheduleType, r.Schedule, r.StartsAt = entities.ScheduleTypeRRule, "FREQ=DAILY;COUNT=1", &past
+		}, ErrInvalidSchedule},
+		{func(r *dto.RecurringNotificationCreate) { r.EndsAt = &past }, ErrInvalidSchedule},
+		{func(r *dto.RecurringNotificationCreate) { r.TimeZone = "Mars/Olympus" }, ErrInvalidTimeZone},
+		{func(r *dto.RecurringNotificationCreate) { r.Priority = "urgent" }, ErrInvalidPriority},
+	}
+	mockRepo.EXPECT().CreateRecurringNotification(gomock.Any(), gomock.Any()).Return(nil)
+	for _, tt := range tests {
+		recurringCreate := valid()
+		tt.modify(&recurringCreate)
+		_, err := s.CreateRecurringNotification(context.Background(), &recurringCreate)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("CreateRecurringNotification(%+v) error = %v, want %v", recurringCreate, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+
+	"notification_system/internal/audit"
+	"notification_system/internal/auth"
+	"notification_system/internal/dto"
+	"notification_system/internal/entities"
+	"notification_system/internal/repositories"
+	slogger "notification_system/pkg/logger"
+)
+
+// RetentionServiceImpl manages the retention policies of the tenant of the caller, the
+// operator manages the ones that apply to the tenants without their own.
+type RetentionServiceImpl struct {
+	retentionRepo repositories.RetentionRepository
+}
+
+func NewRetentionServiceImpl(retentionRepo repositories.RetentionRepository) RetentionService {
+	return &RetentionServiceImpl{retentionRepo: retentionRepo}
+}
+
+func (s *RetentionServiceImpl) GetRetentionPolicies(ctx context.Context) ([]*dto.RetentionPolicy, error) {
+	policies, err := s.retentionRepo.GetRetentionPolicies(ctx, auth.TenantIDFromContext(ctx))
+	if err != nil {
+		return nil, ErrCannotGetRetentionPolicies
+	}
+	return dto.RetentionPolicyEntitiesToDTOs(policies), nil
+}
+
+func (s *RetentionServiceImpl) UpdateRetentionPolicy(ctx context.Context, status string, policyUpdate *dto.RetentionPolicyUpdate) (*dto.RetentionPolicy, error) {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	if !slices.Contains(entities.RetentionStatuses, status) || policyUpdate.RetentionDays <= 0 {
+		return nil, ErrInvalidRetentionPolicy
+	}
+	policy := &entities.RetentionPolicy{
+		TenantID:      auth.TenantIDFromContext(ctx),
+		Status:        status,
+		RetentionDays: policyUpdate.RetentionDays,
+	}
+	before := s.auditedPolicy(ctx, status)
+	if err := s.retentionRepo.UpsertRetentionPolicy(ctx, policy); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrTenantNotFound
+		}
+		logger.Error("failed to update retention policy", slog.Any("error", err))
+		return nil, ErrCannotUpdateRetentionPolicy
+	}
+	logger.Info("retention policy updated",
+		slog.String("status", status),
+		slog.Int("retention_days", int(policy.RetentionDays)),
+	)
+	audit.Record(ctx, "retention_policy.update", "retention_policy", status, before, dto.RetentionPolicyEntityToDTO(policy))
+	return dto.RetentionPolicyEntityToDTO(policy), nil
+}
+
+func (s *RetentionServiceImpl) DeleteRetentionPolicy(ctx context.Context, status string) error {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	before := s.auditedPolicy(ctx, status)
+	if err := s.retentionRepo.DeleteRetentionPolicy(ctx, auth.TenantIDFromContext(ctx), status); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrRetentionPolicyNotFound
+		}
+		logger.Error("failed to delete retention policy", slog.Any("error", err))
+		return ErrCannotDeleteRetentionPolicy
+	}
+	audit.Record(ctx, "retention_policy.delete", "retention_policy", status, before, nil)
+	return nil
+}
+
+// auditedPolicy returns the policy of the status for the audit log, nil when the request
+// is not audited or there is no policy.
+func (s *RetentionServiceImpl) auditedPolicy(ctx context.Context, status string) *dto.RetentionPolicy {
+	if !audit.Enabled(ctx) {
+		return nil
+	}
+	policies, err := s.retentionRepo.GetRetentionPolicies(ctx, auth.TenantIDFromContext(ctx))
+	if err != nil {
+		return nil
+	}
+	for _, policy := range policies {
+		if policy.Status == status {
+			return dto.RetentionPolicyEntityToDTO(policy)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"notification_system/internal/auth"
+	"notification_system/internal/dto"
+	"notification_system/internal/entities"
+	"notification_system/internal/repositories"
+	"notification_system/internal/repositories/mocks"
+)
+
+func TestRetentionServiceImpl_UpdateRetentionPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repomocks.NewMockRetentionRepository(ctrl)
+	tenantID := uuid.New()
+	ctx := context.WithValue(context.Background(), auth.TenantIDKey, tenantID)
+
+	mockRepo.
+		EXPECT().
+		UpsertRetentionPolicy(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, policy *entities.RetentionPolicy) error {
+			if policy.TenantID == nil || *policy.TenantID != tenantID {
+				t.Errorf("TenantID = %v, want the tenant of the caller", policy.TenantID)
+			}
+			if policy.Status != entities.StatusDelivered || policy.RetentionDays != 30 {
+				t.Errorf("unexpected policy %+v", policy)
+			}
+			return nil
+		})
+
+	s := NewRetentionServiceImpl(mockRepo)
+	if _, err := s.UpdateRetentionPolicy(ctx, entities.StatusDelivered, &dto.RetentionPolicyUpdate{RetentionDays: 30}); err != nil {
+		t.Fatalf("UpdateRetentionPolicy() error = %v", err)
+	}
+
+	invalid := []struct {
+		status string
+		days   int32
+	}{
+		{entities.StatusPending, 30},
+		{entities.StatusDigested, 30},
+		{"", 30},
+		{entities.StatusFailed, 0},
+		{entities.StatusFailed, -1},
+	}
+	for _, tt := range invalid {
+		if _, err := s.UpdateRetentionPolicy(ctx, tt.status, &dto.RetentionPolicyUpdate{RetentionDays: tt.days}); !errors.Is(err, ErrInvalidRetentionPolicy) {
+			t.Errorf("UpdateRetentionPolicy(%q, %d) error = %v, want ErrInvalidRetentionPolicy", tt.status, tt.days, err)
+		}
+	}
+}
+
+func TestRetentionServiceImpl_DeleteRetentionPolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repomocks.NewMockRetentionRepository(ctrl)
+
+	mockRepo.
+		EXPECT().
+		DeleteRetentionPolicy(gomock.Any(), (*uuid.UUID)(nil), entities.StatusFailed).
+		Return(repositories.ErrNotFound)
+
+	err := NewRetentionServiceImpl(mockRepo).DeleteRetentionPolicy(context.Background(), entities.StatusFailed)
+	if !errors.Is(err, ErrRetentionPolicyNotFound) {
+		t.Errorf("DeleteRetentionPolicy() error = %v, want ErrRetentionPolicyNotFound", err)
+	}
+}
@@ -10,4 +10,152 @@ var (
 	ErrCannotCreateNotifications     = errors.New("cannot create notifications")
 	ErrTooManyRequestedNotifications = errors.New("too many requested notifications")
 	ErrTooManyNotificationsToCreate  = errors.New("too many notifications to create")
+	ErrInvalidPriority               = errors.New("invalid priority")
+	ErrInvalidChannels               = errors.New("invalid notification channels")
+	ErrInvalidCategory               = errors.New("invalid category")
+	ErrReservedValue                 = errors.New("recipient or content starts with the reserved prefix enc:v1:")
+
+	ErrWebPushNotConfigured            = errors.New("web push is not configured")
+	ErrInvalidWebPushSubscription      = errors.New("invalid web push subscription")
+	ErrWebPushSubscriptionNotFound     = errors.New("web push subscription not found")
+	ErrCannotCreateWebPushSubscription = errors.New("cannot create web push subscription")
+	ErrCannotGetWebPushSubscriptions   = errors.New("cannot get web push subscriptions")
+	ErrCannotDeleteWebPushSubscription = errors.New("cannot delete web push subscription")
+
+	ErrInvalidContact              = errors.New("invalid contact")
+	ErrContactNotFound             = errors.New("contact not found")
+	ErrContactAlreadyExists        = errors.New("contact already exists")
+	ErrCannotCreateContact         = errors.New("cannot create contact")
+	ErrCannotGetContact            = errors.New("cannot get contact")
+	ErrCannotUpdateContact         = errors.New("cannot update contact")
+	ErrCannotDeleteContact         = errors.New("cannot delete contact")
+	ErrInvalidContactAddress       = errors.New("invalid contact address")
+	ErrContactAddressNotFound      = errors.New("contact address not found")
+	ErrContactAddressAlreadyExists = errors.New("contact address already exists")
+	ErrCannotCreateContactAddress  = errors.New("cannot create contact address")
+	ErrCannotUpdateContactAddress  = errors.New("cannot update contact address")
+	ErrCannotDeleteContactAddress  = errors.New("cannot delete contact address")
+	ErrCannotSendVerificationCode  = errors.New("cannot send verification code")
+	ErrInvalidVerificationCode     = errors.New("invalid or expired verification code")
+	ErrCannotVerifyContactAddress  = errors.New("cannot verify contact address")
+
+	ErrInvalidPreference       = errors.New("invalid preference")
+	ErrPreferenceNotFound      = errors.New("preference not found")
+	ErrCannotGetPreferences    = errors.New("cannot get preferences")
+	ErrCannotUpdatePreferences = errors.New("cannot update preferences")
+	ErrCannotDeletePreference  = errors.New("cannot delete preference")
+	ErrCannotGetCategories     = errors.New("cannot get categories")
+	ErrCannotUpdateCategory    = errors.New("cannot update category")
+
+	ErrUnsubscribeNotConfigured = errors.New("unsubscribe links are not configured")
+	ErrInvalidUnsubscribeToken  = errors.New("invalid unsubscribe link")
+	ErrUnsubscribeTokenExpired  = errors.New("unsubscribe link expired")
+	ErrCannotUnsubscribe        = errors.New("cannot unsubscribe")
+
+	ErrInvalidSuppression      = errors.New("invalid suppression")
+	ErrTooManySuppressions     = errors.New("too many suppressions")
+	ErrSuppressionNotFound     = errors.New("suppression not found")
+	ErrCannotCreateSuppression = errors.New("cannot create suppression")
+	ErrCannotDeleteSuppression = errors.New("cannot delete suppression")
+	ErrCannotGetSuppressions   = errors.New("cannot get suppressions")
+
+	ErrInvalidBounce       = errors.New("invalid delivery status notification")
+	ErrCannotProcessBounce = errors.New("cannot process bounce")
+
+	ErrInvalidQuietHours      = errors.New("invalid quiet hours")
+	ErrInvalidTimeZone        = errors.New("invalid time zone")
+	ErrQuietHoursNotFound     = errors.New("quiet hours not found")
+	ErrCannotGetQuietHours    = errors.New("cannot get quiet hours")
+	ErrCannotUpdateQuietHours = errors.New("cannot update quiet hours")
+	ErrCannotDeleteQuietHours = errors.New("cannot delete quiet hours")
+
+	ErrInvalidFrequencyCap      = errors.New("invalid frequency cap")
+	ErrFrequencyCapNotFound     = errors.New("frequency cap not found")
+	ErrCannotGetFrequencyCaps   = errors.New("cannot get frequency caps")
+	ErrCannotUpdateFrequencyCap = errors.New("cannot update frequency cap")
+	ErrCannotDeleteFrequencyCap = errors.New("cannot delete frequency cap")
+
+	ErrInvalidDigest              = errors.New("invalid digest")
+	ErrInvalidDigestTemplate      = errors.New("invalid digest template")
+	ErrDigestTemplateNotFound     = errors.New("digest template not found")
+	ErrCannotGetDigestTemplates   = errors.New("cannot get digest templates")
+	ErrCannotUpdateDigestTemplate = errors.New("cannot update digest template")
+	ErrCannotDeleteDigestTemplate = errors.New("cannot delete digest template")
+
+	ErrInvalidRecurringNotification      = errors.New("invalid recurring notification")
+	ErrInvalidSchedule                   = errors.New("invalid schedule")
+	ErrRecurringNotificationNotFound     = errors.New("recurring notification not found")
+	ErrCannotCreateRecurringNotification = errors.New("cannot create recurring notification")
+	ErrCannotGetRecurringNotifications   = errors.New("cannot get recurring notifications")
+	ErrCannotUpdateRecurringNotification = errors.New("cannot update recurring notification")
+	ErrCannotDeleteRecurringNotification = errors.New("cannot delete recurring notification")
+
+	ErrInvalidTopic                = errors.New("invalid topic")
+	ErrTopicNotFound               = errors.New("topic not found")
+	ErrTopicOrContactNotFound      = errors.New("topic or contact not found")
+	ErrTopicSubscriptionNotFound   = errors.New("topic subscription not found")
+	ErrCannotGetTopics             = errors.New("cannot get topics")
+	ErrCannotUpdateTopic           = errors.New("cannot update topic")
+	ErrCannotDeleteTopic           = errors.New("cannot delete topic")
+	ErrCannotGetTopicSubscriptions = errors.New("cannot get topic subscriptions")
+	ErrCannotSubscribeToTopic      = errors.New("cannot subscribe to topic")
+	ErrCannotUnsubscribeFromTopic  = errors.New("cannot unsubscribe from topic")
+
+	ErrInvalidBroadcast           = errors.New("invalid broadcast")
+	ErrBroadcastNotFound          = errors.New("broadcast not found")
+	ErrInvalidBroadcastTransition = errors.New("broadcast cannot change to this status")
+	ErrCannotCreateBroadcast      = errors.New("cannot create broadcast")
+	ErrCannotGetBroadcasts        = errors.New("cannot get broadcasts")
+	ErrCannotUpdateBroadcast      = errors.New("cannot update broadcast")
+
+	ErrInvalidImport         = errors.New("invalid import")
+	ErrImportNotFound        = errors.New("import not found")
+	ErrImportAlreadyUploaded = errors.New("import has already been uploaded")
+	ErrCannotCreateImport    = errors.New("cannot create import")
+	ErrCannotGetImports      = errors.New("cannot get imports")
+	ErrCannotProcessImport   = errors.New("cannot process import")
+
+	ErrInvalidClient       = errors.New("invalid client")
+	ErrClientAlreadyExists = errors.New("client already exists")
+	ErrClientNotFound      = errors.New("client not found")
+	ErrAPIKeyNotFound      = errors.New("api key not found")
+	ErrInvalidAPIKey       = errors.New("invalid api key")
+	ErrInvalidGracePeriod  = errors.New("invalid grace period")
+	ErrCannotCreateClient  = errors.New("cannot create client")
+	ErrCannotGetClients    = errors.New("cannot get clients")
+	ErrCannotCreateAPIKey  = errors.New("cannot create api key")
+	ErrCannotGetAPIKeys    = errors.New("cannot get api keys")
+	ErrCannotRotateAPIKey  = errors.New("cannot rotate api key")
+	ErrCannotRevokeAPIKey  = errors.New("cannot revoke api key")
+	ErrCannotAuthenticate  = errors.New("cannot authenticate")
+
+	ErrClientNotLinked          = errors.New("token client is not linked to a client")
+	ErrOAuthClientAlreadyLinked = errors.New("oauth client is already linked to another client")
+	ErrCannotUpdateClient       = errors.New("cannot update client")
+
+	ErrInvalidTenant       = errors.New("invalid tenant")
+	ErrTenantNotFound      = errors.New("tenant not found")
+	ErrTenantAlreadyExists = errors.New("tenant already exists")
+	ErrCannotCreateTenant  = errors.New("cannot create tenant")
+	ErrCannotGetTenants    = errors.New("cannot get tenants")
+
+	ErrInvalidQuota      = errors.New("invalid quota")
+	ErrQuotaNotFound     = errors.New("quota not found")
+	ErrQuotaExceeded     = errors.New("quota exceeded")
+	ErrCannotGetQuotas   = errors.New("cannot get quotas")
+	ErrCannotUpdateQuota = errors.New("cannot update quota")
+	ErrCannotDeleteQuota = errors.New("cannot delete quota")
+	ErrCannotGetUsage    = errors.New("cannot get usage")
+
+	ErrInvalidAuditSearch  = errors.New("invalid audit log search")
+	ErrTooManyAuditEntries = errors.New("too many audit log entries requested")
+	ErrCannotGetAuditLog   = errors.New("cannot get audit log")
+	ErrCannotExportAudit   = errors.New("cannot export audit log")
+	ErrCannotRecordAudit   = errors.New("cannot record audit log entry")
+
+	ErrInvalidRetentionPolicy      = errors.New("invalid retention policy")
+	ErrRetentionPolicyNotFound     = errors.New("retention policy not found")
+	ErrCannotGetRetentionPolicies  = errors.New("cannot get retention policies")
+	ErrCannotUpdateRetentionPolicy = errors.New("cannot update retention policy")
+	ErrCannotDeleteRetentionPolicy = errors.New("cannot delete retention policy")
 )
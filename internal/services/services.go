@@ -2,10 +2,14 @@ package services
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/google/uuid"
 
+	"notification_system/internal/auth"
 	"notification_system/internal/dto"
+	"notification_system/pkg/jwt"
 )
 
 type NotificationService interface {
@@ -14,3 +18,132 @@ type NotificationService interface {
 	GetNotificationsByIDs(ctx context.Context, ids []uuid.UUID) ([]*dto.Notification, error)
 	CreateNotifications(ctx context.Context, notifications []*dto.NotificationCreate) ([]uuid.UUID, error)
 }
+
+type WebPushService interface {
+	GetVAPIDPublicKey(ctx context.Context) (*dto.VAPIDPublicKey, error)
+	Subscribe(ctx context.Context, userID string, subscription *dto.WebPushSubscriptionCreate) (*dto.WebPushSubscription, error)
+	GetSubscriptions(ctx context.Context, userID string) ([]*dto.WebPushSubscription, error)
+	Unsubscribe(ctx context.Context, userID, endpoint string) error
+}
+
+type ContactService interface {
+	CreateContact(ctx context.Context, contact *dto.ContactCreate) (*dto.Contact, error)
+	GetContact(ctx context.Context, userID string) (*dto.Contact, error)
+	UpdateContact(ctx context.Context, userID string, contact *dto.ContactUpdate) (*dto.Contact, error)
+	DeleteContact(ctx context.Context, userID string) error
+	AddAddress(ctx context.Context, userID string, address *dto.ContactAddressCreate) (*dto.ContactAddress, error)
+	UpdateAddress(ctx context.Context, userID string, id uuid.UUID, address *dto.ContactAddressUpdate) (*dto.ContactAddress, error)
+	DeleteAddress(ctx context.Context, userID string, id uuid.UUID) error
+	SendVerificationCode(ctx context.Context, userID string, id uuid.UUID) error
+	VerifyAddress(ctx context.Context, userID string, id uuid.UUID, verify *dto.ContactAddressVerify) (*dto.ContactAddress, error)
+}
+
+type PreferenceService interface {
+	GetPreferences(ctx context.Context, userID string) ([]*dto.Preference, error)
+	UpdatePreferences(ctx context.Context, userID string, preferences []*dto.PreferenceUpdate) ([]*dto.Preference, error)
+	DeletePreference(ctx context.Context, userID, category, deliveryType string) error
+	GetCategories(ctx context.Context) ([]*dto.Category, error)
+	UpdateCategory(ctx context.Context, name string, category *dto.CategoryUpdate) (*dto.Category, error)
+}
+
+type UnsubscribeService interface {
+	CheckToken(ctx context.Context, token string) error
+	Unsubscribe(ctx context.Context, token string) error
+}
+
+type SuppressionService interface {
+	AddSuppression(ctx context.Context, suppression *dto.SuppressionCreate) (*dto.Suppression, error)
+	ImportSuppressions(ctx context.Context, suppressions []*dto.SuppressionCreate) (*dto.SuppressionImportResult, error)
+	DeleteSuppression(ctx context.Context, id uuid.UUID) error
+	SearchSuppressions(ctx context.Context, search *dto.SuppressionSearch) ([]*dto.Suppression, error)
+}
+
+type BounceService interface {
+	ProcessBounce(ctx context.Context, message []byte) (*dto.BounceResult, error)
+}
+
+type QuietHoursService interface {
+	GetQuietHours(ctx context.Context, userID, category string) ([]*dto.QuietHours, error)
+	UpdateQuietHours(ctx context.Context, quietHours *dto.QuietHoursUpdate) (*dto.QuietHours, error)
+	DeleteQuietHours(ctx context.Context, userID, category string) error
+}
+
+type FrequencyCapService interface {
+	GetFrequencyCaps(ctx context.Context) ([]*dto.FrequencyCap, error)
+	UpdateFrequencyCap(ctx context.Context, frequencyCap *dto.FrequencyCapUpdate) (*dto.FrequencyCap, error)
+	DeleteFrequencyCap(ctx context.Context, deliveryType, category string) error
+}
+
+type DigestService interface {
+	GetDigestTemplates(ctx context.Context) ([]*dto.DigestTemplate, error)
+	UpdateDigestTemplate(ctx context.Context, key string, template *dto.DigestTemplateUpdate) (*dto.DigestTemplate, error)
+	DeleteDigestTemplate(ctx context.Context, key string) error
+}
+
+type RecurringNotificationService interface {
+	CreateRecurringNotification(ctx context.Context, recurring *dto.RecurringNotificationCreate) (*dto.RecurringNotification, error)
+	GetRecurringNotifications(ctx context.Context, limit, offset uint) ([]*dto.RecurringNotification, error)
+	GetRecurringNotification(ctx context.Context, id uuid.UUID) (*dto.RecurringNotification, error)
+	UpdateRecurringNotification(ctx context.Context, id uuid.UUID, recurring *dto.RecurringNotificationCreate) (*dto.RecurringNotification, error)
+	DeleteRecurringNotification(ctx context.Context, id uuid.UUID) error
+}
+
+type TopicService interface {
+	GetTopics(ctx context.Context) ([]*dto.Topic, error)
+	UpdateTopic(ctx context.Context, name string, topic *dto.TopicUpdate) (*dto.Topic, error)
+	DeleteTopic(ctx context.Context, name string) error
+	GetTopicSubscriptions(ctx context.Context, topic string, limit, offset uint) ([]*dto.TopicSubscription, error)
+	GetUserSubscriptions(ctx context.Context, userID string) ([]*dto.TopicSubscription, error)
+	Subscribe(ctx context.Context, topic, userID string) (*dto.TopicSubscription, error)
+	Unsubscribe(ctx context.Context, topic, userID string) error
+}
+
+type BroadcastService interface {
+	CreateBroadcast(ctx context.Context, broadcast *dto.BroadcastCreate) (*dto.Broadcast, error)
+	GetBroadcasts(ctx context.Context, limit, offset uint) ([]*dto.Broadcast, error)
+	GetBroadcast(ctx context.Context, id uuid.UUID) (*dto.Broadcast, error)
+	PauseBroadcast(ctx context.Context, id uuid.UUID) (*dto.Broadcast, error)
+	ResumeBroadcast(ctx context.Context, id uuid.UUID) (*dto.Broadcast, error)
+	CancelBroadcast(ctx context.Context, id uuid.UUID) (*dto.Broadcast, error)
+}
+
+type ImportService interface {
+	CreateImport(ctx context.Context, imp *dto.ImportCreate) (*dto.Import, error)
+	GetImports(ctx context.Context, limit, offset uint) ([]*dto.Import, error)
+	GetImport(ctx context.Context, id uuid.UUID) (*dto.Import, error)
+	GetImportErrors(ctx context.Context, id uuid.UUID, limit, offset uint) ([]*dto.ImportRowError, error)
+	UploadImport(ctx context.Context, id uuid.UUID, body io.Reader) (*dto.Import, error)
+}
+
+type ClientService interface {
+	CreateClient(ctx context.Context, client *dto.ClientCreate) (*dto.ClientCreated, error)
+	GetClients(ctx context.Context) ([]*dto.Client, error)
+	LinkOAuthClient(ctx context.Context, clientID uuid.UUID, oauthClientID string) (*dto.Client, error)
+	GetAPIKeys(ctx context.Context, clientID uuid.UUID) ([]*dto.APIKey, error)
+	CreateAPIKey(ctx context.Context, clientID uuid.UUID) (*dto.APIKeyCreated, error)
+	RotateAPIKey(ctx context.Context, clientID, keyID uuid.UUID, grace time.Duration) (*dto.APIKeyCreated, error)
+	RevokeAPIKey(ctx context.Context, clientID, keyID uuid.UUID) error
+	Authenticate(ctx context.Context, key string) (*auth.Identity, error)
+	AuthenticateToken(ctx context.Context, claims *jwt.Claims) (*auth.Identity, error)
+	CreateTenant(ctx context.Context, tenant *dto.TenantCreate) (*dto.Tenant, error)
+	GetTenants(ctx context.Context) ([]*dto.Tenant, error)
+}
+
+type QuotaService interface {
+	GetQuotas(ctx context.Context, clientID uuid.UUID) ([]*dto.ClientQuota, error)
+	UpdateQuota(ctx context.Context, clientID uuid.UUID, deliveryType string, quota *dto.ClientQuotaUpdate) (*dto.ClientQuota, error)
+	DeleteQuota(ctx context.Context, clientID uuid.UUID, deliveryType string) error
+	GetUsage(ctx context.Context, clientID uuid.UUID) (*dto.Usage, error)
+}
+
+type AuditService interface {
+	RecordRequest(ctx context.Context, request *dto.AuditRequest) error
+	SearchAuditLog(ctx context.Context, search *dto.AuditSearch) ([]*dto.AuditEntry, error)
+	ExportAuditLog(ctx context.Context, search *dto.AuditSearch, format string, w io.Writer) error
+}
+
+type RetentionService interface {
+	GetRetentionPolicies(ctx context.Context) ([]*dto.RetentionPolicy, error)
+	UpdateRetentionPolicy(ctx context.Context, status string, policy *dto.RetentionPolicyUpdate) (*dto.RetentionPolicy, error)
+	DeleteRetentionPolicy(ctx context.Context, status string) error
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/audit"
+	"notification_system/internal/auth"
+	"notification_system/internal/dto"
+	"notification_system/internal/entities"
+	"notification_system/internal/repositories"
+	slogger "notification_system/pkg/logger"
+)
+
+type SuppressionServiceImpl struct {
+	suppressionRepo repositories.SuppressionRepository
+}
+
+func NewSuppressionServiceImpl(suppressionRepo repositories.SuppressionRepository) SuppressionService {
+	return &SuppressionServiceImpl{suppressionRepo: suppressionRepo}
+}
+
+func (s *SuppressionServiceImpl) AddSuppression(ctx context.Context, suppressionCreate *dto.SuppressionCreate) (*dto.Suppression, error) {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	suppression, err := suppressionEntity(ctx, suppressionCreate)
+	if err != nil {
+		return nil, err
+	}
+	// the entry replaces the active one of the address
+	var before *dto.Suppression
+	if audit.Enabled(ctx) {
+		if current, err := s.suppressionRepo.GetActiveSuppression(ctx, suppression.TenantID, suppression.DeliveryType, suppression.Address); err == nil {
+			before = suppressionAuditState(current)
+		}
+	}
+	if err := s.suppressionRepo.CreateSuppression(ctx, suppression); err != nil {
+		logger.Error("failed to add suppression", slog.Any("error", err))
+		return nil, ErrCannotCreateSuppression
+	}
+	audit.Record(ctx, "suppression.add", "suppression", suppression.ID.String(), before, suppressionAuditState(suppression))
+	return dto.SuppressionEntityToDTO(suppression), nil
+}
+
+func (s *SuppressionServiceImpl) ImportSuppressions(ctx context.Context, suppressionsCreate []*dto.SuppressionCreate) (*dto.SuppressionImportResult, error) {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	suppressions := make([]*entities.Suppression, len(suppressionsCreate))
+	for i, suppressionCreate := range suppressionsCreate {
+		suppression, err := suppressionEntity(ctx, suppressionCreate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: entry %d", err, i+1)
+		}
+		suppressions[i] = suppression
+	}
+	imported, err := s.suppressionRepo.ImportSuppressions(ctx, auth.TenantIDFromContext(ctx), suppressions)
+	if err != nil {
+		if errors.Is(err, repositories.ErrMaxBatchSizeExceeded) {
+			return nil, ErrTooManySuppressions
+		}
+		logger.Error("failed to import suppressions", slog.Any("error", err))
+		return nil, ErrCannotCreateSuppression
+	}
+	logger.Info("suppressions imported", slog.Int("count", imported))
+	if audit.Enabled(ctx) {
+		states := make([]dto.SuppressionCreate, len(suppressionsCreate))
+		for i, suppressionCreate := range suppressionsCreate {
+			states[i] = *suppressionCreate
+			states[i].Address = slogger.Mask(suppressionCreate.Address)
+		}
+		audit.Record(ctx, "suppression.import", "suppression", "", nil, map[string]any{
+			"imported":     imported,
+			"suppressions": states,
+		})
+	}
+	return &dto.SuppressionImportResult{Imported: imported}, nil
+}
+
+func (s *SuppressionServiceImpl) DeleteSuppression(ctx context.Context, id uuid.UUID) error {
+	tenantID := auth.TenantIDFromContext(ctx)
+	var before *dto.Suppression
+	if audit.Enabled(ctx) {
+		if current, err := s.suppressionRepo.GetSuppression(ctx, tenantID, id); err == nil {
+			before = suppressionAuditState(current)
+		}
+	}
+	if err := s.suppressionRepo.DeleteSuppression(ctx, tenantID, id); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrSuppressionNotFound
+		}
+		return ErrCannotDeleteSuppression
+	}
+	audit.Record(ctx, "suppression.remove", "suppression", id.String(), before, nil)
+	return nil
+}
+
+func (s *SuppressionServiceImpl) SearchSuppressions(ctx context.Context, search *dto.SuppressionSearch) ([]*dto.Suppression, error) {
+	suppressions, err := s.suppressionRepo.SearchSuppressions(ctx, &entities.SuppressionFilter{
+		TenantID:     auth.TenantIDFromContext(ctx),
+		DeliveryType: search.DeliveryType,
+		Address:      search.Address,
+		Reason:       search.Reason,
+		Limit:        search.Limit,
+		Offset:       search.Offset,
+	})
+	if err != nil {
+		if errors.Is(err, repositories.ErrMaxBatchSizeExceeded) {
+			return nil, ErrTooManySuppressions
+		}
+		return nil, ErrCannotGetSuppressions
+	}
+	return dto.SuppressionEntitiesToDTOs(suppressions), nil
+}
+
+// suppressionEntity returns the suppression in the list of the caller's tenant.
+func suppressionEntity(ctx context.Context, suppressionCreate *dto.SuppressionCreate) (*entities.Suppression, error) {
+	if suppressionCreate.DeliveryType == "" || suppressionCreate.Address == "" {
+		return nil, ErrInvalidSuppression
+	}
+	reason := suppressionCreate.Reason
+	switch reason {
+	case "":
+		reason = entities.SuppressionManual
+	case entities.SuppressionHardBounce, entities.SuppressionComplaint, entities.SuppressionManual:
+	default:
+		return nil, ErrInvalidSuppression
+	}
+	suppression := &entities.Suppression{
+		TenantID:     auth.TenantIDFromContext(ctx),
+		DeliveryType: suppressionCreate.DeliveryType,
+		Address:      suppressionCreate.Address,
+		Reason:       reason,
+		Details:      suppressionCreate.Details,
+	}
+	if suppressionCreate.ExpiresAt != nil {
+		expiresAt := suppressionCreate.ExpiresAt.UTC()
+		suppression.ExpiresAt = &expiresAt
+	}
+	return suppression, nil
+}
+
+// suppressionAuditState returns the suppression for the audit log, which is kept without
+// retention, with its address masked.
+func suppressionAuditState(suppression *entities.Suppression) *dto.Suppression {
+	state := dto.SuppressionEntityToDTO(suppression)
+	state.Address = slogger.Mask(state.Address)
+	return state
+}
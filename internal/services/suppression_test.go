@@ -0,0 +1,102 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"notification_system/internal/audit"
+	"notification_system/internal/auth"
+	"notification_system/internal/dto"
+	"notification_system/internal/entities"
+	"notification_system/internal/repositories"
+	"notification_system/internal/repositories/mocks"
+)
+
+func TestSuppressionServiceImpl_ImportSuppressions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repomocks.NewMockSuppressionRepository(ctrl)
+
+	mockRepo.
+		EXPECT().
+		ImportSuppressions(gomock.Any(), gomock.Nil(), gomock.Len(2)).
+		DoAndReturn(func(_ context.Context, _ *uuid.UUID, suppressions []*entities.Suppression) (int, error) {
+			if suppressions[0].Reason != entities.SuppressionManual {
+				t.Errorf("reason = %q, want default %q", suppressions[0].Reason, entities.SuppressionManual)
+			}
+			if suppressions[1].Reason != entities.SuppressionComplaint {
+				t.Errorf("reason = %q, want %q", suppressions[1].Reason, entities.SuppressionComplaint)
+			}
+			return 2, nil
+		})
+
+	s := NewSuppressionServiceImpl(mockRepo)
+	result, err := s.ImportSuppressions(context.Background(), []*dto.SuppressionCreate{
+		{DeliveryType: "email", Address: "a@example.com"},
+		{DeliveryType: "email", Address: "b@example.com", Reason: "complaint"},
+	})
+	if err != nil {
+		t.Fatalf("ImportSuppressions() error = %v", err)
+	}
+	if result.Imported != 2 {
+		t.Errorf("imported = %d, want 2", result.Imported)
+	}
+
+	_, err = s.ImportSuppressions(context.Background(), []*dto.SuppressionCreate{
+		{DeliveryType: "email", Address: "a@example.com"},
+		{DeliveryType: "email", Address: "b@example.com", Reason: "bounce"},
+	})
+	if !errors.Is(err, ErrInvalidSuppression) {
+		t.Errorf("ImportSuppressions() error = %v, want %v", err, ErrInvalidSuppression)
+	}
+}
+
+func TestSuppressionServiceImpl_DeleteSuppression(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repomocks.NewMockSuppressionRepository(ctrl)
+
+	mockRepo.
+		EXPECT().
+		DeleteSuppression(gomock.Any(), gomock.Nil(), gomock.Any()).
+		Return(repositories.ErrNotFound)
+
+	s := NewSuppressionServiceImpl(mockRepo)
+	if err := s.DeleteSuppression(context.Background(), uuid.Nil); !errors.Is(err, ErrSuppressionNotFound) {
+		t.Errorf("DeleteSuppression() error = %v, want %v", err, ErrSuppressionNotFound)
+	}
+}
+
+func TestSuppressionServiceImpl_DeleteSuppression_Audited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repomocks.NewMockSuppressionRepository(ctrl)
+	tenantID := uuid.New()
+	ctx := context.WithValue(context.Background(), auth.TenantIDKey, tenantID)
+	ctx = context.WithValue(ctx, audit.ChangeKey, &audit.Change{})
+	suppression := &entities.Suppression{
+		ID:           uuid.New(),
+		TenantID:     &tenantID,
+		DeliveryType: entities.DeliveryTypeEmail,
+		Address:      "user@example.com",
+		Reason:       entities.SuppressionManual,
+	}
+
+	mockRepo.EXPECT().GetSuppression(gomock.Any(), &tenantID, suppression.ID).Return(suppression, nil)
+	mockRepo.EXPECT().DeleteSuppression(gomock.Any(), &tenantID, suppression.ID).Return(nil)
+
+	if err := NewSuppressionServiceImpl(mockRepo).DeleteSuppression(ctx, suppression.ID); err != nil {
+		t.Fatalf("DeleteSuppression() error = %v", err)
+	}
+	change, _ := audit.FromContext(ctx)
+	if change.Action != "suppression.remove" || change.ResourceID != suppression.ID.String() {
+		t.Errorf("unexpected change %q %q", change.Action, change.ResourceID)
+	}
+	masked := bytes.Contains(change.Before, []byte(`"address":"u***@example.com"`)) &&
+		!bytes.Contains(change.Before, []byte("user@example.com"))
+	if !masked || change.After != nil {
+		t.Errorf("Before = %s, After = %s, want the removed suppression with its address masked", change.Before, change.After)
+	}
+}
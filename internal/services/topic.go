@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"notification_system/internal/audit"
+	"notification_system/internal/auth"
+	"notification_system/internal/dto"
+	"notification_system/internal/entities"
+	"notification_system/internal/repositories"
+	slogger "notification_system/pkg/logger"
+)
+
+type TopicServiceImpl struct {
+	topicRepo repositories.TopicRepository
+}
+
+func NewTopicServiceImpl(topicRepo repositories.TopicRepository) TopicService {
+	return &TopicServiceImpl{topicRepo: topicRepo}
+}
+
+func (s *TopicServiceImpl) GetTopics(ctx context.Context) ([]*dto.Topic, error) {
+	topics, err := s.topicRepo.GetTopics(ctx, auth.TenantIDFromContext(ctx))
+	if err != nil {
+		return nil, ErrCannotGetTopics
+	}
+	return dto.TopicEntitiesToDTOs(topics), nil
+}
+
+func (s *TopicServiceImpl) UpdateTopic(ctx context.Context, name string, topicUpdate *dto.TopicUpdate) (*dto.Topic, error) {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	if name == "" {
+		return nil, ErrInvalidTopic
+	}
+	topic := &entities.Topic{
+		Name:        name,
+		TenantID:    auth.TenantIDFromContext(ctx),
+		Description: topicUpdate.Description,
+	}
+	before := s.auditedTopic(ctx, name)
+	if err := s.topicRepo.UpsertTopic(ctx, topic); err != nil {
+		logger.Error("failed to update topic", slog.Any("error", err))
+		return nil, ErrCannotUpdateTopic
+	}
+	audit.Record(ctx, "topic.update", "topic", name, before, dto.TopicEntityToDTO(topic))
+	return dto.TopicEntityToDTO(topic), nil
+}
+
+// DeleteTopic removes the topic together with its subscriptions.
+func (s *TopicServiceImpl) DeleteTopic(ctx context.Context, name string) error {
+	before := s.auditedTopic(ctx, name)
+	if err := s.topicRepo.DeleteTopic(ctx, auth.TenantIDFromContext(ctx), name); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrTopicNotFound
+		}
+		return ErrCannotDeleteTopic
+	}
+	audit.Record(ctx, "topic.delete", "topic", name, before, nil)
+	return nil
+}
+
+func (s *TopicServiceImpl) GetTopicSubscriptions(ctx context.Context, topic string, limit, offset uint) ([]*dto.TopicSubscription, error) {
+	if _, err := s.topicRepo.GetTopic(ctx, auth.TenantIDFromContext(ctx), topic); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrTopicNotFound
+		}
+		return nil, ErrCannotGetTopicSubscriptions
+	}
+	subscriptions, err := s.topicRepo.GetTopicSubscriptions(ctx, auth.TenantIDFromContext(ctx), topic, limit, offset)
+	if err != nil {
+		return nil, ErrCannotGetTopicSubscriptions
+	}
+	return dto.TopicSubscriptionEntitiesToDTOs(subscriptions), nil
+}
+
+func (s *TopicServiceImpl) GetUserSubscriptions(ctx context.Context, userID string) ([]*dto.TopicSubscription, error) {
+	subscriptions, err := s.topicRepo.GetUserSubscriptions(ctx, auth.TenantIDFromContext(ctx), userID)
+	if err != nil {
+		return nil, ErrCannotGetTopicSubscriptions
+	}
+	return dto.TopicSubscriptionEntitiesToDTOs(subscriptions), nil
+}
+
+func (s *TopicServiceImpl) Subscribe(ctx context.Context, topic, userID string) (*dto.TopicSubscription, error) {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	subscription := &entities.TopicSubscription{Topic: topic, TenantID: auth.TenantIDFromContext(ctx), UserID: userID}
+	if err := s.topicRepo.Subscribe(ctx, subscription); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrTopicOrContactNotFound
+		}
+		logger.Error("failed to subscribe to topic", slog.Any("error", err))
+		return nil, ErrCannotSubscribeToTopic
+	}
+	after := dto.TopicSubscriptionEntitiesToDTOs([]*entities.TopicSubscription{subscription})[0]
+	audit.Record(ctx, "topic.subscribe", "topic_subscription", topic+"/"+userID, nil, after)
+	return after, nil
+}
+
+func (s *TopicServiceImpl) Unsubscribe(ctx context.Context, topic, userID string) error {
+	var before *dto.TopicSubscription
+	if audit.Enabled(ctx) {
+		if subscriptions, err := s.topicRepo.GetUserSubscriptions(ctx, auth.TenantIDFromContext(ctx), userID); err == nil {
+			for _, subscription := range dto.TopicSubscriptionEntitiesToDTOs(subscriptions) {
+				if subscription.Topic == topic {
+					before = subscription
+				}
+			}
+		}
+	}
+	if err := s.topicRepo.Unsubscribe(ctx, auth.TenantIDFromContext(ctx), topic, userID); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrTopicSubscriptionNotFound
+		}
+		return ErrCannotUnsubscribeFromTopic
+	}
+	audit.Record(ctx, "topic.unsubscribe", "topic_subscription", topic+"/"+userID, before, nil)
+	return nil
+}
+
+// auditedTopic returns the topic for the audit log, nil when the request is not audited
+// or there is no topic.
+func (s *TopicServiceImpl) auditedTopic(ctx context.Context, name string) *dto.Topic {
+	if !audit.Enabled(ctx) {
+		return nil
+	}
+	topic, err := s.topicRepo.GetTopic(ctx, auth.TenantIDFromContext(ctx), name)
+	if err != nil {
+		return nil
+	}
+	return dto.TopicEntityToDTO(topic)
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"notification_system/internal/entities"
+	"notification_system/internal/repositories"
+	"notification_system/internal/unsubscribe"
+	slogger "notification_system/pkg/logger"
+)
+
+type UnsubscribeServiceImpl struct {
+	preferenceRepo repositories.PreferenceRepository
+	secret         []byte
+}
+
+func NewUnsubscribeServiceImpl(preferenceRepo repositories.PreferenceRepository, secret string) UnsubscribeService {
+	return &UnsubscribeServiceImpl{
+		preferenceRepo: preferenceRepo,
+		secret:         []byte(secret),
+	}
+}
+
+func (s *UnsubscribeServiceImpl) CheckToken(ctx context.Context, token string) error {
+	_, err := s.verify(token)
+	return err
+}
+
+// Unsubscribe records the opt-out of the address, and of the user when the link
+// was sent to a registered user, so future notifications of the category are suppressed.
+func (s *UnsubscribeServiceImpl) Unsubscribe(ctx context.Context, token string) error {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	claims, err := s.verify(token)
+	if err != nil {
+		return err
+	}
+	var tenantID *uuid.UUID
+	if claims.TenantID != "" {
+		id, err := uuid.Parse(claims.TenantID)
+		if err != nil {
+			return ErrInvalidUnsubscribeToken
+		}
+		tenantID = &id
+	}
+	err = s.preferenceRepo.CreateUnsubscribe(ctx, tenantID, claims.DeliveryType, claims.Address, claims.Category)
+	if err != nil {
+		logger.Error("failed to record unsubscribe", slog.Any("error", err))
+		return ErrCannotUnsubscribe
+	}
+	if claims.UserID != "" {
+		preference := &entities.NotificationPreference{
+			TenantID:     tenantID,
+			UserID:       claims.UserID,
+			Category:     claims.Category,
+			DeliveryType: claims.DeliveryType,
+			OptedIn:      false,
+		}
+		err := s.preferenceRepo.UpsertPreferences(ctx, []*entities.NotificationPreference{preference})
+		if err != nil {
+			logger.Error("failed to record opt-out preference", slog.Any("error", err))
+			return ErrCannotUnsubscribe
+		}
+	}
+	logger.Info("recipient unsubscribed",
+		slog.String("delivery_type", claims.DeliveryType),
+		slog.String("category", claims.Category),
+	)
+	return nil
+}
+
+func (s *UnsubscribeServiceImpl) verify(token string) (*unsubscribe.Claims, error) {
+	if len(s.secret) == 0 {
+		return nil, ErrUnsubscribeNotConfigured
+	}
+	claims, err := unsubscribe.Verify(s.secret, token)
+	if err != nil {
+		if errors.Is(err, unsubscribe.ErrExpiredToken) {
+			return nil, ErrUnsubscribeTokenExpired
+		}
+		return nil, ErrInvalidUnsubscribeToken
+	}
+	return claims, nil
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"notification_system/internal/entities"
+	"notification_system/internal/repositories/mocks"
+	"notification_system/internal/unsubscribe"
+)
+
+func TestUnsubscribeServiceImpl_Unsubscribe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockPreferenceRepo := repomocks.NewMockPreferenceRepository(ctrl)
+	s := NewUnsubscribeServiceImpl(mockPreferenceRepo, "secret")
+	tenantID := uuid.New()
+	token, _ := unsubscribe.Sign([]byte("secret"), &unsubscribe.Claims{
+		DeliveryType: entities.DeliveryTypeEmail,
+		Address:      "user@example.com",
+		Category:     "marketing",
+		UserID:       "user-1",
+		TenantID:     tenantID.String(),
+		ExpiresAt:    time.Now().Add(time.Hour).Unix(),
+	})
+
+	// the address is unsubscribed in the tenant that sent the link only
+	mockPreferenceRepo.
+		EXPECT().
+		CreateUnsubscribe(gomock.Any(), &tenantID, entities.DeliveryTypeEmail, "user@example.com", "marketing").
+		Return(nil)
+	mockPreferenceRepo.
+		EXPECT().
+		UpsertPreferences(gomock.Any(), gomock.Len(1)).
+		DoAndReturn(func(_ context.Context, preferences []*entities.NotificationPreference) error {
+			if preferences[0].TenantID == nil || *preferences[0].TenantID != tenantID || preferences[0].OptedIn {
+				t.Errorf("unexpected preference %+v", preferences[0])
+			}
+			return nil
+		})
+	if err := s.Unsubscribe(context.Background(), token); err != nil {
+		t.Fatalf("Unsubscribe() error = %v", err)
+	}
+
+	invalid, _ := unsubscribe.Sign([]byte("secret"), &unsubscribe.Claims{
+		DeliveryType: entities.DeliveryTypeEmail,
+		Address:      "user@example.com",
+		TenantID:     "not a tenant",
+		ExpiresAt:    time.Now().Add(time.Hour).Unix(),
+	})
+	if err := s.Unsubscribe(context.Background(), invalid); !errors.Is(err, ErrInvalidUnsubscribeToken) {
+		t.Errorf("Unsubscribe() error = %v, want %v", err, ErrInvalidUnsubscribeToken)
+	}
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"notification_system/internal/audit"
+	"notification_system/internal/auth"
+	"notification_system/internal/dto"
+	"notification_system/internal/entities"
+	"notification_system/internal/notifiers"
+	"notification_system/internal/repositories"
+	slogger "notification_system/pkg/logger"
+)
+
+type WebPushServiceImpl struct {
+	subscriptionRepo repositories.WebPushSubscriptionRepository
+	vapidPublicKey   string
+}
+
+func NewWebPushServiceImpl(subscriptionRepo repositories.WebPushSubscriptionRepository, vapidPublicKey string) WebPushService {
+	return &WebPushServiceImpl{
+		subscriptionRepo: subscriptionRepo,
+		vapidPublicKey:   vapidPublicKey,
+	}
+}
+
+func (s *WebPushServiceImpl) GetVAPIDPublicKey(ctx context.Context) (*dto.VAPIDPublicKey, error) {
+	if s.vapidPublicKey == "" {
+		return nil, ErrWebPushNotConfigured
+	}
+	return &dto.VAPIDPublicKey{PublicKey: s.vapidPublicKey}, nil
+}
+
+func (s *WebPushServiceImpl) Subscribe(ctx context.Context, userID string, subscriptionCreate *dto.WebPushSubscriptionCreate) (*dto.WebPushSubscription, error) {
+	logger := slogger.GetLoggerFromContext(ctx)
+
+	endpoint, err := url.Parse(subscriptionCreate.Endpoint)
+	if userID == "" || err != nil || endpoint.Scheme != "https" || endpoint.Host == "" {
+		return nil, ErrInvalidWebPushSubscription
+	}
+	if err := notifiers.ValidateWebPushKeys(subscriptionCreate.Keys.P256dh, subscriptionCreate.Keys.Auth); err != nil {
+		return nil, ErrInvalidWebPushSubscription
+	}
+	subscription := &entities.WebPushSubscription{
+		UserID:   userID,
+		TenantID: auth.TenantIDFromContext(ctx),
+		Endpoint: subscriptionCreate.Endpoint,
+		P256dh:   subscriptionCreate.Keys.P256dh,
+		Auth:     subscriptionCreate.Keys.Auth,
+	}
+	if subscriptionCreate.ExpirationTime != nil {
+		expirationTime := time.UnixMilli(*subscriptionCreate.ExpirationTime)
+		subscription.ExpirationTime = &expirationTime
+	}
+	if err := s.subscriptionRepo.CreateWebPushSubscription(ctx, subscription); err != nil {
+		logger.Error("failed to create web push subscription", slog.Any("error", err))
+		return nil, ErrCannotCreateWebPushSubscription
+	}
+	audit.Record(ctx, "web_push_subscription.create", "web_push_subscription", subscription.ID.String(),
+		nil, webPushSubscriptionAuditState(subscription))
+	return dto.WebPushSubscriptionEntityToDTO(subscription), nil
+}
+
+func (s *WebPushServiceImpl) GetSubscriptions(ctx context.Context, userID string) ([]*dto.WebPushSubscription, error) {
+	subscriptions, err := s.subscriptionRepo.GetWebPushSubscriptionsByUserID(ctx, auth.TenantIDFromContext(ctx), userID)
+	if err != nil {
+		return nil, ErrCannotGetWebPushSubscriptions
+	}
+	return dto.WebPushSubscriptionEntitiesToDTOs(subscriptions), nil
+}
+
+func (s *WebPushServiceImpl) Unsubscribe(ctx context.Context, userID, endpoint string) error {
+	var before *dto.WebPushSubscription
+	if audit.Enabled(ctx) {
+		if subscriptions, err := s.subscriptionRepo.GetWebPushSubscriptionsByUserID(ctx, auth.TenantIDFromContext(ctx), userID); err == nil {
+			for _, subscription := range subscriptions {
+				if subscription.Endpoint == endpoint {
+					before = webPushSubscriptionAuditState(subscription)
+				}
+			}
+		}
+	}
+	err := s.subscriptionRepo.DeleteWebPushSubscription(ctx, auth.TenantIDFromContext(ctx), userID, endpoint)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrWebPushSubscriptionNotFound
+		}
+		return ErrCannotDeleteWebPushSubscription
+	}
+	resourceID := ""
+	if before != nil {
+		resourceID = before.ID.String()
+	}
+	audit.Record(ctx, "web_push_subscription.delete", "web_push_subscription", resourceID, before, nil)
+	return nil
+}
+
+// webPushSubscriptionAuditState returns the subscription for the audit log with its endpoint,
+// a capability URL of the browser, masked.
+func webPushSubscriptionAuditState(subscription *entities.WebPushSubscription) *dto.WebPushSubscription {
+	state := dto.WebPushSubscriptionEntityToDTO(subscription)
+	state.Endpoint = slogger.Mask(state.Endpoint)
+	return state
+}
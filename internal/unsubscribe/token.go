@@ -0,0 +1,85 @@
+// Package unsubscribe issues and verifies the signed tokens carried by unsubscribe links.
+package unsubscribe
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid unsubscribe token")
+	ErrExpiredToken = errors.New("unsubscribe token expired")
+)
+
+// Claims identify what the recipient unsubscribes from.
+type Claims struct {
+	DeliveryType string `json:"t"`
+	Address      string `json:"a"`
+	Category     string `json:"c"`
+	UserID       string `json:"u,omitempty"`
+	// TenantID is the tenant the address unsubscribes from, empty for the default tenant
+	TenantID  string `json:"n,omitempty"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Signer builds unsubscribe links pointing to the public endpoint of the service.
+type Signer struct {
+	Secret  []byte
+	BaseURL string
+	TTL     time.Duration
+}
+
+// URL returns the one-click unsubscribe link for the claims, valid for the signer TTL.
+func (s *Signer) URL(claims Claims) (string, error) {
+	claims.ExpiresAt = time.Now().Add(s.TTL).Unix()
+	token, err := Sign(s.Secret, &claims)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(s.BaseURL, "/") + "/api/v1/unsubscribe?token=" + url.QueryEscape(token), nil
+}
+
+func Sign(secret []byte, claims *Claims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("unsubscribe.Sign error: %w", err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(signature(secret, encoded)), nil
+}
+
+func Verify(secret []byte, token string) (*Claims, error) {
+	encoded, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	decodedSig, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(decodedSig, signature(secret, encoded)) {
+		return nil, ErrInvalidToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Address == "" {
+		return nil, ErrInvalidToken
+	}
+	if time.Now().Unix() > claims.ExpiresAt {
+		return nil, ErrExpiredToken
+	}
+	return &claims, nil
+}
+
+func signature(secret []byte, payload string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,63 @@
+package unsubscribe
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	secret := []byte("secret")
+	claims := &Claims{
+		DeliveryType: "email",
+		Address:      "user@example.com",
+		Category:     "marketing",
+		ExpiresAt:    time.Now().Add(time.Hour).Unix(),
+	}
+	token, err := Sign(secret, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Verify(secret, token)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if *got != *claims {
+		t.Errorf("Verify() = %+v, want %+v", got, claims)
+	}
+
+	if _, err := Verify([]byte("other"), token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Verify() with another secret error = %v, want %v", err, ErrInvalidToken)
+	}
+	payload, sig, _ := strings.Cut(token, ".")
+	if _, err := Verify(secret, payload+"x."+sig); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Verify() of tampered token error = %v, want %v", err, ErrInvalidToken)
+	}
+
+	claims.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+	expired, _ := Sign(secret, claims)
+	if _, err := Verify(secret, expired); !errors.Is(err, ErrExpiredToken) {
+		t.Errorf("Verify() of expired token error = %v, want %v", err, ErrExpiredToken)
+	}
+}
+
+func TestSigner_URL(t *testing.T) {
+	signer := &Signer{Secret: []byte("secret"), BaseURL: "https://notify.example.com/", TTL: time.Hour}
+	link, err := signer.URL(Claims{DeliveryType: "email", Address: "user@example.com", Category: "*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Host != "notify.example.com" || u.Path != "/api/v1/unsubscribe" {
+		t.Errorf("URL() = %s", link)
+	}
+	claims, err := Verify(signer.Secret, u.Query().Get("token"))
+	if err != nil || claims.Address != "user@example.com" {
+		t.Errorf("Verify() = %+v, %v", claims, err)
+	}
+}
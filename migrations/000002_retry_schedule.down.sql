@@ -0,0 +1,3 @@
+drop table if exists unreachable_recipients;
+
+alter table notifications drop column if exists next_attempt_at;
\ No newline at end of file
@@ -0,0 +1,9 @@
+alter table notifications add column next_attempt_at timestamp;
+
+create table unreachable_recipients (
+    delivery_type text not null,
+    recipient text not null,
+    reason text not null,
+    created_at timestamp not null default now(),
+    primary key (delivery_type, recipient)
+);
\ No newline at end of file
@@ -0,0 +1,3 @@
+drop table if exists web_push_subscriptions;
+
+alter table notifications drop column if exists priority;
\ No newline at end of file
@@ -0,0 +1,14 @@
+alter table notifications add column priority text not null default 'normal'
+    check (priority in ('low', 'normal', 'high', 'critical'));
+
+create table web_push_subscriptions (
+    id uuid primary key default uuid_generate_v4(),
+    user_id text not null,
+    endpoint text not null unique,
+    p256dh text not null,
+    auth text not null,
+    expiration_time timestamp,
+    created_at timestamp not null default now()
+);
+
+create index web_push_subscriptions_user_id_idx on web_push_subscriptions (user_id);
\ No newline at end of file
@@ -0,0 +1,10 @@
+drop table if exists notification_channels;
+
+delete from notifications where status in ('in_progress', 'expired');
+alter table notifications drop constraint notifications_status_check;
+alter table notifications add constraint notifications_status_check
+    check (status in ('delivered', 'pending', 'in_queue', 'failed'));
+
+drop index if exists notifications_parent_id_idx;
+alter table notifications drop column if exists chain_step;
+alter table notifications drop column if exists parent_id;
\ No newline at end of file
@@ -0,0 +1,20 @@
+alter table notifications add column parent_id uuid;
+alter table notifications add column chain_step smallint;
+
+create index notifications_parent_id_idx on notifications (parent_id) where parent_id is not null;
+
+alter table notifications drop constraint notifications_status_check;
+alter table notifications add constraint notifications_status_check
+    check (status in ('delivered', 'pending', 'in_queue', 'failed', 'in_progress', 'expired'));
+
+create table notification_channels (
+    notification_id uuid not null,
+    step smallint not null,
+    delivery_type text not null,
+    recipient text not null,
+    content text,
+    timeout_seconds integer check (timeout_seconds > 0),
+    condition text not null default 'failed_or_timeout'
+        check (condition in ('failed_or_timeout', 'failed', 'timeout')),
+    primary key (notification_id, step)
+);
\ No newline at end of file
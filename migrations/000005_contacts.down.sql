@@ -0,0 +1,4 @@
+drop table if exists contact_addresses;
+drop table if exists contacts;
+
+alter table notifications drop column if exists user_id;
\ No newline at end of file
@@ -0,0 +1,23 @@
+alter table notifications add column user_id text;
+
+create table contacts (
+    user_id text primary key,
+    name text not null default '',
+    created_at timestamp not null default now(),
+    updated_at timestamp not null default now()
+);
+
+create table contact_addresses (
+    id uuid primary key default uuid_generate_v4(),
+    user_id text not null references contacts (user_id) on delete cascade,
+    delivery_type text not null,
+    address text not null,
+    is_primary boolean not null default false,
+    verified_at timestamp,
+    verification_code_hash text,
+    verification_expires_at timestamp,
+    created_at timestamp not null default now(),
+    unique (user_id, delivery_type, address)
+);
+
+create unique index contact_addresses_primary_idx on contact_addresses (user_id, delivery_type) where is_primary;
\ No newline at end of file
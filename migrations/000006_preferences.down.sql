@@ -0,0 +1,10 @@
+drop table if exists notification_preferences;
+drop table if exists notification_categories;
+
+update notifications set status = 'failed' where status = 'suppressed';
+alter table notifications drop constraint notifications_status_check;
+alter table notifications add constraint notifications_status_check
+    check (status in ('delivered', 'pending', 'in_queue', 'failed', 'in_progress', 'expired'));
+
+alter table notifications drop column if exists status_reason;
+alter table notifications drop column if exists category;
\ No newline at end of file
@@ -0,0 +1,25 @@
+alter table notifications add column category text;
+alter table notifications add column status_reason text;
+
+alter table notifications drop constraint notifications_status_check;
+alter table notifications add constraint notifications_status_check
+    check (status in ('delivered', 'pending', 'in_queue', 'failed', 'in_progress', 'expired', 'suppressed'));
+
+create table notification_categories (
+    name text primary key,
+    description text not null default '',
+    suppressible boolean not null default true,
+    created_at timestamp not null default now()
+);
+
+-- '*' in category or delivery_type matches every category or channel,
+-- the most specific preference wins
+create table notification_preferences (
+    user_id text not null,
+    category text not null default '*',
+    delivery_type text not null default '*',
+    opted_in boolean not null default true,
+    muted_until timestamp,
+    updated_at timestamp not null default now(),
+    primary key (user_id, category, delivery_type)
+);
\ No newline at end of file
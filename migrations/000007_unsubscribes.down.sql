@@ -0,0 +1 @@
+drop table if exists unsubscribes;
\ No newline at end of file
@@ -0,0 +1,7 @@
+create table unsubscribes (
+    delivery_type text not null,
+    address text not null,
+    category text not null default '*',
+    created_at timestamp not null default now(),
+    primary key (delivery_type, address, category)
+);
\ No newline at end of file
@@ -0,0 +1,14 @@
+create table unreachable_recipients (
+    delivery_type text not null,
+    recipient text not null,
+    reason text not null,
+    created_at timestamp not null default now(),
+    primary key (delivery_type, recipient)
+);
+
+insert into unreachable_recipients (delivery_type, recipient, reason, created_at)
+select delivery_type, address, details, created_at
+from suppressions
+where reason = 'hard_bounce';
+
+drop table if exists suppressions;
\ No newline at end of file
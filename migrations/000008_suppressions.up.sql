@@ -0,0 +1,16 @@
+create table suppressions (
+    id uuid primary key default uuid_generate_v4(),
+    delivery_type text not null,
+    address text not null,
+    reason text not null check (reason in ('hard_bounce', 'complaint', 'manual')),
+    details text not null default '',
+    expires_at timestamp,
+    created_at timestamp not null default now(),
+    unique (delivery_type, address)
+);
+
+insert into suppressions (delivery_type, address, reason, details, created_at)
+select delivery_type, recipient, 'hard_bounce', reason, created_at
+from unreachable_recipients;
+
+drop table unreachable_recipients;
\ No newline at end of file
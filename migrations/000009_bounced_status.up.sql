@@ -0,0 +1,3 @@
+alter table notifications drop constraint notifications_status_check;
+alter table notifications add constraint notifications_status_check
+    check (status in ('delivered', 'pending', 'in_queue', 'failed', 'in_progress', 'expired', 'suppressed', 'bounced'));
\ No newline at end of file
@@ -0,0 +1,3 @@
+drop table if exists quiet_hours;
+
+alter table contacts drop column if exists time_zone;
\ No newline at end of file
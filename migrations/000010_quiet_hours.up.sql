@@ -0,0 +1,14 @@
+alter table contacts add column time_zone text not null default '';
+
+-- '*' in user_id or category matches every user or category,
+-- a rule for the user wins over a rule for the category, which wins over the global rule
+create table quiet_hours (
+    user_id text not null default '*',
+    category text not null default '*',
+    start_minute smallint not null check (start_minute between 0 and 1439),
+    end_minute smallint not null check (end_minute between 0 and 1439),
+    time_zone text not null default '',
+    updated_at timestamp not null default now(),
+    primary key (user_id, category),
+    check (start_minute <> end_minute)
+);
\ No newline at end of file
@@ -0,0 +1,2 @@
+drop table if exists frequency_buckets;
+drop table if exists frequency_caps;
\ No newline at end of file
@@ -0,0 +1,24 @@
+-- '*' in delivery_type or category matches every channel or category,
+-- every matching cap applies and each recipient address has its own bucket per cap
+create table frequency_caps (
+    id uuid primary key default uuid_generate_v4(),
+    delivery_type text not null default '*',
+    category text not null default '*',
+    max_count integer not null check (max_count > 0),
+    period_seconds integer not null check (period_seconds > 0),
+    policy text not null default 'delay' check (policy in ('delay', 'drop')),
+    updated_at timestamp not null default now(),
+    unique (delivery_type, category)
+);
+
+-- token buckets refilled continuously at max_count tokens per period
+create table frequency_buckets (
+    cap_id uuid not null references frequency_caps (id) on delete cascade,
+    delivery_type text not null,
+    recipient text not null,
+    tokens double precision not null,
+    updated_at timestamp not null default now(),
+    primary key (cap_id, delivery_type, recipient)
+);
+
+create index frequency_buckets_updated_at_idx on frequency_buckets (updated_at);
\ No newline at end of file
@@ -0,0 +1,13 @@
+drop table if exists digest_templates;
+
+drop index if exists notifications_open_digests_idx;
+
+update notifications set status = 'failed' where status = 'digested' and summary_id is null;
+update notifications set status = 'delivered' where status = 'digested';
+alter table notifications drop constraint notifications_status_check;
+alter table notifications add constraint notifications_status_check
+    check (status in ('delivered', 'pending', 'in_queue', 'failed', 'in_progress', 'expired', 'suppressed', 'bounced'));
+
+alter table notifications drop column if exists summary_id;
+alter table notifications drop column if exists digest_window_seconds;
+alter table notifications drop column if exists digest_key;
\ No newline at end of file
@@ -0,0 +1,17 @@
+alter table notifications add column digest_key text;
+alter table notifications add column digest_window_seconds integer;
+alter table notifications add column summary_id uuid;
+
+alter table notifications drop constraint notifications_status_check;
+alter table notifications add constraint notifications_status_check
+    check (status in ('delivered', 'pending', 'in_queue', 'failed', 'in_progress', 'expired', 'suppressed', 'bounced', 'digested'));
+
+-- digest items waiting for their summary
+create index notifications_open_digests_idx on notifications (digest_key, delivery_type, recipient)
+    where status = 'digested' and summary_id is null;
+
+create table digest_templates (
+    digest_key text primary key,
+    template text not null,
+    updated_at timestamp not null default now()
+);
\ No newline at end of file
@@ -0,0 +1,2 @@
+alter table notifications drop column if exists recurring_id;
+drop table if exists recurring_notifications;
\ No newline at end of file
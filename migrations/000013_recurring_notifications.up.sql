@@ -0,0 +1,30 @@
+-- schedule is a five-field cron expression or an RRULE anchored at starts_at, both read
+-- in time_zone; next_run_at is null once the schedule is exhausted
+create table recurring_notifications (
+    id uuid primary key default uuid_generate_v4(),
+    name text not null,
+    schedule_type text not null check (schedule_type in ('cron', 'rrule')),
+    schedule text not null,
+    time_zone text not null default 'UTC',
+    starts_at timestamp not null default now(),
+    ends_at timestamp,
+    delivery_type text not null,
+    recipients text[] not null default '{}',
+    user_ids text[] not null default '{}',
+    template text not null,
+    variables jsonb not null default '{}',
+    priority text not null default 'normal',
+    category text,
+    enabled boolean not null default true,
+    next_run_at timestamp,
+    last_run_at timestamp,
+    occurrences integer not null default 0,
+    created_at timestamp not null default now(),
+    updated_at timestamp not null default now(),
+    check (cardinality(recipients) + cardinality(user_ids) > 0)
+);
+
+create index recurring_notifications_due_idx on recurring_notifications (next_run_at)
+    where enabled and next_run_at is not null;
+
+alter table notifications add column recurring_id uuid;
\ No newline at end of file
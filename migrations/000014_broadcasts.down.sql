@@ -0,0 +1,4 @@
+alter table notifications drop column if exists broadcast_id;
+drop table if exists broadcasts;
+drop table if exists topic_subscriptions;
+drop table if exists topics;
\ No newline at end of file
@@ -0,0 +1,39 @@
+create table topics (
+    name text primary key,
+    description text not null default '',
+    created_at timestamp not null default now(),
+    updated_at timestamp not null default now()
+);
+
+create table topic_subscriptions (
+    topic text not null references topics (name) on delete cascade,
+    user_id text not null references contacts (user_id) on delete cascade,
+    created_at timestamp not null default now(),
+    primary key (topic, user_id)
+);
+
+-- a broadcast targets the subscribers of a topic or a segment of the contacts and is
+-- expanded in chunks ordered by user_id, cursor is the last user_id expanded
+create table broadcasts (
+    id uuid primary key default uuid_generate_v4(),
+    topic text,
+    segment jsonb,
+    delivery_type text not null,
+    content text not null,
+    priority text not null default 'normal',
+    category text,
+    status text not null default 'pending'
+        check (status in ('pending', 'running', 'paused', 'completed', 'cancelled')),
+    total integer,
+    processed integer not null default 0,
+    cursor text,
+    created_at timestamp not null default now(),
+    updated_at timestamp not null default now(),
+    started_at timestamp,
+    completed_at timestamp,
+    check ((topic is null) <> (segment is null))
+);
+
+create index broadcasts_active_idx on broadcasts (created_at) where status in ('pending', 'running');
+
+alter table notifications add column broadcast_id uuid;
\ No newline at end of file
@@ -0,0 +1,3 @@
+alter table notifications drop column if exists import_id;
+drop table if exists import_errors;
+drop table if exists imports;
\ No newline at end of file
@@ -0,0 +1,30 @@
+-- an import is created with its column mapping first, the file is uploaded to it afterwards
+create table imports (
+    id uuid primary key default uuid_generate_v4(),
+    status text not null default 'awaiting_upload'
+        check (status in ('awaiting_upload', 'processing', 'completed', 'failed')),
+    format text not null check (format in ('csv', 'ndjson')),
+    delivery_type text not null,
+    mapping jsonb not null default '{}',
+    template text not null,
+    priority text not null default 'normal',
+    category text,
+    total_rows integer not null default 0,
+    created_rows integer not null default 0,
+    failed_rows integer not null default 0,
+    error text,
+    created_at timestamp not null default now(),
+    updated_at timestamp not null default now(),
+    started_at timestamp,
+    completed_at timestamp
+);
+
+-- only the first errors of an import are kept, failed_rows counts all of them
+create table import_errors (
+    import_id uuid not null references imports (id) on delete cascade,
+    line integer not null,
+    message text not null,
+    primary key (import_id, line)
+);
+
+alter table notifications add column import_id uuid;
\ No newline at end of file
@@ -0,0 +1,4 @@
+drop index if exists notifications_client_id_idx;
+alter table notifications drop column if exists client_id;
+drop table if exists api_keys;
+drop table if exists clients;
\ No newline at end of file
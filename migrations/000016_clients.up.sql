@@ -0,0 +1,23 @@
+create table clients (
+    id uuid primary key default uuid_generate_v4(),
+    name text not null unique,
+    created_at timestamp not null default now()
+);
+
+-- only the sha256 of a key is stored, the prefix identifies it; a rotated key
+-- keeps working until expires_at so the client can roll the new one out
+create table api_keys (
+    id uuid primary key default uuid_generate_v4(),
+    client_id uuid not null references clients (id) on delete cascade,
+    prefix text not null unique,
+    key_hash text not null,
+    created_at timestamp not null default now(),
+    expires_at timestamp,
+    revoked_at timestamp
+);
+
+create index api_keys_client_id_idx on api_keys (client_id);
+
+alter table notifications add column client_id uuid;
+
+create index notifications_client_id_idx on notifications (client_id, created_at) where status = 'pending';
\ No newline at end of file
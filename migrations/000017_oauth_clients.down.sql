@@ -0,0 +1 @@
+alter table clients drop column if exists oauth_client_id;
\ No newline at end of file
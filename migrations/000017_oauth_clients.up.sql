@@ -0,0 +1,3 @@
+-- links a client to the OAuth client of the tokens issued to it, so token callers are
+-- scoped to the notifications of the client like its API keys
+alter table clients add column oauth_client_id text unique;
\ No newline at end of file
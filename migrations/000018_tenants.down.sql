@@ -0,0 +1,4 @@
+drop index if exists notifications_tenant_id_idx;
+alter table notifications drop column if exists tenant_id;
+alter table clients drop column if exists tenant_id;
+drop table if exists tenants;
\ No newline at end of file
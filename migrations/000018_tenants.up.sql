@@ -0,0 +1,19 @@
+-- a tenant isolates the clients and notifications of a business unit and sends its email
+-- with its own From address and SMTP server; rows without a tenant belong to the default
+-- tenant, which sends with the service configuration
+create table tenants (
+    id uuid primary key default uuid_generate_v4(),
+    name text not null unique,
+    email_from text,
+    smtp_host text,
+    smtp_port integer,
+    smtp_username text,
+    smtp_password text,
+    created_at timestamp not null default now()
+);
+
+alter table clients add column tenant_id uuid references tenants (id);
+
+alter table notifications add column tenant_id uuid references tenants (id);
+
+create index notifications_tenant_id_idx on notifications (tenant_id, created_at);
\ No newline at end of file
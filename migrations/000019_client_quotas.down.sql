@@ -0,0 +1,2 @@
+drop table if exists client_usage;
+drop table if exists client_quotas;
\ No newline at end of file
@@ -0,0 +1,20 @@
+-- a client may create at most daily_limit notifications per channel per UTC day and
+-- monthly_limit per UTC month, unset limits do not apply
+create table client_quotas (
+    client_id uuid not null references clients (id) on delete cascade,
+    delivery_type text not null,
+    daily_limit integer check (daily_limit >= 0),
+    monthly_limit integer check (monthly_limit >= 0),
+    updated_at timestamp not null default now(),
+    primary key (client_id, delivery_type)
+);
+
+-- notifications created per client, channel and period, counted when they are created
+create table client_usage (
+    client_id uuid not null references clients (id) on delete cascade,
+    delivery_type text not null,
+    period text not null check (period in ('day', 'month')),
+    period_start date not null,
+    count integer not null default 0,
+    primary key (client_id, delivery_type, period, period_start)
+);
\ No newline at end of file
@@ -0,0 +1,41 @@
+-- every state-changing API request, with who sent it and what it changed; the log is
+-- append-only, rows can neither be updated nor deleted
+create table audit_log (
+    id uuid primary key default uuid_generate_v4(),
+    occurred_at timestamp not null default now(),
+    request_id text not null,
+    actor_method text,
+    actor_subject text,
+    client_id uuid,
+    tenant_id uuid,
+    ip text not null,
+    method text not null,
+    path text not null,
+    status_code integer not null,
+    action text not null,
+    resource_type text,
+    resource_id text,
+    before jsonb,
+    after jsonb,
+    diff jsonb
+);
+
+-- clients and tenants are not referenced, their entries outlive them
+create index audit_log_occurred_at_idx on audit_log (occurred_at);
+create index audit_log_tenant_id_idx on audit_log (tenant_id, occurred_at);
+create index audit_log_resource_idx on audit_log (resource_type, resource_id);
+create index audit_log_request_id_idx on audit_log (request_id);
+
+create function audit_log_append_only() returns trigger as $$
+begin
+    raise exception 'audit_log is append-only';
+end;
+$$ language plpgsql;
+
+create trigger audit_log_append_only
+    before update or delete on audit_log
+    for each row execute function audit_log_append_only();
+
+create trigger audit_log_no_truncate
+    before truncate on audit_log
+    for each statement execute function audit_log_append_only();
\ No newline at end of file
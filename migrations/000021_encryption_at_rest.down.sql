@@ -0,0 +1,6 @@
+drop index notifications_open_digests_idx;
+create index notifications_open_digests_idx on notifications (digest_key, delivery_type, recipient)
+    where status = 'digested' and summary_id is null;
+
+alter table notification_channels drop column recipient_hash;
+alter table notifications drop column recipient_hash;
\ No newline at end of file
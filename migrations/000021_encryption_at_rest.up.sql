@@ -0,0 +1,9 @@
+-- recipient and content are encrypted by the application, the recipient hash is a keyed
+-- hash of the plaintext recipient to group and look up notifications without decrypting;
+-- rows written before encryption keep their plaintext and no hash until re-encrypted
+alter table notifications add column recipient_hash text;
+alter table notification_channels add column recipient_hash text;
+
+drop index notifications_open_digests_idx;
+create index notifications_open_digests_idx on notifications (digest_key, delivery_type, coalesce(recipient_hash, recipient))
+    where status = 'digested' and summary_id is null;
\ No newline at end of file
@@ -0,0 +1 @@
+drop table if exists retention_policies;
\ No newline at end of file
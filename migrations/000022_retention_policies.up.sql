@@ -0,0 +1,11 @@
+-- notifications in a final status are deleted retention_days after they were created; the
+-- policies without a tenant are set by the operator, they apply to the default tenant and
+-- to every tenant without its own policy for the status. Statuses without a policy are kept
+create table retention_policies (
+    id uuid primary key default uuid_generate_v4(),
+    tenant_id uuid references tenants (id) on delete cascade,
+    status text not null check (status in ('delivered', 'failed', 'expired', 'suppressed', 'bounced')),
+    retention_days integer not null check (retention_days > 0),
+    updated_at timestamp not null default now(),
+    unique nulls not distinct (tenant_id, status)
+);
\ No newline at end of file
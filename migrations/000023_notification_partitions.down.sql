@@ -0,0 +1,15 @@
+create table notifications_unpartitioned (like notifications including defaults including constraints);
+insert into notifications_unpartitioned select * from notifications;
+
+drop table notifications;
+drop function if exists create_notifications_partition(timestamp);
+
+alter table notifications_unpartitioned rename to notifications;
+alter table notifications add primary key (id);
+alter table notifications add foreign key (tenant_id) references tenants (id);
+
+create index notifications_parent_id_idx on notifications (parent_id) where parent_id is not null;
+create index notifications_open_digests_idx on notifications (digest_key, delivery_type, coalesce(recipient_hash, recipient))
+    where status = 'digested' and summary_id is null;
+create index notifications_client_id_idx on notifications (client_id, created_at) where status = 'pending';
+create index notifications_tenant_id_idx on notifications (tenant_id, created_at);
\ No newline at end of file
@@ -0,0 +1,70 @@
+-- notifications are partitioned by month of created_at so the old ones live apart from the
+-- ones being sent and a month emptied by the retention is dropped instead of vacuumed.
+-- The existing rows become one partition up to the next month, later months get their own
+-- partitions created ahead by the retention job, anything else falls into the default one
+alter table notifications drop constraint notifications_pkey;
+alter table notifications drop constraint notifications_tenant_id_fkey;
+alter table notifications rename to notifications_legacy;
+alter index notifications_parent_id_idx rename to notifications_legacy_parent_id_idx;
+alter index notifications_open_digests_idx rename to notifications_legacy_open_digests_idx;
+alter index notifications_client_id_idx rename to notifications_legacy_client_id_idx;
+alter index notifications_tenant_id_idx rename to notifications_legacy_tenant_id_idx;
+
+create table notifications (like notifications_legacy including defaults including constraints)
+    partition by range (created_at);
+
+do $$
+declare
+    upper_bound timestamp;
+begin
+    select greatest(
+        date_trunc('month', localtimestamp) + interval '1 month',
+        coalesce(date_trunc('month', max(created_at)) + interval '1 month', localtimestamp)
+    )
+    into upper_bound
+    from notifications_legacy;
+    execute format(
+        'alter table notifications attach partition notifications_legacy for values from (minvalue) to (%L)',
+        upper_bound
+    );
+end;
+$$;
+
+-- the primary key of a partitioned table has to include the partition key
+alter table notifications add primary key (id, created_at);
+alter table notifications add foreign key (tenant_id) references tenants (id);
+
+-- the existing indexes of the legacy partition are attached, not rebuilt
+create index notifications_parent_id_idx on notifications (parent_id) where parent_id is not null;
+create index notifications_open_digests_idx on notifications (digest_key, delivery_type, coalesce(recipient_hash, recipient))
+    where status = 'digested' and summary_id is null;
+create index notifications_client_id_idx on notifications (client_id, created_at) where status = 'pending';
+create index notifications_tenant_id_idx on notifications (tenant_id, created_at);
+
+-- the retention looks up the oldest root notifications per status and deletes the chain
+-- steps and digest items with them
+create index notifications_retention_idx on notifications (status, created_at) where parent_id is null;
+create index notifications_summary_id_idx on notifications (summary_id) where summary_id is not null;
+
+-- creates the partition of the month of the timestamp, months already covered by a
+-- partition are skipped
+create function create_notifications_partition(partition_month timestamp) returns void as $$
+declare
+    month_start timestamp := date_trunc('month', partition_month);
+begin
+    execute format(
+        'create table if not exists %I partition of notifications for values from (%L) to (%L)',
+        'notifications_' || to_char(month_start, 'YYYY_MM'),
+        month_start,
+        month_start + interval '1 month'
+    );
+exception
+    when invalid_object_definition then
+        null;
+end;
+$$ language plpgsql;
+
+create table notifications_default partition of notifications default;
+
+select create_notifications_partition(date_trunc('month', localtimestamp) + make_interval(months => i))
+from generate_series(1, 3) as i;
\ No newline at end of file
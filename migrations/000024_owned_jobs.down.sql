@@ -0,0 +1,6 @@
+alter table recurring_notifications drop column if exists tenant_id;
+alter table recurring_notifications drop column if exists client_id;
+alter table imports drop column if exists tenant_id;
+alter table imports drop column if exists client_id;
+alter table broadcasts drop column if exists tenant_id;
+alter table broadcasts drop column if exists client_id;
\ No newline at end of file
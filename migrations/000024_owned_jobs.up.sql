@@ -0,0 +1,10 @@
+-- broadcasts, imports and recurring notifications belong to the client that created them
+-- and to its tenant, the notifications they create are sent on behalf of that client
+alter table broadcasts add column client_id uuid references clients (id);
+alter table broadcasts add column tenant_id uuid references tenants (id);
+
+alter table imports add column client_id uuid references clients (id);
+alter table imports add column tenant_id uuid references tenants (id);
+
+alter table recurring_notifications add column client_id uuid references clients (id);
+alter table recurring_notifications add column tenant_id uuid references tenants (id);
\ No newline at end of file
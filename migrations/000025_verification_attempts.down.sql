@@ -0,0 +1 @@
+alter table contact_addresses drop column if exists verification_attempts;
\ No newline at end of file
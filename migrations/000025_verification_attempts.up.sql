@@ -0,0 +1,2 @@
+-- failed attempts against the current verification code, the code is invalidated after a few
+alter table contact_addresses add column verification_attempts integer not null default 0;
\ No newline at end of file
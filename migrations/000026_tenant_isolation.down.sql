@@ -0,0 +1,65 @@
+drop index web_push_subscriptions_user_id_idx;
+alter table web_push_subscriptions drop constraint web_push_subscriptions_endpoint_key;
+alter table web_push_subscriptions drop column if exists tenant_key;
+alter table web_push_subscriptions drop column if exists tenant_id;
+alter table web_push_subscriptions add unique (endpoint);
+create index web_push_subscriptions_user_id_idx on web_push_subscriptions (user_id);
+
+alter table frequency_caps drop constraint frequency_caps_delivery_type_category_key;
+alter table frequency_caps drop column if exists tenant_key;
+alter table frequency_caps drop column if exists tenant_id;
+alter table frequency_caps add unique (delivery_type, category);
+
+alter table quiet_hours drop constraint quiet_hours_pkey;
+alter table quiet_hours drop column if exists tenant_key;
+alter table quiet_hours drop column if exists tenant_id;
+alter table quiet_hours add primary key (user_id, category);
+
+alter table digest_templates drop constraint digest_templates_pkey;
+alter table digest_templates drop column if exists tenant_key;
+alter table digest_templates drop column if exists tenant_id;
+alter table digest_templates add primary key (digest_key);
+
+alter table unsubscribes drop constraint unsubscribes_pkey;
+alter table unsubscribes drop column if exists tenant_key;
+alter table unsubscribes drop column if exists tenant_id;
+alter table unsubscribes add primary key (delivery_type, address, category);
+
+alter table suppressions drop constraint suppressions_delivery_type_address_key;
+alter table suppressions drop column if exists tenant_key;
+alter table suppressions drop column if exists tenant_id;
+alter table suppressions add unique (delivery_type, address);
+
+alter table notification_preferences drop constraint notification_preferences_pkey;
+alter table notification_preferences drop column if exists tenant_key;
+alter table notification_preferences drop column if exists tenant_id;
+alter table notification_preferences add primary key (user_id, category, delivery_type);
+
+alter table topic_subscriptions drop constraint topic_subscriptions_user_id_fkey;
+alter table topic_subscriptions drop constraint topic_subscriptions_topic_fkey;
+alter table topic_subscriptions drop constraint topic_subscriptions_pkey;
+alter table topic_subscriptions drop column if exists tenant_key;
+alter table topic_subscriptions drop column if exists tenant_id;
+
+alter table topics drop constraint topics_pkey;
+alter table topics drop column if exists tenant_key;
+alter table topics drop column if exists tenant_id;
+alter table topics add primary key (name);
+
+drop index contact_addresses_primary_idx;
+alter table contact_addresses drop constraint contact_addresses_user_id_delivery_type_address_key;
+alter table contact_addresses drop constraint contact_addresses_user_id_fkey;
+alter table contact_addresses drop column if exists tenant_key;
+alter table contact_addresses drop column if exists tenant_id;
+
+alter table contacts drop constraint contacts_pkey;
+alter table contacts drop column if exists tenant_key;
+alter table contacts drop column if exists tenant_id;
+alter table contacts add primary key (user_id);
+
+create unique index contact_addresses_primary_idx on contact_addresses (user_id, delivery_type) where is_primary;
+alter table contact_addresses add unique (user_id, delivery_type, address);
+alter table contact_addresses add foreign key (user_id) references contacts (user_id) on delete cascade;
+alter table topic_subscriptions add primary key (topic, user_id);
+alter table topic_subscriptions add foreign key (user_id) references contacts (user_id) on delete cascade;
+alter table topic_subscriptions add foreign key (topic) references topics (name) on delete cascade;
\ No newline at end of file
@@ -0,0 +1,87 @@
+-- contacts, their addresses, preferences and topic subscriptions, the suppression list, the
+-- unsubscribes, topics, digest templates, quiet hours, frequency caps and web push subscriptions
+-- belong to a tenant, rows without a tenant belong to the default tenant. Their keys use
+-- tenant_key, the tenant with the nil UUID for the default tenant as a key column cannot be null
+alter table topic_subscriptions drop constraint topic_subscriptions_topic_fkey;
+alter table topic_subscriptions drop constraint topic_subscriptions_user_id_fkey;
+alter table topic_subscriptions drop constraint topic_subscriptions_pkey;
+alter table contact_addresses drop constraint contact_addresses_user_id_fkey;
+alter table contact_addresses drop constraint contact_addresses_user_id_delivery_type_address_key;
+drop index contact_addresses_primary_idx;
+
+alter table contacts add column tenant_id uuid references tenants (id) on delete cascade;
+alter table contacts add column tenant_key uuid not null
+    generated always as (coalesce(tenant_id, '00000000-0000-0000-0000-000000000000')) stored;
+alter table contacts drop constraint contacts_pkey;
+alter table contacts add primary key (tenant_key, user_id);
+
+alter table contact_addresses add column tenant_id uuid references tenants (id) on delete cascade;
+alter table contact_addresses add column tenant_key uuid not null
+    generated always as (coalesce(tenant_id, '00000000-0000-0000-0000-000000000000')) stored;
+alter table contact_addresses add constraint contact_addresses_user_id_fkey foreign key (tenant_key, user_id)
+    references contacts (tenant_key, user_id) on delete cascade;
+alter table contact_addresses add constraint contact_addresses_user_id_delivery_type_address_key
+    unique (tenant_key, user_id, delivery_type, address);
+create unique index contact_addresses_primary_idx on contact_addresses (tenant_key, user_id, delivery_type) where is_primary;
+
+alter table topics add column tenant_id uuid references tenants (id) on delete cascade;
+alter table topics add column tenant_key uuid not null
+    generated always as (coalesce(tenant_id, '00000000-0000-0000-0000-000000000000')) stored;
+alter table topics drop constraint topics_pkey;
+alter table topics add primary key (tenant_key, name);
+
+alter table topic_subscriptions add column tenant_id uuid references tenants (id) on delete cascade;
+alter table topic_subscriptions add column tenant_key uuid not null
+    generated always as (coalesce(tenant_id, '00000000-0000-0000-0000-000000000000')) stored;
+alter table topic_subscriptions add primary key (tenant_key, topic, user_id);
+alter table topic_subscriptions add constraint topic_subscriptions_topic_fkey foreign key (tenant_key, topic)
+    references topics (tenant_key, name) on delete cascade;
+alter table topic_subscriptions add constraint topic_subscriptions_user_id_fkey foreign key (tenant_key, user_id)
+    references contacts (tenant_key, user_id) on delete cascade;
+
+alter table notification_preferences add column tenant_id uuid references tenants (id) on delete cascade;
+alter table notification_preferences add column tenant_key uuid not null
+    generated always as (coalesce(tenant_id, '00000000-0000-0000-0000-000000000000')) stored;
+alter table notification_preferences drop constraint notification_preferences_pkey;
+alter table notification_preferences add primary key (tenant_key, user_id, category, delivery_type);
+
+alter table suppressions add column tenant_id uuid references tenants (id) on delete cascade;
+alter table suppressions add column tenant_key uuid not null
+    generated always as (coalesce(tenant_id, '00000000-0000-0000-0000-000000000000')) stored;
+alter table suppressions drop constraint suppressions_delivery_type_address_key;
+alter table suppressions add constraint suppressions_delivery_type_address_key
+    unique (tenant_key, delivery_type, address);
+
+alter table unsubscribes add column tenant_id uuid references tenants (id) on delete cascade;
+alter table unsubscribes add column tenant_key uuid not null
+    generated always as (coalesce(tenant_id, '00000000-0000-0000-0000-000000000000')) stored;
+alter table unsubscribes drop constraint unsubscribes_pkey;
+alter table unsubscribes add primary key (tenant_key, delivery_type, address, category);
+
+alter table digest_templates add column tenant_id uuid references tenants (id) on delete cascade;
+alter table digest_templates add column tenant_key uuid not null
+    generated always as (coalesce(tenant_id, '00000000-0000-0000-0000-000000000000')) stored;
+alter table digest_templates drop constraint digest_templates_pkey;
+alter table digest_templates add primary key (tenant_key, digest_key);
+
+alter table quiet_hours add column tenant_id uuid references tenants (id) on delete cascade;
+alter table quiet_hours add column tenant_key uuid not null
+    generated always as (coalesce(tenant_id, '00000000-0000-0000-0000-000000000000')) stored;
+alter table quiet_hours drop constraint quiet_hours_pkey;
+alter table quiet_hours add primary key (tenant_key, user_id, category);
+
+alter table frequency_caps add column tenant_id uuid references tenants (id) on delete cascade;
+alter table frequency_caps add column tenant_key uuid not null
+    generated always as (coalesce(tenant_id, '00000000-0000-0000-0000-000000000000')) stored;
+alter table frequency_caps drop constraint frequency_caps_delivery_type_category_key;
+alter table frequency_caps add constraint frequency_caps_delivery_type_category_key
+    unique (tenant_key, delivery_type, category);
+
+alter table web_push_subscriptions add column tenant_id uuid references tenants (id) on delete cascade;
+alter table web_push_subscriptions add column tenant_key uuid not null
+    generated always as (coalesce(tenant_id, '00000000-0000-0000-0000-000000000000')) stored;
+alter table web_push_subscriptions drop constraint web_push_subscriptions_endpoint_key;
+alter table web_push_subscriptions add constraint web_push_subscriptions_endpoint_key
+    unique (tenant_key, endpoint);
+drop index web_push_subscriptions_user_id_idx;
+create index web_push_subscriptions_user_id_idx on web_push_subscriptions (tenant_key, user_id);
\ No newline at end of file
@@ -0,0 +1,80 @@
+// Package archive writes batches of JSON records to compressed NDJSON files.
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Extension ends the name of every archive file.
+const Extension = ".ndjson.gz"
+
+// Writer writes every batch to a file of its own in the directory, named after the prefix
+// and the time of the batch. A file only appears under its name once it is complete.
+type Writer struct {
+	dir    string
+	prefix string
+	now    func() time.Time
+}
+
+func NewWriter(dir, prefix string) *Writer {
+	return &Writer{dir: dir, prefix: prefix, now: time.Now}
+}
+
+// Write writes the records one per line to a new gzip file and syncs it, it returns the path of the file.
+func (w *Writer) Write(records []json.RawMessage) (string, error) {
+	if err := os.MkdirAll(w.dir, 0o750); err != nil {
+		return "", fmt.Errorf("archive.Write error: %w", err)
+	}
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("archive.Write error: %w", err)
+	}
+	name := fmt.Sprintf("%s-%s-%s%s", w.prefix, w.now().UTC().Format("20060102T150405.000000000Z"), hex.EncodeToString(suffix), Extension)
+	path := filepath.Join(w.dir, name)
+
+	tmp, err := os.CreateTemp(w.dir, "."+name+".*")
+	if err != nil {
+		return "", fmt.Errorf("archive.Write error: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := writeRecords(tmp, records); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("archive.Write %s error: %w", name, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("archive.Write %s error: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("archive.Write %s error: %w", name, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("archive.Write %s error: %w", name, err)
+	}
+	return path, nil
+}
+
+func writeRecords(file *os.File, records []json.RawMessage) error {
+	zw := gzip.NewWriter(file)
+	var line bytes.Buffer
+	for _, record := range records {
+		line.Reset()
+		// a record spread over lines would break the NDJSON
+		if err := json.Compact(&line, record); err != nil {
+			return err
+		}
+		line.WriteByte('\n')
+		if _, err := zw.Write(line.Bytes()); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
@@ -0,0 +1,64 @@
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriter_Write(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "archive")
+	w := NewWriter(dir, "notifications")
+	w.now = func() time.Time { return time.Date(2026, 10, 19, 8, 30, 0, 0, time.UTC) }
+
+	path, err := w.Write([]json.RawMessage{
+		json.RawMessage(`{"id": 1,
+			"content": "hello"}`),
+		json.RawMessage(`{"id":2,"content":"line\nbreak"}`),
+	})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if name := filepath.Base(path); !strings.HasPrefix(name, "notifications-20261019T083000.000000000Z-") || !strings.HasSuffix(name, Extension) {
+		t.Errorf("Write() = %s, want a file named after the prefix and the time", name)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer file.Close()
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("gzip.NewReader() error = %v", err)
+	}
+	var lines []string
+	scanner := bufio.NewScanner(zr)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	want := []string{`{"id":1,"content":"hello"}`, `{"id":2,"content":"line\nbreak"}`}
+	if len(lines) != len(want) || lines[0] != want[0] || lines[1] != want[1] {
+		t.Errorf("lines = %q, want %q", lines, want)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("directory has %d entries, want only the archive", len(entries))
+	}
+}
+
+func TestWriter_WriteInvalidRecord(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := NewWriter(dir, "notifications").Write([]json.RawMessage{json.RawMessage(`{"id":`)}); err == nil {
+		t.Fatal("Write() of invalid JSON, want an error")
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("directory has %d entries, want no partial archive", len(entries))
+	}
+}
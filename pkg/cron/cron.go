@@ -0,0 +1,198 @@
+// Package cron computes the occurrences of recurring schedules given as
+// five-field cron expressions or as a subset of iCalendar RRULEs (RFC 5545).
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidExpression = errors.New("invalid cron expression")
+
+// Schedule yields the occurrences of a recurring schedule.
+type Schedule interface {
+	// Next returns the first occurrence after t in the location of t,
+	// the zero time when there is none.
+	Next(t time.Time) time.Time
+}
+
+// Expression is a parsed "minute hour day-of-month month day-of-week" cron expression.
+type Expression struct {
+	minute, hour, dom, month, dow uint64
+	// domAny and dowAny record a "*" field: when both day fields are restricted
+	// a day matching either of them matches, as in Vixie cron
+	domAny, dowAny bool
+}
+
+type field struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = field{min: 0, max: 59}
+	hourField   = field{min: 0, max: 23}
+	domField    = field{min: 1, max: 31}
+	monthField  = field{min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowField = field{min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse parses a five-field cron expression. Fields accept "*", values, names of
+// months and weekdays, ranges, lists and steps; the @hourly style macros are supported.
+func Parse(expr string) (*Expression, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := macros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: expected 5 fields, got %d", ErrInvalidExpression, len(fields))
+	}
+	e := &Expression{}
+	var err error
+	if e.minute, _, err = parseField(fields[0], minuteField); err != nil {
+		return nil, err
+	}
+	if e.hour, _, err = parseField(fields[1], hourField); err != nil {
+		return nil, err
+	}
+	if e.dom, e.domAny, err = parseField(fields[2], domField); err != nil {
+		return nil, err
+	}
+	if e.month, _, err = parseField(fields[3], monthField); err != nil {
+		return nil, err
+	}
+	if e.dow, e.dowAny, err = parseField(fields[4], dowField); err != nil {
+		return nil, err
+	}
+	// 7 is an alias of sunday
+	if e.dow&(1<<7) != 0 {
+		e.dow |= 1
+	}
+	return e, nil
+}
+
+func parseField(value string, f field) (uint64, bool, error) {
+	var bits uint64
+	for _, part := range strings.Split(value, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step <= 0 {
+				return 0, false, fmt.Errorf("%w: invalid step %q", ErrInvalidExpression, part)
+			}
+		}
+		var lo, hi int
+		switch {
+		case rangePart == "*":
+			lo, hi = f.min, f.max
+		case strings.Contains(rangePart, "-"):
+			loPart, hiPart, _ := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = f.value(loPart); err != nil {
+				return 0, false, err
+			}
+			if hi, err = f.value(hiPart); err != nil {
+				return 0, false, err
+			}
+			if lo > hi {
+				return 0, false, fmt.Errorf("%w: invalid range %q", ErrInvalidExpression, part)
+			}
+		default:
+			var err error
+			if lo, err = f.value(rangePart); err != nil {
+				return 0, false, err
+			}
+			hi = lo
+			if hasStep {
+				hi = f.max
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, value == "*", nil
+}
+
+func (f field) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("%w: value %q out of range %d-%d", ErrInvalidExpression, s, f.min, f.max)
+	}
+	return v, nil
+}
+
+// Next returns the first minute after t matching the expression. Times skipped by a
+// daylight saving transition move to the next valid time.
+func (e *Expression) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	// an expression like "0 0 30 2 *" never matches, give up after a few years
+	yearLimit := t.Year() + 5
+
+wrap:
+	for t.Year() <= yearLimit {
+		for e.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			if t.Month() == time.January {
+				continue wrap
+			}
+		}
+		for !e.dayMatches(t) {
+			month := t.Month()
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			if t.Month() != month {
+				continue wrap
+			}
+		}
+		for e.hour&(1<<uint(t.Hour())) == 0 {
+			day := t.Day()
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			if t.Day() != day {
+				continue wrap
+			}
+		}
+		for e.minute&(1<<uint(t.Minute())) == 0 {
+			hour := t.Hour()
+			t = t.Add(time.Minute)
+			if t.Hour() != hour {
+				continue wrap
+			}
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (e *Expression) dayMatches(t time.Time) bool {
+	domMatch := e.dom&(1<<uint(t.Day())) != 0
+	dowMatch := e.dow&(1<<uint(t.Weekday())) != 0
+	if e.domAny || e.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
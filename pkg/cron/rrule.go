@@ -0,0 +1,347 @@
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+	FreqYearly  = "YEARLY"
+)
+
+// maxPeriods bounds the search for a rule whose BY parts never match, like
+// FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30
+const maxPeriods = 100 * 366
+
+// Rule is a recurrence rule anchored at its first occurrence DTSTART.
+// FREQ of DAILY, WEEKLY, MONTHLY and YEARLY is supported together with
+// INTERVAL, COUNT, UNTIL, WKST, BYMONTH, BYMONTHDAY, BYDAY, BYHOUR and BYMINUTE.
+// BYDAY takes an ordinal like 1MO or -1FR for monthly and yearly rules.
+type Rule struct {
+	Freq       string
+	Interval   int
+	Count      int
+	Until      time.Time
+	WeekStart  time.Weekday
+	ByMonth    []int
+	ByMonthDay []int
+	ByDay      []Weekday
+	ByHour     []int
+	ByMinute   []int
+
+	start time.Time
+}
+
+// Weekday is a BYDAY entry, N is the ordinal within the month or year, 0 means every.
+type Weekday struct {
+	N   int
+	Day time.Weekday
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// ParseRule parses an RRULE value, with or without the "RRULE:" prefix, anchored at start.
+// Occurrences are computed in the location of start.
+func ParseRule(rule string, start time.Time) (*Rule, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	r := &Rule{Interval: 1, WeekStart: time.Monday, start: start.Truncate(time.Second)}
+	for _, part := range strings.Split(rule, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("%w: invalid part %q", ErrInvalidRule, part)
+		}
+		var err error
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			r.Freq = strings.ToUpper(value)
+			if !slices.Contains([]string{FreqDaily, FreqWeekly, FreqMonthly, FreqYearly}, r.Freq) {
+				return nil, fmt.Errorf("%w: unsupported FREQ %q", ErrInvalidRule, value)
+			}
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(value)
+			if err == nil && r.Interval <= 0 {
+				err = errors.New("must be positive")
+			}
+		case "COUNT":
+			r.Count, err = strconv.Atoi(value)
+			if err == nil && r.Count <= 0 {
+				err = errors.New("must be positive")
+			}
+		case "UNTIL":
+			r.Until, err = parseUntil(value, start.Location())
+		case "WKST":
+			day, ok := weekdays[strings.ToUpper(value)]
+			if !ok {
+				err = errors.New("unknown weekday")
+			}
+			r.WeekStart = day
+		case "BYMONTH":
+			r.ByMonth, err = parseInts(value, 1, 12, false)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseInts(value, 1, 31, true)
+		case "BYHOUR":
+			r.ByHour, err = parseInts(value, 0, 23, false)
+		case "BYMINUTE":
+			r.ByMinute, err = parseInts(value, 0, 59, false)
+		case "BYDAY":
+			r.ByDay, err = parseWeekdays(value)
+		default:
+			return nil, fmt.Errorf("%w: unsupported part %q", ErrInvalidRule, name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidRule, name, err)
+		}
+	}
+	if r.Freq == "" {
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return nil, fmt.Errorf("%w: COUNT and UNTIL are mutually exclusive", ErrInvalidRule)
+	}
+	for _, day := range r.ByDay {
+		if day.N != 0 && r.Freq != FreqMonthly && r.Freq != FreqYearly {
+			return nil, fmt.Errorf("%w: BYDAY ordinals require a MONTHLY or YEARLY FREQ", ErrInvalidRule)
+		}
+	}
+	return r, nil
+}
+
+func parseUntil(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102T150405", value, loc); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("20060102", value, loc)
+	if err != nil {
+		return time.Time{}, errors.New("expected a date or a date-time")
+	}
+	// a date UNTIL includes the whole day
+	return t.AddDate(0, 0, 1).Add(-time.Second), nil
+}
+
+func parseInts(value string, lo, hi int, allowNegative bool) ([]int, error) {
+	var values []int
+	for _, s := range strings.Split(value, ",") {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, err
+		}
+		abs := v
+		if allowNegative && v < 0 {
+			abs = -v
+		}
+		if abs < lo || abs > hi {
+			return nil, fmt.Errorf("value %d out of range", v)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+func parseWeekdays(value string) ([]Weekday, error) {
+	var days []Weekday
+	for _, s := range strings.Split(strings.ToUpper(value), ",") {
+		if len(s) < 2 {
+			return nil, fmt.Errorf("invalid weekday %q", s)
+		}
+		day, ok := weekdays[s[len(s)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %q", s)
+		}
+		n := 0
+		if prefix := s[:len(s)-2]; prefix != "" {
+			var err error
+			n, err = strconv.Atoi(prefix)
+			if err != nil || n == 0 || n < -53 || n > 53 {
+				return nil, fmt.Errorf("invalid weekday %q", s)
+			}
+		}
+		days = append(days, Weekday{N: n, Day: day})
+	}
+	return days, nil
+}
+
+// Next returns the first occurrence after t, the zero time when the rule is exhausted.
+func (r *Rule) Next(t time.Time) time.Time {
+	count := 0
+	for period := 0; period < maxPeriods; period++ {
+		for _, occurrence := range r.occurrences(period) {
+			if occurrence.Before(r.start) {
+				continue
+			}
+			if !r.Until.IsZero() && occurrence.After(r.Until) {
+				return time.Time{}
+			}
+			count++
+			if r.Count > 0 && count > r.Count {
+				return time.Time{}
+			}
+			if occurrence.After(t) {
+				return occurrence.In(t.Location())
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// occurrences expands the n-th period of the rule into its sorted occurrences.
+func (r *Rule) occurrences(n int) []time.Time {
+	start, loc := r.start, r.start.Location()
+	var days []time.Time
+	switch r.Freq {
+	case FreqDaily:
+		day := time.Date(start.Year(), start.Month(), start.Day()+n*r.Interval, 0, 0, 0, 0, loc)
+		if r.dayMatches(day) {
+			days = append(days, day)
+		}
+	case FreqWeekly:
+		offset := (int(start.Weekday()) - int(r.WeekStart) + 7) % 7
+		weekStart := time.Date(start.Year(), start.Month(), start.Day()-offset+7*n*r.Interval, 0, 0, 0, 0, loc)
+		for i := 0; i < 7; i++ {
+			day := weekStart.AddDate(0, 0, i)
+			if r.weekdayMatches(day) && r.monthMatches(day) {
+				days = append(days, day)
+			}
+		}
+	case FreqMonthly:
+		month := time.Date(start.Year(), start.Month()+time.Month(n*r.Interval), 1, 0, 0, 0, 0, loc)
+		if r.monthMatches(month) {
+			days = r.daysOfMonth(month)
+		}
+	case FreqYearly:
+		year := start.Year() + n*r.Interval
+		months := r.ByMonth
+		if len(months) == 0 {
+			months = []int{int(start.Month())}
+		}
+		if len(r.ByMonth) == 0 && len(r.ByMonthDay) == 0 && hasOrdinal(r.ByDay) {
+			// ordinals of a yearly rule without BYMONTH count within the year
+			for day := time.Date(year, time.January, 1, 0, 0, 0, 0, loc); day.Year() == year; day = day.AddDate(0, 0, 1) {
+				if r.ordinalMatches(day, time.Date(year, time.January, 1, 0, 0, 0, 0, loc), time.Date(year, time.December, 31, 0, 0, 0, 0, loc)) {
+					days = append(days, day)
+				}
+			}
+			break
+		}
+		slices.Sort(months)
+		for _, m := range months {
+			days = append(days, r.daysOfMonth(time.Date(year, time.Month(m), 1, 0, 0, 0, 0, loc))...)
+		}
+	}
+
+	hours, minutes := r.ByHour, r.ByMinute
+	if len(hours) == 0 {
+		hours = []int{start.Hour()}
+	}
+	if len(minutes) == 0 {
+		minutes = []int{start.Minute()}
+	}
+	hours, minutes = slices.Sorted(slices.Values(hours)), slices.Sorted(slices.Values(minutes))
+	var occurrences []time.Time
+	for _, day := range days {
+		for _, h := range hours {
+			for _, m := range minutes {
+				occurrence := time.Date(day.Year(), day.Month(), day.Day(), h, m, start.Second(), 0, loc)
+				// a time skipped by a daylight saving transition lands on another hour
+				if occurrence.Hour() == h {
+					occurrences = append(occurrences, occurrence)
+				}
+			}
+		}
+	}
+	return occurrences
+}
+
+// daysOfMonth expands a month period, without BY day parts the day of DTSTART recurs.
+func (r *Rule) daysOfMonth(month time.Time) []time.Time {
+	first := month
+	last := month.AddDate(0, 1, -1)
+	var days []time.Time
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		switch {
+		case len(r.ByMonthDay) == 0 && len(r.ByDay) == 0:
+			if day.Day() == r.start.Day() {
+				days = append(days, day)
+			}
+		case len(r.ByMonthDay) > 0 && !r.monthDayMatches(day, last.Day()):
+		case len(r.ByDay) > 0 && !r.ordinalMatches(day, first, last):
+		default:
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+func (r *Rule) dayMatches(day time.Time) bool {
+	last := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+	return r.monthMatches(day) &&
+		(len(r.ByMonthDay) == 0 || r.monthDayMatches(day, last)) &&
+		(len(r.ByDay) == 0 || r.weekdayMatches(day))
+}
+
+func (r *Rule) monthMatches(day time.Time) bool {
+	return len(r.ByMonth) == 0 || slices.Contains(r.ByMonth, int(day.Month()))
+}
+
+func (r *Rule) monthDayMatches(day time.Time, lastDay int) bool {
+	for _, d := range r.ByMonthDay {
+		if d == day.Day() || (d < 0 && lastDay+d+1 == day.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+// weekdayMatches checks BYDAY of a weekly rule, without it the weekday of DTSTART recurs.
+func (r *Rule) weekdayMatches(day time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return r.Freq != FreqWeekly || day.Weekday() == r.start.Weekday()
+	}
+	for _, weekday := range r.ByDay {
+		if weekday.Day == day.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// ordinalMatches checks BYDAY with optional ordinals counted within first..last of one year.
+func (r *Rule) ordinalMatches(day, first, last time.Time) bool {
+	for _, weekday := range r.ByDay {
+		if weekday.Day != day.Weekday() {
+			continue
+		}
+		switch {
+		case weekday.N == 0:
+			return true
+		case weekday.N > 0 && (day.YearDay()-first.YearDay())/7+1 == weekday.N:
+			return true
+		case weekday.N < 0 && (last.YearDay()-day.YearDay())/7+1 == -weekday.N:
+			return true
+		}
+	}
+	return false
+}
+
+func hasOrdinal(days []Weekday) bool {
+	for _, day := range days {
+		if day.N != 0 {
+			return true
+		}
+	}
+	return false
+}
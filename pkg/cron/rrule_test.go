@@ -0,0 +1,64 @@
+package cron
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func occurrences(r *Rule, from time.Time, n int) []time.Time {
+	var result []time.Time
+	for t := r.Next(from); !t.IsZero() && len(result) < n; t = r.Next(t) {
+		result = append(result, t)
+	}
+	return result
+}
+
+func TestRule_Next(t *testing.T) {
+	start := time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC) // a monday
+	date := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		rule string
+		from time.Time
+		want []time.Time
+	}{
+		{"FREQ=DAILY;COUNT=3", start.Add(-time.Hour), []time.Time{date(10, 19, 9, 30), date(10, 20, 9, 30), date(10, 21, 9, 30)}},
+		{"FREQ=DAILY;COUNT=3", start, []time.Time{date(10, 20, 9, 30), date(10, 21, 9, 30)}},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH;BYHOUR=8;BYMINUTE=0", start, []time.Time{date(10, 20, 8, 0), date(10, 22, 8, 0), date(11, 3, 8, 0)}},
+		{"RRULE:FREQ=MONTHLY;BYDAY=-1FR;UNTIL=20261231", start, []time.Time{date(10, 30, 9, 30), date(11, 27, 9, 30), date(12, 25, 9, 30)}},
+		{"FREQ=MONTHLY;BYMONTHDAY=1,-1;BYHOUR=0,12;BYMINUTE=0", start, []time.Time{date(10, 31, 0, 0), date(10, 31, 12, 0), date(11, 1, 0, 0)}},
+		{"FREQ=YEARLY;BYMONTH=11;BYDAY=4TH", start, []time.Time{date(11, 26, 9, 30), time.Date(2027, 11, 25, 9, 30, 0, 0, time.UTC)}},
+		{"FREQ=DAILY;BYMONTH=2;BYMONTHDAY=30", start, nil},
+	}
+	for _, tt := range tests {
+		r, err := ParseRule(tt.rule, start)
+		if err != nil {
+			t.Fatalf("ParseRule(%q) error = %v", tt.rule, err)
+		}
+		got := occurrences(r, tt.from, 10)
+		if r.Count == 0 && r.Until.IsZero() && len(got) > len(tt.want) {
+			// unbounded rules are only checked up to the expected occurrences
+			got = got[:len(tt.want)]
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("ParseRule(%q) occurrences = %v, want %v", tt.rule, got, tt.want)
+			continue
+		}
+		for i := range tt.want {
+			if !got[i].Equal(tt.want[i]) {
+				t.Errorf("ParseRule(%q) occurrence %d = %v, want %v", tt.rule, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestParseRule_Invalid(t *testing.T) {
+	start := time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+	for _, rule := range []string{"", "COUNT=3", "FREQ=HOURLY", "FREQ=DAILY;INTERVAL=0", "FREQ=DAILY;COUNT=2;UNTIL=20261231", "FREQ=WEEKLY;BYDAY=1MO", "FREQ=DAILY;BYHOUR=24", "FREQ=DAILY;BYSETPOS=1"} {
+		if _, err := ParseRule(rule, start); !errors.Is(err, ErrInvalidRule) {
+			t.Errorf("ParseRule(%q) error = %v, want %v", rule, err, ErrInvalidRule)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+// Package envelope encrypts field values with data keys that are wrapped by master keys
+// of a KeyProvider. An encrypted value carries its wrapped data key:
+//
+//	enc:v1:<master key ID>:<wrapped data key>:<nonce and ciphertext>
+//
+// so it can be decrypted without a key table, and values without the prefix are read
+// as plaintext, e.g. rows written before encryption was enabled.
+package envelope
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	prefix = "enc:v1:"
+	// dataKeyTTL bounds how many values share a data key, well below the nonce limit of GCM
+	dataKeyTTL = time.Hour
+	// maxOpenedKeys bounds the cache of unwrapped data keys
+	maxOpenedKeys = 1024
+	// KeyFileCheckInterval is how often a cipher opened from a key file looks for a
+	// rotated file, new values are wrapped with its primary key at the latest after it
+	KeyFileCheckInterval = 10 * time.Second
+)
+
+var encoding = base64.RawStdEncoding
+
+// Cipher encrypts and decrypts field values. A nil Cipher stores values as plaintext
+// and only reads plaintext.
+type Cipher struct {
+	indexKey []byte
+	// path is the key file the master keys are reloaded from, empty for a fixed provider
+	path string
+
+	mu        sync.Mutex
+	provider  KeyProvider
+	modTime   time.Time
+	checkedAt time.Time
+	current   *dataKey
+	opened    map[string]cipher.AEAD
+}
+
+type dataKey struct {
+	header    string
+	aead      cipher.AEAD
+	createdAt time.Time
+}
+
+func New(provider KeyProvider, indexKey []byte) *Cipher {
+	return &Cipher{provider: provider, indexKey: indexKey, opened: make(map[string]cipher.AEAD)}
+}
+
+// Open returns the cipher of a key file, nil when the path is empty. The cipher reloads
+// the master keys when the file changes, so a rotation reaches running processes without
+// a restart; the index key is kept.
+func Open(path string) (*Cipher, error) {
+	if path == "" {
+		return nil, nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	file, err := ReadKeyFile(path)
+	if err != nil {
+		return nil, err
+	}
+	provider, err := file.Provider()
+	if err != nil {
+		return nil, err
+	}
+	indexKey, err := file.BlindIndexKey()
+	if err != nil {
+		return nil, err
+	}
+	c := New(provider, indexKey)
+	c.path, c.modTime, c.checkedAt = path, info.ModTime(), time.Now()
+	return c, nil
+}
+
+// MustOpen is Open for the commands, it panics when the key file cannot be loaded.
+func MustOpen(path string) *Cipher {
+	c, err := Open(path)
+	if err != nil {
+		slog.Error("error loading encryption keys", slog.Any("error", err))
+		panic("failed to load encryption keys")
+	}
+	return c
+}
+
+// Encrypt encrypts the value of the field, the field name is authenticated so a value
+// cannot be moved to another field. Empty values stay empty. Plaintext with the prefix of
+// encrypted values is rejected, stored without keys it could not be read back.
+func (c *Cipher) Encrypt(ctx context.Context, field, plaintext string) (string, error) {
+	if IsEncrypted(plaintext) {
+		return "", ErrReservedValue
+	}
+	if c == nil || plaintext == "" {
+		return plaintext, nil
+	}
+	key, err := c.dataKey(ctx)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, key.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := key.aead.Seal(nonce, nonce, []byte(plaintext), []byte(field))
+	return key.header + encoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a value of the field, plaintext values are returned as they are.
+func (c *Cipher) Decrypt(ctx context.Context, field, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	if c == nil {
+		return "", ErrNoKeys
+	}
+	parts := strings.SplitN(strings.TrimPrefix(value, prefix), ":", 3)
+	if len(parts) != 3 {
+		return "", ErrInvalidValue
+	}
+	aead, err := c.openDataKey(ctx, parts[0], parts[1])
+	if err != nil {
+		return "", err
+	}
+	sealed, err := encoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidValue, err)
+	}
+	plaintext, err := open(aead, sealed, []byte(field))
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// IsCurrent reports whether the value is stored the way Encrypt would store it now,
+// values of other master keys or plaintext need re-encryption.
+func (c *Cipher) IsCurrent(value string) bool {
+	if c == nil || value == "" {
+		return !IsEncrypted(value)
+	}
+	c.mu.Lock()
+	provider, _ := c.reload(false)
+	primary := provider.PrimaryKeyID()
+	c.mu.Unlock()
+	return strings.HasPrefix(value, prefix+primary+":")
+}
+
+// BlindIndex returns a keyed hash of the value to look up or group encrypted values
+// by, empty without keys.
+func (c *Cipher) BlindIndex(value string) string {
+	if c == nil || value == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, c.indexKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// dataKey returns the current data key, a new one is generated and wrapped when the
+// primary master key changed or the key is older than dataKeyTTL.
+func (c *Cipher) dataKey(ctx context.Context) (*dataKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	provider, _ := c.reload(false)
+	primary := provider.PrimaryKeyID()
+	if c.current != nil && time.Since(c.current.createdAt) < dataKeyTTL &&
+		strings.HasPrefix(c.current.header, prefix+primary+":") {
+		return c.current, nil
+	}
+	plainKey := make([]byte, keySize)
+	if _, err := rand.Read(plainKey); err != nil {
+		return nil, err
+	}
+	keyID, wrapped, err := provider.WrapKey(ctx, plainKey)
+	if err != nil {
+		return nil, fmt.Errorf("envelope.Cipher wrap data key error: %w", err)
+	}
+	aead, err := newAEAD(plainKey)
+	if err != nil {
+		return nil, err
+	}
+	c.current = &dataKey{
+		header:    prefix + keyID + ":" + encoding.EncodeToString(wrapped) + ":",
+		aead:      aead,
+		createdAt: time.Now(),
+	}
+	return c.current, nil
+}
+
+// openDataKey unwraps a data key once, the values written with the same key share it.
+func (c *Cipher) openDataKey(ctx context.Context, keyID, encodedKey string) (cipher.AEAD, error) {
+	cacheKey := keyID + ":" + encodedKey
+	c.mu.Lock()
+	aead, ok := c.opened[cacheKey]
+	provider := c.provider
+	c.mu.Unlock()
+	if ok {
+		return aead, nil
+	}
+
+	wrapped, err := encoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidValue, err)
+	}
+	plainKey, err := provider.UnwrapKey(ctx, keyID, wrapped)
+	if errors.Is(err, ErrUnknownKey) {
+		// the value may be of a key added to the key file since it was read
+		c.mu.Lock()
+		reloaded, changed := c.reload(true)
+		c.mu.Unlock()
+		if changed {
+			plainKey, err = reloaded.UnwrapKey(ctx, keyID, wrapped)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("envelope.Cipher unwrap data key error: %w", err)
+	}
+	aead, err = newAEAD(plainKey)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	if len(c.opened) >= maxOpenedKeys {
+		clear(c.opened)
+	}
+	c.opened[cacheKey] = aead
+	c.mu.Unlock()
+	return aead, nil
+}
+
+// reload reads the key file again when it changed since it was read, at most once per
+// KeyFileCheckInterval unless forced, and returns the master keys to use and whether they
+// were reloaded. A file that
+// cannot be read, e.g. while it is written, keeps the loaded keys until the next check.
+// The caller holds c.mu.
+func (c *Cipher) reload(force bool) (KeyProvider, bool) {
+	if c.path == "" || (!force && time.Since(c.checkedAt) < KeyFileCheckInterval) {
+		return c.provider, false
+	}
+	c.checkedAt = time.Now()
+	info, err := os.Stat(c.path)
+	if err != nil || info.ModTime().Equal(c.modTime) {
+		return c.provider, false
+	}
+	file, err := ReadKeyFile(c.path)
+	if err != nil {
+		return c.provider, false
+	}
+	provider, err := file.Provider()
+	if err != nil {
+		return c.provider, false
+	}
+	c.provider, c.modTime = provider, info.ModTime()
+	return c.provider, true
+}
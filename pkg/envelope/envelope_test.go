@@ -0,0 +1,185 @@
+package envelope
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestCipher(t *testing.T, file *KeyFile) *Cipher {
+	t.Helper()
+	provider, err := file.Provider()
+	if err != nil {
+		t.Fatalf("Provider() error = %v", err)
+	}
+	indexKey, err := file.BlindIndexKey()
+	if err != nil {
+		t.Fatalf("BlindIndexKey() error = %v", err)
+	}
+	return New(provider, indexKey)
+}
+
+func TestCipher_EncryptDecrypt(t *testing.T) {
+	ctx := context.Background()
+	file, err := NewKeyFile("k1")
+	if err != nil {
+		t.Fatalf("NewKeyFile() error = %v", err)
+	}
+	c := newTestCipher(t, file)
+
+	encrypted, err := c.Encrypt(ctx, "content", "hello")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if !strings.HasPrefix(encrypted, "enc:v1:k1:") || strings.Contains(encrypted, "hello") {
+		t.Fatalf("Encrypt() = %q, want an opaque value of key k1", encrypted)
+	}
+	again, _ := c.Encrypt(ctx, "content", "hello")
+	if again == encrypted {
+		t.Error("Encrypt() is deterministic, want a fresh nonce per value")
+	}
+	decrypted, err := c.Decrypt(ctx, "content", encrypted)
+	if err != nil || decrypted != "hello" {
+		t.Errorf("Decrypt() = %q, %v, want hello", decrypted, err)
+	}
+	if _, err := c.Decrypt(ctx, "recipient", encrypted); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("Decrypt() of another field error = %v, want ErrInvalidValue", err)
+	}
+	tampered := encrypted[:len(encrypted)-2] + "AA"
+	if _, err := c.Decrypt(ctx, "content", tampered); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("Decrypt() of a tampered value error = %v, want ErrInvalidValue", err)
+	}
+
+	if empty, _ := c.Encrypt(ctx, "content", ""); empty != "" {
+		t.Errorf("Encrypt() of an empty value = %q, want empty", empty)
+	}
+	if plain, err := c.Decrypt(ctx, "content", "legacy"); err != nil || plain != "legacy" {
+		t.Errorf("Decrypt() of plaintext = %q, %v, want it unchanged", plain, err)
+	}
+}
+
+func TestCipher_Rotation(t *testing.T) {
+	ctx := context.Background()
+	file, _ := NewKeyFile("k1")
+	old := newTestCipher(t, file)
+	encrypted, _ := old.Encrypt(ctx, "recipient", "user@example.com")
+
+	if err := file.AddKey("k2"); err != nil {
+		t.Fatalf("AddKey() error = %v", err)
+	}
+	if err := file.AddKey("k2"); err == nil {
+		t.Error("AddKey() of an existing key, want an error")
+	}
+	rotated := newTestCipher(t, file)
+	if rotated.IsCurrent(encrypted) {
+		t.Error("IsCurrent() of a value of the old key = true")
+	}
+	if rotated.IsCurrent("user@example.com") {
+		t.Error("IsCurrent() of plaintext = true")
+	}
+	decrypted, err := rotated.Decrypt(ctx, "recipient", encrypted)
+	if err != nil || decrypted != "user@example.com" {
+		t.Fatalf("Decrypt() after rotation = %q, %v", decrypted, err)
+	}
+	reencrypted, _ := rotated.Encrypt(ctx, "recipient", decrypted)
+	if !rotated.IsCurrent(reencrypted) {
+		t.Errorf("IsCurrent() of %q = false", reencrypted)
+	}
+	if old.BlindIndex("user@example.com") != rotated.BlindIndex("user@example.com") {
+		t.Error("BlindIndex() changed with the master key")
+	}
+
+	delete(file.Keys, "k1")
+	if _, err := newTestCipher(t, file).Decrypt(ctx, "recipient", encrypted); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Decrypt() without the old key error = %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestCipher_Nil(t *testing.T) {
+	ctx := context.Background()
+	var c *Cipher
+	if value, _ := c.Encrypt(ctx, "content", "hello"); value != "hello" {
+		t.Errorf("Encrypt() = %q, want plaintext", value)
+	}
+	if !c.IsCurrent("hello") || c.BlindIndex("hello") != "" {
+		t.Error("nil cipher should keep plaintext current without blind indexes")
+	}
+	if _, err := c.Decrypt(ctx, "content", "enc:v1:k1:a:b"); !errors.Is(err, ErrNoKeys) {
+		t.Errorf("Decrypt() error = %v, want ErrNoKeys", err)
+	}
+	// stored as it is, the value could not be read back
+	if _, err := c.Encrypt(ctx, "content", "enc:v1:k1:a:b"); !errors.Is(err, ErrReservedValue) {
+		t.Errorf("Encrypt() of a prefixed value error = %v, want ErrReservedValue", err)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	if c, err := Open(""); c != nil || err != nil {
+		t.Errorf("Open(\"\") = %v, %v, want a nil cipher", c, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "keys.json")
+	file, _ := NewKeyFile("k1")
+	if err := file.Write(path); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	c, err := Open(path)
+	if err != nil || c == nil {
+		t.Fatalf("Open() = %v, %v", c, err)
+	}
+
+	file.Primary = "missing"
+	_ = file.Write(path)
+	if _, err := Open(path); !errors.Is(err, ErrInvalidKeyFile) {
+		t.Errorf("Open() with a missing primary key error = %v, want ErrInvalidKeyFile", err)
+	}
+	if _, err := NewKeyFile("bad:id"); !errors.Is(err, ErrInvalidKeyFile) {
+		t.Errorf("NewKeyFile() with a separator in the ID error = %v, want ErrInvalidKeyFile", err)
+	}
+}
+
+func TestOpen_Reload(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "keys.json")
+	file, _ := NewKeyFile("k1")
+	_ = file.Write(path)
+	c, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	rotate := func(keyID string, modTime time.Time) {
+		t.Helper()
+		if err := file.AddKey(keyID); err != nil {
+			t.Fatalf("AddKey() error = %v", err)
+		}
+		_ = file.Write(path)
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("Chtimes() error = %v", err)
+		}
+	}
+
+	// a value of a key added since the file was read is decrypted without waiting for the check
+	rotate("k2", time.Now().Add(time.Minute))
+	encrypted, _ := newTestCipher(t, file).Encrypt(ctx, "content", "hello")
+	if decrypted, err := c.Decrypt(ctx, "content", encrypted); err != nil || decrypted != "hello" {
+		t.Fatalf("Decrypt() of a value of the rotated key = %q, %v", decrypted, err)
+	}
+	if value, _ := c.Encrypt(ctx, "content", "hello"); !strings.HasPrefix(value, "enc:v1:k2:") {
+		t.Errorf("Encrypt() after the reload = %q, want the new primary key", value)
+	}
+
+	// new values move to the new primary key once the file is checked again
+	rotate("k3", time.Now().Add(2*time.Minute))
+	if value, _ := c.Encrypt(ctx, "content", "hello"); !strings.HasPrefix(value, "enc:v1:k2:") {
+		t.Errorf("Encrypt() before the check = %q, want the loaded primary key", value)
+	}
+	c.checkedAt = time.Now().Add(-KeyFileCheckInterval)
+	value, _ := c.Encrypt(ctx, "content", "hello")
+	if !strings.HasPrefix(value, "enc:v1:k3:") || !c.IsCurrent(value) {
+		t.Errorf("Encrypt() after the check = %q, want the rotated primary key", value)
+	}
+}
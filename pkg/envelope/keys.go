@@ -0,0 +1,191 @@
+package envelope
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+)
+
+const keySize = 32
+
+var (
+	ErrInvalidKeyFile = errors.New("invalid key file")
+	ErrUnknownKey     = errors.New("unknown master key")
+	ErrNoKeys         = errors.New("encrypted value but no keys are configured")
+	ErrInvalidValue   = errors.New("invalid encrypted value")
+	ErrReservedValue  = errors.New("plaintext starts with the prefix of encrypted values")
+
+	// keyIDPattern keeps key IDs free of the separator of the encrypted values
+	keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+)
+
+// KeyProvider wraps data keys with master keys it never hands out, e.g. a KMS or a local key file.
+type KeyProvider interface {
+	// PrimaryKeyID is the master key new data keys are wrapped with.
+	PrimaryKeyID() string
+	WrapKey(ctx context.Context, dataKey []byte) (keyID string, wrapped []byte, err error)
+	UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+// KeyFile holds base64 encoded 256-bit master keys by ID for development and small
+// deployments. The index key derives the blind indexes and is kept apart from the
+// master keys so that rotating them does not change the indexes.
+type KeyFile struct {
+	Primary  string            `json:"primary"`
+	Keys     map[string]string `json:"keys"`
+	IndexKey string            `json:"index_key"`
+}
+
+// NewKeyFile generates a key file with one master key and an index key.
+func NewKeyFile(keyID string) (*KeyFile, error) {
+	file := &KeyFile{Keys: map[string]string{}, IndexKey: GenerateKey()}
+	if err := file.AddKey(keyID); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// ReadKeyFile reads and validates a key file.
+func ReadKeyFile(path string) (*KeyFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	file := &KeyFile{}
+	if err := json.Unmarshal(data, file); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidKeyFile, err)
+	}
+	if _, err := file.Provider(); err != nil {
+		return nil, err
+	}
+	if _, err := file.BlindIndexKey(); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// Write stores the key file readable by the owner only.
+func (f *KeyFile) Write(path string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// AddKey generates a master key and makes it the primary one. The previous keys stay
+// to decrypt the values until they are re-encrypted.
+func (f *KeyFile) AddKey(keyID string) error {
+	if !keyIDPattern.MatchString(keyID) {
+		return fmt.Errorf("%w: key ID %q must be 1-64 letters, digits, '.', '_' or '-'", ErrInvalidKeyFile, keyID)
+	}
+	if _, ok := f.Keys[keyID]; ok {
+		return fmt.Errorf("%w: key %q already exists", ErrInvalidKeyFile, keyID)
+	}
+	f.Keys[keyID] = GenerateKey()
+	f.Primary = keyID
+	return nil
+}
+
+// Provider returns the key provider of the master keys.
+func (f *KeyFile) Provider() (*LocalKeyProvider, error) {
+	if _, ok := f.Keys[f.Primary]; !ok {
+		return nil, fmt.Errorf("%w: primary key %q is missing", ErrInvalidKeyFile, f.Primary)
+	}
+	provider := &LocalKeyProvider{primary: f.Primary, keys: make(map[string]cipher.AEAD, len(f.Keys))}
+	for keyID, encoded := range f.Keys {
+		if !keyIDPattern.MatchString(keyID) {
+			return nil, fmt.Errorf("%w: invalid key ID %q", ErrInvalidKeyFile, keyID)
+		}
+		key, err := decodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %q: %w", ErrInvalidKeyFile, keyID, err)
+		}
+		provider.keys[keyID], err = newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return provider, nil
+}
+
+func (f *KeyFile) BlindIndexKey() ([]byte, error) {
+	key, err := decodeKey(f.IndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: index key: %w", ErrInvalidKeyFile, err)
+	}
+	return key, nil
+}
+
+// GenerateKey returns a random base64 encoded 256-bit key.
+func GenerateKey() string {
+	key := make([]byte, keySize)
+	_, _ = rand.Read(key)
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+// LocalKeyProvider wraps data keys with AES-256-GCM master keys held in memory.
+type LocalKeyProvider struct {
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+func (p *LocalKeyProvider) PrimaryKeyID() string {
+	return p.primary
+}
+
+func (p *LocalKeyProvider) WrapKey(_ context.Context, dataKey []byte) (string, []byte, error) {
+	aead := p.keys[p.primary]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", nil, err
+	}
+	return p.primary, aead.Seal(nonce, nonce, dataKey, []byte(p.primary)), nil
+}
+
+func (p *LocalKeyProvider) UnwrapKey(_ context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	aead, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, keyID)
+	}
+	return open(aead, wrapped, []byte(keyID))
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != keySize {
+		return nil, fmt.Errorf("key must be %d bytes", keySize)
+	}
+	return key, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// open decrypts a nonce prefixed ciphertext.
+func open(aead cipher.AEAD, sealed, additionalData []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrInvalidValue
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidValue, err)
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,193 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMalformedToken       = errors.New("malformed token")
+	ErrUnsupportedAlgorithm = errors.New("unsupported algorithm")
+	ErrInvalidSignature     = errors.New("invalid signature")
+	ErrUnknownKey           = errors.New("unknown key")
+	ErrExpired              = errors.New("token is expired")
+	ErrNotYetValid          = errors.New("token is not valid yet")
+	ErrInvalidIssuer        = errors.New("invalid issuer")
+	ErrInvalidAudience      = errors.New("invalid audience")
+)
+
+// Claims are the registered claims and the OAuth 2.0 claims of an access token.
+type Claims struct {
+	Issuer    string   `json:"iss,omitempty"`
+	Subject   string   `json:"sub,omitempty"`
+	Audience  Audience `json:"aud,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	// Scope is the space separated list of RFC 8693, some providers send a scp array instead
+	Scope           string   `json:"scope,omitempty"`
+	Scp             []string `json:"scp,omitempty"`
+	ClientID        string   `json:"client_id,omitempty"`
+	AuthorizedParty string   `json:"azp,omitempty"`
+}
+
+// Scopes returns the scopes from scope or scp.
+func (c *Claims) Scopes() []string {
+	if c.Scope != "" {
+		return strings.Fields(c.Scope)
+	}
+	return c.Scp
+}
+
+// Client returns the OAuth client the token was issued to. For the client credentials
+// grant it is client_id or azp, some providers only set the subject.
+func (c *Claims) Client() string {
+	switch {
+	case c.ClientID != "":
+		return c.ClientID
+	case c.AuthorizedParty != "":
+		return c.AuthorizedParty
+	}
+	return c.Subject
+}
+
+// Audience is a single string or an array in JSON.
+type Audience []string
+
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+// Validation is what Validate checks besides the expiry. Empty fields are not checked.
+type Validation struct {
+	Issuer   string
+	Audience string
+	// Leeway allows for clock skew between the issuer and this service
+	Leeway time.Duration
+}
+
+// Validate checks the time claims and the issuer and audience, a token without exp is rejected.
+func (c *Claims) Validate(now time.Time, v Validation) error {
+	if c.ExpiresAt == 0 || !now.Before(time.Unix(c.ExpiresAt, 0).Add(v.Leeway)) {
+		return ErrExpired
+	}
+	if c.NotBefore != 0 && now.Add(v.Leeway).Before(time.Unix(c.NotBefore, 0)) {
+		return ErrNotYetValid
+	}
+	if v.Issuer != "" && c.Issuer != v.Issuer {
+		return ErrInvalidIssuer
+	}
+	if v.Audience != "" && !slices.Contains(c.Audience, v.Audience) {
+		return ErrInvalidAudience
+	}
+	return nil
+}
+
+// KeyFunc returns the public key the token with the header was signed with.
+type KeyFunc func(ctx context.Context, header *Header) (crypto.PublicKey, error)
+
+// Parse verifies the signature of a compact JWS and decodes its payload into claims.
+// Only RS256 and ES256 are accepted, the key has to match the algorithm.
+func Parse(ctx context.Context, token string, keyFunc KeyFunc, claims any) (*Header, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	header := &Header{}
+	if err := json.Unmarshal(headerJSON, header); err != nil {
+		return nil, ErrMalformedToken
+	}
+	if header.Alg != AlgRS256 && header.Alg != AlgES256 {
+		return nil, fmt.Errorf("%w %q", ErrUnsupportedAlgorithm, header.Alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	key, err := keyFunc(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if header.Alg != AlgRS256 || rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], signature) != nil {
+			return nil, ErrInvalidSignature
+		}
+	case *ecdsa.PublicKey:
+		if header.Alg != AlgES256 || len(signature) != 64 {
+			return nil, ErrInvalidSignature
+		}
+		r, s := new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(k, digest[:], r, s) {
+			return nil, ErrInvalidSignature
+		}
+	default:
+		return nil, ErrUnsupportedKey
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return nil, ErrMalformedToken
+	}
+	return header, nil
+}
+
+// Verifier checks access tokens against a key set.
+type Verifier struct {
+	keyFunc    KeyFunc
+	validation Validation
+	now        func() time.Time
+}
+
+func NewVerifier(keyFunc KeyFunc, validation Validation) *Verifier {
+	return &Verifier{keyFunc: keyFunc, validation: validation, now: time.Now}
+}
+
+// Verify returns the claims of a token with a valid signature that passes the validation.
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	claims := &Claims{}
+	if _, err := Parse(ctx, token, v.keyFunc, claims); err != nil {
+		return nil, err
+	}
+	if err := claims.Validate(v.now(), v.validation); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
@@ -0,0 +1,160 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// jwksServer serves the public keys of the signers by kid and counts the fetches.
+func jwksServer(t *testing.T, signers map[string]crypto.Signer) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	fetches := &atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		set := JWKS{}
+		for kid, signer := range signers {
+			jwk, err := NewJWK(signer.Public(), kid)
+			if err != nil {
+				t.Errorf("NewJWK() error = %v", err)
+			}
+			set.Keys = append(set.Keys, jwk)
+		}
+		_ = json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(server.Close)
+	return server, fetches
+}
+
+func TestVerifier(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signers := map[string]crypto.Signer{"rsa": rsaKey, "ec": ecKey}
+	server, _ := jwksServer(t, signers)
+	verifier := NewVerifier(NewKeySet(server.URL, time.Hour).Key, Validation{
+		Issuer:   "https://issuer.example.com",
+		Audience: "notifications",
+		Leeway:   time.Minute,
+	})
+
+	now := time.Now()
+	valid := Claims{
+		Issuer:    "https://issuer.example.com",
+		Subject:   "svc-billing",
+		Audience:  Audience{"notifications", "other"},
+		ExpiresAt: now.Add(time.Hour).Unix(),
+		Scope:     "notifications:read notifications:write",
+		ClientID:  "billing",
+	}
+	for kid, signer := range signers {
+		token, err := Sign(signer, kid, valid)
+		if err != nil {
+			t.Fatalf("Sign() error = %v", err)
+		}
+		claims, err := verifier.Verify(context.Background(), token)
+		if err != nil {
+			t.Fatalf("Verify() of a %s token error = %v", kid, err)
+		}
+		if claims.Client() != "billing" || len(claims.Scopes()) != 2 || claims.Scopes()[1] != "notifications:write" {
+			t.Errorf("claims = %+v", claims)
+		}
+	}
+
+	sign := func(kid string, signer crypto.Signer, change func(c *Claims)) string {
+		claims := valid
+		change(&claims)
+		token, err := Sign(signer, kid, claims)
+		if err != nil {
+			t.Fatalf("Sign() error = %v", err)
+		}
+		return token
+	}
+	token := sign("rsa", rsaKey, func(*Claims) {})
+	parts := strings.Split(token, ".")
+	tampered := parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"admin","exp":9999999999}`)) + "." + parts[2]
+	noneHeader := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"expired", sign("rsa", rsaKey, func(c *Claims) { c.ExpiresAt = now.Add(-2 * time.Minute).Unix() }), ErrExpired},
+		{"without exp", sign("rsa", rsaKey, func(c *Claims) { c.ExpiresAt = 0 }), ErrExpired},
+		{"not yet valid", sign("ec", ecKey, func(c *Claims) { c.NotBefore = now.Add(time.Hour).Unix() }), ErrNotYetValid},
+		{"other issuer", sign("rsa", rsaKey, func(c *Claims) { c.Issuer = "https://evil.example.com" }), ErrInvalidIssuer},
+		{"other audience", sign("rsa", rsaKey, func(c *Claims) { c.Audience = Audience{"other"} }), ErrInvalidAudience},
+		{"tampered", tampered, ErrInvalidSignature},
+		{"signed by another key", sign("rsa", otherKey, func(*Claims) {}), ErrInvalidSignature},
+		{"key of another algorithm", sign("rsa", ecKey, func(*Claims) {}), ErrInvalidSignature},
+		{"unknown kid", sign("gone", rsaKey, func(*Claims) {}), ErrUnknownKey},
+		{"alg none", noneHeader + "." + parts[1] + ".", ErrUnsupportedAlgorithm},
+		{"malformed", "abc", ErrMalformedToken},
+	}
+	for _, tt := range tests {
+		if _, err := verifier.Verify(context.Background(), tt.token); !errors.Is(err, tt.want) {
+			t.Errorf("Verify() of a %s token error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestKeySet_Rotation(t *testing.T) {
+	oldKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	newKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	signers := map[string]crypto.Signer{"old": oldKey}
+	server, fetches := jwksServer(t, signers)
+
+	now := time.Now()
+	keySet := NewKeySet(server.URL, time.Hour)
+	keySet.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if _, err := keySet.Key(ctx, &Header{Kid: "old"}); err != nil {
+		t.Fatalf("Key() error = %v", err)
+	}
+	if _, err := keySet.Key(ctx, &Header{}); err != nil {
+		t.Errorf("Key() without kid of a single key set error = %v", err)
+	}
+	if fetches.Load() != 1 {
+		t.Errorf("fetches = %d, want the set cached", fetches.Load())
+	}
+
+	// the issuer rotates, tokens with the new kid refetch the set once the throttle allows
+	signers["new"] = newKey
+	if _, err := keySet.Key(ctx, &Header{Kid: "new"}); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Key() right after a fetch error = %v, want %v", err, ErrUnknownKey)
+	}
+	now = now.Add(minRefreshInterval)
+	if _, err := keySet.Key(ctx, &Header{Kid: "new"}); err != nil {
+		t.Errorf("Key() of the rotated key error = %v", err)
+	}
+	if fetches.Load() != 2 {
+		t.Errorf("fetches = %d, want 2", fetches.Load())
+	}
+
+	// cached keys outlive an unreachable issuer
+	server.Close()
+	now = now.Add(2 * time.Hour)
+	if _, err := keySet.Key(ctx, &Header{Kid: "old"}); err != nil {
+		t.Errorf("Key() with the issuer down error = %v", err)
+	}
+}
@@ -33,7 +33,8 @@ func SetLogger(env config.AppEnv) {
 			Level: slog.LevelInfo,
 		}))
 	}
-	slog.SetDefault(defaultLogger)
+	// recipients, contents and credentials never reach the log store
+	slog.SetDefault(slog.New(NewRedactHandler(defaultLogger.Handler())))
 }
 
 func GetLoggerFromContext(ctx context.Context) *slog.Logger {
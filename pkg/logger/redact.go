@@ -0,0 +1,122 @@
+package slogger
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces the values of sensitive attributes.
+const Redacted = "[REDACTED]"
+
+// SensitiveKeys are the attribute keys whose values RedactHandler masks by default.
+var SensitiveKeys = []string{
+	"recipient", "recipients", "content", "address", "email", "phone",
+	"password", "secret", "token", "api_key", "authorization", "verification_code",
+}
+
+// errorKeys are the attribute keys whose string values are scrubbed like errors.
+var errorKeys = map[string]struct{}{"error": {}, "err": {}}
+
+var (
+	// emailPattern matches the addresses in SMTP replies and database errors.
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	// urlPattern matches URLs, which carry bot tokens and webhook secrets in their paths.
+	urlPattern = regexp.MustCompile(`[A-Za-z][A-Za-z0-9+.\-]*://[^\s"'<>]+`)
+)
+
+// RedactHandler masks the values of attributes with sensitive keys, in groups and in the
+// values of slog.LogValuer as well, before they reach the wrapped handler. Errors, which may
+// quote addresses or URLs under any key, are logged with those replaced.
+type RedactHandler struct {
+	handler slog.Handler
+	keys    map[string]struct{}
+}
+
+// NewRedactHandler wraps the handler, keys are matched case-insensitively and default to SensitiveKeys.
+func NewRedactHandler(handler slog.Handler, keys ...string) *RedactHandler {
+	if len(keys) == 0 {
+		keys = SensitiveKeys
+	}
+	set := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		set[strings.ToLower(key)] = struct{}{}
+	}
+	return &RedactHandler{handler: handler, keys: set}
+}
+
+func (h *RedactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *RedactHandler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(h.redact(attr))
+		return true
+	})
+	return h.handler.Handle(ctx, redacted)
+}
+
+func (h *RedactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redacted[i] = h.redact(attr)
+	}
+	return &RedactHandler{handler: h.handler.WithAttrs(redacted), keys: h.keys}
+}
+
+func (h *RedactHandler) WithGroup(name string) slog.Handler {
+	return &RedactHandler{handler: h.handler.WithGroup(name), keys: h.keys}
+}
+
+func (h *RedactHandler) redact(attr slog.Attr) slog.Attr {
+	if _, ok := h.keys[strings.ToLower(attr.Key)]; ok {
+		return slog.String(attr.Key, Redacted)
+	}
+	attr.Value = attr.Value.Resolve()
+	switch attr.Value.Kind() {
+	case slog.KindGroup:
+	case slog.KindAny:
+		if err, ok := attr.Value.Any().(error); ok {
+			return slog.String(attr.Key, scrub(err.Error()))
+		}
+		return attr
+	case slog.KindString:
+		if _, ok := errorKeys[strings.ToLower(attr.Key)]; ok {
+			return slog.String(attr.Key, scrub(attr.Value.String()))
+		}
+		return attr
+	default:
+		return attr
+	}
+	group := attr.Value.Group()
+	redacted := make([]slog.Attr, len(group))
+	for i, member := range group {
+		redacted[i] = h.redact(member)
+	}
+	return slog.Attr{Key: attr.Key, Value: slog.GroupValue(redacted...)}
+}
+
+// scrub replaces the addresses and URLs in a message.
+func scrub(message string) string {
+	message = urlPattern.ReplaceAllString(message, Redacted)
+	return emailPattern.ReplaceAllString(message, Redacted)
+}
+
+// Mask keeps a hint of a value for responses to callers that may not see it: the first
+// character and the domain of an email address, the first and last character otherwise.
+func Mask(value string) string {
+	if value == "" {
+		return ""
+	}
+	runes := []rune(value)
+	if at := strings.LastIndex(value, "@"); at > 0 {
+		return string(runes[0]) + "***" + value[at:]
+	}
+	if len(runes) <= 4 {
+		return "***"
+	}
+	return string(runes[0]) + "***" + string(runes[len(runes)-1])
+}
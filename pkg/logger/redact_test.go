@@ -0,0 +1,97 @@
+package slogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+)
+
+type account struct {
+	id    string
+	email string
+}
+
+func (a account) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("id", a.id), slog.String("email", a.email))
+}
+
+func TestRedactHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewRedactHandler(slog.NewJSONHandler(&buf, nil))).
+		With(slog.String("token", "secret-token"))
+
+	logger.Info("sent",
+		slog.String("Recipient", "alice@example.com"),
+		slog.Group("notification", slog.String("content", "Your code is 123456"), slog.String("status", "delivered")),
+		slog.Any("account", account{id: "42", email: "bob@example.com"}),
+		slog.String("delivery_type", "email"),
+	)
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("log line %q: %v", buf.String(), err)
+	}
+	for _, secret := range []string{"secret-token", "alice@example.com", "123456", "bob@example.com"} {
+		if bytes.Contains(buf.Bytes(), []byte(secret)) {
+			t.Errorf("log line %s contains %q", buf.String(), secret)
+		}
+	}
+	if got["Recipient"] != Redacted || got["token"] != Redacted || got["delivery_type"] != "email" {
+		t.Errorf("log line = %v", got)
+	}
+	notification := got["notification"].(map[string]any)
+	if notification["content"] != Redacted || notification["status"] != "delivered" {
+		t.Errorf("group = %v, want only the content redacted", notification)
+	}
+	if account := got["account"].(map[string]any); account["id"] != "42" || account["email"] != Redacted {
+		t.Errorf("log valuer = %v, want only the email redacted", account)
+	}
+}
+
+func TestRedactHandler_Errors(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewRedactHandler(slog.NewJSONHandler(&buf, nil)))
+
+	rejected := fmt.Errorf("EmailNotifier.Notify send error: %w",
+		errors.New("550 5.1.1 <alice@example.com>: Recipient address rejected"))
+	logger.Error("delivery failed",
+		slog.Any("error", rejected),
+		slog.Any("cause", errors.New(`Post "https://hooks.slack.com/services/T0/B0/secret": EOF`)),
+		slog.String("err", "duplicate key (address)=(bob@example.com)"),
+		slog.String("path", "/api/v1/notifications"),
+	)
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("log line %q: %v", buf.String(), err)
+	}
+	for _, secret := range []string{"alice@example.com", "hooks.slack.com", "secret", "bob@example.com"} {
+		if bytes.Contains(buf.Bytes(), []byte(secret)) {
+			t.Errorf("log line %s contains %q", buf.String(), secret)
+		}
+	}
+	if want := "EmailNotifier.Notify send error: 550 5.1.1 <[REDACTED]>: Recipient address rejected"; got["error"] != want {
+		t.Errorf("error = %v, want %q", got["error"], want)
+	}
+	if got["path"] != "/api/v1/notifications" {
+		t.Errorf("path = %v, want it unchanged", got["path"])
+	}
+}
+
+func TestMask(t *testing.T) {
+	tests := map[string]string{
+		"":                  "",
+		"alice@example.com": "a***@example.com",
+		"+4915112345678":    "+***8",
+		"1234":              "***",
+		"Jürgen":            "J***n",
+	}
+	for value, want := range tests {
+		if got := Mask(value); got != want {
+			t.Errorf("Mask(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
@@ -2,18 +2,25 @@ package server
 
 import (
 	"context"
+	"expvar"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"time"
 
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
 	"notification_system/config"
+	"notification_system/internal/auth"
 	"notification_system/internal/handlers/http/v1"
+	"notification_system/internal/notifiers"
+	"notification_system/internal/ratelimit"
 	"notification_system/internal/repositories"
 	"notification_system/internal/services"
 	"notification_system/pkg/database"
+	"notification_system/pkg/envelope"
+	"notification_system/pkg/jwt"
 
 	"github.com/gin-gonic/gin"
 )
@@ -32,9 +39,18 @@ type GinServer struct {
 // @host      localhost:8080
 // @BasePath  /api/v1
 
+// @securityDefinitions.apikey  ApiKeyAuth
+// @in                          header
+// @name                        X-API-Key
+
+// @securityDefinitions.apikey  BearerAuth
+// @in                          header
+// @name                        Authorization
+// @description                 JWT issued by the configured OIDC provider, as "Bearer <token>"
+
 // @externalDocs.description  OpenAPI
 // @externalDocs.url          https://swagger.io/resources/open-api/
-func NewGinServer(cfg *config.Config, db *database.PostgresDatabase) *GinServer {
+func NewGinServer(cfg *config.Config, db *database.PostgresDatabase, cipher *envelope.Cipher) *GinServer {
 	switch cfg.AppEnv {
 	case config.Local, config.Dev:
 		gin.SetMode(gin.DebugMode)
@@ -46,22 +62,223 @@ func NewGinServer(cfg *config.Config, db *database.PostgresDatabase) *GinServer
 
 	router := gin.Default()
 
-	apiV1 := router.Group("/api/v1")
-
-	notificationRepo := repositories.NewNotificationPostgresRepository(db)
-	notificationService := services.NewNotificationServiceImpl(notificationRepo)
-	notificationHandlers := v1.NewNotificationHTTPHandlers(notificationService)
-
-	notificationRoutes := apiV1.Group(
-		"/notifications",
+	auditService := services.NewAuditServiceImpl(repositories.NewAuditPostgresRepository(db))
+	apiV1 := router.Group(
+		"/api/v1",
 		v1.RequestIDMiddleware(),
 		v1.SetLoggerMiddleware(),
+		v1.AuditMiddleware(auditService),
 	)
-	notificationRoutes.GET("/new", notificationHandlers.GetNewNotifications)
-	notificationRoutes.GET("/batch", notificationHandlers.GetNotificationsByIDs)
-	notificationRoutes.GET("/:id", notificationHandlers.GetNotificationByID)
-	notificationRoutes.POST("/", notificationHandlers.CreateNotifications)
 
+	clientService := services.NewClientServiceImpl(repositories.NewClientPostgresRepository(db, cipher))
+	apiKeyHandlers := v1.NewAPIKeyHTTPHandlers(clientService)
+	clientHandlers := v1.NewClientHTTPHandlers(clientService)
+
+	var verifier *jwt.Verifier
+	if cfg.JWKSURL != "" {
+		keySet := jwt.NewKeySet(cfg.JWKSURL, time.Duration(cfg.JWKSCacheTTLSeconds)*time.Second)
+		verifier = jwt.NewVerifier(keySet.Key, jwt.Validation{
+			Issuer:   cfg.JWTIssuer,
+			Audience: cfg.JWTAudience,
+			Leeway:   time.Duration(cfg.JWTLeewaySeconds) * time.Second,
+		})
+	}
+	authenticate := v1.AuthMiddleware(clientService, verifier)
+	limiter := ratelimit.NewLimiter(ratelimit.Limit{Rate: cfg.RateLimitPerSecond, Burst: cfg.RateLimitBurst})
+	rateLimit := v1.RateLimitMiddleware(limiter)
+
+	apiKeyRoutes := apiV1.Group("/api-keys", authenticate, rateLimit, v1.RequireClient())
+	apiKeyRoutes.GET("", apiKeyHandlers.GetAPIKeys)
+	apiKeyRoutes.POST("", apiKeyHandlers.CreateAPIKey)
+	apiKeyRoutes.POST("/:id/rotate", apiKeyHandlers.RotateAPIKey)
+	apiKeyRoutes.DELETE("/:id", apiKeyHandlers.RevokeAPIKey)
+
+	requireOperator := []gin.HandlerFunc{authenticate, v1.RequireScope(auth.ScopeAdmin), v1.RequireOperator()}
+	clientRoutes := apiV1.Group("/clients", requireOperator...)
+	clientRoutes.GET("", clientHandlers.GetClients)
+	clientRoutes.POST("", clientHandlers.CreateClient)
+	clientRoutes.PUT("/:id/oauth-client", clientHandlers.LinkOAuthClient)
+	tenantRoutes := apiV1.Group("/tenants", requireOperator...)
+	tenantRoutes.GET("", clientHandlers.GetTenants)
+	tenantRoutes.POST("", clientHandlers.CreateTenant)
+
+	auditHandlers := v1.NewAuditHTTPHandlers(auditService)
+	auditRoutes := apiV1.Group("/audit-log", authenticate, rateLimit, v1.RequireScope(auth.ScopeAdmin))
+	auditRoutes.GET("", auditHandlers.SearchAuditLog)
+	auditRoutes.GET("/export", auditHandlers.ExportAuditLog)
+
+	retentionHandlers := v1.NewRetentionHTTPHandlers(services.NewRetentionServiceImpl(repositories.NewRetentionPostgresRepository(db)))
+	retentionRoutes := apiV1.Group("/retention-policies", authenticate, rateLimit, v1.RequireScope(auth.ScopeAdmin))
+	retentionRoutes.GET("", retentionHandlers.GetRetentionPolicies)
+	retentionRoutes.PUT("/:status", retentionHandlers.UpdateRetentionPolicy)
+	retentionRoutes.DELETE("/:status", retentionHandlers.DeleteRetentionPolicy)
+
+	quotaRepo := repositories.NewQuotaPostgresRepository(db)
+	quotaHandlers := v1.NewQuotaHTTPHandlers(services.NewQuotaServiceImpl(quotaRepo))
+
+	apiV1.GET("/usage", authenticate, rateLimit, v1.RequireClient(), quotaHandlers.GetUsage)
+	clientRoutes.GET("/:id/usage", quotaHandlers.GetClientUsage)
+	clientRoutes.GET("/:id/quotas", quotaHandlers.GetQuotas)
+	clientRoutes.PUT("/:id/quotas/:delivery_type", quotaHandlers.UpdateQuota)
+	clientRoutes.DELETE("/:id/quotas/:delivery_type", quotaHandlers.DeleteQuota)
+
+	notificationRepo := repositories.NewNotificationPostgresRepository(db, cipher)
+	notificationChainRepo := repositories.NewNotificationChainPostgresRepository(db, cipher)
+	notificationService := services.NewNotificationServiceImpl(notificationRepo, notificationChainRepo, quotaRepo, cfg.MaskPII)
+	notificationHandlers := v1.NewNotificationHTTPHandlers(notificationService)
+
+	notificationRoutes := apiV1.Group("/notifications", authenticate, rateLimit)
+	canRead := v1.RequireScope(auth.ScopeNotificationsRead)
+	canWrite := v1.RequireScope(auth.ScopeNotificationsWrite)
+	notificationRoutes.GET("/new", canRead, notificationHandlers.GetNewNotifications)
+	notificationRoutes.GET("/batch", canRead, notificationHandlers.GetNotificationsByIDs)
+	notificationRoutes.GET("/:id", canRead, notificationHandlers.GetNotificationByID)
+	notificationRoutes.POST("/", canWrite, notificationHandlers.CreateNotifications)
+
+	isAdmin := v1.RequireScope(auth.ScopeAdmin)
+	canReadContacts := v1.RequireScope(auth.ScopeContactsRead)
+	canWriteContacts := v1.RequireScope(auth.ScopeContactsWrite)
+
+	vapidPublicKey := ""
+	if cfg.WebPushVAPIDPrivateKey != "" {
+		vapidKey, err := notifiers.ParseVAPIDPrivateKey(cfg.WebPushVAPIDPrivateKey)
+		if err != nil {
+			slog.Error("invalid VAPID private key", slog.Any("error", err))
+			panic("failed to parse VAPID private key")
+		}
+		vapidPublicKey, _ = notifiers.VAPIDPublicKey(vapidKey)
+	}
+	webPushSubscriptionRepo := repositories.NewWebPushSubscriptionPostgresRepository(db)
+	webPushService := services.NewWebPushServiceImpl(webPushSubscriptionRepo, vapidPublicKey)
+	webPushHandlers := v1.NewWebPushHTTPHandlers(webPushService)
+
+	apiV1.GET("/web-push/vapid-public-key", webPushHandlers.GetVAPIDPublicKey)
+	webPushRoutes := apiV1.Group("/users/:user_id/web-push-subscriptions", authenticate, rateLimit)
+	webPushRoutes.GET("", canReadContacts, webPushHandlers.GetSubscriptions)
+	webPushRoutes.POST("", canWriteContacts, webPushHandlers.Subscribe)
+	webPushRoutes.DELETE("", canWriteContacts, webPushHandlers.Unsubscribe)
+
+	contactRepo := repositories.NewContactPostgresRepository(db)
+	contactService := services.NewContactServiceImpl(contactRepo, notificationRepo)
+	contactHandlers := v1.NewContactHTTPHandlers(contactService)
+
+	apiV1.POST("/users", authenticate, rateLimit, canWriteContacts, contactHandlers.CreateContact)
+	contactRoutes := apiV1.Group("/users/:user_id", authenticate, rateLimit)
+	contactRoutes.GET("", canReadContacts, contactHandlers.GetContact)
+	contactRoutes.PUT("", canWriteContacts, contactHandlers.UpdateContact)
+	contactRoutes.DELETE("", canWriteContacts, contactHandlers.DeleteContact)
+	contactRoutes.POST("/addresses", canWriteContacts, contactHandlers.AddAddress)
+	contactRoutes.PUT("/addresses/:address_id", canWriteContacts, contactHandlers.UpdateAddress)
+	contactRoutes.DELETE("/addresses/:address_id", canWriteContacts, contactHandlers.DeleteAddress)
+	contactRoutes.POST("/addresses/:address_id/verification", canWriteContacts, contactHandlers.SendVerificationCode)
+	contactRoutes.POST("/addresses/:address_id/verification/confirm", canWriteContacts, contactHandlers.VerifyAddress)
+
+	preferenceRepo := repositories.NewPreferencePostgresRepository(db)
+	preferenceService := services.NewPreferenceServiceImpl(preferenceRepo)
+	preferenceHandlers := v1.NewPreferenceHTTPHandlers(preferenceService)
+
+	contactRoutes.GET("/preferences", canReadContacts, preferenceHandlers.GetPreferences)
+	contactRoutes.PUT("/preferences", canWriteContacts, preferenceHandlers.UpdatePreferences)
+	contactRoutes.DELETE("/preferences", canWriteContacts, preferenceHandlers.DeletePreference)
+	categoryRoutes := apiV1.Group("/categories", authenticate, rateLimit)
+	categoryRoutes.GET("", canRead, preferenceHandlers.GetCategories)
+	// categories are shared by the tenants, only the operator changes them
+	categoryRoutes.PUT("/:name", isAdmin, v1.RequireOperator(), preferenceHandlers.UpdateCategory)
+
+	unsubscribeService := services.NewUnsubscribeServiceImpl(preferenceRepo, cfg.UnsubscribeSecret)
+	unsubscribeHandlers := v1.NewUnsubscribeHTTPHandlers(unsubscribeService)
+
+	apiV1.GET("/unsubscribe", unsubscribeHandlers.ConfirmUnsubscribe)
+	apiV1.POST("/unsubscribe", unsubscribeHandlers.Unsubscribe)
+
+	suppressionRepo := repositories.NewSuppressionPostgresRepository(db)
+	suppressionService := services.NewSuppressionServiceImpl(suppressionRepo)
+	suppressionHandlers := v1.NewSuppressionHTTPHandlers(suppressionService)
+
+	suppressionRoutes := apiV1.Group("/suppressions", authenticate, rateLimit)
+	suppressionRoutes.GET("", canRead, suppressionHandlers.SearchSuppressions)
+	suppressionRoutes.POST("", isAdmin, suppressionHandlers.AddSuppression)
+	suppressionRoutes.POST("/import", isAdmin, suppressionHandlers.ImportSuppressions)
+	suppressionRoutes.DELETE("/:id", isAdmin, suppressionHandlers.DeleteSuppression)
+
+	bounceService := services.NewBounceServiceImpl(notificationRepo, suppressionRepo)
+	bounceHandlers := v1.NewBounceHTTPHandlers(bounceService)
+
+	// mail gateways post bounces with an admin token, cmd/bounces reads them without the API
+	apiV1.POST("/bounces", authenticate, rateLimit, isAdmin, bounceHandlers.ProcessBounce)
+
+	quietHoursService := services.NewQuietHoursServiceImpl(repositories.NewQuietHoursPostgresRepository(db))
+	quietHoursHandlers := v1.NewQuietHoursHTTPHandlers(quietHoursService)
+
+	quietHoursRoutes := apiV1.Group("/quiet-hours", authenticate, rateLimit)
+	quietHoursRoutes.GET("", canRead, quietHoursHandlers.GetQuietHours)
+	quietHoursRoutes.PUT("", isAdmin, quietHoursHandlers.UpdateQuietHours)
+	quietHoursRoutes.DELETE("", isAdmin, quietHoursHandlers.DeleteQuietHours)
+
+	frequencyCapService := services.NewFrequencyCapServiceImpl(repositories.NewFrequencyCapPostgresRepository(db, cipher))
+	frequencyCapHandlers := v1.NewFrequencyCapHTTPHandlers(frequencyCapService)
+
+	frequencyCapRoutes := apiV1.Group("/frequency-caps", authenticate, rateLimit)
+	frequencyCapRoutes.GET("", canRead, frequencyCapHandlers.GetFrequencyCaps)
+	frequencyCapRoutes.PUT("", isAdmin, frequencyCapHandlers.UpdateFrequencyCap)
+	frequencyCapRoutes.DELETE("", isAdmin, frequencyCapHandlers.DeleteFrequencyCap)
+
+	digestService := services.NewDigestServiceImpl(repositories.NewDigestPostgresRepository(db, cipher))
+	digestHandlers := v1.NewDigestHTTPHandlers(digestService)
+
+	digestRoutes := apiV1.Group("/digest-templates", authenticate, rateLimit)
+	digestRoutes.GET("", canRead, digestHandlers.GetDigestTemplates)
+	digestRoutes.PUT("/:digest_key", isAdmin, digestHandlers.UpdateDigestTemplate)
+	digestRoutes.DELETE("/:digest_key", isAdmin, digestHandlers.DeleteDigestTemplate)
+
+	recurringRepo := repositories.NewRecurringNotificationPostgresRepository(db, cipher)
+	recurringService := services.NewRecurringNotificationServiceImpl(recurringRepo)
+	recurringHandlers := v1.NewRecurringNotificationHTTPHandlers(recurringService)
+
+	recurringRoutes := apiV1.Group("/recurring-notifications", authenticate, rateLimit)
+	recurringRoutes.GET("", canRead, recurringHandlers.GetRecurringNotifications)
+	recurringRoutes.POST("", canWrite, recurringHandlers.CreateRecurringNotification)
+	recurringRoutes.GET("/:id", canRead, recurringHandlers.GetRecurringNotification)
+	recurringRoutes.PUT("/:id", canWrite, recurringHandlers.UpdateRecurringNotification)
+	recurringRoutes.DELETE("/:id", canWrite, recurringHandlers.DeleteRecurringNotification)
+
+	topicRepo := repositories.NewTopicPostgresRepository(db)
+	topicService := services.NewTopicServiceImpl(topicRepo)
+	topicHandlers := v1.NewTopicHTTPHandlers(topicService)
+
+	topicRoutes := apiV1.Group("/topics", authenticate, rateLimit)
+	topicRoutes.GET("", canRead, topicHandlers.GetTopics)
+	topicRoutes.PUT("/:topic", isAdmin, topicHandlers.UpdateTopic)
+	topicRoutes.DELETE("/:topic", isAdmin, topicHandlers.DeleteTopic)
+	topicRoutes.GET("/:topic/subscribers", canReadContacts, topicHandlers.GetTopicSubscriptions)
+	contactRoutes.GET("/topics", canReadContacts, topicHandlers.GetUserSubscriptions)
+	contactRoutes.PUT("/topics/:topic", canWriteContacts, topicHandlers.Subscribe)
+	contactRoutes.DELETE("/topics/:topic", canWriteContacts, topicHandlers.Unsubscribe)
+
+	broadcastService := services.NewBroadcastServiceImpl(repositories.NewBroadcastPostgresRepository(db, cipher), topicRepo)
+	broadcastHandlers := v1.NewBroadcastHTTPHandlers(broadcastService)
+
+	broadcastRoutes := apiV1.Group("/broadcasts", authenticate, rateLimit)
+	broadcastRoutes.GET("", canRead, broadcastHandlers.GetBroadcasts)
+	broadcastRoutes.POST("", canWrite, broadcastHandlers.CreateBroadcast)
+	broadcastRoutes.GET("/:id", canRead, broadcastHandlers.GetBroadcast)
+	broadcastRoutes.POST("/:id/pause", canWrite, broadcastHandlers.PauseBroadcast)
+	broadcastRoutes.POST("/:id/resume", canWrite, broadcastHandlers.ResumeBroadcast)
+	broadcastRoutes.POST("/:id/cancel", canWrite, broadcastHandlers.CancelBroadcast)
+
+	importService := services.NewImportServiceImpl(repositories.NewImportPostgresRepository(db, cipher))
+	importHandlers := v1.NewImportHTTPHandlers(importService)
+
+	importRoutes := apiV1.Group("/imports", authenticate, rateLimit)
+	importRoutes.GET("", canRead, importHandlers.GetImports)
+	importRoutes.POST("", canWrite, importHandlers.CreateImport)
+	importRoutes.GET("/:id", canRead, importHandlers.GetImport)
+	importRoutes.PUT("/:id/data", canWrite, importHandlers.UploadImport)
+	importRoutes.GET("/:id/errors", canRead, importHandlers.GetImportErrors)
+
+	// the metrics cover every tenant, only the operator may read them
+	router.GET("/debug/vars", v1.RequestIDMiddleware(), v1.SetLoggerMiddleware(), authenticate,
+		v1.RequireScope(auth.ScopeAdmin), v1.RequireOperator(), gin.WrapH(expvar.Handler()))
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	httpServer := &http.Server{
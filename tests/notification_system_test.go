@@ -20,8 +20,22 @@ import (
 
 var (
 	host = fmt.Sprintf("http://localhost:%s", os.Getenv("APP_PORT"))
+	// apiKey authenticates as a client created with go run ./cmd/clients create
+	apiKey = os.Getenv("API_KEY")
 )
 
+func do(method, url string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("X-API-Key", apiKey)
+	return http.DefaultClient.Do(req)
+}
+
 func TestNotificationSystem_SendNotifications(t *testing.T) {
 	email := gofakeit.Email()
 	message := gofakeit.Sentence(5)
@@ -34,7 +48,7 @@ func TestNotificationSystem_SendNotifications(t *testing.T) {
 	payload := "[" + strings.Repeat(notification+",", notificationCount-1) + notification + "]"
 
 	urlCreateNotifications := fmt.Sprintf("%s/api/v1/notifications", host)
-	resp, err := http.Post(urlCreateNotifications, "application/json", bytes.NewBuffer([]byte(payload)))
+	resp, err := do(http.MethodPost, urlCreateNotifications, bytes.NewBuffer([]byte(payload)))
 	if err != nil {
 		t.Errorf("request failed: %v", err)
 		return
@@ -63,7 +77,7 @@ func TestNotificationSystem_SendNotifications(t *testing.T) {
 	deadline := time.Now().Add(10 * time.Second)
 	allDelivered := true
 	for time.Now().Before(deadline) {
-		resp, err = http.Get(urlGetNotificationsByIDs)
+		resp, err = do(http.MethodGet, urlGetNotificationsByIDs, nil)
 		if err != nil {
 			t.Errorf("request failed: %v", err)
 			return